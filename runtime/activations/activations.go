@@ -97,6 +97,19 @@ func (a *Activation[T]) FunctionValues() map[string]T {
 	return values
 }
 
+// ForEach calls the given function for each name-value pair in the activation.
+// The entries of the parent activations are not included.
+func (a *Activation[T]) ForEach(f func(name string, value T) error) error {
+	for name, value := range a.entries { //nolint:maprange
+		err := f(name, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Set sets the given name-value pair in the activation.
 func (a *Activation[T]) Set(name string, value T) {
 	if a.entries == nil {
@@ -19,9 +19,11 @@
 package activations
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestActivations(t *testing.T) {
@@ -71,3 +73,44 @@ func TestActivations(t *testing.T) {
 	assert.Zero(t, activations.Find("b"))
 	assert.Zero(t, activations.Find("c"))
 }
+
+func TestActivationForEach(t *testing.T) {
+
+	t.Parallel()
+
+	activations := &Activations[int]{}
+
+	activations.Set("a", 1)
+	activations.Set("b", 2)
+
+	activations.PushNewWithCurrent()
+
+	activations.Set("a", 3)
+	activations.Set("c", 4)
+
+	entries := map[string]int{}
+
+	err := activations.Current().ForEach(func(name string, value int) error {
+		entries[name] = value
+		return nil
+	})
+	require.NoError(t, err)
+
+	// Entries of the parent are not included
+	assert.Equal(t,
+		map[string]int{
+			"a": 3,
+			"c": 4,
+		},
+		entries,
+	)
+
+	// Errors are returned
+
+	expectedErr := errors.New("test")
+
+	err = activations.Current().ForEach(func(_ string, _ int) error {
+		return expectedErr
+	})
+	require.ErrorIs(t, err, expectedErr)
+}
@@ -30,6 +30,13 @@ type Program struct {
 	// all declarations, in the order they are defined
 	declarations []Declaration
 	indices      programIndices
+	// trivia is all trivia of the program, in source order
+	trivia []Trivia
+	// elementTrivia is the trivia attached to elements
+	elementTrivia map[Element]*ElementTrivia
+	// triviaElements are the elements which have trivia attached,
+	// in the order the trivia was attached
+	triviaElements []Element
 }
 
 var _ Element = &Program{}
@@ -171,12 +178,16 @@ func (p *Program) MarshalJSON() ([]byte, error) {
 	type Alias Program
 	return json.Marshal(&struct {
 		*Alias
-		Type         string
-		Declarations []Declaration
+		Type          string
+		Declarations  []Declaration
+		Trivia        []Trivia            `json:",omitempty"`
+		ElementTrivia []elementTriviaJSON `json:",omitempty"`
 	}{
-		Type:         "Program",
-		Declarations: p.declarations,
-		Alias:        (*Alias)(p),
+		Type:          "Program",
+		Declarations:  p.declarations,
+		Trivia:        p.trivia,
+		ElementTrivia: p.elementTriviaJSON(),
+		Alias:         (*Alias)(p),
 	})
 }
 
@@ -188,11 +199,22 @@ var programSeparatorDoc = prettier.Concat{
 func (p *Program) Doc() prettier.Doc {
 	declarations := p.Declarations()
 
-	docs := make([]prettier.Doc, 0, len(declarations))
-
-	for _, declaration := range declarations {
-		docs = append(docs, declaration.Doc())
+	doc := make(prettier.Concat, 0, len(declarations)*2)
+
+	for i, declaration := range declarations {
+		if i > 0 {
+			// Consecutive imports are not separated by a blank line
+			_, isImport := declaration.(*ImportDeclaration)
+			_, previousIsImport := declarations[i-1].(*ImportDeclaration)
+			if isImport && previousIsImport {
+				doc = append(doc, prettier.HardLine{})
+			} else {
+				doc = append(doc, programSeparatorDoc)
+			}
+		}
+
+		doc = append(doc, declaration.Doc())
 	}
 
-	return prettier.Join(programSeparatorDoc, docs...)
+	return doc
 }
@@ -24,6 +24,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
 )
 
 func TestProgram_MarshalJSON(t *testing.T) {
@@ -46,3 +48,106 @@ func TestProgram_MarshalJSON(t *testing.T) {
 		string(actual),
 	)
 }
+
+func TestProgram_MarshalJSONTrivia(t *testing.T) {
+
+	t.Parallel()
+
+	program := NewProgram(nil, []Declaration{})
+
+	comment := Trivia{
+		Kind: TriviaKindLineComment,
+		Text: "// test",
+		Range: Range{
+			StartPos: Position{Offset: 0, Line: 1, Column: 0},
+			EndPos:   Position{Offset: 6, Line: 1, Column: 6},
+		},
+	}
+
+	program.AttachTrivia([]Trivia{comment})
+
+	assert.Equal(t,
+		ElementTrivia{
+			Dangling: []Trivia{comment},
+		},
+		program.ElementTrivia(program),
+	)
+
+	actual, err := json.Marshal(program)
+	require.NoError(t, err)
+
+	assert.JSONEq(t,
+		// language=json
+		`
+        {
+            "Type": "Program",
+            "Declarations": [],
+            "Trivia": [
+                {
+                    "Kind": "TriviaKindLineComment",
+                    "Text": "// test",
+                    "StartPos": {"Offset": 0, "Line": 1, "Column": 0},
+                    "EndPos": {"Offset": 6, "Line": 1, "Column": 6}
+                }
+            ],
+            "ElementTrivia": [
+                {
+                    "ElementType": "ElementTypeProgram",
+                    "StartPos": {"Offset": 0, "Line": 0, "Column": 0},
+                    "EndPos": {"Offset": 0, "Line": 0, "Column": 0},
+                    "Dangling": [
+                        {
+                            "Kind": "TriviaKindLineComment",
+                            "Text": "// test",
+                            "StartPos": {"Offset": 0, "Line": 1, "Column": 0},
+                            "EndPos": {"Offset": 6, "Line": 1, "Column": 6}
+                        }
+                    ]
+                }
+            ]
+        }
+        `,
+		string(actual),
+	)
+}
+
+func TestProgram_Doc(t *testing.T) {
+
+	t.Parallel()
+
+	program := NewProgram(
+		nil,
+		[]Declaration{
+			&ImportDeclaration{
+				Location: common.StringLocation("a"),
+			},
+			&ImportDeclaration{
+				Location: common.StringLocation("b"),
+			},
+			&FunctionDeclaration{
+				Identifier: Identifier{
+					Identifier: "test",
+				},
+				ParameterList: &ParameterList{},
+			},
+			&FunctionDeclaration{
+				Identifier: Identifier{
+					Identifier: "test2",
+				},
+				ParameterList: &ParameterList{},
+			},
+		},
+	)
+
+	// Consecutive imports are not separated by a blank line
+
+	assert.Equal(t,
+		"import \"a\"\n"+
+			"import \"b\"\n"+
+			"\n"+
+			"fun test()\n"+
+			"\n"+
+			"fun test2()",
+		Prettier(program),
+	)
+}
@@ -0,0 +1,267 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+import (
+	"bytes"
+	"sort"
+)
+
+// Trivia is source code which has no meaning for the program,
+// i.e. a comment or a blank line.
+//
+// Trivia is only available if the program was parsed with trivia enabled
+type Trivia struct {
+	// Text is the source code of a comment, including the delimiters.
+	// Text is empty for blank lines
+	Text string
+	Range
+	Kind TriviaKind
+}
+
+var blockCommentDocStringPrefix = []byte("/**")
+var lineCommentDocStringPrefix = []byte("///")
+
+// IsDocString returns true if the trivia is a documentation comment,
+// i.e. a line comment starting with `///`, or a block comment starting with `/**`
+func (t Trivia) IsDocString() bool {
+	switch t.Kind {
+	case TriviaKindLineComment:
+		return bytes.HasPrefix([]byte(t.Text), lineCommentDocStringPrefix)
+	case TriviaKindBlockComment:
+		return bytes.HasPrefix([]byte(t.Text), blockCommentDocStringPrefix)
+	default:
+		return false
+	}
+}
+
+// ElementTrivia is the trivia attached to an element.
+//
+// Leading trivia precedes the element, trailing trivia follows it.
+// Dangling trivia is inside of an element which has no children the trivia could be attached to,
+// e.g. a comment in an empty block
+type ElementTrivia struct {
+	Leading  []Trivia `json:",omitempty"`
+	Trailing []Trivia `json:",omitempty"`
+	Dangling []Trivia `json:",omitempty"`
+}
+
+// AttachTrivia attaches the given trivia, which must be in source order,
+// to the elements of the program.
+//
+// Each trivia is attached to a child of the innermost element that contains it:
+// A comment which ends a line is a trailing trivia of the preceding child on the same line,
+// other trivia is leading trivia of the following child, if any,
+// and trailing trivia of the preceding child otherwise.
+// Trivia of an element without children is dangling trivia of the element.
+//
+// Only elements which are walked are considered, e.g. a comment in a parameter list
+// is attached to a following element
+func (p *Program) AttachTrivia(trivia []Trivia) {
+	p.trivia = trivia
+	p.elementTrivia = map[Element]*ElementTrivia{}
+	p.triviaElements = nil
+
+	extents := map[Element]Range{}
+
+	for _, t := range trivia {
+		p.attachTrivia(t, extents)
+	}
+}
+
+func (p *Program) attachTrivia(trivia Trivia, extents map[Element]Range) {
+	var parent Element = p
+
+	for {
+		var preceding, following, enclosing Element
+		var precedingExtent, followingExtent Range
+
+		for _, child := range sortedChildren(parent, extents) {
+			extent := elementExtent(child, extents)
+
+			switch {
+			case extent.EndPos.Offset < trivia.StartPos.Offset:
+				preceding = child
+				precedingExtent = extent
+
+			case extent.StartPos.Offset > trivia.EndPos.Offset:
+				if following == nil {
+					following = child
+					followingExtent = extent
+				}
+
+			default:
+				enclosing = child
+			}
+		}
+
+		if enclosing != nil {
+			parent = enclosing
+			continue
+		}
+
+		switch {
+		case trivia.Kind != TriviaKindBlankLine &&
+			preceding != nil &&
+			precedingExtent.EndPos.Line == trivia.StartPos.Line &&
+			(following == nil || followingExtent.StartPos.Line != trivia.EndPos.Line):
+
+			elementTrivia := p.elementTriviaForUpdate(preceding)
+			elementTrivia.Trailing = append(elementTrivia.Trailing, trivia)
+
+		case following != nil:
+			elementTrivia := p.elementTriviaForUpdate(following)
+			elementTrivia.Leading = append(elementTrivia.Leading, trivia)
+
+		case preceding != nil:
+			elementTrivia := p.elementTriviaForUpdate(preceding)
+			elementTrivia.Trailing = append(elementTrivia.Trailing, trivia)
+
+		default:
+			elementTrivia := p.elementTriviaForUpdate(parent)
+			elementTrivia.Dangling = append(elementTrivia.Dangling, trivia)
+		}
+
+		return
+	}
+}
+
+func (p *Program) elementTriviaForUpdate(element Element) *ElementTrivia {
+	elementTrivia, ok := p.elementTrivia[element]
+	if !ok {
+		elementTrivia = &ElementTrivia{}
+		p.elementTrivia[element] = elementTrivia
+		p.triviaElements = append(p.triviaElements, element)
+	}
+	return elementTrivia
+}
+
+// sortedChildren returns the children of the given element, in source order
+func sortedChildren(element Element, extents map[Element]Range) []Element {
+	var children []Element
+	element.Walk(func(child Element) {
+		children = append(children, child)
+	})
+
+	sort.SliceStable(children, func(i, j int) bool {
+		return elementExtent(children[i], extents).StartPos.Offset <
+			elementExtent(children[j], extents).StartPos.Offset
+	})
+
+	return children
+}
+
+// ElementExtent returns the range of the source code of the given element, including all its children.
+//
+// The range of an element does not necessarily include all of its children,
+// e.g. the range of an index expression starts at the opening bracket
+func ElementExtent(element Element) Range {
+	return elementExtent(element, nil)
+}
+
+func elementExtent(element Element, cache map[Element]Range) Range {
+	if extent, ok := cache[element]; ok {
+		return extent
+	}
+
+	extent := Range{
+		StartPos: element.StartPosition(),
+		EndPos:   element.EndPosition(nil),
+	}
+
+	// Lines start at 1, so a zero line indicates an element without a position
+	hasPosition := extent.StartPos.Line != 0
+
+	element.Walk(func(child Element) {
+		childExtent := elementExtent(child, cache)
+
+		if childExtent.StartPos.Line == 0 {
+			return
+		}
+
+		if !hasPosition {
+			extent = childExtent
+			hasPosition = true
+			return
+		}
+
+		if childExtent.StartPos.Offset < extent.StartPos.Offset {
+			extent.StartPos = childExtent.StartPos
+		}
+		if childExtent.EndPos.Offset > extent.EndPos.Offset {
+			extent.EndPos = childExtent.EndPos
+		}
+	})
+
+	if cache != nil {
+		cache[element] = extent
+	}
+
+	return extent
+}
+
+// Trivia returns all trivia of the program, in source order
+func (p *Program) Trivia() []Trivia {
+	return p.trivia
+}
+
+// ElementTrivia returns the trivia attached to the given element
+func (p *Program) ElementTrivia(element Element) ElementTrivia {
+	elementTrivia, ok := p.elementTrivia[element]
+	if !ok {
+		return ElementTrivia{}
+	}
+	return *elementTrivia
+}
+
+type elementTriviaJSON struct {
+	ElementType string
+	Range
+	ElementTrivia
+}
+
+// elementTriviaJSON returns the trivia of all elements,
+// ordered by the positions of the elements, outer elements first
+func (p *Program) elementTriviaJSON() []elementTriviaJSON {
+	if len(p.triviaElements) == 0 {
+		return nil
+	}
+
+	result := make([]elementTriviaJSON, 0, len(p.triviaElements))
+	for _, element := range p.triviaElements {
+		result = append(result, elementTriviaJSON{
+			ElementType: element.ElementType().String(),
+			Range: Range{
+				StartPos: element.StartPosition(),
+				EndPos:   element.EndPosition(nil),
+			},
+			ElementTrivia: *p.elementTrivia[element],
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.StartPos.Offset != b.StartPos.Offset {
+			return a.StartPos.Offset < b.StartPos.Offset
+		}
+		return a.EndPos.Offset > b.EndPos.Offset
+	})
+
+	return result
+}
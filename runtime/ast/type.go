@@ -274,16 +274,18 @@ const arrayTypeStartDoc = prettier.Text("[")
 const arrayTypeEndDoc = prettier.Text("]")
 
 func (t *VariableSizedType) Doc() prettier.Doc {
-	return prettier.Concat{
-		arrayTypeStartDoc,
-		prettier.Indent{
-			Doc: prettier.Concat{
-				prettier.SoftLine{},
-				t.Type.Doc(),
+	return prettier.Group{
+		Doc: prettier.Concat{
+			arrayTypeStartDoc,
+			prettier.Indent{
+				Doc: prettier.Concat{
+					prettier.SoftLine{},
+					t.Type.Doc(),
+				},
 			},
+			prettier.SoftLine{},
+			arrayTypeEndDoc,
 		},
-		prettier.SoftLine{},
-		arrayTypeEndDoc,
 	}
 }
 
@@ -335,18 +337,20 @@ func (t *ConstantSizedType) String() string {
 const constantSizedTypeSeparatorSpaceDoc = prettier.Text("; ")
 
 func (t *ConstantSizedType) Doc() prettier.Doc {
-	return prettier.Concat{
-		arrayTypeStartDoc,
-		prettier.Indent{
-			Doc: prettier.Concat{
-				prettier.SoftLine{},
-				t.Type.Doc(),
-				constantSizedTypeSeparatorSpaceDoc,
-				t.Size.Doc(),
+	return prettier.Group{
+		Doc: prettier.Concat{
+			arrayTypeStartDoc,
+			prettier.Indent{
+				Doc: prettier.Concat{
+					prettier.SoftLine{},
+					t.Type.Doc(),
+					constantSizedTypeSeparatorSpaceDoc,
+					t.Size.Doc(),
+				},
 			},
+			prettier.SoftLine{},
+			arrayTypeEndDoc,
 		},
-		prettier.SoftLine{},
-		arrayTypeEndDoc,
 	}
 }
 
@@ -399,18 +403,20 @@ const dictionaryTypeStartDoc = prettier.Text("{")
 const dictionaryTypeEndDoc = prettier.Text("}")
 
 func (t *DictionaryType) Doc() prettier.Doc {
-	return prettier.Concat{
-		dictionaryTypeStartDoc,
-		prettier.Indent{
-			Doc: prettier.Concat{
-				prettier.SoftLine{},
-				t.KeyType.Doc(),
-				typeSeparatorSpaceDoc,
-				t.ValueType.Doc(),
+	return prettier.Group{
+		Doc: prettier.Concat{
+			dictionaryTypeStartDoc,
+			prettier.Indent{
+				Doc: prettier.Concat{
+					prettier.SoftLine{},
+					t.KeyType.Doc(),
+					typeSeparatorSpaceDoc,
+					t.ValueType.Doc(),
+				},
 			},
+			prettier.SoftLine{},
+			dictionaryTypeEndDoc,
 		},
-		prettier.SoftLine{},
-		dictionaryTypeEndDoc,
 	}
 }
 
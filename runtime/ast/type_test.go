@@ -349,16 +349,18 @@ func TestVariableSizedType_Doc(t *testing.T) {
 	}
 
 	assert.Equal(t,
-		prettier.Concat{
-			prettier.Text("["),
-			prettier.Indent{
-				Doc: prettier.Concat{
-					prettier.SoftLine{},
-					prettier.Text("T"),
+		prettier.Group{
+			Doc: prettier.Concat{
+				prettier.Text("["),
+				prettier.Indent{
+					Doc: prettier.Concat{
+						prettier.SoftLine{},
+						prettier.Text("T"),
+					},
 				},
+				prettier.SoftLine{},
+				prettier.Text("]"),
 			},
-			prettier.SoftLine{},
-			prettier.Text("]"),
 		},
 		ty.Doc(),
 	)
@@ -443,18 +445,20 @@ func TestConstantSizedType_Doc(t *testing.T) {
 	}
 
 	assert.Equal(t,
-		prettier.Concat{
-			prettier.Text("["),
-			prettier.Indent{
-				Doc: prettier.Concat{
-					prettier.SoftLine{},
-					prettier.Text("T"),
-					prettier.Text("; "),
-					prettier.Text("42"),
+		prettier.Group{
+			Doc: prettier.Concat{
+				prettier.Text("["),
+				prettier.Indent{
+					Doc: prettier.Concat{
+						prettier.SoftLine{},
+						prettier.Text("T"),
+						prettier.Text("; "),
+						prettier.Text("42"),
+					},
 				},
+				prettier.SoftLine{},
+				prettier.Text("]"),
 			},
-			prettier.SoftLine{},
-			prettier.Text("]"),
 		},
 		ty.Doc(),
 	)
@@ -561,18 +565,20 @@ func TestDictionaryType_Doc(t *testing.T) {
 	}
 
 	assert.Equal(t,
-		prettier.Concat{
-			prettier.Text("{"),
-			prettier.Indent{
-				Doc: prettier.Concat{
-					prettier.SoftLine{},
-					prettier.Text("AB"),
-					prettier.Text(": "),
-					prettier.Text("CD"),
+		prettier.Group{
+			Doc: prettier.Concat{
+				prettier.Text("{"),
+				prettier.Indent{
+					Doc: prettier.Concat{
+						prettier.SoftLine{},
+						prettier.Text("AB"),
+						prettier.Text(": "),
+						prettier.Text("CD"),
+					},
 				},
+				prettier.SoftLine{},
+				prettier.Text("}"),
 			},
-			prettier.SoftLine{},
-			prettier.Text("}"),
 		},
 		ty.Doc(),
 	)
@@ -0,0 +1,525 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+func TestRuntimeBytecodeVM(t *testing.T) {
+
+	t.Parallel()
+
+	type result struct {
+		value       cadence.Value
+		err         error
+		computation map[common.ComputationKind]uint
+	}
+
+	execute := func(t *testing.T, script string, bytecodeVMEnabled bool, arguments ...cadence.Value) result {
+
+		runtime := newTestInterpreterRuntime()
+		runtime.defaultConfig.ScriptBytecodeVMEnabled = bytecodeVMEnabled
+
+		computation := map[common.ComputationKind]uint{}
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+			meterComputation: func(compKind common.ComputationKind, intensity uint) error {
+				computation[compKind] += intensity
+				return nil
+			},
+			decodeArgument: func(b []byte, t cadence.Type) (cadence.Value, error) {
+				return json.Decode(nil, b)
+			},
+		}
+
+		value, err := runtime.ExecuteScript(
+			Script{
+				Source:    []byte(script),
+				Arguments: encodeArgs(arguments),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.ScriptLocation{},
+			},
+		)
+
+		return result{
+			value:       value,
+			err:         err,
+			computation: computation,
+		}
+	}
+
+	// assertSameResult executes the script with the interpreter and with the bytecode VM,
+	// and asserts that the results, and the metered statements, function invocations and loop iterations are equal
+	assertSameResult := func(t *testing.T, script string, arguments ...cadence.Value) result {
+
+		interpreted := execute(t, script, false, arguments...)
+		compiled := execute(t, script, true, arguments...)
+
+		require.Equal(t, interpreted.value, compiled.value)
+
+		if interpreted.err == nil {
+			require.NoError(t, compiled.err)
+		} else {
+			require.Error(t, compiled.err)
+		}
+
+		for _, kind := range []common.ComputationKind{
+			common.ComputationKindStatement,
+			common.ComputationKindFunctionInvocation,
+			common.ComputationKindLoop,
+		} {
+			require.Equal(t,
+				interpreted.computation[kind],
+				compiled.computation[kind],
+				kind.String(),
+			)
+		}
+
+		return compiled
+	}
+
+	t.Run("functions and loops", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := assertSameResult(t,
+			`
+              pub fun fib(_ n: Int): Int {
+                  if n < 2 {
+                      return n
+                  }
+                  return fib(n - 1) + fib(n - 2)
+              }
+
+              pub fun main(n: Int): [Int] {
+                  let results: [Int] = []
+                  var i = 0
+                  while i < n {
+                      results.append(fib(i))
+                      i = i + 1
+                  }
+                  return results
+              }
+            `,
+			cadence.NewInt(8),
+		)
+
+		require.NoError(t, result.err)
+		require.Equal(t,
+			cadence.NewArray([]cadence.Value{
+				cadence.NewInt(0),
+				cadence.NewInt(1),
+				cadence.NewInt(1),
+				cadence.NewInt(2),
+				cadence.NewInt(3),
+				cadence.NewInt(5),
+				cadence.NewInt(8),
+				cadence.NewInt(13),
+			}).WithType(cadence.NewVariableSizedArrayType(cadence.IntType{})),
+			result.value,
+		)
+	})
+
+	t.Run("composites and optionals", func(t *testing.T) {
+
+		t.Parallel()
+
+		assertSameResult(t, `
+          pub struct Counter {
+              pub var count: Int
+
+              init() {
+                  self.count = 0
+              }
+
+              pub fun increment() {
+                  self.count = self.count + 1
+              }
+          }
+
+          pub fun count(_ values: {String: Int}, _ key: String): Int? {
+              let counter = Counter()
+              if let value = values[key] {
+                  for _ in [1, 2, 3] {
+                      counter.increment()
+                  }
+                  return counter.count + value
+              }
+              return nil
+          }
+
+          pub fun main(): [Int?] {
+              let values = {"a": 1}
+              return [count(values, "a"), count(values, "b")]
+          }
+        `)
+	})
+
+	t.Run("loop", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := assertSameResult(t, `
+          pub fun main(): Int {
+              var sum = 0
+              var i = 0
+              while i < 100 {
+                  sum = sum + i
+                  i = i + 1
+              }
+              return sum
+          }
+        `)
+
+		require.NoError(t, result.err)
+		require.Equal(t, cadence.NewInt(4950), result.value)
+		require.Equal(t, uint(204), result.computation[common.ComputationKindStatement])
+		require.Equal(t, uint(100), result.computation[common.ComputationKindLoop])
+	})
+
+	t.Run("switch", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := assertSameResult(t, `
+          pub fun main(): String {
+              switch 2 {
+                  case 1:
+                      return "one"
+                  case 2:
+                      return "two"
+              }
+              return "other"
+          }
+        `)
+
+		require.NoError(t, result.err)
+		require.Equal(t, cadence.String("two"), result.value)
+		require.Equal(t, uint(2), result.computation[common.ComputationKindStatement])
+	})
+
+	t.Run("failed condition", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := assertSameResult(t, `
+          pub fun main(): Int {
+              pre {
+                  1 > 2: "impossible"
+              }
+              return 1
+          }
+        `)
+
+		require.ErrorAs(t, result.err, &interpreter.ConditionError{})
+	})
+
+	t.Run("error location", func(t *testing.T) {
+
+		t.Parallel()
+
+		script := `
+          pub fun divide(_ a: Int, _ b: Int): Int {
+              return a / b
+          }
+
+          pub fun main(): Int {
+              let a = 1
+              return divide(a, 0) + 1
+          }
+        `
+
+		interpreted := execute(t, script, false)
+		compiled := assertSameResult(t, script)
+
+		// Compiled code reports the location range of the failed statement,
+		// the interpreter reports the location range of the failed expression
+
+		var compiledErr interpreter.DivisionByZeroError
+		require.ErrorAs(t, compiled.err, &compiledErr)
+		require.Equal(t,
+			ast.Position{Offset: 67, Line: 3, Column: 14},
+			compiledErr.StartPosition(),
+		)
+		require.Equal(t, common.ScriptLocation{}, compiledErr.Location)
+
+		var interpretedErr interpreter.DivisionByZeroError
+		require.ErrorAs(t, interpreted.err, &interpretedErr)
+		require.Equal(t,
+			interpretedErr.StartPosition().Line,
+			compiledErr.StartPosition().Line,
+		)
+	})
+
+	t.Run("unsupported feature", func(t *testing.T) {
+
+		t.Parallel()
+
+		// Function expressions are not supported by the compiler yet,
+		// so the script is interpreted
+
+		result := assertSameResult(t, `
+          pub fun main(): Int {
+              let f = fun (): Int { return 42 }
+              return f()
+          }
+        `)
+
+		require.NoError(t, result.err)
+		require.Equal(t, cadence.NewInt(42), result.value)
+	})
+
+	t.Run("compiled program is cached", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := newTestInterpreterRuntime()
+		runtime.defaultConfig.ScriptBytecodeVMEnabled = true
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+		}
+
+		script := Script{
+			Source: []byte(`
+              pub fun main(): Int {
+                  return 42
+              }
+            `),
+		}
+
+		for i := 0; i < 2; i++ {
+			value, err := runtime.ExecuteScript(
+				script,
+				Context{
+					Interface: runtimeInterface,
+					Location:  common.ScriptLocation{},
+				},
+			)
+			require.NoError(t, err)
+			require.Equal(t, cadence.NewInt(42), value)
+		}
+
+		require.Len(t, runtime.compiledPrograms.entries, 1)
+	})
+}
+
+func TestRuntimeBytecodeVMTransaction(t *testing.T) {
+
+	t.Parallel()
+
+	type result struct {
+		err         error
+		logs        []string
+		computation map[common.ComputationKind]uint
+		compiled    bool
+	}
+
+	execute := func(t *testing.T, transaction string, bytecodeVMEnabled bool, arguments ...cadence.Value) result {
+
+		runtime := newTestInterpreterRuntime()
+		runtime.defaultConfig.ScriptBytecodeVMEnabled = bytecodeVMEnabled
+
+		computation := map[common.ComputationKind]uint{}
+		var logs []string
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{
+					common.MustBytesToAddress([]byte{0x1}),
+				}, nil
+			},
+			log: func(message string) {
+				logs = append(logs, message)
+			},
+			meterComputation: func(compKind common.ComputationKind, intensity uint) error {
+				computation[compKind] += intensity
+				return nil
+			},
+			decodeArgument: func(b []byte, t cadence.Type) (cadence.Value, error) {
+				return json.Decode(nil, b)
+			},
+		}
+
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source:    []byte(transaction),
+				Arguments: encodeArgs(arguments),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.TransactionLocation{},
+			},
+		)
+
+		compiled := false
+		for _, element := range runtime.compiledPrograms.entries { //nolint:maprange
+			if element.Value.(*compiledProgram).compiled != nil {
+				compiled = true
+			}
+		}
+
+		return result{
+			err:         err,
+			logs:        logs,
+			computation: computation,
+			compiled:    compiled,
+		}
+	}
+
+	// assertSameResult executes the transaction with the interpreter and with the bytecode VM,
+	// and asserts that the logs, and the metered statements, function invocations and loop iterations are equal
+	assertSameResult := func(t *testing.T, transaction string, arguments ...cadence.Value) result {
+
+		interpreted := execute(t, transaction, false, arguments...)
+		compiled := execute(t, transaction, true, arguments...)
+
+		if interpreted.err == nil {
+			require.NoError(t, compiled.err)
+		} else {
+			require.Error(t, compiled.err)
+		}
+
+		require.Equal(t, interpreted.logs, compiled.logs)
+
+		for _, kind := range []common.ComputationKind{
+			common.ComputationKindStatement,
+			common.ComputationKindFunctionInvocation,
+			common.ComputationKindLoop,
+		} {
+			require.Equal(t,
+				interpreted.computation[kind],
+				compiled.computation[kind],
+				kind.String(),
+			)
+		}
+
+		return compiled
+	}
+
+	const transaction = `
+      transaction(amount: Int) {
+          let address: Address
+          var total: Int
+
+          prepare(signer: AuthAccount) {
+              self.address = signer.address
+              self.total = amount
+              if amount != 0 {
+                  return
+              }
+              self.total = 0
+          }
+
+          pre {
+              self.total >= 0: "negative total"
+          }
+
+          execute {
+              var i = 0
+              while i < 3 {
+                  self.total = self.total + i
+                  i = i + 1
+              }
+              log(self.total)
+              log(self.address)
+          }
+
+          post {
+              self.total >= 3: "invalid total"
+          }
+      }
+    `
+
+	t.Run("prepare, conditions and execute", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := assertSameResult(t, transaction, cadence.NewInt(2))
+
+		require.NoError(t, result.err)
+		require.True(t, result.compiled)
+		require.Equal(t,
+			[]string{"5", "0x0000000000000001"},
+			result.logs,
+		)
+		require.Equal(t, uint(3), result.computation[common.ComputationKindLoop])
+	})
+
+	t.Run("prepare without return", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := assertSameResult(t, transaction, cadence.NewInt(0))
+
+		require.NoError(t, result.err)
+		require.True(t, result.compiled)
+		require.Equal(t,
+			[]string{"3", "0x0000000000000001"},
+			result.logs,
+		)
+	})
+
+	t.Run("failed pre-condition", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := assertSameResult(t, transaction, cadence.NewInt(-2))
+
+		require.True(t, result.compiled)
+		require.ErrorAs(t, result.err, &interpreter.ConditionError{})
+		require.Empty(t, result.logs)
+	})
+
+	t.Run("unsupported feature", func(t *testing.T) {
+
+		t.Parallel()
+
+		// Function expressions are not supported by the compiler yet,
+		// so the transaction is interpreted
+
+		result := assertSameResult(t, `
+          transaction {
+              prepare(signer: AuthAccount) {}
+
+              execute {
+                  let f = fun (): Int { return 42 }
+                  log(f())
+              }
+          }
+        `)
+
+		require.NoError(t, result.err)
+		require.False(t, result.compiled)
+		require.Equal(t, []string{"42"}, result.logs)
+	})
+}
@@ -21,6 +21,7 @@ package main
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -36,6 +37,7 @@ import (
 	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/pretty"
 	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/tools/analysis"
 )
 
 type memberAccountAccessFlags []string
@@ -51,6 +53,9 @@ func (f *memberAccountAccessFlags) Set(value string) error {
 
 var benchFlag = flag.Bool("bench", false, "benchmark the checker")
 var jsonFlag = flag.Bool("json", false, "print the result formatted as JSON")
+var fixFlag = flag.Bool("fix", false, "apply the suggested fixes of the errors to the files")
+var diffFlag = flag.Bool("diff", false, "print the suggested fixes of the errors as a diff, without applying them")
+var formatFlag = flag.String("format", "text", cmd.ReportFormatUsage)
 
 var memberAccountAccessFlag memberAccountAccessFlags
 
@@ -86,8 +91,21 @@ func main() {
 		nested[targetLocation] = struct{}{}
 	}
 
+	reportFormat, err := cmd.ParseReportFormat(*formatFlag)
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+
+	if reportFormat != nil && (*jsonFlag || *diffFlag) {
+		cmd.ExitWithError("cannot combine the -json or -diff flags with the -format flag")
+	}
+
 	args := flag.Args()
-	run(args, *benchFlag, *jsonFlag, memberAccountAccess)
+	if (*fixFlag || *diffFlag) && len(args) == 0 {
+		cmd.ExitWithError("cannot fix the standard input, missing paths of files")
+	}
+
+	run(args, *benchFlag, *jsonFlag, reportFormat, *fixFlag, *diffFlag, memberAccountAccess)
 }
 
 type benchResult struct {
@@ -102,6 +120,9 @@ type result struct {
 	Bench    *benchResult `json:"bench,omitempty"`
 	BenchStr string       `json:"-"`
 	Error    string       `json:"error,omitempty"`
+	Diff     string       `json:"diff,omitempty"`
+	// Diagnostics are the errors as diagnostics, which are reported in machine-readable formats
+	Diagnostics []analysis.Diagnostic `json:"-"`
 }
 
 type output interface {
@@ -153,6 +174,13 @@ func (s stdoutOutput) Append(r result) {
 		}
 	}
 
+	if len(r.Diff) > 0 {
+		_, err = fmt.Fprint(s.writer, r.Diff)
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	if len(r.Error) > 0 {
 		_, err = fmt.Fprintf(s.writer, "error:\t%s\n", r.Error)
 		if err != nil {
@@ -170,6 +198,23 @@ func (s stdoutOutput) End() {
 	// no-op
 }
 
+// reportOutput reports the errors of all results as diagnostics, in a machine-readable format
+type reportOutput struct {
+	diagnostics []analysis.Diagnostic
+	format      analysis.ReportFormat
+}
+
+func (r *reportOutput) Append(res result) {
+	r.diagnostics = append(r.diagnostics, res.Diagnostics...)
+}
+
+func (r *reportOutput) End() {
+	err := analysis.WriteReport(os.Stdout, r.format, "cadence-check", r.diagnostics)
+	if err != nil {
+		panic(err)
+	}
+}
+
 func newStdoutOutput() stdoutOutput {
 	return stdoutOutput{
 		writer: tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0),
@@ -180,6 +225,9 @@ func run(
 	paths []string,
 	bench bool,
 	json bool,
+	reportFormat *analysis.ReportFormat,
+	fix bool,
+	diff bool,
 	memberAccountAccess map[common.Location]map[common.Location]struct{},
 ) {
 	if len(paths) == 0 {
@@ -189,16 +237,21 @@ func run(
 	allSucceeded := true
 
 	var out output
-	if json {
+	switch {
+	case reportFormat != nil:
+		out = &reportOutput{
+			format: *reportFormat,
+		}
+	case json:
 		out = newJSONOutput(len(paths))
-	} else {
+	default:
 		out = newStdoutOutput()
 	}
 
-	useColor := !json
+	useColor := !json && reportFormat == nil
 
 	for _, path := range paths {
-		res, runSucceeded := runPath(path, bench, useColor, memberAccountAccess)
+		res, runSucceeded := runPath(path, bench, useColor, fix, diff, memberAccountAccess)
 		if !runSucceeded {
 			allSucceeded = false
 		}
@@ -217,6 +270,8 @@ func runPath(
 	path string,
 	bench bool,
 	useColor bool,
+	fix bool,
+	diff bool,
 	memberAccountAccess map[common.Location]map[common.Location]struct{},
 ) (res result, succeeded bool) {
 	res = result{
@@ -230,6 +285,7 @@ func runPath(
 	var checker *sema.Checker
 	var program *ast.Program
 	var must func(error)
+	var fixed bool
 
 	codes := map[common.Location][]byte{}
 
@@ -243,12 +299,36 @@ func runPath(
 			}
 		}()
 
-		program, must = cmd.PrepareProgram(code, location, codes)
+		program, must, err = cmd.ParseProgram(code, location, codes)
+		if err != nil {
+			return
+		}
 
 		checker, _ = cmd.PrepareChecker(program, location, codes, memberAccountAccess, must)
 
 		err = checker.Check()
-		if err != nil {
+		if err != nil && (fix || diff) {
+			var diffBuilder strings.Builder
+			var fixErr error
+			fixed, fixErr = fixErrors(&diffBuilder, path, code, err, diff)
+			if fixErr != nil {
+				err = fixErr
+				res.Error = fixErr.Error()
+				return
+			}
+			res.Diff = diffBuilder.String()
+		}
+	}()
+
+	if fixed {
+		// The file was fixed, check it again to report the remaining errors
+		return runPath(path, bench, useColor, false, false, memberAccountAccess)
+	}
+
+	if err != nil {
+		succeeded = false
+
+		if len(res.Error) == 0 {
 			var builder strings.Builder
 			printErr := pretty.NewErrorPrettyPrinter(&builder, useColor).
 				PrettyPrintError(err, location, codes)
@@ -257,10 +337,8 @@ func runPath(
 			}
 			res.Error = builder.String()
 		}
-	}()
 
-	if err != nil {
-		succeeded = false
+		res.Diagnostics = analysis.ErrorDiagnostics(err, location, codes)
 	}
 
 	if bench && err == nil {
@@ -283,6 +361,50 @@ func runPath(
 	return res, succeeded
 }
 
+// fixErrors applies the first suggested fix of each of the given checker errors, if any.
+// It returns true if any fix was applied to the file.
+//
+// If diff is true, the file is not written, instead the differences are written to the given writer
+func fixErrors(writer io.Writer, path string, code []byte, err error, diff bool) (bool, error) {
+	var checkerError *sema.CheckerError
+	if !errors.As(err, &checkerError) {
+		return false, nil
+	}
+
+	var fixes []analysis.SuggestedFix
+
+	for _, childErr := range checkerError.Errors {
+		hasFixes, ok := childErr.(sema.HasSuggestedFixes)
+		if !ok {
+			continue
+		}
+
+		suggestedFixes := hasFixes.SuggestFixes(string(code))
+		if len(suggestedFixes) == 0 {
+			continue
+		}
+
+		fixes = append(fixes, suggestedFixes[0])
+	}
+
+	if len(fixes) == 0 {
+		return false, nil
+	}
+
+	applied, err := cmd.ApplyFixes(writer, path, code, fixes, diff)
+	if err != nil || diff {
+		return false, err
+	}
+
+	for _, fixApplied := range applied {
+		if fixApplied {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func read(path string) []byte {
 	var data []byte
 	var err error
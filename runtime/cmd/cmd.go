@@ -21,6 +21,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/onflow/cadence/runtime/activations"
 
@@ -61,13 +62,21 @@ func PrepareProgramFromFile(location common.StringLocation, codes map[common.Loc
 }
 
 func PrepareProgram(code []byte, location common.Location, codes map[common.Location][]byte) (*ast.Program, func(error)) {
+	program, must, err := ParseProgram(code, location, codes)
+	must(err)
+
+	return program, must
+}
+
+// ParseProgram parses the given code of the program with the given location.
+// Unlike PrepareProgram, a parsing error is returned instead of printed
+func ParseProgram(code []byte, location common.Location, codes map[common.Location][]byte) (*ast.Program, func(error), error) {
 	must := mustClosure(location, codes)
 
 	program, err := parser.ParseProgram(nil, code, parser.Config{})
 	codes[location] = code
-	must(err)
 
-	return program, must
+	return program, must, err
 }
 
 var checkers = map[common.Location]*sema.Checker{}
@@ -128,6 +137,32 @@ func DefaultCheckerConfig(
 				Elaboration: importedChecker.Elaboration,
 			}, nil
 		},
+		ImportSuggestionHandler: func(checker *sema.Checker, identifier string) []common.Location {
+			return suggestFileImports(checker.Location, identifier)
+		},
+	}
+}
+
+// suggestFileImports returns the location of the file named after the given identifier,
+// if it exists in the directory of the file with the given location
+func suggestFileImports(location common.Location, identifier string) []common.Location {
+	stringLocation, ok := location.(common.StringLocation)
+	if !ok || len(stringLocation) == 0 {
+		return nil
+	}
+
+	path := filepath.Join(filepath.Dir(string(stringLocation)), identifier+".cdc")
+	if path == filepath.Clean(string(stringLocation)) {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return nil
+	}
+
+	return []common.Location{
+		common.StringLocation(path),
 	}
 }
 
@@ -141,6 +176,19 @@ func PrepareChecker(
 	must func(error),
 ) (*sema.Checker, func(error)) {
 
+	checker, err := newChecker(program, location, codes, memberAccountAccess)
+	must(err)
+
+	return checker, must
+}
+
+func newChecker(
+	program *ast.Program,
+	location common.Location,
+	codes map[common.Location][]byte,
+	memberAccountAccess map[common.Location]map[common.Location]struct{},
+) (*sema.Checker, error) {
+
 	config := DefaultCheckerConfig(checkers, codes)
 
 	config.MemberAccountAccessHandler = func(checker *sema.Checker, memberLocation common.Location) bool {
@@ -157,29 +205,17 @@ func PrepareChecker(
 		return ok
 	}
 
-	checker, err := sema.NewChecker(
+	return sema.NewChecker(
 		program,
 		location,
 		nil,
 		config,
 	)
-	must(err)
-
-	return checker, must
 }
 
-func PrepareInterpreter(filename string, debugger *interpreter.Debugger) (*interpreter.Interpreter, *sema.Checker, func(error)) {
-
-	codes := map[common.Location][]byte{}
-
-	// do not need to meter this as it's a one-off overhead
-	location := common.NewStringLocation(nil, filename)
-
-	program, must := PrepareProgramFromFile(location, codes)
-
-	checker, must := PrepareChecker(program, location, codes, nil, must)
-
-	must(checker.Check())
+// NewInterpreterConfig returns the interpreter configuration used by the commands,
+// with an in-memory storage and a `log` function which writes to the given logger
+func NewInterpreterConfig(logger stdlib.Logger, debugger *interpreter.Debugger) *interpreter.Config {
 
 	var uuid uint64
 
@@ -189,9 +225,9 @@ func PrepareInterpreter(filename string, debugger *interpreter.Debugger) (*inter
 	// as predeclared values may rely on storage
 
 	baseActivation := activations.NewActivation(nil, interpreter.BaseActivation)
-	interpreter.Declare(baseActivation, stdlib.NewLogFunction(StandardOutputLogger{}))
+	interpreter.Declare(baseActivation, stdlib.NewLogFunction(logger))
 
-	config := &interpreter.Config{
+	return &interpreter.Config{
 		BaseActivation: baseActivation,
 		Storage:        storage,
 		UUIDHandler: func() (uint64, error) {
@@ -203,17 +239,79 @@ func PrepareInterpreter(filename string, debugger *interpreter.Debugger) (*inter
 			panic("Importing programs is not supported yet")
 		},
 	}
+}
+
+func PrepareInterpreter(filename string, debugger *interpreter.Debugger) (*interpreter.Interpreter, *sema.Checker, func(error)) {
+	config := NewInterpreterConfig(StandardOutputLogger{}, debugger)
+	return PrepareInterpreterWithConfig(filename, config)
+}
+
+// PrepareInterpreterWithConfig prepares an interpreter for the program in the file with the given name,
+// like PrepareInterpreter, but with the given interpreter configuration
+func PrepareInterpreterWithConfig(filename string, config *interpreter.Config) (*interpreter.Interpreter, *sema.Checker, func(error)) {
+
+	codes := map[common.Location][]byte{}
+
+	// do not need to meter this as it's a one-off overhead
+	location := common.NewStringLocation(nil, filename)
+
+	must := mustClosure(location, codes)
+
+	inter, checker, err := LoadInterpreter(filename, config, codes)
+	must(err)
+
+	return inter, checker, must
+}
+
+// LoadInterpreter prepares an interpreter for the program in the file with the given name,
+// like PrepareInterpreterWithConfig, but returns errors instead of printing them and exiting.
+// The codes of the loaded programs are added to the given codes, e.g. for pretty-printing errors
+func LoadInterpreter(
+	filename string,
+	config *interpreter.Config,
+	codes map[common.Location][]byte,
+) (
+	*interpreter.Interpreter,
+	*sema.Checker,
+	error,
+) {
+	location := common.NewStringLocation(nil, filename)
+
+	code, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	program, _, err := ParseProgram(code, location, codes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	checker, err := newChecker(program, location, codes, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = checker.Check()
+	if err != nil {
+		return nil, nil, err
+	}
 
 	inter, err := interpreter.NewInterpreter(
 		interpreter.ProgramFromChecker(checker),
 		checker.Location,
 		config,
 	)
-	must(err)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	must(inter.Interpret())
+	err = inter.Interpret()
+	if err != nil {
+		return nil, nil, err
+	}
 
-	return inter, checker, must
+	return inter, checker, nil
 }
 
 func ExitWithError(message string) {
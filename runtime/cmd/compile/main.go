@@ -52,34 +52,32 @@ func main() {
 
 	comp := compiler.NewCompiler(checker)
 
-	functionDeclarations := checker.Program.FunctionDeclarations()
-
-	funcs := make([]*ir.Func, len(functionDeclarations))
-
-	for i, functionDeclaration := range functionDeclarations {
-		funcs[i] = ast.AcceptDeclaration[ir.Stmt](functionDeclaration, comp).(*ir.Func)
-	}
+	funcs := comp.VisitProgram(checker.Program).([]*ir.Func)
 
 	// Generate a WebAssembly module for the functions
 
 	module := compiler.GenerateWasm(funcs)
 
-	// Export all public functions
+	// Only export public functions
 
-	for i, functionDeclaration := range functionDeclarations {
+	publicFunctions := map[string]struct{}{}
+	for _, functionDeclaration := range checker.Program.FunctionDeclarations() {
 		if functionDeclaration.Access != ast.AccessPublic {
 			continue
 		}
+		publicFunctions[functionDeclaration.Identifier.Identifier] = struct{}{}
+	}
 
-		module.Exports = append(module.Exports,
-			&wasm.Export{
-				Name: functionDeclaration.Identifier.Identifier,
-				Descriptor: wasm.FunctionExport{
-					FunctionIndex: uint32(i),
-				},
-			},
-		)
+	exports := module.Exports[:0]
+	for _, export := range module.Exports {
+		if _, ok := export.Descriptor.(wasm.FunctionExport); ok {
+			if _, ok := publicFunctions[export.Name]; !ok {
+				continue
+			}
+		}
+		exports = append(exports, export)
 	}
+	module.Exports = exports
 
 	// Generate WASM binary
 
@@ -87,13 +85,13 @@ func main() {
 	w := wasm.NewWASMWriter(&buf)
 	err := w.WriteModule(module)
 	if err != nil {
-		panic(nil)
+		panic(err)
 	}
 
 	// Write WASM binary to stdout
 
 	_, err = os.Stdout.Write(buf.Bytes())
 	if err != nil {
-		panic(nil)
+		panic(err)
 	}
 }
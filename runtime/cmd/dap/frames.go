@@ -0,0 +1,60 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dap
+
+import (
+	"path/filepath"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+func (s *Server) stackFrame(id int, frame interpreter.Frame) StackFrame {
+	return StackFrame{
+		ID:     id,
+		Name:   frame.FunctionName(),
+		Source: s.locationSource(frame.Location),
+		Line:   frame.Position.Line,
+		// DAP columns are 1-based, AST columns are 0-based
+		Column: frame.Position.Column + 1,
+	}
+}
+
+// locationSource returns the source of the program with the given location.
+// The launched program is read from a file, even if it has a transaction location
+func (s *Server) locationSource(location common.Location) *Source {
+	if s.launch != nil && location == s.location {
+		return pathSource(s.launch.Program)
+	}
+
+	if stringLocation, ok := location.(common.StringLocation); ok {
+		return pathSource(string(stringLocation))
+	}
+
+	return &Source{
+		Name: location.String(),
+	}
+}
+
+func pathSource(path string) *Source {
+	return &Source{
+		Name: filepath.Base(path),
+		Path: path,
+	}
+}
@@ -0,0 +1,235 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dap
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// The types in this file are the subset of the Debug Adapter Protocol
+// (https://microsoft.github.io/debug-adapter-protocol/specification)
+// which is needed to debug programs with the interpreter's debugger.
+
+const (
+	messageTypeRequest  = "request"
+	messageTypeResponse = "response"
+	messageTypeEvent    = "event"
+)
+
+const contentLengthHeader = "Content-Length"
+
+type ProtocolMessage struct {
+	Seq  int    `json:"seq"`
+	Type string `json:"type"`
+}
+
+type Request struct {
+	ProtocolMessage
+	Command   string          `json:"command"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+type Response struct {
+	ProtocolMessage
+	RequestSeq int    `json:"request_seq"`
+	Success    bool   `json:"success"`
+	Command    string `json:"command"`
+	Message    string `json:"message,omitempty"`
+	Body       any    `json:"body,omitempty"`
+}
+
+type Event struct {
+	ProtocolMessage
+	Event string `json:"event"`
+	Body  any    `json:"body,omitempty"`
+}
+
+type Capabilities struct {
+	SupportsConfigurationDoneRequest  bool `json:"supportsConfigurationDoneRequest"`
+	SupportsTerminateRequest          bool `json:"supportsTerminateRequest"`
+	SupportsConditionalBreakpoints    bool `json:"supportsConditionalBreakpoints"`
+	SupportsHitConditionalBreakpoints bool `json:"supportsHitConditionalBreakpoints"`
+	SupportsLogPoints                 bool `json:"supportsLogPoints"`
+}
+
+type LaunchArguments struct {
+	Program     string `json:"program"`
+	StopOnEntry bool   `json:"stopOnEntry"`
+	NoDebug     bool   `json:"noDebug"`
+	// Args are the JSON-CDC encoded arguments of a transaction
+	Args []json.RawMessage `json:"args,omitempty"`
+	// Signers are the addresses of the accounts which sign a transaction
+	Signers []string `json:"signers,omitempty"`
+}
+
+type Source struct {
+	Name string `json:"name,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+type SourceBreakpoint struct {
+	Line         int    `json:"line"`
+	Condition    string `json:"condition,omitempty"`
+	HitCondition string `json:"hitCondition,omitempty"`
+	LogMessage   string `json:"logMessage,omitempty"`
+}
+
+type SetBreakpointsArguments struct {
+	Source      Source             `json:"source"`
+	Breakpoints []SourceBreakpoint `json:"breakpoints"`
+}
+
+type Breakpoint struct {
+	Verified bool    `json:"verified"`
+	Message  string  `json:"message,omitempty"`
+	Line     int     `json:"line,omitempty"`
+	Source   *Source `json:"source,omitempty"`
+}
+
+type SetBreakpointsResponseBody struct {
+	Breakpoints []Breakpoint `json:"breakpoints"`
+}
+
+type Thread struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type ThreadsResponseBody struct {
+	Threads []Thread `json:"threads"`
+}
+
+type StackTraceArguments struct {
+	ThreadID   int `json:"threadId"`
+	StartFrame int `json:"startFrame"`
+	Levels     int `json:"levels"`
+}
+
+type StackFrame struct {
+	ID     int     `json:"id"`
+	Name   string  `json:"name"`
+	Source *Source `json:"source,omitempty"`
+	Line   int     `json:"line"`
+	Column int     `json:"column"`
+}
+
+type StackTraceResponseBody struct {
+	StackFrames []StackFrame `json:"stackFrames"`
+	TotalFrames int          `json:"totalFrames"`
+}
+
+type ScopesArguments struct {
+	FrameID int `json:"frameId"`
+}
+
+type Scope struct {
+	Name               string `json:"name"`
+	VariablesReference int    `json:"variablesReference"`
+	Expensive          bool   `json:"expensive"`
+}
+
+type ScopesResponseBody struct {
+	Scopes []Scope `json:"scopes"`
+}
+
+type VariablesArguments struct {
+	VariablesReference int `json:"variablesReference"`
+}
+
+type Variable struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	Type               string `json:"type,omitempty"`
+	VariablesReference int    `json:"variablesReference"`
+}
+
+type VariablesResponseBody struct {
+	Variables []Variable `json:"variables"`
+}
+
+type ContinueResponseBody struct {
+	AllThreadsContinued bool `json:"allThreadsContinued"`
+}
+
+type StoppedEventBody struct {
+	Reason            string `json:"reason"`
+	Description       string `json:"description,omitempty"`
+	Text              string `json:"text,omitempty"`
+	ThreadID          int    `json:"threadId"`
+	AllThreadsStopped bool   `json:"allThreadsStopped"`
+}
+
+type OutputEventBody struct {
+	Category string `json:"category"`
+	Output   string `json:"output"`
+}
+
+type ExitedEventBody struct {
+	ExitCode int `json:"exitCode"`
+}
+
+// ReadMessage reads the content of the next base protocol message,
+// i.e. the JSON payload following the message's headers.
+func ReadMessage(reader *bufio.Reader) ([]byte, error) {
+	headers, err := textproto.NewReader(reader).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	contentLength := headers.Get(contentLengthHeader)
+	if contentLength == "" {
+		return nil, fmt.Errorf("missing %s header", contentLengthHeader)
+	}
+
+	length, err := strconv.Atoi(strings.TrimSpace(contentLength))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid %s header: %s", contentLengthHeader, contentLength)
+	}
+
+	content := make([]byte, length)
+	_, err = io.ReadFull(reader, content)
+	if err != nil {
+		return nil, err
+	}
+
+	return content, nil
+}
+
+// WriteMessage encodes the given message as JSON
+// and writes it as a base protocol message.
+func WriteMessage(writer io.Writer, message any) error {
+	content, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(writer, "%s: %d\r\n\r\n", contentLengthHeader, len(content))
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(content)
+	return err
+}
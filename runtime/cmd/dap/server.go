@@ -0,0 +1,819 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/onflow/cadence/runtime/cmd"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/emulator"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/parser"
+	"github.com/onflow/cadence/runtime/pretty"
+	"github.com/onflow/cadence/runtime/stdlib"
+)
+
+// The interpreter executes a program on a single thread
+const threadID = 1
+const threadName = "main"
+
+const (
+	stopReasonEntry      = "entry"
+	stopReasonStep       = "step"
+	stopReasonPause      = "pause"
+	stopReasonBreakpoint = "breakpoint"
+)
+
+const (
+	outputCategoryStdout = "stdout"
+	outputCategoryStderr = "stderr"
+	// outputCategoryConsole is the category of logpoint messages
+	outputCategoryConsole = "console"
+)
+
+const localsScopeName = "Locals"
+
+var stepModes = map[string]interpreter.StepMode{
+	"next":    interpreter.StepModeOver,
+	"stepIn":  interpreter.StepModeInto,
+	"stepOut": interpreter.StepModeOut,
+}
+
+// Server is a debug adapter which executes a program with the interpreter,
+// and lets a client debug the program using the Debug Adapter Protocol.
+//
+// Stops of the interpreter's debugger are reported as stopped events,
+// the interpreter's call stack as the stack trace,
+// and the activations of the call stack's frames as their variables.
+type Server struct {
+	reader    *bufio.Reader
+	writer    io.Writer
+	writeLock sync.Mutex
+	seq       int
+	debugger  *interpreter.Debugger
+	launch    *LaunchArguments
+	// location is the location of the launched program
+	location common.Location
+	started  bool
+	exited   chan struct{}
+
+	// lock guards the fields below,
+	// which are accessed by the request loop and the stop watcher
+	lock sync.Mutex
+	// stop is the current stop, or nil if the program is running
+	stop *interpreter.Stop
+	// stopReason is the reason for the next stop, if it was requested
+	stopReason string
+	handles    variableHandles
+}
+
+func NewServer(reader io.Reader, writer io.Writer) *Server {
+	server := &Server{
+		reader:   bufio.NewReader(reader),
+		writer:   writer,
+		debugger: interpreter.NewDebugger(),
+	}
+
+	server.debugger.OnLogpoint = func(_ *interpreter.Breakpoint, message string) {
+		server.sendOutput(outputCategoryConsole, message)
+	}
+
+	return server
+}
+
+// Run handles requests until the client disconnects
+// or the connection is closed.
+func (s *Server) Run() error {
+	for {
+		content, err := ReadMessage(s.reader)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		var request Request
+		err = json.Unmarshal(content, &request)
+		if err != nil {
+			return err
+		}
+
+		if request.Type != messageTypeRequest {
+			continue
+		}
+
+		if s.handleRequest(&request) {
+			return nil
+		}
+	}
+}
+
+// handleRequest handles the given request,
+// and returns true if the session has ended
+func (s *Server) handleRequest(request *Request) (done bool) {
+	var body any
+	var err error
+
+	switch request.Command {
+	case "initialize":
+		body = Capabilities{
+			SupportsConfigurationDoneRequest:  true,
+			SupportsTerminateRequest:          true,
+			SupportsConditionalBreakpoints:    true,
+			SupportsHitConditionalBreakpoints: true,
+			SupportsLogPoints:                 true,
+		}
+
+	case "launch":
+		err = s.handleLaunch(request)
+		if err == nil {
+			s.respond(request, nil)
+			s.sendEvent("initialized", nil)
+			return false
+		}
+
+	case "setBreakpoints":
+		body, err = s.handleSetBreakpoints(request)
+
+	case "setExceptionBreakpoints":
+		// exceptions are not supported, but clients send this request unconditionally
+
+	case "configurationDone":
+		err = s.handleConfigurationDone()
+		if err == nil {
+			// Respond before starting the program,
+			// so that the response is sent before any stopped event
+			s.respond(request, nil)
+			s.start()
+			return false
+		}
+
+	case "threads":
+		body = ThreadsResponseBody{
+			Threads: []Thread{
+				{
+					ID:   threadID,
+					Name: threadName,
+				},
+			},
+		}
+
+	case "stackTrace":
+		body, err = s.handleStackTrace(request)
+
+	case "scopes":
+		body, err = s.handleScopes(request)
+
+	case "variables":
+		body, err = s.handleVariables(request)
+
+	case "continue":
+		if s.resume(interpreter.StepModeNone) {
+			s.respond(request, ContinueResponseBody{AllThreadsContinued: true})
+			s.debugger.Continue()
+			return false
+		}
+		err = errors.New("program is not stopped")
+
+	case "next", "stepIn", "stepOut":
+		if s.resume(stepModes[request.Command]) {
+			s.respond(request, nil)
+			s.debugger.Continue()
+			return false
+		}
+		err = errors.New("program is not stopped")
+
+	case "pause":
+		s.handlePause()
+
+	case "disconnect", "terminate":
+		s.respond(request, nil)
+		s.detach()
+		return true
+
+	default:
+		err = fmt.Errorf("unsupported request: %s", request.Command)
+	}
+
+	if err != nil {
+		s.respondError(request, err)
+	} else {
+		s.respond(request, body)
+	}
+
+	return false
+}
+
+func (s *Server) handleLaunch(request *Request) error {
+	var arguments LaunchArguments
+	err := json.Unmarshal(request.Arguments, &arguments)
+	if err != nil {
+		return err
+	}
+
+	if arguments.Program == "" {
+		return errors.New("missing program")
+	}
+
+	arguments.Program, err = filepath.Abs(arguments.Program)
+	if err != nil {
+		return err
+	}
+
+	s.launch = &arguments
+	s.location = programLocation(arguments.Program)
+	return nil
+}
+
+// transactionLocation is the location of a launched transaction
+var transactionLocation = common.TransactionLocation{}
+
+// programLocation returns the location of the program in the file with the given path.
+//
+// Transactions are executed by the runtime, which requires them to have a transaction location.
+// All other programs have the location of the file
+func programLocation(path string) common.Location {
+	location := common.NewStringLocation(nil, path)
+
+	code, err := os.ReadFile(path)
+	if err != nil {
+		return location
+	}
+
+	// Errors are reported when the program is executed
+	program, err := parser.ParseProgram(nil, code, parser.Config{})
+	if err != nil || program.SoleTransactionDeclaration() == nil {
+		return location
+	}
+
+	return transactionLocation
+}
+
+func (s *Server) handleSetBreakpoints(request *Request) (any, error) {
+	var arguments SetBreakpointsArguments
+	err := json.Unmarshal(request.Arguments, &arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := s.sourceLocation(arguments.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	s.debugger.ClearBreakpointsForLocation(location)
+
+	breakpoints := make([]Breakpoint, 0, len(arguments.Breakpoints))
+	for _, sourceBreakpoint := range arguments.Breakpoints {
+		breakpoint := Breakpoint{
+			Line: sourceBreakpoint.Line,
+		}
+
+		debuggerBreakpoint, err := newBreakpoint(location, sourceBreakpoint)
+		if err != nil {
+			breakpoint.Message = err.Error()
+		} else {
+			s.debugger.SetBreakpoint(debuggerBreakpoint)
+			breakpoint.Verified = true
+		}
+
+		breakpoints = append(breakpoints, breakpoint)
+	}
+
+	return SetBreakpointsResponseBody{
+		Breakpoints: breakpoints,
+	}, nil
+}
+
+func (s *Server) handleConfigurationDone() error {
+	if s.launch == nil {
+		return errors.New("no program was launched")
+	}
+
+	if s.started {
+		return errors.New("program was already started")
+	}
+
+	return nil
+}
+
+// start starts executing the launched program
+func (s *Server) start() {
+	s.started = true
+
+	if s.launch.NoDebug {
+		s.debugger.ClearBreakpoints()
+	} else if s.launch.StopOnEntry {
+		s.requestStop(stopReasonEntry)
+	}
+
+	s.exited = make(chan struct{})
+
+	go s.watchStops()
+	go s.execute()
+}
+
+func (s *Server) handleStackTrace(request *Request) (any, error) {
+	var arguments StackTraceArguments
+	err := json.Unmarshal(request.Arguments, &arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.stop == nil {
+		return nil, errors.New("program is not stopped")
+	}
+
+	stopFrames := s.debugger.Frames(*s.stop)
+
+	stackFrames := make([]StackFrame, 0, len(stopFrames))
+	for id, frame := range stopFrames {
+		if id < arguments.StartFrame {
+			continue
+		}
+		if arguments.Levels > 0 && len(stackFrames) >= arguments.Levels {
+			break
+		}
+		stackFrames = append(stackFrames, s.stackFrame(id, frame))
+	}
+
+	return StackTraceResponseBody{
+		StackFrames: stackFrames,
+		TotalFrames: len(stopFrames),
+	}, nil
+}
+
+func (s *Server) handleScopes(request *Request) (any, error) {
+	var arguments ScopesArguments
+	err := json.Unmarshal(request.Arguments, &arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.stop == nil {
+		return nil, errors.New("program is not stopped")
+	}
+
+	stopFrames := s.debugger.Frames(*s.stop)
+	if arguments.FrameID < 0 || arguments.FrameID >= len(stopFrames) {
+		return nil, fmt.Errorf("invalid frame: %d", arguments.FrameID)
+	}
+
+	scopes := []Scope{}
+
+	frame := stopFrames[arguments.FrameID]
+	if frame.Activation != nil {
+		scopes = append(scopes, Scope{
+			Name: localsScopeName,
+			VariablesReference: s.handles.add(frameVariables{
+				interpreter: frame.Interpreter,
+				activation:  frame.Activation,
+			}),
+		})
+	}
+
+	return ScopesResponseBody{
+		Scopes: scopes,
+	}, nil
+}
+
+func (s *Server) handleVariables(request *Request) (any, error) {
+	var arguments VariablesArguments
+	err := json.Unmarshal(request.Arguments, &arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.stop == nil {
+		return nil, errors.New("program is not stopped")
+	}
+
+	container, ok := s.handles.get(arguments.VariablesReference)
+	if !ok {
+		return nil, fmt.Errorf("invalid variables reference: %d", arguments.VariablesReference)
+	}
+
+	variables := s.handles.variables(container)
+	if variables == nil {
+		variables = []Variable{}
+	}
+
+	return VariablesResponseBody{
+		Variables: variables,
+	}, nil
+}
+
+func (s *Server) handlePause() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.started || s.stop != nil {
+		return
+	}
+
+	s.stopReason = stopReasonPause
+	s.debugger.RequestPause()
+}
+
+// resume prepares resuming the stopped program,
+// and returns false if the program is not stopped.
+//
+// If a step mode is given, the program stops again after the step.
+// The caller must continue the debugger after responding to the request,
+// so that the response is sent before any following stopped event.
+func (s *Server) resume(stepMode interpreter.StepMode) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.stop == nil {
+		return false
+	}
+
+	s.stop = nil
+	s.handles.reset()
+
+	if stepMode != interpreter.StepModeNone {
+		s.stopReason = stopReasonStep
+		s.debugger.RequestStep(stepMode)
+	}
+
+	return true
+}
+
+func (s *Server) requestStop(reason string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.stopReason = reason
+	s.debugger.RequestPause()
+}
+
+// detach lets the program run to completion without further stops
+func (s *Server) detach() {
+	s.debugger.ClearBreakpoints()
+
+	if s.resume(interpreter.StepModeNone) {
+		s.debugger.Continue()
+	}
+}
+
+// watchStops reports the stops of the debugger as stopped events,
+// until the program exits
+func (s *Server) watchStops() {
+	for {
+		select {
+		case stop := <-s.debugger.Stops():
+			s.lock.Lock()
+			s.stop = &stop
+			s.handles.reset()
+			reason := s.stopReason
+			s.stopReason = ""
+			s.lock.Unlock()
+
+			body := StoppedEventBody{
+				Reason:            reason,
+				ThreadID:          threadID,
+				AllThreadsStopped: true,
+			}
+
+			// A breakpoint takes precedence over a requested stop
+			if stop.Breakpoint != nil || reason == "" {
+				body.Reason = stopReasonBreakpoint
+			}
+
+			if stop.ConditionError != nil {
+				body.Description = "invalid breakpoint condition"
+				body.Text = stop.ConditionError.Error()
+			}
+
+			s.sendEvent("stopped", body)
+
+		case <-s.exited:
+			return
+		}
+	}
+}
+
+// execute executes the launched program,
+// and reports the program's output and its termination
+func (s *Server) execute() {
+	defer close(s.exited)
+
+	exitCode := 0
+
+	err := s.executeProgram(s.launch.Program)
+	if err != nil {
+		exitCode = 1
+		s.sendOutput(outputCategoryStderr, err.Error())
+	}
+
+	s.sendEvent("exited", ExitedEventBody{
+		ExitCode: exitCode,
+	})
+	s.sendEvent("terminated", nil)
+}
+
+// executeProgram executes the program in the given file.
+//
+// Transactions are executed in an emulated chain,
+// with the launch arguments and signed by the launch signers.
+// Other programs are checked and interpreted.
+// If such a program declares a global function `main`, it is invoked.
+//
+// Errors are returned pretty-printed.
+func (s *Server) executeProgram(path string) error {
+
+	codes := map[common.Location][]byte{}
+
+	location := s.location
+
+	formatError := func(err error) error {
+		var builder strings.Builder
+		printErr := pretty.NewErrorPrettyPrinter(&builder, false).
+			PrettyPrintError(err, location, codes)
+		if printErr != nil {
+			return err
+		}
+		return errors.New(builder.String())
+	}
+
+	if location == transactionLocation {
+		code, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		codes[location] = code
+
+		err = s.executeTransaction(code)
+		if err != nil {
+			return formatError(err)
+		}
+		return nil
+	}
+
+	if len(s.launch.Args) > 0 || len(s.launch.Signers) > 0 {
+		return errors.New("arguments and signers are only supported for transactions")
+	}
+
+	inter, _, err := cmd.LoadInterpreter(
+		path,
+		cmd.NewInterpreterConfig(outputLogger{server: s}, s.debugger),
+		codes,
+	)
+	if err != nil {
+		return formatError(err)
+	}
+
+	if !inter.Globals.Contains("main") {
+		return nil
+	}
+
+	_, err = inter.Invoke("main")
+	if err != nil {
+		return formatError(err)
+	}
+
+	return nil
+}
+
+// executeTransaction executes the given transaction in a new emulated chain,
+// with the launch arguments and signed by the launch signers
+func (s *Server) executeTransaction(code []byte) error {
+	chain := emulator.NewWithDebugger(s.debugger)
+	chain.OnLog = func(message string) {
+		s.sendOutput(outputCategoryStdout, message)
+	}
+
+	signers, err := createSigners(chain, s.launch.Signers)
+	if err != nil {
+		return err
+	}
+
+	arguments := make([][]byte, 0, len(s.launch.Args))
+	for _, argument := range s.launch.Args {
+		arguments = append(arguments, argument)
+	}
+
+	return chain.ExecuteTransactionAt(transactionLocation, code, arguments, signers...)
+}
+
+// maxSignerAccounts is the maximum number of accounts which are created for signers
+const maxSignerAccounts = 100
+
+// createSigners returns the addresses of the given signers.
+// The emulator creates accounts with consecutive addresses, starting at 0x1,
+// so accounts are created until all signers exist
+func createSigners(chain *emulator.Emulator, signers []string) ([]common.Address, error) {
+	addresses := make([]common.Address, 0, len(signers))
+
+	for _, signer := range signers {
+		address, err := common.HexToAddress(signer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signer address: %s", signer)
+		}
+
+		index := binary.BigEndian.Uint64(address[:])
+		if index == 0 || index > maxSignerAccounts {
+			return nil, fmt.Errorf(
+				"invalid signer address: %s, must be between 0x1 and %#x",
+				signer,
+				maxSignerAccounts,
+			)
+		}
+
+		for uint64(len(chain.Accounts())) < index {
+			_, err := chain.CreateAccount()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		addresses = append(addresses, address)
+	}
+
+	return addresses, nil
+}
+
+func (s *Server) respond(request *Request, body any) {
+	s.send(&Response{
+		ProtocolMessage: ProtocolMessage{
+			Type: messageTypeResponse,
+		},
+		RequestSeq: request.Seq,
+		Success:    true,
+		Command:    request.Command,
+		Body:       body,
+	})
+}
+
+func (s *Server) respondError(request *Request, err error) {
+	s.send(&Response{
+		ProtocolMessage: ProtocolMessage{
+			Type: messageTypeResponse,
+		},
+		RequestSeq: request.Seq,
+		Success:    false,
+		Command:    request.Command,
+		Message:    err.Error(),
+	})
+}
+
+func (s *Server) sendEvent(event string, body any) {
+	s.send(&Event{
+		ProtocolMessage: ProtocolMessage{
+			Type: messageTypeEvent,
+		},
+		Event: event,
+		Body:  body,
+	})
+}
+
+func (s *Server) sendOutput(category string, output string) {
+	if !strings.HasSuffix(output, "\n") {
+		output += "\n"
+	}
+
+	s.sendEvent("output", OutputEventBody{
+		Category: category,
+		Output:   output,
+	})
+}
+
+// send writes the given response or event.
+// Messages are sent from the request loop and the program's goroutines,
+// so sequence numbers are assigned while holding the write lock
+func (s *Server) send(message any) {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+
+	s.seq++
+
+	switch message := message.(type) {
+	case *Response:
+		message.Seq = s.seq
+	case *Event:
+		message.Seq = s.seq
+	}
+
+	// The client is gone if the message cannot be written,
+	// which ends the request loop
+	_ = WriteMessage(s.writer, message)
+}
+
+// outputLogger reports the values logged by the program as output events
+type outputLogger struct {
+	server *Server
+}
+
+var _ stdlib.Logger = outputLogger{}
+
+func (l outputLogger) ProgramLog(message string) error {
+	l.server.sendOutput(outputCategoryStdout, message)
+	return nil
+}
+
+func newBreakpoint(location common.Location, sourceBreakpoint SourceBreakpoint) (*interpreter.Breakpoint, error) {
+	if sourceBreakpoint.Line < 1 {
+		return nil, errors.New("invalid line")
+	}
+
+	breakpoint := &interpreter.Breakpoint{
+		Location: location,
+		Line:     uint(sourceBreakpoint.Line),
+	}
+
+	var err error
+
+	if sourceBreakpoint.Condition != "" {
+		breakpoint.Condition, err = cmd.ParseBreakpointCondition(sourceBreakpoint.Condition)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	breakpoint.HitCount, err = parseHitCondition(sourceBreakpoint.HitCondition)
+	if err != nil {
+		return nil, err
+	}
+
+	if sourceBreakpoint.LogMessage != "" {
+		breakpoint.LogMessage, err = cmd.ParseLogMessage(sourceBreakpoint.LogMessage)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return breakpoint, nil
+}
+
+// parseHitCondition parses the hit condition of a breakpoint,
+// the number of hits before the breakpoint takes effect
+func parseHitCondition(hitCondition string) (uint, error) {
+	hitCondition = strings.TrimSpace(hitCondition)
+	if hitCondition == "" {
+		return 0, nil
+	}
+
+	hitCount, err := strconv.ParseUint(hitCondition, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hit condition, expected number of hits: %s", hitCondition)
+	}
+
+	return uint(hitCount), nil
+}
+
+// sourceLocation returns the location of the program with the given source,
+// which is the location of the launched program if it is the launched program's source
+func (s *Server) sourceLocation(source Source) (common.Location, error) {
+	if source.Path == "" {
+		return nil, errors.New("missing source path")
+	}
+
+	path, err := filepath.Abs(source.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.launch != nil && path == s.launch.Program {
+		return s.location, nil
+	}
+
+	return common.NewStringLocation(nil, path), nil
+}
@@ -0,0 +1,778 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTimeout = 10 * time.Second
+
+type testMessage struct {
+	Type       string          `json:"type"`
+	Seq        int             `json:"seq"`
+	RequestSeq int             `json:"request_seq"`
+	Command    string          `json:"command"`
+	Success    bool            `json:"success"`
+	Message    string          `json:"message"`
+	Event      string          `json:"event"`
+	Body       json.RawMessage `json:"body"`
+}
+
+type testClient struct {
+	t        *testing.T
+	writer   io.Writer
+	messages chan testMessage
+	// events are the events received while waiting for responses
+	events []testMessage
+	seq    int
+	errs   chan error
+}
+
+func newTestClient(t *testing.T) *testClient {
+	serverReader, clientWriter := io.Pipe()
+	clientReader, serverWriter := io.Pipe()
+
+	client := &testClient{
+		t:        t,
+		writer:   clientWriter,
+		messages: make(chan testMessage, 100),
+		errs:     make(chan error, 1),
+	}
+
+	go func() {
+		client.errs <- NewServer(serverReader, serverWriter).Run()
+	}()
+
+	go func() {
+		reader := bufio.NewReader(clientReader)
+		for {
+			content, err := ReadMessage(reader)
+			if err != nil {
+				close(client.messages)
+				return
+			}
+
+			var message testMessage
+			err = json.Unmarshal(content, &message)
+			if err != nil {
+				panic(err)
+			}
+			client.messages <- message
+		}
+	}()
+
+	t.Cleanup(func() {
+		_ = clientWriter.Close()
+		_ = serverWriter.Close()
+	})
+
+	return client
+}
+
+func (c *testClient) next() testMessage {
+	select {
+	case message, ok := <-c.messages:
+		require.True(c.t, ok, "connection closed")
+		return message
+	case <-time.After(testTimeout):
+		require.FailNow(c.t, "timeout waiting for message")
+		return testMessage{}
+	}
+}
+
+// request sends a request and returns its response
+func (c *testClient) request(command string, arguments any) testMessage {
+	c.seq++
+
+	request := map[string]any{
+		"seq":     c.seq,
+		"type":    "request",
+		"command": command,
+	}
+	if arguments != nil {
+		request["arguments"] = arguments
+	}
+
+	err := WriteMessage(c.writer, request)
+	require.NoError(c.t, err)
+
+	for {
+		message := c.next()
+		if message.Type == messageTypeEvent {
+			c.events = append(c.events, message)
+			continue
+		}
+
+		require.Equal(c.t, messageTypeResponse, message.Type)
+		require.Equal(c.t, c.seq, message.RequestSeq)
+		require.Equal(c.t, command, message.Command)
+		return message
+	}
+}
+
+func (c *testClient) mustRequest(command string, arguments any, body any) {
+	response := c.request(command, arguments)
+	require.True(c.t, response.Success, response.Message)
+
+	if body != nil {
+		err := json.Unmarshal(response.Body, body)
+		require.NoError(c.t, err)
+	}
+}
+
+// event returns the next event with the given name,
+// skipping other events
+func (c *testClient) event(name string) testMessage {
+	for len(c.events) > 0 {
+		message := c.events[0]
+		c.events = c.events[1:]
+		if message.Event == name {
+			return message
+		}
+	}
+
+	for {
+		message := c.next()
+		if message.Type == messageTypeEvent && message.Event == name {
+			return message
+		}
+	}
+}
+
+func (c *testClient) stopped() StoppedEventBody {
+	var body StoppedEventBody
+	err := json.Unmarshal(c.event("stopped").Body, &body)
+	require.NoError(c.t, err)
+	return body
+}
+
+func (c *testClient) launch(path string, stopOnEntry bool, lines ...int) {
+	breakpoints := make([]SourceBreakpoint, 0, len(lines))
+	for _, line := range lines {
+		breakpoints = append(breakpoints, SourceBreakpoint{Line: line})
+	}
+
+	c.launchWithBreakpoints(path, stopOnEntry, breakpoints)
+}
+
+func (c *testClient) launchWithBreakpoints(path string, stopOnEntry bool, breakpoints []SourceBreakpoint) {
+	c.launchWithArguments(
+		LaunchArguments{
+			Program:     path,
+			StopOnEntry: stopOnEntry,
+		},
+		breakpoints,
+	)
+}
+
+func (c *testClient) launchWithArguments(arguments LaunchArguments, breakpoints []SourceBreakpoint) {
+	var capabilities Capabilities
+	c.mustRequest("initialize", map[string]any{"adapterID": "cadence"}, &capabilities)
+	require.True(c.t, capabilities.SupportsConfigurationDoneRequest)
+
+	c.mustRequest("launch", arguments, nil)
+	c.event("initialized")
+
+	var setBreakpoints SetBreakpointsResponseBody
+	c.mustRequest(
+		"setBreakpoints",
+		SetBreakpointsArguments{
+			Source:      Source{Path: arguments.Program},
+			Breakpoints: breakpoints,
+		},
+		&setBreakpoints,
+	)
+	require.Len(c.t, setBreakpoints.Breakpoints, len(breakpoints))
+	for _, breakpoint := range setBreakpoints.Breakpoints {
+		require.True(c.t, breakpoint.Verified)
+	}
+
+	c.mustRequest("configurationDone", nil, nil)
+}
+
+func (c *testClient) variables(reference int) []Variable {
+	var body VariablesResponseBody
+	c.mustRequest("variables", VariablesArguments{VariablesReference: reference}, &body)
+	return body.Variables
+}
+
+func (c *testClient) locals() []Variable {
+	return c.frameLocals(0)
+}
+
+func (c *testClient) frameLocals(frameID int) []Variable {
+	var scopes ScopesResponseBody
+	c.mustRequest("scopes", ScopesArguments{FrameID: frameID}, &scopes)
+	require.Len(c.t, scopes.Scopes, 1)
+	require.Equal(c.t, localsScopeName, scopes.Scopes[0].Name)
+
+	return c.variables(scopes.Scopes[0].VariablesReference)
+}
+
+func writeProgram(t *testing.T, code string) string {
+	path := filepath.Join(t.TempDir(), "test.cdc")
+	err := os.WriteFile(path, []byte(code), 0600)
+	require.NoError(t, err)
+	return path
+}
+
+const testProgram = `
+pub fun add(_ a: Int, _ b: Int): Int {
+    let sum = a + b
+    return sum
+}
+
+pub fun main() {
+    let numbers = [1, 2]
+    let x = add(numbers[0], numbers[1])
+    log(x)
+}
+`
+
+func TestMessages(t *testing.T) {
+
+	t.Parallel()
+
+	var buffer bytes.Buffer
+
+	err := WriteMessage(&buffer, map[string]any{"seq": 1})
+	require.NoError(t, err)
+	err = WriteMessage(&buffer, map[string]any{"seq": 2})
+	require.NoError(t, err)
+
+	require.Equal(t,
+		"Content-Length: 9\r\n\r\n{\"seq\":1}Content-Length: 9\r\n\r\n{\"seq\":2}",
+		buffer.String(),
+	)
+
+	reader := bufio.NewReader(&buffer)
+
+	content, err := ReadMessage(reader)
+	require.NoError(t, err)
+	assert.Equal(t, `{"seq":1}`, string(content))
+
+	content, err = ReadMessage(reader)
+	require.NoError(t, err)
+	assert.Equal(t, `{"seq":2}`, string(content))
+
+	_, err = ReadMessage(reader)
+	require.ErrorIs(t, err, io.EOF)
+
+	_, err = ReadMessage(bufio.NewReader(bytes.NewBufferString("Foo: 1\r\n\r\n")))
+	require.EqualError(t, err, "missing Content-Length header")
+}
+
+func TestBreakpoint(t *testing.T) {
+
+	t.Parallel()
+
+	path := writeProgram(t, testProgram)
+
+	client := newTestClient(t)
+	client.launch(path, false, 4)
+
+	stopped := client.stopped()
+	assert.Equal(t, stopReasonBreakpoint, stopped.Reason)
+	assert.Equal(t, threadID, stopped.ThreadID)
+
+	var threads ThreadsResponseBody
+	client.mustRequest("threads", nil, &threads)
+	require.Equal(t, []Thread{{ID: threadID, Name: threadName}}, threads.Threads)
+
+	var stackTrace StackTraceResponseBody
+	client.mustRequest("stackTrace", StackTraceArguments{ThreadID: threadID}, &stackTrace)
+
+	require.Len(t, stackTrace.StackFrames, 2)
+	assert.Equal(t, 2, stackTrace.TotalFrames)
+
+	source := &Source{Name: "test.cdc", Path: path}
+
+	assert.Equal(t,
+		StackFrame{
+			ID:     0,
+			Name:   "add",
+			Source: source,
+			Line:   4,
+			Column: 5,
+		},
+		stackTrace.StackFrames[0],
+	)
+
+	callerFrame := stackTrace.StackFrames[1]
+	assert.Equal(t, "main", callerFrame.Name)
+	assert.Equal(t, source, callerFrame.Source)
+	assert.Equal(t, 9, callerFrame.Line)
+
+	assert.Equal(t,
+		[]Variable{
+			{Name: "a", Value: "1", Type: "Int"},
+			{Name: "b", Value: "2", Type: "Int"},
+			{Name: "sum", Value: "3", Type: "Int"},
+		},
+		client.locals(),
+	)
+
+	// The variables of the caller are available
+
+	callerLocals := client.frameLocals(callerFrame.ID)
+	require.Len(t, callerLocals, 1)
+
+	numbers := callerLocals[0]
+	assert.Equal(t, "numbers", numbers.Name)
+	assert.Equal(t, "[Int]", numbers.Type)
+	require.NotZero(t, numbers.VariablesReference)
+
+	assert.Equal(t,
+		[]Variable{
+			{Name: "[0]", Value: "1", Type: "Int"},
+			{Name: "[1]", Value: "2", Type: "Int"},
+		},
+		client.variables(numbers.VariablesReference),
+	)
+
+	response := client.request("scopes", ScopesArguments{FrameID: 2})
+	require.False(t, response.Success)
+
+	client.mustRequest("continue", map[string]any{"threadId": threadID}, nil)
+
+	output := client.event("output")
+	var outputBody OutputEventBody
+	err := json.Unmarshal(output.Body, &outputBody)
+	require.NoError(t, err)
+	assert.Equal(t, OutputEventBody{Category: outputCategoryStdout, Output: "3\n"}, outputBody)
+
+	var exited ExitedEventBody
+	err = json.Unmarshal(client.event("exited").Body, &exited)
+	require.NoError(t, err)
+	assert.Equal(t, 0, exited.ExitCode)
+
+	client.event("terminated")
+
+	response = client.request("disconnect", nil)
+	require.True(t, response.Success)
+	require.NoError(t, <-client.errs)
+}
+
+func TestStepping(t *testing.T) {
+
+	t.Parallel()
+
+	path := writeProgram(t, testProgram)
+
+	client := newTestClient(t)
+	client.launch(path, true)
+
+	stopped := client.stopped()
+	assert.Equal(t, stopReasonEntry, stopped.Reason)
+
+	var stackTrace StackTraceResponseBody
+	client.mustRequest("stackTrace", StackTraceArguments{ThreadID: threadID}, &stackTrace)
+	require.NotEmpty(t, stackTrace.StackFrames)
+	assert.Equal(t, "main", stackTrace.StackFrames[0].Name)
+	assert.Equal(t, 8, stackTrace.StackFrames[0].Line)
+
+	client.mustRequest("next", map[string]any{"threadId": threadID}, nil)
+
+	stopped = client.stopped()
+	assert.Equal(t, stopReasonStep, stopped.Reason)
+
+	client.mustRequest("stackTrace", StackTraceArguments{ThreadID: threadID}, &stackTrace)
+	assert.Equal(t, 9, stackTrace.StackFrames[0].Line)
+
+	locals := client.locals()
+	require.Len(t, locals, 1)
+
+	numbers := locals[0]
+	assert.Equal(t, "numbers", numbers.Name)
+	assert.Equal(t, "[1, 2]", numbers.Value)
+	assert.Equal(t, "[Int]", numbers.Type)
+	require.NotZero(t, numbers.VariablesReference)
+
+	assert.Equal(t,
+		[]Variable{
+			{Name: "[0]", Value: "1", Type: "Int"},
+			{Name: "[1]", Value: "2", Type: "Int"},
+		},
+		client.variables(numbers.VariablesReference),
+	)
+
+	// Variable references are invalidated when the program continues
+
+	client.mustRequest("continue", map[string]any{"threadId": threadID}, nil)
+	client.event("terminated")
+
+	response := client.request("variables", VariablesArguments{VariablesReference: numbers.VariablesReference})
+	assert.False(t, response.Success)
+
+	response = client.request("terminate", nil)
+	require.True(t, response.Success)
+	require.NoError(t, <-client.errs)
+}
+
+func TestCompositeVariables(t *testing.T) {
+
+	t.Parallel()
+
+	path := writeProgram(t, `
+pub struct Point {
+    pub let x: Int
+    pub let y: Int
+
+    init(x: Int, y: Int) {
+        self.x = x
+        self.y = y
+    }
+
+    pub fun sum(): Int {
+        return self.x + self.y
+    }
+}
+
+pub fun main() {
+    let point: Point? = Point(x: 1, y: 2)
+    let names = {"one": 1}
+    log(point!.sum())
+}
+`)
+
+	client := newTestClient(t)
+	client.launch(path, false, 12, 19)
+
+	stopped := client.stopped()
+	assert.Equal(t, stopReasonBreakpoint, stopped.Reason)
+
+	locals := client.locals()
+	require.Len(t, locals, 2)
+
+	assert.Equal(t, "names", locals[0].Name)
+	assert.Equal(t,
+		[]Variable{
+			{Name: `"one"`, Value: "1", Type: "Int"},
+		},
+		client.variables(locals[0].VariablesReference),
+	)
+
+	assert.Equal(t, "point", locals[1].Name)
+	assert.Equal(t, "Point?", locals[1].Type)
+	assert.Equal(t,
+		[]Variable{
+			{Name: "x", Value: "1", Type: "Int"},
+			{Name: "y", Value: "2", Type: "Int"},
+		},
+		client.variables(locals[1].VariablesReference),
+	)
+
+	client.mustRequest("continue", map[string]any{"threadId": threadID}, nil)
+
+	stopped = client.stopped()
+	assert.Equal(t, stopReasonBreakpoint, stopped.Reason)
+
+	var stackTrace StackTraceResponseBody
+	client.mustRequest("stackTrace", StackTraceArguments{ThreadID: threadID}, &stackTrace)
+	require.Len(t, stackTrace.StackFrames, 2)
+	assert.Equal(t, "Point.sum", stackTrace.StackFrames[0].Name)
+	assert.Equal(t, "main", stackTrace.StackFrames[1].Name)
+
+	locals = client.locals()
+	require.Len(t, locals, 1)
+	assert.Equal(t, "self", locals[0].Name)
+	assert.Equal(t, "Point", locals[0].Type)
+
+	client.mustRequest("disconnect", nil, nil)
+	require.NoError(t, <-client.errs)
+}
+
+func TestCheckingError(t *testing.T) {
+
+	t.Parallel()
+
+	path := writeProgram(t, `
+pub fun main() {
+    let x: Int = "one"
+}
+`)
+
+	client := newTestClient(t)
+	client.launch(path, false)
+
+	var output OutputEventBody
+	err := json.Unmarshal(client.event("output").Body, &output)
+	require.NoError(t, err)
+	assert.Equal(t, outputCategoryStderr, output.Category)
+	assert.Contains(t, output.Output, "mismatched types")
+
+	var exited ExitedEventBody
+	err = json.Unmarshal(client.event("exited").Body, &exited)
+	require.NoError(t, err)
+	assert.Equal(t, 1, exited.ExitCode)
+
+	client.event("terminated")
+}
+
+func TestTransaction(t *testing.T) {
+
+	t.Parallel()
+
+	path := writeProgram(t, `
+transaction(amount: Int) {
+    prepare(first: AuthAccount, second: AuthAccount) {
+        let total = amount * 2
+        log(first.address)
+        log(second.address)
+        log(total)
+    }
+}
+`)
+
+	client := newTestClient(t)
+	client.launchWithArguments(
+		LaunchArguments{
+			Program: path,
+			Args: []json.RawMessage{
+				json.RawMessage(`{"type":"Int","value":"21"}`),
+			},
+			Signers: []string{"0x1", "0x3"},
+		},
+		[]SourceBreakpoint{{Line: 5}},
+	)
+
+	assert.Equal(t, stopReasonBreakpoint, client.stopped().Reason)
+
+	var stackTrace StackTraceResponseBody
+	client.mustRequest("stackTrace", StackTraceArguments{ThreadID: threadID}, &stackTrace)
+	require.NotEmpty(t, stackTrace.StackFrames)
+	assert.Equal(t, &Source{Name: "test.cdc", Path: path}, stackTrace.StackFrames[0].Source)
+	assert.Equal(t, 5, stackTrace.StackFrames[0].Line)
+
+	locals := client.locals()
+	require.NotEmpty(t, locals)
+	assert.Contains(t, locals, Variable{Name: "total", Value: "42", Type: "Int"})
+
+	client.mustRequest("continue", map[string]any{"threadId": threadID}, nil)
+
+	var logs []string
+	for len(logs) < 3 {
+		var output OutputEventBody
+		err := json.Unmarshal(client.event("output").Body, &output)
+		require.NoError(t, err)
+		assert.Equal(t, outputCategoryStdout, output.Category)
+		logs = append(logs, output.Output)
+	}
+
+	assert.Equal(t,
+		[]string{"0x0000000000000001\n", "0x0000000000000003\n", "42\n"},
+		logs,
+	)
+
+	var exited ExitedEventBody
+	err := json.Unmarshal(client.event("exited").Body, &exited)
+	require.NoError(t, err)
+	assert.Equal(t, 0, exited.ExitCode)
+
+	client.event("terminated")
+}
+
+func TestArgumentsOfScript(t *testing.T) {
+
+	t.Parallel()
+
+	path := writeProgram(t, testProgram)
+
+	client := newTestClient(t)
+	client.launchWithArguments(
+		LaunchArguments{
+			Program: path,
+			Signers: []string{"0x1"},
+		},
+		nil,
+	)
+
+	var output OutputEventBody
+	err := json.Unmarshal(client.event("output").Body, &output)
+	require.NoError(t, err)
+	assert.Equal(t, outputCategoryStderr, output.Category)
+	assert.Contains(t, output.Output, "only supported for transactions")
+
+	client.event("terminated")
+}
+
+func TestUnsupportedRequest(t *testing.T) {
+
+	t.Parallel()
+
+	client := newTestClient(t)
+
+	response := client.request("evaluate", nil)
+	assert.False(t, response.Success)
+	assert.Equal(t, "unsupported request: evaluate", response.Message)
+
+	response = client.request("stackTrace", StackTraceArguments{ThreadID: threadID})
+	assert.False(t, response.Success)
+	assert.Equal(t, "program is not stopped", response.Message)
+
+	response = client.request("configurationDone", nil)
+	assert.False(t, response.Success)
+	assert.Equal(t, "no program was launched", response.Message)
+}
+
+func TestStepIntoAndOut(t *testing.T) {
+
+	t.Parallel()
+
+	path := writeProgram(t, testProgram)
+
+	client := newTestClient(t)
+	client.launch(path, false, 9)
+
+	stopped := client.stopped()
+	assert.Equal(t, stopReasonBreakpoint, stopped.Reason)
+
+	currentFrame := func() StackFrame {
+		var stackTrace StackTraceResponseBody
+		client.mustRequest("stackTrace", StackTraceArguments{ThreadID: threadID}, &stackTrace)
+		require.NotEmpty(t, stackTrace.StackFrames)
+		return stackTrace.StackFrames[0]
+	}
+
+	client.mustRequest("stepIn", map[string]any{"threadId": threadID}, nil)
+	assert.Equal(t, stopReasonStep, client.stopped().Reason)
+
+	frame := currentFrame()
+	assert.Equal(t, "add", frame.Name)
+	assert.Equal(t, 3, frame.Line)
+
+	client.mustRequest("stepOut", map[string]any{"threadId": threadID}, nil)
+	assert.Equal(t, stopReasonStep, client.stopped().Reason)
+
+	frame = currentFrame()
+	assert.Equal(t, "main", frame.Name)
+	assert.Equal(t, 10, frame.Line)
+
+	client.mustRequest("continue", map[string]any{"threadId": threadID}, nil)
+	client.event("terminated")
+}
+
+func TestConditionalBreakpointsAndLogpoints(t *testing.T) {
+
+	t.Parallel()
+
+	path := writeProgram(t, `
+pub fun main() {
+    var i = 0
+    while i < 5 {
+        i = i + 1
+        let j = i * 10
+    }
+}
+`)
+
+	client := newTestClient(t)
+	client.launchWithBreakpoints(
+		path,
+		false,
+		[]SourceBreakpoint{
+			{
+				Line:       5,
+				LogMessage: "i is {i}",
+			},
+			{
+				Line:         6,
+				Condition:    "i % 2 == 0",
+				HitCondition: "2",
+			},
+		},
+	)
+
+	var logs []string
+	for len(logs) < 4 {
+		var output OutputEventBody
+		err := json.Unmarshal(client.event("output").Body, &output)
+		require.NoError(t, err)
+		assert.Equal(t, outputCategoryConsole, output.Category)
+		logs = append(logs, output.Output)
+	}
+
+	assert.Equal(t,
+		[]string{"i is 0\n", "i is 1\n", "i is 2\n", "i is 3\n"},
+		logs,
+	)
+
+	// The condition is satisfied for i = 2 and i = 4,
+	// and the breakpoint takes effect on the second hit
+
+	assert.Equal(t, stopReasonBreakpoint, client.stopped().Reason)
+	assert.Equal(t,
+		[]Variable{
+			{Name: "i", Value: "4", Type: "Int"},
+		},
+		client.locals(),
+	)
+
+	client.mustRequest("continue", map[string]any{"threadId": threadID}, nil)
+
+	var output OutputEventBody
+	err := json.Unmarshal(client.event("output").Body, &output)
+	require.NoError(t, err)
+	assert.Equal(t, "i is 4\n", output.Output)
+
+	client.event("terminated")
+}
+
+func TestInvalidHitCondition(t *testing.T) {
+
+	t.Parallel()
+
+	path := writeProgram(t, testProgram)
+
+	client := newTestClient(t)
+
+	var setBreakpoints SetBreakpointsResponseBody
+	client.mustRequest(
+		"setBreakpoints",
+		SetBreakpointsArguments{
+			Source: Source{Path: path},
+			Breakpoints: []SourceBreakpoint{
+				{Line: 3, HitCondition: ">= 2"},
+			},
+		},
+		&setBreakpoints,
+	)
+
+	assert.Equal(t,
+		[]Breakpoint{
+			{
+				Verified: false,
+				Message:  "invalid hit condition, expected number of hits: >= 2",
+				Line:     3,
+			},
+		},
+		setBreakpoints.Breakpoints,
+	)
+}
@@ -0,0 +1,112 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dap
+
+import (
+	"sort"
+
+	"github.com/onflow/cadence/runtime/cmd"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// variableHandles maps variable references to the scopes and values
+// which can be expanded by the client.
+//
+// References are only valid while the program is stopped.
+type variableHandles struct {
+	containers []any
+}
+
+func (h *variableHandles) add(container any) int {
+	h.containers = append(h.containers, container)
+	// references must be positive, zero means the variable has no children
+	return len(h.containers)
+}
+
+func (h *variableHandles) get(reference int) (any, bool) {
+	if reference < 1 || reference > len(h.containers) {
+		return nil, false
+	}
+	return h.containers[reference-1], true
+}
+
+func (h *variableHandles) reset() {
+	h.containers = nil
+}
+
+// frameVariables are the variables of a frame
+type frameVariables struct {
+	interpreter *interpreter.Interpreter
+	activation  *interpreter.VariableActivation
+}
+
+// valueVariables are the children of a value
+type valueVariables struct {
+	interpreter *interpreter.Interpreter
+	value       interpreter.Value
+}
+
+func (h *variableHandles) variables(container any) []Variable {
+	switch container := container.(type) {
+	case frameVariables:
+		inter := container.interpreter
+		values := container.activation.FunctionValues()
+
+		names := make([]string, 0, len(values))
+		for name := range values { //nolint:maprange
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		result := make([]Variable, 0, len(names))
+		for _, name := range names {
+			result = append(result, h.variable(inter, name, values[name].GetValue()))
+		}
+		return result
+
+	case valueVariables:
+		inter := container.interpreter
+		children := cmd.ValueChildren(inter, container.value)
+
+		result := make([]Variable, 0, len(children))
+		for _, child := range children {
+			result = append(result, h.variable(inter, child.Name, child.Value))
+		}
+		return result
+	}
+
+	return nil
+}
+
+func (h *variableHandles) variable(inter *interpreter.Interpreter, name string, value interpreter.Value) Variable {
+	variable := Variable{
+		Name:  name,
+		Value: value.String(),
+		Type:  cmd.ValueType(inter, value),
+	}
+
+	if cmd.HasValueChildren(inter, value) {
+		variable.VariablesReference = h.add(valueVariables{
+			interpreter: inter,
+			value:       value,
+		})
+	}
+
+	return variable
+}
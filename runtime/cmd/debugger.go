@@ -0,0 +1,151 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/parser"
+)
+
+// ParseExpression parses an expression which is evaluated by the debugger
+func ParseExpression(code string) (ast.Expression, error) {
+	expression, errs := parser.ParseExpression(nil, []byte(code), parser.Config{})
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid expression: %w", errs[0])
+	}
+	err := checkPureExpression(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+	return expression, nil
+}
+
+// ParseBreakpointCondition parses the condition of a breakpoint
+func ParseBreakpointCondition(condition string) (ast.Expression, error) {
+	expression, errs := parser.ParseExpression(nil, []byte(condition), parser.Config{})
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid condition: %w", errs[0])
+	}
+	err := checkPureExpression(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition: %w", err)
+	}
+	return expression, nil
+}
+
+// checkPureExpression returns an error if the given expression may have side effects.
+//
+// Expressions evaluated by the debugger share the state of the debugged program,
+// so they may not invoke functions, create, destroy or move resources,
+// attach attachments, or declare functions, which may contain assignments
+func checkPureExpression(expression ast.Expression) (err error) {
+	ast.Inspect(expression, func(element ast.Element) bool {
+		if err != nil {
+			return false
+		}
+
+		switch element := element.(type) {
+		case *ast.InvocationExpression:
+			err = fmt.Errorf("invocations are not allowed: %s", element)
+		case *ast.CreateExpression:
+			err = fmt.Errorf("resource creations are not allowed: %s", element)
+		case *ast.DestroyExpression:
+			err = fmt.Errorf("resource destructions are not allowed: %s", element)
+		case *ast.AttachExpression:
+			err = fmt.Errorf("attachments are not allowed: %s", element)
+		case *ast.FunctionExpression:
+			err = fmt.Errorf("function expressions are not allowed: %s", element)
+		case *ast.UnaryExpression:
+			if element.Operation == ast.OperationMove {
+				err = fmt.Errorf("moves are not allowed: %s", element)
+			}
+		}
+
+		return err == nil
+	})
+
+	return err
+}
+
+// ParseLogMessage parses the message of a logpoint.
+// Expressions enclosed in braces are interpolated,
+// the text between them is represented as string expressions
+func ParseLogMessage(message string) ([]ast.Expression, error) {
+	parts := []ast.Expression{}
+
+	addText := func(text string) {
+		if text == "" {
+			return
+		}
+		parts = append(parts, ast.NewStringExpression(nil, text, ast.EmptyRange))
+	}
+
+	for {
+		start := strings.IndexByte(message, '{')
+		if start < 0 {
+			break
+		}
+
+		end := strings.IndexByte(message[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated expression in log message: %s", message[start:])
+		}
+		end += start
+
+		addText(message[:start])
+
+		code := message[start+1 : end]
+		expression, errs := parser.ParseExpression(nil, []byte(code), parser.Config{})
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("invalid expression in log message: %s: %w", code, errs[0])
+		}
+		err := checkPureExpression(expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression in log message: %s: %w", code, err)
+		}
+		parts = append(parts, expression)
+
+		message = message[end+1:]
+	}
+
+	addText(message)
+
+	return parts, nil
+}
+
+// FormatLogMessage is the inverse of ParseLogMessage
+func FormatLogMessage(parts []ast.Expression) string {
+	var builder strings.Builder
+
+	for _, part := range parts {
+		if stringExpression, ok := part.(*ast.StringExpression); ok {
+			builder.WriteString(stringExpression.Value)
+			continue
+		}
+
+		builder.WriteByte('{')
+		builder.WriteString(part.String())
+		builder.WriteByte('}')
+	}
+
+	return builder.String()
+}
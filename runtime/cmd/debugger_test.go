@@ -0,0 +1,77 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+)
+
+func TestParseLogMessage(t *testing.T) {
+
+	t.Parallel()
+
+	parts, err := ParseLogMessage("x = {x + 1}, done")
+	require.NoError(t, err)
+	require.Len(t, parts, 3)
+
+	assert.Equal(t, "x = ", parts[0].(*ast.StringExpression).Value)
+	assert.IsType(t, &ast.BinaryExpression{}, parts[1])
+	assert.Equal(t, ", done", parts[2].(*ast.StringExpression).Value)
+
+	assert.Equal(t, "x = {x + 1}, done", FormatLogMessage(parts))
+
+	_, err = ParseLogMessage("x = {x")
+	require.EqualError(t, err, "unterminated expression in log message: {x")
+
+	_, err = ParseLogMessage("x = {x +}")
+	require.Error(t, err)
+}
+
+func TestParseImpureExpressions(t *testing.T) {
+
+	t.Parallel()
+
+	for _, code := range []string{
+		"foo()",
+		"x.append(1)",
+		"[1, bar()]",
+		"create R()",
+		"destroy r",
+		"<-r",
+		"attach A() to r",
+		"fun (): Int { x = 1; return x }",
+	} {
+		_, err := ParseExpression(code)
+		assert.Error(t, err, code)
+
+		_, err = ParseBreakpointCondition(code)
+		assert.Error(t, err, code)
+
+		_, err = ParseLogMessage("{" + code + "}")
+		assert.Error(t, err, code)
+	}
+
+	_, err := ParseBreakpointCondition("x.length > 1 && y[0] == 2")
+	require.NoError(t, err)
+}
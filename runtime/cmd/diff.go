@@ -0,0 +1,229 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContextLines is the number of unchanged lines shown around changes in a diff
+const diffContextLines = 3
+
+type diffOperationKind byte
+
+const (
+	diffOperationEqual  diffOperationKind = ' '
+	diffOperationDelete diffOperationKind = '-'
+	diffOperationInsert diffOperationKind = '+'
+)
+
+type diffOperation struct {
+	line string
+	kind diffOperationKind
+}
+
+// UnifiedDiff returns the differences between the old and the new code in the unified diff format,
+// using the given path as the name of both the old and the new file.
+// It returns an empty string if there are no differences
+func UnifiedDiff(path string, oldCode []byte, newCode []byte) string {
+	operations := diffLines(
+		splitLines(string(oldCode)),
+		splitLines(string(newCode)),
+	)
+
+	// Determine the line numbers in the old and the new code before each operation
+
+	oldLines := make([]int, len(operations)+1)
+	newLines := make([]int, len(operations)+1)
+	for i, operation := range operations {
+		oldLines[i+1] = oldLines[i]
+		newLines[i+1] = newLines[i]
+		if operation.kind != diffOperationInsert {
+			oldLines[i+1]++
+		}
+		if operation.kind != diffOperationDelete {
+			newLines[i+1]++
+		}
+	}
+
+	var builder strings.Builder
+
+	for i := 0; i < len(operations); {
+		if operations[i].kind == diffOperationEqual {
+			i++
+			continue
+		}
+
+		// Extend the hunk over all changes which are separated
+		// by at most twice the number of context lines
+
+		lastChange := i
+		for j := i + 1; j < len(operations); j++ {
+			if operations[j].kind != diffOperationEqual {
+				lastChange = j
+			} else if j-lastChange > 2*diffContextLines {
+				break
+			}
+		}
+
+		start := i - diffContextLines
+		if start < 0 {
+			start = 0
+		}
+		end := lastChange + 1 + diffContextLines
+		if end > len(operations) {
+			end = len(operations)
+		}
+
+		if builder.Len() == 0 {
+			fmt.Fprintf(&builder, "--- %s\n+++ %s\n", path, path)
+		}
+
+		fmt.Fprintf(
+			&builder,
+			"@@ -%s +%s @@\n",
+			hunkRange(oldLines[start], oldLines[end]),
+			hunkRange(newLines[start], newLines[end]),
+		)
+
+		for _, operation := range operations[start:end] {
+			builder.WriteByte(byte(operation.kind))
+			builder.WriteString(operation.line)
+			if !strings.HasSuffix(operation.line, "\n") {
+				builder.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		i = end
+	}
+
+	return builder.String()
+}
+
+// hunkRange returns the range of lines in a hunk header,
+// given the number of lines before the hunk and at the end of the hunk
+func hunkRange(start int, end int) string {
+	count := end - start
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines splits the given code into lines, which include the line terminator, if any
+func splitLines(code string) []string {
+	if code == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(code, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns the shortest sequence of operations which turns the old lines into the new lines,
+// using Myers' difference algorithm
+func diffLines(oldLines []string, newLines []string) []diffOperation {
+	n := len(oldLines)
+	m := len(newLines)
+	maxDistance := n + m
+	offset := maxDistance
+
+	// v contains the furthest reaching x position for each diagonal k, at index offset+k.
+	// The trace contains the state of v before each round
+
+	v := make([]int, 2*maxDistance+2)
+	var trace [][]int
+
+Rounds:
+	for d := 0; d <= maxDistance; d++ {
+		trace = append(trace, append([]int(nil), v...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+
+			for x < n && y < m && oldLines[x] == newLines[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				break Rounds
+			}
+		}
+	}
+
+	// Backtrack through the trace to determine the operations, in reverse
+
+	var operations []diffOperation
+
+	x := n
+	y := m
+
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var previousK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			previousK = k + 1
+		} else {
+			previousK = k - 1
+		}
+
+		previousX := v[offset+previousK]
+		previousY := previousX - previousK
+
+		for x > previousX && y > previousY {
+			x--
+			y--
+			operations = append(operations, diffOperation{kind: diffOperationEqual, line: oldLines[x]})
+		}
+
+		if x == previousX {
+			y--
+			operations = append(operations, diffOperation{kind: diffOperationInsert, line: newLines[y]})
+		} else {
+			x--
+			operations = append(operations, diffOperation{kind: diffOperationDelete, line: oldLines[x]})
+		}
+	}
+
+	for x > 0 && y > 0 {
+		x--
+		y--
+		operations = append(operations, diffOperation{kind: diffOperationEqual, line: oldLines[x]})
+	}
+
+	for i, j := 0, len(operations)-1; i < j; i, j = i+1, j-1 {
+		operations[i], operations[j] = operations[j], operations[i]
+	}
+
+	return operations
+}
@@ -0,0 +1,97 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("equal", func(t *testing.T) {
+
+		t.Parallel()
+
+		code := []byte("a\nb\n")
+		require.Equal(t, "", UnifiedDiff("test.cdc", code, code))
+	})
+
+	t.Run("changes", func(t *testing.T) {
+
+		t.Parallel()
+
+		oldCode := []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n")
+		newCode := []byte("1\n2a\n3\n4\n5\n6\n7\n8\n9\n10\n11\n13\n14\n15\n16\n")
+
+		require.Equal(t,
+			"--- test.cdc\n"+
+				"+++ test.cdc\n"+
+				"@@ -1,5 +1,5 @@\n"+
+				" 1\n"+
+				"-2\n"+
+				"+2a\n"+
+				" 3\n"+
+				" 4\n"+
+				" 5\n"+
+				"@@ -9,7 +9,7 @@\n"+
+				" 9\n"+
+				" 10\n"+
+				" 11\n"+
+				"-12\n"+
+				" 13\n"+
+				" 14\n"+
+				" 15\n"+
+				"+16\n",
+			UnifiedDiff("test.cdc", oldCode, newCode),
+		)
+	})
+
+	t.Run("missing newline", func(t *testing.T) {
+
+		t.Parallel()
+
+		require.Equal(t,
+			"--- test.cdc\n"+
+				"+++ test.cdc\n"+
+				"@@ -1,1 +1,2 @@\n"+
+				"-a\n"+
+				"\\ No newline at end of file\n"+
+				"+a\n"+
+				"+b\n",
+			UnifiedDiff("test.cdc", []byte("a"), []byte("a\nb\n")),
+		)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+
+		t.Parallel()
+
+		require.Equal(t,
+			"--- test.cdc\n"+
+				"+++ test.cdc\n"+
+				"@@ -0,0 +1,1 @@\n"+
+				"+a\n",
+			UnifiedDiff("test.cdc", nil, []byte("a\n")),
+		)
+	})
+}
@@ -0,0 +1,382 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package execute
+
+import (
+	goErrors "errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/cmd"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/emulator"
+	"github.com/onflow/cadence/runtime/parser"
+)
+
+// NewConsoleREPLWithAccounts returns a REPL which additionally provides commands
+// to create accounts, deploy contracts, execute transactions and scripts,
+// and to inspect the storage of accounts and emitted events.
+//
+// The accounts, contracts and storage are emulated in memory
+func NewConsoleREPLWithAccounts() (*ConsoleREPL, error) {
+	consoleREPL, err := NewConsoleREPL()
+	if err != nil {
+		return nil, err
+	}
+
+	consoleREPL.emulator = emulator.NewWithDebugger(consoleREPL.debugger)
+	consoleREPL.emulator.OnLog = func(message string) {
+		fmt.Println(message)
+	}
+
+	return consoleREPL, nil
+}
+
+func (consoleREPL *ConsoleREPL) createAccount() {
+	address, err := consoleREPL.emulator.CreateAccount()
+	if err != nil {
+		printError(fmt.Sprintf("Failed to create account: %s", err))
+		return
+	}
+
+	fmt.Println(colorizeResult(address.HexWithPrefix()))
+}
+
+func (consoleREPL *ConsoleREPL) listAccounts() {
+	e := consoleREPL.emulator
+
+	for _, address := range e.Accounts() {
+		names, err := e.Contracts(address)
+		if err != nil {
+			printError(fmt.Sprintf("Failed to get contracts of account %s: %s", address.HexWithPrefix(), err))
+			return
+		}
+
+		if len(names) == 0 {
+			fmt.Println(address.HexWithPrefix())
+		} else {
+			fmt.Printf("%s: %s\n", address.HexWithPrefix(), strings.Join(names, ", "))
+		}
+	}
+}
+
+func (consoleREPL *ConsoleREPL) deployContract(argument string) {
+	fields := strings.Fields(argument)
+	if len(fields) != 2 {
+		printError("Missing address or path")
+		return
+	}
+
+	address, err := common.HexToAddress(fields[0])
+	if err != nil {
+		printError(fmt.Sprintf("Invalid address: %s", fields[0]))
+		return
+	}
+
+	path := fields[1]
+	code, err := os.ReadFile(path)
+	if err != nil {
+		printError(fmt.Sprintf("Failed to read file %s: %s", path, err))
+		return
+	}
+
+	var name string
+	consoleREPL.evaluate(func() {
+		name, err = consoleREPL.emulator.DeployContract(address, code)
+	})
+	if err != nil {
+		consoleREPL.printAccountError("Failed to deploy contract", err)
+		return
+	}
+
+	fmt.Printf("Deployed %s to %s\n", name, address.HexWithPrefix())
+}
+
+// executeTransaction executes a transaction, signed by the given comma-separated signers, e.g. `0x1,0x2 code`.
+// The code is either the path of a file, a transaction, or statements,
+// which are executed in the prepare phase of a transaction
+func (consoleREPL *ConsoleREPL) executeTransaction(argument string) {
+	signersArgument, codeArgument, _ := strings.Cut(strings.TrimSpace(argument), " ")
+
+	var signers []common.Address
+	for _, signer := range strings.Split(signersArgument, ",") {
+		address, err := common.HexToAddress(signer)
+		if err != nil {
+			printError(fmt.Sprintf("Invalid signer address: %s", signer))
+			return
+		}
+		signers = append(signers, address)
+	}
+
+	code, err := readCodeArgument(codeArgument)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	if len(code) == 0 {
+		printError("Missing transaction")
+		return
+	}
+
+	imports, err := consoleREPL.contractImports()
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+
+	code = transactionCode(code, len(signers), imports)
+
+	consoleREPL.evaluate(func() {
+		err = consoleREPL.emulator.ExecuteTransaction([]byte(code), signers...)
+	})
+	if err != nil {
+		consoleREPL.printAccountError("Failed to execute transaction", err)
+	}
+}
+
+// executeScript executes a script and prints its result.
+// The code is either the path of a file, a script, or an expression
+func (consoleREPL *ConsoleREPL) executeScript(argument string) {
+	code, err := readCodeArgument(argument)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	if len(code) == 0 {
+		printError("Missing script")
+		return
+	}
+
+	imports, err := consoleREPL.contractImports()
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+
+	code = scriptCode(code, imports)
+
+	var result cadence.Value
+	consoleREPL.evaluate(func() {
+		result, err = consoleREPL.emulator.ExecuteScript([]byte(code))
+	})
+	if err != nil {
+		consoleREPL.printAccountError("Failed to execute script", err)
+		return
+	}
+
+	fmt.Println(colorizeResult(result.String()))
+}
+
+func (consoleREPL *ConsoleREPL) showStorage(argument string) {
+	addressArgument := strings.TrimSpace(argument)
+	if len(addressArgument) == 0 {
+		printError("Missing address")
+		return
+	}
+
+	address, err := common.HexToAddress(addressArgument)
+	if err != nil {
+		printError(fmt.Sprintf("Invalid address: %s", addressArgument))
+		return
+	}
+
+	inter, err := consoleREPL.emulator.Storage()
+	if err != nil {
+		printError(fmt.Sprintf("Failed to read storage: %s", err))
+		return
+	}
+
+	for _, child := range cmd.StoredValues(inter, address) {
+		printValueTree(inter, child.Name, child.Value, 0, defaultExploreDepth)
+	}
+}
+
+func (consoleREPL *ConsoleREPL) showEvents() {
+	for _, event := range consoleREPL.emulator.Events() {
+		fmt.Println(event.String())
+	}
+}
+
+// printAccountError prints an error which occurred while executing a transaction or script.
+// Errors of the program are pretty printed with the code they occurred in
+func (consoleREPL *ConsoleREPL) printAccountError(message string, err error) {
+	var runtimeErr runtime.Error
+	if goErrors.As(err, &runtimeErr) {
+		consoleREPL.onError(runtimeErr.Err, runtimeErr.Location, runtimeErr.Codes)
+		return
+	}
+
+	printError(fmt.Sprintf("%s: %s", message, err))
+}
+
+// contractImports returns import declarations for the contracts deployed to all accounts,
+// so transactions and scripts given as statements or expressions can refer to them.
+// If several accounts have a contract with the same name, the contract of the first account is imported
+func (consoleREPL *ConsoleREPL) contractImports() (string, error) {
+	e := consoleREPL.emulator
+
+	var builder strings.Builder
+	imported := map[string]struct{}{}
+
+	for _, address := range e.Accounts() {
+		names, err := e.Contracts(address)
+		if err != nil {
+			return "", fmt.Errorf("failed to get contracts of account %s: %w", address.HexWithPrefix(), err)
+		}
+
+		for _, name := range names {
+			if _, ok := imported[name]; ok {
+				continue
+			}
+			imported[name] = struct{}{}
+
+			_, _ = fmt.Fprintf(&builder, "import %s from %s\n", name, address.HexWithPrefix())
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// readCodeArgument returns the code given as the argument of a command.
+// If the argument is the path of a Cadence file, the contents of the file are returned
+func readCodeArgument(argument string) (string, error) {
+	argument = strings.TrimSpace(argument)
+	if !strings.HasSuffix(argument, ".cdc") {
+		return argument, nil
+	}
+
+	code, err := os.ReadFile(argument)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file %s: %w", argument, err)
+	}
+
+	return string(code), nil
+}
+
+// transactionCode returns the given code if it declares a transaction.
+// Otherwise, the code is considered statements,
+// and a transaction is returned which executes the statements in its prepare phase.
+// The signers are available as `signer`, or `signer1`, `signer2`, etc. if there are multiple
+func transactionCode(code string, signerCount int, imports string) string {
+	program, err := parser.ParseProgram(nil, []byte(code), parser.Config{})
+	if err == nil && len(program.TransactionDeclarations()) > 0 {
+		return code
+	}
+
+	parameters := make([]string, 0, signerCount)
+	for i := 1; i <= signerCount; i++ {
+		name := "signer"
+		if signerCount > 1 {
+			name = fmt.Sprintf("signer%d", i)
+		}
+		parameters = append(parameters, fmt.Sprintf("%s: AuthAccount", name))
+	}
+
+	return fmt.Sprintf(
+		"%stransaction {\n    prepare(%s) {\n        %s\n    }\n}\n",
+		imports,
+		strings.Join(parameters, ", "),
+		code,
+	)
+}
+
+// scriptCode returns the given code if it declares a script's main function.
+// Otherwise, the code is considered an expression,
+// and a script is returned which returns the result of the expression
+func scriptCode(code string, imports string) string {
+	program, err := parser.ParseProgram(nil, []byte(code), parser.Config{})
+	if err == nil && hasMainFunction(program) {
+		return code
+	}
+
+	return fmt.Sprintf(
+		"%spub fun main(): AnyStruct {\n    return %s\n}\n",
+		imports,
+		code,
+	)
+}
+
+func hasMainFunction(program *ast.Program) bool {
+	for _, declaration := range program.FunctionDeclarations() {
+		if declaration.Identifier.Identifier == "main" {
+			return true
+		}
+	}
+	return false
+}
+
+var accountCommands []command
+
+func init() {
+	accountCommands = []command{
+		{
+			name:        "account",
+			description: "Create an account",
+			handler: func(consoleREPL *ConsoleREPL, _ string) {
+				consoleREPL.createAccount()
+			},
+		},
+		{
+			name:        "accounts",
+			description: "List accounts and their contracts",
+			handler: func(consoleREPL *ConsoleREPL, _ string) {
+				consoleREPL.listAccounts()
+			},
+		},
+		{
+			name:        "deploy",
+			description: "Deploy or update contract from file: .deploy address path",
+			handler: func(consoleREPL *ConsoleREPL, argument string) {
+				consoleREPL.deployContract(argument)
+			},
+		},
+		{
+			name:        "transaction",
+			description: "Execute transaction, file, or statements with signers: .transaction 0x1,0x2 code",
+			handler: func(consoleREPL *ConsoleREPL, argument string) {
+				consoleREPL.executeTransaction(argument)
+			},
+		},
+		{
+			name:        "script",
+			description: "Execute script, file, or expression: .script code",
+			handler: func(consoleREPL *ConsoleREPL, argument string) {
+				consoleREPL.executeScript(argument)
+			},
+		},
+		{
+			name:        "storage",
+			description: "Show values stored in account",
+			handler: func(consoleREPL *ConsoleREPL, argument string) {
+				consoleREPL.showStorage(argument)
+			},
+		},
+		{
+			name:        "events",
+			description: "Show emitted events",
+			handler: func(consoleREPL *ConsoleREPL, _ string) {
+				consoleREPL.showEvents()
+			},
+		},
+	}
+}
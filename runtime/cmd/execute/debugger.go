@@ -21,11 +21,14 @@ package execute
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 
 	"github.com/c-bata/go-prompt"
 
+	"github.com/onflow/cadence/runtime/cmd"
+	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/interpreter"
 )
 
@@ -40,12 +43,46 @@ const commandShortShow = "s"
 const commandLongShow = "show"
 const commandShortWhere = "w"
 const commandLongWhere = "where"
+const commandShortStepIn = "i"
+const commandLongStepIn = "in"
+const commandShortStepOut = "o"
+const commandLongStepOut = "out"
+const commandShortBreak = "b"
+const commandLongBreak = "break"
+const commandLongLogpoint = "logpoint"
+const commandLongHitCount = "hitcount"
+const commandShortDelete = "d"
+const commandLongDelete = "delete"
+const commandLongBreakpoints = "breakpoints"
+const commandShortBacktrace = "bt"
+const commandLongBacktrace = "backtrace"
+const commandShortUp = "u"
+const commandLongUp = "up"
+const commandLongDown = "down"
+const commandShortExplore = "x"
+const commandLongExplore = "explore"
+const commandLongStorage = "storage"
+
+// defaultExploreDepth is the number of levels of a value shown by the explore command
+const defaultExploreDepth = 3
 
 var debuggerCommandSuggestions = []prompt.Suggest{
 	{Text: commandLongContinue, Description: "Continue"},
-	{Text: commandLongNext, Description: "Next / step"},
+	{Text: commandLongNext, Description: "Next / step over"},
+	{Text: commandLongStepIn, Description: "Step into function call"},
+	{Text: commandLongStepOut, Description: "Step out of function"},
 	{Text: commandLongWhere, Description: "Location info"},
+	{Text: commandLongBacktrace, Description: "List frames of the call stack"},
+	{Text: commandLongUp, Description: "Select the caller's frame"},
+	{Text: commandLongDown, Description: "Select the callee's frame"},
 	{Text: commandLongShow, Description: "Show variable(s)"},
+	{Text: commandLongExplore, Description: "Explore value: explore [-depth n] expression"},
+	{Text: commandLongStorage, Description: "Explore account storage: storage address"},
+	{Text: commandLongBreak, Description: "Set breakpoint: break [file:]line [if condition]"},
+	{Text: commandLongLogpoint, Description: "Set logpoint: logpoint [file:]line message with {expression}"},
+	{Text: commandLongHitCount, Description: "Stop at breakpoint after hits: hitcount [file:]line count"},
+	{Text: commandLongDelete, Description: "Delete breakpoint: delete [file:]line"},
+	{Text: commandLongBreakpoints, Description: "List breakpoints"},
 	{Text: commandLongExit, Description: "Exit"},
 	{Text: commandLongHelp, Description: "Help"},
 }
@@ -53,13 +90,40 @@ var debuggerCommandSuggestions = []prompt.Suggest{
 type InteractiveDebugger struct {
 	debugger *interpreter.Debugger
 	stop     interpreter.Stop
+	// frames are the frames of the stop, innermost first
+	frames []interpreter.Frame
+	// frame is the index of the selected frame
+	frame int
+	// done is closed when the program finished, if it is not nil
+	done <-chan struct{}
+	// finished is true if the program finished while stepping
+	finished bool
 }
 
-func NewInteractiveDebugger(debugger *interpreter.Debugger, stop interpreter.Stop) *InteractiveDebugger {
-	return &InteractiveDebugger{
+// NewInteractiveDebugger returns a debugger for the program stopped at the given stop.
+// If the given done channel is not nil, it must be closed when the program finishes,
+// so that stepping past the end of the program exits the debugger
+func NewInteractiveDebugger(
+	debugger *interpreter.Debugger,
+	stop interpreter.Stop,
+	done <-chan struct{},
+) *InteractiveDebugger {
+	d := &InteractiveDebugger{
 		debugger: debugger,
-		stop:     stop,
+		done:     done,
 	}
+	d.setStop(stop)
+	return d
+}
+
+func (d *InteractiveDebugger) setStop(stop interpreter.Stop) {
+	d.stop = stop
+	d.frames = d.debugger.Frames(stop)
+	d.frame = 0
+}
+
+func (d *InteractiveDebugger) currentFrame() interpreter.Frame {
+	return d.frames[d.frame]
 }
 
 func (d *InteractiveDebugger) Continue() {
@@ -67,13 +131,335 @@ func (d *InteractiveDebugger) Continue() {
 }
 
 func (d *InteractiveDebugger) Next() {
-	d.stop = d.debugger.Next()
+	d.step(interpreter.StepModeOver)
+}
+
+func (d *InteractiveDebugger) StepIn() {
+	d.step(interpreter.StepModeInto)
+}
+
+func (d *InteractiveDebugger) StepOut() {
+	d.step(interpreter.StepModeOut)
+}
+
+func (d *InteractiveDebugger) step(mode interpreter.StepMode) {
+	d.debugger.RequestStep(mode)
+	d.debugger.Continue()
+
+	select {
+	case stop := <-d.debugger.Stops():
+		d.setStop(stop)
+		d.showStop()
+
+	case <-d.done:
+		d.finished = true
+		fmt.Println("finished")
+	}
+}
+
+// Backtrace lists the frames of the call stack, innermost first,
+// and marks the selected frame
+func (d *InteractiveDebugger) Backtrace() {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	for index, frame := range d.frames {
+		marker := " "
+		if index == d.frame {
+			marker = "*"
+		}
+		_, _ = fmt.Fprintf(w,
+			"%s #%d\t%s\t%s @ %d\n",
+			marker,
+			index,
+			frame.FunctionName(),
+			frame.Location,
+			frame.Position.Line,
+		)
+	}
+	_ = w.Flush()
+}
+
+// Up selects the frame of the caller of the selected frame
+func (d *InteractiveDebugger) Up() {
+	if d.frame+1 >= len(d.frames) {
+		d.printError("already in the outermost frame")
+		return
+	}
+	d.frame++
+	d.Where()
+}
+
+// Down selects the frame of the callee of the selected frame
+func (d *InteractiveDebugger) Down() {
+	if d.frame == 0 {
+		d.printError("already in the innermost frame")
+		return
+	}
+	d.frame--
+	d.Where()
+}
+
+// Explore evaluates the given expression in the selected frame,
+// and shows the resulting value as a tree.
+// The number of levels shown may be given with `-depth n`
+func (d *InteractiveDebugger) Explore(arguments []string) {
+	depth := defaultExploreDepth
+
+	if len(arguments) > 0 && arguments[0] == "-depth" {
+		if len(arguments) < 2 {
+			d.printError("missing depth")
+			return
+		}
+		parsedDepth, err := strconv.ParseUint(arguments[1], 10, 32)
+		if err != nil {
+			d.printError(fmt.Sprintf("invalid depth: %s", arguments[1]))
+			return
+		}
+		depth = int(parsedDepth)
+		arguments = arguments[2:]
+	}
+
+	if len(arguments) == 0 {
+		d.printError("missing expression")
+		return
+	}
+
+	code := strings.Join(arguments, " ")
+
+	expression, err := cmd.ParseExpression(code)
+	if err != nil {
+		d.printError(err.Error())
+		return
+	}
+
+	frame := d.currentFrame()
+
+	value, err := d.debugger.EvaluateIn(frame, expression)
+	if err != nil {
+		d.printError(err.Error())
+		return
+	}
+
+	printValueTree(frame.Interpreter, code, value, 0, depth)
+}
+
+// Storage shows the values stored in the account with the given address
+func (d *InteractiveDebugger) Storage(arguments []string) {
+	if len(arguments) != 1 {
+		d.printError("expected address")
+		return
+	}
+
+	address, err := common.HexToAddress(arguments[0])
+	if err != nil {
+		d.printError(fmt.Sprintf("invalid address: %s", arguments[0]))
+		return
+	}
+
+	inter := d.currentFrame().Interpreter
+
+	for _, child := range cmd.StoredValues(inter, address) {
+		printValueTree(inter, child.Name, child.Value, 0, defaultExploreDepth)
+	}
+}
+
+// printValueTree prints the given value and its children, up to the given depth
+func printValueTree(
+	inter *interpreter.Interpreter,
+	name string,
+	value interpreter.Value,
+	level int,
+	depth int,
+) {
+	indentation := strings.Repeat("  ", level)
+
+	typeName := cmd.ValueType(inter, value)
+	if typeName != "" {
+		typeName = fmt.Sprintf(" (%s)", typeName)
+	}
+
+	if level >= depth || !cmd.HasValueChildren(inter, value) {
+		fmt.Printf("%s%s%s = %s\n", indentation, name, typeName, colorizeValue(value))
+		return
+	}
+
+	fmt.Printf("%s%s%s\n", indentation, name, typeName)
+
+	for _, child := range cmd.ValueChildren(inter, value) {
+		printValueTree(inter, child.Name, child.Value, level+1, depth)
+	}
 }
 
-// Show shows the values for the variables with the given names.
+func (d *InteractiveDebugger) showStop() {
+	if d.stop.ConditionError != nil {
+		message := fmt.Sprintf(
+			"error: invalid breakpoint condition: %s",
+			d.stop.ConditionError,
+		)
+		fmt.Println(colorizeError(message))
+	}
+
+	d.Where()
+}
+
+// Break sets a breakpoint on the given line.
+// The line may be prefixed with a file name, and followed by a condition,
+// e.g. `main.cdc:12 if x > 1`
+func (d *InteractiveDebugger) Break(arguments []string) {
+	if len(arguments) < 1 {
+		d.printError("missing line")
+		return
+	}
+
+	location, line, ok := d.parseLine(arguments[0])
+	if !ok {
+		return
+	}
+
+	breakpoint := &interpreter.Breakpoint{
+		Location: location,
+		Line:     line,
+	}
+
+	conditionArguments := arguments[1:]
+	if len(conditionArguments) > 0 {
+		if conditionArguments[0] != "if" || len(conditionArguments) < 2 {
+			d.printError("expected condition: if <expression>")
+			return
+		}
+		condition, err := cmd.ParseBreakpointCondition(strings.Join(conditionArguments[1:], " "))
+		if err != nil {
+			d.printError(err.Error())
+			return
+		}
+		breakpoint.Condition = condition
+	}
+
+	d.debugger.SetBreakpoint(breakpoint)
+}
+
+// Logpoint sets a logpoint on the given line.
+// Expressions in the message which are enclosed in braces are interpolated
+func (d *InteractiveDebugger) Logpoint(arguments []string) {
+	if len(arguments) < 2 {
+		d.printError("expected line and message")
+		return
+	}
+
+	location, line, ok := d.parseLine(arguments[0])
+	if !ok {
+		return
+	}
+
+	logMessage, err := cmd.ParseLogMessage(strings.Join(arguments[1:], " "))
+	if err != nil {
+		d.printError(err.Error())
+		return
+	}
+
+	d.debugger.SetBreakpoint(&interpreter.Breakpoint{
+		Location:   location,
+		Line:       line,
+		LogMessage: logMessage,
+	})
+}
+
+// HitCount sets the number of hits after which the breakpoint on the given line takes effect
+func (d *InteractiveDebugger) HitCount(arguments []string) {
+	if len(arguments) != 2 {
+		d.printError("expected line and hit count")
+		return
+	}
+
+	location, line, ok := d.parseLine(arguments[0])
+	if !ok {
+		return
+	}
+
+	hitCount, err := strconv.ParseUint(arguments[1], 10, 32)
+	if err != nil {
+		d.printError(fmt.Sprintf("invalid hit count: %s", arguments[1]))
+		return
+	}
+
+	breakpoint := d.debugger.Breakpoint(location, line)
+	if breakpoint == nil {
+		d.printError(fmt.Sprintf("no breakpoint at line %d", line))
+		return
+	}
+
+	// Replace the breakpoint instead of updating it,
+	// as it may be concurrently read by the interpreter
+	updated := *breakpoint
+	updated.HitCount = uint(hitCount)
+	d.debugger.SetBreakpoint(&updated)
+}
+
+func (d *InteractiveDebugger) Delete(arguments []string) {
+	if len(arguments) != 1 {
+		d.printError("expected line")
+		return
+	}
+
+	location, line, ok := d.parseLine(arguments[0])
+	if !ok {
+		return
+	}
+
+	d.debugger.RemoveBreakpoint(location, line)
+}
+
+func (d *InteractiveDebugger) ListBreakpoints() {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	for _, breakpoint := range d.debugger.Breakpoints() {
+		_, _ = fmt.Fprintf(w,
+			"%s:%d\thits: %d",
+			breakpoint.Location,
+			breakpoint.Line,
+			breakpoint.Hits(),
+		)
+		if breakpoint.HitCount > 0 {
+			_, _ = fmt.Fprintf(w, "\tafter: %d", breakpoint.HitCount)
+		}
+		if breakpoint.Condition != nil {
+			_, _ = fmt.Fprintf(w, "\tif %s", breakpoint.Condition)
+		}
+		if breakpoint.IsLogpoint() {
+			_, _ = fmt.Fprintf(w, "\tlog %s", cmd.FormatLogMessage(breakpoint.LogMessage))
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+	_ = w.Flush()
+}
+
+// parseLine parses a line number, optionally prefixed with a file name.
+// Without a file name, the line refers to the location of the current stop
+func (d *InteractiveDebugger) parseLine(argument string) (common.Location, uint, bool) {
+	location := d.stop.Interpreter.Location
+
+	lineArgument := argument
+	separatorIndex := strings.LastIndexByte(argument, ':')
+	if separatorIndex >= 0 {
+		location = common.NewStringLocation(nil, argument[:separatorIndex])
+		lineArgument = argument[separatorIndex+1:]
+	}
+
+	line, err := strconv.ParseUint(lineArgument, 10, 32)
+	if err != nil || line == 0 {
+		d.printError(fmt.Sprintf("invalid line: %s", lineArgument))
+		return nil, 0, false
+	}
+
+	return location, uint(line), true
+}
+
+func (d *InteractiveDebugger) printError(message string) {
+	fmt.Println(colorizeError(fmt.Sprintf("error: %s", message)))
+}
+
+// Show shows the values for the variables with the given names in the selected frame.
 // If no names are given, lists all non-base variables
 func (d *InteractiveDebugger) Show(names []string) {
-	current := d.debugger.CurrentActivation(d.stop.Interpreter)
+	current := d.currentFrame().Activation
 	switch len(names) {
 	case 0:
 		for name := range current.FunctionValues() { //nolint:maprange
@@ -122,10 +508,34 @@ func (d *InteractiveDebugger) Run() {
 			d.Continue()
 		case commandShortNext, commandLongNext:
 			d.Next()
+		case commandShortStepIn, commandLongStepIn:
+			d.StepIn()
+		case commandShortStepOut, commandLongStepOut:
+			d.StepOut()
+		case commandShortBreak, commandLongBreak:
+			d.Break(arguments)
+		case commandLongLogpoint:
+			d.Logpoint(arguments)
+		case commandLongHitCount:
+			d.HitCount(arguments)
+		case commandShortDelete, commandLongDelete:
+			d.Delete(arguments)
+		case commandLongBreakpoints:
+			d.ListBreakpoints()
 		case commandShortShow, commandLongShow:
 			d.Show(arguments)
+		case commandShortExplore, commandLongExplore:
+			d.Explore(arguments)
+		case commandLongStorage:
+			d.Storage(arguments)
 		case commandShortWhere, commandLongWhere:
 			d.Where()
+		case commandShortBacktrace, commandLongBacktrace:
+			d.Backtrace()
+		case commandShortUp, commandLongUp:
+			d.Up()
+		case commandLongDown:
+			d.Down()
 		case commandShortHelp, commandLongHelp:
 			d.Help()
 		case commandLongExit:
@@ -146,11 +556,14 @@ func (d *InteractiveDebugger) Run() {
 	}
 
 	exitChecker := func(in string, breakline bool) bool {
+		if !breakline {
+			return false
+		}
 		switch in {
 		case commandShortContinue, commandLongContinue:
-			return breakline
+			return true
 		}
-		return false
+		return d.finished
 	}
 
 	fmt.Println()
@@ -175,10 +588,12 @@ func (d *InteractiveDebugger) Help() {
 	_ = w.Flush()
 }
 
+// Where shows the location of the selected frame
 func (d *InteractiveDebugger) Where() {
+	frame := d.currentFrame()
 	fmt.Printf(
 		"%s @ %d\n",
-		d.stop.Interpreter.Location,
-		d.stop.Statement.StartPosition().Line,
+		frame.Location,
+		frame.Position.Line,
 	)
 }
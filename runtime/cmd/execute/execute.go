@@ -19,26 +19,117 @@
 package execute
 
 import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/onflow/cadence/runtime"
 	"github.com/onflow/cadence/runtime/cmd"
 	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/profiler"
 )
 
 // Execute parses the given filename and prints any syntax errors.
 // If there are no syntax errors, the program is interpreted.
 // If after the interpretation a global function `main` is defined, it will be called.
 // The program may call the function `log` to print a value.
+//
+// With the `--profile` flag, the computation and memory usage of the program
+// is written to the given file as a pprof profile.
+// With the `--trace` flag, the execution trace of the program
+// is written to the given file as a Chrome trace or as OTLP-JSON
 func Execute(args []string, debugger *interpreter.Debugger) {
 
+	flags := flag.NewFlagSet("execute", flag.ExitOnError)
+	profileFlag := flags.String("profile", "", "write a pprof profile of the computation and memory usage to the given file")
+	traceFlag := flags.String("trace", "", "write the execution trace to the given file")
+	traceFormatFlag := flags.String("trace-format", "chrome", "the format of the execution trace: chrome or otlp")
+	_ = flags.Parse(args)
+
+	var marshalTrace func(*runtime.TraceRecorder) ([]byte, error)
+	switch *traceFormatFlag {
+	case "chrome":
+		marshalTrace = (*runtime.TraceRecorder).MarshalChromeTrace
+	case "otlp":
+		marshalTrace = (*runtime.TraceRecorder).MarshalOTLP
+	default:
+		cmd.ExitWithError(fmt.Sprintf("unsupported trace format: %s", *traceFormatFlag))
+	}
+
+	args = flags.Args()
 	if len(args) < 1 {
 		cmd.ExitWithError("no input file")
 	}
 
-	inter, _, must := cmd.PrepareInterpreter(args[0], debugger)
+	config := cmd.NewInterpreterConfig(cmd.StandardOutputLogger{}, debugger)
+
+	var prof *profiler.Profiler
+	if *profileFlag != "" {
+		prof = profiler.NewProfiler()
+		prof.Install(config)
+	}
+
+	var traceRecorder *runtime.TraceRecorder
+	if *traceFlag != "" {
+		traceRecorder = runtime.NewTraceRecorder()
+		config.TracingEnabled = true
+		config.OnRecordTrace = func(
+			inter *interpreter.Interpreter,
+			operation string,
+			duration time.Duration,
+			attrs []attribute.KeyValue,
+		) {
+			traceRecorder.RecordTrace(operation, inter.Location, duration, attrs)
+		}
+	}
+
+	inter, _, must := cmd.PrepareInterpreterWithConfig(args[0], config)
 
-	if !inter.Globals.Contains("main") {
-		return
+	var err error
+	if inter.Globals.Contains("main") {
+		_, err = inter.Invoke("main")
+	}
+
+	// Write the profile and trace even if the program failed,
+	// as the usage up to the failure is of interest, too
+	if prof != nil {
+		writeProfile(prof, *profileFlag)
+	}
+	if traceRecorder != nil {
+		writeTrace(traceRecorder, marshalTrace, *traceFlag)
 	}
 
-	_, err := inter.Invoke("main")
 	must(err)
 }
+
+func writeProfile(prof *profiler.Profiler, path string) {
+	file, err := os.Create(path)
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+	defer file.Close()
+
+	err = prof.WriteProfile(file)
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+}
+
+func writeTrace(
+	traceRecorder *runtime.TraceRecorder,
+	marshal func(*runtime.TraceRecorder) ([]byte, error),
+	path string,
+) {
+	trace, err := marshal(traceRecorder)
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+
+	err = os.WriteFile(path, trace, 0644)
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+}
@@ -23,7 +23,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"unicode"
 
@@ -33,7 +35,9 @@ import (
 	"github.com/onflow/cadence"
 	jsoncdc "github.com/onflow/cadence/encoding/json"
 	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/cmd"
 	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/emulator"
 	"github.com/onflow/cadence/runtime/interpreter"
 	"github.com/onflow/cadence/runtime/pretty"
 	"github.com/onflow/cadence/runtime/sema"
@@ -45,16 +49,26 @@ type ConsoleREPL struct {
 	lineNumber         int
 	errorPrettyPrinter pretty.ErrorPrettyPrinter
 	repl               *runtime.REPL
+	debugger           *interpreter.Debugger
 	historyWriter      *csv.Writer
+	// emulator is the emulated chain used by the account commands,
+	// nil if the REPL was not started with accounts
+	emulator *emulator.Emulator
 }
 
 func NewConsoleREPL() (*ConsoleREPL, error) {
+	debugger := interpreter.NewDebugger()
+	debugger.OnLogpoint = func(_ *interpreter.Breakpoint, message string) {
+		fmt.Println(message)
+	}
+
 	consoleREPL := &ConsoleREPL{
 		lineNumber:         1,
 		errorPrettyPrinter: pretty.NewErrorPrettyPrinter(os.Stderr, true),
+		debugger:           debugger,
 	}
 
-	repl, err := runtime.NewREPL()
+	repl, err := runtime.NewREPLWithDebugger(debugger)
 	if err != nil {
 		return nil, err
 	}
@@ -80,7 +94,7 @@ func (consoleREPL *ConsoleREPL) onResult(value interpreter.Value) {
 
 func (consoleREPL *ConsoleREPL) handleCommand(command string) {
 	parts := strings.SplitN(command, " ", 2)
-	for _, command := range commands {
+	for _, command := range consoleREPL.availableCommands() {
 		if command.name != parts[0][1:] {
 			continue
 		}
@@ -138,6 +152,181 @@ func (consoleREPL *ConsoleREPL) showType(expression string) {
 	}
 }
 
+func (consoleREPL *ConsoleREPL) save(path string) {
+	file, err := os.Create(path)
+	if err != nil {
+		printError(fmt.Sprintf("Failed to create file %s: %s", path, err))
+		return
+	}
+	defer file.Close()
+
+	err = consoleREPL.repl.Save(file)
+	if err != nil {
+		printError(fmt.Sprintf("Failed to save session to %s: %s", path, err))
+	}
+}
+
+func (consoleREPL *ConsoleREPL) load(path string) {
+	code, err := os.ReadFile(path)
+	if err != nil {
+		printError(fmt.Sprintf("Failed to read file %s: %s", path, err))
+		return
+	}
+
+	consoleREPL.evaluate(func() {
+		err = consoleREPL.repl.Load(code)
+	})
+	if err != nil {
+		printError(fmt.Sprintf("Failed to load session from %s: %s", path, err))
+	}
+
+	consoleREPL.updateLineNumber()
+}
+
+func (consoleREPL *ConsoleREPL) reset() {
+	err := consoleREPL.repl.Reset()
+	if err != nil {
+		printError(fmt.Sprintf("Failed to reset session: %s", err))
+	}
+
+	consoleREPL.updateLineNumber()
+}
+
+func (consoleREPL *ConsoleREPL) undo() {
+	var err error
+	consoleREPL.evaluate(func() {
+		err = consoleREPL.repl.Undo()
+	})
+	if err != nil {
+		printError(fmt.Sprintf("Failed to undo: %s", err))
+	}
+
+	consoleREPL.updateLineNumber()
+}
+
+// updateLineNumber sets the line number to the line following the inputs of the session
+func (consoleREPL *ConsoleREPL) updateLineNumber() {
+	lineNumber := 1
+	for _, input := range consoleREPL.repl.Inputs() {
+		lineNumber += strings.Count(input, "\n")
+	}
+	consoleREPL.lineNumber = lineNumber
+}
+
+// accept lets the REPL accept and evaluate the given code
+func (consoleREPL *ConsoleREPL) accept(code string) (inputIsComplete bool, err error) {
+	consoleREPL.evaluate(func() {
+		inputIsComplete, err = consoleREPL.repl.Accept([]byte(code), true)
+	})
+	return
+}
+
+// evaluate runs the given function, which evaluates code.
+//
+// While the code is evaluated, stops of the debugger, e.g. at breakpoints,
+// or after an interrupt (Ctrl-C), are handled by the interactive debugger.
+// When the debugger is continued, the evaluation resumes
+func (consoleREPL *ConsoleREPL) evaluate(f func()) {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		f()
+	}()
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	debugger := consoleREPL.debugger
+
+	// Requests which were not fulfilled before the evaluation finished
+	// must not stop the next evaluation
+	defer debugger.CancelRequests()
+
+	for {
+		select {
+		case <-done:
+			return
+
+		case <-interrupts:
+			debugger.RequestPause()
+
+		case stop := <-debugger.Stops():
+			NewInteractiveDebugger(debugger, stop, done).Run()
+		}
+	}
+}
+
+// setBreakpoint sets a breakpoint at the first statement of a function declared in the session,
+// or at a line, optionally with a condition, e.g. `foo if x > 1`
+func (consoleREPL *ConsoleREPL) setBreakpoint(argument string) {
+	target, condition, hasCondition := strings.Cut(strings.TrimSpace(argument), " if ")
+	target = strings.TrimSpace(target)
+	if len(target) == 0 {
+		printError("Missing function name or line")
+		return
+	}
+
+	line, err := consoleREPL.breakpointLine(target)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+
+	breakpoint := &interpreter.Breakpoint{
+		Location: common.REPLLocation{},
+		Line:     line,
+	}
+
+	if hasCondition {
+		breakpoint.Condition, err = cmd.ParseBreakpointCondition(condition)
+		if err != nil {
+			printError(err.Error())
+			return
+		}
+	}
+
+	consoleREPL.debugger.SetBreakpoint(breakpoint)
+}
+
+func (consoleREPL *ConsoleREPL) deleteBreakpoint(argument string) {
+	target := strings.TrimSpace(argument)
+	if len(target) == 0 {
+		printError("Missing function name or line")
+		return
+	}
+
+	line, err := consoleREPL.breakpointLine(target)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+
+	consoleREPL.debugger.RemoveBreakpoint(common.REPLLocation{}, line)
+}
+
+// breakpointLine returns the line of the given breakpoint target,
+// which is either a line number or the name of a function
+func (consoleREPL *ConsoleREPL) breakpointLine(target string) (uint, error) {
+	line, err := strconv.ParseUint(target, 10, 32)
+	if err == nil {
+		return uint(line), nil
+	}
+
+	return consoleREPL.repl.FunctionLine(target)
+}
+
+func (consoleREPL *ConsoleREPL) listBreakpoints() {
+	for _, breakpoint := range consoleREPL.debugger.Breakpoints() {
+		if breakpoint.Condition != nil {
+			fmt.Printf("%d if %s\n", breakpoint.Line, breakpoint.Condition)
+		} else {
+			fmt.Printf("%d\n", breakpoint.Line)
+		}
+	}
+}
+
 func (consoleREPL *ConsoleREPL) execute(line string) {
 	if consoleREPL.code == "" && strings.HasPrefix(line, ".") {
 		consoleREPL.handleCommand(line)
@@ -147,7 +336,7 @@ func (consoleREPL *ConsoleREPL) execute(line string) {
 
 	consoleREPL.code += line + "\n"
 
-	inputIsComplete, err := consoleREPL.repl.Accept([]byte(consoleREPL.code), true)
+	inputIsComplete, err := consoleREPL.accept(consoleREPL.code)
 	if err == nil {
 		consoleREPL.lineNumber++
 
@@ -178,7 +367,7 @@ func (consoleREPL *ConsoleREPL) suggest(d prompt.Document) []prompt.Suggest {
 	if wordBeforeCursor[0] == commandPrefix {
 		commandLookupPrefix := wordBeforeCursor[1:]
 
-		for _, command := range commands {
+		for _, command := range consoleREPL.availableCommands() {
 			if !strings.HasPrefix(command.name, commandLookupPrefix) {
 				continue
 			}
@@ -340,7 +529,7 @@ Press ^C to abort current expression, ^D to exit
 func (consoleREPL *ConsoleREPL) printHelp() {
 	println(replHelpMessagePrefix)
 
-	for _, command := range commands {
+	for _, command := range consoleREPL.availableCommands() {
 		fmt.Printf(
 			"%c%s\t%s\n",
 			commandPrefix,
@@ -360,6 +549,18 @@ type command struct {
 
 var commands []command
 
+// availableCommands returns the commands of the REPL,
+// which include the account commands if the REPL was started with accounts
+func (consoleREPL *ConsoleREPL) availableCommands() []command {
+	if consoleREPL.emulator == nil {
+		return commands
+	}
+
+	result := make([]command, 0, len(commands)+len(accountCommands))
+	result = append(result, commands...)
+	return append(result, accountCommands...)
+}
+
 func init() {
 	commands = []command{
 		{
@@ -400,6 +601,65 @@ func init() {
 				consoleREPL.showType(argument)
 			},
 		},
+		{
+			name:        "save",
+			description: "Save session to file",
+			handler: func(consoleREPL *ConsoleREPL, argument string) {
+				path := strings.TrimSpace(argument)
+				if len(path) == 0 {
+					printError("Missing path")
+					return
+				}
+				consoleREPL.save(path)
+			},
+		},
+		{
+			name:        "load",
+			description: "Load session from file",
+			handler: func(consoleREPL *ConsoleREPL, argument string) {
+				path := strings.TrimSpace(argument)
+				if len(path) == 0 {
+					printError("Missing path")
+					return
+				}
+				consoleREPL.load(path)
+			},
+		},
+		{
+			name:        "reset",
+			description: "Discard all declarations and values",
+			handler: func(consoleREPL *ConsoleREPL, _ string) {
+				consoleREPL.reset()
+			},
+		},
+		{
+			name:        "undo",
+			description: "Undo the last input",
+			handler: func(consoleREPL *ConsoleREPL, _ string) {
+				consoleREPL.undo()
+			},
+		},
+		{
+			name:        "break",
+			description: "Set breakpoint on function or line, optionally with condition: .break name [if condition]",
+			handler: func(consoleREPL *ConsoleREPL, argument string) {
+				consoleREPL.setBreakpoint(argument)
+			},
+		},
+		{
+			name:        "delete",
+			description: "Delete breakpoint on function or line",
+			handler: func(consoleREPL *ConsoleREPL, argument string) {
+				consoleREPL.deleteBreakpoint(argument)
+			},
+		},
+		{
+			name:        "breakpoints",
+			description: "List breakpoints",
+			handler: func(consoleREPL *ConsoleREPL, _ string) {
+				consoleREPL.listBreakpoints()
+			},
+		},
 	}
 }
 
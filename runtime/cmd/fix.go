@@ -0,0 +1,67 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+// ApplyFixes applies the given suggested fixes to the given code of the file with the given path.
+// Fixes which overlap with previously applied fixes are skipped.
+//
+// If diff is true, the file is not written, instead the differences are written to the given writer.
+// It returns which of the fixes were applied
+func ApplyFixes(
+	writer io.Writer,
+	path string,
+	code []byte,
+	fixes []analysis.SuggestedFix,
+	diff bool,
+) ([]bool, error) {
+	fixed, applied, err := analysis.ApplyFixes(code, fixes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply fixes to %s: %w", path, err)
+	}
+
+	if bytes.Equal(code, fixed) {
+		return applied, nil
+	}
+
+	if diff {
+		_, err = io.WriteString(writer, UnifiedDiff(path, code, fixed))
+		return applied, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.WriteFile(path, fixed, info.Mode())
+	if err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
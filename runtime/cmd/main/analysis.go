@@ -0,0 +1,200 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/pretty"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+// contractDirectories maps addresses to directories which contain the code
+// of the contracts deployed to the address, one file per contract, named after the contract,
+// e.g. `FungibleToken.cdc`.
+//
+// It is a flag value, which is set using the syntax `address=directory`
+type contractDirectories map[common.Address]string
+
+var _ flag.Value = contractDirectories{}
+
+func (d contractDirectories) String() string {
+	pairs := make([]string, 0, len(d))
+	for address, directory := range d { //nolint:maprange
+		pairs = append(pairs, fmt.Sprintf("%s=%s", address.HexWithPrefix(), directory))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (d contractDirectories) Set(value string) error {
+	addressString, directory, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("invalid contract directory, expected address=directory: %s", value)
+	}
+
+	address, err := common.HexToAddress(addressString)
+	if err != nil {
+		return fmt.Errorf("invalid address: %s", addressString)
+	}
+
+	d[address] = directory
+	return nil
+}
+
+func (d contractDirectories) contractNames(address common.Address) ([]string, error) {
+	directory, ok := d[address]
+	if !ok {
+		return nil, fmt.Errorf("missing contract directory for address: %s", address.HexWithPrefix())
+	}
+
+	paths, err := filepath.Glob(filepath.Join(directory, "*.cdc"))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(paths))
+	for _, path := range paths {
+		names = append(names, strings.TrimSuffix(filepath.Base(path), ".cdc"))
+	}
+	return names, nil
+}
+
+func (d contractDirectories) contractPath(location common.AddressLocation) (string, error) {
+	directory, ok := d[location.Address]
+	if !ok {
+		return "", fmt.Errorf("missing contract directory for address: %s", location.Address.HexWithPrefix())
+	}
+
+	return filepath.Join(directory, location.Name+".cdc"), nil
+}
+
+// newAnalysisConfig returns a configuration for loading programs from files.
+//
+// Imports of string locations are resolved as paths of files.
+// Imports of address locations are resolved using the given contract directories.
+//
+// The codes of all loaded programs are added to the given codes.
+// All language features are enabled, so programs using any of them can be analyzed
+func newAnalysisConfig(
+	mode analysis.LoadMode,
+	directories contractDirectories,
+	codes map[common.Location][]byte,
+) *analysis.Config {
+	return &analysis.Config{
+		Mode:                         mode,
+		AccountLinkingEnabled:        true,
+		AttachmentsEnabled:           true,
+		CapabilityControllersEnabled: true,
+		ResolveAddressContractNames:  directories.contractNames,
+		ResolveCode: func(
+			location common.Location,
+			_ common.Location,
+			_ ast.Range,
+		) ([]byte, error) {
+			var path string
+			switch location := location.(type) {
+			case common.StringLocation:
+				path = string(location)
+
+			case common.AddressLocation:
+				var err error
+				path, err = directories.contractPath(location)
+				if err != nil {
+					return nil, err
+				}
+
+			default:
+				return nil, fmt.Errorf("cannot import `%s`. only files and addresses are supported", location)
+			}
+
+			code, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+
+			codes[location] = code
+			return code, nil
+		},
+	}
+}
+
+// loadPrograms loads the programs in the files with the given paths, and the programs they import.
+// It returns the loaded programs, and the locations of the files
+func loadPrograms(config *analysis.Config, paths []string) (analysis.Programs, []common.Location, error) {
+	locations := make([]common.Location, 0, len(paths))
+	for _, path := range paths {
+		locations = append(locations, common.StringLocation(path))
+	}
+
+	programs, err := analysis.Load(config, locations...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return programs, locations, nil
+}
+
+// findCadenceFiles returns the paths of the Cadence files in the given paths.
+// Directories are searched for Cadence files recursively
+func findCadenceFiles(paths []string) ([]string, error) {
+	var files []string
+
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if entry.IsDir() || filepath.Ext(path) != ".cdc" {
+				return nil
+			}
+
+			files = append(files, path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// printLoadError pretty prints the given error, which occurred when loading programs
+func printLoadError(err error, codes map[common.Location][]byte) {
+	var location common.Location
+	if locatedErr, ok := err.(common.HasLocation); ok {
+		location = locatedErr.ImportLocation()
+	}
+
+	printErr := pretty.NewErrorPrettyPrinter(os.Stderr, true).
+		PrettyPrintError(err, location, codes)
+	if printErr != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/cmd"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// convertCoverage converts the JSON coverage reports in the files with the given paths
+// to the LCOV or Cobertura format. Multiple reports are merged.
+//
+// Locations are mapped to file paths: string locations are used as paths,
+// and address locations are resolved using the given contract directories.
+// Other locations are named by their ID
+func convertCoverage(args []string) {
+	flags := flag.NewFlagSet("coverage", flag.ExitOnError)
+	formatFlag := flags.String("format", "lcov", "the format of the converted report: lcov or cobertura")
+	outputFlag := flags.String("output", "", "the file the converted report is written to, instead of stdout")
+	excludeFlag := flags.String("exclude", "", "a comma-separated list of IDs of locations to exclude, e.g. I.Test")
+	includeFlag := flags.String("include", "", "a regular expression, only locations with a matching ID are included")
+	directories := contractDirectories{}
+	flags.Var(directories, "contracts", "resolve contracts deployed to an address to files in a directory (address=directory), can be repeated")
+	_ = flags.Parse(args)
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		cmd.ExitWithError("missing paths of JSON coverage reports")
+	}
+
+	coverageReport := runtime.NewCoverageReport()
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			cmd.ExitWithError(err.Error())
+		}
+
+		report := runtime.NewCoverageReport()
+		err = json.Unmarshal(data, report)
+		if err != nil {
+			cmd.ExitWithError(fmt.Sprintf("invalid coverage report %s: %s", path, err))
+		}
+
+		coverageReport.Merge(*report)
+	}
+
+	if *excludeFlag != "" {
+		for _, locationID := range strings.Split(*excludeFlag, ",") {
+			location, _, err := common.DecodeTypeID(nil, strings.TrimSpace(locationID))
+			if err != nil || location == nil {
+				cmd.ExitWithError(fmt.Sprintf("invalid location ID: %s", locationID))
+			}
+			coverageReport.ExcludeLocation(location)
+		}
+	}
+
+	if *includeFlag != "" {
+		include, err := regexp.Compile(*includeFlag)
+		if err != nil {
+			cmd.ExitWithError(fmt.Sprintf("invalid include pattern: %s", err))
+		}
+		coverageReport.WithLocationFilter(func(location common.Location) bool {
+			return include.MatchString(location.ID())
+		})
+	}
+
+	coverageReport.WithLocationPathResolver(func(location common.Location) string {
+		switch location := location.(type) {
+		case common.StringLocation:
+			return string(location)
+
+		case common.AddressLocation:
+			path, err := directories.contractPath(location)
+			if err == nil {
+				return path
+			}
+		}
+
+		return location.ID()
+	})
+
+	var converted []byte
+	var err error
+
+	switch *formatFlag {
+	case "lcov":
+		converted, err = coverageReport.MarshalLCOV()
+	case "cobertura":
+		converted, err = coverageReport.MarshalCobertura()
+	default:
+		cmd.ExitWithError(fmt.Sprintf("unsupported format: %s", *formatFlag))
+	}
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+
+	if *outputFlag == "" {
+		_, err = os.Stdout.Write(converted)
+	} else {
+		err = os.WriteFile(*outputFlag, converted, 0644)
+	}
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+}
@@ -0,0 +1,97 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/onflow/cadence/runtime/cmd"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/tools/analysis"
+	"github.com/onflow/cadence/tools/docgen"
+)
+
+// document generates the documentation of the programs in the files with the given paths,
+// and writes the pages to the output directory.
+// The imports of the programs are loaded to resolve types
+func document(args []string) {
+	flags := flag.NewFlagSet("doc", flag.ExitOnError)
+	outputFlag := flags.String("output", "docs", "the directory the documentation is written to")
+	formatFlag := flags.String("format", "all", "the format of the documentation: markdown, html, or all")
+	directories := contractDirectories{}
+	flags.Var(directories, "contracts", "resolve imports of contracts deployed to an address from a directory (address=directory), can be repeated")
+	_ = flags.Parse(args)
+
+	var formats []docgen.Format
+	switch *formatFlag {
+	case "markdown":
+		formats = []docgen.Format{docgen.FormatMarkdown}
+	case "html":
+		formats = []docgen.Format{docgen.FormatHTML}
+	case "all":
+		formats = []docgen.Format{docgen.FormatMarkdown, docgen.FormatHTML}
+	default:
+		cmd.ExitWithError(fmt.Sprintf("unsupported format: %s", *formatFlag))
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		cmd.ExitWithError("missing paths of files to document")
+	}
+
+	codes := map[common.Location][]byte{}
+
+	config := newAnalysisConfig(analysis.NeedTypes, directories, codes)
+
+	programs, locations, err := loadPrograms(config, paths)
+	if err != nil {
+		printLoadError(err, codes)
+		os.Exit(1)
+	}
+
+	documentedPrograms := make([]*analysis.Program, 0, len(locations))
+	for _, location := range locations {
+		documentedPrograms = append(documentedPrograms, programs[location])
+	}
+
+	documentation := docgen.New(documentedPrograms...)
+
+	err = os.MkdirAll(*outputFlag, 0755)
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+
+	for _, format := range formats {
+		pages, err := documentation.Pages(format)
+		if err != nil {
+			cmd.ExitWithError(err.Error())
+		}
+
+		for _, page := range pages {
+			path := filepath.Join(*outputFlag, page.Name+format.FileExtension())
+			err := os.WriteFile(path, page.Content, 0644)
+			if err != nil {
+				cmd.ExitWithError(err.Error())
+			}
+		}
+	}
+}
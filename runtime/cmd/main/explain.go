@@ -0,0 +1,55 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/cmd"
+	"github.com/onflow/cadence/runtime/errors"
+
+	// register the explanations of the checking and runtime errors
+	_ "github.com/onflow/cadence/runtime/interpreter"
+	_ "github.com/onflow/cadence/runtime/sema"
+)
+
+// explain prints the explanation of the error with the given code.
+// Without a code, the codes and titles of all errors are listed
+func explain(args []string) {
+	if len(args) == 0 {
+		for _, explanation := range errors.ErrorExplanations() {
+			fmt.Println(explanation.Title())
+		}
+		return
+	}
+
+	if len(args) > 1 {
+		cmd.ExitWithError("expected a single error code")
+	}
+
+	code := errors.ErrorCode(strings.ToUpper(args[0]))
+
+	explanation, ok := errors.LookupErrorExplanation(code)
+	if !ok {
+		cmd.ExitWithError(fmt.Sprintf("unknown error code: %s", args[0]))
+	}
+
+	fmt.Print(explanation.Explanation)
+}
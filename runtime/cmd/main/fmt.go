@@ -0,0 +1,160 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/formatter"
+	"github.com/onflow/cadence/runtime/parser"
+	"github.com/onflow/cadence/runtime/pretty"
+)
+
+// format formats the Cadence files with the given paths in place.
+// Directories are searched for Cadence files recursively.
+// Without paths, the standard input is formatted to the standard output.
+//
+// In check mode, files are not written, instead the paths of files which are not formatted are printed,
+// and the command fails if any file is not formatted
+func format(args []string) {
+	flags := flag.NewFlagSet("fmt", flag.ExitOnError)
+	checkFlag := flags.Bool("check", false, "only check if files are formatted, and list the files which are not")
+	_ = flags.Parse(args)
+
+	config := formatter.Config{}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		err := formatStandardInput(config)
+		if err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	var failed bool
+
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if entry.IsDir() || filepath.Ext(path) != ".cdc" {
+				return nil
+			}
+
+			formatted, err := formatFile(path, *checkFlag, config)
+			if err != nil {
+				printFormatError(path, err)
+				failed = true
+				return nil
+			}
+
+			if *checkFlag && !formatted {
+				fmt.Println(path)
+				failed = true
+			}
+
+			return nil
+		})
+		if err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// formatFile formats the file with the given path.
+// It returns true if the file was already formatted.
+// The file is only written if it is not formatted, and not in check mode
+func formatFile(path string, check bool, config formatter.Config) (bool, error) {
+	code, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	formatted, err := formatter.Format(code, config)
+	if err != nil {
+		return false, err
+	}
+
+	if bytes.Equal(code, formatted) {
+		return true, nil
+	}
+
+	if check {
+		return false, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+
+	return false, os.WriteFile(path, formatted, info.Mode())
+}
+
+// printFormatError prints the error which occurred when formatting the file with the given path.
+// Syntax errors are pretty printed
+func printFormatError(path string, err error) {
+	var parserError parser.Error
+	if errors.As(err, &parserError) {
+		location := common.StringLocation(path)
+		codes := map[common.Location][]byte{
+			location: parserError.Code,
+		}
+
+		printErr := pretty.NewErrorPrettyPrinter(os.Stderr, true).
+			PrettyPrintError(parserError, location, codes)
+		if printErr == nil {
+			return
+		}
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "%s: %s\n", path, err)
+}
+
+func formatStandardInput(config formatter.Config) error {
+	code, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	formatted, err := formatter.Format(code, config)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(formatted)
+	return err
+}
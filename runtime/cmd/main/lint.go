@@ -0,0 +1,287 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/cmd"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/tools/analysis"
+	"github.com/onflow/cadence/tools/lint"
+)
+
+// lintFiles runs the lint rules on the Cadence files with the given paths,
+// and prints the reported diagnostics.
+// Directories are searched for Cadence files recursively.
+// The command fails if any diagnostics are reported.
+//
+// In fix mode, the first suggested fix of each diagnostic is applied to the files,
+// and the diagnostics which remain are reported.
+// In diff mode, the files are not written, instead the suggested fixes are printed as a diff,
+// followed by all diagnostics
+func lintFiles(args []string) {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	configFlag := flags.String("config", "", "the path of a JSON configuration file, which enables or disables rules, e.g. {\"rules\": {\"shadowing\": false}}")
+	enableFlag := flags.String("enable", "", "a comma-separated list of rules to enable")
+	disableFlag := flags.String("disable", "", "a comma-separated list of rules to disable")
+	rulesFlag := flags.Bool("rules", false, "list the available rules")
+	securityFlag := flags.Bool("security", false, "only run the rules which report potential vulnerabilities, unless enabled otherwise")
+	fixFlag := flags.Bool("fix", false, "apply the suggested fixes of the diagnostics to the files")
+	diffFlag := flags.Bool("diff", false, "print the suggested fixes of the diagnostics as a diff, without applying them")
+	formatFlag := flags.String("format", "text", cmd.ReportFormatUsage)
+	directories := contractDirectories{}
+	flags.Var(directories, "contracts", "resolve imports of contracts deployed to an address from a directory (address=directory), can be repeated")
+	_ = flags.Parse(args)
+
+	if *rulesFlag {
+		for _, name := range lint.Rules() {
+			analyzer, _ := lint.Analyzer(name)
+			fmt.Printf("%s: %s\n", name, analyzer.Description)
+		}
+		return
+	}
+
+	reportFormat, err := cmd.ParseReportFormat(*formatFlag)
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+
+	if reportFormat != nil && *diffFlag {
+		cmd.ExitWithError("cannot combine the --diff flag with the --format flag")
+	}
+
+	config, err := lintConfig(*configFlag, *enableFlag, *disableFlag, *securityFlag)
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+
+	// Validate the configuration before loading programs
+	_, err = config.Analyzers()
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+
+	paths, err := findCadenceFiles(flags.Args())
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+	if len(paths) == 0 {
+		cmd.ExitWithError("missing paths of files to lint")
+	}
+
+	diagnostics, codes := lintPaths(paths, config, directories, reportFormat)
+
+	if *fixFlag || *diffFlag {
+		fixed, err := fixDiagnostics(diagnostics, codes, *diffFlag)
+		if err != nil {
+			cmd.ExitWithError(err.Error())
+		}
+
+		if fixed {
+			// Files were fixed, lint them again to report the remaining diagnostics
+			diagnostics, _ = lintPaths(paths, config, directories, reportFormat)
+		}
+	}
+
+	if reportFormat != nil {
+		err = analysis.WriteReport(os.Stdout, *reportFormat, "cadence-lint", diagnostics)
+		if err != nil {
+			cmd.ExitWithError(err.Error())
+		}
+	} else {
+		printDiagnostics(diagnostics)
+	}
+
+	if len(diagnostics) > 0 {
+		os.Exit(1)
+	}
+}
+
+func printDiagnostics(diagnostics []analysis.Diagnostic) {
+	for _, diagnostic := range diagnostics {
+		fmt.Printf(
+			"%s:%d:%d: %s (%s)\n",
+			diagnostic.Location,
+			diagnostic.StartPos.Line,
+			diagnostic.StartPos.Column,
+			diagnostic.Message,
+			diagnostic.Category,
+		)
+	}
+}
+
+// lintPaths loads the programs in the files with the given paths and runs the lint rules on them.
+// It returns the reported diagnostics, and the codes of the loaded programs.
+//
+// If the programs fail to load, the error is reported in the given format, if any,
+// and the command fails
+func lintPaths(
+	paths []string,
+	config lint.Config,
+	directories contractDirectories,
+	reportFormat *analysis.ReportFormat,
+) (
+	[]analysis.Diagnostic,
+	map[common.Location][]byte,
+) {
+	codes := map[common.Location][]byte{}
+
+	analysisConfig := newAnalysisConfig(lint.LoadMode, directories, codes)
+	analysisConfig.HandleCheckerError = lint.HandleCheckerError
+
+	programs, locations, err := loadPrograms(analysisConfig, paths)
+	if err != nil {
+		if reportFormat != nil {
+			reportErr := analysis.WriteReport(
+				os.Stdout,
+				*reportFormat,
+				"cadence-lint",
+				analysis.ErrorDiagnostics(err, nil, codes),
+			)
+			if reportErr != nil {
+				cmd.ExitWithError(reportErr.Error())
+			}
+		} else {
+			printLoadError(err, codes)
+		}
+		os.Exit(1)
+	}
+
+	var diagnostics []analysis.Diagnostic
+
+	for _, location := range locations {
+		programDiagnostics, err := lint.Lint(programs[location], config)
+		if err != nil {
+			cmd.ExitWithError(err.Error())
+		}
+		diagnostics = append(diagnostics, programDiagnostics...)
+	}
+
+	return diagnostics, codes
+}
+
+// fixDiagnostics applies the first suggested fix of each of the given diagnostics, if any,
+// to the files which contain the diagnostics.
+// It returns true if any fix was applied to a file.
+//
+// If diff is true, the files are not written, instead the differences are printed
+func fixDiagnostics(
+	diagnostics []analysis.Diagnostic,
+	codes map[common.Location][]byte,
+	diff bool,
+) (bool, error) {
+	var locations []common.Location
+	fixes := map[common.Location][]analysis.SuggestedFix{}
+
+	for _, diagnostic := range diagnostics {
+		if len(diagnostic.SuggestedFixes) == 0 {
+			continue
+		}
+
+		location := diagnostic.Location
+		if _, ok := fixes[location]; !ok {
+			locations = append(locations, location)
+		}
+		fixes[location] = append(fixes[location], diagnostic.SuggestedFixes[0])
+	}
+
+	var fixed bool
+
+	for _, location := range locations {
+		stringLocation, ok := location.(common.StringLocation)
+		if !ok {
+			continue
+		}
+
+		applied, err := cmd.ApplyFixes(
+			os.Stdout,
+			string(stringLocation),
+			codes[location],
+			fixes[location],
+			diff,
+		)
+		if err != nil {
+			return false, err
+		}
+
+		for _, fixApplied := range applied {
+			if fixApplied && !diff {
+				fixed = true
+			}
+		}
+	}
+
+	return fixed, nil
+}
+
+// lintConfig returns the lint configuration in the file with the given path, if any,
+// with the given comma-separated lists of rules enabled and disabled.
+// If security is true, rules which are not security rules are disabled, unless enabled explicitly
+func lintConfig(path string, enable string, disable string, security bool) (lint.Config, error) {
+	config := lint.Config{
+		Rules: map[string]bool{},
+	}
+
+	if security {
+		for _, rule := range lint.Rules() {
+			if !lint.IsSecurityRule(rule) {
+				config.Rules[rule] = false
+			}
+		}
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return config, err
+		}
+
+		err = json.Unmarshal(data, &config)
+		if err != nil {
+			return config, fmt.Errorf("invalid lint configuration %s: %w", path, err)
+		}
+	}
+
+	if config.Rules == nil {
+		config.Rules = map[string]bool{}
+	}
+
+	for _, rules := range []struct {
+		list    string
+		enabled bool
+	}{
+		{enable, true},
+		{disable, false},
+	} {
+		for _, rule := range strings.Split(rules.list, ",") {
+			rule = strings.TrimSpace(rule)
+			if rule == "" {
+				continue
+			}
+			config.Rules[rule] = rules.enabled
+		}
+	}
+
+	return config, nil
+}
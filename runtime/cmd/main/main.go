@@ -19,37 +19,129 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"net"
 	"os"
 	"os/signal"
 
+	"github.com/onflow/cadence/runtime/cmd"
+	"github.com/onflow/cadence/runtime/cmd/dap"
 	"github.com/onflow/cadence/runtime/cmd/execute"
 	"github.com/onflow/cadence/runtime/interpreter"
 )
 
 func main() {
-	if len(os.Args) > 1 {
-		// TODO: also make the REPL support the interactive debugger
+	if len(os.Args) < 2 {
+		repl(nil)
+		return
+	}
 
-		signals := make(chan os.Signal, 1)
+	args := os.Args[2:]
 
-		signal.Notify(signals, os.Interrupt)
+	switch os.Args[1] {
+	case "coverage":
+		convertCoverage(args)
+	case "debug":
+		debug(args)
+	case "doc":
+		document(args)
+	case "explain":
+		explain(args)
+	case "fmt":
+		format(args)
+	case "lint":
+		lintFiles(args)
+	case "repl":
+		repl(args)
+	case "test":
+		runTests(args)
+	default:
+		execute.Execute(os.Args[1:], newDebugger())
+	}
+}
 
-		debugger := interpreter.NewDebugger()
+// newDebugger returns a debugger which prints the messages of logpoints,
+// and which starts the interactive debugger when the program is interrupted
+func newDebugger() *interpreter.Debugger {
+	signals := make(chan os.Signal, 1)
 
-		go func() {
-			for range signals {
-				stop := debugger.Pause()
-				execute.NewInteractiveDebugger(debugger, stop).Run()
-				debugger.Continue()
-			}
-		}()
+	signal.Notify(signals, os.Interrupt)
 
-		execute.Execute(os.Args[1:], debugger)
-	} else {
-		repl, err := execute.NewConsoleREPL()
-		if err != nil {
-			panic(err)
+	debugger := interpreter.NewDebugger()
+	debugger.OnLogpoint = func(_ *interpreter.Breakpoint, message string) {
+		fmt.Println(message)
+	}
+
+	go func() {
+		for range signals {
+			stop := debugger.Pause()
+			// The interactive debugger continues the program when it exits
+			execute.NewInteractiveDebugger(debugger, stop, nil).Run()
 		}
-		repl.Run()
+	}()
+
+	return debugger
+}
+
+// repl runs the REPL.
+// With accounts, the REPL additionally lets users create accounts, deploy contracts,
+// and execute transactions and scripts against emulated accounts and storage
+func repl(args []string) {
+	flags := flag.NewFlagSet("repl", flag.ExitOnError)
+	accountsFlag := flags.Bool("accounts", false, "emulate accounts, contracts and storage")
+	_ = flags.Parse(args)
+
+	var consoleREPL *execute.ConsoleREPL
+	var err error
+	if *accountsFlag {
+		consoleREPL, err = execute.NewConsoleREPLWithAccounts()
+	} else {
+		consoleREPL, err = execute.NewConsoleREPL()
 	}
+	if err != nil {
+		panic(err)
+	}
+
+	consoleREPL.Run()
+}
+
+// debug runs a debug adapter, which lets editors debug programs
+// using the Debug Adapter Protocol.
+// The adapter communicates over stdio, or over TCP if a listen address is given
+func debug(args []string) {
+	flags := flag.NewFlagSet("debug", flag.ExitOnError)
+	dapFlag := flags.Bool("dap", false, "speak the Debug Adapter Protocol")
+	listenFlag := flags.String("listen", "", "accept a debug adapter client on the given TCP address, instead of using stdio")
+	_ = flags.Parse(args)
+
+	if !*dapFlag {
+		cmd.ExitWithError("only the Debug Adapter Protocol is supported, use `debug --dap`")
+	}
+
+	var err error
+	if *listenFlag == "" {
+		err = dap.NewServer(os.Stdin, os.Stdout).Run()
+	} else {
+		err = serveDAP(*listenFlag)
+	}
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+}
+
+func serveDAP(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	conn, err := listener.Accept()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return dap.NewServer(conn, conn).Run()
 }
@@ -0,0 +1,283 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/cmd"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/pretty"
+	"github.com/onflow/cadence/runtime/testrunner"
+)
+
+// testFileSuffix is the suffix of the names of test files
+const testFileSuffix = "_test.cdc"
+
+// runTests runs the tests of the Cadence test files with the given paths,
+// and reports the results.
+// Directories are searched for test files, i.e. files ending in `_test.cdc`, recursively.
+// The command fails if any test fails, or if any test file cannot be checked
+func runTests(args []string) {
+	flags := flag.NewFlagSet("test", flag.ExitOnError)
+	runFlag := flags.String("run", "", "a regular expression, only tests with a matching name are run")
+	formatFlag := flags.String("format", "text", "the format of the results: text, junit, or json")
+	outputFlag := flags.String("output", "", "the file the results are written to, instead of stdout")
+	verboseFlag := flags.Bool("v", false, "print the logs of all tests, not just of failed tests")
+	coverageFlag := flags.String("coverage", "", "the file the coverage report is written to")
+	coverageFormatFlag := flags.String("coverage-format", "json", "the format of the coverage report: json, lcov, or cobertura")
+	_ = flags.Parse(args)
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	switch *formatFlag {
+	case "text", "junit", "json":
+		break
+	default:
+		cmd.ExitWithError(fmt.Sprintf("unsupported format: %s", *formatFlag))
+	}
+
+	switch *coverageFormatFlag {
+	case "json", "lcov", "cobertura":
+		break
+	default:
+		cmd.ExitWithError(fmt.Sprintf("unsupported coverage format: %s", *coverageFormatFlag))
+	}
+
+	runner := testrunner.NewTestRunner()
+
+	if *runFlag != "" {
+		filter, err := regexp.Compile(*runFlag)
+		if err != nil {
+			cmd.ExitWithError(fmt.Sprintf("invalid run pattern: %s", err))
+		}
+		runner.TestFilter = filter
+	}
+
+	var coverageReport *runtime.CoverageReport
+	if *coverageFlag != "" {
+		coverageReport = runtime.NewCoverageReport()
+		// Only cover the imported files and the deployed contracts,
+		// not the scripts and transactions executed by the tests
+		coverageReport.WithLocationFilter(func(location common.Location) bool {
+			switch location.(type) {
+			case common.StringLocation, common.AddressLocation:
+				return true
+			default:
+				return false
+			}
+		})
+		runner.CoverageReport = coverageReport
+	}
+
+	files, err := findTestFiles(paths)
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+
+	report := testrunner.NewReport()
+
+	for _, file := range files {
+		location := common.StringLocation(file)
+
+		code, err := os.ReadFile(file)
+		if err != nil {
+			cmd.ExitWithError(err.Error())
+		}
+
+		results, err := runner.RunTests(location, code)
+		report.Add(location, results, err)
+	}
+
+	switch *formatFlag {
+	case "text":
+		// Errors are only colored when they are written to a terminal
+		colored := *outputFlag == "" && isTerminal(os.Stdout)
+
+		var output bytes.Buffer
+		printTestReport(&output, report, *verboseFlag, colored)
+		if coverageReport != nil {
+			fmt.Fprintln(&output, coverageReport)
+		}
+		writeOutput(*outputFlag, output.Bytes())
+
+	case "junit":
+		data, err := report.MarshalJUnit()
+		if err != nil {
+			cmd.ExitWithError(err.Error())
+		}
+		writeOutput(*outputFlag, data)
+
+	case "json":
+		data, err := json.Marshal(report)
+		if err != nil {
+			cmd.ExitWithError(err.Error())
+		}
+		writeOutput(*outputFlag, data)
+	}
+
+	if coverageReport != nil {
+		writeCoverageReport(coverageReport, *coverageFlag, *coverageFormatFlag)
+	}
+
+	if !report.Passed() {
+		os.Exit(1)
+	}
+}
+
+// findTestFiles returns the test files in the given paths.
+// Files are used as given, directories are searched for test files recursively
+func findTestFiles(paths []string) ([]string, error) {
+	var testFiles []string
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			testFiles = append(testFiles, path)
+			continue
+		}
+
+		files, err := findCadenceFiles([]string{path})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			if strings.HasSuffix(file, testFileSuffix) {
+				testFiles = append(testFiles, file)
+			}
+		}
+	}
+
+	return testFiles, nil
+}
+
+// printTestReport writes the results of the tests in a human-readable format to the given writer.
+// Errors are pretty printed, including code excerpts and stack traces
+func printTestReport(writer pretty.Writer, report *testrunner.Report, verbose bool, colored bool) {
+	for _, suite := range report.Suites {
+		if suite.Error != nil {
+			printTestError(writer, suite.Error, colored)
+			fmt.Fprintf(writer, "FAIL\t%s\t[check failed]\n", suite.Location)
+			continue
+		}
+
+		var duration float64
+
+		for _, result := range suite.Results {
+			duration += result.Duration.Seconds()
+
+			status := "PASS"
+			if !result.Passed() {
+				status = "FAIL"
+			}
+
+			if result.Passed() && !verbose {
+				continue
+			}
+
+			fmt.Fprintf(writer, "--- %s: %s (%.2fs)\n", status, result.TestName, result.Duration.Seconds())
+			for _, message := range result.Logs {
+				fmt.Fprintf(writer, "    %s\n", message)
+			}
+			if result.Error != nil {
+				printTestError(writer, result.Error, colored)
+			}
+		}
+
+		switch {
+		case len(suite.Results) == 0:
+			fmt.Fprintf(writer, "ok  \t%s\t[no tests to run]\n", suite.Location)
+		case suite.Results.Failed() > 0:
+			fmt.Fprintf(writer, "FAIL\t%s\t%.3fs\n", suite.Location, duration)
+		default:
+			fmt.Fprintf(writer, "ok  \t%s\t%.3fs\n", suite.Location, duration)
+		}
+	}
+}
+
+func printTestError(writer pretty.Writer, err error, colored bool) {
+	runtimeErr, ok := err.(runtime.Error)
+	if !ok {
+		fmt.Fprintln(writer, err)
+		return
+	}
+
+	printErr := pretty.NewErrorPrettyPrinter(writer, colored).
+		PrettyPrintError(runtimeErr.Err, runtimeErr.Location, runtimeErr.Codes)
+	if printErr != nil {
+		fmt.Fprintln(writer, err)
+	}
+}
+
+// isTerminal returns true if the given file is a terminal
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func writeOutput(path string, data []byte) {
+	var err error
+	if path == "" {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+}
+
+// writeCoverageReport writes the given coverage report to the file with the given path,
+// in the given format
+func writeCoverageReport(coverageReport *runtime.CoverageReport, path string, format string) {
+	var data []byte
+	var err error
+
+	switch format {
+	case "json":
+		data, err = json.Marshal(coverageReport)
+	case "lcov":
+		data, err = coverageReport.MarshalLCOV()
+	case "cobertura":
+		data, err = coverageReport.MarshalCobertura()
+	}
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+
+	writeOutput(path, data)
+}
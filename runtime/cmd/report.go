@@ -0,0 +1,49 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+// ReportFormatUsage describes the formats supported by ParseReportFormat
+const ReportFormatUsage = "the format of the diagnostics: text, json, sarif, or github"
+
+// ParseReportFormat returns the machine-readable format of diagnostics with the given name,
+// or nil for the human-readable text format
+func ParseReportFormat(name string) (*analysis.ReportFormat, error) {
+	var format analysis.ReportFormat
+
+	switch name {
+	case "", "text":
+		return nil, nil
+	case "json":
+		format = analysis.ReportFormatJSON
+	case "sarif":
+		format = analysis.ReportFormatSARIF
+	case "github":
+		format = analysis.ReportFormatGitHub
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", name)
+	}
+
+	return &format, nil
+}
@@ -0,0 +1,55 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+func TestParseReportFormat(t *testing.T) {
+
+	t.Parallel()
+
+	for name, expected := range map[string]*analysis.ReportFormat{ //nolint:maprange
+		"":     nil,
+		"text": nil,
+	} {
+		format, err := ParseReportFormat(name)
+		require.NoError(t, err)
+		require.Equal(t, expected, format)
+	}
+
+	for name, expected := range map[string]analysis.ReportFormat{ //nolint:maprange
+		"json":   analysis.ReportFormatJSON,
+		"sarif":  analysis.ReportFormatSARIF,
+		"github": analysis.ReportFormatGitHub,
+	} {
+		format, err := ParseReportFormat(name)
+		require.NoError(t, err)
+		require.NotNil(t, format)
+		require.Equal(t, expected, *format)
+	}
+
+	_, err := ParseReportFormat("xml")
+	require.EqualError(t, err, "unsupported format: xml")
+}
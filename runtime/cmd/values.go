@@ -0,0 +1,266 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// ValueChild is a named child of a value, as shown when exploring a value
+type ValueChild struct {
+	Name  string
+	Value interpreter.Value
+}
+
+// maxLinkDepth is the maximum number of links followed when exploring a capability
+const maxLinkDepth = 16
+
+// ValueChildren returns the children of the given value which can be explored:
+//
+//   - The fields of composites, sorted by name
+//   - The elements of arrays and dictionaries
+//   - The children of the referenced value of references,
+//     including values borrowed from account storage
+//   - The address, path, and target value of path capabilities
+//   - The address and stored values of accounts
+//
+// Optionals are unwrapped. Functions are not included.
+func ValueChildren(inter *interpreter.Interpreter, value interpreter.Value) []ValueChild {
+	var children []ValueChild
+
+	add := func(name string, value interpreter.Value) {
+		switch value.(type) {
+		case nil, interpreter.FunctionValue:
+			// Computed fields which were not computed yet, and functions
+			return
+		}
+		children = append(children, ValueChild{
+			Name:  name,
+			Value: value,
+		})
+	}
+
+	switch value := unwrapOptional(inter, value).(type) {
+	case *interpreter.CompositeValue:
+		value.ForEachField(inter, func(name string, fieldValue interpreter.Value) (resume bool) {
+			add(name, fieldValue)
+			return true
+		})
+		sort.Slice(children, func(i, j int) bool {
+			return children[i].Name < children[j].Name
+		})
+
+	case *interpreter.SimpleCompositeValue:
+		value.ForEachField(func(name string, fieldValue interpreter.Value) (resume bool) {
+			add(name, fieldValue)
+			return true
+		})
+
+		address, ok := value.Fields[sema.AuthAccountTypeAddressFieldName].(interpreter.AddressValue)
+		if !ok {
+			break
+		}
+
+		switch value.TypeID {
+		case sema.AuthAccountType.ID():
+			children = append(children, StoredValues(inter, address.ToAddress())...)
+
+		case sema.PublicAccountType.ID():
+			children = append(
+				children,
+				StoredValues(inter, address.ToAddress(), common.PathDomainPublic)...,
+			)
+		}
+
+	case *interpreter.ArrayValue:
+		index := 0
+		value.Iterate(inter, func(element interpreter.Value) (resume bool) {
+			add(fmt.Sprintf("[%d]", index), element)
+			index++
+			return true
+		})
+
+	case *interpreter.DictionaryValue:
+		value.Iterate(inter, func(key, element interpreter.Value) (resume bool) {
+			add(key.String(), element)
+			return true
+		})
+
+	case *interpreter.EphemeralReferenceValue,
+		*interpreter.StorageReferenceValue:
+
+		referencedValue := referencedValue(inter, value)
+		if referencedValue != nil {
+			return ValueChildren(inter, referencedValue)
+		}
+
+	case *interpreter.PathCapabilityValue:
+		add("address", value.Address)
+		add("path", value.Path)
+
+		target := capabilityTarget(inter, value)
+		if target != nil {
+			add("target", target)
+		}
+	}
+
+	return children
+}
+
+// ValueType returns the qualified name of the type of the given value,
+// or the empty string if the value has no type
+func ValueType(inter *interpreter.Interpreter, value interpreter.Value) string {
+	staticType := value.StaticType(inter)
+	if staticType == nil {
+		return ""
+	}
+
+	semaType, err := inter.ConvertStaticToSemaType(staticType)
+	if err != nil {
+		return ""
+	}
+
+	return semaType.QualifiedString()
+}
+
+// HasValueChildren returns true if the given value has children,
+// without determining them, see ValueChildren
+func HasValueChildren(inter *interpreter.Interpreter, value interpreter.Value) bool {
+	switch value := unwrapOptional(inter, value).(type) {
+	case *interpreter.CompositeValue:
+		return true
+
+	case *interpreter.SimpleCompositeValue:
+		return len(ValueChildren(inter, value)) > 0
+
+	case *interpreter.ArrayValue:
+		return value.Count() > 0
+
+	case *interpreter.DictionaryValue:
+		return value.Count() > 0
+
+	case *interpreter.EphemeralReferenceValue,
+		*interpreter.StorageReferenceValue:
+
+		referencedValue := referencedValue(inter, value)
+		return referencedValue != nil &&
+			HasValueChildren(inter, referencedValue)
+
+	case *interpreter.PathCapabilityValue:
+		return true
+	}
+
+	return false
+}
+
+// StoredValues returns the values stored in the given account,
+// in the given path domains, or all path domains if none are given.
+//
+// The children are named by their paths, and sorted by them.
+func StoredValues(
+	inter *interpreter.Interpreter,
+	address common.Address,
+	domains ...common.PathDomain,
+) []ValueChild {
+
+	if len(domains) == 0 {
+		domains = common.AllPathDomains
+	}
+
+	var children []ValueChild
+
+	for _, domain := range domains {
+		storageMap := inter.Storage().GetStorageMap(address, domain.Identifier(), false)
+		if storageMap == nil {
+			continue
+		}
+
+		var domainChildren []ValueChild
+
+		iterator := storageMap.Iterator(inter)
+		for key, value := iterator.Next(); key != nil; key, value = iterator.Next() {
+			identifier := string(key.(interpreter.StringAtreeValue))
+			path := interpreter.NewUnmeteredPathValue(domain, identifier)
+			domainChildren = append(domainChildren, ValueChild{
+				Name:  path.String(),
+				Value: value,
+			})
+		}
+
+		sort.Slice(domainChildren, func(i, j int) bool {
+			return domainChildren[i].Name < domainChildren[j].Name
+		})
+
+		children = append(children, domainChildren...)
+	}
+
+	return children
+}
+
+func referencedValue(inter *interpreter.Interpreter, value interpreter.Value) interpreter.Value {
+	reference, ok := value.(interpreter.ReferenceValue)
+	if !ok {
+		return nil
+	}
+
+	referencedValue := reference.ReferencedValue(inter, interpreter.EmptyLocationRange, false)
+	if referencedValue == nil {
+		return nil
+	}
+	return *referencedValue
+}
+
+// capabilityTarget returns the value targeted by the given capability,
+// following links, or nil if there is no such value
+func capabilityTarget(inter *interpreter.Interpreter, capability *interpreter.PathCapabilityValue) interpreter.Value {
+	address := capability.Address.ToAddress()
+	path := capability.Path
+
+	for i := 0; i < maxLinkDepth; i++ {
+		value := inter.ReadStored(
+			address,
+			path.Domain.Identifier(),
+			interpreter.StringStorageMapKey(path.Identifier),
+		)
+
+		link, ok := value.(interpreter.PathLinkValue)
+		if !ok {
+			return value
+		}
+
+		path = link.TargetPath
+	}
+
+	return nil
+}
+
+func unwrapOptional(inter *interpreter.Interpreter, value interpreter.Value) interpreter.Value {
+	for {
+		someValue, ok := value.(*interpreter.SomeValue)
+		if !ok {
+			return value
+		}
+		value = someValue.InnerValue(inter, interpreter.EmptyLocationRange)
+	}
+}
@@ -0,0 +1,119 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+func TestValueChildren(t *testing.T) {
+
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "test.cdc")
+	err := os.WriteFile(
+		path,
+		[]byte(`
+          pub struct Point {
+              pub let y: Int
+              pub let x: Int
+
+              init(x: Int, y: Int) {
+                  self.x = x
+                  self.y = y
+              }
+
+              pub fun sum(): Int {
+                  return self.x + self.y
+              }
+          }
+
+          pub let point: Point? = Point(x: 1, y: 2)
+          pub let numbers = {"a": [3, 4]}
+          pub let empty: [Int] = []
+          pub let ref = &numbers as &{String: [Int]}
+        `),
+		0600,
+	)
+	require.NoError(t, err)
+
+	inter, _, _ := PrepareInterpreter(path, nil)
+
+	global := func(name string) interpreter.Value {
+		return inter.Globals.Get(name).GetValue()
+	}
+
+	type child struct {
+		name  string
+		value string
+	}
+
+	children := func(value interpreter.Value) []child {
+		var result []child
+		for _, valueChild := range ValueChildren(inter, value) {
+			result = append(result, child{
+				name:  valueChild.Name,
+				value: valueChild.Value.String(),
+			})
+		}
+		return result
+	}
+
+	// Optionals are unwrapped, fields are sorted, and functions are omitted
+
+	point := global("point")
+	assert.True(t, HasValueChildren(inter, point))
+	assert.Equal(t,
+		[]child{
+			{name: "x", value: "1"},
+			{name: "y", value: "2"},
+		},
+		children(point),
+	)
+	assert.Equal(t, "Point?", ValueType(inter, point))
+
+	// Dictionaries are expanded to their entries
+
+	numbers := global("numbers")
+	assert.Equal(t,
+		[]child{
+			{name: `"a"`, value: "[3, 4]"},
+		},
+		children(numbers),
+	)
+
+	// References are expanded to the children of the referenced value
+
+	assert.Equal(t, children(numbers), children(global("ref")))
+
+	// Empty containers and simple values have no children
+
+	assert.False(t, HasValueChildren(inter, global("empty")))
+	assert.Empty(t, children(global("empty")))
+
+	assert.False(t, HasValueChildren(inter, interpreter.NewUnmeteredIntValueFromInt64(1)))
+	assert.Empty(t, children(interpreter.NewUnmeteredIntValueFromInt64(1)))
+}
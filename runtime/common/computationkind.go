@@ -32,7 +32,8 @@ const (
 	ComputationKindStatement ComputationKind = ComputationKindRangeStart + iota
 	ComputationKindLoop
 	ComputationKindFunctionInvocation
-	_
+	// compiled code
+	ComputationKindWasmInstruction
 	_
 	_
 	_
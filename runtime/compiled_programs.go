@@ -0,0 +1,115 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/onflow/cadence/runtime/compiler"
+	"github.com/onflow/cadence/runtime/compiler/bytecode"
+	"github.com/onflow/cadence/runtime/compiler/ir"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// compiledProgramsCacheSize is the maximum number of compiled programs which are kept
+const compiledProgramsCacheSize = 128
+
+// compiledPrograms caches the results of compiling programs to bytecode.
+//
+// Programs are keyed by identity: the host environment returns the same program
+// for the same code (see Interface.GetOrLoadProgram), so repeated executions
+// of a script only compile it once.
+// The cache holds at most compiledProgramsCacheSize programs, evicting the least recently used ones.
+// Programs stay alive until they are evicted, even if the host environment already released them
+type compiledPrograms struct {
+	entries map[*interpreter.Program]*list.Element
+	order   *list.List
+	lock    sync.Mutex
+}
+
+type compiledProgram struct {
+	program *interpreter.Program
+	// compiled is nil if the program uses features which are not supported by the compiler yet
+	compiled *bytecode.Program
+}
+
+func newCompiledPrograms() *compiledPrograms {
+	return &compiledPrograms{
+		entries: map[*interpreter.Program]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+// get returns the compiled program for the given program, compiling it if needed.
+// It returns false if the program uses features which are not supported by the compiler yet
+func (c *compiledPrograms) get(program *interpreter.Program) (*bytecode.Program, bool) {
+	c.lock.Lock()
+	element, ok := c.entries[program]
+	if ok {
+		c.order.MoveToFront(element)
+	}
+	c.lock.Unlock()
+
+	if ok {
+		compiled := element.Value.(*compiledProgram).compiled
+		return compiled, compiled != nil
+	}
+
+	// Compile outside the lock, concurrent compilations of the same program are harmless
+
+	compiled, _ := compileProgram(program)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.entries[program]; !ok {
+		c.entries[program] = c.order.PushFront(&compiledProgram{
+			program:  program,
+			compiled: compiled,
+		})
+
+		if c.order.Len() > compiledProgramsCacheSize {
+			oldest := c.order.Back()
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*compiledProgram).program)
+		}
+	}
+
+	return compiled, compiled != nil
+}
+
+// compileProgram compiles all functions of the given program to bytecode.
+// It returns false if the program uses features which are not supported by the compiler yet
+func compileProgram(program *interpreter.Program) (_ *bytecode.Program, ok bool) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			if _, isUnsupported := recovered.(compiler.UnsupportedError); !isUnsupported {
+				panic(recovered)
+			}
+			ok = false
+		}
+	}()
+
+	comp := compiler.NewElaborationCompiler(program.Elaboration)
+	comp.InterpretedComposites = true
+	funcs := comp.VisitProgram(program.Program).([]*ir.Func)
+
+	return bytecode.Generate(funcs), true
+}
@@ -0,0 +1,611 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bytecode
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/onflow/cadence/runtime/compiler/ir"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+type codeGen struct {
+	program         *Program
+	code            []byte
+	labels          []*label
+	constantIndices map[constantKey]uint16
+	typeIndices     map[string]uint16
+	nameIndices     map[string]uint16
+}
+
+type constantKey struct {
+	data string
+	kind ConstantKind
+}
+
+// label is the target of a branch.
+// Branches to a loop jump backwards to the start of the loop,
+// branches to other labels jump forwards to the end of the label
+type label struct {
+	// jumps are the offsets of the targets of jumps to the end of the label,
+	// which are patched once the end is known
+	jumps  []int
+	start  int
+	isLoop bool
+}
+
+var _ ir.Visitor = &codeGen{}
+
+// Generate generates a program for the given functions.
+// The index of a function in the given slice is its function index,
+// i.e. calls refer to it by this index
+func Generate(funcs []*ir.Func) *Program {
+	g := &codeGen{
+		program: &Program{},
+	}
+
+	for _, f := range funcs {
+		f.Accept(g)
+	}
+
+	return g.program
+}
+
+func (g *codeGen) emit(opcode Opcode, operands ...uint16) {
+	g.code = append(g.code, byte(opcode))
+	for _, operand := range operands {
+		g.code = binary.BigEndian.AppendUint16(g.code, operand)
+	}
+}
+
+// emitJump emits a jump with a placeholder target,
+// and returns the offset of the target, so it can be patched
+func (g *codeGen) emitJump(opcode Opcode) int {
+	g.emit(opcode, 0)
+	return len(g.code) - 2
+}
+
+// patchJump sets the target of the jump at the given offset to the current offset
+func (g *codeGen) patchJump(offset int) {
+	binary.BigEndian.PutUint16(g.code[offset:], g.target(len(g.code)))
+}
+
+func (g *codeGen) target(offset int) uint16 {
+	if offset > math.MaxUint16 {
+		panic(fmt.Errorf("bytecode: code size limit exceeded: %d", offset))
+	}
+	return uint16(offset)
+}
+
+func (g *codeGen) pushLabel(isLoop bool) *label {
+	l := &label{
+		start:  len(g.code),
+		isLoop: isLoop,
+	}
+	g.labels = append(g.labels, l)
+	return l
+}
+
+func (g *codeGen) popLabel() {
+	lastIndex := len(g.labels) - 1
+	l := g.labels[lastIndex]
+	g.labels = g.labels[:lastIndex]
+
+	for _, jump := range l.jumps {
+		g.patchJump(jump)
+	}
+}
+
+func (g *codeGen) branchTarget(index uint32) *label {
+	labelCount := uint32(len(g.labels))
+	if index >= labelCount {
+		panic(errors.NewUnreachableError())
+	}
+	return g.labels[labelCount-1-index]
+}
+
+func index(count int) uint16 {
+	if count > math.MaxUint16 {
+		panic(fmt.Errorf("bytecode: index limit exceeded: %d", count))
+	}
+	return uint16(count)
+}
+
+func (g *codeGen) addConstant(kind ConstantKind, data []byte) uint16 {
+	constant := Constant{
+		Kind: kind,
+		Data: data,
+	}
+
+	// Constants are only added once
+	key := constantKey{
+		kind: kind,
+		data: string(data),
+	}
+	if constantIndex, ok := g.constantIndices[key]; ok {
+		return constantIndex
+	}
+
+	constantIndex := index(len(g.program.Constants))
+	g.program.Constants = append(g.program.Constants, constant)
+
+	if g.constantIndices == nil {
+		g.constantIndices = map[constantKey]uint16{}
+	}
+	g.constantIndices[key] = constantIndex
+
+	return constantIndex
+}
+
+func (g *codeGen) emitConstant(kind ConstantKind, data []byte) {
+	g.emit(OpcodeConstant, g.addConstant(kind, data))
+}
+
+func (g *codeGen) addType(ty sema.Type) uint16 {
+	staticType := interpreter.ConvertSemaToStaticType(nil, ty)
+	encoded, err := interpreter.StaticTypeToBytes(staticType)
+	if err != nil {
+		panic(fmt.Errorf("failed to encode type %s: %w", ty, err))
+	}
+
+	key := string(encoded)
+	if typeIndex, ok := g.typeIndices[key]; ok {
+		return typeIndex
+	}
+
+	typeIndex := index(len(g.program.Types))
+	g.program.Types = append(g.program.Types, encoded)
+
+	if g.typeIndices == nil {
+		g.typeIndices = map[string]uint16{}
+	}
+	g.typeIndices[key] = typeIndex
+
+	return typeIndex
+}
+
+func (g *codeGen) addName(name string) uint16 {
+	if nameIndex, ok := g.nameIndices[name]; ok {
+		return nameIndex
+	}
+
+	nameIndex := index(len(g.program.Names))
+	g.program.Names = append(g.program.Names, name)
+
+	if g.nameIndices == nil {
+		g.nameIndices = map[string]uint16{}
+	}
+	g.nameIndices[name] = nameIndex
+
+	return nameIndex
+}
+
+func (g *codeGen) VisitFunc(f *ir.Func) ir.Repr {
+	g.code = nil
+	g.labels = nil
+
+	f.Statement.Accept(g)
+
+	// Semantic analysis ensures that all paths of functions with a result return.
+	// Functions without a result may implicitly return at the end
+	hasResult := len(f.Type.Results) > 0
+	if !hasResult {
+		g.emit(OpcodeReturnVoid)
+	}
+
+	g.program.Functions = append(
+		g.program.Functions,
+		&Function{
+			Name:           f.Name,
+			Code:           g.code,
+			ParameterCount: index(len(f.Type.Params)),
+			LocalCount:     index(len(f.Locals)),
+			HasResult:      hasResult,
+		},
+	)
+
+	g.code = nil
+
+	return nil
+}
+
+// constants
+
+func (g *codeGen) VisitInt(i ir.Int) ir.Repr {
+	g.emitConstant(ConstantKindInt, i.Value)
+	return nil
+}
+
+func (g *codeGen) VisitString(s ir.String) ir.Repr {
+	g.emitConstant(ConstantKindString, []byte(s.Value))
+	return nil
+}
+
+func (g *codeGen) VisitCharacter(c ir.Character) ir.Repr {
+	g.emitConstant(ConstantKindCharacter, []byte(c.Value))
+	return nil
+}
+
+func (g *codeGen) VisitBool(b ir.Bool) ir.Repr {
+	if b.Value {
+		g.emit(OpcodeTrue)
+	} else {
+		g.emit(OpcodeFalse)
+	}
+	return nil
+}
+
+func (g *codeGen) VisitNil(_ ir.Nil) ir.Repr {
+	g.emit(OpcodeNil)
+	return nil
+}
+
+func (g *codeGen) VisitVoid(_ ir.Void) ir.Repr {
+	g.emit(OpcodeVoid)
+	return nil
+}
+
+func (g *codeGen) VisitFix64(f ir.Fix64) ir.Repr {
+	data := binary.BigEndian.AppendUint64(nil, uint64(f.Value))
+	g.emitConstant(ConstantKindFix64, data)
+	return nil
+}
+
+func (g *codeGen) VisitUFix64(f ir.UFix64) ir.Repr {
+	data := binary.BigEndian.AppendUint64(nil, f.Value)
+	g.emitConstant(ConstantKindUFix64, data)
+	return nil
+}
+
+func (g *codeGen) VisitPath(p ir.Path) ir.Repr {
+	data := append([]byte{byte(p.Domain)}, p.Identifier...)
+	g.emitConstant(ConstantKindPath, data)
+	return nil
+}
+
+// statements
+
+func (g *codeGen) VisitSequence(sequence *ir.Sequence) ir.Repr {
+	for _, stmt := range sequence.Stmts {
+		stmt.Accept(g)
+	}
+	return nil
+}
+
+func (g *codeGen) VisitBlock(block *ir.Block) ir.Repr {
+	g.pushLabel(false)
+	for _, stmt := range block.Stmts {
+		stmt.Accept(g)
+	}
+	g.popLabel()
+	return nil
+}
+
+func (g *codeGen) VisitLoop(loop *ir.Loop) ir.Repr {
+	g.pushLabel(true)
+	for _, stmt := range loop.Stmts {
+		stmt.Accept(g)
+	}
+	g.popLabel()
+	return nil
+}
+
+func (g *codeGen) VisitIf(i *ir.If) ir.Repr {
+	i.Test.Accept(g)
+	elseJump := g.emitJump(OpcodeJumpIfFalse)
+
+	l := g.pushLabel(false)
+
+	i.Then.Accept(g)
+
+	if i.Else != nil {
+		l.jumps = append(l.jumps, g.emitJump(OpcodeJump))
+		g.patchJump(elseJump)
+		i.Else.Accept(g)
+	} else {
+		g.patchJump(elseJump)
+	}
+
+	g.popLabel()
+	return nil
+}
+
+func (g *codeGen) emitBranch(opcode Opcode, target *label) {
+	if target.isLoop {
+		g.emit(opcode, g.target(target.start))
+	} else {
+		target.jumps = append(target.jumps, g.emitJump(opcode))
+	}
+}
+
+func (g *codeGen) VisitBranch(branch *ir.Branch) ir.Repr {
+	g.emitBranch(OpcodeJump, g.branchTarget(branch.Index))
+	return nil
+}
+
+func (g *codeGen) VisitBranchIf(branchIf *ir.BranchIf) ir.Repr {
+	branchIf.Exp.Accept(g)
+	g.emitBranch(OpcodeJumpIfTrue, g.branchTarget(branchIf.Index))
+	return nil
+}
+
+func (g *codeGen) VisitStoreLocal(storeLocal *ir.StoreLocal) ir.Repr {
+	storeLocal.Exp.Accept(g)
+	g.emit(OpcodeSetLocal, index(int(storeLocal.LocalIndex)))
+	return nil
+}
+
+func (g *codeGen) VisitDrop(drop *ir.Drop) ir.Repr {
+	drop.Exp.Accept(g)
+	g.emit(OpcodePop)
+	return nil
+}
+
+func (g *codeGen) VisitReturn(r *ir.Return) ir.Repr {
+	if r.Exp != nil {
+		r.Exp.Accept(g)
+		g.emit(OpcodeReturn)
+	} else {
+		g.emit(OpcodeReturnVoid)
+	}
+	return nil
+}
+
+func (g *codeGen) VisitStoreMember(storeMember *ir.StoreMember) ir.Repr {
+	storeMember.Exp.Accept(g)
+	storeMember.Value.Accept(g)
+	g.emit(OpcodeSetMember, g.addName(storeMember.Name))
+	return nil
+}
+
+func (g *codeGen) VisitStoreIndex(storeIndex *ir.StoreIndex) ir.Repr {
+	storeIndex.Exp.Accept(g)
+	storeIndex.Index.Accept(g)
+	storeIndex.Value.Accept(g)
+	g.emit(OpcodeSetIndex)
+	return nil
+}
+
+func (g *codeGen) VisitEmit(emit *ir.Emit) ir.Repr {
+	emit.Exp.Accept(g)
+	g.emit(OpcodeEmit, g.addType(emit.Type))
+	return nil
+}
+
+func (g *codeGen) VisitCondition(condition *ir.Condition) ir.Repr {
+	// If the test is false, fail with the condition kind and message
+	condition.Test.Accept(g)
+	successJump := g.emitJump(OpcodeJumpIfTrue)
+
+	if condition.Message != nil {
+		condition.Message.Accept(g)
+	} else {
+		g.emit(OpcodeNil)
+	}
+	g.emit(OpcodeFailCondition, uint16(condition.Kind))
+
+	g.patchJump(successJump)
+	return nil
+}
+
+func (g *codeGen) VisitLoopIteration(_ *ir.LoopIteration) ir.Repr {
+	g.emit(OpcodeLoopIteration)
+	return nil
+}
+
+func (g *codeGen) VisitStatementStart(statement *ir.StatementStart) ir.Repr {
+	rangeIndex := index(len(g.program.Ranges))
+	g.program.Ranges = append(g.program.Ranges, statement.Range)
+	g.emit(OpcodeStatement, rangeIndex)
+	return nil
+}
+
+// expressions
+
+func (g *codeGen) VisitConst(c *ir.Const) ir.Repr {
+	c.Constant.Accept(g)
+	return nil
+}
+
+func (g *codeGen) VisitCopyLocal(c *ir.CopyLocal) ir.Repr {
+	g.emit(OpcodeGetLocal, index(int(c.LocalIndex)))
+	return nil
+}
+
+func (g *codeGen) VisitMoveLocal(m *ir.MoveLocal) ir.Repr {
+	// TODO: invalidate local
+	g.emit(OpcodeGetLocal, index(int(m.LocalIndex)))
+	return nil
+}
+
+func (g *codeGen) VisitTeeLocal(t *ir.TeeLocal) ir.Repr {
+	t.Exp.Accept(g)
+	g.emit(OpcodeDup)
+	g.emit(OpcodeSetLocal, index(int(t.LocalIndex)))
+	return nil
+}
+
+func (g *codeGen) VisitUnOpExpr(expr *ir.UnOpExpr) ir.Repr {
+	expr.Expr.Accept(g)
+
+	var opcode Opcode
+	switch expr.Op {
+	case ir.UnOpNegate:
+		opcode = OpcodeNegate
+	case ir.UnOpMinus:
+		opcode = OpcodeMinus
+	case ir.UnOpForce:
+		opcode = OpcodeForce
+	case ir.UnOpIsNil:
+		opcode = OpcodeIsNil
+	case ir.UnOpSome:
+		opcode = OpcodeSome
+	case ir.UnOpTransfer:
+		opcode = OpcodeTransfer
+	default:
+		panic(errors.NewUnreachableError())
+	}
+
+	g.emit(opcode)
+	return nil
+}
+
+var binaryOpcodes = map[ir.BinOp]Opcode{
+	ir.BinOpPlus:              OpcodeAdd,
+	ir.BinOpMinus:             OpcodeSubtract,
+	ir.BinOpMul:               OpcodeMultiply,
+	ir.BinOpDiv:               OpcodeDivide,
+	ir.BinOpMod:               OpcodeMod,
+	ir.BinOpLess:              OpcodeLess,
+	ir.BinOpLessEqual:         OpcodeLessEqual,
+	ir.BinOpGreater:           OpcodeGreater,
+	ir.BinOpGreaterEqual:      OpcodeGreaterEqual,
+	ir.BinOpEqual:             OpcodeEqual,
+	ir.BinOpNotEqual:          OpcodeNotEqual,
+	ir.BinOpBitwiseOr:         OpcodeBitwiseOr,
+	ir.BinOpBitwiseXor:        OpcodeBitwiseXor,
+	ir.BinOpBitwiseAnd:        OpcodeBitwiseAnd,
+	ir.BinOpBitwiseLeftShift:  OpcodeBitwiseLeftShift,
+	ir.BinOpBitwiseRightShift: OpcodeBitwiseRightShift,
+}
+
+func (g *codeGen) VisitBinOpExpr(expr *ir.BinOpExpr) ir.Repr {
+	expr.Left.Accept(g)
+	expr.Right.Accept(g)
+
+	opcode, ok := binaryOpcodes[expr.Op]
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	g.emit(opcode)
+	return nil
+}
+
+func (g *codeGen) VisitCall(call *ir.Call) ir.Repr {
+	for _, argument := range call.Arguments {
+		argument.Accept(g)
+	}
+	g.emit(
+		OpcodeCall,
+		index(int(call.FunctionIndex)),
+		index(len(call.Arguments)),
+	)
+	return nil
+}
+
+func (g *codeGen) VisitGlobal(global *ir.Global) ir.Repr {
+	g.emit(OpcodeGetGlobal, g.addName(global.Name))
+	return nil
+}
+
+func (g *codeGen) VisitConditional(conditional *ir.Conditional) ir.Repr {
+	conditional.Test.Accept(g)
+	elseJump := g.emitJump(OpcodeJumpIfFalse)
+
+	conditional.Then.Accept(g)
+	endJump := g.emitJump(OpcodeJump)
+
+	g.patchJump(elseJump)
+	conditional.Else.Accept(g)
+
+	g.patchJump(endJump)
+	return nil
+}
+
+func (g *codeGen) VisitInvoke(invoke *ir.Invoke) ir.Repr {
+	invoke.Function.Accept(g)
+	for _, argument := range invoke.Arguments {
+		argument.Accept(g)
+	}
+	g.emit(OpcodeInvoke, index(len(invoke.Arguments)))
+	return nil
+}
+
+func (g *codeGen) VisitMember(member *ir.Member) ir.Repr {
+	member.Exp.Accept(g)
+	g.emit(OpcodeGetMember, g.addName(member.Name))
+	return nil
+}
+
+func (g *codeGen) VisitIndex(i *ir.Index) ir.Repr {
+	i.Exp.Accept(g)
+	i.Index.Accept(g)
+	g.emit(OpcodeGetIndex)
+	return nil
+}
+
+func (g *codeGen) VisitArray(array *ir.Array) ir.Repr {
+	for _, element := range array.Elements {
+		element.Accept(g)
+	}
+	g.emit(
+		OpcodeNewArray,
+		g.addType(array.Type),
+		index(len(array.Elements)),
+	)
+	return nil
+}
+
+func (g *codeGen) VisitDictionary(dictionary *ir.Dictionary) ir.Repr {
+	for _, entry := range dictionary.Entries {
+		entry.Key.Accept(g)
+		entry.Value.Accept(g)
+	}
+	g.emit(
+		OpcodeNewDictionary,
+		g.addType(dictionary.Type),
+		index(len(dictionary.Entries)),
+	)
+	return nil
+}
+
+func (g *codeGen) VisitConvert(convert *ir.Convert) ir.Repr {
+	convert.Exp.Accept(g)
+	g.emit(OpcodeConvert, g.addType(convert.Type))
+	return nil
+}
+
+func (g *codeGen) VisitFailableCast(cast *ir.FailableCast) ir.Repr {
+	cast.Exp.Accept(g)
+	g.emit(OpcodeFailableCast, g.addType(cast.Type))
+	return nil
+}
+
+func (g *codeGen) VisitForceCast(cast *ir.ForceCast) ir.Repr {
+	cast.Exp.Accept(g)
+	g.emit(OpcodeForceCast, g.addType(cast.Type))
+	return nil
+}
+
+func (g *codeGen) VisitReference(reference *ir.Reference) ir.Repr {
+	reference.Exp.Accept(g)
+	g.emit(OpcodeReference, g.addType(reference.Type))
+	return nil
+}
+
+func (g *codeGen) VisitDestroy(destroy *ir.Destroy) ir.Repr {
+	destroy.Exp.Accept(g)
+	g.emit(OpcodeDestroy)
+	return nil
+}
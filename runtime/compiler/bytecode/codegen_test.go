@@ -0,0 +1,198 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bytecode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/compiler/ir"
+)
+
+func TestGenerateSimple(t *testing.T) {
+
+	t.Parallel()
+
+	program := Generate([]*ir.Func{
+		{
+			Name: "inc",
+			Type: ir.FuncType{
+				Params: []ir.ValType{
+					ir.ValTypeInt,
+				},
+				Results: []ir.ValType{
+					ir.ValTypeInt,
+				},
+			},
+			Locals: []ir.Local{
+				{Type: ir.ValTypeInt},
+			},
+			Statement: &ir.Sequence{
+				Stmts: []ir.Stmt{
+					&ir.StoreLocal{
+						LocalIndex: 1,
+						Exp: &ir.Const{
+							Constant: ir.Int{Value: []byte{1, 1}},
+						},
+					},
+					&ir.Return{
+						Exp: &ir.BinOpExpr{
+							Op: ir.BinOpPlus,
+							Left: &ir.CopyLocal{
+								LocalIndex: 0,
+							},
+							Right: &ir.CopyLocal{
+								LocalIndex: 1,
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	require.Equal(t,
+		[]Constant{
+			{
+				Kind: ConstantKindInt,
+				Data: []byte{1, 1},
+			},
+		},
+		program.Constants,
+	)
+
+	require.Len(t, program.Functions, 1)
+
+	function := program.Functions[0]
+	require.Equal(t, "inc", function.Name)
+	require.Equal(t, uint16(1), function.ParameterCount)
+	require.Equal(t, uint16(1), function.LocalCount)
+	require.True(t, function.HasResult)
+
+	instructions, err := Decode(function.Code)
+	require.NoError(t, err)
+
+	require.Equal(t,
+		[]Instruction{
+			{Opcode: OpcodeConstant, Operands: []uint16{0}},
+			{Opcode: OpcodeSetLocal, Operands: []uint16{1}},
+			{Opcode: OpcodeGetLocal, Operands: []uint16{0}},
+			{Opcode: OpcodeGetLocal, Operands: []uint16{1}},
+			{Opcode: OpcodeAdd},
+			{Opcode: OpcodeReturn},
+		},
+		instructions,
+	)
+}
+
+func TestGenerateLoop(t *testing.T) {
+
+	t.Parallel()
+
+	// while i < n { i = i + 1 }, as compiled by the compiler:
+	// block { loop { br_if 1 (!test); iteration; block { body }; br 0 } }
+
+	program := Generate([]*ir.Func{
+		{
+			Name: "count",
+			Type: ir.FuncType{
+				Params: []ir.ValType{
+					ir.ValTypeInt,
+					ir.ValTypeInt,
+				},
+			},
+			Statement: &ir.Block{
+				Stmts: []ir.Stmt{
+					&ir.Loop{
+						Stmts: []ir.Stmt{
+							&ir.BranchIf{
+								Exp: &ir.UnOpExpr{
+									Op: ir.UnOpNegate,
+									Expr: &ir.BinOpExpr{
+										Op:    ir.BinOpLess,
+										Left:  &ir.CopyLocal{LocalIndex: 0},
+										Right: &ir.CopyLocal{LocalIndex: 1},
+									},
+								},
+								Index: 1,
+							},
+							&ir.LoopIteration{},
+							&ir.Block{
+								Stmts: []ir.Stmt{
+									&ir.StoreLocal{
+										LocalIndex: 0,
+										Exp: &ir.BinOpExpr{
+											Op:   ir.BinOpPlus,
+											Left: &ir.CopyLocal{LocalIndex: 0},
+											Right: &ir.Const{
+												Constant: ir.Int{Value: []byte{1, 1}},
+											},
+										},
+									},
+								},
+							},
+							&ir.Branch{
+								Index: 0,
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	require.Len(t, program.Functions, 1)
+
+	function := program.Functions[0]
+	require.False(t, function.HasResult)
+
+	instructions, err := Decode(function.Code)
+	require.NoError(t, err)
+
+	require.Equal(t,
+		[]Instruction{
+			// offset 0
+			{Opcode: OpcodeGetLocal, Operands: []uint16{0}},
+			// offset 3
+			{Opcode: OpcodeGetLocal, Operands: []uint16{1}},
+			// offset 6
+			{Opcode: OpcodeLess},
+			// offset 7
+			{Opcode: OpcodeNegate},
+			// offset 8: exit the loop
+			{Opcode: OpcodeJumpIfTrue, Operands: []uint16{25}},
+			// offset 11
+			{Opcode: OpcodeLoopIteration},
+			// offset 12
+			{Opcode: OpcodeGetLocal, Operands: []uint16{0}},
+			// offset 15
+			{Opcode: OpcodeConstant, Operands: []uint16{0}},
+			// offset 18
+			{Opcode: OpcodeAdd},
+			// offset 19
+			{Opcode: OpcodeSetLocal, Operands: []uint16{0}},
+			// offset 22: continue the loop
+			{Opcode: OpcodeJump, Operands: []uint16{0}},
+			// offset 25
+			{Opcode: OpcodeReturnVoid},
+		},
+		instructions,
+	)
+}
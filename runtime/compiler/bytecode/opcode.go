@@ -0,0 +1,175 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bytecode
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=Opcode -trimprefix=Opcode
+
+// Opcode is the operation code of an instruction.
+//
+// Operands follow the opcode in the code, and are encoded as big-endian uint16.
+// The comment of each opcode lists its operands, and its effect on the operand stack.
+type Opcode byte
+
+const (
+	OpcodeUnknown Opcode = iota
+
+	// constants
+
+	// OpcodeConstant (constant index): -> value
+	OpcodeConstant
+	// OpcodeTrue: -> true
+	OpcodeTrue
+	// OpcodeFalse: -> false
+	OpcodeFalse
+	// OpcodeNil: -> nil
+	OpcodeNil
+	// OpcodeVoid: -> void
+	OpcodeVoid
+
+	// locals and globals
+
+	// OpcodeGetLocal (local index): -> value
+	OpcodeGetLocal
+	// OpcodeSetLocal (local index): value ->
+	OpcodeSetLocal
+	// OpcodeGetGlobal (name index): -> value
+	OpcodeGetGlobal
+
+	// stack
+
+	// OpcodePop: value ->
+	OpcodePop
+	// OpcodeDup: value -> value, value
+	OpcodeDup
+
+	// control flow
+
+	// OpcodeJump (target): ->
+	OpcodeJump
+	// OpcodeJumpIfFalse (target): test ->
+	OpcodeJumpIfFalse
+	// OpcodeJumpIfTrue (target): test ->
+	OpcodeJumpIfTrue
+	// OpcodeLoopIteration: ->
+	// Reports an iteration of the enclosing loop
+	OpcodeLoopIteration
+	// OpcodeStatement (range): ->
+	// Reports the execution of the statement with the given source range
+	OpcodeStatement
+	// OpcodeReturn: value ->
+	OpcodeReturn
+	// OpcodeReturnVoid: ->
+	OpcodeReturnVoid
+	// OpcodeFailCondition (condition kind): message ->
+	OpcodeFailCondition
+
+	// binary operations: left, right -> result
+
+	OpcodeAdd
+	OpcodeSubtract
+	OpcodeMultiply
+	OpcodeDivide
+	OpcodeMod
+	OpcodeLess
+	OpcodeLessEqual
+	OpcodeGreater
+	OpcodeGreaterEqual
+	OpcodeEqual
+	OpcodeNotEqual
+	OpcodeBitwiseOr
+	OpcodeBitwiseXor
+	OpcodeBitwiseAnd
+	OpcodeBitwiseLeftShift
+	OpcodeBitwiseRightShift
+
+	// unary operations: value -> result
+
+	OpcodeNegate
+	OpcodeMinus
+	OpcodeForce
+	OpcodeIsNil
+	OpcodeSome
+	OpcodeTransfer
+
+	// invocations
+
+	// OpcodeCall (function index, argument count): arguments... -> result
+	OpcodeCall
+	// OpcodeInvoke (argument count): function, arguments... -> result
+	OpcodeInvoke
+
+	// values
+
+	// OpcodeGetMember (name index): target -> value
+	OpcodeGetMember
+	// OpcodeSetMember (name index): target, value ->
+	OpcodeSetMember
+	// OpcodeGetIndex: target, index -> value
+	OpcodeGetIndex
+	// OpcodeSetIndex: target, index, value ->
+	OpcodeSetIndex
+	// OpcodeNewArray (type index, element count): elements... -> array
+	OpcodeNewArray
+	// OpcodeNewDictionary (type index, entry count): keys and values... -> dictionary
+	OpcodeNewDictionary
+	// OpcodeConvert (type index): value -> result
+	OpcodeConvert
+	// OpcodeFailableCast (type index): value -> result
+	OpcodeFailableCast
+	// OpcodeForceCast (type index): value -> result
+	OpcodeForceCast
+	// OpcodeReference (type index): value -> reference
+	OpcodeReference
+	// OpcodeDestroy: resource -> void
+	OpcodeDestroy
+	// OpcodeEmit (type index): event ->
+	OpcodeEmit
+)
+
+// OperandCounts are the number of operands of each opcode
+var OperandCounts = [...]int{
+	OpcodeConstant:      1,
+	OpcodeGetLocal:      1,
+	OpcodeSetLocal:      1,
+	OpcodeGetGlobal:     1,
+	OpcodeJump:          1,
+	OpcodeJumpIfFalse:   1,
+	OpcodeJumpIfTrue:    1,
+	OpcodeStatement:     1,
+	OpcodeFailCondition: 1,
+	OpcodeCall:          2,
+	OpcodeInvoke:        1,
+	OpcodeGetMember:     1,
+	OpcodeSetMember:     1,
+	OpcodeNewArray:      2,
+	OpcodeNewDictionary: 2,
+	OpcodeConvert:       1,
+	OpcodeFailableCast:  1,
+	OpcodeForceCast:     1,
+	OpcodeReference:     1,
+	OpcodeEmit:          1,
+}
+
+// OperandCount returns the number of operands of the opcode
+func (o Opcode) OperandCount() int {
+	if int(o) >= len(OperandCounts) {
+		return 0
+	}
+	return OperandCounts[o]
+}
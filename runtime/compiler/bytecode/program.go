@@ -0,0 +1,120 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bytecode
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/ast"
+)
+
+// Program is a compiled program
+type Program struct {
+	Functions []*Function
+	Constants []Constant
+	// Types are the types used by instructions, encoded as static types
+	Types [][]byte
+	// Names are the names used by instructions, e.g. of globals and members
+	Names []string
+	// Ranges are the source ranges of statements, used to report errors
+	Ranges []ast.Range
+}
+
+// Function is a compiled function
+type Function struct {
+	Name           string
+	Code           []byte
+	ParameterCount uint16
+	// LocalCount is the number of locals, excluding the parameters
+	LocalCount uint16
+	HasResult  bool
+}
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=ConstantKind
+
+type ConstantKind byte
+
+const (
+	ConstantKindUnknown ConstantKind = iota
+	// ConstantKindInt is an Int, encoded as a sign byte (1 if positive),
+	// followed by the big-endian magnitude
+	ConstantKindInt
+	ConstantKindString
+	ConstantKindCharacter
+	// ConstantKindFix64 is a Fix64, encoded as big-endian int64
+	ConstantKindFix64
+	// ConstantKindUFix64 is a UFix64, encoded as big-endian uint64
+	ConstantKindUFix64
+	// ConstantKindPath is a path, encoded as the domain byte,
+	// followed by the identifier
+	ConstantKindPath
+)
+
+type Constant struct {
+	Data []byte
+	Kind ConstantKind
+}
+
+// Instruction is a decoded instruction
+type Instruction struct {
+	Operands []uint16
+	Opcode   Opcode
+}
+
+func (i Instruction) String() string {
+	var builder strings.Builder
+	builder.WriteString(i.Opcode.String())
+	for _, operand := range i.Operands {
+		_, _ = fmt.Fprintf(&builder, " %d", operand)
+	}
+	return builder.String()
+}
+
+// Decode decodes the given code into instructions
+func Decode(code []byte) ([]Instruction, error) {
+	var instructions []Instruction
+
+	for offset := 0; offset < len(code); {
+		opcode := Opcode(code[offset])
+		offset++
+
+		operandCount := opcode.OperandCount()
+		if offset+operandCount*2 > len(code) {
+			return nil, fmt.Errorf("missing operands of %s at offset %d", opcode, offset)
+		}
+
+		var operands []uint16
+		if operandCount > 0 {
+			operands = make([]uint16, operandCount)
+			for i := range operands {
+				operands[i] = binary.BigEndian.Uint16(code[offset:])
+				offset += 2
+			}
+		}
+
+		instructions = append(instructions, Instruction{
+			Opcode:   opcode,
+			Operands: operands,
+		})
+	}
+
+	return instructions, nil
+}
@@ -24,21 +24,23 @@ import (
 	"github.com/onflow/cadence/runtime/compiler/ir"
 	"github.com/onflow/cadence/runtime/compiler/wasm"
 	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
 )
 
-const RuntimeModuleName = "crt"
-
 type wasmCodeGen struct {
-	mod                        *wasm.ModuleBuilder
-	code                       *wasm.Code
-	runtimeFunctionIndexInt    uint32
-	runtimeFunctionIndexString uint32
-	runtimeFunctionIndexAdd    uint32
+	mod                    *wasm.ModuleBuilder
+	code                   *wasm.Code
+	instructions           []wasm.Instruction
+	runtimeFunctionIndices map[string]uint32
+	constantOffsets        map[string]uint32
+	funcs                  []*ir.Func
+	funcIndexOffset        uint32
 }
 
 func (codeGen *wasmCodeGen) VisitInt(i ir.Int) ir.Repr {
 	codeGen.emitConstantCall(
-		codeGen.runtimeFunctionIndexInt,
+		RuntimeFunctionNameInt,
 		i.Value,
 	)
 	return nil
@@ -46,12 +48,59 @@ func (codeGen *wasmCodeGen) VisitInt(i ir.Int) ir.Repr {
 
 func (codeGen *wasmCodeGen) VisitString(s ir.String) ir.Repr {
 	codeGen.emitConstantCall(
-		codeGen.runtimeFunctionIndexString,
+		RuntimeFunctionNameString,
 		[]byte(s.Value),
 	)
 	return nil
 }
 
+func (codeGen *wasmCodeGen) VisitCharacter(c ir.Character) ir.Repr {
+	codeGen.emitConstantCall(
+		RuntimeFunctionNameCharacter,
+		[]byte(c.Value),
+	)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitBool(b ir.Bool) ir.Repr {
+	var value int32
+	if b.Value {
+		value = 1
+	}
+	codeGen.emit(wasm.InstructionI32Const{Value: value})
+	codeGen.emitRuntimeCall(RuntimeFunctionNameBool)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitNil(_ ir.Nil) ir.Repr {
+	codeGen.emitRuntimeCall(RuntimeFunctionNameNil)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitVoid(_ ir.Void) ir.Repr {
+	codeGen.emitRuntimeCall(RuntimeFunctionNameVoid)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitFix64(f ir.Fix64) ir.Repr {
+	codeGen.emit(wasm.InstructionI64Const{Value: f.Value})
+	codeGen.emitRuntimeCall(RuntimeFunctionNameFix64)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitUFix64(f ir.UFix64) ir.Repr {
+	codeGen.emit(wasm.InstructionI64Const{Value: int64(f.Value)})
+	codeGen.emitRuntimeCall(RuntimeFunctionNameUFix64)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitPath(p ir.Path) ir.Repr {
+	codeGen.emit(wasm.InstructionI32Const{Value: int32(p.Domain)})
+	codeGen.emitConstant([]byte(p.Identifier))
+	codeGen.emitRuntimeCall(RuntimeFunctionNamePath)
+	return nil
+}
+
 func (codeGen *wasmCodeGen) VisitSequence(sequence *ir.Sequence) ir.Repr {
 	for _, stmt := range sequence.Stmts {
 		stmt.Accept(codeGen)
@@ -59,29 +108,60 @@ func (codeGen *wasmCodeGen) VisitSequence(sequence *ir.Sequence) ir.Repr {
 	return nil
 }
 
-func (codeGen *wasmCodeGen) VisitBlock(_ *ir.Block) ir.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (codeGen *wasmCodeGen) VisitBlock(block *ir.Block) ir.Repr {
+	codeGen.emit(wasm.InstructionBlock{
+		Block: wasm.Block{
+			Instructions1: codeGen.generateStmts(block.Stmts),
+		},
+	})
+	return nil
 }
 
-func (codeGen *wasmCodeGen) VisitLoop(_ *ir.Loop) ir.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (codeGen *wasmCodeGen) VisitLoop(loop *ir.Loop) ir.Repr {
+	codeGen.emit(wasm.InstructionLoop{
+		Block: wasm.Block{
+			Instructions1: codeGen.generateStmts(loop.Stmts),
+		},
+	})
+	return nil
 }
 
-func (codeGen *wasmCodeGen) VisitIf(_ *ir.If) ir.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (codeGen *wasmCodeGen) VisitIf(i *ir.If) ir.Repr {
+	codeGen.emitTest(i.Test)
+
+	thenInstructions := codeGen.generateInstructions(func() {
+		i.Then.Accept(codeGen)
+	})
+
+	var elseInstructions []wasm.Instruction
+	if i.Else != nil {
+		elseInstructions = codeGen.generateInstructions(func() {
+			i.Else.Accept(codeGen)
+		})
+	}
+
+	codeGen.emit(wasm.InstructionIf{
+		Block: wasm.Block{
+			Instructions1: thenInstructions,
+			Instructions2: elseInstructions,
+		},
+	})
+	return nil
 }
 
-func (codeGen *wasmCodeGen) VisitBranch(_ *ir.Branch) ir.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (codeGen *wasmCodeGen) VisitBranch(branch *ir.Branch) ir.Repr {
+	codeGen.emit(wasm.InstructionBr{
+		LabelIndex: branch.Index,
+	})
+	return nil
 }
 
-func (codeGen *wasmCodeGen) VisitBranchIf(_ *ir.BranchIf) ir.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (codeGen *wasmCodeGen) VisitBranchIf(branchIf *ir.BranchIf) ir.Repr {
+	codeGen.emitTest(branchIf.Exp)
+	codeGen.emit(wasm.InstructionBrIf{
+		LabelIndex: branchIf.Index,
+	})
+	return nil
 }
 
 func (codeGen *wasmCodeGen) VisitStoreLocal(storeLocal *ir.StoreLocal) ir.Repr {
@@ -92,63 +172,321 @@ func (codeGen *wasmCodeGen) VisitStoreLocal(storeLocal *ir.StoreLocal) ir.Repr {
 	return nil
 }
 
-func (codeGen *wasmCodeGen) VisitDrop(_ *ir.Drop) ir.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (codeGen *wasmCodeGen) VisitDrop(drop *ir.Drop) ir.Repr {
+	drop.Exp.Accept(codeGen)
+	codeGen.emit(wasm.InstructionDrop{})
+	return nil
 }
 
 func (codeGen *wasmCodeGen) VisitReturn(r *ir.Return) ir.Repr {
-	r.Exp.Accept(codeGen)
+	if r.Exp != nil {
+		r.Exp.Accept(codeGen)
+	}
 	codeGen.emit(wasm.InstructionReturn{})
 	return nil
 }
 
+func (codeGen *wasmCodeGen) VisitStoreMember(storeMember *ir.StoreMember) ir.Repr {
+	storeMember.Exp.Accept(codeGen)
+	codeGen.emitConstant([]byte(storeMember.Name))
+	storeMember.Value.Accept(codeGen)
+	codeGen.emitRuntimeCall(RuntimeFunctionNameSetMember)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitStoreIndex(storeIndex *ir.StoreIndex) ir.Repr {
+	storeIndex.Exp.Accept(codeGen)
+	storeIndex.Index.Accept(codeGen)
+	storeIndex.Value.Accept(codeGen)
+	codeGen.emitRuntimeCall(RuntimeFunctionNameSetIndex)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitEmit(emit *ir.Emit) ir.Repr {
+	emit.Exp.Accept(codeGen)
+	codeGen.emitTypeConstant(emit.Type)
+	codeGen.emitRuntimeCall(RuntimeFunctionNameEmit)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitCondition(condition *ir.Condition) ir.Repr {
+	// If the test is false, fail with the condition kind and message
+	codeGen.emitTest(condition.Test)
+	codeGen.emit(wasm.InstructionI32Eqz{})
+
+	failInstructions := codeGen.generateInstructions(func() {
+		codeGen.emit(wasm.InstructionI32Const{Value: int32(condition.Kind)})
+		if condition.Message != nil {
+			condition.Message.Accept(codeGen)
+		} else {
+			codeGen.emitRuntimeCall(RuntimeFunctionNameNil)
+		}
+		codeGen.emitRuntimeCall(RuntimeFunctionNameFailCondition)
+		codeGen.emit(wasm.InstructionUnreachable{})
+	})
+
+	codeGen.emit(wasm.InstructionIf{
+		Block: wasm.Block{
+			Instructions1: failInstructions,
+		},
+	})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitLoopIteration(_ *ir.LoopIteration) ir.Repr {
+	codeGen.emitRuntimeCall(RuntimeFunctionNameReportLoopIteration)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitStatementStart(_ *ir.StatementStart) ir.Repr {
+	// NOTE: WebAssembly modules have no position information yet,
+	// so only the execution of the statement is reported
+	codeGen.emitRuntimeCall(RuntimeFunctionNameReportStatement)
+	return nil
+}
+
 func (codeGen *wasmCodeGen) VisitConst(c *ir.Const) ir.Repr {
 	c.Constant.Accept(codeGen)
 	return nil
 }
 
 func (codeGen *wasmCodeGen) VisitCopyLocal(c *ir.CopyLocal) ir.Repr {
-	// TODO: copy
 	codeGen.emit(wasm.InstructionLocalGet{
 		LocalIndex: c.LocalIndex,
 	})
 	return nil
 }
 
-func (codeGen *wasmCodeGen) VisitMoveLocal(_ *ir.MoveLocal) ir.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (codeGen *wasmCodeGen) VisitMoveLocal(m *ir.MoveLocal) ir.Repr {
+	// TODO: invalidate local
+	codeGen.emit(wasm.InstructionLocalGet{
+		LocalIndex: m.LocalIndex,
+	})
+	return nil
 }
 
-func (codeGen *wasmCodeGen) VisitUnOpExpr(_ *ir.UnOpExpr) ir.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (codeGen *wasmCodeGen) VisitTeeLocal(t *ir.TeeLocal) ir.Repr {
+	t.Exp.Accept(codeGen)
+	codeGen.emit(wasm.InstructionLocalTee{
+		LocalIndex: t.LocalIndex,
+	})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitUnOpExpr(expr *ir.UnOpExpr) ir.Repr {
+	expr.Expr.Accept(codeGen)
+
+	var name string
+	switch expr.Op {
+	case ir.UnOpNegate:
+		name = RuntimeFunctionNameNegate
+	case ir.UnOpMinus:
+		name = RuntimeFunctionNameMinus
+	case ir.UnOpForce:
+		name = RuntimeFunctionNameForce
+	case ir.UnOpIsNil:
+		name = RuntimeFunctionNameIsNil
+	case ir.UnOpSome:
+		name = RuntimeFunctionNameSome
+	case ir.UnOpTransfer:
+		name = RuntimeFunctionNameTransfer
+	default:
+		panic(errors.NewUnreachableError())
+	}
+
+	codeGen.emitRuntimeCall(name)
+	return nil
 }
 
 func (codeGen *wasmCodeGen) VisitBinOpExpr(expr *ir.BinOpExpr) ir.Repr {
 	expr.Left.Accept(codeGen)
 	expr.Right.Accept(codeGen)
-	// TODO: add remaining operations, take types into account
+
+	var name string
 	switch expr.Op {
 	case ir.BinOpPlus:
-		codeGen.emit(wasm.InstructionCall{
-			FuncIndex: codeGen.runtimeFunctionIndexAdd,
-		})
-		return nil
+		name = RuntimeFunctionNameAdd
+	case ir.BinOpMinus:
+		name = RuntimeFunctionNameSubtract
+	case ir.BinOpMul:
+		name = RuntimeFunctionNameMultiply
+	case ir.BinOpDiv:
+		name = RuntimeFunctionNameDivide
+	case ir.BinOpMod:
+		name = RuntimeFunctionNameMod
+	case ir.BinOpLess:
+		name = RuntimeFunctionNameLess
+	case ir.BinOpLessEqual:
+		name = RuntimeFunctionNameLessEqual
+	case ir.BinOpGreater:
+		name = RuntimeFunctionNameGreater
+	case ir.BinOpGreaterEqual:
+		name = RuntimeFunctionNameGreaterEqual
+	case ir.BinOpEqual:
+		name = RuntimeFunctionNameEqual
+	case ir.BinOpNotEqual:
+		name = RuntimeFunctionNameNotEqual
+	case ir.BinOpBitwiseOr:
+		name = RuntimeFunctionNameBitwiseOr
+	case ir.BinOpBitwiseXor:
+		name = RuntimeFunctionNameBitwiseXor
+	case ir.BinOpBitwiseAnd:
+		name = RuntimeFunctionNameBitwiseAnd
+	case ir.BinOpBitwiseLeftShift:
+		name = RuntimeFunctionNameBitwiseLeftShift
+	case ir.BinOpBitwiseRightShift:
+		name = RuntimeFunctionNameBitwiseRightShift
+	default:
+		panic(errors.NewUnreachableError())
 	}
-	panic(errors.NewUnreachableError())
+
+	codeGen.emitRuntimeCall(name)
+	return nil
 }
 
-func (codeGen *wasmCodeGen) VisitCall(_ *ir.Call) ir.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (codeGen *wasmCodeGen) VisitCall(call *ir.Call) ir.Repr {
+	for _, argument := range call.Arguments {
+		argument.Accept(codeGen)
+	}
+
+	// Like in the interpreter, invocations are reported by the caller
+
+	codeGen.emitRuntimeCall(RuntimeFunctionNameReportFunctionInvocation)
+	codeGen.emit(wasm.InstructionCall{
+		FuncIndex: codeGen.funcIndexOffset + call.FunctionIndex,
+	})
+	codeGen.emitRuntimeCall(RuntimeFunctionNameReportFunctionReturn)
+
+	// Calls are expressions, so they must result in a value.
+	// Functions without a result return void
+
+	f := codeGen.funcs[call.FunctionIndex]
+	if len(f.Type.Results) == 0 {
+		codeGen.emitRuntimeCall(RuntimeFunctionNameVoid)
+	}
+
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitGlobal(global *ir.Global) ir.Repr {
+	codeGen.emitConstantCall(
+		RuntimeFunctionNameGlobal,
+		[]byte(global.Name),
+	)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitConditional(conditional *ir.Conditional) ir.Repr {
+	codeGen.emitTest(conditional.Test)
+
+	thenInstructions := codeGen.generateInstructions(func() {
+		conditional.Then.Accept(codeGen)
+	})
+
+	elseInstructions := codeGen.generateInstructions(func() {
+		conditional.Else.Accept(codeGen)
+	})
+
+	codeGen.emit(wasm.InstructionIf{
+		Block: wasm.Block{
+			BlockType:     wasm.ValueTypeExternRef,
+			Instructions1: thenInstructions,
+			Instructions2: elseInstructions,
+		},
+	})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitInvoke(invoke *ir.Invoke) ir.Repr {
+	invoke.Function.Accept(codeGen)
+	codeGen.emitList(invoke.Arguments)
+	codeGen.emitRuntimeCall(RuntimeFunctionNameInvoke)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitMember(member *ir.Member) ir.Repr {
+	member.Exp.Accept(codeGen)
+	codeGen.emitConstant([]byte(member.Name))
+	codeGen.emitRuntimeCall(RuntimeFunctionNameGetMember)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitIndex(index *ir.Index) ir.Repr {
+	index.Exp.Accept(codeGen)
+	index.Index.Accept(codeGen)
+	codeGen.emitRuntimeCall(RuntimeFunctionNameGetIndex)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitArray(array *ir.Array) ir.Repr {
+	codeGen.emitList(array.Elements)
+	codeGen.emitTypeConstant(array.Type)
+	codeGen.emitRuntimeCall(RuntimeFunctionNameArray)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitDictionary(dictionary *ir.Dictionary) ir.Repr {
+	// The entries are passed as a list of alternating keys and values
+	keysAndValues := make([]ir.Expr, 0, len(dictionary.Entries)*2)
+	for _, entry := range dictionary.Entries {
+		keysAndValues = append(keysAndValues, entry.Key, entry.Value)
+	}
+	codeGen.emitList(keysAndValues)
+	codeGen.emitTypeConstant(dictionary.Type)
+	codeGen.emitRuntimeCall(RuntimeFunctionNameDictionary)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitConvert(convert *ir.Convert) ir.Repr {
+	convert.Exp.Accept(codeGen)
+	codeGen.emitTypeConstant(convert.Type)
+	codeGen.emitRuntimeCall(RuntimeFunctionNameConvert)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitFailableCast(cast *ir.FailableCast) ir.Repr {
+	cast.Exp.Accept(codeGen)
+	codeGen.emitTypeConstant(cast.Type)
+	codeGen.emitRuntimeCall(RuntimeFunctionNameFailableCast)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitForceCast(cast *ir.ForceCast) ir.Repr {
+	cast.Exp.Accept(codeGen)
+	codeGen.emitTypeConstant(cast.Type)
+	codeGen.emitRuntimeCall(RuntimeFunctionNameForceCast)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitReference(reference *ir.Reference) ir.Repr {
+	reference.Exp.Accept(codeGen)
+	codeGen.emitTypeConstant(reference.Type)
+	codeGen.emitRuntimeCall(RuntimeFunctionNameReference)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitDestroy(destroy *ir.Destroy) ir.Repr {
+	destroy.Exp.Accept(codeGen)
+	codeGen.emitRuntimeCall(RuntimeFunctionNameDestroy)
+	return nil
 }
 
 func (codeGen *wasmCodeGen) VisitFunc(f *ir.Func) ir.Repr {
 	codeGen.code = &wasm.Code{}
 	codeGen.code.Locals = generateWasmLocalTypes(f.Locals)
+
+	codeGen.instructions = nil
 	f.Statement.Accept(codeGen)
+
+	// Semantic analysis ensures that all paths of functions with a result return.
+	// Make this explicit, so the end of the function is valid
+	if len(f.Type.Results) > 0 {
+		codeGen.emit(wasm.InstructionUnreachable{})
+	}
+
+	codeGen.code.Instructions = codeGen.instructions
+	codeGen.instructions = nil
+
 	functionType := generateWasmFunctionType(f.Type)
 	funcIndex := codeGen.mod.AddFunction(f.Name, functionType, codeGen.code)
 	// TODO: make export dependent on visibility modifier
@@ -162,55 +500,104 @@ func (codeGen *wasmCodeGen) VisitFunc(f *ir.Func) ir.Repr {
 }
 
 func (codeGen *wasmCodeGen) emit(inst wasm.Instruction) {
-	codeGen.code.Instructions = append(codeGen.code.Instructions, inst)
+	codeGen.instructions = append(codeGen.instructions, inst)
+}
+
+// generateInstructions returns the instructions emitted by the given function,
+// e.g. to generate the instructions of a nested block
+func (codeGen *wasmCodeGen) generateInstructions(f func()) []wasm.Instruction {
+	previous := codeGen.instructions
+	codeGen.instructions = nil
+	f()
+	result := codeGen.instructions
+	codeGen.instructions = previous
+	return result
+}
+
+func (codeGen *wasmCodeGen) generateStmts(stmts []ir.Stmt) []wasm.Instruction {
+	return codeGen.generateInstructions(func() {
+		for _, stmt := range stmts {
+			stmt.Accept(codeGen)
+		}
+	})
+}
+
+// emitTest emits the given expression, which must result in a boolean value,
+// and converts the result to an i32, so it can be used in a branch
+func (codeGen *wasmCodeGen) emitTest(test ir.Expr) {
+	test.Accept(codeGen)
+	codeGen.emitRuntimeCall(RuntimeFunctionNameIsTrue)
+}
+
+// emitList emits a new list of the given values
+func (codeGen *wasmCodeGen) emitList(values []ir.Expr) {
+	codeGen.emitRuntimeCall(RuntimeFunctionNameNewList)
+	for _, value := range values {
+		value.Accept(codeGen)
+		codeGen.emitRuntimeCall(RuntimeFunctionNameAppendList)
+	}
+}
+
+func (codeGen *wasmCodeGen) emitRuntimeCall(name string) {
+	funcIndex, ok := codeGen.runtimeFunctionIndices[name]
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+	codeGen.emit(wasm.InstructionCall{FuncIndex: funcIndex})
 }
 
 func (codeGen *wasmCodeGen) addConstant(value []byte) uint32 {
+	// Constants are only added once
+	key := string(value)
+	if offset, ok := codeGen.constantOffsets[key]; ok {
+		return offset
+	}
+
 	offset := codeGen.mod.RequireMemory(uint32(len(value)))
 	// TODO: optimize:
 	//   let module builder generate one data entry of all constants,
 	//   instead of one data entry for each constant
 	codeGen.mod.AddData(offset, value)
+
+	if codeGen.constantOffsets == nil {
+		codeGen.constantOffsets = map[string]uint32{}
+	}
+	codeGen.constantOffsets[key] = offset
+
 	return offset
 }
 
-func (codeGen *wasmCodeGen) emitConstantCall(funcIndex uint32, value []byte) {
+// emitConstant emits the memory offset and the length of the given constant
+func (codeGen *wasmCodeGen) emitConstant(value []byte) {
 	memoryOffset := codeGen.addConstant(value)
 	codeGen.emit(wasm.InstructionI32Const{Value: int32(memoryOffset)})
 
 	length := int32(len(value))
 	codeGen.emit(wasm.InstructionI32Const{Value: length})
-
-	codeGen.emit(wasm.InstructionCall{FuncIndex: funcIndex})
 }
 
-var constantFunctionType = &wasm.FunctionType{
-	Params: []wasm.ValueType{
-		// memory offset
-		wasm.ValueTypeI32,
-		// length
-		wasm.ValueTypeI32,
-	},
-	Results: []wasm.ValueType{
-		wasm.ValueTypeExternRef,
-	},
+func (codeGen *wasmCodeGen) emitConstantCall(name string, value []byte) {
+	codeGen.emitConstant(value)
+	codeGen.emitRuntimeCall(name)
 }
 
-var addFunctionType = &wasm.FunctionType{
-	Params: []wasm.ValueType{
-		wasm.ValueTypeExternRef,
-		wasm.ValueTypeExternRef,
-	},
-	Results: []wasm.ValueType{
-		wasm.ValueTypeExternRef,
-	},
+// emitTypeConstant emits the given type as a constant,
+// encoded as a static type
+func (codeGen *wasmCodeGen) emitTypeConstant(ty sema.Type) {
+	staticType := interpreter.ConvertSemaToStaticType(nil, ty)
+	encoded, err := interpreter.StaticTypeToBytes(staticType)
+	if err != nil {
+		panic(fmt.Errorf("failed to encode type %s: %w", ty, err))
+	}
+	codeGen.emitConstant(encoded)
 }
 
 func (codeGen *wasmCodeGen) addRuntimeImports() {
-	// NOTE: ensure to update the imports in the vm
-	codeGen.runtimeFunctionIndexInt = codeGen.addRuntimeImport("Int", constantFunctionType)
-	codeGen.runtimeFunctionIndexString = codeGen.addRuntimeImport("String", constantFunctionType)
-	codeGen.runtimeFunctionIndexAdd = codeGen.addRuntimeImport("add", addFunctionType)
+	codeGen.runtimeFunctionIndices = make(map[string]uint32, len(RuntimeFunctions))
+	for _, runtimeFunction := range RuntimeFunctions {
+		codeGen.runtimeFunctionIndices[runtimeFunction.Name] =
+			codeGen.addRuntimeImport(runtimeFunction.Name, runtimeFunction.Type)
+	}
 }
 
 func (codeGen *wasmCodeGen) addRuntimeImport(name string, funcType *wasm.FunctionType) uint32 {
@@ -221,13 +608,20 @@ func (codeGen *wasmCodeGen) addRuntimeImport(name string, funcType *wasm.Functio
 	return funcIndex
 }
 
+// GenerateWasm generates a WebAssembly module for the given functions.
+// The index of a function in the given slice is its function index,
+// i.e. calls refer to it by this index
 func GenerateWasm(funcs []*ir.Func) *wasm.Module {
 	g := &wasmCodeGen{
-		mod: &wasm.ModuleBuilder{},
+		mod:   &wasm.ModuleBuilder{},
+		funcs: funcs,
 	}
 
 	g.addRuntimeImports()
 
+	// Function indices include function imports
+	g.funcIndexOffset = uint32(len(RuntimeFunctions))
+
 	for _, f := range funcs {
 		f.Accept(g)
 	}
@@ -246,10 +640,13 @@ func generateWasmLocalTypes(locals []ir.Local) []wasm.ValueType {
 }
 
 func generateWasmValType(valType ir.ValType) wasm.ValueType {
-	// TODO: add remaining types
+	// NOTE: all values are represented as external references
 	switch valType {
 	case ir.ValTypeInt,
-		ir.ValTypeString:
+		ir.ValTypeString,
+		ir.ValTypeBool,
+		ir.ValTypeNumber,
+		ir.ValTypeValue:
 
 		return wasm.ValueTypeExternRef
 	}
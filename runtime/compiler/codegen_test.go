@@ -29,8 +29,6 @@ import (
 
 func TestWasmCodeGenSimple(t *testing.T) {
 
-	t.Skip("WIP")
-
 	mod := GenerateWasm([]*ir.Func{
 		{
 			Name: "inc",
@@ -70,70 +68,43 @@ func TestWasmCodeGenSimple(t *testing.T) {
 		},
 	})
 
+	// All runtime functions are imported, in order,
+	// and the compiled functions follow
+
+	var expectedTypes []*wasm.FunctionType
+	var expectedImports []*wasm.Import
+	runtimeFunctionIndices := map[string]uint32{}
+
+	for i, runtimeFunction := range RuntimeFunctions {
+		expectedTypes = append(expectedTypes, runtimeFunction.Type)
+		expectedImports = append(expectedImports, &wasm.Import{
+			Module:    RuntimeModuleName,
+			Name:      runtimeFunction.Name,
+			TypeIndex: uint32(i),
+		})
+		runtimeFunctionIndices[runtimeFunction.Name] = uint32(i)
+	}
+
+	// function type of inc
+	expectedTypes = append(expectedTypes, &wasm.FunctionType{
+		Params: []wasm.ValueType{
+			wasm.ValueTypeExternRef,
+		},
+		Results: []wasm.ValueType{
+			wasm.ValueTypeExternRef,
+		},
+	})
+
+	incIndex := uint32(len(RuntimeFunctions))
+
 	require.Equal(t,
 		&wasm.Module{
-			Types: []*wasm.FunctionType{
-				// function type of crt.Int
-				{
-					Params: []wasm.ValueType{
-						wasm.ValueTypeI32,
-						wasm.ValueTypeI32,
-					},
-					Results: []wasm.ValueType{
-						wasm.ValueTypeExternRef,
-					},
-				},
-				// function type of crt.String
-				{
-					Params: []wasm.ValueType{
-						wasm.ValueTypeI32,
-						wasm.ValueTypeI32,
-					},
-					Results: []wasm.ValueType{
-						wasm.ValueTypeExternRef,
-					},
-				},
-				// function type of add
-				{
-					Params: []wasm.ValueType{
-						wasm.ValueTypeExternRef,
-						wasm.ValueTypeExternRef,
-					},
-					Results: []wasm.ValueType{
-						wasm.ValueTypeExternRef,
-					},
-				},
-				// function type of inc
-				{
-					Params: []wasm.ValueType{
-						wasm.ValueTypeExternRef,
-					},
-					Results: []wasm.ValueType{
-						wasm.ValueTypeExternRef,
-					},
-				},
-			},
-			Imports: []*wasm.Import{
-				{
-					Module:    RuntimeModuleName,
-					Name:      "Int",
-					TypeIndex: 0,
-				},
-				{
-					Module:    RuntimeModuleName,
-					Name:      "String",
-					TypeIndex: 1,
-				},
-				{
-					Module:    RuntimeModuleName,
-					Name:      "add",
-					TypeIndex: 2,
-				},
-			},
+			Types:   expectedTypes,
+			Imports: expectedImports,
 			Functions: []*wasm.Function{
 				{
 					Name:      "inc",
-					TypeIndex: 3,
+					TypeIndex: incIndex,
 					Code: &wasm.Code{
 						Locals: []wasm.ValueType{
 							wasm.ValueTypeExternRef,
@@ -142,12 +113,17 @@ func TestWasmCodeGenSimple(t *testing.T) {
 						Instructions: []wasm.Instruction{
 							wasm.InstructionI32Const{Value: 0},
 							wasm.InstructionI32Const{Value: 2},
-							wasm.InstructionCall{FuncIndex: 0},
+							wasm.InstructionCall{
+								FuncIndex: runtimeFunctionIndices[RuntimeFunctionNameInt],
+							},
 							wasm.InstructionLocalSet{LocalIndex: 1},
 							wasm.InstructionLocalGet{LocalIndex: 0},
 							wasm.InstructionLocalGet{LocalIndex: 1},
-							wasm.InstructionCall{FuncIndex: 2},
+							wasm.InstructionCall{
+								FuncIndex: runtimeFunctionIndices[RuntimeFunctionNameAdd],
+							},
 							wasm.InstructionReturn{},
+							wasm.InstructionUnreachable{},
 						},
 					},
 				},
@@ -177,7 +153,7 @@ func TestWasmCodeGenSimple(t *testing.T) {
 				{
 					Name: "inc",
 					Descriptor: wasm.FunctionExport{
-						FunctionIndex: 3,
+						FunctionIndex: incIndex,
 					},
 				},
 				{
@@ -195,6 +171,4 @@ func TestWasmCodeGenSimple(t *testing.T) {
 	w := wasm.NewWASMWriter(&buf)
 	err := w.WriteModule(mod)
 	require.NoError(t, err)
-
-	_ = wasm.WASM2WAT(buf.Bytes())
 }
@@ -19,17 +19,53 @@
 package compiler
 
 import (
+	"math/big"
+
+	"github.com/onflow/cadence/fixedpoint"
 	"github.com/onflow/cadence/runtime/activations"
 	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/compiler/ir"
 	"github.com/onflow/cadence/runtime/errors"
 	"github.com/onflow/cadence/runtime/sema"
 )
 
 type Compiler struct {
-	Checker     *sema.Checker
-	activations *activations.Activations[*Local]
-	locals      []*Local
+	Elaboration *sema.Elaboration
+	// InterpretedComposites specifies if composite and interface declarations are left to the interpreter,
+	// i.e. compiled code creates composites and invokes their functions through the interpreter.
+	// If false, programs which declare composites or interfaces are not supported
+	InterpretedComposites bool
+	activations           *activations.Activations[*Local]
+	locals                []*Local
+	functionIndices       map[string]uint32
+	// depth is the number of labels (blocks, loops and ifs)
+	// enclosing the currently compiled statement
+	depth        uint32
+	jumpTargets  []jumpTarget
+	returnTarget *returnTarget
+	inFunction   bool
+	// transactionFields are the locals which store the fields of the compiled transaction,
+	// which are accessed as members of `self`
+	transactionFields map[string]*Local
+}
+
+// TransactionFunctionName is the name of the function which a transaction declaration is compiled to
+const TransactionFunctionName = "$transaction"
+
+// jumpTarget is the target of break and continue statements,
+// i.e. a loop or a switch statement
+type jumpTarget struct {
+	breakDepth    uint32
+	continueDepth uint32
+	isLoop        bool
+}
+
+// returnTarget is the target of return statements
+// in a function with post-conditions
+type returnTarget struct {
+	resultLocal *Local
+	depth       uint32
 }
 
 var _ ast.DeclarationVisitor[ir.Stmt] = &Compiler{}
@@ -37,8 +73,14 @@ var _ ast.StatementVisitor[ir.Stmt] = &Compiler{}
 var _ ast.ExpressionVisitor[ir.Expr] = &Compiler{}
 
 func NewCompiler(checker *sema.Checker) *Compiler {
+	return NewElaborationCompiler(checker.Elaboration)
+}
+
+// NewElaborationCompiler returns a new compiler for a program
+// which was checked and resulted in the given elaboration
+func NewElaborationCompiler(elaboration *sema.Elaboration) *Compiler {
 	return &Compiler{
-		Checker:     checker,
+		Elaboration: elaboration,
 		activations: activations.NewActivations[*Local](nil),
 	}
 }
@@ -46,10 +88,17 @@ func NewCompiler(checker *sema.Checker) *Compiler {
 // declareLocal declares a local
 func (compiler *Compiler) declareLocal(identifier string, valType ir.ValType) *Local {
 	// NOTE: semantic analysis already checked possible invalid redeclaration
+	local := compiler.declareTemporary(valType)
+	compiler.setLocal(identifier, local)
+	return local
+}
+
+// declareTemporary declares a local which has no name,
+// e.g. to hold an intermediate value
+func (compiler *Compiler) declareTemporary(valType ir.ValType) *Local {
 	index := uint32(len(compiler.locals))
 	local := NewLocal(index, valType)
 	compiler.locals = append(compiler.locals, local)
-	compiler.setLocal(identifier, local)
 	return local
 }
 
@@ -61,64 +110,566 @@ func (compiler *Compiler) setLocal(name string, variable *Local) {
 	compiler.activations.Set(name, variable)
 }
 
+func (compiler *Compiler) compileExpression(expression ast.Expression) ir.Expr {
+	return ast.AcceptExpression[ir.Expr](expression, compiler)
+}
+
+func (compiler *Compiler) compileStatement(statement ast.Statement) ir.Stmt {
+	return ast.AcceptStatement[ir.Stmt](statement, compiler)
+}
+
+// compileStatements compiles the given statements.
+// Like in the interpreter, the execution of each statement is reported
+func (compiler *Compiler) compileStatements(statements []ast.Statement) []ir.Stmt {
+	stmts := make([]ir.Stmt, 0, len(statements)*2)
+	for _, statement := range statements {
+		stmts = append(stmts,
+			&ir.StatementStart{
+				Range: ast.NewUnmeteredRangeFromPositioned(statement),
+			},
+			compiler.compileStatement(statement),
+		)
+	}
+	return stmts
+}
+
+// enterLabel must be called when compiling a statement which introduces a label,
+// i.e. a block, loop, or if statement.
+// The returned function must be called when leaving the label
+func (compiler *Compiler) enterLabel() (leave func()) {
+	compiler.depth++
+	return func() {
+		compiler.depth--
+	}
+}
+
+// transferAndConvert transfers the value of the given expression,
+// and converts it from the given value type to the given target type,
+// if needed
+func (compiler *Compiler) transferAndConvert(exp ir.Expr, valueType, targetType sema.Type) ir.Expr {
+	if !isTransferFree(valueType) {
+		exp = &ir.UnOpExpr{
+			Op:   ir.UnOpTransfer,
+			Expr: exp,
+		}
+	}
+
+	if needsConversion(valueType, targetType) {
+		exp = &ir.Convert{
+			Exp:  exp,
+			Type: targetType,
+		}
+	}
+
+	return exp
+}
+
 func (compiler *Compiler) VisitReturnStatement(statement *ast.ReturnStatement) ir.Stmt {
-	exp := ast.AcceptExpression[ir.Expr](statement.Expression, compiler)
+	var exp ir.Expr
+	if statement.Expression != nil {
+		exp = compiler.compileExpression(statement.Expression)
+
+		returnStatementTypes := compiler.Elaboration.ReturnStatementTypes(statement)
+		exp = compiler.transferAndConvert(
+			exp,
+			returnStatementTypes.ValueType,
+			returnStatementTypes.ReturnType,
+		)
+	}
+
+	// If the function has post-conditions,
+	// store the result and branch to the post-conditions
+
+	returnTarget := compiler.returnTarget
+	if returnTarget != nil {
+		var stmts []ir.Stmt
+		if returnTarget.resultLocal != nil && exp != nil {
+			stmts = append(stmts,
+				&ir.StoreLocal{
+					LocalIndex: returnTarget.resultLocal.Index,
+					Exp:        exp,
+				},
+			)
+		}
+		stmts = append(stmts,
+			&ir.Branch{
+				Index: compiler.depth - returnTarget.depth,
+			},
+		)
+		return &ir.Sequence{
+			Stmts: stmts,
+		}
+	}
+
 	return &ir.Return{
 		Exp: exp,
 	}
 }
 
 func (compiler *Compiler) VisitBreakStatement(_ *ast.BreakStatement) ir.Stmt {
-	// TODO
-	panic(errors.NewUnreachableError())
+	// NOTE: semantic analysis already checked that the statement is in a loop or switch
+	target := compiler.jumpTargets[len(compiler.jumpTargets)-1]
+	return &ir.Branch{
+		Index: compiler.depth - target.breakDepth,
+	}
 }
 
 func (compiler *Compiler) VisitContinueStatement(_ *ast.ContinueStatement) ir.Stmt {
-	// TODO
+	// NOTE: semantic analysis already checked that the statement is in a loop.
+	// Switch statements are not targets of continue statements
+	for i := len(compiler.jumpTargets) - 1; i >= 0; i-- {
+		target := compiler.jumpTargets[i]
+		if !target.isLoop {
+			continue
+		}
+		return &ir.Branch{
+			Index: compiler.depth - target.continueDepth,
+		}
+	}
+
 	panic(errors.NewUnreachableError())
 }
 
-func (compiler *Compiler) VisitIfStatement(_ *ast.IfStatement) ir.Stmt {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) VisitIfStatement(statement *ast.IfStatement) ir.Stmt {
+	switch test := statement.Test.(type) {
+	case ast.Expression:
+		return compiler.visitIfStatementWithTestExpression(test, statement.Then, statement.Else)
+	case *ast.VariableDeclaration:
+		return compiler.visitIfStatementWithVariableDeclaration(test, statement.Then, statement.Else)
+	default:
+		panic(errors.NewUnreachableError())
+	}
 }
 
-func (compiler *Compiler) VisitWhileStatement(_ *ast.WhileStatement) ir.Stmt {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) visitIfStatementWithTestExpression(
+	test ast.Expression,
+	thenBlock, elseBlock *ast.Block,
+) ir.Stmt {
+	testExp := compiler.compileExpression(test)
+
+	leave := compiler.enterLabel()
+	defer leave()
+
+	thenStmt := compiler.visitBlock(thenBlock)
+
+	var elseStmt ir.Stmt
+	if elseBlock != nil {
+		elseStmt = compiler.visitBlock(elseBlock)
+	}
+
+	return &ir.If{
+		Test: testExp,
+		Then: thenStmt,
+		Else: elseStmt,
+	}
 }
 
-func (compiler *Compiler) VisitForStatement(_ *ast.ForStatement) ir.Stmt {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) visitIfStatementWithVariableDeclaration(
+	declaration *ast.VariableDeclaration,
+	thenBlock, elseBlock *ast.Block,
+) ir.Stmt {
+
+	// TODO: potential storage removal
+	// TODO: second value
+
+	variableDeclarationTypes := compiler.Elaboration.VariableDeclarationTypes(declaration)
+
+	// Evaluate the optional value into a temporary local
+
+	optionalLocal := compiler.declareTemporary(ir.ValTypeValue)
+	storeOptional := &ir.StoreLocal{
+		LocalIndex: optionalLocal.Index,
+		Exp:        compiler.compileExpression(declaration.Value),
+	}
+
+	leave := compiler.enterLabel()
+	defer leave()
+
+	// If the optional is not nil, declare the variable
+	// in a new scope, bound to the unwrapped value
+
+	compiler.activations.PushNewWithCurrent()
+
+	targetType := variableDeclarationTypes.TargetType
+	local := compiler.declareLocal(
+		declaration.Identifier.Identifier,
+		compileValueType(targetType),
+	)
+
+	var valueType sema.Type
+	if optionalType, ok := variableDeclarationTypes.ValueType.(*sema.OptionalType); ok {
+		valueType = optionalType.Type
+	}
+
+	storeUnwrapped := &ir.StoreLocal{
+		LocalIndex: local.Index,
+		Exp: compiler.transferAndConvert(
+			&ir.UnOpExpr{
+				Op: ir.UnOpForce,
+				Expr: &ir.CopyLocal{
+					LocalIndex: optionalLocal.Index,
+				},
+			},
+			valueType,
+			targetType,
+		),
+	}
+
+	thenStmt := &ir.Sequence{
+		Stmts: []ir.Stmt{
+			storeUnwrapped,
+			compiler.visitBlock(thenBlock),
+		},
+	}
+
+	compiler.activations.Pop()
+
+	var elseStmt ir.Stmt
+	if elseBlock != nil {
+		elseStmt = compiler.visitBlock(elseBlock)
+	}
+
+	return &ir.Sequence{
+		Stmts: []ir.Stmt{
+			storeOptional,
+			&ir.If{
+				Test: &ir.UnOpExpr{
+					Op: ir.UnOpNegate,
+					Expr: &ir.UnOpExpr{
+						Op: ir.UnOpIsNil,
+						Expr: &ir.CopyLocal{
+							LocalIndex: optionalLocal.Index,
+						},
+					},
+				},
+				Then: thenStmt,
+				Else: elseStmt,
+			},
+		},
+	}
 }
 
-func (compiler *Compiler) VisitEmitStatement(_ *ast.EmitStatement) ir.Stmt {
-	// TODO
-	panic(errors.NewUnreachableError())
+// compileLoop compiles a loop with the given body.
+//
+// The header is compiled at the start of each iteration,
+// and may branch out of the loop using the given index.
+// Like in the interpreter, the iteration is reported after the header,
+// i.e. once it is known that the body is executed.
+// The footer is compiled at the end of each iteration,
+// i.e. after the body, and also after continue statements.
+//
+// The generated IR has the following structure:
+//
+//	block            ;; target of break
+//	  loop           ;; start of iteration
+//	    header
+//	    iteration
+//	    block        ;; target of continue
+//	      body
+//	    end
+//	    footer
+//	    br 0         ;; next iteration
+//	  end
+//	end
+func (compiler *Compiler) compileLoop(
+	compileHeader func(breakIndex uint32) []ir.Stmt,
+	body *ast.Block,
+	compileFooter func() []ir.Stmt,
+) ir.Stmt {
+
+	leaveBreak := compiler.enterLabel()
+	defer leaveBreak()
+
+	breakDepth := compiler.depth
+
+	leaveLoop := compiler.enterLabel()
+	defer leaveLoop()
+
+	var stmts []ir.Stmt
+	if compileHeader != nil {
+		stmts = append(stmts, compileHeader(compiler.depth-breakDepth)...)
+	}
+
+	stmts = append(stmts, &ir.LoopIteration{})
+
+	leaveContinue := compiler.enterLabel()
+
+	compiler.jumpTargets = append(
+		compiler.jumpTargets,
+		jumpTarget{
+			breakDepth:    breakDepth,
+			continueDepth: compiler.depth,
+			isLoop:        true,
+		},
+	)
+
+	bodyStmt := compiler.visitBlock(body)
+
+	compiler.jumpTargets = compiler.jumpTargets[:len(compiler.jumpTargets)-1]
+
+	leaveContinue()
+
+	stmts = append(stmts,
+		&ir.Block{
+			Stmts: []ir.Stmt{
+				bodyStmt,
+			},
+		},
+	)
+
+	if compileFooter != nil {
+		stmts = append(stmts, compileFooter()...)
+	}
+
+	stmts = append(stmts,
+		&ir.Branch{
+			Index: 0,
+		},
+	)
+
+	return &ir.Block{
+		Stmts: []ir.Stmt{
+			&ir.Loop{
+				Stmts: stmts,
+			},
+		},
+	}
+}
+
+func (compiler *Compiler) VisitWhileStatement(statement *ast.WhileStatement) ir.Stmt {
+	return compiler.compileLoop(
+		func(breakIndex uint32) []ir.Stmt {
+			return []ir.Stmt{
+				&ir.BranchIf{
+					Exp: &ir.UnOpExpr{
+						Op:   ir.UnOpNegate,
+						Expr: compiler.compileExpression(statement.Test),
+					},
+					Index: breakIndex,
+				},
+			}
+		},
+		statement.Block,
+		nil,
+	)
+}
+
+func (compiler *Compiler) VisitForStatement(statement *ast.ForStatement) ir.Stmt {
+
+	// The iterated value (an array or a string) is indexed,
+	// from the first to the last element / character
+
+	compiler.activations.PushNewWithCurrent()
+	defer compiler.activations.Pop()
+
+	// Evaluate and transfer the iterated value
+
+	valueLocal := compiler.declareTemporary(ir.ValTypeValue)
+	storeValue := &ir.StoreLocal{
+		LocalIndex: valueLocal.Index,
+		Exp: &ir.UnOpExpr{
+			Op:   ir.UnOpTransfer,
+			Expr: compiler.compileExpression(statement.Value),
+		},
+	}
+
+	// Initialize the index and determine the count
+
+	var indexLocal *Local
+	if statement.Index != nil {
+		indexLocal = compiler.declareLocal(statement.Index.Identifier, ir.ValTypeInt)
+	} else {
+		indexLocal = compiler.declareTemporary(ir.ValTypeInt)
+	}
+
+	storeIndex := &ir.StoreLocal{
+		LocalIndex: indexLocal.Index,
+		Exp:        compileIntConstant(big.NewInt(0)),
+	}
+
+	countLocal := compiler.declareTemporary(ir.ValTypeInt)
+	storeCount := &ir.StoreLocal{
+		LocalIndex: countLocal.Index,
+		Exp: &ir.Member{
+			Exp: &ir.CopyLocal{
+				LocalIndex: valueLocal.Index,
+			},
+			Name: "length",
+		},
+	}
+
+	elementLocal := compiler.declareLocal(statement.Identifier.Identifier, ir.ValTypeValue)
+
+	loop := compiler.compileLoop(
+		func(breakIndex uint32) []ir.Stmt {
+			return []ir.Stmt{
+				// Exit the loop if all elements have been iterated
+				&ir.BranchIf{
+					Exp: &ir.BinOpExpr{
+						Op: ir.BinOpGreaterEqual,
+						Left: &ir.CopyLocal{
+							LocalIndex: indexLocal.Index,
+						},
+						Right: &ir.CopyLocal{
+							LocalIndex: countLocal.Index,
+						},
+					},
+					Index: breakIndex,
+				},
+				// Bind the current element
+				&ir.StoreLocal{
+					LocalIndex: elementLocal.Index,
+					Exp: &ir.Index{
+						Exp: &ir.CopyLocal{
+							LocalIndex: valueLocal.Index,
+						},
+						Index: &ir.CopyLocal{
+							LocalIndex: indexLocal.Index,
+						},
+					},
+				},
+			}
+		},
+		statement.Block,
+		func() []ir.Stmt {
+			return []ir.Stmt{
+				// Advance to the next element
+				&ir.StoreLocal{
+					LocalIndex: indexLocal.Index,
+					Exp: &ir.BinOpExpr{
+						Op: ir.BinOpPlus,
+						Left: &ir.CopyLocal{
+							LocalIndex: indexLocal.Index,
+						},
+						Right: compileIntConstant(big.NewInt(1)),
+					},
+				},
+			}
+		},
+	)
+
+	return &ir.Sequence{
+		Stmts: []ir.Stmt{
+			storeValue,
+			storeIndex,
+			storeCount,
+			loop,
+		},
+	}
+}
+
+func (compiler *Compiler) VisitEmitStatement(statement *ast.EmitStatement) ir.Stmt {
+	eventType := compiler.Elaboration.EmitStatementEventType(statement)
+	return &ir.Emit{
+		Exp:  compiler.compileExpression(statement.InvocationExpression),
+		Type: eventType,
+	}
 }
 
 func (compiler *Compiler) VisitRemoveStatement(_ *ast.RemoveStatement) ir.Stmt {
-	// TODO
-	panic(errors.NewUnreachableError())
+	panic(UnsupportedError{
+		Feature: "attachment removal",
+	})
 }
 
-func (compiler *Compiler) VisitSwitchStatement(_ *ast.SwitchStatement) ir.Stmt {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) VisitSwitchStatement(statement *ast.SwitchStatement) ir.Stmt {
+
+	// The switch statement is compiled to a block,
+	// which is the target of break statements,
+	// and which contains a chain of if statements,
+	// one for each case
+
+	leave := compiler.enterLabel()
+	defer leave()
+
+	compiler.jumpTargets = append(
+		compiler.jumpTargets,
+		jumpTarget{
+			breakDepth: compiler.depth,
+		},
+	)
+	defer func() {
+		compiler.jumpTargets = compiler.jumpTargets[:len(compiler.jumpTargets)-1]
+	}()
+
+	testLocal := compiler.declareTemporary(ir.ValTypeValue)
+
+	stmts := []ir.Stmt{
+		&ir.StoreLocal{
+			LocalIndex: testLocal.Index,
+			Exp:        compiler.compileExpression(statement.Expression),
+		},
+	}
+
+	cases := compiler.compileSwitchCases(testLocal, statement.Cases)
+	if cases != nil {
+		stmts = append(stmts, cases)
+	}
+
+	return &ir.Block{
+		Stmts: stmts,
+	}
+}
+
+func (compiler *Compiler) compileSwitchCases(testLocal *Local, cases []*ast.SwitchCase) ir.Stmt {
+	if len(cases) == 0 {
+		return nil
+	}
+
+	switchCase := cases[0]
+
+	// If the case has no expression it is the default case
+
+	if switchCase.Expression == nil {
+		return compiler.compileSwitchCaseStatements(switchCase)
+	}
+
+	test := &ir.BinOpExpr{
+		Op: ir.BinOpEqual,
+		Left: &ir.CopyLocal{
+			LocalIndex: testLocal.Index,
+		},
+		Right: compiler.compileExpression(switchCase.Expression),
+	}
+
+	leave := compiler.enterLabel()
+	defer leave()
+
+	return &ir.If{
+		Test: test,
+		Then: compiler.compileSwitchCaseStatements(switchCase),
+		Else: compiler.compileSwitchCases(testLocal, cases[1:]),
+	}
+}
+
+func (compiler *Compiler) compileSwitchCaseStatements(switchCase *ast.SwitchCase) ir.Stmt {
+	// NOTE: the new block ensures that a new scope is introduced
+	block := ast.NewBlock(
+		nil,
+		switchCase.Statements,
+		ast.EmptyRange,
+	)
+	return compiler.visitBlock(block)
 }
 
 func (compiler *Compiler) VisitVariableDeclaration(declaration *ast.VariableDeclaration) ir.Stmt {
 
 	// TODO: potential storage removal
-	// TODO: copy and convert
 	// TODO: second value
 
+	variableDeclarationTypes := compiler.Elaboration.VariableDeclarationTypes(declaration)
+	targetType := variableDeclarationTypes.TargetType
+	valueType := variableDeclarationTypes.ValueType
+
+	// NOTE: compile the value before declaring the local,
+	// as the value might refer to a shadowed variable of the same name
+
+	exp := compiler.compileExpression(declaration.Value)
+	exp = compiler.transferAndConvert(exp, valueType, targetType)
+
 	identifier := declaration.Identifier.Identifier
-	targetType := compiler.Checker.Elaboration.VariableDeclarationTypes(declaration).TargetType
 	valType := compileValueType(targetType)
 	local := compiler.declareLocal(identifier, valType)
-	exp := ast.AcceptExpression[ir.Expr](declaration.Value, compiler)
 
 	return &ir.StoreLocal{
 		LocalIndex: local.Index,
@@ -126,46 +677,266 @@ func (compiler *Compiler) VisitVariableDeclaration(declaration *ast.VariableDecl
 	}
 }
 
-func (compiler *Compiler) VisitAssignmentStatement(_ *ast.AssignmentStatement) ir.Stmt {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) VisitAssignmentStatement(statement *ast.AssignmentStatement) ir.Stmt {
+
+	// TODO: potential storage removal
+	// TODO: check target is nil for force-assignment
+
+	assignmentStatementTypes := compiler.Elaboration.AssignmentStatementTypes(statement)
+
+	target := compiler.compileAssignmentTarget(statement.Target)
+
+	value := compiler.compileExpression(statement.Value)
+	value = compiler.transferAndConvert(
+		value,
+		assignmentStatementTypes.ValueType,
+		assignmentStatementTypes.TargetType,
+	)
+
+	return &ir.Sequence{
+		Stmts: append(
+			target.setup,
+			target.set(value),
+		),
+	}
 }
 
-func (compiler *Compiler) VisitSwapStatement(_ *ast.SwapStatement) ir.Stmt {
-	// TODO
-	panic(errors.NewUnreachableError())
+// assignmentTarget is the compiled target of an assignment or swap.
+// The setup statements evaluate the sub-expressions of the target (e.g. the indexed value)
+// exactly once, so getting and setting the target does not re-evaluate them
+type assignmentTarget struct {
+	get   func() ir.Expr
+	set   func(value ir.Expr) ir.Stmt
+	setup []ir.Stmt
 }
 
-func (compiler *Compiler) VisitExpressionStatement(_ *ast.ExpressionStatement) ir.Stmt {
-	// TODO
+func (compiler *Compiler) compileAssignmentTarget(expression ast.Expression) assignmentTarget {
+	switch expression := expression.(type) {
+	case *ast.IdentifierExpression:
+		local := compiler.findLocal(expression.Identifier.Identifier)
+		if local == nil {
+			panic(UnsupportedError{
+				Feature: "assignment to global variable",
+			})
+		}
+
+		return assignmentTarget{
+			get: func() ir.Expr {
+				return &ir.CopyLocal{
+					LocalIndex: local.Index,
+				}
+			},
+			set: func(value ir.Expr) ir.Stmt {
+				return &ir.StoreLocal{
+					LocalIndex: local.Index,
+					Exp:        value,
+				}
+			},
+		}
+
+	case *ast.MemberExpression:
+		if local := compiler.transactionField(expression); local != nil {
+			return assignmentTarget{
+				get: func() ir.Expr {
+					return &ir.CopyLocal{
+						LocalIndex: local.Index,
+					}
+				},
+				set: func(value ir.Expr) ir.Stmt {
+					return &ir.StoreLocal{
+						LocalIndex: local.Index,
+						Exp:        value,
+					}
+				},
+			}
+		}
+
+		targetLocal := compiler.declareTemporary(ir.ValTypeValue)
+		name := expression.Identifier.Identifier
+
+		return assignmentTarget{
+			setup: []ir.Stmt{
+				&ir.StoreLocal{
+					LocalIndex: targetLocal.Index,
+					Exp:        compiler.compileExpression(expression.Expression),
+				},
+			},
+			get: func() ir.Expr {
+				return &ir.Member{
+					Exp: &ir.CopyLocal{
+						LocalIndex: targetLocal.Index,
+					},
+					Name: name,
+				}
+			},
+			set: func(value ir.Expr) ir.Stmt {
+				return &ir.StoreMember{
+					Exp: &ir.CopyLocal{
+						LocalIndex: targetLocal.Index,
+					},
+					Name:  name,
+					Value: value,
+				}
+			},
+		}
+
+	case *ast.IndexExpression:
+		if _, ok := compiler.Elaboration.AttachmentAccessTypes(expression); ok {
+			panic(UnsupportedError{
+				Feature: "attachment access",
+			})
+		}
+
+		targetLocal := compiler.declareTemporary(ir.ValTypeValue)
+		indexLocal := compiler.declareTemporary(ir.ValTypeValue)
+
+		return assignmentTarget{
+			setup: []ir.Stmt{
+				&ir.StoreLocal{
+					LocalIndex: targetLocal.Index,
+					Exp:        compiler.compileExpression(expression.TargetExpression),
+				},
+				&ir.StoreLocal{
+					LocalIndex: indexLocal.Index,
+					Exp:        compiler.compileExpression(expression.IndexingExpression),
+				},
+			},
+			get: func() ir.Expr {
+				return &ir.Index{
+					Exp: &ir.CopyLocal{
+						LocalIndex: targetLocal.Index,
+					},
+					Index: &ir.CopyLocal{
+						LocalIndex: indexLocal.Index,
+					},
+				}
+			},
+			set: func(value ir.Expr) ir.Stmt {
+				return &ir.StoreIndex{
+					Exp: &ir.CopyLocal{
+						LocalIndex: targetLocal.Index,
+					},
+					Index: &ir.CopyLocal{
+						LocalIndex: indexLocal.Index,
+					},
+					Value: value,
+				}
+			},
+		}
+	}
+
 	panic(errors.NewUnreachableError())
 }
 
+func (compiler *Compiler) VisitSwapStatement(statement *ast.SwapStatement) ir.Stmt {
+
+	// TODO: potential storage removal
+
+	swapStatementTypes := compiler.Elaboration.SwapStatementTypes(statement)
+	leftType := swapStatementTypes.LeftType
+	rightType := swapStatementTypes.RightType
+
+	left := compiler.compileAssignmentTarget(statement.Left)
+	right := compiler.compileAssignmentTarget(statement.Right)
+
+	// Evaluate both sides, then set the right value to the left target,
+	// and the left value to the right target
+
+	leftLocal := compiler.declareTemporary(ir.ValTypeValue)
+	rightLocal := compiler.declareTemporary(ir.ValTypeValue)
+
+	var stmts []ir.Stmt
+	stmts = append(stmts, left.setup...)
+	stmts = append(stmts, right.setup...)
+	stmts = append(stmts,
+		&ir.StoreLocal{
+			LocalIndex: leftLocal.Index,
+			Exp:        left.get(),
+		},
+		&ir.StoreLocal{
+			LocalIndex: rightLocal.Index,
+			Exp:        right.get(),
+		},
+		left.set(
+			compiler.transferAndConvert(
+				&ir.CopyLocal{
+					LocalIndex: rightLocal.Index,
+				},
+				rightType,
+				leftType,
+			),
+		),
+		right.set(
+			compiler.transferAndConvert(
+				&ir.CopyLocal{
+					LocalIndex: leftLocal.Index,
+				},
+				leftType,
+				rightType,
+			),
+		),
+	)
+
+	return &ir.Sequence{
+		Stmts: stmts,
+	}
+}
+
+func (compiler *Compiler) VisitExpressionStatement(statement *ast.ExpressionStatement) ir.Stmt {
+	return &ir.Drop{
+		Exp: compiler.compileExpression(statement.Expression),
+	}
+}
+
 func (compiler *Compiler) VisitVoidExpression(_ *ast.VoidExpression) ir.Expr {
-	panic(errors.NewUnreachableError())
+	return &ir.Const{
+		Constant: ir.Void{},
+	}
 }
 
-func (compiler *Compiler) VisitBoolExpression(_ *ast.BoolExpression) ir.Expr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) VisitBoolExpression(expression *ast.BoolExpression) ir.Expr {
+	return &ir.Const{
+		Constant: ir.Bool{
+			Value: expression.Value,
+		},
+	}
 }
 
 func (compiler *Compiler) VisitNilExpression(_ *ast.NilExpression) ir.Expr {
-	// TODO
-	panic(errors.NewUnreachableError())
+	return &ir.Const{
+		Constant: ir.Nil{},
+	}
 }
 
 func (compiler *Compiler) VisitIntegerExpression(expression *ast.IntegerExpression) ir.Expr {
+	exp := compileIntConstant(expression.Value)
+
+	// Integer literals of other types are converted
+
+	integerType := compiler.Elaboration.IntegerExpressionType(expression)
+	switch integerType {
+	case nil, sema.IntType, sema.IntegerType, sema.SignedIntegerType:
+		return exp
+
+	default:
+		return &ir.Convert{
+			Exp:  exp,
+			Type: integerType,
+		}
+	}
+}
+
+func compileIntConstant(integer *big.Int) ir.Expr {
 	var value []byte
 
-	if expression.Value.Sign() < 0 {
+	if integer.Sign() < 0 {
 		value = append(value, 0)
 	} else {
 		value = append(value, 1)
 	}
 
 	value = append(value,
-		expression.Value.Bytes()...,
+		integer.Bytes()...,
 	)
 
 	return &ir.Const{
@@ -175,64 +946,352 @@ func (compiler *Compiler) VisitIntegerExpression(expression *ast.IntegerExpressi
 	}
 }
 
-func (compiler *Compiler) VisitFixedPointExpression(_ *ast.FixedPointExpression) ir.Expr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) VisitFixedPointExpression(expression *ast.FixedPointExpression) ir.Expr {
+	// TODO: adjust once/if we support more fixed point types
+
+	fixedPointSubType := compiler.Elaboration.FixedPointExpression(expression)
+
+	value := fixedpoint.ConvertToFixedPointBigInt(
+		expression.Negative,
+		expression.UnsignedInteger,
+		expression.Fractional,
+		expression.Scale,
+		sema.Fix64Scale,
+	)
+
+	var constant ir.Constant
+
+	switch fixedPointSubType {
+	case sema.Fix64Type, sema.SignedFixedPointType:
+		constant = ir.Fix64{Value: value.Int64()}
+	case sema.UFix64Type:
+		constant = ir.UFix64{Value: value.Uint64()}
+	case sema.FixedPointType:
+		if expression.Negative {
+			constant = ir.Fix64{Value: value.Int64()}
+		} else {
+			constant = ir.UFix64{Value: value.Uint64()}
+		}
+	default:
+		panic(errors.NewUnreachableError())
+	}
+
+	return &ir.Const{
+		Constant: constant,
+	}
 }
 
-func (compiler *Compiler) VisitArrayExpression(_ *ast.ArrayExpression) ir.Expr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) VisitArrayExpression(expression *ast.ArrayExpression) ir.Expr {
+	arrayExpressionTypes := compiler.Elaboration.ArrayExpressionTypes(expression)
+	argumentTypes := arrayExpressionTypes.ArgumentTypes
+	arrayType := arrayExpressionTypes.ArrayType
+	elementType := arrayType.ElementType(false)
+
+	elements := make([]ir.Expr, len(expression.Values))
+	for i, value := range expression.Values {
+		elements[i] = compiler.transferAndConvert(
+			compiler.compileExpression(value),
+			argumentTypes[i],
+			elementType,
+		)
+	}
+
+	return &ir.Array{
+		Type:     arrayType,
+		Elements: elements,
+	}
 }
 
-func (compiler *Compiler) VisitDictionaryExpression(_ *ast.DictionaryExpression) ir.Expr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) VisitDictionaryExpression(expression *ast.DictionaryExpression) ir.Expr {
+	dictionaryExpressionTypes := compiler.Elaboration.DictionaryExpressionTypes(expression)
+	entryTypes := dictionaryExpressionTypes.EntryTypes
+	dictionaryType := dictionaryExpressionTypes.DictionaryType
+
+	entries := make([]ir.DictionaryEntry, len(expression.Entries))
+	for i, entry := range expression.Entries {
+		entryType := entryTypes[i]
+		entries[i] = ir.DictionaryEntry{
+			Key: compiler.transferAndConvert(
+				compiler.compileExpression(entry.Key),
+				entryType.KeyType,
+				dictionaryType.KeyType,
+			),
+			Value: compiler.transferAndConvert(
+				compiler.compileExpression(entry.Value),
+				entryType.ValueType,
+				dictionaryType.ValueType,
+			),
+		}
+	}
+
+	return &ir.Dictionary{
+		Type:    dictionaryType,
+		Entries: entries,
+	}
 }
 
 func (compiler *Compiler) VisitIdentifierExpression(expression *ast.IdentifierExpression) ir.Expr {
-	// TODO
-	local := compiler.findLocal(expression.Identifier.Identifier)
-	// TODO: moves
+	name := expression.Identifier.Identifier
+
+	// The fields of a compiled transaction are locals,
+	// so the transaction itself is not available as a value
+	if compiler.transactionFields != nil && name == sema.SelfIdentifier {
+		panic(UnsupportedError{
+			Feature: "transaction value",
+		})
+	}
+
+	local := compiler.findLocal(name)
+	if local == nil {
+		// The identifier refers to a global,
+		// e.g. a function, a composite, or a built-in value
+		return &ir.Global{
+			Name: name,
+		}
+	}
+
 	return &ir.CopyLocal{
 		LocalIndex: local.Index,
 	}
 }
 
-func (compiler *Compiler) VisitInvocationExpression(_ *ast.InvocationExpression) ir.Expr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) VisitInvocationExpression(expression *ast.InvocationExpression) ir.Expr {
+
+	invocationExpressionTypes := compiler.Elaboration.InvocationExpressionTypes(expression)
+
+	// TODO: type arguments
+	typeArguments := invocationExpressionTypes.TypeArguments
+	if typeArguments != nil && typeArguments.Len() > 0 {
+		panic(UnsupportedError{
+			Feature: "invocation with type arguments",
+		})
+	}
+	argumentTypes := invocationExpressionTypes.ArgumentTypes
+	parameterTypes := invocationExpressionTypes.TypeParameterTypes
+
+	arguments := make([]ir.Expr, len(expression.Arguments))
+	for i, argument := range expression.Arguments {
+		arguments[i] = compiler.transferAndConvert(
+			compiler.compileExpression(argument.Expression),
+			argumentTypes[i],
+			parameterTypes[i],
+		)
+	}
+
+	switch invokedExpression := expression.InvokedExpression.(type) {
+	case *ast.IdentifierExpression:
+		// If the invoked function is a compiled function
+		// which is not shadowed by a local, call it directly
+
+		name := invokedExpression.Identifier.Identifier
+		if compiler.findLocal(name) == nil {
+			if functionIndex, ok := compiler.functionIndices[name]; ok {
+				return &ir.Call{
+					FunctionIndex: functionIndex,
+					Arguments:     arguments,
+				}
+			}
+		}
+
+	case *ast.MemberExpression:
+		// If the member access is optional chaining,
+		// only invoke the function if the accessed value is not nil,
+		// and wrap the result in an optional
+
+		if invokedExpression.Optional {
+			return compiler.compileOptionalChaining(
+				invokedExpression.Expression,
+				func(value ir.Expr) ir.Expr {
+					return &ir.UnOpExpr{
+						Op: ir.UnOpSome,
+						Expr: &ir.Invoke{
+							Function: &ir.Member{
+								Exp:  value,
+								Name: invokedExpression.Identifier.Identifier,
+							},
+							Arguments: arguments,
+						},
+					}
+				},
+			)
+		}
+	}
+
+	return &ir.Invoke{
+		Function:  compiler.compileExpression(expression.InvokedExpression),
+		Arguments: arguments,
+	}
 }
 
-func (compiler *Compiler) VisitMemberExpression(_ *ast.MemberExpression) ir.Expr {
-	// TODO
-	panic(errors.NewUnreachableError())
+// compileOptionalChaining compiles an optional chaining of the given optional expression:
+// If the value is nil, the result is nil.
+// If the value is not nil, the result is produced by the given function,
+// which is passed the unwrapped value
+func (compiler *Compiler) compileOptionalChaining(
+	expression ast.Expression,
+	compileAccess func(value ir.Expr) ir.Expr,
+) ir.Expr {
+	optionalLocal := compiler.declareTemporary(ir.ValTypeValue)
+
+	return &ir.Conditional{
+		Test: &ir.UnOpExpr{
+			Op: ir.UnOpIsNil,
+			Expr: &ir.TeeLocal{
+				LocalIndex: optionalLocal.Index,
+				Exp:        compiler.compileExpression(expression),
+			},
+		},
+		Then: &ir.Const{
+			Constant: ir.Nil{},
+		},
+		Else: compileAccess(
+			&ir.UnOpExpr{
+				Op: ir.UnOpForce,
+				Expr: &ir.CopyLocal{
+					LocalIndex: optionalLocal.Index,
+				},
+			},
+		),
+	}
 }
 
-func (compiler *Compiler) VisitIndexExpression(_ *ast.IndexExpression) ir.Expr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) VisitMemberExpression(expression *ast.MemberExpression) ir.Expr {
+
+	// TODO: potential storage removal
+
+	if local := compiler.transactionField(expression); local != nil {
+		return &ir.CopyLocal{
+			LocalIndex: local.Index,
+		}
+	}
+
+	name := expression.Identifier.Identifier
+
+	if !expression.Optional {
+		return &ir.Member{
+			Exp:  compiler.compileExpression(expression.Expression),
+			Name: name,
+		}
+	}
+
+	// If the member access is optional chaining, only wrap the result value
+	// in an optional, if it is not already an optional value
+
+	memberInfo, _ := compiler.Elaboration.MemberExpressionMemberInfo(expression)
+
+	var isOptionalMember bool
+	if memberInfo.Member != nil {
+		_, isOptionalMember = memberInfo.Member.TypeAnnotation.Type.(*sema.OptionalType)
+	}
+
+	return compiler.compileOptionalChaining(
+		expression.Expression,
+		func(value ir.Expr) ir.Expr {
+			var result ir.Expr = &ir.Member{
+				Exp:  value,
+				Name: name,
+			}
+			if !isOptionalMember {
+				result = &ir.UnOpExpr{
+					Op:   ir.UnOpSome,
+					Expr: result,
+				}
+			}
+			return result
+		},
+	)
 }
 
-func (compiler *Compiler) VisitConditionalExpression(_ *ast.ConditionalExpression) ir.Expr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) VisitIndexExpression(expression *ast.IndexExpression) ir.Expr {
+
+	// TODO: potential storage removal
+
+	if _, ok := compiler.Elaboration.AttachmentAccessTypes(expression); ok {
+		panic(UnsupportedError{
+			Feature: "attachment access",
+		})
+	}
+
+	return &ir.Index{
+		Exp:   compiler.compileExpression(expression.TargetExpression),
+		Index: compiler.compileExpression(expression.IndexingExpression),
+	}
+}
+
+func (compiler *Compiler) VisitConditionalExpression(expression *ast.ConditionalExpression) ir.Expr {
+	return &ir.Conditional{
+		Test: compiler.compileExpression(expression.Test),
+		Then: compiler.compileExpression(expression.Then),
+		Else: compiler.compileExpression(expression.Else),
+	}
 }
 
 func (compiler *Compiler) VisitAttachExpression(_ *ast.AttachExpression) ir.Expr {
-	// TODO
-	panic(errors.NewUnreachableError())
+	panic(UnsupportedError{
+		Feature: "attach expression",
+	})
 }
 
-func (compiler *Compiler) VisitUnaryExpression(_ *ast.UnaryExpression) ir.Expr {
-	// TODO
+func (compiler *Compiler) VisitUnaryExpression(expression *ast.UnaryExpression) ir.Expr {
+	switch expression.Operation {
+	case ast.OperationNegate:
+		return &ir.UnOpExpr{
+			Op:   ir.UnOpNegate,
+			Expr: compiler.compileExpression(expression.Expression),
+		}
+
+	case ast.OperationMinus:
+		return &ir.UnOpExpr{
+			Op:   ir.UnOpMinus,
+			Expr: compiler.compileExpression(expression.Expression),
+		}
+
+	case ast.OperationMove:
+		// Moving a local invalidates it
+		if identifierExpression, ok := expression.Expression.(*ast.IdentifierExpression); ok {
+			local := compiler.findLocal(identifierExpression.Identifier.Identifier)
+			if local != nil {
+				return &ir.MoveLocal{
+					LocalIndex: local.Index,
+				}
+			}
+		}
+
+		return compiler.compileExpression(expression.Expression)
+	}
+
 	panic(errors.NewUnreachableError())
 }
 
 func (compiler *Compiler) VisitBinaryExpression(expression *ast.BinaryExpression) ir.Expr {
+	switch expression.Operation {
+	case ast.OperationAnd:
+		// only evaluate right-hand side if left-hand side is true
+		return &ir.Conditional{
+			Test: compiler.compileExpression(expression.Left),
+			Then: compiler.compileExpression(expression.Right),
+			Else: &ir.Const{
+				Constant: ir.Bool{Value: false},
+			},
+		}
+
+	case ast.OperationOr:
+		// only evaluate right-hand side if left-hand side is false
+		return &ir.Conditional{
+			Test: compiler.compileExpression(expression.Left),
+			Then: &ir.Const{
+				Constant: ir.Bool{Value: true},
+			},
+			Else: compiler.compileExpression(expression.Right),
+		}
+
+	case ast.OperationNilCoalesce:
+		return compiler.compileNilCoalescing(expression)
+	}
+
 	op := compileBinaryOperation(expression.Operation)
-	left := ast.AcceptExpression[ir.Expr](expression.Left, compiler)
-	right := ast.AcceptExpression[ir.Expr](expression.Right, compiler)
+	left := compiler.compileExpression(expression.Left)
+	right := compiler.compileExpression(expression.Right)
 
 	return &ir.BinOpExpr{
 		Op:    op,
@@ -241,52 +1300,199 @@ func (compiler *Compiler) VisitBinaryExpression(expression *ast.BinaryExpression
 	}
 }
 
+func (compiler *Compiler) compileNilCoalescing(expression *ast.BinaryExpression) ir.Expr {
+	binaryExpressionTypes := compiler.Elaboration.BinaryExpressionTypes(expression)
+
+	// only evaluate right-hand side if left-hand side is nil
+
+	optionalLocal := compiler.declareTemporary(ir.ValTypeValue)
+
+	right := compiler.compileExpression(expression.Right)
+	if needsConversion(binaryExpressionTypes.RightType, binaryExpressionTypes.ResultType) {
+		right = &ir.Convert{
+			Exp:  right,
+			Type: binaryExpressionTypes.ResultType,
+		}
+	}
+
+	return &ir.Conditional{
+		Test: &ir.UnOpExpr{
+			Op: ir.UnOpIsNil,
+			Expr: &ir.TeeLocal{
+				LocalIndex: optionalLocal.Index,
+				Exp:        compiler.compileExpression(expression.Left),
+			},
+		},
+		Then: right,
+		Else: &ir.UnOpExpr{
+			Op: ir.UnOpForce,
+			Expr: &ir.CopyLocal{
+				LocalIndex: optionalLocal.Index,
+			},
+		},
+	}
+}
+
 func (compiler *Compiler) VisitFunctionExpression(_ *ast.FunctionExpression) ir.Expr {
-	// TODO
-	panic(errors.NewUnreachableError())
+	// TODO: closure conversion
+	panic(UnsupportedError{
+		Feature: "function expression",
+	})
 }
 
-func (compiler *Compiler) VisitStringExpression(e *ast.StringExpression) ir.Expr {
+func (compiler *Compiler) VisitStringExpression(expression *ast.StringExpression) ir.Expr {
+	stringType := compiler.Elaboration.StringExpressionType(expression)
+
+	if stringType == sema.CharacterType {
+		return &ir.Const{
+			Constant: ir.Character{
+				Value: expression.Value,
+			},
+		}
+	}
+
 	return &ir.Const{
 		Constant: ir.String{
-			Value: e.Value,
+			Value: expression.Value,
 		},
 	}
 }
 
-func (compiler *Compiler) VisitCastingExpression(_ *ast.CastingExpression) ir.Expr {
-	// TODO
+func (compiler *Compiler) VisitCastingExpression(expression *ast.CastingExpression) ir.Expr {
+	exp := compiler.compileExpression(expression.Expression)
+
+	castingExpressionTypes := compiler.Elaboration.CastingExpressionTypes(expression)
+	targetType := castingExpressionTypes.TargetType
+
+	switch expression.Operation {
+	case ast.OperationFailableCast:
+		return &ir.FailableCast{
+			Exp:  exp,
+			Type: targetType,
+		}
+
+	case ast.OperationForceCast:
+		return &ir.ForceCast{
+			Exp:  exp,
+			Type: targetType,
+		}
+
+	case ast.OperationCast:
+		// The cast may upcast to an optional type, e.g. `1 as Int?`, so box
+		if !needsConversion(castingExpressionTypes.StaticValueType, targetType) {
+			return exp
+		}
+		return &ir.Convert{
+			Exp:  exp,
+			Type: targetType,
+		}
+	}
+
 	panic(errors.NewUnreachableError())
 }
 
-func (compiler *Compiler) VisitCreateExpression(_ *ast.CreateExpression) ir.Expr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) VisitCreateExpression(expression *ast.CreateExpression) ir.Expr {
+	return compiler.compileExpression(expression.InvocationExpression)
 }
 
-func (compiler *Compiler) VisitDestroyExpression(_ *ast.DestroyExpression) ir.Expr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) VisitDestroyExpression(expression *ast.DestroyExpression) ir.Expr {
+	return &ir.Destroy{
+		Exp: compiler.compileExpression(expression.Expression),
+	}
 }
 
-func (compiler *Compiler) VisitReferenceExpression(_ *ast.ReferenceExpression) ir.Expr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) VisitReferenceExpression(expression *ast.ReferenceExpression) ir.Expr {
+	borrowType := compiler.Elaboration.ReferenceExpressionBorrowType(expression)
+	return &ir.Reference{
+		Exp:  compiler.compileExpression(expression.Expression),
+		Type: borrowType,
+	}
 }
 
-func (compiler *Compiler) VisitForceExpression(_ *ast.ForceExpression) ir.Expr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) VisitForceExpression(expression *ast.ForceExpression) ir.Expr {
+	return &ir.UnOpExpr{
+		Op:   ir.UnOpForce,
+		Expr: compiler.compileExpression(expression.Expression),
+	}
 }
 
-func (compiler *Compiler) VisitPathExpression(_ *ast.PathExpression) ir.Expr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) VisitPathExpression(expression *ast.PathExpression) ir.Expr {
+	return &ir.Const{
+		Constant: ir.Path{
+			Domain:     common.PathDomainFromIdentifier(expression.Domain.Identifier),
+			Identifier: expression.Identifier.Identifier,
+		},
+	}
 }
 
-func (compiler *Compiler) VisitProgram(_ *ast.Program) ir.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+// VisitProgram compiles all function declarations of the program.
+// The index of each function in the result is its function index.
+//
+// Imports and global variables are declared by the interpreter,
+// and compiled code accesses them as globals.
+// Composites and interfaces are only supported if they are interpreted (see InterpretedComposites).
+// A transaction is compiled to a function named TransactionFunctionName,
+// which follows the other functions.
+// Other declarations, e.g. attachments, are not supported yet
+func (compiler *Compiler) VisitProgram(program *ast.Program) ir.Repr {
+
+	var functionDeclarations []*ast.FunctionDeclaration
+	var transactionDeclaration *ast.TransactionDeclaration
+
+	for _, declaration := range program.Declarations() {
+		switch declaration := declaration.(type) {
+		case *ast.FunctionDeclaration:
+			functionDeclarations = append(functionDeclarations, declaration)
+
+		case *ast.TransactionDeclaration:
+			if transactionDeclaration != nil {
+				panic(UnsupportedError{
+					Feature: "multiple transaction declarations",
+				})
+			}
+			transactionDeclaration = declaration
+
+		case *ast.ImportDeclaration,
+			*ast.PragmaDeclaration,
+			*ast.VariableDeclaration:
+
+			continue
+
+		case *ast.CompositeDeclaration,
+			*ast.InterfaceDeclaration:
+
+			if compiler.InterpretedComposites {
+				continue
+			}
+			ast.AcceptDeclaration[ir.Stmt](declaration, compiler)
+
+		default:
+			ast.AcceptDeclaration[ir.Stmt](declaration, compiler)
+		}
+	}
+
+	// Assign all function indices before compiling the functions,
+	// so functions can call each other
+
+	compiler.functionIndices = make(map[string]uint32, len(functionDeclarations))
+	for i, functionDeclaration := range functionDeclarations {
+		compiler.functionIndices[functionDeclaration.Identifier.Identifier] = uint32(i)
+	}
+
+	funcs := make([]*ir.Func, 0, len(functionDeclarations)+1)
+	for _, functionDeclaration := range functionDeclarations {
+		funcs = append(funcs,
+			compiler.VisitFunctionDeclaration(functionDeclaration).(*ir.Func),
+		)
+	}
+
+	if transactionDeclaration != nil {
+		funcs = append(funcs,
+			compiler.VisitTransactionDeclaration(transactionDeclaration).(*ir.Func),
+		)
+	}
+
+	return funcs
 }
 
 func (compiler *Compiler) VisitSpecialFunctionDeclaration(declaration *ast.SpecialFunctionDeclaration) ir.Stmt {
@@ -296,15 +1502,31 @@ func (compiler *Compiler) VisitSpecialFunctionDeclaration(declaration *ast.Speci
 func (compiler *Compiler) VisitFunctionDeclaration(declaration *ast.FunctionDeclaration) ir.Stmt {
 
 	// TODO: declare function in current scope, use current scope in function
-	// TODO: conditions
+
+	if compiler.inFunction {
+		panic(UnsupportedError{
+			Feature: "nested function declaration",
+		})
+	}
+
+	compiler.inFunction = true
+	defer func() {
+		compiler.inFunction = false
+	}()
 
 	compiler.locals = nil
+	compiler.depth = 0
+	compiler.jumpTargets = nil
+	compiler.returnTarget = nil
 
-	block := declaration.FunctionBlock.Block
+	compiler.activations.PushNewWithCurrent()
+	defer compiler.activations.Pop()
+
+	functionBlock := declaration.FunctionBlock
 
 	// Declare a local for each parameter
 
-	functionType := compiler.Checker.Elaboration.FunctionDeclarationFunctionType(declaration)
+	functionType := compiler.Elaboration.FunctionDeclarationFunctionType(declaration)
 
 	parameters := declaration.ParameterList.Parameters
 
@@ -317,7 +1539,7 @@ func (compiler *Compiler) VisitFunctionDeclaration(declaration *ast.FunctionDecl
 
 	// Compile the function block
 
-	stmt := compiler.visitBlock(block)
+	stmt := compiler.compileFunctionBlock(functionBlock, functionType)
 
 	// Important: compile locals after compiling function block,
 	// and don't include parameters in locals
@@ -334,6 +1556,125 @@ func (compiler *Compiler) VisitFunctionDeclaration(declaration *ast.FunctionDecl
 	}
 }
 
+func (compiler *Compiler) compileFunctionBlock(
+	functionBlock *ast.FunctionBlock,
+	functionType *sema.FunctionType,
+) ir.Stmt {
+
+	if functionBlock == nil {
+		return &ir.Sequence{}
+	}
+
+	preConditions := functionBlock.PreConditions
+	postConditions := functionBlock.PostConditions
+
+	// Without conditions, the function block is just the block
+
+	if preConditions.IsEmpty() && postConditions.IsEmpty() {
+		return compiler.visitBlock(functionBlock.Block)
+	}
+
+	var stmts []ir.Stmt
+
+	if preConditions != nil {
+		stmts = append(stmts, compiler.compileConditions(*preConditions)...)
+	}
+
+	if postConditions.IsEmpty() {
+		stmts = append(stmts, compiler.visitBlock(functionBlock.Block))
+		return &ir.Sequence{
+			Stmts: stmts,
+		}
+	}
+
+	// The function has post-conditions.
+	//
+	// The statements which evaluate the `before` expressions are compiled first.
+	//
+	// The function block is wrapped in a block.
+	// Return statements store the result in a local,
+	// then branch out of the block to the post-conditions.
+
+	postConditionsRewrite := compiler.Elaboration.PostConditionsRewrite(postConditions)
+
+	stmts = append(stmts,
+		compiler.compileStatements(postConditionsRewrite.BeforeStatements)...,
+	)
+
+	returnType := functionType.ReturnTypeAnnotation.Type
+
+	var resultLocal *Local
+	if returnType != sema.VoidType {
+		resultLocal = compiler.declareLocal(
+			sema.ResultIdentifier,
+			compileValueType(returnType),
+		)
+	}
+
+	leave := compiler.enterLabel()
+
+	compiler.returnTarget = &returnTarget{
+		resultLocal: resultLocal,
+		depth:       compiler.depth,
+	}
+
+	stmts = append(stmts,
+		&ir.Block{
+			Stmts: []ir.Stmt{
+				compiler.visitBlock(functionBlock.Block),
+			},
+		},
+	)
+
+	compiler.returnTarget = nil
+
+	leave()
+
+	stmts = append(stmts,
+		compiler.compileConditions(postConditionsRewrite.RewrittenPostConditions)...,
+	)
+
+	var result ir.Expr
+	if resultLocal != nil {
+		result = &ir.CopyLocal{
+			LocalIndex: resultLocal.Index,
+		}
+	}
+
+	stmts = append(stmts,
+		&ir.Return{
+			Exp: result,
+		},
+	)
+
+	return &ir.Sequence{
+		Stmts: stmts,
+	}
+}
+
+func (compiler *Compiler) compileConditions(conditions ast.Conditions) []ir.Stmt {
+	stmts := make([]ir.Stmt, 0, len(conditions)*2)
+	for _, condition := range conditions {
+		var message ir.Expr
+		if condition.Message != nil {
+			message = compiler.compileExpression(condition.Message)
+		}
+
+		// Like in the interpreter, each condition is executed as a statement
+		stmts = append(stmts,
+			&ir.StatementStart{
+				Range: ast.NewUnmeteredRangeFromPositioned(condition.Test),
+			},
+			&ir.Condition{
+				Kind:    condition.Kind,
+				Test:    compiler.compileExpression(condition.Test),
+				Message: message,
+			},
+		)
+	}
+	return stmts
+}
+
 func (compiler *Compiler) visitBlock(block *ast.Block) ir.Stmt {
 
 	// Block scope: each block gets an activation record
@@ -343,10 +1684,7 @@ func (compiler *Compiler) visitBlock(block *ast.Block) ir.Stmt {
 
 	// Compile each statement in the block
 
-	stmts := make([]ir.Stmt, len(block.Statements))
-	for i, statement := range block.Statements {
-		stmts[i] = ast.AcceptStatement[ir.Stmt](statement, compiler)
-	}
+	stmts := compiler.compileStatements(block.Statements)
 
 	// NOTE: just return an IR statement sequence,
 	// there is no need for an IR block
@@ -356,66 +1694,262 @@ func (compiler *Compiler) visitBlock(block *ast.Block) ir.Stmt {
 }
 
 func (compiler *Compiler) VisitCompositeDeclaration(_ *ast.CompositeDeclaration) ir.Stmt {
-	// TODO
-	panic(errors.NewUnreachableError())
+	panic(UnsupportedError{
+		Feature: "composite declaration",
+	})
 }
 
 func (compiler *Compiler) VisitAttachmentDeclaration(_ *ast.AttachmentDeclaration) ir.Stmt {
-	// TODO
-	panic(errors.NewUnreachableError())
+	panic(UnsupportedError{
+		Feature: "attachment declaration",
+	})
 }
 
 func (compiler *Compiler) VisitInterfaceDeclaration(_ *ast.InterfaceDeclaration) ir.Stmt {
-	// TODO
-	panic(errors.NewUnreachableError())
+	panic(UnsupportedError{
+		Feature: "interface declaration",
+	})
 }
 
 func (compiler *Compiler) VisitFieldDeclaration(_ *ast.FieldDeclaration) ir.Stmt {
-	// TODO
-	panic(errors.NewUnreachableError())
+	panic(UnsupportedError{
+		Feature: "field declaration",
+	})
 }
 
 func (compiler *Compiler) VisitPragmaDeclaration(_ *ast.PragmaDeclaration) ir.Stmt {
-	// TODO
-	panic(errors.NewUnreachableError())
+	panic(UnsupportedError{
+		Feature: "pragma declaration",
+	})
 }
 
 func (compiler *Compiler) VisitImportDeclaration(_ *ast.ImportDeclaration) ir.Stmt {
-	// TODO
-	panic(errors.NewUnreachableError())
+	panic(UnsupportedError{
+		Feature: "import declaration",
+	})
 }
 
-func (compiler *Compiler) VisitTransactionDeclaration(_ *ast.TransactionDeclaration) ir.Stmt {
-	// TODO
-	panic(errors.NewUnreachableError())
+// VisitTransactionDeclaration compiles the transaction to a function named TransactionFunctionName.
+// The parameters of the function are the parameters of the transaction,
+// followed by the parameters of the prepare block, i.e. the signers.
+//
+// Like in the interpreter, the prepare block is executed first,
+// followed by the pre-conditions, the execute block, and the post-conditions.
+// The fields of the transaction are stored in locals
+func (compiler *Compiler) VisitTransactionDeclaration(declaration *ast.TransactionDeclaration) ir.Stmt {
+
+	if compiler.inFunction {
+		panic(errors.NewUnreachableError())
+	}
+
+	compiler.inFunction = true
+	defer func() {
+		compiler.inFunction = false
+	}()
+
+	compiler.locals = nil
+	compiler.depth = 0
+	compiler.jumpTargets = nil
+	compiler.returnTarget = nil
+
+	compiler.activations.PushNewWithCurrent()
+	defer compiler.activations.Pop()
+
+	transactionType := compiler.Elaboration.TransactionDeclarationType(declaration)
+
+	// Declare a local for each parameter of the transaction
+
+	if declaration.ParameterList != nil {
+		for i, parameter := range declaration.ParameterList.Parameters {
+			parameterType := transactionType.Parameters[i].TypeAnnotation.Type
+			compiler.declareLocal(
+				parameter.Identifier.Identifier,
+				compileValueType(parameterType),
+			)
+		}
+	}
+
+	// Declare a local for each parameter of the prepare block.
+	// The parameters are only in scope in the prepare block
+
+	compiler.activations.PushNewWithCurrent()
+
+	var prepareFunction *ast.FunctionDeclaration
+	if declaration.Prepare != nil {
+		prepareFunction = declaration.Prepare.FunctionDeclaration
+
+		for i, parameter := range prepareFunction.ParameterList.Parameters {
+			parameterType := transactionType.PrepareParameters[i].TypeAnnotation.Type
+			compiler.declareLocal(
+				parameter.Identifier.Identifier,
+				compileValueType(parameterType),
+			)
+		}
+	}
+
+	parameterCount := len(compiler.locals)
+
+	// Declare a local for each field
+
+	compiler.transactionFields = make(map[string]*Local, len(transactionType.Fields))
+	defer func() {
+		compiler.transactionFields = nil
+	}()
+
+	for _, fieldName := range transactionType.Fields {
+		member, _ := transactionType.Members.Get(fieldName)
+		compiler.transactionFields[fieldName] =
+			compiler.declareTemporary(compileValueType(member.TypeAnnotation.Type))
+	}
+
+	var stmts []ir.Stmt
+
+	if prepareFunction != nil {
+		stmts = append(stmts, compiler.compileTransactionBlock(prepareFunction))
+	}
+
+	compiler.activations.Pop()
+
+	postConditionsRewrite := compiler.Elaboration.PostConditionsRewrite(declaration.PostConditions)
+
+	stmts = append(stmts,
+		compiler.compileStatements(postConditionsRewrite.BeforeStatements)...,
+	)
+
+	if declaration.PreConditions != nil {
+		stmts = append(stmts, compiler.compileConditions(*declaration.PreConditions)...)
+	}
+
+	if declaration.Execute != nil {
+		stmts = append(stmts,
+			compiler.compileTransactionBlock(declaration.Execute.FunctionDeclaration),
+		)
+	}
+
+	stmts = append(stmts,
+		compiler.compileConditions(postConditionsRewrite.RewrittenPostConditions)...,
+	)
+
+	// Important: compile locals after compiling the blocks,
+	// and don't include parameters in locals
+	locals := compileLocals(compiler.locals[parameterCount:])
+
+	return &ir.Func{
+		Name:   TransactionFunctionName,
+		Type:   compileFunctionType(transactionType.EntryPointFunctionType()),
+		Locals: locals,
+		Statement: &ir.Sequence{
+			Stmts: stmts,
+		},
+	}
+}
+
+// compileTransactionBlock compiles the block of the prepare or execute function of a transaction.
+// Return statements branch out of the block, to the next part of the transaction
+func (compiler *Compiler) compileTransactionBlock(function *ast.FunctionDeclaration) ir.Stmt {
+	functionBlock := function.FunctionBlock
+	if functionBlock == nil {
+		return &ir.Sequence{}
+	}
+
+	if !functionBlock.PreConditions.IsEmpty() || !functionBlock.PostConditions.IsEmpty() {
+		panic(UnsupportedError{
+			Feature: "conditions in transaction blocks",
+		})
+	}
+
+	leave := compiler.enterLabel()
+	defer leave()
+
+	compiler.returnTarget = &returnTarget{
+		depth: compiler.depth,
+	}
+	defer func() {
+		compiler.returnTarget = nil
+	}()
+
+	return &ir.Block{
+		Stmts: []ir.Stmt{
+			compiler.visitBlock(functionBlock.Block),
+		},
+	}
+}
+
+// transactionField returns the local of the field of the compiled transaction
+// which the given member expression accesses, if any
+func (compiler *Compiler) transactionField(expression *ast.MemberExpression) *Local {
+	if compiler.transactionFields == nil {
+		return nil
+	}
+
+	identifierExpression, ok := expression.Expression.(*ast.IdentifierExpression)
+	if !ok || identifierExpression.Identifier.Identifier != sema.SelfIdentifier {
+		return nil
+	}
+
+	return compiler.transactionFields[expression.Identifier.Identifier]
 }
 
 func (compiler *Compiler) VisitEnumCaseDeclaration(_ *ast.EnumCaseDeclaration) ir.Stmt {
-	// TODO
-	panic(errors.NewUnreachableError())
+	panic(UnsupportedError{
+		Feature: "enum case declaration",
+	})
 }
 
 func compileBinaryOperation(operation ast.Operation) ir.BinOp {
-	// TODO: add remaining operations
 	switch operation {
 	case ast.OperationPlus:
 		return ir.BinOpPlus
+	case ast.OperationMinus:
+		return ir.BinOpMinus
+	case ast.OperationMul:
+		return ir.BinOpMul
+	case ast.OperationDiv:
+		return ir.BinOpDiv
+	case ast.OperationMod:
+		return ir.BinOpMod
+	case ast.OperationLess:
+		return ir.BinOpLess
+	case ast.OperationLessEqual:
+		return ir.BinOpLessEqual
+	case ast.OperationGreater:
+		return ir.BinOpGreater
+	case ast.OperationGreaterEqual:
+		return ir.BinOpGreaterEqual
+	case ast.OperationEqual:
+		return ir.BinOpEqual
+	case ast.OperationNotEqual:
+		return ir.BinOpNotEqual
+	case ast.OperationBitwiseOr:
+		return ir.BinOpBitwiseOr
+	case ast.OperationBitwiseXor:
+		return ir.BinOpBitwiseXor
+	case ast.OperationBitwiseAnd:
+		return ir.BinOpBitwiseAnd
+	case ast.OperationBitwiseLeftShift:
+		return ir.BinOpBitwiseLeftShift
+	case ast.OperationBitwiseRightShift:
+		return ir.BinOpBitwiseRightShift
 	}
 
 	panic(errors.NewUnreachableError())
 }
 
 func compileValueType(ty sema.Type) ir.ValType {
-	// TODO: add remaining types
-
 	switch ty {
 	case sema.StringType:
 		return ir.ValTypeString
 	case sema.IntType:
 		return ir.ValTypeInt
+	case sema.BoolType:
+		return ir.ValTypeBool
 	}
 
-	panic(errors.NewUnreachableError())
+	if sema.IsSubType(ty, sema.NumberType) {
+		return ir.ValTypeNumber
+	}
+
+	return ir.ValTypeValue
 }
 
 func compileFunctionType(functionType *sema.FunctionType) ir.FuncType {
@@ -447,3 +1981,49 @@ func compileLocals(locals []*Local) []ir.Local {
 	}
 	return result
 }
+
+// isTransferFree returns true if values of the given type
+// never need to be transferred, i.e. they are never copied or moved,
+// e.g. because they are immutable and not stored in containers
+func isTransferFree(ty sema.Type) bool {
+	switch ty {
+	case sema.BoolType,
+		sema.StringType,
+		sema.CharacterType,
+		sema.VoidType,
+		sema.NeverType,
+		sema.MetaType,
+		sema.PathType,
+		sema.StoragePathType,
+		sema.CapabilityPathType,
+		sema.PublicPathType,
+		sema.PrivatePathType:
+
+		return true
+	}
+
+	switch ty.(type) {
+	case *sema.AddressType,
+		*sema.ReferenceType,
+		*sema.FunctionType:
+
+		return true
+	}
+
+	return sema.IsSubType(ty, sema.NumberType)
+}
+
+// needsConversion returns true if a value of the given value type
+// needs to be converted and/or boxed to the given target type
+func needsConversion(valueType, targetType sema.Type) bool {
+	if valueType == nil || targetType == nil {
+		return false
+	}
+
+	// Function values are never converted
+	if _, ok := targetType.(*sema.FunctionType); ok {
+		return false
+	}
+
+	return !valueType.Equal(targetType)
+}
@@ -23,10 +23,31 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/compiler/ir"
 	"github.com/onflow/cadence/runtime/tests/checker"
 )
 
+func statementStart(
+	startOffset, startLine, startColumn int,
+	endOffset, endLine, endColumn int,
+) *ir.StatementStart {
+	return &ir.StatementStart{
+		Range: ast.Range{
+			StartPos: ast.Position{
+				Offset: startOffset,
+				Line:   startLine,
+				Column: startColumn,
+			},
+			EndPos: ast.Position{
+				Offset: endOffset,
+				Line:   endLine,
+				Column: endColumn,
+			},
+		},
+	}
+}
+
 func TestCompilerSimple(t *testing.T) {
 
 	checker, err := checker.ParseAndCheck(t, `
@@ -58,12 +79,14 @@ func TestCompilerSimple(t *testing.T) {
 			},
 			Statement: &ir.Sequence{
 				Stmts: []ir.Stmt{
+					statementStart(40, 3, 10, 50, 3, 20),
 					&ir.StoreLocal{
 						LocalIndex: 1,
 						Exp: &ir.Const{
 							Constant: ir.Int{Value: []byte{1, 1}},
 						},
 					},
+					statementStart(62, 4, 10, 75, 4, 23),
 					&ir.Return{
 						Exp: &ir.BinOpExpr{
 							Op: ir.BinOpPlus,
@@ -81,3 +104,301 @@ func TestCompilerSimple(t *testing.T) {
 		res,
 	)
 }
+
+func TestCompilerWhile(t *testing.T) {
+
+	checker, err := checker.ParseAndCheck(t, `
+      fun count(_ n: Int): Int {
+          var i = 0
+          while i < n {
+              i = i + 1
+          }
+          return i
+      }
+    `)
+
+	require.NoError(t, err)
+
+	compiler := NewCompiler(checker)
+
+	res := compiler.VisitFunctionDeclaration(checker.Program.FunctionDeclarations()[0])
+
+	require.Equal(t,
+		&ir.Func{
+			Name: "count",
+			Type: ir.FuncType{
+				Params: []ir.ValType{
+					ir.ValTypeInt,
+				},
+				Results: []ir.ValType{
+					ir.ValTypeInt,
+				},
+			},
+			Locals: []ir.Local{
+				{Type: ir.ValTypeInt},
+			},
+			Statement: &ir.Sequence{
+				Stmts: []ir.Stmt{
+					statementStart(44, 3, 10, 52, 3, 18),
+					&ir.StoreLocal{
+						LocalIndex: 1,
+						Exp: &ir.Const{
+							Constant: ir.Int{Value: []byte{1}},
+						},
+					},
+					statementStart(64, 4, 10, 112, 6, 10),
+					&ir.Block{
+						Stmts: []ir.Stmt{
+							&ir.Loop{
+								Stmts: []ir.Stmt{
+									// exit the loop if the test fails
+									&ir.BranchIf{
+										Exp: &ir.UnOpExpr{
+											Op: ir.UnOpNegate,
+											Expr: &ir.BinOpExpr{
+												Op: ir.BinOpLess,
+												Left: &ir.CopyLocal{
+													LocalIndex: 1,
+												},
+												Right: &ir.CopyLocal{
+													LocalIndex: 0,
+												},
+											},
+										},
+										Index: 1,
+									},
+									// report the iteration
+									&ir.LoopIteration{},
+									// body, which continue statements branch out of
+									&ir.Block{
+										Stmts: []ir.Stmt{
+											&ir.Sequence{
+												Stmts: []ir.Stmt{
+													statementStart(92, 5, 14, 100, 5, 22),
+													&ir.Sequence{
+														Stmts: []ir.Stmt{
+															&ir.StoreLocal{
+																LocalIndex: 1,
+																Exp: &ir.BinOpExpr{
+																	Op: ir.BinOpPlus,
+																	Left: &ir.CopyLocal{
+																		LocalIndex: 1,
+																	},
+																	Right: &ir.Const{
+																		Constant: ir.Int{Value: []byte{1, 1}},
+																	},
+																},
+															},
+														},
+													},
+												},
+											},
+										},
+									},
+									&ir.Branch{
+										Index: 0,
+									},
+								},
+							},
+						},
+					},
+					statementStart(124, 7, 10, 131, 7, 17),
+					&ir.Return{
+						Exp: &ir.CopyLocal{
+							LocalIndex: 1,
+						},
+					},
+				},
+			},
+		},
+		res,
+	)
+}
+
+func TestCompilerInvocation(t *testing.T) {
+
+	checker, err := checker.ParseAndCheck(t, `
+      fun double(_ n: Int): Int {
+          return n * 2
+      }
+
+      fun quadruple(_ n: Int): Int {
+          return double(double(n))
+      }
+    `)
+
+	require.NoError(t, err)
+
+	compiler := NewCompiler(checker)
+
+	res := compiler.VisitProgram(checker.Program)
+
+	require.IsType(t, []*ir.Func{}, res)
+	funcs := res.([]*ir.Func)
+	require.Len(t, funcs, 2)
+
+	require.Equal(t,
+		&ir.Sequence{
+			Stmts: []ir.Stmt{
+				statementStart(114, 7, 10, 137, 7, 33),
+				&ir.Return{
+					Exp: &ir.Call{
+						FunctionIndex: 0,
+						Arguments: []ir.Expr{
+							&ir.Call{
+								FunctionIndex: 0,
+								Arguments: []ir.Expr{
+									&ir.CopyLocal{
+										LocalIndex: 0,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		funcs[1].Statement,
+	)
+}
+
+func TestCompilerUnsupported(t *testing.T) {
+
+	checker, err := checker.ParseAndCheck(t, `
+      fun test(): Int {
+          let f = fun (): Int { return 1 }
+          return f()
+      }
+    `)
+
+	require.NoError(t, err)
+
+	compiler := NewCompiler(checker)
+
+	require.PanicsWithValue(t,
+		UnsupportedError{
+			Feature: "function expression",
+		},
+		func() {
+			compiler.VisitFunctionDeclaration(checker.Program.FunctionDeclarations()[0])
+		},
+	)
+}
+
+func TestCompilerUnsupportedDeclaration(t *testing.T) {
+
+	checker, err := checker.ParseAndCheck(t, `
+      struct S {
+          fun test(): Int {
+              return 1
+          }
+      }
+
+      fun test(): Int {
+          return S().test()
+      }
+    `)
+
+	require.NoError(t, err)
+
+	compiler := NewCompiler(checker)
+
+	// The functions of composites are not compiled yet,
+	// so the program must not be compiled partially
+
+	require.PanicsWithValue(t,
+		UnsupportedError{
+			Feature: "composite declaration",
+		},
+		func() {
+			compiler.VisitProgram(checker.Program)
+		},
+	)
+
+	// Unless the composites are interpreted
+
+	compiler = NewCompiler(checker)
+	compiler.InterpretedComposites = true
+
+	res := compiler.VisitProgram(checker.Program)
+
+	require.IsType(t, []*ir.Func{}, res)
+	funcs := res.([]*ir.Func)
+	require.Len(t, funcs, 1)
+	require.Equal(t, "test", funcs[0].Name)
+}
+
+func TestCompilerTransaction(t *testing.T) {
+
+	checker, err := checker.ParseAndCheck(t, `
+      fun double(_ n: Int): Int {
+          return n * 2
+      }
+
+      transaction(amount: Int) {
+          var total: Int
+
+          prepare(signer: AuthAccount) {
+              self.total = double(amount)
+          }
+
+          execute {
+              self.total = self.total + 1
+          }
+      }
+    `)
+
+	require.NoError(t, err)
+
+	compiler := NewCompiler(checker)
+
+	res := compiler.VisitProgram(checker.Program)
+
+	require.IsType(t, []*ir.Func{}, res)
+	funcs := res.([]*ir.Func)
+	require.Len(t, funcs, 2)
+
+	// The transaction is compiled to a function,
+	// which has the parameters of the transaction and of the prepare block,
+	// and which stores the field in a local
+
+	transaction := funcs[1]
+	require.Equal(t, TransactionFunctionName, transaction.Name)
+	require.Equal(t,
+		ir.FuncType{
+			Params: []ir.ValType{
+				ir.ValTypeInt,
+				ir.ValTypeValue,
+			},
+		},
+		transaction.Type,
+	)
+	require.Equal(t,
+		[]ir.Local{
+			{Type: ir.ValTypeInt},
+		},
+		transaction.Locals,
+	)
+}
+
+func TestCompilerUnsupportedTypeArguments(t *testing.T) {
+
+	checker, err := checker.ParseAndCheck(t, `
+      fun test(account: AuthAccount): Int? {
+          return account.load<Int>(from: /storage/n)
+      }
+    `)
+
+	require.NoError(t, err)
+
+	compiler := NewCompiler(checker)
+
+	require.PanicsWithValue(t,
+		UnsupportedError{
+			Feature: "invocation with type arguments",
+		},
+		func() {
+			compiler.VisitProgram(checker.Program)
+		},
+	)
+}
@@ -15,7 +15,6 @@
  * See the License for the specific language governing permissions and
  * limitations under the License.
  */
-
 package ir
 
 //go:generate go run golang.org/x/tools/cmd/stringer -type=BinOp
@@ -25,4 +24,19 @@ type BinOp uint
 const (
 	BinOpUnknown BinOp = iota
 	BinOpPlus
+	BinOpMinus
+	BinOpMul
+	BinOpDiv
+	BinOpMod
+	BinOpLess
+	BinOpLessEqual
+	BinOpGreater
+	BinOpGreaterEqual
+	BinOpEqual
+	BinOpNotEqual
+	BinOpBitwiseOr
+	BinOpBitwiseXor
+	BinOpBitwiseAnd
+	BinOpBitwiseLeftShift
+	BinOpBitwiseRightShift
 )
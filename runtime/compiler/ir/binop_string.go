@@ -10,11 +10,26 @@ func _() {
 	var x [1]struct{}
 	_ = x[BinOpUnknown-0]
 	_ = x[BinOpPlus-1]
+	_ = x[BinOpMinus-2]
+	_ = x[BinOpMul-3]
+	_ = x[BinOpDiv-4]
+	_ = x[BinOpMod-5]
+	_ = x[BinOpLess-6]
+	_ = x[BinOpLessEqual-7]
+	_ = x[BinOpGreater-8]
+	_ = x[BinOpGreaterEqual-9]
+	_ = x[BinOpEqual-10]
+	_ = x[BinOpNotEqual-11]
+	_ = x[BinOpBitwiseOr-12]
+	_ = x[BinOpBitwiseXor-13]
+	_ = x[BinOpBitwiseAnd-14]
+	_ = x[BinOpBitwiseLeftShift-15]
+	_ = x[BinOpBitwiseRightShift-16]
 }
 
-const _BinOp_name = "BinOpUnknownBinOpPlus"
+const _BinOp_name = "BinOpUnknownBinOpPlusBinOpMinusBinOpMulBinOpDivBinOpModBinOpLessBinOpLessEqualBinOpGreaterBinOpGreaterEqualBinOpEqualBinOpNotEqualBinOpBitwiseOrBinOpBitwiseXorBinOpBitwiseAndBinOpBitwiseLeftShiftBinOpBitwiseRightShift"
 
-var _BinOp_index = [...]uint8{0, 12, 21}
+var _BinOp_index = [...]uint8{0, 12, 21, 31, 39, 47, 55, 64, 78, 90, 107, 117, 130, 144, 159, 174, 195, 217}
 
 func (i BinOp) String() string {
 	if i >= BinOp(len(_BinOp_index)-1) {
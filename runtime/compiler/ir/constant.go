@@ -18,6 +18,10 @@
 
 package ir
 
+import (
+	"github.com/onflow/cadence/runtime/common"
+)
+
 type Constant interface {
 	isConstant()
 	Accept(Visitor) Repr
@@ -42,3 +46,74 @@ func (String) isConstant() {}
 func (c String) Accept(v Visitor) Repr {
 	return v.VisitString(c)
 }
+
+type Character struct {
+	Value string
+}
+
+func (Character) isConstant() {}
+
+func (c Character) Accept(v Visitor) Repr {
+	return v.VisitCharacter(c)
+}
+
+type Bool struct {
+	Value bool
+}
+
+func (Bool) isConstant() {}
+
+func (c Bool) Accept(v Visitor) Repr {
+	return v.VisitBool(c)
+}
+
+type Nil struct{}
+
+func (Nil) isConstant() {}
+
+func (c Nil) Accept(v Visitor) Repr {
+	return v.VisitNil(c)
+}
+
+type Void struct{}
+
+func (Void) isConstant() {}
+
+func (c Void) Accept(v Visitor) Repr {
+	return v.VisitVoid(c)
+}
+
+// Fix64 is a signed fixed-point constant.
+// The value is scaled by sema.Fix64Factor
+type Fix64 struct {
+	Value int64
+}
+
+func (Fix64) isConstant() {}
+
+func (c Fix64) Accept(v Visitor) Repr {
+	return v.VisitFix64(c)
+}
+
+// UFix64 is an unsigned fixed-point constant.
+// The value is scaled by sema.Fix64Factor
+type UFix64 struct {
+	Value uint64
+}
+
+func (UFix64) isConstant() {}
+
+func (c UFix64) Accept(v Visitor) Repr {
+	return v.VisitUFix64(c)
+}
+
+type Path struct {
+	Identifier string
+	Domain     common.PathDomain
+}
+
+func (Path) isConstant() {}
+
+func (c Path) Accept(v Visitor) Repr {
+	return v.VisitPath(c)
+}
@@ -18,6 +18,10 @@
 
 package ir
 
+import (
+	"github.com/onflow/cadence/runtime/sema"
+)
+
 type Expr interface {
 	isExpr()
 	Accept(Visitor) Repr
@@ -86,3 +90,162 @@ func (*Call) isExpr() {}
 func (e *Call) Accept(v Visitor) Repr {
 	return v.VisitCall(e)
 }
+
+// TeeLocal stores the value of the expression in a local,
+// and also results in the value
+type TeeLocal struct {
+	Exp        Expr
+	LocalIndex uint32
+}
+
+func (*TeeLocal) isExpr() {}
+
+func (e *TeeLocal) Accept(v Visitor) Repr {
+	return v.VisitTeeLocal(e)
+}
+
+// Global is a global value which is not compiled,
+// e.g. a built-in function, a composite constructor, a contract, etc.
+type Global struct {
+	Name string
+}
+
+func (*Global) isExpr() {}
+
+func (e *Global) Accept(v Visitor) Repr {
+	return v.VisitGlobal(e)
+}
+
+type Conditional struct {
+	Test Expr
+	Then Expr
+	Else Expr
+}
+
+func (*Conditional) isExpr() {}
+
+func (e *Conditional) Accept(v Visitor) Repr {
+	return v.VisitConditional(e)
+}
+
+// Invoke invokes a function value.
+// Functions which are compiled are called using Call
+type Invoke struct {
+	Function  Expr
+	Arguments []Expr
+}
+
+func (*Invoke) isExpr() {}
+
+func (e *Invoke) Accept(v Visitor) Repr {
+	return v.VisitInvoke(e)
+}
+
+type Member struct {
+	Exp  Expr
+	Name string
+}
+
+func (*Member) isExpr() {}
+
+func (e *Member) Accept(v Visitor) Repr {
+	return v.VisitMember(e)
+}
+
+type Index struct {
+	Exp   Expr
+	Index Expr
+}
+
+func (*Index) isExpr() {}
+
+func (e *Index) Accept(v Visitor) Repr {
+	return v.VisitIndex(e)
+}
+
+type Array struct {
+	Type     sema.ArrayType
+	Elements []Expr
+}
+
+func (*Array) isExpr() {}
+
+func (e *Array) Accept(v Visitor) Repr {
+	return v.VisitArray(e)
+}
+
+type DictionaryEntry struct {
+	Key   Expr
+	Value Expr
+}
+
+type Dictionary struct {
+	Type    *sema.DictionaryType
+	Entries []DictionaryEntry
+}
+
+func (*Dictionary) isExpr() {}
+
+func (e *Dictionary) Accept(v Visitor) Repr {
+	return v.VisitDictionary(e)
+}
+
+// Convert transfers the value of the expression,
+// converts it to the given type, and boxes it, if needed
+type Convert struct {
+	Exp  Expr
+	Type sema.Type
+}
+
+func (*Convert) isExpr() {}
+
+func (e *Convert) Accept(v Visitor) Repr {
+	return v.VisitConvert(e)
+}
+
+// FailableCast results in the value of the expression wrapped in an optional,
+// if it is a subtype of the given type, or nil otherwise
+type FailableCast struct {
+	Exp  Expr
+	Type sema.Type
+}
+
+func (*FailableCast) isExpr() {}
+
+func (e *FailableCast) Accept(v Visitor) Repr {
+	return v.VisitFailableCast(e)
+}
+
+// ForceCast results in the value of the expression,
+// if it is a subtype of the given type, and aborts otherwise
+type ForceCast struct {
+	Exp  Expr
+	Type sema.Type
+}
+
+func (*ForceCast) isExpr() {}
+
+func (e *ForceCast) Accept(v Visitor) Repr {
+	return v.VisitForceCast(e)
+}
+
+type Reference struct {
+	Exp  Expr
+	Type sema.Type
+}
+
+func (*Reference) isExpr() {}
+
+func (e *Reference) Accept(v Visitor) Repr {
+	return v.VisitReference(e)
+}
+
+type Destroy struct {
+	Exp Expr
+}
+
+func (*Destroy) isExpr() {}
+
+func (e *Destroy) Accept(v Visitor) Repr {
+	return v.VisitDestroy(e)
+}
@@ -18,6 +18,11 @@
 
 package ir
 
+import (
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
 type Stmt interface {
 	isStmt()
 	Accept(Visitor) Repr
@@ -116,3 +121,74 @@ func (*Return) isStmt() {}
 func (s *Return) Accept(v Visitor) Repr {
 	return v.VisitReturn(s)
 }
+
+type StoreMember struct {
+	Exp   Expr
+	Value Expr
+	Name  string
+}
+
+func (*StoreMember) isStmt() {}
+
+func (s *StoreMember) Accept(v Visitor) Repr {
+	return v.VisitStoreMember(s)
+}
+
+type StoreIndex struct {
+	Exp   Expr
+	Index Expr
+	Value Expr
+}
+
+func (*StoreIndex) isStmt() {}
+
+func (s *StoreIndex) Accept(v Visitor) Repr {
+	return v.VisitStoreIndex(s)
+}
+
+type Emit struct {
+	Exp  Expr
+	Type *sema.CompositeType
+}
+
+func (*Emit) isStmt() {}
+
+func (s *Emit) Accept(v Visitor) Repr {
+	return v.VisitEmit(s)
+}
+
+// Condition aborts with the message if the test is false
+type Condition struct {
+	Test    Expr
+	Message Expr
+	Kind    ast.ConditionKind
+}
+
+func (*Condition) isStmt() {}
+
+func (s *Condition) Accept(v Visitor) Repr {
+	return v.VisitCondition(s)
+}
+
+// LoopIteration reports an iteration of the enclosing loop,
+// e.g. for computation metering
+type LoopIteration struct{}
+
+func (*LoopIteration) isStmt() {}
+
+func (s *LoopIteration) Accept(v Visitor) Repr {
+	return v.VisitLoopIteration(s)
+}
+
+// StatementStart reports that a statement is about to be executed,
+// e.g. for computation metering.
+// The range of the statement is used to report errors
+type StatementStart struct {
+	Range ast.Range
+}
+
+func (*StatementStart) isStmt() {}
+
+func (s *StatementStart) Accept(v Visitor) Repr {
+	return v.VisitStatementStart(s)
+}
@@ -15,7 +15,6 @@
  * See the License for the specific language governing permissions and
  * limitations under the License.
  */
-
 package ir
 
 //go:generate go run golang.org/x/tools/cmd/stringer -type=UnOp
@@ -24,4 +23,16 @@ type UnOp uint
 
 const (
 	UnOpUnknown UnOp = iota
+	// UnOpNegate is the boolean negation
+	UnOpNegate
+	// UnOpMinus is the numeric negation
+	UnOpMinus
+	// UnOpForce unwraps an optional, and aborts if it is nil
+	UnOpForce
+	// UnOpIsNil tests if an optional is nil
+	UnOpIsNil
+	// UnOpSome wraps a value in an optional
+	UnOpSome
+	// UnOpTransfer copies value-kinded values, and moves resource-kinded values
+	UnOpTransfer
 )
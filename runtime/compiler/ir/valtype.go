@@ -15,7 +15,6 @@
  * See the License for the specific language governing permissions and
  * limitations under the License.
  */
-
 package ir
 
 //go:generate go run golang.org/x/tools/cmd/stringer -type=ValType
@@ -26,4 +25,10 @@ const (
 	ValTypeUnknown ValType = iota
 	ValTypeInt
 	ValTypeString
+	ValTypeBool
+	// ValTypeNumber is the type of all number values other than Int
+	ValTypeNumber
+	// ValTypeValue is the type of all other values,
+	// e.g. optionals, composites, arrays, dictionaries, references, etc.
+	ValTypeValue
 )
@@ -11,11 +11,14 @@ func _() {
 	_ = x[ValTypeUnknown-0]
 	_ = x[ValTypeInt-1]
 	_ = x[ValTypeString-2]
+	_ = x[ValTypeBool-3]
+	_ = x[ValTypeNumber-4]
+	_ = x[ValTypeValue-5]
 }
 
-const _ValType_name = "ValTypeUnknownValTypeIntValTypeString"
+const _ValType_name = "ValTypeUnknownValTypeIntValTypeStringValTypeBoolValTypeNumberValTypeValue"
 
-var _ValType_index = [...]uint8{0, 14, 24, 37}
+var _ValType_index = [...]uint8{0, 14, 24, 37, 48, 61, 73}
 
 func (i ValType) String() string {
 	if i >= ValType(len(_ValType_index)-1) {
@@ -23,6 +23,13 @@ type Repr any
 type ConstVisitor interface {
 	VisitInt(Int) Repr
 	VisitString(String) Repr
+	VisitCharacter(Character) Repr
+	VisitBool(Bool) Repr
+	VisitNil(Nil) Repr
+	VisitVoid(Void) Repr
+	VisitFix64(Fix64) Repr
+	VisitUFix64(UFix64) Repr
+	VisitPath(Path) Repr
 }
 
 type StmtVisitor interface {
@@ -35,6 +42,12 @@ type StmtVisitor interface {
 	VisitStoreLocal(*StoreLocal) Repr
 	VisitDrop(*Drop) Repr
 	VisitReturn(*Return) Repr
+	VisitStoreMember(*StoreMember) Repr
+	VisitStoreIndex(*StoreIndex) Repr
+	VisitEmit(*Emit) Repr
+	VisitCondition(*Condition) Repr
+	VisitLoopIteration(*LoopIteration) Repr
+	VisitStatementStart(*StatementStart) Repr
 }
 
 type ExprVisitor interface {
@@ -44,6 +57,19 @@ type ExprVisitor interface {
 	VisitUnOpExpr(*UnOpExpr) Repr
 	VisitBinOpExpr(*BinOpExpr) Repr
 	VisitCall(*Call) Repr
+	VisitTeeLocal(*TeeLocal) Repr
+	VisitGlobal(*Global) Repr
+	VisitConditional(*Conditional) Repr
+	VisitInvoke(*Invoke) Repr
+	VisitMember(*Member) Repr
+	VisitIndex(*Index) Repr
+	VisitArray(*Array) Repr
+	VisitDictionary(*Dictionary) Repr
+	VisitConvert(*Convert) Repr
+	VisitFailableCast(*FailableCast) Repr
+	VisitForceCast(*ForceCast) Repr
+	VisitReference(*Reference) Repr
+	VisitDestroy(*Destroy) Repr
 }
 
 type Visitor interface {
@@ -0,0 +1,334 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compiler
+
+import (
+	"github.com/onflow/cadence/runtime/compiler/wasm"
+)
+
+const RuntimeModuleName = "crt"
+
+// RuntimeFunction is a function of the compiler runtime,
+// which is imported by the generated WebAssembly modules.
+//
+// Values are represented as external references.
+// Constants, names, and types are passed as a memory offset and length.
+// Types are encoded as static types.
+type RuntimeFunction struct {
+	Type *wasm.FunctionType
+	Name string
+}
+
+const (
+	// constants
+	RuntimeFunctionNameInt       = "Int"
+	RuntimeFunctionNameString    = "String"
+	RuntimeFunctionNameCharacter = "Character"
+	RuntimeFunctionNameBool      = "Bool"
+	RuntimeFunctionNameNil       = "Nil"
+	RuntimeFunctionNameVoid      = "Void"
+	RuntimeFunctionNameFix64     = "Fix64"
+	RuntimeFunctionNameUFix64    = "UFix64"
+	RuntimeFunctionNamePath      = "Path"
+
+	// binary operations
+	RuntimeFunctionNameAdd               = "add"
+	RuntimeFunctionNameSubtract          = "subtract"
+	RuntimeFunctionNameMultiply          = "multiply"
+	RuntimeFunctionNameDivide            = "divide"
+	RuntimeFunctionNameMod               = "mod"
+	RuntimeFunctionNameLess              = "less"
+	RuntimeFunctionNameLessEqual         = "less_equal"
+	RuntimeFunctionNameGreater           = "greater"
+	RuntimeFunctionNameGreaterEqual      = "greater_equal"
+	RuntimeFunctionNameEqual             = "equal"
+	RuntimeFunctionNameNotEqual          = "not_equal"
+	RuntimeFunctionNameBitwiseOr         = "bitwise_or"
+	RuntimeFunctionNameBitwiseXor        = "bitwise_xor"
+	RuntimeFunctionNameBitwiseAnd        = "bitwise_and"
+	RuntimeFunctionNameBitwiseLeftShift  = "bitwise_left_shift"
+	RuntimeFunctionNameBitwiseRightShift = "bitwise_right_shift"
+
+	// unary operations
+	RuntimeFunctionNameNegate   = "negate"
+	RuntimeFunctionNameMinus    = "minus"
+	RuntimeFunctionNameForce    = "force"
+	RuntimeFunctionNameIsNil    = "is_nil"
+	RuntimeFunctionNameSome     = "some"
+	RuntimeFunctionNameTransfer = "transfer"
+
+	// control flow
+	RuntimeFunctionNameIsTrue        = "is_true"
+	RuntimeFunctionNameFailCondition = "fail_condition"
+	RuntimeFunctionNameGlobal        = "global"
+	RuntimeFunctionNameNewList       = "new_list"
+	RuntimeFunctionNameAppendList    = "append_list"
+	RuntimeFunctionNameInvoke        = "invoke"
+	RuntimeFunctionNameGetMember     = "get_member"
+	RuntimeFunctionNameSetMember     = "set_member"
+	RuntimeFunctionNameGetIndex      = "get_index"
+	RuntimeFunctionNameSetIndex      = "set_index"
+	RuntimeFunctionNameArray         = "array"
+	RuntimeFunctionNameDictionary    = "dictionary"
+	RuntimeFunctionNameConvert       = "convert"
+	RuntimeFunctionNameFailableCast  = "failable_cast"
+	RuntimeFunctionNameForceCast     = "force_cast"
+	RuntimeFunctionNameReference     = "reference"
+	RuntimeFunctionNameDestroy       = "destroy"
+	RuntimeFunctionNameEmit          = "emit"
+
+	// reporting
+	RuntimeFunctionNameReportFunctionInvocation = "report_function_invocation"
+	RuntimeFunctionNameReportFunctionReturn     = "report_function_return"
+	RuntimeFunctionNameReportLoopIteration      = "report_loop_iteration"
+	RuntimeFunctionNameReportStatement          = "report_statement"
+)
+
+var constantFunctionType = &wasm.FunctionType{
+	Params: []wasm.ValueType{
+		// memory offset
+		wasm.ValueTypeI32,
+		// length
+		wasm.ValueTypeI32,
+	},
+	Results: []wasm.ValueType{
+		wasm.ValueTypeExternRef,
+	},
+}
+
+var nullaryFunctionType = &wasm.FunctionType{
+	Results: []wasm.ValueType{
+		wasm.ValueTypeExternRef,
+	},
+}
+
+var reportFunctionType = &wasm.FunctionType{}
+
+var unaryFunctionType = &wasm.FunctionType{
+	Params: []wasm.ValueType{
+		wasm.ValueTypeExternRef,
+	},
+	Results: []wasm.ValueType{
+		wasm.ValueTypeExternRef,
+	},
+}
+
+var binaryFunctionType = &wasm.FunctionType{
+	Params: []wasm.ValueType{
+		wasm.ValueTypeExternRef,
+		wasm.ValueTypeExternRef,
+	},
+	Results: []wasm.ValueType{
+		wasm.ValueTypeExternRef,
+	},
+}
+
+// valueAndConstantFunctionType is the type of functions
+// which are passed a value and a constant, e.g. a name or a type
+var valueAndConstantFunctionType = &wasm.FunctionType{
+	Params: []wasm.ValueType{
+		wasm.ValueTypeExternRef,
+		// memory offset
+		wasm.ValueTypeI32,
+		// length
+		wasm.ValueTypeI32,
+	},
+	Results: []wasm.ValueType{
+		wasm.ValueTypeExternRef,
+	},
+}
+
+// RuntimeFunctions are the functions of the compiler runtime,
+// in the order they are imported by generated WebAssembly modules
+var RuntimeFunctions = []RuntimeFunction{
+	// NOTE: ensure to update the imports in the vm
+
+	// constants
+
+	{Name: RuntimeFunctionNameInt, Type: constantFunctionType},
+	{Name: RuntimeFunctionNameString, Type: constantFunctionType},
+	{Name: RuntimeFunctionNameCharacter, Type: constantFunctionType},
+	{
+		Name: RuntimeFunctionNameBool,
+		Type: &wasm.FunctionType{
+			Params: []wasm.ValueType{
+				wasm.ValueTypeI32,
+			},
+			Results: []wasm.ValueType{
+				wasm.ValueTypeExternRef,
+			},
+		},
+	},
+	{Name: RuntimeFunctionNameNil, Type: nullaryFunctionType},
+	{Name: RuntimeFunctionNameVoid, Type: nullaryFunctionType},
+	{
+		Name: RuntimeFunctionNameFix64,
+		Type: &wasm.FunctionType{
+			Params: []wasm.ValueType{
+				wasm.ValueTypeI64,
+			},
+			Results: []wasm.ValueType{
+				wasm.ValueTypeExternRef,
+			},
+		},
+	},
+	{
+		Name: RuntimeFunctionNameUFix64,
+		Type: &wasm.FunctionType{
+			Params: []wasm.ValueType{
+				wasm.ValueTypeI64,
+			},
+			Results: []wasm.ValueType{
+				wasm.ValueTypeExternRef,
+			},
+		},
+	},
+	{
+		Name: RuntimeFunctionNamePath,
+		Type: &wasm.FunctionType{
+			Params: []wasm.ValueType{
+				// domain
+				wasm.ValueTypeI32,
+				// identifier memory offset
+				wasm.ValueTypeI32,
+				// identifier length
+				wasm.ValueTypeI32,
+			},
+			Results: []wasm.ValueType{
+				wasm.ValueTypeExternRef,
+			},
+		},
+	},
+
+	// binary operations
+
+	{Name: RuntimeFunctionNameAdd, Type: binaryFunctionType},
+	{Name: RuntimeFunctionNameSubtract, Type: binaryFunctionType},
+	{Name: RuntimeFunctionNameMultiply, Type: binaryFunctionType},
+	{Name: RuntimeFunctionNameDivide, Type: binaryFunctionType},
+	{Name: RuntimeFunctionNameMod, Type: binaryFunctionType},
+	{Name: RuntimeFunctionNameLess, Type: binaryFunctionType},
+	{Name: RuntimeFunctionNameLessEqual, Type: binaryFunctionType},
+	{Name: RuntimeFunctionNameGreater, Type: binaryFunctionType},
+	{Name: RuntimeFunctionNameGreaterEqual, Type: binaryFunctionType},
+	{Name: RuntimeFunctionNameEqual, Type: binaryFunctionType},
+	{Name: RuntimeFunctionNameNotEqual, Type: binaryFunctionType},
+	{Name: RuntimeFunctionNameBitwiseOr, Type: binaryFunctionType},
+	{Name: RuntimeFunctionNameBitwiseXor, Type: binaryFunctionType},
+	{Name: RuntimeFunctionNameBitwiseAnd, Type: binaryFunctionType},
+	{Name: RuntimeFunctionNameBitwiseLeftShift, Type: binaryFunctionType},
+	{Name: RuntimeFunctionNameBitwiseRightShift, Type: binaryFunctionType},
+
+	// unary operations
+
+	{Name: RuntimeFunctionNameNegate, Type: unaryFunctionType},
+	{Name: RuntimeFunctionNameMinus, Type: unaryFunctionType},
+	{Name: RuntimeFunctionNameForce, Type: unaryFunctionType},
+	{Name: RuntimeFunctionNameIsNil, Type: unaryFunctionType},
+	{Name: RuntimeFunctionNameSome, Type: unaryFunctionType},
+	{Name: RuntimeFunctionNameTransfer, Type: unaryFunctionType},
+
+	// control flow
+
+	{
+		Name: RuntimeFunctionNameIsTrue,
+		Type: &wasm.FunctionType{
+			Params: []wasm.ValueType{
+				wasm.ValueTypeExternRef,
+			},
+			Results: []wasm.ValueType{
+				wasm.ValueTypeI32,
+			},
+		},
+	},
+	{
+		Name: RuntimeFunctionNameFailCondition,
+		Type: &wasm.FunctionType{
+			Params: []wasm.ValueType{
+				// condition kind
+				wasm.ValueTypeI32,
+				// message
+				wasm.ValueTypeExternRef,
+			},
+		},
+	},
+
+	// values
+
+	{Name: RuntimeFunctionNameGlobal, Type: constantFunctionType},
+	{Name: RuntimeFunctionNameNewList, Type: nullaryFunctionType},
+	{Name: RuntimeFunctionNameAppendList, Type: binaryFunctionType},
+	{Name: RuntimeFunctionNameInvoke, Type: binaryFunctionType},
+	{Name: RuntimeFunctionNameGetMember, Type: valueAndConstantFunctionType},
+	{
+		Name: RuntimeFunctionNameSetMember,
+		Type: &wasm.FunctionType{
+			Params: []wasm.ValueType{
+				// target
+				wasm.ValueTypeExternRef,
+				// name memory offset
+				wasm.ValueTypeI32,
+				// name length
+				wasm.ValueTypeI32,
+				// value
+				wasm.ValueTypeExternRef,
+			},
+		},
+	},
+	{Name: RuntimeFunctionNameGetIndex, Type: binaryFunctionType},
+	{
+		Name: RuntimeFunctionNameSetIndex,
+		Type: &wasm.FunctionType{
+			Params: []wasm.ValueType{
+				// target
+				wasm.ValueTypeExternRef,
+				// index
+				wasm.ValueTypeExternRef,
+				// value
+				wasm.ValueTypeExternRef,
+			},
+		},
+	},
+	{Name: RuntimeFunctionNameArray, Type: valueAndConstantFunctionType},
+	{Name: RuntimeFunctionNameDictionary, Type: valueAndConstantFunctionType},
+	{Name: RuntimeFunctionNameConvert, Type: valueAndConstantFunctionType},
+	{Name: RuntimeFunctionNameFailableCast, Type: valueAndConstantFunctionType},
+	{Name: RuntimeFunctionNameForceCast, Type: valueAndConstantFunctionType},
+	{Name: RuntimeFunctionNameReference, Type: valueAndConstantFunctionType},
+	{Name: RuntimeFunctionNameDestroy, Type: unaryFunctionType},
+	{
+		Name: RuntimeFunctionNameEmit,
+		Type: &wasm.FunctionType{
+			Params: []wasm.ValueType{
+				// event
+				wasm.ValueTypeExternRef,
+				// event type memory offset
+				wasm.ValueTypeI32,
+				// event type length
+				wasm.ValueTypeI32,
+			},
+		},
+	},
+	// reporting
+
+	{Name: RuntimeFunctionNameReportFunctionInvocation, Type: reportFunctionType},
+	{Name: RuntimeFunctionNameReportFunctionReturn, Type: reportFunctionType},
+	{Name: RuntimeFunctionNameReportLoopIteration, Type: reportFunctionType},
+	{Name: RuntimeFunctionNameReportStatement, Type: reportFunctionType},
+}
@@ -30,6 +30,14 @@ type Buffer struct {
 	offset offset
 }
 
+// NewBuffer returns a new buffer for the given data,
+// e.g. to read a WASM binary
+func NewBuffer(data []byte) *Buffer {
+	return &Buffer{
+		data: data,
+	}
+}
+
 func (buf *Buffer) WriteByte(b byte) error {
 	if buf.offset < offset(len(buf.data)) {
 		buf.data[buf.offset] = b
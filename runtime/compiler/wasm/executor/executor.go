@@ -0,0 +1,732 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package executor implements a pure-Go executor for WebAssembly modules.
+//
+// Values are represented as Go values:
+// i32 values as int32, i64 values as int64,
+// function references as FunctionReference,
+// and external references as arbitrary Go values (nil is the null reference).
+//
+// Execution is deterministic: it does not depend on the platform,
+// and the executed instructions are metered, and limited.
+package executor
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/compiler/wasm"
+)
+
+type Value = any
+
+// HostFunction is a function implemented by the embedder,
+// which is imported by a module
+type HostFunction struct {
+	Type     *wasm.FunctionType
+	Function func(instance *Instance, arguments []Value) []Value
+}
+
+// Imports are the host functions available to a module,
+// by module name and function name
+type Imports map[string]map[string]HostFunction
+
+// Trap is an error which occurs when executing an instruction fails,
+// e.g. the `unreachable` instruction
+type Trap struct {
+	Message string
+}
+
+var _ error = Trap{}
+
+func (t Trap) Error() string {
+	return fmt.Sprintf("trap: %s", t.Message)
+}
+
+func trap(format string, arguments ...any) {
+	panic(Trap{
+		Message: fmt.Sprintf(format, arguments...),
+	})
+}
+
+// FunctionReference is a reference to a function of an instance
+type FunctionReference struct {
+	FunctionIndex uint32
+}
+
+// ComputationGauge meters computation.
+// It is compatible with the runtime interface
+type ComputationGauge interface {
+	MeterComputation(kind common.ComputationKind, intensity uint) error
+}
+
+const (
+	defaultMaxCallDepth   = 1000
+	defaultMaxStackHeight = 100_000
+
+	// meteringBatchSize is the number of instructions which are metered at once
+	meteringBatchSize = 1000
+)
+
+// Config is the configuration of an instance
+type Config struct {
+	// ComputationGauge meters the executed instructions, if it is set.
+	// Instructions are metered in batches, with ComputationKindWasmInstruction
+	ComputationGauge ComputationGauge
+	// MaxCallDepth is the maximum depth of nested calls of module functions.
+	// If zero, a default is used
+	MaxCallDepth int
+	// MaxStackHeight is the maximum number of values on the stack.
+	// If zero, a default is used
+	MaxStackHeight int
+}
+
+// meteringError is a failure to meter computation
+type meteringError struct {
+	err error
+}
+
+// function is a function of an instance, either imported or defined in the module
+type function struct {
+	host *HostFunction
+	code *wasm.Code
+	typ  *wasm.FunctionType
+	name string
+}
+
+// Instance is an instantiated module
+type Instance struct {
+	module           *wasm.Module
+	computationGauge ComputationGauge
+	functions        []function
+	exports          map[string]uint32
+	memory           []byte
+	stack            []Value
+	maxCallDepth     int
+	maxStackHeight   int
+	callDepth        int
+	// instructionCount is the number of executed instructions which are not metered yet
+	instructionCount uint
+}
+
+// NewInstance instantiates the given module.
+// The module's imports are resolved using the given imports.
+// The configuration is optional
+func NewInstance(module *wasm.Module, imports Imports, config *Config) (*Instance, error) {
+
+	if config == nil {
+		config = &Config{}
+	}
+
+	instance := &Instance{
+		module:           module,
+		exports:          map[string]uint32{},
+		computationGauge: config.ComputationGauge,
+		maxCallDepth:     config.MaxCallDepth,
+		maxStackHeight:   config.MaxStackHeight,
+	}
+
+	if instance.maxCallDepth == 0 {
+		instance.maxCallDepth = defaultMaxCallDepth
+	}
+
+	if instance.maxStackHeight == 0 {
+		instance.maxStackHeight = defaultMaxStackHeight
+	}
+
+	typeAt := func(typeIndex uint32) (*wasm.FunctionType, error) {
+		if int(typeIndex) >= len(module.Types) {
+			return nil, fmt.Errorf("invalid type index: %d", typeIndex)
+		}
+		return module.Types[typeIndex], nil
+	}
+
+	// Function indices include function imports
+
+	for _, imp := range module.Imports {
+		typ, err := typeAt(imp.TypeIndex)
+		if err != nil {
+			return nil, err
+		}
+
+		hostFunction, ok := imports[imp.Module][imp.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing import: %s", imp.FullName())
+		}
+
+		if !functionTypesEqual(hostFunction.Type, typ) {
+			return nil, fmt.Errorf("incompatible type of import %s", imp.FullName())
+		}
+
+		instance.functions = append(
+			instance.functions,
+			function{
+				name: imp.FullName(),
+				typ:  typ,
+				host: &hostFunction,
+			},
+		)
+	}
+
+	for _, f := range module.Functions {
+		typ, err := typeAt(f.TypeIndex)
+		if err != nil {
+			return nil, err
+		}
+
+		instance.functions = append(
+			instance.functions,
+			function{
+				name: f.Name,
+				typ:  typ,
+				code: f.Code,
+			},
+		)
+	}
+
+	// NOTE: currently only one memory is supported
+
+	if len(module.Memories) > 1 {
+		return nil, fmt.Errorf("unsupported number of memories: %d", len(module.Memories))
+	}
+
+	if len(module.Memories) > 0 {
+		instance.memory = make([]byte, int(module.Memories[0].Min)*wasm.MemoryPageSize)
+	}
+
+	for _, data := range module.Data {
+		if data.MemoryIndex != 0 {
+			return nil, fmt.Errorf("invalid data memory index: %d", data.MemoryIndex)
+		}
+
+		if len(data.Offset) != 1 {
+			return nil, fmt.Errorf("unsupported data offset")
+		}
+		offsetInstruction, ok := data.Offset[0].(wasm.InstructionI32Const)
+		if !ok {
+			return nil, fmt.Errorf("unsupported data offset: %#+v", data.Offset[0])
+		}
+
+		offset := int(uint32(offsetInstruction.Value))
+		end := offset + len(data.Init)
+		if end > len(instance.memory) {
+			return nil, fmt.Errorf("data segment does not fit into memory: %d", end)
+		}
+
+		copy(instance.memory[offset:end], data.Init)
+	}
+
+	for _, export := range module.Exports {
+		functionExport, ok := export.Descriptor.(wasm.FunctionExport)
+		if !ok {
+			continue
+		}
+		if int(functionExport.FunctionIndex) >= len(instance.functions) {
+			return nil, fmt.Errorf("invalid export function index: %d", functionExport.FunctionIndex)
+		}
+		instance.exports[export.Name] = functionExport.FunctionIndex
+	}
+
+	if module.StartFunctionIndex != nil {
+		startFunctionIndex := *module.StartFunctionIndex
+		if int(startFunctionIndex) >= len(instance.functions) {
+			return nil, fmt.Errorf("invalid start function index: %d", startFunctionIndex)
+		}
+
+		_, err := instance.invoke(startFunctionIndex, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return instance, nil
+}
+
+func functionTypesEqual(a, b *wasm.FunctionType) bool {
+	if len(a.Params) != len(b.Params) || len(a.Results) != len(b.Results) {
+		return false
+	}
+	for i, param := range a.Params {
+		if b.Params[i] != param {
+			return false
+		}
+	}
+	for i, result := range a.Results {
+		if b.Results[i] != result {
+			return false
+		}
+	}
+	return true
+}
+
+// Memory returns the memory of the instance
+func (i *Instance) Memory() []byte {
+	return i.memory
+}
+
+// MemoryRange returns the given range of the instance's memory,
+// and traps if the range is out of bounds
+func (i *Instance) MemoryRange(offset int32, length int32) []byte {
+	start := int64(uint32(offset))
+	end := start + int64(uint32(length))
+	if end > int64(len(i.memory)) {
+		trap("out of bounds memory access: %d", end)
+	}
+	return i.memory[start:end]
+}
+
+// Invoke invokes the exported function with the given name
+func (i *Instance) Invoke(name string, arguments ...Value) ([]Value, error) {
+	functionIndex, ok := i.exports[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function: %s", name)
+	}
+
+	return i.invoke(functionIndex, arguments)
+}
+
+func (i *Instance) invoke(functionIndex uint32, arguments []Value) (results []Value, err error) {
+	f := i.functions[functionIndex]
+
+	typ := f.typ
+	if len(arguments) != len(typ.Params) {
+		return nil, fmt.Errorf(
+			"invalid argument count for function %s: expected %d, got %d",
+			f.name,
+			len(typ.Params),
+			len(arguments),
+		)
+	}
+
+	for index, param := range typ.Params {
+		if !hasValueType(arguments[index], param) {
+			return nil, fmt.Errorf("invalid argument %d for function %s: %#+v", index, f.name, arguments[index])
+		}
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			switch recovered := recovered.(type) {
+			case Trap:
+				err = recovered
+			case meteringError:
+				err = recovered.err
+			default:
+				panic(recovered)
+			}
+
+			// The stack might be left in an inconsistent state
+			i.stack = nil
+			i.callDepth = 0
+			i.instructionCount = 0
+		}
+	}()
+
+	i.stack = append(i.stack, arguments...)
+	i.call(functionIndex)
+
+	results = i.popN(len(typ.Results))
+
+	i.meter()
+
+	return results, nil
+}
+
+// meter meters the executed instructions which are not metered yet
+func (i *Instance) meter() {
+	count := i.instructionCount
+	if count == 0 {
+		return
+	}
+	i.instructionCount = 0
+
+	if i.computationGauge == nil {
+		return
+	}
+
+	err := i.computationGauge.MeterComputation(common.ComputationKindWasmInstruction, count)
+	if err != nil {
+		panic(meteringError{err: err})
+	}
+}
+
+func hasValueType(value Value, valueType wasm.ValueType) bool {
+	switch valueType {
+	case wasm.ValueTypeI32:
+		_, ok := value.(int32)
+		return ok
+	case wasm.ValueTypeI64:
+		_, ok := value.(int64)
+		return ok
+	case wasm.ValueTypeFuncRef:
+		if value == nil {
+			return true
+		}
+		_, ok := value.(FunctionReference)
+		return ok
+	default:
+		return true
+	}
+}
+
+func zeroValue(valueType wasm.ValueType) Value {
+	switch valueType {
+	case wasm.ValueTypeI32:
+		return int32(0)
+	case wasm.ValueTypeI64:
+		return int64(0)
+	default:
+		return nil
+	}
+}
+
+func (i *Instance) push(value Value) {
+	if len(i.stack) >= i.maxStackHeight {
+		trap("value stack exhausted")
+	}
+	i.stack = append(i.stack, value)
+}
+
+func (i *Instance) pop() Value {
+	lastIndex := len(i.stack) - 1
+	if lastIndex < 0 {
+		trap("stack underflow")
+	}
+	value := i.stack[lastIndex]
+	i.stack[lastIndex] = nil
+	i.stack = i.stack[:lastIndex]
+	return value
+}
+
+// popN pops the given number of values, in the order they were pushed
+func (i *Instance) popN(count int) []Value {
+	start := len(i.stack) - count
+	if start < 0 {
+		trap("stack underflow")
+	}
+	values := make([]Value, count)
+	copy(values, i.stack[start:])
+	i.truncate(start)
+	return values
+}
+
+func (i *Instance) truncate(height int) {
+	for index := height; index < len(i.stack); index++ {
+		i.stack[index] = nil
+	}
+	i.stack = i.stack[:height]
+}
+
+func (i *Instance) popI32() int32 {
+	value, ok := i.pop().(int32)
+	if !ok {
+		trap("expected i32")
+	}
+	return value
+}
+
+func (i *Instance) popI64() int64 {
+	value, ok := i.pop().(int64)
+	if !ok {
+		trap("expected i64")
+	}
+	return value
+}
+
+// call calls the function with the given index.
+// The arguments are on the stack, and are replaced with the results
+func (i *Instance) call(functionIndex uint32) {
+	if int(functionIndex) >= len(i.functions) {
+		trap("invalid function index: %d", functionIndex)
+	}
+	f := i.functions[functionIndex]
+
+	arguments := i.popN(len(f.typ.Params))
+
+	if f.host != nil {
+		// Meter before calling the host,
+		// so metering is in order with any metering of the host
+		i.meter()
+
+		results := f.host.Function(i, arguments)
+		if len(results) != len(f.typ.Results) {
+			trap("invalid result count of host function %s: %d", f.name, len(results))
+		}
+		i.stack = append(i.stack, results...)
+		return
+	}
+
+	i.callDepth++
+	defer func() {
+		i.callDepth--
+	}()
+
+	if i.callDepth > i.maxCallDepth {
+		trap("call stack exhausted")
+	}
+
+	locals := make([]Value, len(arguments)+len(f.code.Locals))
+	copy(locals, arguments)
+	for index, local := range f.code.Locals {
+		locals[len(arguments)+index] = zeroValue(local)
+	}
+
+	fr := &frame{
+		locals: locals,
+	}
+
+	height := len(i.stack)
+	resultCount := len(f.typ.Results)
+
+	// The body of a function is an implicit block,
+	// and returns are branches out of it
+	i.execute(fr, f.code.Instructions)
+	i.exitBlock(height, resultCount)
+}
+
+// frame is the state of a function invocation
+type frame struct {
+	locals []Value
+}
+
+// control is the result of executing a sequence of instructions
+type control struct {
+	// depth is the relative depth of the target label of a branch
+	depth uint32
+	kind  controlKind
+}
+
+type controlKind uint8
+
+const (
+	controlNext controlKind = iota
+	controlBranch
+	controlReturn
+)
+
+// exitBlock handles the end of a block (or function body) with the given results,
+// which was entered with the given stack height
+func (i *Instance) exitBlock(height int, resultCount int) {
+	results := i.popN(resultCount)
+	i.truncate(height)
+	i.stack = append(i.stack, results...)
+}
+
+func (i *Instance) blockArity(blockType wasm.BlockType) (params int, results int) {
+	switch blockType := blockType.(type) {
+	case nil:
+		return 0, 0
+	case wasm.ValueType:
+		return 0, 1
+	case wasm.TypeIndexBlockType:
+		if int(blockType.TypeIndex) >= len(i.module.Types) {
+			trap("invalid block type index: %d", blockType.TypeIndex)
+		}
+		typ := i.module.Types[blockType.TypeIndex]
+		return len(typ.Params), len(typ.Results)
+	default:
+		trap("unsupported block type: %#+v", blockType)
+		return 0, 0
+	}
+}
+
+// executeBlock executes a block, loop, or the branch of an if.
+// It returns the control for the enclosing instructions
+func (i *Instance) executeBlock(fr *frame, block wasm.Block, instructions []wasm.Instruction, isLoop bool) control {
+	params, results := i.blockArity(block.BlockType)
+	height := len(i.stack) - params
+
+	for {
+		c := i.execute(fr, instructions)
+
+		switch c.kind {
+		case controlNext:
+			i.exitBlock(height, results)
+			return control{}
+
+		case controlReturn:
+			return c
+
+		case controlBranch:
+			if c.depth > 0 {
+				c.depth--
+				return c
+			}
+
+			if isLoop {
+				// A branch to a loop continues the loop,
+				// with the loop's parameters
+				i.exitBlock(height, params)
+				continue
+			}
+
+			i.exitBlock(height, results)
+			return control{}
+		}
+	}
+}
+
+// execute executes the given instructions
+func (i *Instance) execute(fr *frame, instructions []wasm.Instruction) control {
+	for _, instruction := range instructions {
+
+		i.instructionCount++
+		if i.instructionCount >= meteringBatchSize {
+			i.meter()
+		}
+
+		switch instruction := instruction.(type) {
+
+		// control instructions
+
+		case wasm.InstructionUnreachable:
+			trap("unreachable")
+
+		case wasm.InstructionNop:
+			// no-op
+
+		case wasm.InstructionBlock:
+			c := i.executeBlock(fr, instruction.Block, instruction.Block.Instructions1, false)
+			if c.kind != controlNext {
+				return c
+			}
+
+		case wasm.InstructionLoop:
+			c := i.executeBlock(fr, instruction.Block, instruction.Block.Instructions1, true)
+			if c.kind != controlNext {
+				return c
+			}
+
+		case wasm.InstructionIf:
+			var branch []wasm.Instruction
+			if i.popI32() != 0 {
+				branch = instruction.Block.Instructions1
+			} else {
+				branch = instruction.Block.Instructions2
+			}
+			c := i.executeBlock(fr, instruction.Block, branch, false)
+			if c.kind != controlNext {
+				return c
+			}
+
+		case wasm.InstructionBr:
+			return control{
+				kind:  controlBranch,
+				depth: instruction.LabelIndex,
+			}
+
+		case wasm.InstructionBrIf:
+			if i.popI32() != 0 {
+				return control{
+					kind:  controlBranch,
+					depth: instruction.LabelIndex,
+				}
+			}
+
+		case wasm.InstructionBrTable:
+			index := uint32(i.popI32())
+			labelIndex := instruction.DefaultLabelIndex
+			if index < uint32(len(instruction.LabelIndices)) {
+				labelIndex = instruction.LabelIndices[index]
+			}
+			return control{
+				kind:  controlBranch,
+				depth: labelIndex,
+			}
+
+		case wasm.InstructionReturn:
+			return control{
+				kind: controlReturn,
+			}
+
+		case wasm.InstructionCall:
+			i.call(instruction.FuncIndex)
+
+		case wasm.InstructionCallIndirect:
+			// NOTE: modules do not have tables yet
+			trap("invalid table index: %d", instruction.TableIndex)
+
+		case wasm.InstructionEnd:
+			// The end of blocks is implicit
+
+		// reference instructions
+
+		case wasm.InstructionRefNull:
+			i.push(nil)
+
+		case wasm.InstructionRefIsNull:
+			i.push(boolToI32(i.pop() == nil))
+
+		case wasm.InstructionRefFunc:
+			if int(instruction.FuncIndex) >= len(i.functions) {
+				trap("invalid function index: %d", instruction.FuncIndex)
+			}
+			i.push(FunctionReference{
+				FunctionIndex: instruction.FuncIndex,
+			})
+
+		// parametric instructions
+
+		case wasm.InstructionDrop:
+			i.pop()
+
+		case wasm.InstructionSelect:
+			test := i.popI32()
+			second := i.pop()
+			first := i.pop()
+			if test != 0 {
+				i.push(first)
+			} else {
+				i.push(second)
+			}
+
+		// variable instructions
+
+		case wasm.InstructionLocalGet:
+			i.push(fr.locals[i.localIndex(fr, instruction.LocalIndex)])
+
+		case wasm.InstructionLocalSet:
+			fr.locals[i.localIndex(fr, instruction.LocalIndex)] = i.pop()
+
+		case wasm.InstructionLocalTee:
+			value := i.pop()
+			fr.locals[i.localIndex(fr, instruction.LocalIndex)] = value
+			i.push(value)
+
+		case wasm.InstructionGlobalGet:
+			// NOTE: modules do not have globals yet
+			trap("invalid global index: %d", instruction.GlobalIndex)
+
+		case wasm.InstructionGlobalSet:
+			trap("invalid global index: %d", instruction.GlobalIndex)
+
+		default:
+			i.executeNumeric(instruction)
+		}
+	}
+
+	return control{}
+}
+
+func (i *Instance) localIndex(fr *frame, index uint32) uint32 {
+	if int(index) >= len(fr.locals) {
+		trap("invalid local index: %d", index)
+	}
+	return index
+}
@@ -0,0 +1,480 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/compiler/wasm"
+)
+
+var i32ToI32FunctionType = &wasm.FunctionType{
+	Params:  []wasm.ValueType{wasm.ValueTypeI32},
+	Results: []wasm.ValueType{wasm.ValueTypeI32},
+}
+
+func TestExecutorLoop(t *testing.T) {
+
+	t.Parallel()
+
+	module := &wasm.Module{
+		Types: []*wasm.FunctionType{
+			i32ToI32FunctionType,
+		},
+		Imports: []*wasm.Import{
+			{Module: "env", Name: "inc", TypeIndex: 0},
+			{Module: "env", Name: "dec", TypeIndex: 0},
+		},
+		Functions: []*wasm.Function{
+			{
+				Name:      "count",
+				TypeIndex: 0,
+				Code: &wasm.Code{
+					Locals: []wasm.ValueType{wasm.ValueTypeI32},
+					Instructions: []wasm.Instruction{
+						wasm.InstructionBlock{
+							Block: wasm.Block{
+								Instructions1: []wasm.Instruction{
+									wasm.InstructionLoop{
+										Block: wasm.Block{
+											Instructions1: []wasm.Instruction{
+												// exit the loop if n == 0
+												wasm.InstructionLocalGet{LocalIndex: 0},
+												wasm.InstructionI32Eqz{},
+												wasm.InstructionBrIf{LabelIndex: 1},
+												// n = dec(n)
+												wasm.InstructionLocalGet{LocalIndex: 0},
+												wasm.InstructionCall{FuncIndex: 1},
+												wasm.InstructionLocalSet{LocalIndex: 0},
+												// count = inc(count)
+												wasm.InstructionLocalGet{LocalIndex: 1},
+												wasm.InstructionCall{FuncIndex: 0},
+												wasm.InstructionLocalSet{LocalIndex: 1},
+												wasm.InstructionBr{LabelIndex: 0},
+											},
+										},
+									},
+								},
+							},
+						},
+						wasm.InstructionLocalGet{LocalIndex: 1},
+					},
+				},
+			},
+		},
+		Exports: []*wasm.Export{
+			{
+				Name:       "count",
+				Descriptor: wasm.FunctionExport{FunctionIndex: 2},
+			},
+		},
+	}
+
+	calls := 0
+
+	imports := Imports{
+		"env": {
+			"inc": {
+				Type: i32ToI32FunctionType,
+				Function: func(_ *Instance, arguments []Value) []Value {
+					calls++
+					return []Value{arguments[0].(int32) + 1}
+				},
+			},
+			"dec": {
+				Type: i32ToI32FunctionType,
+				Function: func(_ *Instance, arguments []Value) []Value {
+					return []Value{arguments[0].(int32) - 1}
+				},
+			},
+		},
+	}
+
+	instance, err := NewInstance(module, imports, nil)
+	require.NoError(t, err)
+
+	results, err := instance.Invoke("count", int32(5))
+	require.NoError(t, err)
+	require.Equal(t, []Value{int32(5)}, results)
+	require.Equal(t, 5, calls)
+}
+
+func TestExecutorMissingImport(t *testing.T) {
+
+	t.Parallel()
+
+	module := &wasm.Module{
+		Types: []*wasm.FunctionType{
+			i32ToI32FunctionType,
+		},
+		Imports: []*wasm.Import{
+			{Module: "env", Name: "inc", TypeIndex: 0},
+		},
+	}
+
+	_, err := NewInstance(module, nil, nil)
+	require.EqualError(t, err, "missing import: env.inc")
+}
+
+func TestExecutorTrap(t *testing.T) {
+
+	t.Parallel()
+
+	module := &wasm.Module{
+		Types: []*wasm.FunctionType{
+			{},
+		},
+		Functions: []*wasm.Function{
+			{
+				TypeIndex: 0,
+				Code: &wasm.Code{
+					Instructions: []wasm.Instruction{
+						wasm.InstructionUnreachable{},
+					},
+				},
+			},
+		},
+		Exports: []*wasm.Export{
+			{
+				Name:       "fail",
+				Descriptor: wasm.FunctionExport{FunctionIndex: 0},
+			},
+		},
+	}
+
+	instance, err := NewInstance(module, nil, nil)
+	require.NoError(t, err)
+
+	_, err = instance.Invoke("fail")
+	require.ErrorAs(t, err, &Trap{})
+}
+
+func TestExecutorMemory(t *testing.T) {
+
+	t.Parallel()
+
+	module := &wasm.Module{
+		Memories: []*wasm.Memory{
+			{Min: 1},
+		},
+		Data: []*wasm.Data{
+			{
+				Offset: []wasm.Instruction{
+					wasm.InstructionI32Const{Value: 4},
+				},
+				Init: []byte{1, 2, 3},
+			},
+		},
+	}
+
+	instance, err := NewInstance(module, nil, nil)
+	require.NoError(t, err)
+
+	require.Len(t, instance.Memory(), wasm.MemoryPageSize)
+	require.Equal(t, []byte{0, 1, 2, 3}, instance.MemoryRange(3, 4))
+}
+
+// newTestInstance returns an instance of a module with a single function,
+// which is exported as "test"
+func newTestInstance(
+	t *testing.T,
+	functionType *wasm.FunctionType,
+	instructions []wasm.Instruction,
+	config *Config,
+) *Instance {
+	module := &wasm.Module{
+		Types: []*wasm.FunctionType{
+			functionType,
+		},
+		Functions: []*wasm.Function{
+			{
+				TypeIndex: 0,
+				Code: &wasm.Code{
+					Instructions: instructions,
+				},
+			},
+		},
+		Exports: []*wasm.Export{
+			{
+				Name:       "test",
+				Descriptor: wasm.FunctionExport{FunctionIndex: 0},
+			},
+		},
+	}
+
+	instance, err := NewInstance(module, nil, config)
+	require.NoError(t, err)
+
+	return instance
+}
+
+func TestExecutorNumeric(t *testing.T) {
+
+	t.Parallel()
+
+	i32ResultType := &wasm.FunctionType{
+		Results: []wasm.ValueType{wasm.ValueTypeI32},
+	}
+
+	i64ResultType := &wasm.FunctionType{
+		Results: []wasm.ValueType{wasm.ValueTypeI64},
+	}
+
+	i32 := func(value int32) wasm.Instruction {
+		return wasm.InstructionI32Const{Value: value}
+	}
+
+	i64 := func(value int64) wasm.Instruction {
+		return wasm.InstructionI64Const{Value: value}
+	}
+
+	type testCase struct {
+		name         string
+		functionType *wasm.FunctionType
+		instructions []wasm.Instruction
+		expected     Value
+		trap         string
+	}
+
+	testCases := []testCase{
+		{"i32.add", i32ResultType, []wasm.Instruction{i32(math.MaxInt32), i32(1), wasm.InstructionI32Add{}}, int32(math.MinInt32), ""},
+		{"i32.sub", i32ResultType, []wasm.Instruction{i32(1), i32(3), wasm.InstructionI32Sub{}}, int32(-2), ""},
+		{"i32.mul", i32ResultType, []wasm.Instruction{i32(-3), i32(4), wasm.InstructionI32Mul{}}, int32(-12), ""},
+		{"i32.div_s", i32ResultType, []wasm.Instruction{i32(-7), i32(2), wasm.InstructionI32DivS{}}, int32(-3), ""},
+		{"i32.div_s zero", i32ResultType, []wasm.Instruction{i32(1), i32(0), wasm.InstructionI32DivS{}}, nil, "integer divide by zero"},
+		{"i32.div_s overflow", i32ResultType, []wasm.Instruction{i32(math.MinInt32), i32(-1), wasm.InstructionI32DivS{}}, nil, "integer overflow"},
+		{"i32.div_u", i32ResultType, []wasm.Instruction{i32(-1), i32(2), wasm.InstructionI32DivU{}}, int32(math.MaxInt32), ""},
+		{"i32.rem_s", i32ResultType, []wasm.Instruction{i32(-7), i32(2), wasm.InstructionI32RemS{}}, int32(-1), ""},
+		{"i32.rem_s overflow", i32ResultType, []wasm.Instruction{i32(math.MinInt32), i32(-1), wasm.InstructionI32RemS{}}, int32(0), ""},
+		{"i32.rem_u", i32ResultType, []wasm.Instruction{i32(-1), i32(10), wasm.InstructionI32RemU{}}, int32(5), ""},
+		{"i32.and", i32ResultType, []wasm.Instruction{i32(6), i32(3), wasm.InstructionI32And{}}, int32(2), ""},
+		{"i32.or", i32ResultType, []wasm.Instruction{i32(6), i32(3), wasm.InstructionI32Or{}}, int32(7), ""},
+		{"i32.xor", i32ResultType, []wasm.Instruction{i32(6), i32(3), wasm.InstructionI32Xor{}}, int32(5), ""},
+		{"i32.shl", i32ResultType, []wasm.Instruction{i32(1), i32(33), wasm.InstructionI32Shl{}}, int32(2), ""},
+		{"i32.shr_s", i32ResultType, []wasm.Instruction{i32(-8), i32(1), wasm.InstructionI32ShrS{}}, int32(-4), ""},
+		{"i32.shr_u", i32ResultType, []wasm.Instruction{i32(-8), i32(28), wasm.InstructionI32ShrU{}}, int32(15), ""},
+		{"i32.rotl", i32ResultType, []wasm.Instruction{i32(math.MinInt32), i32(1), wasm.InstructionI32Rotl{}}, int32(1), ""},
+		{"i32.rotr", i32ResultType, []wasm.Instruction{i32(1), i32(1), wasm.InstructionI32Rotr{}}, int32(math.MinInt32), ""},
+		{"i32.clz", i32ResultType, []wasm.Instruction{i32(1), wasm.InstructionI32Clz{}}, int32(31), ""},
+		{"i32.ctz", i32ResultType, []wasm.Instruction{i32(8), wasm.InstructionI32Ctz{}}, int32(3), ""},
+		{"i32.popcnt", i32ResultType, []wasm.Instruction{i32(-1), wasm.InstructionI32Popcnt{}}, int32(32), ""},
+		{"i32.lt_s", i32ResultType, []wasm.Instruction{i32(-1), i32(0), wasm.InstructionI32LtS{}}, int32(1), ""},
+		{"i32.lt_u", i32ResultType, []wasm.Instruction{i32(-1), i32(0), wasm.InstructionI32LtU{}}, int32(0), ""},
+		{"i32.ge_u", i32ResultType, []wasm.Instruction{i32(-1), i32(0), wasm.InstructionI32GeU{}}, int32(1), ""},
+		{"i32.eq", i32ResultType, []wasm.Instruction{i32(2), i32(2), wasm.InstructionI32Eq{}}, int32(1), ""},
+		{"i64.add", i64ResultType, []wasm.Instruction{i64(math.MaxInt64), i64(1), wasm.InstructionI64Add{}}, int64(math.MinInt64), ""},
+		{"i64.div_s overflow", i64ResultType, []wasm.Instruction{i64(math.MinInt64), i64(-1), wasm.InstructionI64DivS{}}, nil, "integer overflow"},
+		{"i64.rem_u zero", i64ResultType, []wasm.Instruction{i64(1), i64(0), wasm.InstructionI64RemU{}}, nil, "integer divide by zero"},
+		{"i64.shl", i64ResultType, []wasm.Instruction{i64(1), i64(65), wasm.InstructionI64Shl{}}, int64(2), ""},
+		{"i64.rotr", i64ResultType, []wasm.Instruction{i64(1), i64(1), wasm.InstructionI64Rotr{}}, int64(math.MinInt64), ""},
+		{"i64.popcnt", i64ResultType, []wasm.Instruction{i64(-1), wasm.InstructionI64Popcnt{}}, int64(64), ""},
+		{"i64.gt_u", i32ResultType, []wasm.Instruction{i64(-1), i64(0), wasm.InstructionI64GtU{}}, int32(1), ""},
+		{"i32.wrap_i64", i32ResultType, []wasm.Instruction{i64(1<<32 + 5), wasm.InstructionI32WrapI64{}}, int32(5), ""},
+		{"i64.extend_i32_s", i64ResultType, []wasm.Instruction{i32(-1), wasm.InstructionI64ExtendI32S{}}, int64(-1), ""},
+		{"i64.extend_i32_u", i64ResultType, []wasm.Instruction{i32(-1), wasm.InstructionI64ExtendI32U{}}, int64(math.MaxUint32), ""},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+
+			t.Parallel()
+
+			instance := newTestInstance(t, testCase.functionType, testCase.instructions, nil)
+
+			results, err := instance.Invoke("test")
+			if testCase.trap != "" {
+				require.Equal(t, Trap{Message: testCase.trap}, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, []Value{testCase.expected}, results)
+		})
+	}
+}
+
+func TestExecutorBrTable(t *testing.T) {
+
+	t.Parallel()
+
+	// Returns 10 for 0, 20 for 1, and 30 otherwise
+
+	instance := newTestInstance(t,
+		i32ToI32FunctionType,
+		[]wasm.Instruction{
+			wasm.InstructionBlock{
+				Block: wasm.Block{
+					Instructions1: []wasm.Instruction{
+						wasm.InstructionBlock{
+							Block: wasm.Block{
+								Instructions1: []wasm.Instruction{
+									wasm.InstructionBlock{
+										Block: wasm.Block{
+											Instructions1: []wasm.Instruction{
+												wasm.InstructionLocalGet{LocalIndex: 0},
+												wasm.InstructionBrTable{
+													LabelIndices:      []uint32{0, 1},
+													DefaultLabelIndex: 2,
+												},
+											},
+										},
+									},
+									wasm.InstructionI32Const{Value: 10},
+									wasm.InstructionReturn{},
+								},
+							},
+						},
+						wasm.InstructionI32Const{Value: 20},
+						wasm.InstructionReturn{},
+					},
+				},
+			},
+			wasm.InstructionI32Const{Value: 30},
+		},
+		nil,
+	)
+
+	for argument, expected := range []int32{10, 20, 30, 30} {
+		results, err := instance.Invoke("test", int32(argument))
+		require.NoError(t, err)
+		require.Equal(t, []Value{expected}, results)
+	}
+}
+
+type testComputationGauge struct {
+	limit    uint
+	computed uint
+}
+
+var _ ComputationGauge = &testComputationGauge{}
+
+var errComputationLimitExceeded = errors.New("computation limit exceeded")
+
+func (g *testComputationGauge) MeterComputation(kind common.ComputationKind, intensity uint) error {
+	if kind != common.ComputationKindWasmInstruction {
+		return nil
+	}
+	g.computed += intensity
+	if g.computed > g.limit {
+		return errComputationLimitExceeded
+	}
+	return nil
+}
+
+func TestExecutorMetering(t *testing.T) {
+
+	t.Parallel()
+
+	// Counts down the argument to zero
+
+	instructions := []wasm.Instruction{
+		wasm.InstructionBlock{
+			Block: wasm.Block{
+				Instructions1: []wasm.Instruction{
+					wasm.InstructionLoop{
+						Block: wasm.Block{
+							Instructions1: []wasm.Instruction{
+								wasm.InstructionLocalGet{LocalIndex: 0},
+								wasm.InstructionI32Eqz{},
+								wasm.InstructionBrIf{LabelIndex: 1},
+								wasm.InstructionLocalGet{LocalIndex: 0},
+								wasm.InstructionI32Const{Value: 1},
+								wasm.InstructionI32Sub{},
+								wasm.InstructionLocalSet{LocalIndex: 0},
+								wasm.InstructionBr{LabelIndex: 0},
+							},
+						},
+					},
+				},
+			},
+		},
+		wasm.InstructionLocalGet{LocalIndex: 0},
+	}
+
+	t.Run("count", func(t *testing.T) {
+
+		t.Parallel()
+
+		gauge := &testComputationGauge{
+			limit: math.MaxUint,
+		}
+
+		instance := newTestInstance(t, i32ToI32FunctionType, instructions, &Config{
+			ComputationGauge: gauge,
+		})
+
+		_, err := instance.Invoke("test", int32(3))
+		require.NoError(t, err)
+
+		// block, loop, 3 * 8 instructions of the loop body,
+		// 3 instructions of the last iteration, and the final local.get
+		require.Equal(t, uint(2+3*8+3+1), gauge.computed)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+
+		t.Parallel()
+
+		gauge := &testComputationGauge{
+			limit: 10_000,
+		}
+
+		instance := newTestInstance(t, i32ToI32FunctionType, instructions, &Config{
+			ComputationGauge: gauge,
+		})
+
+		_, err := instance.Invoke("test", int32(math.MaxInt32))
+		require.ErrorIs(t, err, errComputationLimitExceeded)
+
+		// The instance can be used after the failure
+
+		gauge.limit = math.MaxUint
+
+		results, err := instance.Invoke("test", int32(1))
+		require.NoError(t, err)
+		require.Equal(t, []Value{int32(0)}, results)
+	})
+}
+
+func TestExecutorCallDepthLimit(t *testing.T) {
+
+	t.Parallel()
+
+	// Calls itself indefinitely
+
+	instance := newTestInstance(t,
+		&wasm.FunctionType{},
+		[]wasm.Instruction{
+			wasm.InstructionCall{FuncIndex: 0},
+		},
+		&Config{
+			MaxCallDepth: 100,
+		},
+	)
+
+	_, err := instance.Invoke("test")
+	require.Equal(t, Trap{Message: "call stack exhausted"}, err)
+}
@@ -0,0 +1,340 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"math"
+	"math/bits"
+
+	"github.com/onflow/cadence/runtime/compiler/wasm"
+)
+
+// executeNumeric executes the given numeric instruction
+func (i *Instance) executeNumeric(instruction wasm.Instruction) {
+	switch instruction := instruction.(type) {
+
+	case wasm.InstructionI32Const:
+		i.push(instruction.Value)
+
+	case wasm.InstructionI64Const:
+		i.push(instruction.Value)
+
+	// i32 comparisons
+
+	case wasm.InstructionI32Eqz:
+		i.push(boolToI32(i.popI32() == 0))
+
+	case wasm.InstructionI32Eq:
+		a, b := i.popI32Operands()
+		i.push(boolToI32(a == b))
+
+	case wasm.InstructionI32Ne:
+		a, b := i.popI32Operands()
+		i.push(boolToI32(a != b))
+
+	case wasm.InstructionI32LtS:
+		a, b := i.popI32Operands()
+		i.push(boolToI32(a < b))
+
+	case wasm.InstructionI32LtU:
+		a, b := i.popI32Operands()
+		i.push(boolToI32(uint32(a) < uint32(b)))
+
+	case wasm.InstructionI32GtS:
+		a, b := i.popI32Operands()
+		i.push(boolToI32(a > b))
+
+	case wasm.InstructionI32GtU:
+		a, b := i.popI32Operands()
+		i.push(boolToI32(uint32(a) > uint32(b)))
+
+	case wasm.InstructionI32LeS:
+		a, b := i.popI32Operands()
+		i.push(boolToI32(a <= b))
+
+	case wasm.InstructionI32LeU:
+		a, b := i.popI32Operands()
+		i.push(boolToI32(uint32(a) <= uint32(b)))
+
+	case wasm.InstructionI32GeS:
+		a, b := i.popI32Operands()
+		i.push(boolToI32(a >= b))
+
+	case wasm.InstructionI32GeU:
+		a, b := i.popI32Operands()
+		i.push(boolToI32(uint32(a) >= uint32(b)))
+
+	// i64 comparisons
+
+	case wasm.InstructionI64Eqz:
+		i.push(boolToI32(i.popI64() == 0))
+
+	case wasm.InstructionI64Eq:
+		a, b := i.popI64Operands()
+		i.push(boolToI32(a == b))
+
+	case wasm.InstructionI64Ne:
+		a, b := i.popI64Operands()
+		i.push(boolToI32(a != b))
+
+	case wasm.InstructionI64LtS:
+		a, b := i.popI64Operands()
+		i.push(boolToI32(a < b))
+
+	case wasm.InstructionI64LtU:
+		a, b := i.popI64Operands()
+		i.push(boolToI32(uint64(a) < uint64(b)))
+
+	case wasm.InstructionI64GtS:
+		a, b := i.popI64Operands()
+		i.push(boolToI32(a > b))
+
+	case wasm.InstructionI64GtU:
+		a, b := i.popI64Operands()
+		i.push(boolToI32(uint64(a) > uint64(b)))
+
+	case wasm.InstructionI64LeS:
+		a, b := i.popI64Operands()
+		i.push(boolToI32(a <= b))
+
+	case wasm.InstructionI64LeU:
+		a, b := i.popI64Operands()
+		i.push(boolToI32(uint64(a) <= uint64(b)))
+
+	case wasm.InstructionI64GeS:
+		a, b := i.popI64Operands()
+		i.push(boolToI32(a >= b))
+
+	case wasm.InstructionI64GeU:
+		a, b := i.popI64Operands()
+		i.push(boolToI32(uint64(a) >= uint64(b)))
+
+	// i32 arithmetic
+
+	case wasm.InstructionI32Clz:
+		i.push(int32(bits.LeadingZeros32(uint32(i.popI32()))))
+
+	case wasm.InstructionI32Ctz:
+		i.push(int32(bits.TrailingZeros32(uint32(i.popI32()))))
+
+	case wasm.InstructionI32Popcnt:
+		i.push(int32(bits.OnesCount32(uint32(i.popI32()))))
+
+	case wasm.InstructionI32Add:
+		a, b := i.popI32Operands()
+		i.push(a + b)
+
+	case wasm.InstructionI32Sub:
+		a, b := i.popI32Operands()
+		i.push(a - b)
+
+	case wasm.InstructionI32Mul:
+		a, b := i.popI32Operands()
+		i.push(a * b)
+
+	case wasm.InstructionI32DivS:
+		a, b := i.popI32Operands()
+		if b == 0 {
+			trap("integer divide by zero")
+		}
+		if a == math.MinInt32 && b == -1 {
+			trap("integer overflow")
+		}
+		i.push(a / b)
+
+	case wasm.InstructionI32DivU:
+		a, b := i.popI32Operands()
+		if b == 0 {
+			trap("integer divide by zero")
+		}
+		i.push(int32(uint32(a) / uint32(b)))
+
+	case wasm.InstructionI32RemS:
+		a, b := i.popI32Operands()
+		if b == 0 {
+			trap("integer divide by zero")
+		}
+		// NOTE: math.MinInt32 % -1 is 0 in Go, as required
+		i.push(a % b)
+
+	case wasm.InstructionI32RemU:
+		a, b := i.popI32Operands()
+		if b == 0 {
+			trap("integer divide by zero")
+		}
+		i.push(int32(uint32(a) % uint32(b)))
+
+	case wasm.InstructionI32And:
+		a, b := i.popI32Operands()
+		i.push(a & b)
+
+	case wasm.InstructionI32Or:
+		a, b := i.popI32Operands()
+		i.push(a | b)
+
+	case wasm.InstructionI32Xor:
+		a, b := i.popI32Operands()
+		i.push(a ^ b)
+
+	// NOTE: shift and rotation counts are taken modulo the bit width
+
+	case wasm.InstructionI32Shl:
+		a, b := i.popI32Operands()
+		i.push(a << (uint32(b) % 32))
+
+	case wasm.InstructionI32ShrS:
+		a, b := i.popI32Operands()
+		i.push(a >> (uint32(b) % 32))
+
+	case wasm.InstructionI32ShrU:
+		a, b := i.popI32Operands()
+		i.push(int32(uint32(a) >> (uint32(b) % 32)))
+
+	case wasm.InstructionI32Rotl:
+		a, b := i.popI32Operands()
+		i.push(int32(bits.RotateLeft32(uint32(a), int(uint32(b)%32))))
+
+	case wasm.InstructionI32Rotr:
+		a, b := i.popI32Operands()
+		i.push(int32(bits.RotateLeft32(uint32(a), -int(uint32(b)%32))))
+
+	// i64 arithmetic
+
+	case wasm.InstructionI64Clz:
+		i.push(int64(bits.LeadingZeros64(uint64(i.popI64()))))
+
+	case wasm.InstructionI64Ctz:
+		i.push(int64(bits.TrailingZeros64(uint64(i.popI64()))))
+
+	case wasm.InstructionI64Popcnt:
+		i.push(int64(bits.OnesCount64(uint64(i.popI64()))))
+
+	case wasm.InstructionI64Add:
+		a, b := i.popI64Operands()
+		i.push(a + b)
+
+	case wasm.InstructionI64Sub:
+		a, b := i.popI64Operands()
+		i.push(a - b)
+
+	case wasm.InstructionI64Mul:
+		a, b := i.popI64Operands()
+		i.push(a * b)
+
+	case wasm.InstructionI64DivS:
+		a, b := i.popI64Operands()
+		if b == 0 {
+			trap("integer divide by zero")
+		}
+		if a == math.MinInt64 && b == -1 {
+			trap("integer overflow")
+		}
+		i.push(a / b)
+
+	case wasm.InstructionI64DivU:
+		a, b := i.popI64Operands()
+		if b == 0 {
+			trap("integer divide by zero")
+		}
+		i.push(int64(uint64(a) / uint64(b)))
+
+	case wasm.InstructionI64RemS:
+		a, b := i.popI64Operands()
+		if b == 0 {
+			trap("integer divide by zero")
+		}
+		i.push(a % b)
+
+	case wasm.InstructionI64RemU:
+		a, b := i.popI64Operands()
+		if b == 0 {
+			trap("integer divide by zero")
+		}
+		i.push(int64(uint64(a) % uint64(b)))
+
+	case wasm.InstructionI64And:
+		a, b := i.popI64Operands()
+		i.push(a & b)
+
+	case wasm.InstructionI64Or:
+		a, b := i.popI64Operands()
+		i.push(a | b)
+
+	case wasm.InstructionI64Xor:
+		a, b := i.popI64Operands()
+		i.push(a ^ b)
+
+	case wasm.InstructionI64Shl:
+		a, b := i.popI64Operands()
+		i.push(a << (uint64(b) % 64))
+
+	case wasm.InstructionI64ShrS:
+		a, b := i.popI64Operands()
+		i.push(a >> (uint64(b) % 64))
+
+	case wasm.InstructionI64ShrU:
+		a, b := i.popI64Operands()
+		i.push(int64(uint64(a) >> (uint64(b) % 64)))
+
+	case wasm.InstructionI64Rotl:
+		a, b := i.popI64Operands()
+		i.push(int64(bits.RotateLeft64(uint64(a), int(uint64(b)%64))))
+
+	case wasm.InstructionI64Rotr:
+		a, b := i.popI64Operands()
+		i.push(int64(bits.RotateLeft64(uint64(a), -int(uint64(b)%64))))
+
+	// conversions
+
+	case wasm.InstructionI32WrapI64:
+		i.push(int32(i.popI64()))
+
+	case wasm.InstructionI64ExtendI32S:
+		i.push(int64(i.popI32()))
+
+	case wasm.InstructionI64ExtendI32U:
+		i.push(int64(uint32(i.popI32())))
+
+	default:
+		trap("unsupported instruction: %T", instruction)
+	}
+}
+
+// popI32Operands pops the operands of a binary i32 instruction,
+// in the order they were pushed
+func (i *Instance) popI32Operands() (int32, int32) {
+	b := i.popI32()
+	a := i.popI32()
+	return a, b
+}
+
+// popI64Operands pops the operands of a binary i64 instruction,
+// in the order they were pushed
+func (i *Instance) popI64Operands() (int64, int64) {
+	b := i.popI64()
+	a := i.popI64()
+	return a, b
+}
+
+func boolToI32(b bool) int32 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -348,7 +348,7 @@ func (r *WASMReader) readValType() (ValueType, error) {
 	}
 
 	switch valType {
-	case ValueTypeI32, ValueTypeI64:
+	case ValueTypeI32, ValueTypeI64, ValueTypeFuncRef, ValueTypeExternRef:
 		return valType, nil
 	}
 
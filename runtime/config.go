@@ -35,10 +35,16 @@ type Config struct {
 	ResourceOwnerChangeHandlerEnabled bool
 	// CoverageReport enables and collects coverage reporting metrics
 	CoverageReport *CoverageReport
+	// TraceRecorder enables tracing and records the reported spans,
+	// in addition to the host environment, if tracing is enabled
+	TraceRecorder *TraceRecorder
 	// AccountLinkingEnabled specifies if account linking is enabled
 	AccountLinkingEnabled bool
 	// AttachmentsEnabled specifies if attachments are enabled
 	AttachmentsEnabled bool
 	// CapabilityControllersEnabled specifies if capability controllers are enabled
 	CapabilityControllersEnabled bool
+	// ScriptBytecodeVMEnabled specifies if scripts and transactions are executed by the bytecode virtual machine.
+	// Programs which use features not supported by the compiler yet are interpreted
+	ScriptBytecodeVMEnabled bool
 }
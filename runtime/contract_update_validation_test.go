@@ -1196,7 +1196,7 @@ func TestRuntimeContractUpdateValidation(t *testing.T) {
 		err := testDeployAndUpdate(t, "Test", oldCode, newCode)
 		RequireError(t, err)
 
-		assert.Contains(t, err.Error(), "error: field add has non-storable type: ((Int, Int): Int)")
+		assert.Contains(t, err.Error(), "error[C0033]: field add has non-storable type: ((Int, Int): Int)")
 	})
 
 	t.Run("Test conformance", func(t *testing.T) {
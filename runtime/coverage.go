@@ -21,13 +21,42 @@ package runtime
 import (
 	"bytes"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"path/filepath"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
 )
 
+// BranchPoint identifies an element which branches, e.g. an if statement,
+// by its kind and its position.
+// See interpreter.BranchKind for the branches of each kind of element.
+type BranchPoint struct {
+	Kind   interpreter.BranchKind
+	Line   int
+	Column int
+}
+
+// NewBranchPoint returns the BranchPoint for the given branching element.
+func NewBranchPoint(kind interpreter.BranchKind, element ast.Element) BranchPoint {
+	position := element.StartPosition()
+	// Chained optional member accesses, e.g. `a?.b?.c`, start at the same position,
+	// so they are identified by the position of their `?.` token instead.
+	if memberExpression, ok := element.(*ast.MemberExpression); ok {
+		position = memberExpression.AccessPos
+	}
+	return BranchPoint{
+		Kind:   kind,
+		Line:   position.Line,
+		Column: position.Column,
+	}
+}
+
 // LocationCoverage records coverage information for a location.
 type LocationCoverage struct {
 	// Contains hit count for each line on a given location.
@@ -35,6 +64,25 @@ type LocationCoverage struct {
 	LineHits map[int]int
 	// Total number of statements on a given location.
 	Statements int
+	// Contains hit count for each branch of each branching element
+	// on a given location. A hit count of 0 means the branch was not taken.
+	BranchHits map[BranchPoint][]int
+	// Contains hit count for each function declared on a given location,
+	// by qualified name, e.g. `Vault.withdraw`.
+	// A hit count of 0 means the function was never invoked.
+	FunctionHits map[string]int
+	// Contains the line of each function declared on a given location.
+	FunctionLines map[string]int
+	// Contains the qualified name of each function declared on a given location,
+	// by the position of its declaration.
+	functionNames map[functionPosition]string
+}
+
+// functionPosition identifies a function declaration by its position.
+// The offset is not part of it, as it is not serialized.
+type functionPosition struct {
+	Line   int
+	Column int
 }
 
 // AddLineHit increments the hit count for the given line.
@@ -70,6 +118,105 @@ func (c *LocationCoverage) Percentage() string {
 	return fmt.Sprintf("%0.1f%%", percentage)
 }
 
+// AddBranchHit increments the hit count for the given branch
+// of the given branch point.
+func (c *LocationCoverage) AddBranchHit(branchPoint BranchPoint, branch int) {
+	// Branches which were not inspected are dropped.
+	hits, ok := c.BranchHits[branchPoint]
+	if !ok || branch < 0 || branch >= len(hits) {
+		return
+	}
+	hits[branch]++
+}
+
+// AddFunctionHit increments the hit count for the function
+// declared at the given position.
+func (c *LocationCoverage) AddFunctionHit(position ast.Position) {
+	// Functions which were not inspected are dropped.
+	name, ok := c.functionNames[functionPosition{
+		Line:   position.Line,
+		Column: position.Column,
+	}]
+	if !ok {
+		return
+	}
+	c.FunctionHits[name]++
+}
+
+// Branches returns the count of branches for a given location.
+func (c *LocationCoverage) Branches() int {
+	branches := 0
+	for _, hits := range c.BranchHits { // nolint:maprange
+		branches += len(hits)
+	}
+	return branches
+}
+
+// CoveredBranches returns the count of covered branches for a given location.
+// This is the number of branches with a hit count > 0.
+func (c *LocationCoverage) CoveredBranches() int {
+	coveredBranches := 0
+	for _, hits := range c.BranchHits { // nolint:maprange
+		for _, branchHits := range hits {
+			if branchHits > 0 {
+				coveredBranches += 1
+			}
+		}
+	}
+	return coveredBranches
+}
+
+// BranchPercentage returns a string representation of the covered
+// branches percentage. It is defined as the ratio of covered
+// branches over the total branches for a given location.
+func (c *LocationCoverage) BranchPercentage() string {
+	return coveragePercentage(c.CoveredBranches(), c.Branches())
+}
+
+// CoveredFunctions returns the count of covered functions for a given location.
+// This is the number of functions with a hit count > 0.
+func (c *LocationCoverage) CoveredFunctions() int {
+	coveredFunctions := 0
+	for _, hits := range c.FunctionHits { // nolint:maprange
+		if hits > 0 {
+			coveredFunctions += 1
+		}
+	}
+	return coveredFunctions
+}
+
+// MissedFunctions returns an array with the names of the missed functions
+// for a given location. These are all the functions with a hit count == 0.
+// The resulting array is sorted in ascending order.
+func (c *LocationCoverage) MissedFunctions() []string {
+	missedFunctions := make([]string, 0)
+	for name, hits := range c.FunctionHits { // nolint:maprange
+		if hits == 0 {
+			missedFunctions = append(missedFunctions, name)
+		}
+	}
+	sort.Strings(missedFunctions)
+	return missedFunctions
+}
+
+// FunctionPercentage returns a string representation of the covered
+// functions percentage. It is defined as the ratio of covered
+// functions over the total functions for a given location.
+func (c *LocationCoverage) FunctionPercentage() string {
+	return coveragePercentage(c.CoveredFunctions(), len(c.FunctionHits))
+}
+
+// coveragePercentage returns a string representation of the ratio
+// of the given covered count over the given total. If there is nothing
+// to cover, the percentage is 100%.
+func coveragePercentage(covered int, total int) string {
+	var percentage float64 = 100
+	if total != 0 {
+		percentage = 100 * float64(covered) / float64(total)
+	}
+	return fmt.Sprintf("%0.1f%%", percentage)
+}
+
 // CoveredLines returns the count of covered lines for a given location.
 // This is the number of lines with a hit count > 0.
 func (c *LocationCoverage) CoveredLines() int {
@@ -100,13 +247,23 @@ func (c *LocationCoverage) MissedLines() []int {
 // given lineHits map.
 func NewLocationCoverage(lineHits map[int]int) *LocationCoverage {
 	return &LocationCoverage{
-		LineHits:   lineHits,
-		Statements: len(lineHits),
+		LineHits:      lineHits,
+		Statements:    len(lineHits),
+		BranchHits:    map[BranchPoint][]int{},
+		FunctionHits:  map[string]int{},
+		FunctionLines: map[string]int{},
+		functionNames: map[functionPosition]string{},
 	}
 }
 
 type LocationFilter func(location Location) bool
 
+// LocationPathResolver returns the path of the file which contains
+// the program of the given location. It is used when exporting
+// a CoverageReport to formats which refer to source files,
+// such as LCOV and Cobertura.
+type LocationPathResolver func(location Location) string
+
 // CoverageReport collects coverage information per location.
 // It keeps track of inspected locations, and can also exclude
 // locations from coverage collection.
@@ -120,6 +277,10 @@ type CoverageReport struct {
 	// This filter can be used to inject custom logic on
 	// each location/program inspection.
 	LocationFilter LocationFilter `json:"-"`
+	// This resolver can be used to map locations to file paths,
+	// when exporting to the LCOV or Cobertura format.
+	// By default, the ID of the location is used.
+	LocationPathResolver LocationPathResolver `json:"-"`
 }
 
 // WithLocationFilter sets the LocationFilter for the current
@@ -130,6 +291,14 @@ func (r *CoverageReport) WithLocationFilter(
 	r.LocationFilter = locationFilter
 }
 
+// WithLocationPathResolver sets the LocationPathResolver for the
+// current CoverageReport.
+func (r *CoverageReport) WithLocationPathResolver(
+	locationPathResolver LocationPathResolver,
+) {
+	r.LocationPathResolver = locationPathResolver
+}
+
 // ExcludeLocation adds the given location to the map of excluded
 // locations.
 func (r *CoverageReport) ExcludeLocation(location Location) {
@@ -160,8 +329,46 @@ func (r *CoverageReport) AddLineHit(location Location, line int) {
 	locationCoverage.AddLineHit(line)
 }
 
+// AddBranchHit increments the hit count for the given branch of the
+// given branching element, on the given location. The method call is
+// a NO-OP in the same cases as AddLineHit.
+func (r *CoverageReport) AddBranchHit(
+	location Location,
+	kind interpreter.BranchKind,
+	element ast.Element,
+	branch int,
+) {
+	if r.IsLocationExcluded(location) {
+		return
+	}
+
+	if !r.IsLocationInspected(location) {
+		return
+	}
+
+	locationCoverage := r.Coverage[location]
+	locationCoverage.AddBranchHit(NewBranchPoint(kind, element), branch)
+}
+
+// AddFunctionHit increments the hit count for the given function
+// declaration, on the given location. The method call is a NO-OP
+// in the same cases as AddLineHit.
+func (r *CoverageReport) AddFunctionHit(location Location, declaration *ast.FunctionDeclaration) {
+	if r.IsLocationExcluded(location) {
+		return
+	}
+
+	if !r.IsLocationInspected(location) {
+		return
+	}
+
+	locationCoverage := r.Coverage[location]
+	locationCoverage.AddFunctionHit(declaration.StartPos)
+}
+
 // InspectProgram inspects the elements of the given *ast.Program, and counts its
-// statements. If inspection is successful, the location is marked as inspected.
+// statements, branches and functions. If inspection is successful, the location
+// is marked as inspected.
 // If the given location is excluded from coverage collection, the method call
 // results in a NO-OP.
 // If the CoverageReport.LocationFilter is present, and calling it with the given
@@ -179,50 +386,166 @@ func (r *CoverageReport) InspectProgram(location Location, program *ast.Program)
 		line := hasPosition.StartPosition().Line
 		lineHits[line] = 0
 	}
-	var depth int
 
-	inspector := ast.NewInspector(program)
-	inspector.Elements(
-		nil, func(element ast.Element, push bool) bool {
-			if push {
-				depth++
-
-				_, isStatement := element.(ast.Statement)
-				_, isDeclaration := element.(ast.Declaration)
-				_, isVariableDeclaration := element.(*ast.VariableDeclaration)
-
-				// Track only the statements that are not declarations, such as:
-				// - *ast.CompositeDeclaration
-				// - *ast.SpecialFunctionDeclaration
-				// - *ast.FunctionDeclaration
-				// However, also track local (i.e. non-top level) variable declarations.
-				if (isStatement && !isDeclaration) ||
-					(isVariableDeclaration && depth > 2) {
-					recordLine(element)
+	locationCoverage := NewLocationCoverage(lineHits)
+
+	recordBranchPoint := func(kind interpreter.BranchKind, element ast.Element, branches int) {
+		locationCoverage.BranchHits[NewBranchPoint(kind, element)] = make([]int, branches)
+	}
+
+	// Conditions are not walked by the inspector,
+	// so the branching elements in their tests are recorded separately.
+	var recordBranchPoints func(element ast.Element)
+
+	recordConditions := func(conditions *ast.Conditions) {
+		if conditions == nil {
+			return
+		}
+		for _, condition := range *conditions {
+			recordBranchPoint(interpreter.BranchKindCondition, condition.Test, 2)
+			ast.NewInspector(condition.Test).Preorder(nil, recordBranchPoints)
+		}
+	}
+
+	recordBranchPoints = func(element ast.Element) {
+		switch element := element.(type) {
+		case *ast.IfStatement:
+			recordBranchPoint(interpreter.BranchKindIf, element, 2)
+
+		case *ast.ConditionalExpression:
+			recordBranchPoint(interpreter.BranchKindConditional, element, 2)
+
+		case *ast.SwitchStatement:
+			// If there is no default case, there is an additional
+			// branch for when no case matched
+			branches := len(element.Cases) + 1
+			for _, switchCase := range element.Cases {
+				if switchCase.Expression == nil {
+					branches--
+					break
 				}
+			}
+			recordBranchPoint(interpreter.BranchKindSwitch, element, branches)
+
+		case *ast.BinaryExpression:
+			if element.Operation == ast.OperationNilCoalesce {
+				recordBranchPoint(interpreter.BranchKindNilCoalescing, element, 2)
+			}
 
-				functionBlock, isFunctionBlock := element.(*ast.FunctionBlock)
-				// Track also pre/post conditions defined inside functions.
-				if isFunctionBlock {
-					if functionBlock.PreConditions != nil {
-						for _, condition := range *functionBlock.PreConditions {
-							recordLine(condition.Test)
-						}
+		case *ast.MemberExpression:
+			if element.Optional {
+				recordBranchPoint(interpreter.BranchKindOptionalChaining, element, 2)
+			}
+
+		case *ast.FunctionBlock:
+			recordConditions(element.PreConditions)
+			recordConditions(element.PostConditions)
+
+		case *ast.TransactionDeclaration:
+			recordConditions(element.PreConditions)
+			recordConditions(element.PostConditions)
+		}
+	}
+
+	recordFunction := func(declaration *ast.FunctionDeclaration, name string, stack []ast.Element) {
+		// Functions without a body, e.g. in interfaces, cannot be invoked
+		if declaration.FunctionBlock == nil {
+			return
+		}
+		qualifiedName := qualifiedFunctionName(name, stack)
+		locationCoverage.FunctionHits[qualifiedName] = 0
+		locationCoverage.FunctionLines[qualifiedName] = declaration.StartPos.Line
+		locationCoverage.functionNames[functionPosition{
+			Line:   declaration.StartPos.Line,
+			Column: declaration.StartPos.Column,
+		}] = qualifiedName
+	}
+
+	inspector := ast.NewInspector(program)
+	inspector.WithStack(
+		nil, func(element ast.Element, push bool, stack []ast.Element) bool {
+			if !push {
+				return true
+			}
+
+			depth := len(stack)
+
+			_, isStatement := element.(ast.Statement)
+			_, isDeclaration := element.(ast.Declaration)
+			_, isVariableDeclaration := element.(*ast.VariableDeclaration)
+
+			// Track only the statements that are not declarations, such as:
+			// - *ast.CompositeDeclaration
+			// - *ast.SpecialFunctionDeclaration
+			// - *ast.FunctionDeclaration
+			// However, also track local (i.e. non-top level) variable declarations.
+			if (isStatement && !isDeclaration) ||
+				(isVariableDeclaration && depth > 2) {
+				recordLine(element)
+			}
+
+			functionBlock, isFunctionBlock := element.(*ast.FunctionBlock)
+			// Track also pre/post conditions defined inside functions.
+			if isFunctionBlock {
+				if functionBlock.PreConditions != nil {
+					for _, condition := range *functionBlock.PreConditions {
+						recordLine(condition.Test)
 					}
-					if functionBlock.PostConditions != nil {
-						for _, condition := range *functionBlock.PostConditions {
-							recordLine(condition.Test)
-						}
+				}
+				if functionBlock.PostConditions != nil {
+					for _, condition := range *functionBlock.PostConditions {
+						recordLine(condition.Test)
 					}
 				}
-			} else {
-				depth--
+			}
+
+			recordBranchPoints(element)
+
+			// Track the declared functions, including special functions,
+			// e.g. initializers, and the prepare and execute blocks of transactions.
+			outerStack := stack[:depth-1]
+			switch element := element.(type) {
+			case *ast.FunctionDeclaration:
+				recordFunction(element, element.Identifier.Identifier, outerStack)
+			case *ast.SpecialFunctionDeclaration:
+				recordFunction(element.FunctionDeclaration, element.Kind.Keywords(), outerStack)
 			}
 
 			return true
 		})
 
-	r.Coverage[location] = NewLocationCoverage(lineHits)
+	locationCoverage.Statements = len(lineHits)
+	r.Coverage[location] = locationCoverage
+}
+
+// qualifiedFunctionName returns the name of a function, qualified by
+// the names of the enclosing declarations in the given stack,
+// e.g. `Vault.withdraw`.
+func qualifiedFunctionName(name string, stack []ast.Element) string {
+	var qualifiedName strings.Builder
+	for _, element := range stack {
+		var enclosingName string
+		switch element := element.(type) {
+		case *ast.CompositeDeclaration:
+			enclosingName = element.Identifier.Identifier
+		case *ast.InterfaceDeclaration:
+			enclosingName = element.Identifier.Identifier
+		case *ast.AttachmentDeclaration:
+			enclosingName = element.Identifier.Identifier
+		case *ast.FunctionDeclaration:
+			enclosingName = element.Identifier.Identifier
+		case *ast.SpecialFunctionDeclaration:
+			enclosingName = element.Kind.Keywords()
+		case *ast.TransactionDeclaration:
+			enclosingName = "transaction"
+		default:
+			continue
+		}
+		qualifiedName.WriteString(enclosingName)
+		qualifiedName.WriteByte('.')
+	}
+	qualifiedName.WriteString(name)
+	return qualifiedName.String()
 }
 
 // IsLocationInspected checks whether the given location,
@@ -269,8 +592,8 @@ func (r *CoverageReport) Reset() {
 }
 
 // Merge adds all the collected coverage information to the
-// calling object. Excluded locations are also taken into
-// account.
+// calling object, i.e. line, branch and function coverage.
+// Excluded locations are also taken into account.
 func (r *CoverageReport) Merge(other CoverageReport) {
 	for location, locationCoverage := range other.Coverage { // nolint:maprange
 		r.Coverage[location] = locationCoverage
@@ -328,20 +651,93 @@ func (r *CoverageReport) Misses() int {
 	return r.Statements() - r.Hits()
 }
 
+// Branches returns the total count of branches, for all the
+// locations included in the CoverageReport.
+func (r *CoverageReport) Branches() int {
+	totalBranches := 0
+	for _, locationCoverage := range r.Coverage { // nolint:maprange
+		totalBranches += locationCoverage.Branches()
+	}
+	return totalBranches
+}
+
+// BranchHits returns the total count of covered branches, for all
+// the locations included in the CoverageReport.
+func (r *CoverageReport) BranchHits() int {
+	totalCoveredBranches := 0
+	for _, locationCoverage := range r.Coverage { // nolint:maprange
+		totalCoveredBranches += locationCoverage.CoveredBranches()
+	}
+	return totalCoveredBranches
+}
+
+// BranchMisses returns the total count of non-covered branches,
+// for all the locations included in the CoverageReport.
+func (r *CoverageReport) BranchMisses() int {
+	return r.Branches() - r.BranchHits()
+}
+
+// BranchPercentage returns a string representation of the covered
+// branches percentage, for all locations.
+func (r *CoverageReport) BranchPercentage() string {
+	return coveragePercentage(r.BranchHits(), r.Branches())
+}
+
+// Functions returns the total count of declared functions, for all
+// the locations included in the CoverageReport.
+func (r *CoverageReport) Functions() int {
+	totalFunctions := 0
+	for _, locationCoverage := range r.Coverage { // nolint:maprange
+		totalFunctions += len(locationCoverage.FunctionHits)
+	}
+	return totalFunctions
+}
+
+// FunctionHits returns the total count of invoked functions, for all
+// the locations included in the CoverageReport.
+func (r *CoverageReport) FunctionHits() int {
+	totalCoveredFunctions := 0
+	for _, locationCoverage := range r.Coverage { // nolint:maprange
+		totalCoveredFunctions += locationCoverage.CoveredFunctions()
+	}
+	return totalCoveredFunctions
+}
+
+// FunctionMisses returns the total count of functions which were
+// never invoked, for all the locations included in the CoverageReport.
+func (r *CoverageReport) FunctionMisses() int {
+	return r.Functions() - r.FunctionHits()
+}
+
+// FunctionPercentage returns a string representation of the covered
+// functions percentage, for all locations.
+func (r *CoverageReport) FunctionPercentage() string {
+	return coveragePercentage(r.FunctionHits(), r.Functions())
+}
+
 // Summary returns a CoverageReportSummary object, containing
 // key metrics for a CoverageReport, such as:
 // - Total Locations,
 // - Total Statements,
 // - Total Hits,
 // - Total Misses,
-// - Overall Coverage Percentage.
+// - Overall Coverage Percentage,
+// - and the same metrics for branches and functions.
 func (r *CoverageReport) Summary() CoverageReportSummary {
 	return CoverageReportSummary{
-		Locations:  r.TotalLocations(),
-		Statements: r.Statements(),
-		Hits:       r.Hits(),
-		Misses:     r.Misses(),
-		Coverage:   r.Percentage(),
+		Locations:        r.TotalLocations(),
+		Statements:       r.Statements(),
+		Hits:             r.Hits(),
+		Misses:           r.Misses(),
+		Coverage:         r.Percentage(),
+		Branches:         r.Branches(),
+		BranchHits:       r.BranchHits(),
+		BranchMisses:     r.BranchMisses(),
+		BranchCoverage:   r.BranchPercentage(),
+		Functions:        r.Functions(),
+		FunctionHits:     r.FunctionHits(),
+		FunctionMisses:   r.FunctionMisses(),
+		FunctionCoverage: r.FunctionPercentage(),
 	}
 }
 
@@ -363,37 +759,82 @@ func (r *CoverageReport) Summary() CoverageReportSummary {
 // - Hits increased by 2,
 // - Misses decreased by 2,
 // - Coverage Δ increased by 100.0%.
+//
+// Branches and functions are compared in the same way.
 func (r *CoverageReport) Diff(other CoverageReport) CoverageReportSummary {
-	baseCoverage := 100 * float64(r.Hits()) / float64(r.Statements())
-	newCoverage := 100 * float64(other.Hits()) / float64(other.Statements())
-	coverageDelta := fmt.Sprintf(
-		"%0.1f%%",
-		100*(newCoverage-baseCoverage)/baseCoverage,
-	)
 	return CoverageReportSummary{
 		Locations:  other.TotalLocations() - r.TotalLocations(),
 		Statements: other.Statements() - r.Statements(),
 		Hits:       other.Hits() - r.Hits(),
 		Misses:     other.Misses() - r.Misses(),
-		Coverage:   coverageDelta,
+		Coverage: coverageDelta(
+			r.Hits(), r.Statements(),
+			other.Hits(), other.Statements(),
+		),
+		Branches:     other.Branches() - r.Branches(),
+		BranchHits:   other.BranchHits() - r.BranchHits(),
+		BranchMisses: other.BranchMisses() - r.BranchMisses(),
+		BranchCoverage: coverageDelta(
+			r.BranchHits(), r.Branches(),
+			other.BranchHits(), other.Branches(),
+		),
+		Functions:      other.Functions() - r.Functions(),
+		FunctionHits:   other.FunctionHits() - r.FunctionHits(),
+		FunctionMisses: other.FunctionMisses() - r.FunctionMisses(),
+		FunctionCoverage: coverageDelta(
+			r.FunctionHits(), r.Functions(),
+			other.FunctionHits(), other.Functions(),
+		),
 	}
 }
 
+// coverageDelta returns a string representation of the relative
+// change from the base coverage ratio to the new coverage ratio.
+// Like for the percentages, nothing to cover counts as full coverage.
+// The relative change from no coverage at all is undefined,
+// so in that case the change in percentage points is returned.
+func coverageDelta(baseHits, baseTotal, newHits, newTotal int) string {
+	coverageRatio := func(hits, total int) float64 {
+		if total == 0 {
+			return 100
+		}
+		return 100 * float64(hits) / float64(total)
+	}
+	baseCoverage := coverageRatio(baseHits, baseTotal)
+	newCoverage := coverageRatio(newHits, newTotal)
+	if baseCoverage == 0 {
+		return fmt.Sprintf("%0.1f%%", newCoverage)
+	}
+	return fmt.Sprintf(
+		"%0.1f%%",
+		100*(newCoverage-baseCoverage)/baseCoverage,
+	)
+}
+
 // CoverageReportSummary contains key metrics that are derived
 // from a CoverageReport object, such as:
 // - Total Locations,
 // - Total Statements,
 // - Total Hits,
 // - Total Misses,
-// - Overall Coverage Percentage.
+// - Overall Coverage Percentage,
+// - and the same metrics for branches and functions.
 // This metrics can be utilized in various ways, such as a CI
 // plugin/app.
 type CoverageReportSummary struct {
-	Locations  int    `json:"locations"`
-	Statements int    `json:"statements"`
-	Hits       int    `json:"hits"`
-	Misses     int    `json:"misses"`
-	Coverage   string `json:"coverage"`
+	Locations        int    `json:"locations"`
+	Statements       int    `json:"statements"`
+	Hits             int    `json:"hits"`
+	Misses           int    `json:"misses"`
+	Coverage         string `json:"coverage"`
+	Branches         int    `json:"branches"`
+	BranchHits       int    `json:"branch_hits"`
+	BranchMisses     int    `json:"branch_misses"`
+	BranchCoverage   string `json:"branch_coverage"`
+	Functions        int    `json:"functions"`
+	FunctionHits     int    `json:"function_hits"`
+	FunctionMisses   int    `json:"function_misses"`
+	FunctionCoverage string `json:"function_coverage"`
 }
 
 // NewCoverageReport creates and returns a *CoverageReport.
@@ -411,10 +852,65 @@ type crAlias CoverageReport
 // as fields in the LocationCoverage struct, we simply populate
 // this lcAlias struct, with the corresponding methods, upon marshalling.
 type lcAlias struct {
-	LineHits    map[int]int `json:"line_hits"`
-	MissedLines []int       `json:"missed_lines"`
-	Statements  int         `json:"statements"`
-	Percentage  string      `json:"percentage"`
+	LineHits           map[int]int    `json:"line_hits"`
+	MissedLines        []int          `json:"missed_lines"`
+	Statements         int            `json:"statements"`
+	Percentage         string         `json:"percentage"`
+	BranchHits         []bpAlias      `json:"branch_hits"`
+	Branches           int            `json:"branches"`
+	BranchPercentage   string         `json:"branch_percentage"`
+	FunctionHits       map[string]int `json:"function_hits"`
+	FunctionLines      map[string]int `json:"function_lines"`
+	FunctionColumns    map[string]int `json:"function_columns"`
+	MissedFunctions    []string       `json:"missed_functions"`
+	FunctionPercentage string         `json:"function_percentage"`
+}
+
+// The BranchPoint keys of the LocationCoverage.BranchHits map
+// are not strings, so each branch point and its hit counts
+// are serialized as a bpAlias.
+type bpAlias struct {
+	Kind   string `json:"kind"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Hits   []int  `json:"hits"`
+}
+
+// branchHitsAliases returns the branch hits of the given *LocationCoverage
+// as a list of bpAlias, sorted by position.
+func branchHitsAliases(locationCoverage *LocationCoverage) []bpAlias {
+	branchHits := make([]bpAlias, 0, len(locationCoverage.BranchHits))
+	for branchPoint, hits := range locationCoverage.BranchHits { // nolint:maprange
+		branchHits = append(branchHits, bpAlias{
+			Kind:   branchPoint.Kind.Name(),
+			Line:   branchPoint.Line,
+			Column: branchPoint.Column,
+			Hits:   hits,
+		})
+	}
+	sort.Slice(branchHits, func(i, j int) bool {
+		a, b := branchHits[i], branchHits[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.Kind < b.Kind
+	})
+	return branchHits
+}
+
+// functionColumns returns the column of each function declared
+// on the given *LocationCoverage, by qualified name. Together with
+// the function lines, they allow to record function hits on a
+// deserialized *LocationCoverage.
+func functionColumns(locationCoverage *LocationCoverage) map[string]int {
+	columns := make(map[string]int, len(locationCoverage.functionNames))
+	for position, name := range locationCoverage.functionNames { // nolint:maprange
+		columns[name] = position.Column
+	}
+	return columns
 }
 
 // MarshalJSON serializes each common.Location/*LocationCoverage
@@ -424,10 +920,18 @@ func (r *CoverageReport) MarshalJSON() ([]byte, error) {
 	coverage := make(map[string]lcAlias, len(r.Coverage))
 	for location, locationCoverage := range r.Coverage { // nolint:maprange
 		coverage[location.ID()] = lcAlias{
-			LineHits:    locationCoverage.LineHits,
-			MissedLines: locationCoverage.MissedLines(),
-			Statements:  locationCoverage.Statements,
-			Percentage:  locationCoverage.Percentage(),
+			LineHits:           locationCoverage.LineHits,
+			MissedLines:        locationCoverage.MissedLines(),
+			Statements:         locationCoverage.Statements,
+			Percentage:         locationCoverage.Percentage(),
+			BranchHits:         branchHitsAliases(locationCoverage),
+			Branches:           locationCoverage.Branches(),
+			BranchPercentage:   locationCoverage.BranchPercentage(),
+			FunctionHits:       locationCoverage.FunctionHits,
+			FunctionLines:      locationCoverage.FunctionLines,
+			FunctionColumns:    functionColumns(locationCoverage),
+			MissedFunctions:    locationCoverage.MissedFunctions(),
+			FunctionPercentage: locationCoverage.FunctionPercentage(),
 		}
 	}
 	return json.Marshal(&struct {
@@ -465,10 +969,37 @@ func (r *CoverageReport) UnmarshalJSON(data []byte) error {
 		if location == nil {
 			return fmt.Errorf("invalid Location ID: %s", locationID)
 		}
-		r.Coverage[location] = &LocationCoverage{
-			LineHits:   locationCoverage.LineHits,
-			Statements: locationCoverage.Statements,
+		decodedLocationCoverage := NewLocationCoverage(locationCoverage.LineHits)
+		decodedLocationCoverage.Statements = locationCoverage.Statements
+		for _, branchHits := range locationCoverage.BranchHits {
+			kind, ok := interpreter.BranchKindFromName(branchHits.Kind)
+			if !ok {
+				return fmt.Errorf("invalid branch kind: %s", branchHits.Kind)
+			}
+			branchPoint := BranchPoint{
+				Kind:   kind,
+				Line:   branchHits.Line,
+				Column: branchHits.Column,
+			}
+			decodedLocationCoverage.BranchHits[branchPoint] = branchHits.Hits
+		}
+		for name, hits := range locationCoverage.FunctionHits { // nolint:maprange
+			decodedLocationCoverage.FunctionHits[name] = hits
 		}
+		for name, line := range locationCoverage.FunctionLines { // nolint:maprange
+			decodedLocationCoverage.FunctionLines[name] = line
+		}
+		for name, column := range locationCoverage.FunctionColumns { // nolint:maprange
+			line, ok := locationCoverage.FunctionLines[name]
+			if !ok {
+				return fmt.Errorf("invalid function: %s", name)
+			}
+			decodedLocationCoverage.functionNames[functionPosition{
+				Line:   line,
+				Column: column,
+			}] = name
+		}
+		r.Coverage[location] = decodedLocationCoverage
 		r.Locations[location] = struct{}{}
 	}
 	for _, locationID := range cr.ExcludedLocations {
@@ -485,40 +1016,131 @@ func (r *CoverageReport) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-// MarshalLCOV serializes each common.Location/*LocationCoverage
-// key/value pair on the *CoverageReport.Coverage map, to the
-// LCOV format. Currently supports only line coverage, function
-// and branch coverage are not yet available.
-// Description for the LCOV file format, can be found here
-// https://github.com/linux-test-project/lcov/blob/master/man/geninfo.1#L948.
-func (r *CoverageReport) MarshalLCOV() ([]byte, error) {
-	i := 0
-	locations := make([]common.Location, len(r.Coverage))
+// locationPath returns the file path for the given location,
+// using the LocationPathResolver, if any.
+func (r *CoverageReport) locationPath(location Location) string {
+	if r.LocationPathResolver != nil {
+		return r.LocationPathResolver(location)
+	}
+	return location.ID()
+}
+
+// exportedLocations returns the locations which are exported
+// to the LCOV and Cobertura formats, sorted by their ID.
+// Excluded locations, and locations which are rejected by the
+// LocationFilter, are omitted. This matters for reports which
+// are unmarshalled from JSON, as their locations were not inspected
+// with the exclusions and the filter of the calling object.
+func (r *CoverageReport) exportedLocations() []common.Location {
+	locations := make([]common.Location, 0, len(r.Coverage))
 	for location := range r.Coverage { // nolint:maprange
-		locations[i] = location
-		i++
+		if r.IsLocationExcluded(location) {
+			continue
+		}
+		if r.LocationFilter != nil && !r.LocationFilter(location) {
+			continue
+		}
+		locations = append(locations, location)
 	}
 	sort.Slice(locations, func(i, j int) bool {
 		return locations[i].ID() < locations[j].ID()
 	})
+	return locations
+}
+
+// sortedFunctionNames returns the names of the functions of the given
+// *LocationCoverage, sorted by line, then by name.
+func sortedFunctionNames(coverage *LocationCoverage) []string {
+	names := make([]string, 0, len(coverage.FunctionHits))
+	for name := range coverage.FunctionHits { // nolint:maprange
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := names[i], names[j]
+		lineA, lineB := coverage.FunctionLines[a], coverage.FunctionLines[b]
+		if lineA != lineB {
+			return lineA < lineB
+		}
+		return a < b
+	})
+	return names
+}
 
+// sortedLines returns the lines of the given *LocationCoverage,
+// sorted in ascending order.
+func sortedLines(coverage *LocationCoverage) []int {
+	lines := make([]int, 0, len(coverage.LineHits))
+	for line := range coverage.LineHits { // nolint:maprange
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+	return lines
+}
+
+// MarshalLCOV serializes each common.Location/*LocationCoverage
+// key/value pair on the *CoverageReport.Coverage map, to the
+// LCOV format, including line, function and branch coverage.
+// Each branch point is reported as a block of branches.
+// Source files are named using the LocationPathResolver.
+// Excluded locations, and locations rejected by the LocationFilter,
+// are omitted.
+// Description for the LCOV file format, can be found here
+// https://github.com/linux-test-project/lcov/blob/master/man/geninfo.1#L948.
+func (r *CoverageReport) MarshalLCOV() ([]byte, error) {
 	buf := new(bytes.Buffer)
-	for _, location := range locations {
+	for _, location := range r.exportedLocations() {
 		coverage := r.Coverage[location]
-		_, err := fmt.Fprintf(buf, "TN:\nSF:%s\n", location.ID())
+		_, err := fmt.Fprintf(buf, "TN:\nSF:%s\n", r.locationPath(location))
 		if err != nil {
 			return nil, err
 		}
 
-		i := 0
-		lines := make([]int, len(coverage.LineHits))
-		for line := range coverage.LineHits { // nolint:maprange
-			lines[i] = line
-			i++
+		if len(coverage.FunctionHits) > 0 {
+			functionNames := sortedFunctionNames(coverage)
+			for _, name := range functionNames {
+				_, err = fmt.Fprintf(buf, "FN:%v,%s\n", coverage.FunctionLines[name], name)
+				if err != nil {
+					return nil, err
+				}
+			}
+			for _, name := range functionNames {
+				_, err = fmt.Fprintf(buf, "FNDA:%v,%s\n", coverage.FunctionHits[name], name)
+				if err != nil {
+					return nil, err
+				}
+			}
+			_, err = fmt.Fprintf(
+				buf,
+				"FNF:%v\nFNH:%v\n",
+				len(coverage.FunctionHits),
+				coverage.CoveredFunctions(),
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if len(coverage.BranchHits) > 0 {
+			for block, branchHits := range branchHitsAliases(coverage) {
+				for branch, hits := range branchHits.Hits {
+					_, err = fmt.Fprintf(buf, "BRDA:%v,%v,%v,%v\n", branchHits.Line, block, branch, hits)
+					if err != nil {
+						return nil, err
+					}
+				}
+			}
+			_, err = fmt.Fprintf(
+				buf,
+				"BRF:%v\nBRH:%v\n",
+				coverage.Branches(),
+				coverage.CoveredBranches(),
+			)
+			if err != nil {
+				return nil, err
+			}
 		}
-		sort.Ints(lines)
 
-		for _, line := range lines {
+		for _, line := range sortedLines(coverage) {
 			hits := coverage.LineHits[line]
 			_, err = fmt.Fprintf(buf, "DA:%v,%v\n", line, hits)
 			if err != nil {
@@ -539,3 +1161,234 @@ func (r *CoverageReport) MarshalLCOV() ([]byte, error) {
 
 	return buf.Bytes(), nil
 }
+
+// coberturaDocType is the document type declaration of the Cobertura XML format.
+const coberturaDocType = `<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">` + "\n"
+
+type coberturaCoverage struct {
+	XMLName         xml.Name           `xml:"coverage"`
+	LineRate        float64            `xml:"line-rate,attr"`
+	BranchRate      float64            `xml:"branch-rate,attr"`
+	LinesCovered    int                `xml:"lines-covered,attr"`
+	LinesValid      int                `xml:"lines-valid,attr"`
+	BranchesCovered int                `xml:"branches-covered,attr"`
+	BranchesValid   int                `xml:"branches-valid,attr"`
+	Complexity      float64            `xml:"complexity,attr"`
+	Version         string             `xml:"version,attr"`
+	Timestamp       int64              `xml:"timestamp,attr"`
+	Packages        []coberturaPackage `xml:"packages>package"`
+}
+
+type coberturaPackage struct {
+	Name       string           `xml:"name,attr"`
+	LineRate   float64          `xml:"line-rate,attr"`
+	BranchRate float64          `xml:"branch-rate,attr"`
+	Complexity float64          `xml:"complexity,attr"`
+	Classes    []coberturaClass `xml:"classes>class"`
+}
+
+type coberturaClass struct {
+	Name       string           `xml:"name,attr"`
+	Filename   string           `xml:"filename,attr"`
+	LineRate   float64          `xml:"line-rate,attr"`
+	BranchRate float64          `xml:"branch-rate,attr"`
+	Complexity float64          `xml:"complexity,attr"`
+	Methods    coberturaMethods `xml:"methods"`
+	Lines      []coberturaLine  `xml:"lines>line"`
+}
+
+type coberturaMethods struct {
+	Methods []coberturaMethod `xml:"method"`
+}
+
+type coberturaMethod struct {
+	Name       string          `xml:"name,attr"`
+	Signature  string          `xml:"signature,attr"`
+	LineRate   float64         `xml:"line-rate,attr"`
+	BranchRate float64         `xml:"branch-rate,attr"`
+	Complexity float64         `xml:"complexity,attr"`
+	Lines      []coberturaLine `xml:"lines>line"`
+}
+
+type coberturaLine struct {
+	Number            int    `xml:"number,attr"`
+	Hits              int    `xml:"hits,attr"`
+	Branch            bool   `xml:"branch,attr"`
+	ConditionCoverage string `xml:"condition-coverage,attr,omitempty"`
+}
+
+// coberturaBranchRate returns the ratio of covered branches over branches.
+// Without branches, the rate is 0, like for reports without branch coverage.
+func coberturaBranchRate(coveredBranches int, branches int) float64 {
+	if branches == 0 {
+		return 0
+	}
+	return float64(coveredBranches) / float64(branches)
+}
+
+// coberturaLineRate returns the ratio of covered lines over statements.
+// Like the percentage, the ratio is saturated at 1.
+func coberturaLineRate(coveredLines int, statements int) float64 {
+	if statements == 0 || coveredLines > statements {
+		return 1
+	}
+	return float64(coveredLines) / float64(statements)
+}
+
+// MarshalCobertura serializes each common.Location/*LocationCoverage
+// key/value pair on the *CoverageReport.Coverage map, to the
+// Cobertura XML format, including line, function and branch coverage.
+// Functions are reported as methods, and lines with branches
+// report the coverage of their branches.
+//
+// Each location is reported as a class, named after the location ID,
+// in the file given by the LocationPathResolver. Classes are grouped
+// into packages by the directory of their file.
+// Excluded locations, and locations rejected by the LocationFilter,
+// are omitted.
+// Description for the Cobertura file format, can be found here
+// https://github.com/cobertura/web/blob/master/htdocs/xml/coverage-04.dtd.
+func (r *CoverageReport) MarshalCobertura() ([]byte, error) {
+	packages := map[string]*coberturaPackage{}
+	var packageNames []string
+
+	packageCoveredLines := map[string]int{}
+	packageStatements := map[string]int{}
+	packageCoveredBranches := map[string]int{}
+	packageBranches := map[string]int{}
+
+	coveredLines := 0
+	statements := 0
+	coveredBranches := 0
+	branches := 0
+
+	for _, location := range r.exportedLocations() {
+		coverage := r.Coverage[location]
+		path := r.locationPath(location)
+
+		// Sum up the branches of all branch points on each line
+		lineBranches := map[int]int{}
+		lineCoveredBranches := map[int]int{}
+		for branchPoint, hits := range coverage.BranchHits { // nolint:maprange
+			for _, branchHits := range hits {
+				lineBranches[branchPoint.Line]++
+				if branchHits > 0 {
+					lineCoveredBranches[branchPoint.Line]++
+				}
+			}
+		}
+
+		lines := make([]coberturaLine, 0, len(coverage.LineHits))
+		for _, line := range sortedLines(coverage) {
+			coberturaLine := coberturaLine{
+				Number: line,
+				Hits:   coverage.LineHits[line],
+			}
+			if branchCount := lineBranches[line]; branchCount > 0 {
+				coveredBranchCount := lineCoveredBranches[line]
+				coberturaLine.Branch = true
+				coberturaLine.ConditionCoverage = fmt.Sprintf(
+					"%d%% (%d/%d)",
+					100*coveredBranchCount/branchCount,
+					coveredBranchCount,
+					branchCount,
+				)
+			}
+			lines = append(lines, coberturaLine)
+		}
+
+		functionNames := sortedFunctionNames(coverage)
+		methods := make([]coberturaMethod, 0, len(functionNames))
+		for _, name := range functionNames {
+			hits := coverage.FunctionHits[name]
+			var lineRate float64
+			if hits > 0 {
+				lineRate = 1
+			}
+			methods = append(methods, coberturaMethod{
+				Name:     name,
+				LineRate: lineRate,
+				Lines: []coberturaLine{
+					{
+						Number: coverage.FunctionLines[name],
+						Hits:   hits,
+					},
+				},
+			})
+		}
+
+		locationCoveredLines := coverage.CoveredLines()
+		locationCoveredBranches := coverage.CoveredBranches()
+		locationBranches := coverage.Branches()
+
+		packageName := filepath.ToSlash(filepath.Dir(path))
+		pkg, ok := packages[packageName]
+		if !ok {
+			pkg = &coberturaPackage{
+				Name: packageName,
+			}
+			packages[packageName] = pkg
+			packageNames = append(packageNames, packageName)
+		}
+
+		pkg.Classes = append(pkg.Classes, coberturaClass{
+			Name:       location.ID(),
+			Filename:   path,
+			LineRate:   coberturaLineRate(locationCoveredLines, coverage.Statements),
+			BranchRate: coberturaBranchRate(locationCoveredBranches, locationBranches),
+			Methods: coberturaMethods{
+				Methods: methods,
+			},
+			Lines: lines,
+		})
+
+		packageCoveredLines[packageName] += locationCoveredLines
+		packageStatements[packageName] += coverage.Statements
+		packageCoveredBranches[packageName] += locationCoveredBranches
+		packageBranches[packageName] += locationBranches
+
+		coveredLines += locationCoveredLines
+		statements += coverage.Statements
+		coveredBranches += locationCoveredBranches
+		branches += locationBranches
+	}
+
+	sort.Strings(packageNames)
+
+	report := coberturaCoverage{
+		LineRate:        coberturaLineRate(coveredLines, statements),
+		BranchRate:      coberturaBranchRate(coveredBranches, branches),
+		LinesCovered:    coveredLines,
+		LinesValid:      statements,
+		BranchesCovered: coveredBranches,
+		BranchesValid:   branches,
+		Timestamp:       time.Now().UnixMilli(),
+		Packages:        make([]coberturaPackage, 0, len(packageNames)),
+	}
+
+	for _, packageName := range packageNames {
+		pkg := packages[packageName]
+		pkg.LineRate = coberturaLineRate(
+			packageCoveredLines[packageName],
+			packageStatements[packageName],
+		)
+		pkg.BranchRate = coberturaBranchRate(
+			packageCoveredBranches[packageName],
+			packageBranches[packageName],
+		)
+		report.Packages = append(report.Packages, *pkg)
+	}
+
+	encoded, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString(xml.Header)
+	buf.WriteString(coberturaDocType)
+	buf.Write(encoded)
+	buf.WriteString("\n")
+
+	return buf.Bytes(), nil
+}
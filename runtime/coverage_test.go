@@ -21,6 +21,7 @@ package runtime
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,6 +29,7 @@ import (
 
 	"github.com/onflow/cadence"
 	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
 	"github.com/onflow/cadence/runtime/parser"
 	"github.com/onflow/cadence/runtime/stdlib"
 )
@@ -141,6 +143,71 @@ func TestLocationCoveragePercentage(t *testing.T) {
 	assert.Equal(t, "100.0%", locationCoverage.Percentage())
 }
 
+func TestLocationCoverageAddBranchHit(t *testing.T) {
+
+	t.Parallel()
+
+	locationCoverage := NewLocationCoverage(map[int]int{})
+
+	ifBranchPoint := BranchPoint{
+		Kind:   interpreter.BranchKindIf,
+		Line:   3,
+		Column: 4,
+	}
+	switchBranchPoint := BranchPoint{
+		Kind:   interpreter.BranchKindSwitch,
+		Line:   7,
+		Column: 4,
+	}
+	locationCoverage.BranchHits[ifBranchPoint] = make([]int, 2)
+	locationCoverage.BranchHits[switchBranchPoint] = make([]int, 3)
+
+	locationCoverage.AddBranchHit(ifBranchPoint, 0)
+	locationCoverage.AddBranchHit(ifBranchPoint, 0)
+	locationCoverage.AddBranchHit(switchBranchPoint, 2)
+	// Branches of branch points that were not inspected,
+	// and unknown branches, are dropped.
+	locationCoverage.AddBranchHit(switchBranchPoint, 3)
+	locationCoverage.AddBranchHit(
+		BranchPoint{
+			Kind:   interpreter.BranchKindConditional,
+			Line:   9,
+			Column: 2,
+		},
+		0,
+	)
+
+	assert.Equal(
+		t,
+		map[BranchPoint][]int{
+			ifBranchPoint:     {2, 0},
+			switchBranchPoint: {0, 0, 1},
+		},
+		locationCoverage.BranchHits,
+	)
+	assert.Equal(t, 5, locationCoverage.Branches())
+	assert.Equal(t, 2, locationCoverage.CoveredBranches())
+	assert.Equal(t, "40.0%", locationCoverage.BranchPercentage())
+}
+
+func TestLocationCoverageFunctions(t *testing.T) {
+
+	t.Parallel()
+
+	locationCoverage := NewLocationCoverage(map[int]int{})
+
+	// Without functions, there is nothing to cover
+	assert.Equal(t, "100.0%", locationCoverage.FunctionPercentage())
+
+	locationCoverage.FunctionHits["foo"] = 2
+	locationCoverage.FunctionHits["bar"] = 0
+	locationCoverage.FunctionHits["baz"] = 0
+
+	assert.Equal(t, 1, locationCoverage.CoveredFunctions())
+	assert.Equal(t, []string{"bar", "baz"}, locationCoverage.MissedFunctions())
+	assert.Equal(t, "33.3%", locationCoverage.FunctionPercentage())
+}
+
 func TestNewCoverageReport(t *testing.T) {
 
 	t.Parallel()
@@ -333,7 +400,21 @@ func TestCoverageReportWithFlowLocation(t *testing.T) {
 	        },
 	        "missed_lines": [3, 4, 5, 7],
 	        "statements": 4,
-	        "percentage": "0.0%"
+	        "percentage": "0.0%",
+	        "branch_hits": [],
+	        "branches": 0,
+	        "branch_percentage": "100.0%",
+	        "function_hits": {
+	          "answer": 0
+	        },
+	        "function_lines": {
+	          "answer": 2
+	        },
+	        "function_columns": {
+	          "answer": 3
+	        },
+	        "missed_functions": ["answer"],
+	        "function_percentage": "0.0%"
 	      }
 	    },
 	    "excluded_locations": []
@@ -379,7 +460,21 @@ func TestCoverageReportWithREPLLocation(t *testing.T) {
 	        },
 	        "missed_lines": [3, 4, 5, 7],
 	        "statements": 4,
-	        "percentage": "0.0%"
+	        "percentage": "0.0%",
+	        "branch_hits": [],
+	        "branches": 0,
+	        "branch_percentage": "100.0%",
+	        "function_hits": {
+	          "answer": 0
+	        },
+	        "function_lines": {
+	          "answer": 2
+	        },
+	        "function_columns": {
+	          "answer": 3
+	        },
+	        "missed_functions": ["answer"],
+	        "function_percentage": "0.0%"
 	      }
 	    },
 	    "excluded_locations": []
@@ -425,7 +520,21 @@ func TestCoverageReportWithScriptLocation(t *testing.T) {
 	        },
 	        "missed_lines": [3, 4, 5, 7],
 	        "statements": 4,
-	        "percentage": "0.0%"
+	        "percentage": "0.0%",
+	        "branch_hits": [],
+	        "branches": 0,
+	        "branch_percentage": "100.0%",
+	        "function_hits": {
+	          "answer": 0
+	        },
+	        "function_lines": {
+	          "answer": 2
+	        },
+	        "function_columns": {
+	          "answer": 3
+	        },
+	        "missed_functions": ["answer"],
+	        "function_percentage": "0.0%"
 	      }
 	    },
 	    "excluded_locations": []
@@ -471,7 +580,21 @@ func TestCoverageReportWithStringLocation(t *testing.T) {
 	        },
 	        "missed_lines": [3, 4, 5, 7],
 	        "statements": 4,
-	        "percentage": "0.0%"
+	        "percentage": "0.0%",
+	        "branch_hits": [],
+	        "branches": 0,
+	        "branch_percentage": "100.0%",
+	        "function_hits": {
+	          "answer": 0
+	        },
+	        "function_lines": {
+	          "answer": 2
+	        },
+	        "function_columns": {
+	          "answer": 3
+	        },
+	        "missed_functions": ["answer"],
+	        "function_percentage": "0.0%"
 	      }
 	    },
 	    "excluded_locations": []
@@ -517,7 +640,21 @@ func TestCoverageReportWithIdentifierLocation(t *testing.T) {
 	        },
 	        "missed_lines": [3, 4, 5, 7],
 	        "statements": 4,
-	        "percentage": "0.0%"
+	        "percentage": "0.0%",
+	        "branch_hits": [],
+	        "branches": 0,
+	        "branch_percentage": "100.0%",
+	        "function_hits": {
+	          "answer": 0
+	        },
+	        "function_lines": {
+	          "answer": 2
+	        },
+	        "function_columns": {
+	          "answer": 3
+	        },
+	        "missed_functions": ["answer"],
+	        "function_percentage": "0.0%"
 	      }
 	    },
 	    "excluded_locations": []
@@ -563,7 +700,21 @@ func TestCoverageReportWithTransactionLocation(t *testing.T) {
 	        },
 	        "missed_lines": [3, 4, 5, 7],
 	        "statements": 4,
-	        "percentage": "0.0%"
+	        "percentage": "0.0%",
+	        "branch_hits": [],
+	        "branches": 0,
+	        "branch_percentage": "100.0%",
+	        "function_hits": {
+	          "answer": 0
+	        },
+	        "function_lines": {
+	          "answer": 2
+	        },
+	        "function_columns": {
+	          "answer": 3
+	        },
+	        "missed_functions": ["answer"],
+	        "function_percentage": "0.0%"
 	      }
 	    },
 	    "excluded_locations": []
@@ -612,7 +763,21 @@ func TestCoverageReportWithAddressLocation(t *testing.T) {
 	        },
 	        "missed_lines": [3, 4, 5, 7],
 	        "statements": 4,
-	        "percentage": "0.0%"
+	        "percentage": "0.0%",
+	        "branch_hits": [],
+	        "branches": 0,
+	        "branch_percentage": "100.0%",
+	        "function_hits": {
+	          "answer": 0
+	        },
+	        "function_lines": {
+	          "answer": 2
+	        },
+	        "function_columns": {
+	          "answer": 3
+	        },
+	        "missed_functions": ["answer"],
+	        "function_percentage": "0.0%"
 	      }
 	    },
 	    "excluded_locations": []
@@ -757,7 +922,21 @@ func TestCoverageReportPercentage(t *testing.T) {
 	        },
 	        "missed_lines": [5, 7],
 	        "statements": 4,
-	        "percentage": "50.0%"
+	        "percentage": "50.0%",
+	        "branch_hits": [],
+	        "branches": 0,
+	        "branch_percentage": "100.0%",
+	        "function_hits": {
+	          "answer": 0
+	        },
+	        "function_lines": {
+	          "answer": 2
+	        },
+	        "function_columns": {
+	          "answer": 3
+	        },
+	        "missed_functions": ["answer"],
+	        "function_percentage": "0.0%"
 	      }
 	    },
 	    "excluded_locations": []
@@ -808,7 +987,21 @@ func TestCoverageReportString(t *testing.T) {
 	        },
 	        "missed_lines": [7],
 	        "statements": 4,
-	        "percentage": "75.0%"
+	        "percentage": "75.0%",
+	        "branch_hits": [],
+	        "branches": 0,
+	        "branch_percentage": "100.0%",
+	        "function_hits": {
+	          "answer": 0
+	        },
+	        "function_lines": {
+	          "answer": 2
+	        },
+	        "function_columns": {
+	          "answer": 3
+	        },
+	        "missed_functions": ["answer"],
+	        "function_percentage": "0.0%"
 	      }
 	    },
 	    "excluded_locations": []
@@ -858,7 +1051,15 @@ func TestCoverageReportDiff(t *testing.T) {
 	    "hits": 2,
 	    "locations": 1,
 	    "misses": 2,
-	    "statements": 4
+	    "statements": 4,
+	    "branch_coverage": "100.0%",
+	    "branch_hits": 0,
+	    "branch_misses": 0,
+	    "branches": 0,
+	    "function_coverage": "0.0%",
+	    "function_hits": 0,
+	    "function_misses": 1,
+	    "functions": 1
 	  }
 	`
 	require.JSONEq(t, expected, string(actual))
@@ -870,6 +1071,10 @@ func TestCoverageReportDiff(t *testing.T) {
 	otherCoverageReport.AddLineHit(location, 5)
 	otherCoverageReport.AddLineHit(location, 5)
 	otherCoverageReport.AddLineHit(location, 7)
+	otherCoverageReport.AddFunctionHit(
+		location,
+		program.FunctionDeclarations()[0],
+	)
 
 	diff := coverageReport.Diff(*otherCoverageReport)
 
@@ -882,7 +1087,15 @@ func TestCoverageReportDiff(t *testing.T) {
 	    "hits": 2,
 	    "locations": 0,
 	    "misses": -2,
-	    "statements": 0
+	    "statements": 0,
+	    "branch_coverage": "0.0%",
+	    "branch_hits": 0,
+	    "branch_misses": 0,
+	    "branches": 0,
+	    "function_coverage": "100.0%",
+	    "function_hits": 1,
+	    "function_misses": -1,
+	    "functions": 0
 	  }
 	`
 	require.JSONEq(t, expected, string(actual))
@@ -984,7 +1197,25 @@ func TestCoverageReportMerge(t *testing.T) {
 	        },
 	        "missed_lines": [4, 8, 12, 13, 16],
 	        "statements": 5,
-	        "percentage": "0.0%"
+	        "percentage": "0.0%",
+	        "branch_hits": [
+	          {"kind": "condition", "line": 4, "column": 7, "hits": [0, 0]},
+	          {"kind": "condition", "line": 8, "column": 7, "hits": [0, 0]},
+	          {"kind": "if", "line": 12, "column": 5, "hits": [0, 0]}
+	        ],
+	        "branches": 6,
+	        "branch_percentage": "0.0%",
+	        "function_hits": {
+	          "factorial": 0
+	        },
+	        "function_lines": {
+	          "factorial": 2
+	        },
+	        "function_columns": {
+	          "factorial": 3
+	        },
+	        "missed_functions": ["factorial"],
+	        "function_percentage": "0.0%"
 	      },
 	      "S.IntegerTraits": {
 	        "line_hits": {
@@ -1005,7 +1236,31 @@ func TestCoverageReportMerge(t *testing.T) {
 	        },
 	        "missed_lines": [13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 25, 26, 29],
 	        "statements": 14,
-	        "percentage": "7.1%"
+	        "percentage": "7.1%",
+	        "branch_hits": [
+	          {"kind": "if", "line": 13, "column": 5, "hits": [0, 0]},
+	          {"kind": "if", "line": 15, "column": 12, "hits": [0, 0]},
+	          {"kind": "if", "line": 17, "column": 12, "hits": [0, 0]},
+	          {"kind": "if", "line": 19, "column": 12, "hits": [0, 0]},
+	          {"kind": "if", "line": 21, "column": 12, "hits": [0, 0]},
+	          {"kind": "if", "line": 25, "column": 5, "hits": [0, 0]}
+	        ],
+	        "branches": 12,
+	        "branch_percentage": "0.0%",
+	        "function_hits": {
+	          "addSpecialNumber": 0,
+	          "getIntegerTrait": 0
+	        },
+	        "function_lines": {
+	          "addSpecialNumber": 8,
+	          "getIntegerTrait": 12
+	        },
+	        "function_columns": {
+	          "addSpecialNumber": 3,
+	          "getIntegerTrait": 3
+	        },
+	        "missed_functions": ["addSpecialNumber", "getIntegerTrait"],
+	        "function_percentage": "0.0%"
 	      }
 	    },
 	    "excluded_locations": ["S.FooContract"]
@@ -1031,7 +1286,25 @@ func TestCoverageReportUnmarshalJSON(t *testing.T) {
 	        },
 	        "missed_lines": [4, 8, 12, 13, 16],
 	        "statements": 5,
-	        "percentage": "0.0%"
+	        "percentage": "0.0%",
+	        "branch_hits": [
+	          {"kind": "condition", "line": 4, "column": 7, "hits": [1, 0]},
+	          {"kind": "condition", "line": 8, "column": 7, "hits": [0, 0]},
+	          {"kind": "if", "line": 12, "column": 5, "hits": [0, 0]}
+	        ],
+	        "branches": 6,
+	        "branch_percentage": "16.7%",
+	        "function_hits": {
+	          "factorial": 1
+	        },
+	        "function_lines": {
+	          "factorial": 2
+	        },
+	        "function_columns": {
+	          "factorial": 3
+	        },
+	        "missed_functions": [],
+	        "function_percentage": "100.0%"
 	      },
 	      "S.IntegerTraits": {
 	        "line_hits": {
@@ -1052,7 +1325,31 @@ func TestCoverageReportUnmarshalJSON(t *testing.T) {
 	        },
 	        "missed_lines": [9, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 25, 26, 29],
 	        "statements": 14,
-	        "percentage": "0.0%"
+	        "percentage": "0.0%",
+	        "branch_hits": [
+	          {"kind": "if", "line": 13, "column": 5, "hits": [0, 0]},
+	          {"kind": "if", "line": 15, "column": 12, "hits": [0, 0]},
+	          {"kind": "if", "line": 17, "column": 12, "hits": [0, 0]},
+	          {"kind": "if", "line": 19, "column": 12, "hits": [0, 0]},
+	          {"kind": "if", "line": 21, "column": 12, "hits": [0, 0]},
+	          {"kind": "if", "line": 25, "column": 5, "hits": [0, 0]}
+	        ],
+	        "branches": 12,
+	        "branch_percentage": "0.0%",
+	        "function_hits": {
+	          "addSpecialNumber": 0,
+	          "getIntegerTrait": 0
+	        },
+	        "function_lines": {
+	          "addSpecialNumber": 8,
+	          "getIntegerTrait": 12
+	        },
+	        "function_columns": {
+	          "addSpecialNumber": 3,
+	          "getIntegerTrait": 3
+	        },
+	        "missed_functions": ["addSpecialNumber", "getIntegerTrait"],
+	        "function_percentage": "0.0%"
 	      }
 	    },
 	    "excluded_locations": ["I.Test"]
@@ -1087,6 +1384,20 @@ func TestCoverageReportUnmarshalJSON(t *testing.T) {
 		map[int]int{4: 0, 8: 0, 12: 0, 13: 0, 16: 0},
 		coverageReport.Coverage[factorialLocation].LineHits,
 	)
+	assert.Equal(
+		t,
+		[]int{1, 0},
+		coverageReport.Coverage[factorialLocation].BranchHits[BranchPoint{
+			Kind:   interpreter.BranchKindCondition,
+			Line:   4,
+			Column: 7,
+		}],
+	)
+	assert.Equal(
+		t,
+		map[string]int{"factorial": 1},
+		coverageReport.Coverage[factorialLocation].FunctionHits,
+	)
 
 	actual, err := json.Marshal(coverageReport)
 	require.NoError(t, err)
@@ -1129,6 +1440,44 @@ func TestCoverageReportUnmarshalJSON(t *testing.T) {
 	)
 }
 
+func TestCoverageReportUnmarshalJSONWithFunctionHit(t *testing.T) {
+
+	t.Parallel()
+
+	script := []byte(`
+	  pub fun answer(): Int {
+	    return 42
+	  }
+	`)
+
+	program, err := parser.ParseProgram(nil, script, parser.Config{})
+	require.NoError(t, err)
+
+	coverageReport := NewCoverageReport()
+
+	location := common.StringLocation("AnswerScript")
+	coverageReport.InspectProgram(location, program)
+
+	data, err := json.Marshal(coverageReport)
+	require.NoError(t, err)
+
+	decodedCoverageReport := NewCoverageReport()
+	err = json.Unmarshal(data, decodedCoverageReport)
+	require.NoError(t, err)
+
+	decodedCoverageReport.AddFunctionHit(
+		location,
+		program.FunctionDeclarations()[0],
+	)
+
+	assert.Equal(
+		t,
+		map[string]int{"answer": 1},
+		decodedCoverageReport.Coverage[location].FunctionHits,
+	)
+	assert.Equal(t, "100.0%", decodedCoverageReport.Coverage[location].FunctionPercentage())
+}
+
 func TestCoverageReportUnmarshalJSONWithFormatError(t *testing.T) {
 
 	t.Parallel()
@@ -1157,7 +1506,21 @@ func TestCoverageReportUnmarshalJSONWithDecodeLocationError(t *testing.T) {
 	        },
 	        "missed_lines": [4, 8, 12, 13, 16],
 	        "statements": 5,
-	        "percentage": "0.0%"
+	        "percentage": "0.0%",
+	        "branch_hits": [],
+	        "branches": 0,
+	        "branch_percentage": "100.0%",
+	        "function_hits": {
+	          "answer": 0
+	        },
+	        "function_lines": {
+	          "answer": 2
+	        },
+	        "function_columns": {
+	          "answer": 3
+	        },
+	        "missed_functions": ["answer"],
+	        "function_percentage": "0.0%"
 	      }
 	    },
 	    "excluded_locations": ["I.Test"]
@@ -1186,7 +1549,21 @@ func TestCoverageReportUnmarshalJSONWithDecodeExcludedLocationError(t *testing.T
 	        },
 	        "missed_lines": [4, 8, 12, 13, 16],
 	        "statements": 5,
-	        "percentage": "0.0%"
+	        "percentage": "0.0%",
+	        "branch_hits": [],
+	        "branches": 0,
+	        "branch_percentage": "100.0%",
+	        "function_hits": {
+	          "answer": 0
+	        },
+	        "function_lines": {
+	          "answer": 2
+	        },
+	        "function_columns": {
+	          "answer": 3
+	        },
+	        "missed_functions": ["answer"],
+	        "function_percentage": "0.0%"
 	      }
 	    },
 	    "excluded_locations": ["XI.Test"]
@@ -1341,7 +1718,37 @@ func TestRuntimeCoverage(t *testing.T) {
 	        },
 	        "missed_lines": [],
 	        "statements": 19,
-	        "percentage": "100.0%"
+	        "percentage": "100.0%",
+	        "branch_hits": [
+	          {"kind": "if", "line": 13, "column": 5, "hits": [1, 9]},
+	          {"kind": "if", "line": 15, "column": 12, "hits": [1, 8]},
+	          {"kind": "if", "line": 17, "column": 12, "hits": [1, 7]},
+	          {"kind": "if", "line": 19, "column": 12, "hits": [1, 6]},
+	          {"kind": "if", "line": 21, "column": 12, "hits": [1, 5]},
+	          {"kind": "if", "line": 25, "column": 5, "hits": [4, 1]},
+	          {"kind": "condition", "line": 34, "column": 7, "hits": [7, 0]},
+	          {"kind": "condition", "line": 38, "column": 7, "hits": [7, 0]},
+	          {"kind": "if", "line": 42, "column": 5, "hits": [2, 5]}
+	        ],
+	        "branches": 18,
+	        "branch_percentage": "88.9%",
+	        "function_hits": {
+	          "addSpecialNumber": 1,
+	          "factorial": 7,
+	          "getIntegerTrait": 10
+	        },
+	        "function_lines": {
+	          "addSpecialNumber": 8,
+	          "factorial": 32,
+	          "getIntegerTrait": 12
+	        },
+	        "function_columns": {
+	          "addSpecialNumber": 3,
+	          "factorial": 3,
+	          "getIntegerTrait": 3
+	        },
+	        "missed_functions": [],
+	        "function_percentage": "100.0%"
 	      },
 	      "s.0000000000000000000000000000000000000000000000000000000000000000": {
 	        "line_hits": {
@@ -1357,7 +1764,21 @@ func TestRuntimeCoverage(t *testing.T) {
 	        },
 	        "missed_lines": [],
 	        "statements": 9,
-	        "percentage": "100.0%"
+	        "percentage": "100.0%",
+	        "branch_hits": [],
+	        "branches": 0,
+	        "branch_percentage": "100.0%",
+	        "function_hits": {
+	          "main": 1
+	        },
+	        "function_lines": {
+	          "main": 4
+	        },
+	        "function_columns": {
+	          "main": 3
+	        },
+	        "missed_functions": [],
+	        "function_percentage": "100.0%"
 	      }
 	    },
 	    "excluded_locations": []
@@ -1493,7 +1914,31 @@ func TestRuntimeCoverageWithExcludedLocation(t *testing.T) {
 	        },
 	        "missed_lines": [],
 	        "statements": 14,
-	        "percentage": "100.0%"
+	        "percentage": "100.0%",
+	        "branch_hits": [
+	          {"kind": "if", "line": 13, "column": 5, "hits": [1, 9]},
+	          {"kind": "if", "line": 15, "column": 12, "hits": [1, 8]},
+	          {"kind": "if", "line": 17, "column": 12, "hits": [1, 7]},
+	          {"kind": "if", "line": 19, "column": 12, "hits": [1, 6]},
+	          {"kind": "if", "line": 21, "column": 12, "hits": [1, 5]},
+	          {"kind": "if", "line": 25, "column": 5, "hits": [4, 1]}
+	        ],
+	        "branches": 12,
+	        "branch_percentage": "100.0%",
+	        "function_hits": {
+	          "addSpecialNumber": 1,
+	          "getIntegerTrait": 10
+	        },
+	        "function_lines": {
+	          "addSpecialNumber": 8,
+	          "getIntegerTrait": 12
+	        },
+	        "function_columns": {
+	          "addSpecialNumber": 3,
+	          "getIntegerTrait": 3
+	        },
+	        "missed_functions": [],
+	        "function_percentage": "100.0%"
 	      }
 	    },
 	    "excluded_locations": ["s.0000000000000000000000000000000000000000000000000000000000000000"]
@@ -1634,7 +2079,31 @@ func TestRuntimeCoverageWithLocationFilter(t *testing.T) {
 	        },
 	        "missed_lines": [],
 	        "statements": 14,
-	        "percentage": "100.0%"
+	        "percentage": "100.0%",
+	        "branch_hits": [
+	          {"kind": "if", "line": 13, "column": 5, "hits": [1, 9]},
+	          {"kind": "if", "line": 15, "column": 12, "hits": [1, 8]},
+	          {"kind": "if", "line": 17, "column": 12, "hits": [1, 7]},
+	          {"kind": "if", "line": 19, "column": 12, "hits": [1, 6]},
+	          {"kind": "if", "line": 21, "column": 12, "hits": [1, 5]},
+	          {"kind": "if", "line": 25, "column": 5, "hits": [4, 1]}
+	        ],
+	        "branches": 12,
+	        "branch_percentage": "100.0%",
+	        "function_hits": {
+	          "addSpecialNumber": 1,
+	          "getIntegerTrait": 10
+	        },
+	        "function_lines": {
+	          "addSpecialNumber": 8,
+	          "getIntegerTrait": 12
+	        },
+	        "function_columns": {
+	          "addSpecialNumber": 3,
+	          "getIntegerTrait": 3
+	        },
+	        "missed_functions": [],
+	        "function_percentage": "100.0%"
 	      }
 	    },
 	    "excluded_locations": []
@@ -1720,12 +2189,201 @@ func TestRuntimeCoverageWithNoStatements(t *testing.T) {
 	    "hits": 0,
 	    "locations": 0,
 	    "misses": 0,
-	    "statements": 0
+	    "statements": 0,
+	    "branch_coverage": "100.0%",
+	    "branch_hits": 0,
+	    "branch_misses": 0,
+	    "branches": 0,
+	    "function_coverage": "100.0%",
+	    "function_hits": 0,
+	    "function_misses": 0,
+	    "functions": 0
 	  }
 	`
 	require.JSONEq(t, expected, string(actual))
 }
 
+func TestRuntimeCoverageBranches(t *testing.T) {
+
+	t.Parallel()
+
+	script := []byte(`
+	  pub fun classify(_ n: Int): String {
+	    switch n {
+	      case 1:
+	        return "one"
+	      case 2:
+	        return "two"
+	    }
+	    return n > 0 ? "many" : "none"
+	  }
+
+	  pub fun orZero(_ n: Int?): Int {
+	    return n ?? 0
+	  }
+
+	  pub struct Box {
+	    pub let value: Int
+
+	    init(value: Int) {
+	      self.value = value
+	    }
+	  }
+
+	  pub fun unbox(_ box: Box?): Int? {
+	    return box?.value
+	  }
+
+	  pub fun positive(_ n: Int): Int {
+	    pre {
+	      n > 0: "n must be positive"
+	    }
+	    return n
+	  }
+
+	  pub fun main() {
+	    classify(1)
+	    classify(3)
+	    orZero(1)
+	    unbox(nil)
+	    positive(-1)
+	  }
+	`)
+
+	coverageReport := NewCoverageReport()
+
+	location := common.ScriptLocation{0x1}
+
+	runtime := NewInterpreterRuntime(Config{
+		CoverageReport: coverageReport,
+	})
+
+	_, err := runtime.ExecuteScript(
+		Script{
+			Source: script,
+		},
+		Context{
+			Interface:      &testRuntimeInterface{},
+			Location:       location,
+			CoverageReport: coverageReport,
+		},
+	)
+	// The pre-condition of the last invocation fails
+	require.Error(t, err)
+
+	locationCoverage := coverageReport.Coverage[location]
+	require.NotNil(t, locationCoverage)
+
+	assert.Equal(
+		t,
+		map[BranchPoint][]int{
+			// The first case matched once, the second never,
+			// and no case matched once
+			{Kind: interpreter.BranchKindSwitch, Line: 3, Column: 5}:             {1, 0, 1},
+			{Kind: interpreter.BranchKindConditional, Line: 9, Column: 12}:       {1, 0},
+			{Kind: interpreter.BranchKindNilCoalescing, Line: 13, Column: 12}:    {1, 0},
+			{Kind: interpreter.BranchKindOptionalChaining, Line: 25, Column: 16}: {0, 1},
+			// The pre-condition failed
+			{Kind: interpreter.BranchKindCondition, Line: 30, Column: 7}: {0, 1},
+		},
+		locationCoverage.BranchHits,
+	)
+
+	// All lines of the failing paths were executed,
+	// but several branches were not
+	assert.Equal(t, 11, locationCoverage.Branches())
+	assert.Equal(t, 6, locationCoverage.CoveredBranches())
+	assert.Equal(t, "54.5%", locationCoverage.BranchPercentage())
+}
+
+func TestRuntimeCoverageFunctions(t *testing.T) {
+
+	t.Parallel()
+
+	script := []byte(`
+	  pub struct Counter {
+	    pub var count: Int
+
+	    init() {
+	      self.count = 0
+	    }
+
+	    pub fun increment() {
+	      self.count = self.count + 1
+	    }
+
+	    pub fun reset() {
+	      self.count = 0
+	    }
+	  }
+
+	  pub struct interface HasCount {
+	    pub fun getCount(): Int
+	  }
+
+	  pub fun unused() {}
+
+	  pub fun main(): Int {
+	    let counter = Counter()
+	    counter.increment()
+	    let double = fun (_ n: Int): Int {
+	      return n * 2
+	    }
+	    return double(counter.count)
+	  }
+	`)
+
+	coverageReport := NewCoverageReport()
+
+	location := common.ScriptLocation{0x1}
+
+	runtime := NewInterpreterRuntime(Config{
+		CoverageReport: coverageReport,
+	})
+
+	value, err := runtime.ExecuteScript(
+		Script{
+			Source: script,
+		},
+		Context{
+			Interface:      &testRuntimeInterface{},
+			Location:       location,
+			CoverageReport: coverageReport,
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, cadence.NewInt(2), value)
+
+	locationCoverage := coverageReport.Coverage[location]
+	require.NotNil(t, locationCoverage)
+
+	// Functions without a body, and function expressions, are not tracked
+	assert.Equal(
+		t,
+		map[string]int{
+			"Counter.init":      1,
+			"Counter.increment": 1,
+			"Counter.reset":     0,
+			"unused":            0,
+			"main":              1,
+		},
+		locationCoverage.FunctionHits,
+	)
+	assert.Equal(
+		t,
+		[]string{"Counter.reset", "unused"},
+		locationCoverage.MissedFunctions(),
+	)
+	assert.Equal(t, "60.0%", locationCoverage.FunctionPercentage())
+
+	summary := coverageReport.Summary()
+	assert.Equal(t, 5, summary.Functions)
+	assert.Equal(t, 3, summary.FunctionHits)
+	assert.Equal(t, 2, summary.FunctionMisses)
+	assert.Equal(t, "60.0%", summary.FunctionCoverage)
+}
+
 func TestCoverageReportLCOVFormat(t *testing.T) {
 
 	t.Parallel()
@@ -1827,6 +2485,26 @@ func TestCoverageReportLCOVFormat(t *testing.T) {
 
 	expected := `TN:
 SF:S.IntegerTraits
+FN:8,addSpecialNumber
+FN:12,getIntegerTrait
+FNDA:1,addSpecialNumber
+FNDA:10,getIntegerTrait
+FNF:2
+FNH:2
+BRDA:13,0,0,1
+BRDA:13,0,1,9
+BRDA:15,1,0,1
+BRDA:15,1,1,8
+BRDA:17,2,0,1
+BRDA:17,2,1,7
+BRDA:19,3,0,1
+BRDA:19,3,1,6
+BRDA:21,4,0,1
+BRDA:21,4,1,5
+BRDA:25,5,0,4
+BRDA:25,5,1,1
+BRF:12
+BRH:12
 DA:9,1
 DA:13,10
 DA:14,1
@@ -1853,3 +2531,172 @@ end_of_record
 		coverageReport.String(),
 	)
 }
+
+func TestCoverageReportLCOVFormatWithLocationPathResolver(t *testing.T) {
+
+	t.Parallel()
+
+	data := `
+	  {
+	    "coverage": {
+	      "S.Factorial": {
+	        "line_hits": {"4": 1, "8": 0},
+	        "statements": 2
+	      },
+	      "A.0000000000000001.Counter": {
+	        "line_hits": {"3": 2, "1": 1},
+	        "statements": 2
+	      },
+	      "I.Test": {
+	        "line_hits": {"5": 1},
+	        "statements": 1
+	      },
+	      "s.0000000000000000000000000000000000000000000000000000000000000000": {
+	        "line_hits": {"2": 1},
+	        "statements": 1
+	      }
+	    },
+	    "excluded_locations": ["I.Test"]
+	  }
+	`
+
+	coverageReport := NewCoverageReport()
+	err := json.Unmarshal([]byte(data), coverageReport)
+	require.NoError(t, err)
+
+	coverageReport.WithLocationFilter(func(location common.Location) bool {
+		_, isScriptLocation := location.(common.ScriptLocation)
+		return !isScriptLocation
+	})
+	coverageReport.WithLocationPathResolver(func(location common.Location) string {
+		switch location := location.(type) {
+		case common.StringLocation:
+			return "scripts/" + string(location) + ".cdc"
+		case common.AddressLocation:
+			return "contracts/" + location.Name + ".cdc"
+		default:
+			return location.ID()
+		}
+	})
+
+	actual, err := coverageReport.MarshalLCOV()
+	require.NoError(t, err)
+
+	expected := `TN:
+SF:contracts/Counter.cdc
+DA:1,1
+DA:3,2
+LF:2
+LH:2
+end_of_record
+TN:
+SF:scripts/Factorial.cdc
+DA:4,1
+DA:8,0
+LF:2
+LH:1
+end_of_record
+`
+	require.Equal(t, expected, string(actual))
+}
+
+func TestCoverageReportCoberturaFormat(t *testing.T) {
+
+	t.Parallel()
+
+	data := `
+	  {
+	    "coverage": {
+	      "S.Factorial": {
+	        "line_hits": {"4": 1, "8": 0},
+	        "statements": 2,
+	        "branch_hits": [
+	          {"kind": "condition", "line": 4, "column": 7, "hits": [1, 0]}
+	        ],
+	        "function_hits": {"factorial": 1},
+	        "function_lines": {"factorial": 2}
+	      },
+	      "S.IntegerTraits": {
+	        "line_hits": {"9": 3},
+	        "statements": 1
+	      },
+	      "A.0000000000000001.Counter": {
+	        "line_hits": {"3": 2, "1": 1, "7": 0, "9": 0},
+	        "statements": 4
+	      },
+	      "I.Test": {
+	        "line_hits": {"5": 1},
+	        "statements": 1
+	      }
+	    },
+	    "excluded_locations": ["I.Test"]
+	  }
+	`
+
+	coverageReport := NewCoverageReport()
+	err := json.Unmarshal([]byte(data), coverageReport)
+	require.NoError(t, err)
+
+	coverageReport.WithLocationPathResolver(func(location common.Location) string {
+		switch location := location.(type) {
+		case common.StringLocation:
+			return "scripts/" + string(location) + ".cdc"
+		case common.AddressLocation:
+			return "contracts/" + location.Name + ".cdc"
+		default:
+			return location.ID()
+		}
+	})
+
+	actual, err := coverageReport.MarshalCobertura()
+	require.NoError(t, err)
+
+	// The timestamp is the time of the export
+	timestamp := regexp.MustCompile(` timestamp="\d+"`)
+	require.Regexp(t, timestamp, string(actual))
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">
+<coverage line-rate="0.5714285714285714" branch-rate="0.5" lines-covered="4" lines-valid="7" branches-covered="1" branches-valid="2" complexity="0" version="">
+  <packages>
+    <package name="contracts" line-rate="0.5" branch-rate="0" complexity="0">
+      <classes>
+        <class name="A.0000000000000001.Counter" filename="contracts/Counter.cdc" line-rate="0.5" branch-rate="0" complexity="0">
+          <methods></methods>
+          <lines>
+            <line number="1" hits="1" branch="false"></line>
+            <line number="3" hits="2" branch="false"></line>
+            <line number="7" hits="0" branch="false"></line>
+            <line number="9" hits="0" branch="false"></line>
+          </lines>
+        </class>
+      </classes>
+    </package>
+    <package name="scripts" line-rate="0.6666666666666666" branch-rate="0.5" complexity="0">
+      <classes>
+        <class name="S.Factorial" filename="scripts/Factorial.cdc" line-rate="0.5" branch-rate="0.5" complexity="0">
+          <methods>
+            <method name="factorial" signature="" line-rate="1" branch-rate="0" complexity="0">
+              <lines>
+                <line number="2" hits="1" branch="false"></line>
+              </lines>
+            </method>
+          </methods>
+          <lines>
+            <line number="4" hits="1" branch="true" condition-coverage="50% (1/2)"></line>
+            <line number="8" hits="0" branch="false"></line>
+          </lines>
+        </class>
+        <class name="S.IntegerTraits" filename="scripts/IntegerTraits.cdc" line-rate="1" branch-rate="0" complexity="0">
+          <methods></methods>
+          <lines>
+            <line number="9" hits="3" branch="false"></line>
+          </lines>
+        </class>
+      </classes>
+    </package>
+  </packages>
+</coverage>
+`
+	require.Equal(t, expected, timestamp.ReplaceAllString(string(actual), ""))
+}
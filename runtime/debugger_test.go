@@ -25,6 +25,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/cmd"
 	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/interpreter"
 )
@@ -202,3 +203,519 @@ func TestRuntimeDebuggerBreakpoints(t *testing.T) {
 
 	require.True(t, logged)
 }
+
+// executeTransactionWithDebugger executes the given transaction in a goroutine.
+// The returned function waits for the transaction to finish execution,
+// and returns the logged messages
+func executeTransactionWithDebugger(
+	t *testing.T,
+	debugger *interpreter.Debugger,
+	location common.Location,
+	code string,
+) (wait func() []string) {
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	var logs []string
+
+	go func() {
+		defer wg.Done()
+
+		runtime := newTestInterpreterRuntime()
+		runtime.defaultConfig.Debugger = debugger
+
+		address := common.MustBytesToAddress([]byte{0x1})
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{address}, nil
+			},
+			log: func(message string) {
+				logs = append(logs, message)
+			},
+		}
+
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: []byte(code),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  location,
+			},
+		)
+		require.NoError(t, err)
+	}()
+
+	return func() []string {
+		wg.Wait()
+		return logs
+	}
+}
+
+func mustParseExpression(t *testing.T, code string) ast.Expression {
+	expression, err := cmd.ParseBreakpointCondition(code)
+	require.NoError(t, err)
+	return expression
+}
+
+func mustParseLogMessage(t *testing.T, message string) []ast.Expression {
+	logMessage, err := cmd.ParseLogMessage(message)
+	require.NoError(t, err)
+	return logMessage
+}
+
+const debuggerSteppingTransaction = `
+  pub fun double(_ x: Int): Int {
+      let doubled = x * 2
+      return doubled
+  }
+
+  transaction {
+      prepare(signer: AuthAccount) {
+          let a = double(1)
+          let b = double(a)
+          log(b)
+      }
+  }
+`
+
+func TestRuntimeDebuggerStepping(t *testing.T) {
+
+	t.Parallel()
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+	location := nextTransactionLocation()
+
+	debugger := interpreter.NewDebugger()
+	debugger.AddBreakpoint(location, 9)
+
+	wait := executeTransactionWithDebugger(t, debugger, location, debuggerSteppingTransaction)
+
+	stop := <-debugger.Stops()
+	require.Equal(t, 9, stop.Statement.StartPosition().Line)
+	require.NotNil(t, stop.Breakpoint)
+
+	// Stepping into the invocation stops in the invoked function
+
+	stop = debugger.StepIn()
+	require.Equal(t, 3, stop.Statement.StartPosition().Line)
+	require.Nil(t, stop.Breakpoint)
+
+	// Stepping over statements stays in the function
+
+	stop = debugger.Next()
+	require.Equal(t, 4, stop.Statement.StartPosition().Line)
+
+	// Stepping out returns to the caller
+
+	stop = debugger.StepOut()
+	require.Equal(t, 10, stop.Statement.StartPosition().Line)
+
+	// Stepping over the invocation does not stop in the invoked function
+
+	stop = debugger.Next()
+	require.Equal(t, 11, stop.Statement.StartPosition().Line)
+
+	activation := debugger.CurrentActivation(stop.Interpreter)
+	require.Equal(
+		t,
+		interpreter.NewUnmeteredIntValueFromInt64(4),
+		activation.Find("b").GetValue(),
+	)
+
+	debugger.Continue()
+
+	require.Equal(t, []string{"4"}, wait())
+}
+
+func TestRuntimeDebuggerConditionalBreakpoints(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      transaction {
+          prepare(signer: AuthAccount) {
+              var i = 0
+              while i < 5 {
+                  i = i + 1
+              }
+              log(i)
+          }
+      }
+    `
+
+	t.Run("condition", func(t *testing.T) {
+
+		t.Parallel()
+
+		nextTransactionLocation := newTransactionLocationGenerator()
+		location := nextTransactionLocation()
+
+		debugger := interpreter.NewDebugger()
+		debugger.SetBreakpoint(&interpreter.Breakpoint{
+			Location:  location,
+			Line:      6,
+			Condition: mustParseExpression(t, "i == 3 && signer.address == 0x1"),
+		})
+
+		wait := executeTransactionWithDebugger(t, debugger, location, code)
+
+		stop := <-debugger.Stops()
+		require.Equal(t, 6, stop.Statement.StartPosition().Line)
+		require.NoError(t, stop.ConditionError)
+
+		value, err := debugger.Evaluate(stop.Interpreter, mustParseExpression(t, "i * 10"))
+		require.NoError(t, err)
+		require.Equal(
+			t,
+			interpreter.NewUnmeteredIntValueFromInt64(30),
+			value,
+		)
+
+		debugger.Continue()
+
+		require.Equal(t, []string{"5"}, wait())
+		require.Equal(t, uint(1), stop.Breakpoint.Hits())
+	})
+
+	t.Run("hit count", func(t *testing.T) {
+
+		t.Parallel()
+
+		nextTransactionLocation := newTransactionLocationGenerator()
+		location := nextTransactionLocation()
+
+		debugger := interpreter.NewDebugger()
+		debugger.SetBreakpoint(&interpreter.Breakpoint{
+			Location: location,
+			Line:     6,
+			HitCount: 4,
+		})
+
+		wait := executeTransactionWithDebugger(t, debugger, location, code)
+
+		stop := <-debugger.Stops()
+		require.Equal(
+			t,
+			interpreter.NewUnmeteredIntValueFromInt64(3),
+			debugger.CurrentActivation(stop.Interpreter).Find("i").GetValue(),
+		)
+
+		debugger.Continue()
+
+		// The breakpoint takes effect on every hit after the hit count is reached
+
+		stop = <-debugger.Stops()
+		require.Equal(
+			t,
+			interpreter.NewUnmeteredIntValueFromInt64(4),
+			debugger.CurrentActivation(stop.Interpreter).Find("i").GetValue(),
+		)
+
+		debugger.ClearBreakpoints()
+		debugger.Continue()
+
+		require.Equal(t, []string{"5"}, wait())
+	})
+
+	t.Run("invalid condition", func(t *testing.T) {
+
+		t.Parallel()
+
+		nextTransactionLocation := newTransactionLocationGenerator()
+		location := nextTransactionLocation()
+
+		debugger := interpreter.NewDebugger()
+		debugger.SetBreakpoint(&interpreter.Breakpoint{
+			Location:  location,
+			Line:      8,
+			Condition: mustParseExpression(t, "i + 1"),
+		})
+
+		wait := executeTransactionWithDebugger(t, debugger, location, code)
+
+		stop := <-debugger.Stops()
+		require.Equal(t, 8, stop.Statement.StartPosition().Line)
+		require.Error(t, stop.ConditionError)
+
+		debugger.Continue()
+
+		require.Equal(t, []string{"5"}, wait())
+	})
+}
+
+func TestRuntimeDebuggerLogpoints(t *testing.T) {
+
+	t.Parallel()
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+	location := nextTransactionLocation()
+
+	var messages []string
+
+	debugger := interpreter.NewDebugger()
+	debugger.OnLogpoint = func(_ *interpreter.Breakpoint, message string) {
+		messages = append(messages, message)
+	}
+
+	debugger.SetBreakpoint(&interpreter.Breakpoint{
+		Location:   location,
+		Line:       4,
+		LogMessage: mustParseLogMessage(t, `x = {x}, doubled = {doubled}, {"done"}, {y}`),
+	})
+	debugger.SetBreakpoint(&interpreter.Breakpoint{
+		Location:   location,
+		Line:       10,
+		Condition:  mustParseExpression(t, "a > 1"),
+		LogMessage: mustParseLogMessage(t, "a = {a}"),
+	})
+
+	wait := executeTransactionWithDebugger(t, debugger, location, debuggerSteppingTransaction)
+
+	// Logpoints do not stop the program
+
+	require.Equal(t, []string{"4"}, wait())
+
+	require.Equal(
+		t,
+		[]string{
+			"x = 1, doubled = 2, done, <error: cannot find variable in this scope: `y`>",
+			"a = 2",
+			"x = 2, doubled = 4, done, <error: cannot find variable in this scope: `y`>",
+		},
+		messages,
+	)
+}
+
+func TestRuntimeDebuggerEvaluationNotMetered(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      transaction {
+          prepare(signer: AuthAccount) {
+              var i = 0
+              while i < 3 {
+                  i = i + 1
+              }
+              log(i)
+          }
+      }
+    `
+
+	type usage struct {
+		computation map[common.ComputationKind]uint
+		memory      map[common.MemoryKind]uint64
+	}
+
+	execute := func(breakpoints ...*interpreter.Breakpoint) usage {
+		nextTransactionLocation := newTransactionLocationGenerator()
+		location := nextTransactionLocation()
+
+		debugger := interpreter.NewDebugger()
+		debugger.OnLogpoint = func(_ *interpreter.Breakpoint, _ string) {}
+		for _, breakpoint := range breakpoints {
+			breakpoint.Location = location
+			debugger.SetBreakpoint(breakpoint)
+		}
+
+		runtime := newTestInterpreterRuntime()
+		runtime.defaultConfig.Debugger = debugger
+
+		result := usage{
+			computation: map[common.ComputationKind]uint{},
+			memory:      map[common.MemoryKind]uint64{},
+		}
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{common.MustBytesToAddress([]byte{0x1})}, nil
+			},
+			log: func(_ string) {},
+			meterComputation: func(compKind common.ComputationKind, intensity uint) error {
+				result.computation[compKind] += intensity
+				return nil
+			},
+			meterMemory: func(usage common.MemoryUsage) error {
+				result.memory[usage.Kind] += usage.Amount
+				return nil
+			},
+		}
+
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: []byte(code),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  location,
+			},
+		)
+		require.NoError(t, err)
+
+		return result
+	}
+
+	expected := execute()
+
+	actual := execute(
+		&interpreter.Breakpoint{
+			Line:      5,
+			Condition: mustParseExpression(t, `[i, i * 2].length > 10 && "a" == "b"`),
+		},
+		&interpreter.Breakpoint{
+			Line:       6,
+			LogMessage: mustParseLogMessage(t, `i = {[i, i + 1]}`),
+		},
+	)
+
+	require.Equal(t, expected, actual)
+}
+
+func TestRuntimeDebuggerFrames(t *testing.T) {
+
+	t.Parallel()
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+	location := nextTransactionLocation()
+
+	debugger := interpreter.NewDebugger()
+	debugger.SetBreakpoint(&interpreter.Breakpoint{
+		Location: location,
+		Line:     3,
+		HitCount: 2,
+	})
+
+	wait := executeTransactionWithDebugger(t, debugger, location, debuggerSteppingTransaction)
+
+	stop := <-debugger.Stops()
+	require.Equal(t, 3, stop.Statement.StartPosition().Line)
+
+	frames := debugger.Frames(stop)
+	require.Len(t, frames, 2)
+
+	functionFrame := frames[0]
+	require.Equal(t, "double", functionFrame.FunctionName())
+	require.Equal(t, location, functionFrame.Location)
+	require.Equal(t, 3, functionFrame.Position.Line)
+
+	callerFrame := frames[1]
+	require.Equal(t, "transaction.prepare", callerFrame.FunctionName())
+	require.Equal(t, location, callerFrame.Location)
+	require.Equal(t, 10, callerFrame.Position.Line)
+
+	// Expressions are evaluated in the activation of the frame
+
+	value, err := debugger.EvaluateIn(functionFrame, mustParseExpression(t, "x"))
+	require.NoError(t, err)
+	require.Equal(t, interpreter.NewUnmeteredIntValueFromInt64(2), value)
+
+	value, err = debugger.EvaluateIn(callerFrame, mustParseExpression(t, "a + 1"))
+	require.NoError(t, err)
+	require.Equal(t, interpreter.NewUnmeteredIntValueFromInt64(3), value)
+
+	// The variables of the callee are not in scope of the caller
+
+	_, err = debugger.EvaluateIn(callerFrame, mustParseExpression(t, "x"))
+	require.Error(t, err)
+
+	debugger.Continue()
+
+	require.Equal(t, []string{"4"}, wait())
+}
+
+func TestRuntimeDebuggerStorageValues(t *testing.T) {
+
+	t.Parallel()
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+	location := nextTransactionLocation()
+
+	debugger := interpreter.NewDebugger()
+	debugger.AddBreakpoint(location, 9)
+
+	wait := executeTransactionWithDebugger(t, debugger, location, `
+      transaction {
+          prepare(signer: AuthAccount) {
+              signer.save([1, 2], to: /storage/numbers)
+              signer.link<&[Int]>(/public/numbers, target: /storage/numbers)
+              let ref = signer.borrow<&[Int]>(from: /storage/numbers)!
+              let cap = signer.getCapability<&[Int]>(/public/numbers)
+              let account = getAccount(signer.address)
+              log(ref.length)
+          }
+      }
+    `)
+
+	stop := <-debugger.Stops()
+	require.Equal(t, 9, stop.Statement.StartPosition().Line)
+
+	frame := debugger.Frames(stop)[0]
+	inter := frame.Interpreter
+
+	evaluate := func(code string) interpreter.Value {
+		value, err := debugger.EvaluateIn(frame, mustParseExpression(t, code))
+		require.NoError(t, err)
+		return value
+	}
+
+	childNames := func(children []cmd.ValueChild) []string {
+		names := make([]string, 0, len(children))
+		for _, child := range children {
+			names = append(names, child.Name)
+		}
+		return names
+	}
+
+	numbers := interpreter.NewArrayValue(
+		inter,
+		interpreter.EmptyLocationRange,
+		interpreter.VariableSizedStaticType{
+			Type: interpreter.PrimitiveStaticTypeInt,
+		},
+		common.ZeroAddress,
+		interpreter.NewUnmeteredIntValueFromInt64(1),
+		interpreter.NewUnmeteredIntValueFromInt64(2),
+	)
+
+	// The borrowed reference is expanded to the elements of the stored array
+
+	ref := evaluate("ref")
+	require.IsType(t, &interpreter.StorageReferenceValue{}, ref)
+	require.True(t, cmd.HasValueChildren(inter, ref))
+
+	refChildren := cmd.ValueChildren(inter, ref)
+	require.Equal(t, []string{"[0]", "[1]"}, childNames(refChildren))
+	require.Equal(t, interpreter.NewUnmeteredIntValueFromInt64(2), refChildren[1].Value)
+
+	// The capability is expanded to its address, path, and target
+
+	capChildren := cmd.ValueChildren(inter, evaluate("cap"))
+	require.Equal(t, []string{"address", "path", "target"}, childNames(capChildren))
+	require.True(t, numbers.Equal(inter, interpreter.EmptyLocationRange, capChildren[2].Value))
+
+	// The account is expanded to its address and stored values
+
+	signerChildren := cmd.ValueChildren(inter, evaluate("signer"))
+	require.Equal(t,
+		[]string{"address", "/storage/numbers", "/public/numbers"},
+		childNames(signerChildren),
+	)
+	require.True(t, numbers.Equal(inter, interpreter.EmptyLocationRange, signerChildren[1].Value))
+
+	// The public account only has public stored values
+
+	publicAccountChildren := cmd.ValueChildren(inter, evaluate("account"))
+	require.Equal(t,
+		[]string{"address", "/public/numbers"},
+		childNames(publicAccountChildren),
+	)
+
+	debugger.Continue()
+
+	require.Equal(t, []string{"2"}, wait())
+}
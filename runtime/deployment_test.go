@@ -281,13 +281,13 @@ func TestRuntimeTransactionWithContractDeployment(t *testing.T) {
 					"5 |                       signer.contracts.add(name: \"Test\", code: \"0a202020202020202020202020202070756220636f6e74726163742054657374207b7d0a0a202020202020202020202020202066756e2074657374436173652829207b7d0a202020202020202020202020\".decodeHex())\n"+
 					"  |                       ^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^\n"+
 					"\n"+
-					"error: function declarations are not valid at the top-level\n"+
+					"error[C0115]: function declarations are not valid at the top-level\n"+
 					" --> 2a00000000000000.Test:4:18\n"+
 					"  |\n"+
 					"4 |               fun testCase() {}\n"+
 					"  |                   ^^^^^^^^\n"+
 					"\n"+
-					"error: missing access modifier for function\n"+
+					"error[C0020]: missing access modifier for function\n"+
 					" --> 2a00000000000000.Test:4:14\n"+
 					"  |\n"+
 					"4 |               fun testCase() {}\n"+
@@ -337,7 +337,7 @@ func TestRuntimeTransactionWithContractDeployment(t *testing.T) {
 					"5 |                       signer.contracts.add(name: \"Test\", code: \"0a202020202020202020202020202070756220636f6e74726163742054657374207b0a2020202020202020202020202020202020207075622066756e20746573742829207b2058207d0a20202020202020202020202020207d0a202020202020202020202020\".decodeHex())\n"+
 					"  |                       ^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^\n"+
 					"\n"+
-					"error: cannot find variable in this scope: `X`\n"+
+					"error[C0003]: cannot find variable in this scope: `X`\n"+
 					" --> 2a00000000000000.Test:3:35\n"+
 					"  |\n"+
 					"3 |                   pub fun test() { X }\n"+
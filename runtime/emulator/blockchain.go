@@ -0,0 +1,575 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+)
+
+// accountKeyWeight is the weight of the keys of accounts created by the blockchain,
+// which is sufficient to sign transactions
+const accountKeyWeight = 1000
+
+// queuedTransaction is a transaction which was added to the current block,
+// but which was not executed yet
+type queuedTransaction struct {
+	code        []byte
+	authorizers []common.Address
+	signers     []common.Address
+	arguments   [][]byte
+}
+
+// snapshot is a named snapshot of the blockchain
+type snapshot struct {
+	state  *state
+	states []*state
+}
+
+// Blockchain is an in-memory blockchain for the Cadence testing framework,
+// which is built on the emulator. See stdlib.Blockchain.
+//
+// Transactions are added to the current block, and executed one after the other.
+// Once all transactions of the block are executed, the block can be committed.
+// Scripts and transactions observe the last committed block.
+//
+// The state after each commit is kept, so the blockchain can be reset to a height,
+// and the state can be saved and restored as named snapshots
+type Blockchain struct {
+	emulator       *Emulator
+	serviceAccount *stdlib.Account
+	transactions   []*queuedTransaction
+	// states are the states after each block commit, by height
+	states                 []*state
+	snapshots              map[string]snapshot
+	standardLibraryHandler stdlib.StandardLibraryHandler
+	// mocks are the mocked functions of deployed contracts
+	mocks map[contractFunctionKey]*contractFunctionMock
+	// directory is the directory which relative paths of contracts are resolved against.
+	// If empty, paths are resolved against the working directory
+	directory string
+}
+
+var _ stdlib.Blockchain = &Blockchain{}
+var _ stdlib.ContractFunctionMocker = &Blockchain{}
+
+func NewBlockchain() *Blockchain {
+	return NewBlockchainWithConfig(runtime.Config{
+		AtreeValidationEnabled: true,
+	})
+}
+
+// NewBlockchainWithConfig returns a new blockchain, which executes programs
+// using a runtime with the given configuration, e.g. with a coverage report
+func NewBlockchainWithConfig(config runtime.Config) *Blockchain {
+	emulator := newEmulator(config)
+
+	// The standard library handler is used to import values into the interpreters of tests
+	environment := runtime.NewBaseInterpreterEnvironment(config)
+	environment.Configure(
+		emulator.iface,
+		runtime.NewCodesAndPrograms(),
+		nil,
+		nil,
+	)
+
+	blockchain := &Blockchain{
+		emulator:               emulator,
+		snapshots:              map[string]snapshot{},
+		standardLibraryHandler: environment,
+		mocks:                  map[contractFunctionKey]*contractFunctionMock{},
+	}
+
+	emulator.iface.onContractValue = blockchain.mockContractFunctions
+
+	serviceAccount, err := blockchain.CreateAccount()
+	if err != nil {
+		panic(err)
+	}
+	blockchain.serviceAccount = serviceAccount
+
+	// The genesis block contains the service account
+	blockchain.states = []*state{emulator.iface.state()}
+
+	return blockchain
+}
+
+// newPublicKey returns a new ECDSA P-256 public key.
+// The private key is not needed, as the signatures of transactions are not verified
+func newPublicKey() (*stdlib.PublicKey, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	const coordinateLength = 32
+	publicKey := make([]byte, 2*coordinateLength)
+	privateKey.PublicKey.X.FillBytes(publicKey[:coordinateLength])
+	privateKey.PublicKey.Y.FillBytes(publicKey[coordinateLength:])
+
+	return &stdlib.PublicKey{
+		PublicKey: publicKey,
+		SignAlgo:  sema.SignatureAlgorithmECDSA_P256,
+	}, nil
+}
+
+func (b *Blockchain) CreateAccount() (*stdlib.Account, error) {
+	iface := b.emulator.iface
+
+	address, err := iface.CreateAccount(common.ZeroAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := newPublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = iface.AddAccountKey(address, publicKey, sema.HashAlgorithmSHA3_256, accountKeyWeight)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stdlib.Account{
+		PublicKey: publicKey,
+		Address:   address,
+	}, nil
+}
+
+func (b *Blockchain) GetAccount(address interpreter.AddressValue) (*stdlib.Account, error) {
+	account, err := b.emulator.iface.account(address.ToAddress())
+	if err != nil {
+		return nil, err
+	}
+
+	var publicKey *stdlib.PublicKey
+	if len(account.keys) > 0 {
+		publicKey = account.keys[0].PublicKey
+	}
+
+	return &stdlib.Account{
+		PublicKey: publicKey,
+		Address:   address.ToAddress(),
+	}, nil
+}
+
+func (b *Blockchain) ServiceAccount() (*stdlib.Account, error) {
+	return b.serviceAccount, nil
+}
+
+// exportArguments exports the given values of the given interpreter
+func exportArguments(inter *interpreter.Interpreter, arguments []interpreter.Value) ([]cadence.Value, error) {
+	exportedArguments := make([]cadence.Value, 0, len(arguments))
+
+	for _, argument := range arguments {
+		exportedArgument, err := runtime.ExportValue(argument, inter, interpreter.EmptyLocationRange)
+		if err != nil {
+			return nil, err
+		}
+
+		exportedArguments = append(exportedArguments, exportedArgument)
+	}
+
+	return exportedArguments, nil
+}
+
+// encodeArguments encodes the given values as JSON-Cadence
+func encodeArguments(arguments []cadence.Value) ([][]byte, error) {
+	encodedArguments := make([][]byte, 0, len(arguments))
+
+	for _, argument := range arguments {
+		encodedArgument, err := jsoncdc.Encode(argument)
+		if err != nil {
+			return nil, err
+		}
+
+		encodedArguments = append(encodedArguments, encodedArgument)
+	}
+
+	return encodedArguments, nil
+}
+
+// exportAndEncodeArguments exports the given values of the given interpreter,
+// and encodes them as JSON-Cadence
+func exportAndEncodeArguments(inter *interpreter.Interpreter, arguments []interpreter.Value) ([][]byte, error) {
+	exportedArguments, err := exportArguments(inter, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeArguments(exportedArguments)
+}
+
+func (b *Blockchain) RunScript(
+	inter *interpreter.Interpreter,
+	code string,
+	arguments []interpreter.Value,
+) *stdlib.ScriptResult {
+
+	encodedArguments, err := exportAndEncodeArguments(inter, arguments)
+	if err != nil {
+		return &stdlib.ScriptResult{
+			Error: err,
+		}
+	}
+
+	result, err := b.emulator.executeScript([]byte(code), encodedArguments)
+	if err != nil {
+		return &stdlib.ScriptResult{
+			Error: err,
+		}
+	}
+
+	value, err := runtime.ImportValue(
+		inter,
+		interpreter.EmptyLocationRange,
+		b.standardLibraryHandler,
+		result,
+		nil,
+	)
+	if err != nil {
+		return &stdlib.ScriptResult{
+			Error: err,
+		}
+	}
+
+	return &stdlib.ScriptResult{
+		Value: value,
+	}
+}
+
+func (b *Blockchain) AddTransaction(
+	inter *interpreter.Interpreter,
+	code string,
+	authorizers []common.Address,
+	signers []*stdlib.Account,
+	arguments []interpreter.Value,
+) error {
+
+	encodedArguments, err := exportAndEncodeArguments(inter, arguments)
+	if err != nil {
+		return err
+	}
+
+	signerAddresses := make([]common.Address, 0, len(signers))
+	for _, signer := range signers {
+		signerAddresses = append(signerAddresses, signer.Address)
+	}
+
+	b.transactions = append(b.transactions, &queuedTransaction{
+		code:        []byte(code),
+		authorizers: authorizers,
+		signers:     signerAddresses,
+		arguments:   encodedArguments,
+	})
+
+	return nil
+}
+
+// ExecuteNextTransaction executes the next transaction of the current block.
+// It returns nil if all transactions of the current block were executed.
+//
+// Signatures are not verified, but each authorizer of the transaction must be one of its signers
+func (b *Blockchain) ExecuteNextTransaction() *stdlib.TransactionResult {
+	if len(b.transactions) == 0 {
+		return nil
+	}
+
+	transaction := b.transactions[0]
+	b.transactions = b.transactions[1:]
+
+	for _, authorizer := range transaction.authorizers {
+		if !containsAddress(transaction.signers, authorizer) {
+			return &stdlib.TransactionResult{
+				Error: errors.NewDefaultUserError(
+					"authorizer %s did not sign the transaction",
+					authorizer.HexWithPrefix(),
+				),
+			}
+		}
+	}
+
+	err := b.emulator.runTransaction(
+		transaction.code,
+		transaction.arguments,
+		transaction.authorizers...,
+	)
+
+	return &stdlib.TransactionResult{
+		Error: err,
+	}
+}
+
+func containsAddress(addresses []common.Address, address common.Address) bool {
+	for _, other := range addresses {
+		if other == address {
+			return true
+		}
+	}
+	return false
+}
+
+// CommitBlock commits the current block.
+// It fails if not all transactions of the block were executed
+func (b *Blockchain) CommitBlock() error {
+	if len(b.transactions) > 0 {
+		return errors.NewDefaultUserError(
+			"cannot commit block: %d transaction(s) were not executed",
+			len(b.transactions),
+		)
+	}
+
+	b.commitBlock()
+
+	return nil
+}
+
+// commitBlock commits a new block and records the resulting state,
+// so the blockchain can later be reset to the block's height
+func (b *Blockchain) commitBlock() {
+	iface := b.emulator.iface
+	iface.commitBlock(time.Unix(0, iface.currentBlock().Timestamp).Add(blockInterval))
+
+	b.states = append(b.states, iface.state())
+}
+
+// deployContractTransactionWithArguments returns a transaction which deploys or updates a contract,
+// like deployContractTransaction, and passes the given arguments to the contract's initializer.
+// The parameters of the transaction have the types of the arguments
+func deployContractTransactionWithArguments(initializerArguments []cadence.Value) string {
+	var parameters, arguments strings.Builder
+	for index, argument := range initializerArguments {
+		_, _ = fmt.Fprintf(&parameters, ", arg%d: %s", index, argument.Type().ID())
+		_, _ = fmt.Fprintf(&arguments, ", arg%d", index)
+	}
+
+	return fmt.Sprintf(
+		`
+          transaction(name: String, code: String, update: Bool%s) {
+              prepare(signer: AuthAccount) {
+                  if update {
+                      signer.contracts.update__experimental(name: name, code: code.utf8)
+                  } else {
+                      signer.contracts.add(name: name, code: code.utf8%s)
+                  }
+              }
+          }
+        `,
+		parameters.String(),
+		arguments.String(),
+	)
+}
+
+// SetDirectory sets the directory which relative paths of contracts are resolved against,
+// e.g. the directory of the test file which uses the blockchain
+func (b *Blockchain) SetDirectory(directory string) {
+	b.directory = directory
+}
+
+// DeployContract deploys the contract with the given name in the file with the given path
+// to the service account, initializes it with the given arguments, and commits a block.
+// If the contract is already deployed, it is updated, and the arguments must be empty
+func (b *Blockchain) DeployContract(
+	inter *interpreter.Interpreter,
+	name string,
+	path string,
+	arguments []interpreter.Value,
+) error {
+
+	if b.directory != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(b.directory, path)
+	}
+
+	code, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	address := b.serviceAccount.Address
+
+	existingCode, err := b.emulator.iface.GetAccountContractCode(common.NewAddressLocation(nil, address, name))
+	if err != nil {
+		return err
+	}
+
+	if existingCode != nil && len(arguments) > 0 {
+		return errors.NewDefaultUserError("cannot pass arguments when updating contract %s", name)
+	}
+
+	initializerArguments, err := exportArguments(inter, arguments)
+	if err != nil {
+		return err
+	}
+
+	encodedArguments, err := encodeArguments(
+		append(
+			[]cadence.Value{
+				cadence.String(name),
+				cadence.String(code),
+				cadence.Bool(existingCode != nil),
+			},
+			initializerArguments...,
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	err = b.emulator.runTransaction(
+		[]byte(deployContractTransactionWithArguments(initializerArguments)),
+		encodedArguments,
+		address,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Like transactions executed by the test framework,
+	// the deployment is committed in its own block
+
+	b.commitBlock()
+
+	return nil
+}
+
+func (b *Blockchain) StandardLibraryHandler() stdlib.StandardLibraryHandler {
+	return b.standardLibraryHandler
+}
+
+func (b *Blockchain) Logs() []string {
+	return b.emulator.Logs()
+}
+
+// Events returns the events emitted by successful transactions as values of the given interpreter,
+// optionally only the events of the given type
+func (b *Blockchain) Events(
+	inter *interpreter.Interpreter,
+	eventType interpreter.StaticType,
+) interpreter.Value {
+
+	var values []interpreter.Value
+
+	for _, event := range b.emulator.Events() {
+		if eventType != nil && event.EventType.ID() != string(eventType.ID()) {
+			continue
+		}
+
+		value, err := runtime.ImportValue(
+			inter,
+			interpreter.EmptyLocationRange,
+			b.standardLibraryHandler,
+			event,
+			nil,
+		)
+		if err != nil {
+			panic(err)
+		}
+
+		values = append(values, value)
+	}
+
+	return interpreter.NewArrayValue(
+		inter,
+		interpreter.EmptyLocationRange,
+		interpreter.NewVariableSizedStaticType(
+			inter,
+			interpreter.PrimitiveStaticTypeAnyStruct,
+		),
+		common.ZeroAddress,
+		values...,
+	)
+}
+
+// Reset resets the blockchain to the state after the commit of the block with the given height.
+// Transactions which were not executed yet are discarded
+func (b *Blockchain) Reset(height uint64) {
+	if height >= uint64(len(b.states)) {
+		panic(errors.NewDefaultUserError(
+			"cannot reset to height %d: the current height is %d",
+			height,
+			len(b.states)-1,
+		))
+	}
+
+	b.states = b.states[:height+1]
+	b.emulator.iface.restoreState(b.states[height])
+	b.transactions = nil
+}
+
+// MoveTime moves the time of the blockchain by the given number of seconds,
+// which may be negative
+func (b *Blockchain) MoveTime(delta int64) {
+	blocks := b.emulator.iface.blocks
+	blocks[len(blocks)-1].Timestamp += (time.Duration(delta) * time.Second).Nanoseconds()
+}
+
+// CreateSnapshot saves the current state of the blockchain as a snapshot with the given name.
+// An existing snapshot with the same name is replaced
+func (b *Blockchain) CreateSnapshot(name string) error {
+	b.snapshots[name] = snapshot{
+		state:  b.emulator.iface.state(),
+		states: append([]*state(nil), b.states...),
+	}
+	return nil
+}
+
+// LoadSnapshot restores the state of the blockchain saved as the snapshot with the given name.
+// Transactions which were not executed yet are discarded
+func (b *Blockchain) LoadSnapshot(name string) error {
+	snapshot, ok := b.snapshots[name]
+	if !ok {
+		return errors.NewDefaultUserError("cannot load snapshot: no snapshot named %s", name)
+	}
+
+	b.states = append([]*state(nil), snapshot.states...)
+	b.emulator.iface.restoreState(snapshot.state)
+	b.transactions = nil
+
+	return nil
+}
+
+// Program returns the checked program of the contract with the given location.
+// It can be used to import values of the contract's types into other interpreters
+func (b *Blockchain) Program(location common.AddressLocation) (*interpreter.Program, error) {
+	code, err := b.emulator.iface.GetAccountContractCode(location)
+	if err != nil {
+		return nil, err
+	}
+	if code == nil {
+		return nil, fmt.Errorf("cannot find contract: %s", location)
+	}
+
+	return b.emulator.runtime.ParseAndCheckProgram(code, b.emulator.newContext(location))
+}
@@ -0,0 +1,502 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+// newTestInterpreter returns an interpreter which imports the contracts deployed to the given blockchain,
+// like the interpreters of Cadence tests
+func newTestInterpreter(t *testing.T, blockchain *Blockchain) *interpreter.Interpreter {
+	inter, err := interpreter.NewInterpreter(
+		nil,
+		utils.TestLocation,
+		&interpreter.Config{
+			Storage: interpreter.NewInMemoryStorage(nil),
+			ImportLocationHandler: func(inter *interpreter.Interpreter, location common.Location) interpreter.Import {
+				program, err := blockchain.Program(location.(common.AddressLocation))
+				require.NoError(t, err)
+
+				subInterpreter, err := inter.NewSubInterpreter(program, location)
+				require.NoError(t, err)
+
+				return interpreter.InterpreterImport{
+					Interpreter: subInterpreter,
+				}
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	return inter
+}
+
+const testCounterContract = `
+  pub contract Counter {
+
+      pub event Incremented(count: Int)
+
+      pub var count: Int
+
+      init(start: Int) {
+          self.count = start
+      }
+
+      pub fun increment() {
+          self.count = self.count + 1
+          log(self.count)
+          emit Incremented(count: self.count)
+      }
+  }
+`
+
+const testIncrementTransaction = `
+  import Counter from 0x1
+
+  transaction {
+      prepare(signer: AuthAccount) {
+          Counter.increment()
+      }
+  }
+`
+
+const testCountScript = `
+  import Counter from 0x1
+
+  pub fun main(): Int {
+      return Counter.count
+  }
+`
+
+func newTestBlockchainWithCounter(t *testing.T) (*Blockchain, *interpreter.Interpreter) {
+	path := filepath.Join(t.TempDir(), "Counter.cdc")
+	err := os.WriteFile(path, []byte(testCounterContract), 0600)
+	require.NoError(t, err)
+
+	blockchain := NewBlockchain()
+	inter := newTestInterpreter(t, blockchain)
+
+	err = blockchain.DeployContract(
+		inter,
+		"Counter",
+		path,
+		[]interpreter.Value{interpreter.NewUnmeteredIntValueFromInt64(40)},
+	)
+	require.NoError(t, err)
+
+	return blockchain, inter
+}
+
+func testCount(t *testing.T, blockchain *Blockchain, inter *interpreter.Interpreter) interpreter.Value {
+	result := blockchain.RunScript(inter, testCountScript, nil)
+	require.NoError(t, result.Error)
+	return result.Value
+}
+
+func testIncrement(t *testing.T, blockchain *Blockchain, inter *interpreter.Interpreter) {
+	account, err := blockchain.CreateAccount()
+	require.NoError(t, err)
+
+	err = blockchain.AddTransaction(
+		inter,
+		testIncrementTransaction,
+		[]common.Address{account.Address},
+		[]*stdlib.Account{account},
+		nil,
+	)
+	require.NoError(t, err)
+
+	result := blockchain.ExecuteNextTransaction()
+	require.NotNil(t, result)
+	require.NoError(t, result.Error)
+
+	require.NoError(t, blockchain.CommitBlock())
+}
+
+func TestBlockchainAccounts(t *testing.T) {
+
+	t.Parallel()
+
+	blockchain := NewBlockchain()
+
+	serviceAccount, err := blockchain.ServiceAccount()
+	require.NoError(t, err)
+	assert.Equal(t, common.MustBytesToAddress([]byte{0x1}), serviceAccount.Address)
+
+	account, err := blockchain.CreateAccount()
+	require.NoError(t, err)
+	assert.Equal(t, common.MustBytesToAddress([]byte{0x2}), account.Address)
+
+	gotAccount, err := blockchain.GetAccount(interpreter.AddressValue(account.Address))
+	require.NoError(t, err)
+	assert.Equal(t, account, gotAccount)
+
+	_, err = blockchain.GetAccount(interpreter.AddressValue{0x42})
+	require.Error(t, err)
+}
+
+func TestBlockchainDeployContract(t *testing.T) {
+
+	t.Parallel()
+
+	blockchain, inter := newTestBlockchainWithCounter(t)
+
+	assert.Equal(t, interpreter.NewUnmeteredIntValueFromInt64(40), testCount(t, blockchain, inter))
+
+	testIncrement(t, blockchain, inter)
+
+	assert.Equal(t, interpreter.NewUnmeteredIntValueFromInt64(41), testCount(t, blockchain, inter))
+	assert.Equal(t, []string{"41"}, blockchain.Logs())
+
+	t.Run("update", func(t *testing.T) {
+
+		path := filepath.Join(t.TempDir(), "Counter.cdc")
+		err := os.WriteFile(path, []byte(testCounterContract), 0600)
+		require.NoError(t, err)
+
+		err = blockchain.DeployContract(
+			inter,
+			"Counter",
+			path,
+			[]interpreter.Value{interpreter.NewUnmeteredIntValueFromInt64(1)},
+		)
+		require.Error(t, err)
+
+		err = blockchain.DeployContract(inter, "Counter", path, nil)
+		require.NoError(t, err)
+
+		// The contract is updated, but not initialized again
+		assert.Equal(t, interpreter.NewUnmeteredIntValueFromInt64(41), testCount(t, blockchain, inter))
+	})
+}
+
+func TestBlockchainTransactions(t *testing.T) {
+
+	t.Parallel()
+
+	blockchain, inter := newTestBlockchainWithCounter(t)
+
+	t.Run("no transactions", func(t *testing.T) {
+		assert.Nil(t, blockchain.ExecuteNextTransaction())
+	})
+
+	t.Run("missing signer", func(t *testing.T) {
+
+		account, err := blockchain.CreateAccount()
+		require.NoError(t, err)
+
+		err = blockchain.AddTransaction(
+			inter,
+			testIncrementTransaction,
+			[]common.Address{account.Address},
+			nil,
+			nil,
+		)
+		require.NoError(t, err)
+
+		result := blockchain.ExecuteNextTransaction()
+		require.NotNil(t, result)
+		require.ErrorContains(t, result.Error, "did not sign the transaction")
+	})
+
+	t.Run("commit with pending transactions", func(t *testing.T) {
+
+		err := blockchain.AddTransaction(inter, "transaction {}", nil, nil, nil)
+		require.NoError(t, err)
+
+		require.ErrorContains(t, blockchain.CommitBlock(), "were not executed")
+
+		result := blockchain.ExecuteNextTransaction()
+		require.NotNil(t, result)
+		require.NoError(t, result.Error)
+
+		require.NoError(t, blockchain.CommitBlock())
+	})
+}
+
+func TestBlockchainEvents(t *testing.T) {
+
+	t.Parallel()
+
+	blockchain, inter := newTestBlockchainWithCounter(t)
+
+	testIncrement(t, blockchain, inter)
+	testIncrement(t, blockchain, inter)
+
+	eventType := interpreter.NewCompositeStaticTypeComputeTypeID(
+		nil,
+		common.NewAddressLocation(nil, common.MustBytesToAddress([]byte{0x1}), "Counter"),
+		"Counter.Incremented",
+	)
+
+	events := blockchain.Events(inter, eventType)
+	require.IsType(t, &interpreter.ArrayValue{}, events)
+	assert.Equal(t, 2, events.(*interpreter.ArrayValue).Count())
+
+	otherEventType := interpreter.NewCompositeStaticTypeComputeTypeID(
+		nil,
+		common.NewAddressLocation(nil, common.MustBytesToAddress([]byte{0x1}), "Counter"),
+		"Counter.Decremented",
+	)
+
+	events = blockchain.Events(inter, otherEventType)
+	require.IsType(t, &interpreter.ArrayValue{}, events)
+	assert.Equal(t, 0, events.(*interpreter.ArrayValue).Count())
+}
+
+func TestBlockchainReset(t *testing.T) {
+
+	t.Parallel()
+
+	blockchain, inter := newTestBlockchainWithCounter(t)
+
+	// Genesis block and deployment
+	height := uint64(1)
+
+	testIncrement(t, blockchain, inter)
+	assert.Equal(t, interpreter.NewUnmeteredIntValueFromInt64(41), testCount(t, blockchain, inter))
+
+	blockchain.Reset(height)
+	assert.Equal(t, interpreter.NewUnmeteredIntValueFromInt64(40), testCount(t, blockchain, inter))
+
+	assert.Panics(t, func() {
+		blockchain.Reset(height + 1)
+	})
+}
+
+func TestBlockchainSnapshots(t *testing.T) {
+
+	t.Parallel()
+
+	blockchain, inter := newTestBlockchainWithCounter(t)
+
+	require.NoError(t, blockchain.CreateSnapshot("deployed"))
+
+	testIncrement(t, blockchain, inter)
+	require.NoError(t, blockchain.CreateSnapshot("incremented"))
+
+	require.NoError(t, blockchain.LoadSnapshot("deployed"))
+	assert.Equal(t, interpreter.NewUnmeteredIntValueFromInt64(40), testCount(t, blockchain, inter))
+
+	// Snapshots can be loaded repeatedly
+
+	testIncrement(t, blockchain, inter)
+	testIncrement(t, blockchain, inter)
+	assert.Equal(t, interpreter.NewUnmeteredIntValueFromInt64(42), testCount(t, blockchain, inter))
+
+	require.NoError(t, blockchain.LoadSnapshot("incremented"))
+	assert.Equal(t, interpreter.NewUnmeteredIntValueFromInt64(41), testCount(t, blockchain, inter))
+
+	require.NoError(t, blockchain.LoadSnapshot("deployed"))
+	assert.Equal(t, interpreter.NewUnmeteredIntValueFromInt64(40), testCount(t, blockchain, inter))
+
+	require.Error(t, blockchain.LoadSnapshot("unknown"))
+}
+
+func TestBlockchainMoveTime(t *testing.T) {
+
+	t.Parallel()
+
+	blockchain := NewBlockchain()
+	inter := newTestInterpreter(t, blockchain)
+
+	const script = `
+      pub fun main(): UFix64 {
+          return getCurrentBlock().timestamp
+      }
+    `
+
+	before := blockchain.RunScript(inter, script, nil)
+	require.NoError(t, before.Error)
+
+	blockchain.MoveTime(3600)
+
+	after := blockchain.RunScript(inter, script, nil)
+	require.NoError(t, after.Error)
+
+	require.IsType(t, interpreter.UFix64Value(0), before.Value)
+	require.IsType(t, interpreter.UFix64Value(0), after.Value)
+	assert.Equal(
+		t,
+		interpreter.UFix64Value(3600*100_000_000),
+		after.Value.(interpreter.UFix64Value)-before.Value.(interpreter.UFix64Value),
+	)
+}
+
+func TestBlockchainMockContractFunction(t *testing.T) {
+
+	t.Parallel()
+
+	const oracleContract = `
+      pub contract Oracle {
+          pub fun price(_ symbol: String): UFix64 {
+              return 1.0
+          }
+      }
+    `
+
+	const consumerContract = `
+      import Oracle from 0x1
+
+      pub contract Consumer {
+          pub fun value(_ symbol: String, amount: UFix64): UFix64 {
+              return Oracle.price(symbol) * amount
+          }
+      }
+    `
+
+	const script = `
+      import Consumer from 0x1
+
+      pub fun main(): UFix64 {
+          return Consumer.value("FLOW", amount: 10.0)
+      }
+    `
+
+	directory := t.TempDir()
+
+	blockchain := NewBlockchain()
+	inter := newTestInterpreter(t, blockchain)
+
+	// The consumer imports the oracle, so the oracle must be deployed first
+	for _, contract := range []struct {
+		name string
+		code string
+	}{
+		{name: "Oracle", code: oracleContract},
+		{name: "Consumer", code: consumerContract},
+	} {
+		path := filepath.Join(directory, contract.name+".cdc")
+		err := os.WriteFile(path, []byte(contract.code), 0600)
+		require.NoError(t, err)
+
+		err = blockchain.DeployContract(inter, contract.name, path, nil)
+		require.NoError(t, err)
+	}
+
+	oracleType := &sema.CompositeType{
+		Location:   common.NewAddressLocation(nil, common.MustBytesToAddress([]byte{0x1}), "Oracle"),
+		Identifier: "Oracle",
+		Kind:       common.CompositeKindContract,
+	}
+
+	value := func() interpreter.Value {
+		result := blockchain.RunScript(inter, script, nil)
+		require.NoError(t, result.Error)
+		return result.Value
+	}
+
+	ufix64 := func(integer uint64) interpreter.Value {
+		return interpreter.NewUnmeteredUFix64ValueWithInteger(integer, interpreter.EmptyLocationRange)
+	}
+
+	t.Run("spy", func(t *testing.T) {
+
+		var calls [][]interpreter.Value
+
+		err := blockchain.MockContractFunction(
+			inter,
+			oracleType,
+			"price",
+			func(arguments []interpreter.Value) interpreter.Value {
+				calls = append(calls, arguments)
+				return nil
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, ufix64(10), value())
+		assert.Equal(
+			t,
+			[][]interpreter.Value{
+				{interpreter.NewUnmeteredStringValue("FLOW")},
+			},
+			calls,
+		)
+	})
+
+	t.Run("mock", func(t *testing.T) {
+
+		err := blockchain.MockContractFunction(
+			inter,
+			oracleType,
+			"price",
+			func(_ []interpreter.Value) interpreter.Value {
+				return ufix64(2)
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, ufix64(20), value())
+	})
+
+	t.Run("unmock", func(t *testing.T) {
+
+		blockchain.UnmockContractFunction(oracleType, "price")
+
+		assert.Equal(t, ufix64(10), value())
+	})
+
+	t.Run("invalid result", func(t *testing.T) {
+
+		err := blockchain.MockContractFunction(
+			inter,
+			oracleType,
+			"price",
+			func(_ []interpreter.Value) interpreter.Value {
+				return interpreter.NewUnmeteredStringValue("2.0")
+			},
+		)
+		require.NoError(t, err)
+
+		result := blockchain.RunScript(inter, script, nil)
+		require.Error(t, result.Error)
+
+		blockchain.UnmockContractFunction(oracleType, "price")
+	})
+
+	t.Run("not deployed", func(t *testing.T) {
+
+		err := blockchain.MockContractFunction(
+			inter,
+			&sema.CompositeType{
+				Location:   common.StringLocation("Oracle.cdc"),
+				Identifier: "Oracle",
+				Kind:       common.CompositeKindContract,
+			},
+			"price",
+			func(_ []interpreter.Value) interpreter.Value {
+				return nil
+			},
+		)
+		require.ErrorContains(t, err, "is not deployed")
+	})
+}
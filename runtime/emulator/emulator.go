@@ -0,0 +1,294 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/parser"
+)
+
+// blockInterval is the time between two blocks of the emulated chain
+const blockInterval = time.Second
+
+const deployContractTransaction = `
+transaction(name: String, code: String, update: Bool) {
+    prepare(signer: AuthAccount) {
+        if update {
+            signer.contracts.update__experimental(name: name, code: code.utf8)
+        } else {
+            signer.contracts.add(name: name, code: code.utf8)
+        }
+    }
+}
+`
+
+// Emulator is an in-memory emulation of a chain.
+//
+// It lets programs use accounts and their storage, contracts, capabilities and events,
+// without a node. Each transaction is executed in a new block
+type Emulator struct {
+	runtime   runtime.Runtime
+	iface     *runtimeInterface
+	locations uint64
+	// coverageReport, if set, collects the coverage of the executed programs
+	coverageReport *runtime.CoverageReport
+	// OnLog is called when a program logs a message
+	OnLog func(message string)
+}
+
+func New() *Emulator {
+	return NewWithDebugger(nil)
+}
+
+// NewWithDebugger returns a new emulator, which executes programs using the given debugger.
+// The debugger may be nil
+func NewWithDebugger(debugger *interpreter.Debugger) *Emulator {
+	return newEmulator(runtime.Config{
+		Debugger:               debugger,
+		AtreeValidationEnabled: true,
+	})
+}
+
+func newEmulator(config runtime.Config) *Emulator {
+	emulator := &Emulator{
+		runtime:        runtime.NewInterpreterRuntime(config),
+		iface:          newRuntimeInterface(),
+		coverageReport: config.CoverageReport,
+	}
+
+	emulator.iface.onLog = func(message string) {
+		if emulator.OnLog != nil {
+			emulator.OnLog(message)
+		}
+	}
+
+	return emulator
+}
+
+// CreateAccount creates a new account and returns its address
+func (e *Emulator) CreateAccount() (common.Address, error) {
+	return e.iface.CreateAccount(common.ZeroAddress)
+}
+
+// Accounts returns the addresses of all accounts, in the order they were created
+func (e *Emulator) Accounts() []common.Address {
+	addresses := make([]common.Address, 0, len(e.iface.accounts))
+	for index := uint64(1); index < e.iface.nextAddress; index++ {
+		var address common.Address
+		binary.BigEndian.PutUint64(address[:], index)
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+// DeployContract deploys the given contract or contract interface to the account with the given address.
+// If the account already has a contract with the same name, the contract is updated.
+//
+// DeployContract returns the name of the deployed contract
+func (e *Emulator) DeployContract(address common.Address, code []byte) (string, error) {
+	name, err := contractName(code)
+	if err != nil {
+		return "", err
+	}
+
+	existingCode, err := e.iface.GetAccountContractCode(common.NewAddressLocation(nil, address, name))
+	if err != nil {
+		return "", err
+	}
+
+	arguments := []cadence.Value{
+		cadence.String(name),
+		cadence.String(code),
+		cadence.Bool(existingCode != nil),
+	}
+
+	encodedArguments := make([][]byte, 0, len(arguments))
+	for _, argument := range arguments {
+		encodedArgument, err := jsoncdc.Encode(argument)
+		if err != nil {
+			return "", err
+		}
+		encodedArguments = append(encodedArguments, encodedArgument)
+	}
+
+	err = e.executeTransaction([]byte(deployContractTransaction), encodedArguments, address)
+	if err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// contractName returns the name of the sole contract or contract interface declared in the given code
+func contractName(code []byte) (string, error) {
+	program, err := parser.ParseProgram(nil, code, parser.Config{})
+	if err != nil {
+		return "", err
+	}
+
+	if contract := program.SoleContractDeclaration(); contract != nil {
+		return contract.Identifier.Identifier, nil
+	}
+
+	if contractInterface := program.SoleContractInterfaceDeclaration(); contractInterface != nil {
+		return contractInterface.Identifier.Identifier, nil
+	}
+
+	return "", fmt.Errorf("code must declare exactly one contract or contract interface")
+}
+
+// ExecuteTransaction executes the given transaction, signed by the accounts with the given addresses
+func (e *Emulator) ExecuteTransaction(code []byte, signers ...common.Address) error {
+	return e.executeTransaction(code, nil, signers...)
+}
+
+// ExecuteTransactionAt executes the given transaction with the given JSON-CDC encoded arguments,
+// signed by the accounts with the given addresses.
+//
+// The transaction has the given location, so breakpoints of a debugger for the location apply
+func (e *Emulator) ExecuteTransactionAt(
+	location common.TransactionLocation,
+	code []byte,
+	arguments [][]byte,
+	signers ...common.Address,
+) error {
+	e.commitBlock()
+
+	return e.runTransactionAt(location, code, arguments, signers...)
+}
+
+func (e *Emulator) executeTransaction(code []byte, arguments [][]byte, signers ...common.Address) error {
+	e.commitBlock()
+
+	return e.runTransaction(code, arguments, signers...)
+}
+
+// commitBlock commits the current block, so the next transaction is executed in a new block
+func (e *Emulator) commitBlock() {
+	e.iface.commitBlock(time.Unix(0, e.iface.currentBlock().Timestamp).Add(blockInterval))
+}
+
+// runTransaction executes the given transaction in the current block
+func (e *Emulator) runTransaction(code []byte, arguments [][]byte, signers ...common.Address) error {
+	location := common.NewTransactionLocation(nil, e.nextLocationID())
+	return e.runTransactionAt(location, code, arguments, signers...)
+}
+
+// runTransactionAt executes the given transaction with the given location in the current block
+func (e *Emulator) runTransactionAt(
+	location common.Location,
+	code []byte,
+	arguments [][]byte,
+	signers ...common.Address,
+) error {
+	iface := e.iface
+
+	for _, signer := range signers {
+		if _, err := iface.account(signer); err != nil {
+			return err
+		}
+	}
+
+	iface.signers = signers
+	defer func() {
+		iface.signers = nil
+	}()
+
+	// Events of failed transactions are discarded
+	eventCount := len(iface.events)
+
+	err := e.runtime.ExecuteTransaction(
+		runtime.Script{
+			Source:    code,
+			Arguments: arguments,
+		},
+		e.newContext(location),
+	)
+	if err != nil {
+		iface.events = iface.events[:eventCount]
+		return err
+	}
+
+	return nil
+}
+
+// ExecuteScript executes the given script and returns its result
+func (e *Emulator) ExecuteScript(code []byte) (cadence.Value, error) {
+	return e.executeScript(code, nil)
+}
+
+func (e *Emulator) executeScript(code []byte, arguments [][]byte) (cadence.Value, error) {
+	return e.runtime.ExecuteScript(
+		runtime.Script{
+			Source:    code,
+			Arguments: arguments,
+		},
+		e.newContext(common.NewScriptLocation(nil, e.nextLocationID())),
+	)
+}
+
+func (e *Emulator) newContext(location common.Location) runtime.Context {
+	return runtime.Context{
+		Interface:      e.iface,
+		Location:       location,
+		CoverageReport: e.coverageReport,
+	}
+}
+
+// nextLocationID returns a unique identifier for the location of a transaction or script
+func (e *Emulator) nextLocationID() []byte {
+	e.locations++
+
+	var id [common.TransactionIDLength]byte
+	binary.BigEndian.PutUint64(id[len(id)-8:], e.locations)
+	return id[:]
+}
+
+// Events returns all events emitted by successful transactions, in the order they were emitted
+func (e *Emulator) Events() []cadence.Event {
+	return e.iface.events
+}
+
+// Logs returns all messages logged by programs, in the order they were logged
+func (e *Emulator) Logs() []string {
+	return e.iface.logs
+}
+
+// Contracts returns the names of the contracts deployed to the account with the given address
+func (e *Emulator) Contracts(address common.Address) ([]string, error) {
+	return e.iface.GetAccountContractNames(address)
+}
+
+// Storage returns an interpreter which can be used to inspect the storage of accounts,
+// e.g. using cmd.StoredValues
+func (e *Emulator) Storage() (*interpreter.Interpreter, error) {
+	_, inter, err := e.runtime.Storage(runtime.Context{
+		Interface: e.iface,
+		Location:  common.NewScriptLocation(nil, e.nextLocationID()),
+	})
+	return inter, err
+}
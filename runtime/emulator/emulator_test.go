@@ -0,0 +1,236 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+func TestEmulator(t *testing.T) {
+
+	t.Parallel()
+
+	emulator := New()
+
+	var logs []string
+	emulator.OnLog = func(message string) {
+		logs = append(logs, message)
+	}
+
+	address, err := emulator.CreateAccount()
+	require.NoError(t, err)
+	assert.Equal(t, common.MustBytesToAddress([]byte{0x1}), address)
+
+	other, err := emulator.CreateAccount()
+	require.NoError(t, err)
+	assert.Equal(t, common.MustBytesToAddress([]byte{0x2}), other)
+
+	assert.Equal(t, []common.Address{address, other}, emulator.Accounts())
+
+	// Deploy a contract
+
+	const contract = `
+      pub contract Counters {
+
+          pub event Incremented(count: Int)
+
+          pub resource Counter {
+              pub var count: Int
+
+              init() {
+                  self.count = 0
+              }
+
+              pub fun increment() {
+                  self.count = self.count + 1
+                  emit Incremented(count: self.count)
+              }
+          }
+
+          pub fun createCounter(): @Counter {
+              return <-create Counter()
+          }
+      }
+    `
+
+	name, err := emulator.DeployContract(address, []byte(contract))
+	require.NoError(t, err)
+	assert.Equal(t, "Counters", name)
+
+	contracts, err := emulator.Contracts(address)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Counters"}, contracts)
+
+	// Use the contract in transactions
+
+	err = emulator.ExecuteTransaction(
+		[]byte(`
+          import Counters from 0x1
+
+          transaction {
+              prepare(signer: AuthAccount) {
+                  signer.save(<-Counters.createCounter(), to: /storage/counter)
+                  signer.link<&Counters.Counter>(/public/counter, target: /storage/counter)
+              }
+          }
+        `),
+		other,
+	)
+	require.NoError(t, err)
+
+	err = emulator.ExecuteTransaction(
+		[]byte(`
+          import Counters from 0x1
+
+          transaction {
+              prepare(signer: AuthAccount) {
+                  let counter = signer.borrow<&Counters.Counter>(from: /storage/counter)!
+                  counter.increment()
+                  log(counter.count)
+              }
+          }
+        `),
+		other,
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"1"}, logs)
+	assert.Equal(t, []string{"1"}, emulator.Logs())
+
+	events := emulator.Events()
+	require.Len(t, events, 2)
+	assert.Equal(t,
+		"flow.AccountContractAdded",
+		events[0].EventType.ID(),
+	)
+	assert.Equal(t,
+		"A.0000000000000001.Counters.Incremented",
+		events[1].EventType.ID(),
+	)
+	assert.Equal(t,
+		[]cadence.Value{cadence.NewInt(1)},
+		events[1].Fields,
+	)
+
+	// Failed transactions do not emit events and do not change storage
+
+	err = emulator.ExecuteTransaction(
+		[]byte(`
+          import Counters from 0x1
+
+          transaction {
+              prepare(signer: AuthAccount) {
+                  signer.borrow<&Counters.Counter>(from: /storage/counter)!.increment()
+                  panic("failed")
+              }
+          }
+        `),
+		other,
+	)
+	require.ErrorContains(t, err, "failed")
+	assert.Len(t, emulator.Events(), 2)
+
+	// Read the storage in a script
+
+	value, err := emulator.ExecuteScript([]byte(`
+      import Counters from 0x1
+
+      pub fun main(): Int {
+          return getAccount(0x2).getCapability<&Counters.Counter>(/public/counter).borrow()!.count
+      }
+    `))
+	require.NoError(t, err)
+	assert.Equal(t, cadence.NewInt(1), value)
+
+	// Inspect the storage
+
+	inter, err := emulator.Storage()
+	require.NoError(t, err)
+
+	storageMap := inter.Storage().GetStorageMap(other, common.PathDomainStorage.Identifier(), false)
+	require.NotNil(t, storageMap)
+
+	counter := storageMap.ReadValue(nil, interpreter.StringStorageMapKey("counter"))
+	require.IsType(t, &interpreter.CompositeValue{}, counter)
+	assert.Equal(t,
+		"A.0000000000000001.Counters.Counter",
+		string(counter.(*interpreter.CompositeValue).TypeID()),
+	)
+}
+
+func TestEmulatorUpdateContract(t *testing.T) {
+
+	t.Parallel()
+
+	emulator := New()
+
+	address, err := emulator.CreateAccount()
+	require.NoError(t, err)
+
+	_, err = emulator.DeployContract(address, []byte(`
+      pub contract Test {
+          pub fun answer(): Int {
+              return 1
+          }
+      }
+    `))
+	require.NoError(t, err)
+
+	_, err = emulator.DeployContract(address, []byte(`
+      pub contract Test {
+          pub fun answer(): Int {
+              return 42
+          }
+      }
+    `))
+	require.NoError(t, err)
+
+	value, err := emulator.ExecuteScript([]byte(`
+      import Test from 0x1
+
+      pub fun main(): Int {
+          return Test.answer()
+      }
+    `))
+	require.NoError(t, err)
+	assert.Equal(t, cadence.NewInt(42), value)
+}
+
+func TestEmulatorErrors(t *testing.T) {
+
+	t.Parallel()
+
+	emulator := New()
+
+	_, err := emulator.DeployContract(common.ZeroAddress, []byte(`pub fun test() {}`))
+	require.EqualError(t, err, "code must declare exactly one contract or contract interface")
+
+	err = emulator.ExecuteTransaction(
+		[]byte(`transaction { prepare(signer: AuthAccount) {} }`),
+		common.MustBytesToAddress([]byte{0x1}),
+	)
+	require.EqualError(t, err, "account does not exist: 0x0000000000000001")
+}
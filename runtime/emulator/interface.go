@@ -0,0 +1,606 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/onflow/atree"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+)
+
+// defaultStorageCapacity is the storage capacity of every account, in bytes
+const defaultStorageCapacity = 100 * 1024 * 1024
+
+// account is an account of the emulated chain
+type account struct {
+	keys      []*runtime.AccountKey
+	contracts map[string][]byte
+	// nextID is the next ID generated for the account, e.g. for capability controllers
+	nextID uint64
+}
+
+// runtimeInterface is the in-memory implementation of the runtime interface,
+// which is used by the emulator
+type runtimeInterface struct {
+	ledger   *Ledger
+	accounts map[common.Address]*account
+	// nextAddress is the address of the next account which is created
+	nextAddress uint64
+	signers     []common.Address
+	programs    map[common.Location]*interpreter.Program
+	events      []cadence.Event
+	logs        []string
+	onLog       func(message string)
+	// onContractValue is called when the value of a deployed contract is loaded
+	onContractValue func(inter *interpreter.Interpreter, contract *interpreter.CompositeValue)
+	uuid            uint64
+	blocks          []runtime.Block
+	random          *rand.Rand
+}
+
+var _ runtime.Interface = &runtimeInterface{}
+
+// state is a copy of the state of the emulated chain,
+// i.e. of the storage, the accounts, the events, and the blocks
+type state struct {
+	ledger      *Ledger
+	accounts    map[common.Address]*account
+	nextAddress uint64
+	events      []cadence.Event
+	uuid        uint64
+	blocks      []runtime.Block
+}
+
+func (a *account) clone() *account {
+	keys := make([]*runtime.AccountKey, 0, len(a.keys))
+	for _, key := range a.keys {
+		keyCopy := *key
+		keys = append(keys, &keyCopy)
+	}
+
+	contracts := make(map[string][]byte, len(a.contracts))
+	for name, code := range a.contracts { //nolint:maprange
+		contracts[name] = code
+	}
+
+	return &account{
+		keys:      keys,
+		contracts: contracts,
+		nextID:    a.nextID,
+	}
+}
+
+func cloneAccounts(accounts map[common.Address]*account) map[common.Address]*account {
+	clone := make(map[common.Address]*account, len(accounts))
+	for address, account := range accounts { //nolint:maprange
+		clone[address] = account.clone()
+	}
+	return clone
+}
+
+// state returns a copy of the current state of the emulated chain
+func (i *runtimeInterface) state() *state {
+	return &state{
+		ledger:      i.ledger.clone(),
+		accounts:    cloneAccounts(i.accounts),
+		nextAddress: i.nextAddress,
+		events:      append([]cadence.Event(nil), i.events...),
+		uuid:        i.uuid,
+		blocks:      append([]runtime.Block(nil), i.blocks...),
+	}
+}
+
+// restoreState restores the given state of the emulated chain.
+// The state is copied, so it can be restored again
+func (i *runtimeInterface) restoreState(state *state) {
+	i.ledger = state.ledger.clone()
+	i.accounts = cloneAccounts(state.accounts)
+	i.nextAddress = state.nextAddress
+	i.events = append([]cadence.Event(nil), state.events...)
+	i.uuid = state.uuid
+	i.blocks = append([]runtime.Block(nil), state.blocks...)
+
+	// The contracts may have changed
+	i.programs = map[common.Location]*interpreter.Program{}
+}
+
+func newRuntimeInterface() *runtimeInterface {
+	i := &runtimeInterface{
+		ledger:      NewLedger(),
+		accounts:    map[common.Address]*account{},
+		nextAddress: 1,
+		programs:    map[common.Location]*interpreter.Program{},
+		// The random source is seeded, so executions are deterministic
+		random: rand.New(rand.NewSource(0)),
+	}
+	i.commitBlock(time.Unix(0, 0))
+	return i
+}
+
+func (i *runtimeInterface) commitBlock(timestamp time.Time) {
+	height := uint64(len(i.blocks))
+
+	var hash stdlib.BlockHash
+	binary.BigEndian.PutUint64(hash[len(hash)-8:], height)
+
+	i.blocks = append(i.blocks, runtime.Block{
+		Height:    height,
+		View:      height,
+		Hash:      hash,
+		Timestamp: timestamp.UnixNano(),
+	})
+}
+
+func (i *runtimeInterface) currentBlock() runtime.Block {
+	return i.blocks[len(i.blocks)-1]
+}
+
+func (i *runtimeInterface) account(address common.Address) (*account, error) {
+	account, ok := i.accounts[address]
+	if !ok {
+		return nil, fmt.Errorf("account does not exist: %s", address.HexWithPrefix())
+	}
+	return account, nil
+}
+
+func (i *runtimeInterface) MeterMemory(_ common.MemoryUsage) error {
+	return nil
+}
+
+func (i *runtimeInterface) MeterComputation(_ common.ComputationKind, _ uint) error {
+	return nil
+}
+
+func (i *runtimeInterface) ComputationUsed() (uint64, error) {
+	return 0, nil
+}
+
+func (i *runtimeInterface) MemoryUsed() (uint64, error) {
+	return 0, nil
+}
+
+func (i *runtimeInterface) InteractionUsed() (uint64, error) {
+	return 0, nil
+}
+
+func (i *runtimeInterface) ResolveLocation(
+	identifiers []runtime.Identifier,
+	location runtime.Location,
+) (
+	[]runtime.ResolvedLocation,
+	error,
+) {
+	addressLocation, ok := location.(common.AddressLocation)
+	if !ok {
+		return []runtime.ResolvedLocation{
+			{
+				Location:    location,
+				Identifiers: identifiers,
+			},
+		}, nil
+	}
+
+	// If no specific identifiers are imported, import all contracts of the account
+
+	if len(identifiers) == 0 {
+		names, err := i.GetAccountContractNames(addressLocation.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range names {
+			identifiers = append(identifiers, runtime.Identifier{
+				Identifier: name,
+			})
+		}
+	}
+
+	resolvedLocations := make([]runtime.ResolvedLocation, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		resolvedLocations = append(resolvedLocations, runtime.ResolvedLocation{
+			Location: common.AddressLocation{
+				Address: addressLocation.Address,
+				Name:    identifier.Identifier,
+			},
+			Identifiers: []runtime.Identifier{identifier},
+		})
+	}
+
+	return resolvedLocations, nil
+}
+
+func (i *runtimeInterface) GetCode(location runtime.Location) ([]byte, error) {
+	addressLocation, ok := location.(common.AddressLocation)
+	if !ok {
+		return nil, fmt.Errorf("cannot import location: %s", location)
+	}
+	return i.GetAccountContractCode(addressLocation)
+}
+
+func (i *runtimeInterface) GetOrLoadProgram(
+	location runtime.Location,
+	load func() (*interpreter.Program, error),
+) (
+	program *interpreter.Program,
+	err error,
+) {
+	// Only the programs of contracts are cached,
+	// the locations of transactions and scripts are unique
+	_, cache := location.(common.AddressLocation)
+	if !cache {
+		return load()
+	}
+
+	program, ok := i.programs[location]
+	if ok {
+		return program, nil
+	}
+
+	program, err = load()
+	if err != nil {
+		return nil, err
+	}
+
+	i.programs[location] = program
+
+	return program, nil
+}
+
+func (i *runtimeInterface) SetInterpreterSharedState(state *interpreter.SharedState) {
+	// The shared state is not reused,
+	// but the contract value handler of its configuration is wrapped,
+	// so that the values of deployed contracts can be intercepted when they are loaded
+
+	if i.onContractValue == nil {
+		return
+	}
+
+	config := state.Config
+	handler := config.ContractValueHandler
+	if handler == nil {
+		return
+	}
+
+	config.ContractValueHandler = func(
+		inter *interpreter.Interpreter,
+		compositeType *sema.CompositeType,
+		constructorGenerator func(common.Address) *interpreter.HostFunctionValue,
+		invocationRange ast.Range,
+	) interpreter.ContractValue {
+		value := handler(inter, compositeType, constructorGenerator, invocationRange)
+
+		if contract, ok := value.(*interpreter.CompositeValue); ok {
+			i.onContractValue(inter, contract)
+		}
+
+		return value
+	}
+}
+
+func (i *runtimeInterface) GetInterpreterSharedState() *interpreter.SharedState {
+	return nil
+}
+
+func (i *runtimeInterface) GetValue(owner, key []byte) (value []byte, err error) {
+	return i.ledger.GetValue(owner, key)
+}
+
+func (i *runtimeInterface) SetValue(owner, key, value []byte) (err error) {
+	return i.ledger.SetValue(owner, key, value)
+}
+
+func (i *runtimeInterface) ValueExists(owner, key []byte) (exists bool, err error) {
+	return i.ledger.ValueExists(owner, key)
+}
+
+func (i *runtimeInterface) AllocateStorageIndex(owner []byte) (atree.StorageIndex, error) {
+	return i.ledger.AllocateStorageIndex(owner)
+}
+
+func (i *runtimeInterface) CreateAccount(_ runtime.Address) (address runtime.Address, err error) {
+	binary.BigEndian.PutUint64(address[:], i.nextAddress)
+	i.nextAddress++
+
+	i.accounts[address] = &account{
+		contracts: map[string][]byte{},
+	}
+
+	return address, nil
+}
+
+func (i *runtimeInterface) AddEncodedAccountKey(_ runtime.Address, _ []byte) error {
+	return errors.NewDefaultUserError("encoded account keys are not supported")
+}
+
+func (i *runtimeInterface) RevokeEncodedAccountKey(_ runtime.Address, _ int) (publicKey []byte, err error) {
+	return nil, errors.NewDefaultUserError("encoded account keys are not supported")
+}
+
+func (i *runtimeInterface) AddAccountKey(
+	address runtime.Address,
+	publicKey *runtime.PublicKey,
+	hashAlgo runtime.HashAlgorithm,
+	weight int,
+) (
+	*runtime.AccountKey,
+	error,
+) {
+	account, err := i.account(address)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &runtime.AccountKey{
+		KeyIndex:  len(account.keys),
+		PublicKey: publicKey,
+		HashAlgo:  hashAlgo,
+		Weight:    weight,
+	}
+	account.keys = append(account.keys, key)
+
+	return key, nil
+}
+
+func (i *runtimeInterface) GetAccountKey(address runtime.Address, index int) (*runtime.AccountKey, error) {
+	account, err := i.account(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if index < 0 || index >= len(account.keys) {
+		return nil, nil
+	}
+
+	return account.keys[index], nil
+}
+
+func (i *runtimeInterface) AccountKeysCount(address runtime.Address) (uint64, error) {
+	account, err := i.account(address)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(len(account.keys)), nil
+}
+
+func (i *runtimeInterface) RevokeAccountKey(address runtime.Address, index int) (*runtime.AccountKey, error) {
+	key, err := i.GetAccountKey(address, index)
+	if err != nil || key == nil {
+		return nil, err
+	}
+
+	key.IsRevoked = true
+
+	return key, nil
+}
+
+func (i *runtimeInterface) UpdateAccountContractCode(location common.AddressLocation, code []byte) (err error) {
+	account, err := i.account(location.Address)
+	if err != nil {
+		return err
+	}
+
+	account.contracts[location.Name] = code
+
+	// The program of the contract changed
+	delete(i.programs, location)
+
+	return nil
+}
+
+func (i *runtimeInterface) GetAccountContractCode(location common.AddressLocation) (code []byte, err error) {
+	account, ok := i.accounts[location.Address]
+	if !ok {
+		return nil, nil
+	}
+
+	return account.contracts[location.Name], nil
+}
+
+func (i *runtimeInterface) RemoveAccountContractCode(location common.AddressLocation) (err error) {
+	account, err := i.account(location.Address)
+	if err != nil {
+		return err
+	}
+
+	delete(account.contracts, location.Name)
+	delete(i.programs, location)
+
+	return nil
+}
+
+func (i *runtimeInterface) GetAccountContractNames(address runtime.Address) ([]string, error) {
+	account, ok := i.accounts[address]
+	if !ok {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(account.contracts))
+	for name := range account.contracts { //nolint:maprange
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (i *runtimeInterface) GetSigningAccounts() ([]runtime.Address, error) {
+	return i.signers, nil
+}
+
+func (i *runtimeInterface) ProgramLog(message string) error {
+	i.logs = append(i.logs, message)
+	if i.onLog != nil {
+		i.onLog(message)
+	}
+	return nil
+}
+
+func (i *runtimeInterface) EmitEvent(event cadence.Event) error {
+	i.events = append(i.events, event)
+	return nil
+}
+
+func (i *runtimeInterface) GenerateUUID() (uint64, error) {
+	uuid := i.uuid
+	i.uuid++
+	return uuid, nil
+}
+
+func (i *runtimeInterface) DecodeArgument(argument []byte, _ cadence.Type) (cadence.Value, error) {
+	return jsoncdc.Decode(nil, argument)
+}
+
+func (i *runtimeInterface) GetCurrentBlockHeight() (uint64, error) {
+	return i.currentBlock().Height, nil
+}
+
+func (i *runtimeInterface) GetBlockAtHeight(height uint64) (block runtime.Block, exists bool, err error) {
+	if height >= uint64(len(i.blocks)) {
+		return runtime.Block{}, false, nil
+	}
+	return i.blocks[height], true, nil
+}
+
+func (i *runtimeInterface) ReadRandom(buffer []byte) error {
+	_, err := i.random.Read(buffer)
+	return err
+}
+
+func (i *runtimeInterface) VerifySignature(
+	_ []byte,
+	_ string,
+	_ []byte,
+	_ []byte,
+	_ runtime.SignatureAlgorithm,
+	_ runtime.HashAlgorithm,
+) (bool, error) {
+	return false, errors.NewDefaultUserError("signature verification is not supported")
+}
+
+func (i *runtimeInterface) Hash(data []byte, tag string, hashAlgorithm runtime.HashAlgorithm) ([]byte, error) {
+	data = append([]byte(tag), data...)
+
+	switch hashAlgorithm {
+	case sema.HashAlgorithmSHA2_256:
+		hash := sha256.Sum256(data)
+		return hash[:], nil
+
+	case sema.HashAlgorithmSHA2_384:
+		hash := sha512.Sum384(data)
+		return hash[:], nil
+
+	case sema.HashAlgorithmSHA3_256:
+		hash := sha3.Sum256(data)
+		return hash[:], nil
+
+	case sema.HashAlgorithmSHA3_384:
+		hash := sha3.Sum384(data)
+		return hash[:], nil
+
+	case sema.HashAlgorithmKECCAK_256:
+		hasher := sha3.NewLegacyKeccak256()
+		hasher.Write(data)
+		return hasher.Sum(nil), nil
+	}
+
+	return nil, errors.NewDefaultUserError("hash algorithm is not supported: %s", hashAlgorithm)
+}
+
+func (i *runtimeInterface) GetAccountBalance(_ common.Address) (value uint64, err error) {
+	return 0, nil
+}
+
+func (i *runtimeInterface) GetAccountAvailableBalance(_ common.Address) (value uint64, err error) {
+	return 0, nil
+}
+
+func (i *runtimeInterface) GetStorageUsed(address runtime.Address) (value uint64, err error) {
+	return i.ledger.StorageUsed(address[:]), nil
+}
+
+func (i *runtimeInterface) GetStorageCapacity(_ runtime.Address) (value uint64, err error) {
+	return defaultStorageCapacity, nil
+}
+
+func (i *runtimeInterface) ImplementationDebugLog(_ string) error {
+	return nil
+}
+
+func (i *runtimeInterface) ValidatePublicKey(_ *runtime.PublicKey) error {
+	return nil
+}
+
+func (i *runtimeInterface) RecordTrace(
+	_ string,
+	_ runtime.Location,
+	_ time.Duration,
+	_ []attribute.KeyValue,
+) {
+	// NO-OP
+}
+
+func (i *runtimeInterface) BLSVerifyPOP(_ *runtime.PublicKey, _ []byte) (bool, error) {
+	return false, errors.NewDefaultUserError("BLS is not supported")
+}
+
+func (i *runtimeInterface) BLSAggregateSignatures(_ [][]byte) ([]byte, error) {
+	return nil, errors.NewDefaultUserError("BLS is not supported")
+}
+
+func (i *runtimeInterface) BLSAggregatePublicKeys(_ []*runtime.PublicKey) (*runtime.PublicKey, error) {
+	return nil, errors.NewDefaultUserError("BLS is not supported")
+}
+
+func (i *runtimeInterface) ResourceOwnerChanged(
+	_ *interpreter.Interpreter,
+	_ *interpreter.CompositeValue,
+	_ common.Address,
+	_ common.Address,
+) {
+	// NO-OP
+}
+
+func (i *runtimeInterface) GenerateAccountID(address common.Address) (uint64, error) {
+	account, err := i.account(address)
+	if err != nil {
+		return 0, err
+	}
+
+	account.nextID++
+	return account.nextID, nil
+}
@@ -0,0 +1,90 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"encoding/binary"
+	"strings"
+
+	"github.com/onflow/atree"
+)
+
+// Ledger is an in-memory ledger
+type Ledger struct {
+	values         map[string][]byte
+	storageIndices map[string]uint64
+}
+
+var _ atree.Ledger = &Ledger{}
+
+func NewLedger() *Ledger {
+	return &Ledger{
+		values:         map[string][]byte{},
+		storageIndices: map[string]uint64{},
+	}
+}
+
+func ledgerKey(owner, key []byte) string {
+	return strings.Join([]string{string(owner), string(key)}, "|")
+}
+
+func (l *Ledger) GetValue(owner, key []byte) (value []byte, err error) {
+	return l.values[ledgerKey(owner, key)], nil
+}
+
+func (l *Ledger) SetValue(owner, key, value []byte) (err error) {
+	l.values[ledgerKey(owner, key)] = value
+	return nil
+}
+
+func (l *Ledger) ValueExists(owner, key []byte) (exists bool, err error) {
+	return len(l.values[ledgerKey(owner, key)]) > 0, nil
+}
+
+func (l *Ledger) AllocateStorageIndex(owner []byte) (result atree.StorageIndex, err error) {
+	index := l.storageIndices[string(owner)] + 1
+	l.storageIndices[string(owner)] = index
+	binary.BigEndian.PutUint64(result[:], index)
+	return
+}
+
+// StorageUsed returns the number of bytes stored for the given owner
+func (l *Ledger) StorageUsed(owner []byte) uint64 {
+	prefix := string(owner) + "|"
+
+	var used uint64
+	for key, value := range l.values { //nolint:maprange
+		if strings.HasPrefix(key, prefix) {
+			used += uint64(len(key) + len(value))
+		}
+	}
+	return used
+}
+
+// clone returns a copy of the ledger
+func (l *Ledger) clone() *Ledger {
+	clone := NewLedger()
+	for key, value := range l.values { //nolint:maprange
+		clone.values[key] = value
+	}
+	for owner, index := range l.storageIndices { //nolint:maprange
+		clone.storageIndices[owner] = index
+	}
+	return clone
+}
@@ -0,0 +1,185 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+)
+
+// contractFunctionKey identifies a function of a deployed contract
+type contractFunctionKey struct {
+	contractTypeID common.TypeID
+	functionName   string
+}
+
+// contractFunctionMock is a mocked function of a deployed contract.
+// The calls of the function are handled by a handler of a test,
+// which runs in the test's interpreter
+type contractFunctionMock struct {
+	inter   *interpreter.Interpreter
+	handler stdlib.ContractFunctionHandler
+}
+
+// MockContractFunction replaces the function with the given name of the given deployed contract.
+// The arguments and the results of the calls are copied between the interpreters
+// of the blockchain and of the test, by exporting and importing them
+func (b *Blockchain) MockContractFunction(
+	inter *interpreter.Interpreter,
+	contractType *sema.CompositeType,
+	functionName string,
+	handler stdlib.ContractFunctionHandler,
+) error {
+	if _, ok := contractType.Location.(common.AddressLocation); !ok {
+		return errors.NewDefaultUserError(
+			"cannot mock function %s: contract %s is not deployed",
+			functionName,
+			contractType.QualifiedString(),
+		)
+	}
+
+	b.mocks[contractFunctionKey{
+		contractTypeID: contractType.ID(),
+		functionName:   functionName,
+	}] = &contractFunctionMock{
+		inter:   inter,
+		handler: handler,
+	}
+
+	return nil
+}
+
+// UnmockContractFunction restores the function with the given name of the given deployed contract
+func (b *Blockchain) UnmockContractFunction(
+	contractType *sema.CompositeType,
+	functionName string,
+) {
+	delete(b.mocks, contractFunctionKey{
+		contractTypeID: contractType.ID(),
+		functionName:   functionName,
+	})
+}
+
+// mockContractFunctions replaces the mocked functions of the given contract value,
+// which was loaded by a script or transaction
+func (b *Blockchain) mockContractFunctions(
+	inter *interpreter.Interpreter,
+	contract *interpreter.CompositeValue,
+) {
+	var functions map[string]interpreter.FunctionValue
+
+	for key, mock := range b.mocks { //nolint:maprange
+		if key.contractTypeID != contract.TypeID() {
+			continue
+		}
+
+		// The functions are shared by all values of the contract type,
+		// so only replace the functions of this value
+
+		if functions == nil {
+			contract.InitializeFunctions(inter)
+
+			functions = make(map[string]interpreter.FunctionValue, len(contract.Functions))
+			for name, function := range contract.Functions { //nolint:maprange
+				functions[name] = function
+			}
+		}
+
+		function, ok := functions[key.functionName]
+		if !ok {
+			continue
+		}
+
+		functions[key.functionName] = b.newMockedFunction(function, mock)
+	}
+
+	if functions != nil {
+		contract.Functions = functions
+	}
+}
+
+// newMockedFunction returns a function which has the type of the given function,
+// and which calls the handler of the given mock.
+// If the handler returns no result, the given function is called
+func (b *Blockchain) newMockedFunction(
+	function interpreter.FunctionValue,
+	mock *contractFunctionMock,
+) interpreter.FunctionValue {
+	functionType := function.FunctionType()
+
+	return interpreter.NewUnmeteredHostFunctionValue(
+		functionType,
+		func(invocation interpreter.Invocation) interpreter.Value {
+			inter := invocation.Interpreter
+			locationRange := invocation.LocationRange
+
+			arguments := make([]interpreter.Value, 0, len(invocation.Arguments))
+			for _, argument := range invocation.Arguments {
+				exportedArgument, err := runtime.ExportValue(argument, inter, locationRange)
+				if err != nil {
+					panic(err)
+				}
+
+				importedArgument, err := runtime.ImportValue(
+					mock.inter,
+					interpreter.EmptyLocationRange,
+					b.standardLibraryHandler,
+					exportedArgument,
+					nil,
+				)
+				if err != nil {
+					panic(err)
+				}
+
+				arguments = append(arguments, importedArgument)
+			}
+
+			result := mock.handler(arguments)
+			if result == nil {
+				result, err := inter.InvokeFunction(function, invocation)
+				if err != nil {
+					panic(err)
+				}
+				return result
+			}
+
+			exportedResult, err := runtime.ExportValue(result, mock.inter, interpreter.EmptyLocationRange)
+			if err != nil {
+				panic(err)
+			}
+
+			importedResult, err := runtime.ImportValue(
+				inter,
+				locationRange,
+				b.standardLibraryHandler,
+				exportedResult,
+				functionType.ReturnTypeAnnotation.Type,
+			)
+			if err != nil {
+				panic(err)
+			}
+
+			return importedResult
+		},
+	)
+}
@@ -141,20 +141,22 @@ func (e *interpreterEnvironment) newInterpreterConfig() *interpreter.Config {
 		OnRecordTrace:                        e.newOnRecordTraceHandler(),
 		OnResourceOwnerChange:                e.newResourceOwnerChangedHandler(),
 		CompositeTypeHandler:                 e.newCompositeTypeHandler(),
-		TracingEnabled:                       e.config.TracingEnabled,
+		TracingEnabled:                       e.config.TracingEnabled || e.config.TraceRecorder != nil,
 		AtreeValueValidationEnabled:          e.config.AtreeValidationEnabled,
 		// NOTE: ignore e.config.AtreeValidationEnabled here,
 		// and disable storage validation after each value modification.
 		// Instead, storage is validated after commits (if validation is enabled),
 		// see interpreterEnvironment.CommitStorage
-		AtreeStorageValidationEnabled: false,
-		Debugger:                      e.config.Debugger,
-		OnStatement:                   e.newOnStatementHandler(),
-		OnMeterComputation:            e.newOnMeterComputation(),
-		OnFunctionInvocation:          e.newOnFunctionInvocationHandler(),
-		OnInvokedFunctionReturn:       e.newOnInvokedFunctionReturnHandler(),
-		IDCapabilityBorrowHandler:     stdlib.BorrowCapabilityController,
-		IDCapabilityCheckHandler:      stdlib.CheckCapabilityController,
+		AtreeStorageValidationEnabled:   false,
+		Debugger:                        e.config.Debugger,
+		OnStatement:                     e.newOnStatementHandler(),
+		OnBranch:                        e.newOnBranchHandler(),
+		OnInterpretedFunctionInvocation: e.newOnInterpretedFunctionInvocationHandler(),
+		OnMeterComputation:              e.newOnMeterComputation(),
+		OnFunctionInvocation:            e.newOnFunctionInvocationHandler(),
+		OnInvokedFunctionReturn:         e.newOnInvokedFunctionReturnHandler(),
+		IDCapabilityBorrowHandler:       stdlib.BorrowCapabilityController,
+		IDCapabilityCheckHandler:        stdlib.CheckCapabilityController,
 	}
 }
 
@@ -653,6 +655,51 @@ func (e *interpreterEnvironment) newOnStatementHandler() interpreter.OnStatement
 	}
 }
 
+func (e *interpreterEnvironment) newOnBranchHandler() interpreter.OnBranchFunc {
+	if e.config.CoverageReport == nil {
+		return nil
+	}
+
+	return func(
+		inter *interpreter.Interpreter,
+		kind interpreter.BranchKind,
+		element ast.Element,
+		branch int,
+	) {
+		location := inter.Location
+		if !e.coverageReport.IsLocationInspected(location) {
+			program := inter.Program.Program
+			e.coverageReport.InspectProgram(location, program)
+		}
+
+		e.coverageReport.AddBranchHit(location, kind, element, branch)
+	}
+}
+
+func (e *interpreterEnvironment) newOnInterpretedFunctionInvocationHandler() interpreter.OnInterpretedFunctionInvocationFunc {
+	if e.config.CoverageReport == nil {
+		return nil
+	}
+
+	return func(_ *interpreter.Interpreter, function *interpreter.InterpretedFunctionValue) {
+		declaration := function.Declaration
+		if declaration == nil {
+			return
+		}
+
+		// The function is declared in the program of its own interpreter,
+		// which may differ from the invoking interpreter
+		inter := function.Interpreter
+		location := inter.Location
+		if !e.coverageReport.IsLocationInspected(location) {
+			program := inter.Program.Program
+			e.coverageReport.InspectProgram(location, program)
+		}
+
+		e.coverageReport.AddFunctionHit(location, declaration)
+	}
+}
+
 func (e *interpreterEnvironment) newOnRecordTraceHandler() interpreter.OnRecordTraceFunc {
 	return func(
 		interpreter *interpreter.Interpreter,
@@ -660,6 +707,17 @@ func (e *interpreterEnvironment) newOnRecordTraceHandler() interpreter.OnRecordT
 		duration time.Duration,
 		attrs []attribute.KeyValue,
 	) {
+		traceRecorder := e.config.TraceRecorder
+		if traceRecorder != nil {
+			traceRecorder.RecordTrace(functionName, interpreter.Location, duration, attrs)
+		}
+
+		// The trace recorder enables tracing on its own,
+		// so only report to the host environment if it enabled tracing
+		if !e.config.TracingEnabled {
+			return
+		}
+
 		errors.WrapPanic(func() {
 			e.runtimeInterface.RecordTrace(functionName, interpreter.Location, duration, attrs)
 		})
@@ -95,7 +95,7 @@ func TestRuntimeError(t *testing.T) {
 			t,
 			err,
 			"Execution failed:\n"+
-				"error: missing access modifier for function\n"+
+				"error[C0020]: missing access modifier for function\n"+
 				" --> 0100000000000000000000000000000000000000000000000000000000000000:1:0\n"+
 				"  |\n"+
 				"1 | fun test() {}\n"+
@@ -135,7 +135,7 @@ func TestRuntimeError(t *testing.T) {
 			t,
 			err,
 			"Execution failed:\n"+
-				"error: overflow\n"+
+				"error[R0008]: overflow\n"+
 				" --> 0100000000000000000000000000000000000000000000000000000000000000:6:16\n"+
 				"  |\n"+
 				"6 |                 a + b\n"+
@@ -173,7 +173,7 @@ func TestRuntimeError(t *testing.T) {
 			t,
 			err,
 			"Execution failed:\n"+
-				"error: unexpectedly found nil while forcing an Optional value\n"+
+				"error[R0013]: unexpectedly found nil while forcing an Optional value\n"+
 				" --> 0100000000000000000000000000000000000000000000000000000000000000:4:12\n"+
 				"  |\n"+
 				"4 | 				let y = x!\n"+
@@ -320,7 +320,7 @@ func TestRuntimeError(t *testing.T) {
 			t,
 			err,
 			"Execution failed:\n"+
-				"error: missing access modifier for function\n"+
+				"error[C0020]: missing access modifier for function\n"+
 				" --> imported:1:0\n"+
 				"  |\n"+
 				"1 | fun test() {}\n"+
@@ -382,7 +382,7 @@ func TestRuntimeError(t *testing.T) {
 				"5 |                 add()\n"+
 				"  |                 ^^^^^\n"+
 				"\n"+
-				"error: overflow\n"+
+				"error[R0008]: overflow\n"+
 				" --> imported:6:16\n"+
 				"  |\n"+
 				"6 |                 a + b\n"+
@@ -469,25 +469,25 @@ func TestRuntimeError(t *testing.T) {
 		)
 		require.EqualError(t, err,
 			"Execution failed:\n"+
-				"error: function declarations are not valid at the top-level\n"+
+				"error[C0115]: function declarations are not valid at the top-level\n"+
 				" --> 0000000000000002.B:3:22\n"+
 				"  |\n"+
 				"3 |               pub fun bar() {\n"+
 				"  |                       ^^^\n"+
 				"\n"+
-				"error: cannot find variable in this scope: `X`\n"+
+				"error[C0003]: cannot find variable in this scope: `X`\n"+
 				" --> 0000000000000002.B:5:18\n"+
 				"  |\n"+
 				"5 |                   X\n"+
 				"  |                   ^ not found in this scope\n"+
 				"\n"+
-				"error: function declarations are not valid at the top-level\n"+
+				"error[C0115]: function declarations are not valid at the top-level\n"+
 				" --> 0000000000000001.A:8:22\n"+
 				"  |\n"+
 				"8 |               pub fun foo() {\n"+
 				"  |                       ^^^\n"+
 				"\n"+
-				"error: cannot find variable in this scope: `Y`\n"+
+				"error[C0003]: cannot find variable in this scope: `Y`\n"+
 				"  --> 0000000000000001.A:10:18\n"+
 				"   |\n"+
 				"10 |                   Y\n"+
@@ -0,0 +1,114 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package errors
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ErrorCode is a stable code which identifies a kind of user error, e.g. `C0123`.
+//
+// Codes of checking errors start with `C`, codes of runtime errors start with `R`.
+// A code never changes, and is never reused for a different kind of error,
+// even if the error is removed
+type ErrorCode string
+
+// HasErrorCode is an interface for errors that provide a stable error code
+type HasErrorCode interface {
+	ErrorCode() ErrorCode
+}
+
+// ErrorExplanation is an entry of the error catalog,
+// which explains the errors with a code in long-form, with examples
+type ErrorExplanation struct {
+	Code ErrorCode
+	// Explanation is the explanation, formatted as Markdown
+	Explanation string
+}
+
+// Title returns the first line of the explanation, without the Markdown heading prefix
+func (e ErrorExplanation) Title() string {
+	title, _, _ := strings.Cut(e.Explanation, "\n")
+	return strings.TrimSpace(strings.TrimLeft(title, "#"))
+}
+
+var errorExplanations = map[ErrorCode]ErrorExplanation{}
+
+// RegisterErrorExplanations registers the explanations in the Markdown files of the given file system,
+// in the given directory. The name of each file is the error code, e.g. `C0123.md`.
+//
+// It panics if an explanation for an error code is already registered
+func RegisterErrorExplanations(fsys fs.FS, dir string) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || path.Ext(name) != ".md" {
+			continue
+		}
+
+		explanation, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			panic(err)
+		}
+
+		RegisterErrorExplanation(ErrorExplanation{
+			Code:        ErrorCode(strings.TrimSuffix(name, ".md")),
+			Explanation: string(explanation),
+		})
+	}
+}
+
+// RegisterErrorExplanation registers the given explanation.
+//
+// It panics if an explanation for the error code is already registered
+func RegisterErrorExplanation(explanation ErrorExplanation) {
+	code := explanation.Code
+	if _, ok := errorExplanations[code]; ok {
+		panic(fmt.Errorf("cannot register explanation for error code %s: already registered", code))
+	}
+	errorExplanations[code] = explanation
+}
+
+// LookupErrorExplanation returns the registered explanation for the given error code, if any
+func LookupErrorExplanation(code ErrorCode) (ErrorExplanation, bool) {
+	explanation, ok := errorExplanations[code]
+	return explanation, ok
+}
+
+// ErrorExplanations returns all registered explanations, sorted by error code
+func ErrorExplanations() []ErrorExplanation {
+	explanations := make([]ErrorExplanation, 0, len(errorExplanations))
+	for _, explanation := range errorExplanations { //nolint:maprange
+		explanations = append(explanations, explanation)
+	}
+
+	sort.Slice(explanations, func(i, j int) bool {
+		return explanations[i].Code < explanations[j].Code
+	})
+
+	return explanations
+}
@@ -0,0 +1,236 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package formatter formats Cadence programs in a canonical style.
+//
+// The program is rendered using the pretty-printing of the AST (ast.Program.Doc),
+// and the comments and blank lines of the program are inserted into the result.
+// The result is verified to be the same program, with the same comments
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/turbolent/prettier"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/parser"
+)
+
+// DefaultLineWidth is the default maximum width of lines
+const DefaultLineWidth = 80
+
+const indentation = "    "
+
+type Config struct {
+	// ParserConfig is the configuration used to parse programs.
+	// Trivia is always enabled
+	ParserConfig parser.Config
+	// LineWidth is the maximum width of lines.
+	// If zero, DefaultLineWidth is used
+	LineWidth int
+}
+
+// Format formats the given code.
+//
+// Formatting is idempotent: formatting formatted code results in the same code
+func Format(code []byte, config Config) ([]byte, error) {
+	parserConfig := config.ParserConfig
+	parserConfig.TriviaEnabled = true
+
+	program, err := parser.ParseProgram(nil, code, parserConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	lineWidth := config.LineWidth
+	if lineWidth == 0 {
+		lineWidth = DefaultLineWidth
+	}
+
+	// Line comments must end the line, so lists which contain elements with line comments
+	// are rendered on multiple lines
+
+	restore := breakListsWithLineComments(program, lineWidth)
+
+	var builder strings.Builder
+	prettier.Prettier(&builder, program.Doc(), lineWidth, indentation)
+
+	restore()
+
+	formatted := trimTrailingWhitespace(
+		strings.ReplaceAll(builder.String(), lineBreakMarker, ""),
+	)
+
+	// Parse the formatted code, to determine the positions of the elements,
+	// which are needed to insert the trivia
+
+	formattedProgram, err := parser.ParseProgram(nil, formatted, config.ParserConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse formatted program: %w", err)
+	}
+
+	result, err := insertTrivia(program, formattedProgram, formatted)
+	if err != nil {
+		return nil, err
+	}
+
+	err = verify(program, result, parserConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// trimTrailingWhitespace removes whitespace at the end of the lines of the given code,
+// e.g. the indentation of blank lines, and ends the code with a newline
+func trimTrailingWhitespace(code string) []byte {
+	if len(code) == 0 {
+		return nil
+	}
+
+	lines := strings.Split(code, "\n")
+
+	var builder strings.Builder
+	for _, line := range lines {
+		builder.WriteString(strings.TrimRight(line, " \t"))
+		builder.WriteByte('\n')
+	}
+
+	return []byte(builder.String())
+}
+
+// IsFormatted returns true if the given code is formatted
+func IsFormatted(code []byte, config Config) (bool, error) {
+	formatted, err := Format(code, config)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(code, formatted), nil
+}
+
+// verify checks that the formatted code is the same program as the original program,
+// and that it has the same comments
+func verify(program *ast.Program, formatted []byte, config parser.Config) error {
+	formattedProgram, err := parser.ParseProgram(nil, formatted, config)
+	if err != nil {
+		return fmt.Errorf("failed to parse formatted program: %w", err)
+	}
+
+	if !equalComments(program.Trivia(), formattedProgram.Trivia()) {
+		return fmt.Errorf("formatting would change the comments of the program")
+	}
+
+	equal, err := equalPrograms(program, formattedProgram)
+	if err != nil {
+		return err
+	}
+	if !equal {
+		return fmt.Errorf("formatting would change the program")
+	}
+
+	return nil
+}
+
+func equalComments(a, b []ast.Trivia) bool {
+	comments := func(trivia []ast.Trivia) []string {
+		var result []string
+		for _, item := range trivia {
+			if item.Kind == ast.TriviaKindBlankLine {
+				continue
+			}
+			result = append(result, item.Text)
+		}
+		return result
+	}
+
+	aComments := comments(a)
+	bComments := comments(b)
+
+	if len(aComments) != len(bComments) {
+		return false
+	}
+
+	for i, comment := range aComments {
+		if bComments[i] != comment {
+			return false
+		}
+	}
+
+	return true
+}
+
+// equalPrograms returns true if the given programs are equal, ignoring positions and trivia
+func equalPrograms(a, b *ast.Program) (bool, error) {
+	aJSON, err := programJSON(a)
+	if err != nil {
+		return false, err
+	}
+
+	bJSON, err := programJSON(b)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(aJSON, bJSON), nil
+}
+
+func programJSON(program *ast.Program) ([]byte, error) {
+	encoded, err := json.Marshal(program)
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+	err = json.Unmarshal(encoded, &value)
+	if err != nil {
+		return nil, err
+	}
+
+	if object, ok := value.(map[string]any); ok {
+		delete(object, "Trivia")
+		delete(object, "ElementTrivia")
+	}
+
+	return json.Marshal(withoutPositions(value))
+}
+
+// withoutPositions removes all positions from the given JSON value
+func withoutPositions(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, nested := range value { //nolint:maprange
+			if strings.HasSuffix(key, "Pos") {
+				delete(value, key)
+				continue
+			}
+			value[key] = withoutPositions(nested)
+		}
+
+	case []any:
+		for i, nested := range value {
+			value[i] = withoutPositions(nested)
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,263 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package formatter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/parser"
+	"github.com/onflow/cadence/runtime/tests/examples"
+)
+
+func testFormat(t *testing.T, code string, expected string) {
+	formatted, err := Format([]byte(code), Config{})
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(formatted))
+
+	// Formatting is idempotent
+
+	formattedAgain, err := Format(formatted, Config{})
+	require.NoError(t, err)
+	assert.Equal(t, string(formatted), string(formattedAgain))
+}
+
+func TestFormat(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `// License header
+
+import Foo from 0x1
+import   Bar from 0x2
+/// Doc comment
+pub fun   test(a: Int,   b: Int): Int {
+    let x = a+b   // sum
+
+
+    // explanation
+    let y = foo(/* first */ 1,   2 /* second */)
+    if x > y {
+        // nothing
+    }
+    return x   * y
+}
+pub struct S {
+    pub let x: Int // field
+
+    init() { self.x = 1 }
+    // end of S
+}
+// end of file
+`
+
+	const expected = `// License header
+
+import Foo from 0x1
+import Bar from 0x2
+
+/// Doc comment
+pub fun test(a: Int, b: Int): Int {
+    let x = a + b // sum
+
+    // explanation
+    let y = foo(/* first */ 1, 2 /* second */)
+    if x > y {
+        // nothing
+    }
+    return x * y
+}
+
+pub struct S {
+    pub let x: Int // field
+
+    init() {
+        self.x = 1
+    }
+    // end of S
+}
+// end of file
+`
+
+	testFormat(t, code, expected)
+}
+
+func TestFormatComments(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("only comments", func(t *testing.T) {
+
+		t.Parallel()
+
+		testFormat(t,
+			"\n// first\n\n/* second */\n",
+			"// first\n/* second */\n",
+		)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+
+		t.Parallel()
+
+		testFormat(t, "\n\n", "")
+	})
+
+	t.Run("line comment inside of line", func(t *testing.T) {
+
+		t.Parallel()
+
+		testFormat(t,
+			"let x = foo(1 // one\n, 2)\n",
+			"let x =\n    foo(\n        1, // one\n        2\n    )\n",
+		)
+	})
+
+	t.Run("line comments of arguments", func(t *testing.T) {
+
+		t.Parallel()
+
+		testFormat(t,
+			"fun test() { foo(1, // first\n 2 // second\n) }\n",
+			"fun test() {\n    foo(\n        1, // first\n        2 // second\n    )\n}\n",
+		)
+	})
+
+	t.Run("block comment after separator", func(t *testing.T) {
+
+		t.Parallel()
+
+		testFormat(t,
+			"let arr = [\n 1, /* one */\n 2\n]\n",
+			"let arr = [1, /* one */ 2]\n",
+		)
+	})
+
+	t.Run("empty composite", func(t *testing.T) {
+
+		t.Parallel()
+
+		testFormat(t,
+			"pub struct S { /* nothing */ }\n",
+			"pub struct S {\n    /* nothing */\n}\n",
+		)
+	})
+}
+
+func TestFormatInvalid(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := Format([]byte("fun test() {"), Config{})
+	require.Error(t, err)
+
+	var parserError parser.Error
+	require.ErrorAs(t, err, &parserError)
+}
+
+func TestIsFormatted(t *testing.T) {
+
+	t.Parallel()
+
+	formatted, err := IsFormatted([]byte("let x = 1\n"), Config{})
+	require.NoError(t, err)
+	assert.True(t, formatted)
+
+	formatted, err = IsFormatted([]byte("let   x = 1\n"), Config{})
+	require.NoError(t, err)
+	assert.False(t, formatted)
+}
+
+func TestFormatExamples(t *testing.T) {
+
+	t.Parallel()
+
+	for name, code := range map[string]string{
+		"FungibleTokenContractInterface": examples.FungibleTokenContractInterface,
+		"ExampleFungibleTokenContract":   examples.ExampleFungibleTokenContract,
+	} {
+		code := code
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			formatted, err := Format([]byte(code), Config{})
+			require.NoError(t, err)
+
+			// The formatted code is the same program
+
+			program, err := parser.ParseProgram(nil, []byte(code), parser.Config{})
+			require.NoError(t, err)
+
+			formattedProgram, err := parser.ParseProgram(nil, formatted, parser.Config{})
+			require.NoError(t, err)
+
+			equal, err := equalPrograms(program, formattedProgram)
+			require.NoError(t, err)
+			assert.True(t, equal)
+
+			// Formatting is idempotent
+
+			formattedAgain, err := Format(formatted, Config{})
+			require.NoError(t, err)
+			assert.Equal(t, string(formatted), string(formattedAgain))
+		})
+	}
+}
+
+func TestFormatIdempotent(t *testing.T) {
+
+	t.Parallel()
+
+	fixtures := map[string]string{
+		"FungibleTokenContractInterface": examples.FungibleTokenContractInterface,
+		"ExampleFungibleTokenContract":   examples.ExampleFungibleTokenContract,
+	}
+
+	paths, err := filepath.Glob(filepath.Join("testdata", "*.cdc"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		code, err := os.ReadFile(path)
+		require.NoError(t, err)
+		fixtures[path] = string(code)
+	}
+
+	for name, code := range fixtures { //nolint:maprange
+		code := code
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			formatted, err := Format([]byte(code), Config{})
+			require.NoError(t, err)
+
+			formattedAgain, err := Format(formatted, Config{})
+			require.NoError(t, err)
+			assert.Equal(t, string(formatted), string(formattedAgain))
+		})
+	}
+}
@@ -0,0 +1,456 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package formatter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/turbolent/prettier"
+
+	"github.com/onflow/cadence/runtime/ast"
+)
+
+// insertion is text which is inserted into the formatted code
+type insertion struct {
+	text string
+	// offset is the offset in the formatted code at which the text is inserted
+	offset int
+	// sourceOffset is the offset of the trivia in the original code.
+	// It orders insertions at the same offset
+	sourceOffset int
+}
+
+// insertTrivia inserts the trivia of the given program into the given formatted code.
+//
+// The formatted program is the result of parsing the formatted code.
+// As it is equal to the original program, the elements of both programs correspond
+// when they are walked in the same order
+func insertTrivia(program, formattedProgram *ast.Program, formatted []byte) ([]byte, error) {
+	elements := preOrderElements(program)
+	formattedElements := preOrderElements(formattedProgram)
+
+	if len(elements) != len(formattedElements) {
+		return nil, fmt.Errorf("formatted program has different elements")
+	}
+
+	inserter := &triviaInserter{
+		code: formatted,
+	}
+
+	for i, element := range elements {
+		formattedElement := formattedElements[i]
+		if element.ElementType() != formattedElement.ElementType() {
+			return nil, fmt.Errorf("formatted program has different elements")
+		}
+
+		trivia := program.ElementTrivia(element)
+
+		inserter.insertLeading(formattedElement, trivia.Leading)
+		inserter.insertTrailing(element, formattedElement, trivia.Trailing)
+		inserter.insertDangling(formattedElement, trivia.Dangling)
+	}
+
+	return inserter.apply(), nil
+}
+
+// preOrderElements returns the given program and all its elements, in pre-order
+func preOrderElements(program *ast.Program) []ast.Element {
+	var elements []ast.Element
+
+	var walk func(element ast.Element)
+	walk = func(element ast.Element) {
+		elements = append(elements, element)
+		element.Walk(walk)
+	}
+	walk(program)
+
+	return elements
+}
+
+type triviaInserter struct {
+	code       []byte
+	insertions []insertion
+}
+
+func (i *triviaInserter) insert(offset int, text string, trivia ast.Trivia) {
+	if len(text) == 0 {
+		return
+	}
+
+	i.insertions = append(i.insertions, insertion{
+		offset:       offset,
+		text:         text,
+		sourceOffset: trivia.StartPos.Offset,
+	})
+}
+
+func (i *triviaInserter) apply() []byte {
+	sort.SliceStable(i.insertions, func(a, b int) bool {
+		insertionA := i.insertions[a]
+		insertionB := i.insertions[b]
+		if insertionA.offset != insertionB.offset {
+			return insertionA.offset < insertionB.offset
+		}
+		return insertionA.sourceOffset < insertionB.sourceOffset
+	})
+
+	var builder strings.Builder
+	var offset int
+	for _, insertion := range i.insertions {
+		builder.Write(i.code[offset:insertion.offset])
+		builder.WriteString(insertion.text)
+		offset = insertion.offset
+	}
+	builder.Write(i.code[offset:])
+
+	return []byte(builder.String())
+}
+
+// lineStart returns the offset of the start of the line which contains the given offset
+func (i *triviaInserter) lineStart(offset int) int {
+	for offset > 0 && i.code[offset-1] != '\n' {
+		offset--
+	}
+	return offset
+}
+
+// indentation returns the indentation of the line which contains the given offset
+func (i *triviaInserter) indentation(offset int) string {
+	start := i.lineStart(offset)
+	end := start
+	for end < len(i.code) && (i.code[end] == ' ' || i.code[end] == '\t') {
+		end++
+	}
+	return string(i.code[start:end])
+}
+
+// isFirstOnLine returns true if only whitespace precedes the given offset on its line
+func (i *triviaInserter) isFirstOnLine(offset int) bool {
+	return strings.TrimSpace(string(i.code[i.lineStart(offset):offset])) == ""
+}
+
+// allowsBlankLine returns true if a blank line may be inserted before the line starting at the given offset,
+// i.e. if the line is not the first line of the code or a block, and the previous line is not blank
+func (i *triviaInserter) allowsBlankLine(lineStart int) bool {
+	if lineStart == 0 {
+		return false
+	}
+
+	previousLineStart := i.lineStart(lineStart - 1)
+	previousLine := strings.TrimSpace(string(i.code[previousLineStart:lineStart]))
+	if previousLine == "" {
+		return false
+	}
+
+	switch previousLine[len(previousLine)-1] {
+	case '{', '(', '[':
+		return false
+	}
+
+	return true
+}
+
+func (i *triviaInserter) insertLeading(element ast.Element, trivia []ast.Trivia) {
+	if len(trivia) == 0 {
+		return
+	}
+
+	offset := ast.ElementExtent(element).StartPos.Offset
+
+	if !i.isFirstOnLine(offset) {
+		// The element is inside a line, e.g. an argument.
+		// Blank lines are dropped, and a line comment ends the line
+		for _, item := range trivia {
+			switch item.Kind {
+			case ast.TriviaKindLineComment:
+				i.insert(offset, item.Text+"\n"+i.indentation(offset)+indentation, item)
+			case ast.TriviaKindBlockComment:
+				i.insert(offset, item.Text+" ", item)
+			}
+		}
+		return
+	}
+
+	lineStart := i.lineStart(offset)
+	elementIndentation := i.indentation(offset)
+
+	allowsBlankLine := i.allowsBlankLine(lineStart)
+
+	for _, item := range trivia {
+		switch item.Kind {
+		case ast.TriviaKindBlankLine:
+			if allowsBlankLine {
+				i.insert(lineStart, "\n", item)
+				allowsBlankLine = false
+			}
+
+		default:
+			i.insert(lineStart, elementIndentation+item.Text+"\n", item)
+			allowsBlankLine = true
+		}
+	}
+}
+
+func (i *triviaInserter) insertTrailing(element, formattedElement ast.Element, trivia []ast.Trivia) {
+	if len(trivia) == 0 {
+		return
+	}
+
+	formattedExtent := ast.ElementExtent(formattedElement)
+
+	// The trivia follows the separator of the element, if any,
+	// e.g. the comma after an argument, so it is attached to the same element
+	// when the formatted code is parsed again
+
+	offset := formattedExtent.EndPos.Offset + 1
+	if offset < len(i.code) && i.code[offset] == ',' {
+		offset++
+	}
+	elementIndentation := i.indentation(formattedExtent.StartPos.Offset)
+	endLine := ast.ElementExtent(element).EndPos.Line
+
+	var builder strings.Builder
+	var endsLine bool
+
+	for _, item := range trivia {
+		if item.Kind == ast.TriviaKindBlankLine {
+			continue
+		}
+
+		if item.StartPos.Line == endLine && !endsLine {
+			// The comment was on the same line as the end of the element
+			builder.WriteByte(' ')
+		} else {
+			builder.WriteByte('\n')
+			builder.WriteString(elementIndentation)
+		}
+		builder.WriteString(item.Text)
+
+		endsLine = item.Kind == ast.TriviaKindLineComment
+	}
+
+	// Code following a line comment must be on the next line
+	if endsLine && offset < len(i.code) && i.code[offset] != '\n' {
+		builder.WriteByte('\n')
+		builder.WriteString(elementIndentation)
+	}
+
+	i.insert(offset, builder.String(), trivia[0])
+}
+
+func (i *triviaInserter) insertDangling(element ast.Element, trivia []ast.Trivia) {
+	var comments []ast.Trivia
+	for _, item := range trivia {
+		if item.Kind != ast.TriviaKindBlankLine {
+			comments = append(comments, item)
+		}
+	}
+
+	if len(comments) == 0 {
+		return
+	}
+
+	if _, ok := element.(*ast.Program); ok {
+		// The program has no declarations
+		var builder strings.Builder
+		for _, comment := range comments {
+			builder.WriteString(comment.Text)
+			builder.WriteByte('\n')
+		}
+		i.insert(0, builder.String(), comments[0])
+		return
+	}
+
+	extent := ast.ElementExtent(element)
+
+	endOffset := extent.EndPos.Offset
+	elementIndentation := i.indentation(extent.StartPos.Offset)
+
+	var builder strings.Builder
+
+	if endOffset < len(i.code) && i.code[endOffset] == '}' {
+		// The comments are inside of empty braces, e.g. an empty block
+		for _, comment := range comments {
+			builder.WriteByte('\n')
+			builder.WriteString(elementIndentation)
+			builder.WriteString(indentation)
+			builder.WriteString(comment.Text)
+		}
+		builder.WriteByte('\n')
+		builder.WriteString(elementIndentation)
+
+		i.insert(endOffset, builder.String(), comments[0])
+		return
+	}
+
+	// Otherwise, the comments follow the element
+
+	var endsLine bool
+	for _, comment := range comments {
+		if !endsLine {
+			builder.WriteByte(' ')
+		}
+		builder.WriteString(comment.Text)
+		endsLine = comment.Kind == ast.TriviaKindLineComment
+		if endsLine {
+			builder.WriteByte('\n')
+			builder.WriteString(elementIndentation)
+		}
+	}
+
+	offset := endOffset + 1
+	if endsLine && offset < len(i.code) && i.code[offset] == '\n' {
+		// Avoid an empty line after the comment
+		text := strings.TrimRight(builder.String(), " \t")
+		builder.Reset()
+		builder.WriteString(strings.TrimSuffix(text, "\n"))
+	}
+
+	i.insert(offset, builder.String(), comments[0])
+}
+
+// lineBreakMarker is rendered after a list which must be broken into multiple lines.
+// It is wider than any line, so the list and the enclosing groups do not fit on one line,
+// and is removed from the rendered code
+var lineBreakMarker = "\x00"
+
+// lineBreakingExpression is an expression which is rendered followed by a line break marker
+type lineBreakingExpression struct {
+	ast.Expression
+	marker string
+}
+
+func (e lineBreakingExpression) Doc() prettier.Doc {
+	return prettier.Concat{
+		e.Expression.Doc(),
+		prettier.Text(e.marker),
+	}
+}
+
+// breakListsWithLineComments temporarily replaces lists, i.e. invocations, arrays, and dictionaries,
+// which have items with line comments, so the lists are rendered on multiple lines,
+// and the line comments can end the lines of the items.
+//
+// The returned function restores the original lists,
+// and must be called before the trivia of the program is inserted
+func breakListsWithLineComments(program *ast.Program, lineWidth int) (restore func()) {
+	marker := strings.Repeat(lineBreakMarker, lineWidth+1)
+
+	var restores []func()
+
+	replace := func(expression *ast.Expression) {
+		if *expression == nil || !hasItemWithLineComment(program, *expression) {
+			return
+		}
+
+		original := *expression
+		*expression = lineBreakingExpression{
+			Expression: original,
+			marker:     marker,
+		}
+		restores = append(restores, func() {
+			*expression = original
+		})
+	}
+
+	var walk func(element ast.Element)
+	walk = func(element ast.Element) {
+
+		// Walk the children first, the lists are replaced while walking the parent
+
+		element.Walk(walk)
+
+		switch element := element.(type) {
+		case *ast.ExpressionStatement:
+			replace(&element.Expression)
+
+		case *ast.ReturnStatement:
+			replace(&element.Expression)
+
+		case *ast.AssignmentStatement:
+			replace(&element.Value)
+
+		case *ast.VariableDeclaration:
+			replace(&element.Value)
+			replace(&element.SecondValue)
+
+		case *ast.InvocationExpression:
+			for _, argument := range element.Arguments {
+				replace(&argument.Expression)
+			}
+
+		case *ast.ArrayExpression:
+			for i := range element.Values {
+				replace(&element.Values[i])
+			}
+
+		case *ast.DictionaryExpression:
+			for i := range element.Entries {
+				entry := &element.Entries[i]
+				replace(&entry.Key)
+				replace(&entry.Value)
+			}
+		}
+	}
+	walk(program)
+
+	return func() {
+		for _, restore := range restores {
+			restore()
+		}
+	}
+}
+
+// hasItemWithLineComment returns true if the given expression is a list,
+// and one of its items has a line comment
+func hasItemWithLineComment(program *ast.Program, expression ast.Expression) bool {
+	var items []ast.Element
+
+	switch expression := expression.(type) {
+	case *ast.InvocationExpression:
+		for _, argument := range expression.Arguments {
+			items = append(items, argument.Expression)
+		}
+
+	case *ast.ArrayExpression:
+		for _, value := range expression.Values {
+			items = append(items, value)
+		}
+
+	case *ast.DictionaryExpression:
+		for _, entry := range expression.Entries {
+			items = append(items, entry.Key, entry.Value)
+		}
+	}
+
+	for _, item := range items {
+		trivia := program.ElementTrivia(item)
+		for _, comments := range [][]ast.Trivia{trivia.Leading, trivia.Trailing} {
+			for _, comment := range comments {
+				if comment.Kind == ast.TriviaKindLineComment {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
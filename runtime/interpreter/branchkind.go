@@ -0,0 +1,86 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"github.com/onflow/cadence/runtime/errors"
+)
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=BranchKind
+
+// BranchKind is the kind of an element which branches,
+// i.e. which executes only one of multiple alternatives.
+type BranchKind uint
+
+const (
+	BranchKindUnknown BranchKind = iota
+	// BranchKindIf is an if statement.
+	// Branch 0 is the then-block, branch 1 is the else-block, if any
+	BranchKindIf
+	// BranchKindSwitch is a switch statement.
+	// Branch N is the N-th case, branch len(cases) is taken when no case matched
+	BranchKindSwitch
+	// BranchKindConditional is a conditional expression (`a ? b : c`).
+	// Branch 0 is the then-expression, branch 1 is the else-expression
+	BranchKindConditional
+	// BranchKindNilCoalescing is a nil-coalescing expression (`a ?? b`).
+	// Branch 0 is taken when the left-hand side is not nil,
+	// branch 1 is the right-hand side
+	BranchKindNilCoalescing
+	// BranchKindOptionalChaining is an optional chaining member access (`a?.b`).
+	// Branch 0 is taken when the accessed value is not nil, branch 1 when it is nil
+	BranchKindOptionalChaining
+	// BranchKindCondition is a pre-condition or post-condition.
+	// Branch 0 is taken when the condition passed, branch 1 when it failed
+	BranchKindCondition
+)
+
+func BranchKindCount() int {
+	return len(_BranchKind_index) - 1
+}
+
+// Name returns the name of the kind, as used in coverage reports
+func (k BranchKind) Name() string {
+	switch k {
+	case BranchKindIf:
+		return "if"
+	case BranchKindSwitch:
+		return "switch"
+	case BranchKindConditional:
+		return "conditional"
+	case BranchKindNilCoalescing:
+		return "nil-coalescing"
+	case BranchKindOptionalChaining:
+		return "optional-chaining"
+	case BranchKindCondition:
+		return "condition"
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
+// BranchKindFromName returns the kind with the given name, as returned by Name
+func BranchKindFromName(name string) (BranchKind, bool) {
+	for kind := BranchKindUnknown + 1; int(kind) < BranchKindCount(); kind++ {
+		if kind.Name() == name {
+			return kind, true
+		}
+	}
+	return BranchKindUnknown, false
+}
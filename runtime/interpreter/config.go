@@ -57,6 +57,10 @@ type Config struct {
 	OnStatement OnStatementFunc
 	// OnLoopIteration is triggered when a loop iteration is about to be executed
 	OnLoopIteration OnLoopIterationFunc
+	// OnInterpretedFunctionInvocation is triggered when an interpreted function is about to be invoked
+	OnInterpretedFunctionInvocation OnInterpretedFunctionInvocationFunc
+	// OnBranch is triggered when a branch of a branching element is about to be taken
+	OnBranch OnBranchFunc
 	// InvalidatedResourceValidationEnabled determines if the validation of invalidated resources is enabled
 	InvalidatedResourceValidationEnabled bool
 	// TracingEnabled determines if tracing is enabled.
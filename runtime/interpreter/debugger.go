@@ -19,31 +19,103 @@
 package interpreter
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"sync/atomic"
 
 	"github.com/bits-and-blooms/bitset"
 
+	"github.com/onflow/cadence/runtime/activations"
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/sema"
 )
 
 type Stop struct {
 	Interpreter *Interpreter
 	Statement   ast.Statement
+	// Breakpoint is the breakpoint which caused the stop, if any
+	Breakpoint *Breakpoint
+	// ConditionError is the error which occurred
+	// when evaluating the condition of the breakpoint, if any
+	ConditionError error
+}
+
+// Breakpoint is a breakpoint on a line of a location.
+type Breakpoint struct {
+	Location common.Location
+	Line     uint
+	// Condition is an optional boolean expression,
+	// which is evaluated in the current activation when the breakpoint is reached.
+	// The breakpoint is only hit if the condition evaluates to true
+	Condition ast.Expression
+	// HitCount is the number of hits before the breakpoint takes effect.
+	// If it is zero, the breakpoint takes effect on every hit
+	HitCount uint
+	// LogMessage, if not nil, makes the breakpoint a logpoint:
+	// When it takes effect, the values of the expressions are concatenated and logged,
+	// instead of stopping the program. String values are logged without quotes
+	LogMessage []ast.Expression
+	hits       uint32
+}
+
+// Hits returns how often the breakpoint was hit,
+// i.e. how often it was reached and its condition was satisfied
+func (b *Breakpoint) Hits() uint {
+	return uint(atomic.LoadUint32(&b.hits))
+}
+
+func (b *Breakpoint) IsLogpoint() bool {
+	return b.LogMessage != nil
+}
+
+type StepMode uint8
+
+const (
+	// StepModeNone indicates no step was requested
+	StepModeNone StepMode = iota
+	// StepModeInto stops at the next statement
+	StepModeInto
+	// StepModeOver stops at the next statement in the current function or its callers
+	StepModeOver
+	// StepModeOut stops at the next statement in a caller of the current function
+	StepModeOut
+)
+
+type locationBreakpoints struct {
+	lines       *bitset.BitSet
+	breakpoints map[uint]*Breakpoint
 }
 
 type Debugger struct {
-	stops          chan Stop
-	continues      chan struct{}
-	breakpoints    map[common.Location]*bitset.BitSet
+	stops     chan Stop
+	continues chan struct{}
+	// lock guards breakpoints and the step state,
+	// which may be changed while the program is running
+	lock        sync.RWMutex
+	breakpoints map[common.Location]*locationBreakpoints
+	stepMode    StepMode
+	// stepDepth is the call stack depth at which the step was requested
+	stepDepth int
+	// stopDepth is the call stack depth of the last stop
+	stopDepth      int
 	pauseRequested uint32
+	// evaluating is non-zero while expressions are evaluated,
+	// statements executed during evaluation do not stop the program
+	evaluating int32
+	// OnLogpoint is called when a logpoint takes effect,
+	// with the interpolated message of the logpoint
+	OnLogpoint func(breakpoint *Breakpoint, message string)
 }
 
 func NewDebugger() *Debugger {
 	return &Debugger{
 		stops:       make(chan Stop),
 		continues:   make(chan struct{}),
-		breakpoints: map[common.Location]*bitset.BitSet{},
+		breakpoints: map[common.Location]*locationBreakpoints{},
 	}
 }
 
@@ -51,58 +123,224 @@ func (d *Debugger) Stops() <-chan Stop {
 	return d.stops
 }
 
+// AddBreakpoint adds an unconditional breakpoint
 func (d *Debugger) AddBreakpoint(location common.Location, line uint) {
-	breakpoints, ok := d.breakpoints[location]
+	d.SetBreakpoint(&Breakpoint{
+		Location: location,
+		Line:     line,
+	})
+}
+
+// SetBreakpoint adds the given breakpoint,
+// replacing any existing breakpoint on the same line
+func (d *Debugger) SetBreakpoint(breakpoint *Breakpoint) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	breakpoints, ok := d.breakpoints[breakpoint.Location]
 	if !ok {
-		breakpoints = bitset.New(1024)
-		d.breakpoints[location] = breakpoints
+		breakpoints = &locationBreakpoints{
+			lines:       bitset.New(1024),
+			breakpoints: map[uint]*Breakpoint{},
+		}
+		d.breakpoints[breakpoint.Location] = breakpoints
 	}
-	breakpoints.Set(line)
+	breakpoints.lines.Set(breakpoint.Line)
+	breakpoints.breakpoints[breakpoint.Line] = breakpoint
+}
+
+// Breakpoint returns the breakpoint on the given line, if any
+func (d *Debugger) Breakpoint(location common.Location, line uint) *Breakpoint {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	breakpoints, ok := d.breakpoints[location]
+	if !ok || !breakpoints.lines.Test(line) {
+		return nil
+	}
+	return breakpoints.breakpoints[line]
+}
+
+// Breakpoints returns all breakpoints, ordered by location and line
+func (d *Debugger) Breakpoints() []*Breakpoint {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	var result []*Breakpoint
+	for _, breakpoints := range d.breakpoints { //nolint:maprange
+		for _, breakpoint := range breakpoints.breakpoints { //nolint:maprange
+			result = append(result, breakpoint)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a := result[i]
+		b := result[j]
+		aID := a.Location.ID()
+		bID := b.Location.ID()
+		if aID != bID {
+			return aID < bID
+		}
+		return a.Line < b.Line
+	})
+
+	return result
 }
 
 func (d *Debugger) RemoveBreakpoint(location common.Location, line uint) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
 	breakpoints, ok := d.breakpoints[location]
 	if !ok {
 		return
 	}
-	breakpoints.Clear(line)
+	breakpoints.lines.Clear(line)
+	delete(breakpoints.breakpoints, line)
 }
 
 func (d *Debugger) ClearBreakpoints() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
 	for location := range d.breakpoints { //nolint:maprange
 		delete(d.breakpoints, location)
 	}
 }
 
 func (d *Debugger) ClearBreakpointsForLocation(location common.Location) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
 	delete(d.breakpoints, location)
 }
 
 func (d *Debugger) onStatement(interpreter *Interpreter, statement ast.Statement) {
-	if !atomic.CompareAndSwapUint32(&d.pauseRequested, 1, 0) {
-		breakpoints, ok := d.breakpoints[interpreter.Location]
-		if !ok {
-			return
-		}
-
-		startPosition := statement.StartPosition()
-		if !breakpoints.Test(uint(startPosition.Line)) {
-			return
-		}
+	if atomic.LoadInt32(&d.evaluating) > 0 {
+		return
 	}
 
-	d.stops <- Stop{
+	depth := len(interpreter.CallStack())
+
+	stop := Stop{
 		Interpreter: interpreter,
 		Statement:   statement,
 	}
 
+	if !d.shouldStop(&stop, depth) {
+		return
+	}
+
+	d.lock.Lock()
+	d.stepMode = StepModeNone
+	d.stopDepth = depth
+	d.lock.Unlock()
+
+	d.stops <- stop
+
 	<-d.continues
 }
 
+func (d *Debugger) shouldStop(stop *Stop, depth int) bool {
+	// NOTE: all conditions are evaluated,
+	// so that breakpoint hits and pause requests are not lost
+
+	breakpointHit := d.hitBreakpoint(stop)
+	paused := atomic.CompareAndSwapUint32(&d.pauseRequested, 1, 0)
+	stepped := d.stepCompleted(depth)
+
+	return breakpointHit || paused || stepped
+}
+
+func (d *Debugger) stepCompleted(depth int) bool {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	switch d.stepMode {
+	case StepModeInto:
+		return true
+	case StepModeOver:
+		return depth <= d.stepDepth
+	case StepModeOut:
+		return depth < d.stepDepth
+	}
+
+	return false
+}
+
+// hitBreakpoint checks if the stop's statement has a breakpoint which takes effect,
+// and returns true if the program should stop.
+// Logpoints which take effect log their message, but do not stop the program
+func (d *Debugger) hitBreakpoint(stop *Stop) bool {
+	line := uint(stop.Statement.StartPosition().Line)
+	breakpoint := d.Breakpoint(stop.Interpreter.Location, line)
+	if breakpoint == nil {
+		return false
+	}
+
+	if breakpoint.Condition != nil {
+		result, err := d.evaluate(
+			stop.Interpreter,
+			stop.Interpreter.activations.Current(),
+			breakpoint.Condition,
+			sema.BoolType,
+		)
+		if err != nil {
+			// Stop, so the condition can be fixed
+			stop.Breakpoint = breakpoint
+			stop.ConditionError = err
+			return true
+		}
+
+		if result != TrueValue {
+			return false
+		}
+	}
+
+	hits := atomic.AddUint32(&breakpoint.hits, 1)
+	if uint(hits) < breakpoint.HitCount {
+		return false
+	}
+
+	if breakpoint.IsLogpoint() {
+		onLogpoint := d.OnLogpoint
+		if onLogpoint != nil {
+			message := d.logMessage(stop.Interpreter, breakpoint.LogMessage)
+			onLogpoint(breakpoint, message)
+		}
+		return false
+	}
+
+	stop.Breakpoint = breakpoint
+	return true
+}
+
 func (d *Debugger) RequestPause() {
 	atomic.StoreUint32(&d.pauseRequested, 1)
 }
 
+// CancelRequests cancels pending pause and step requests,
+// e.g. when the program finished before they were fulfilled
+func (d *Debugger) CancelRequests() {
+	atomic.StoreUint32(&d.pauseRequested, 0)
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.stepMode = StepModeNone
+}
+
+// RequestStep requests the program to stop after a step of the given mode.
+// The step is relative to the last stop, so the program must be stopped
+// and continued afterwards
+func (d *Debugger) RequestStep(mode StepMode) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.stepMode = mode
+	d.stepDepth = d.stopDepth
+}
+
 func (d *Debugger) Continue() {
 	d.continues <- struct{}{}
 }
@@ -112,12 +350,329 @@ func (d *Debugger) Pause() Stop {
 	return <-d.Stops()
 }
 
-func (d *Debugger) Next() Stop {
-	d.RequestPause()
+func (d *Debugger) step(mode StepMode) Stop {
+	d.RequestStep(mode)
 	d.Continue()
 	return <-d.Stops()
 }
 
+// Next steps over the current statement,
+// i.e. it stops at the next statement in the current function or its callers
+func (d *Debugger) Next() Stop {
+	return d.step(StepModeOver)
+}
+
+// StepIn stops at the next statement,
+// which may be in a function invoked by the current statement
+func (d *Debugger) StepIn() Stop {
+	return d.step(StepModeInto)
+}
+
+// StepOut stops at the next statement in a caller of the current function
+func (d *Debugger) StepOut() Stop {
+	return d.step(StepModeOut)
+}
+
 func (d *Debugger) CurrentActivation(interpreter *Interpreter) *VariableActivation {
 	return interpreter.activations.Current()
 }
+
+// Frame is a frame of the call stack of a stopped program
+type Frame struct {
+	// Interpreter is the interpreter executing the frame's code
+	Interpreter *Interpreter
+	// Activation is the activation of the frame's variables
+	Activation *VariableActivation
+	Location   common.Location
+	Position   ast.Position
+}
+
+const topLevelFrameName = "<top level>"
+
+// FunctionName returns the qualified name of the function containing the frame's position,
+// e.g. `S.foo`, `transaction.prepare`, or `<top level>`
+func (f Frame) FunctionName() string {
+	program := f.Interpreter.Program
+	if program == nil || program.Program == nil {
+		return topLevelFrameName
+	}
+
+	name := enclosingFunctionName(program.Program.Declarations(), f.Position, "")
+	if name == "" {
+		return topLevelFrameName
+	}
+	return name
+}
+
+// Frames returns the frames of the given stop, innermost first.
+//
+// The innermost frame is the stopped statement,
+// each outer frame is the call site of an invocation on the call stack.
+func (d *Debugger) Frames(stop Stop) []Frame {
+	frames := []Frame{
+		{
+			Interpreter: stop.Interpreter,
+			Activation:  stop.Interpreter.activations.Current(),
+			Location:    stop.Interpreter.Location,
+			Position:    stop.Statement.StartPosition(),
+		},
+	}
+
+	callStack := stop.Interpreter.SharedState.callStack
+	for i := len(callStack.Invocations) - 1; i >= 0; i-- {
+		invocation := callStack.Invocations[i]
+
+		// Invocations by the host environment have no call site
+		locationRange := invocation.LocationRange
+		if locationRange.HasPosition == nil ||
+			invocation.Interpreter == nil {
+
+			continue
+		}
+
+		frames = append(frames, Frame{
+			Interpreter: invocation.Interpreter,
+			Activation:  callStack.callerActivation(i),
+			Location:    locationRange.Location,
+			Position:    locationRange.StartPosition(),
+		})
+	}
+
+	return frames
+}
+
+// enclosingFunctionName returns the qualified name of the innermost function
+// in the given declarations which contains the given position,
+// or the empty string if there is no such function.
+func enclosingFunctionName(declarations []ast.Declaration, position ast.Position, prefix string) string {
+	for _, declaration := range declarations {
+		if !containsPosition(declaration, position) {
+			continue
+		}
+
+		switch declaration := declaration.(type) {
+		case *ast.FunctionDeclaration:
+			return prefix + declaration.Identifier.Identifier
+
+		case *ast.SpecialFunctionDeclaration:
+			return prefix + declaration.Kind.Keywords()
+
+		case *ast.TransactionDeclaration:
+			var specialFunctions []ast.Declaration
+			if declaration.Prepare != nil {
+				specialFunctions = append(specialFunctions, declaration.Prepare)
+			}
+			if declaration.Execute != nil {
+				specialFunctions = append(specialFunctions, declaration.Execute)
+			}
+			return enclosingFunctionName(specialFunctions, position, prefix+"transaction.")
+		}
+
+		members := declaration.DeclarationMembers()
+		identifier := declaration.DeclarationIdentifier()
+		if members == nil || identifier == nil {
+			return ""
+		}
+
+		return enclosingFunctionName(
+			members.Declarations(),
+			position,
+			prefix+identifier.Identifier+".",
+		)
+	}
+
+	return ""
+}
+
+func containsPosition(element ast.HasPosition, position ast.Position) bool {
+	return element.StartPosition().Compare(position) <= 0 &&
+		position.Compare(element.EndPosition(nil)) <= 0
+}
+
+// Evaluate evaluates the given expression in the current activation of the given interpreter.
+//
+// The expression is checked against the types of the values in the activation,
+// so it may only refer to variables and global declarations, but not to types.
+// Access control is not enforced.
+func (d *Debugger) Evaluate(interpreter *Interpreter, expression ast.Expression) (Value, error) {
+	return d.evaluate(interpreter, interpreter.activations.Current(), expression, nil)
+}
+
+// EvaluateIn evaluates the given expression in the activation of the given frame,
+// like Evaluate
+func (d *Debugger) EvaluateIn(frame Frame, expression ast.Expression) (Value, error) {
+	return d.evaluate(frame.Interpreter, frame.Activation, expression, nil)
+}
+
+func (d *Debugger) evaluate(
+	interpreter *Interpreter,
+	activation *VariableActivation,
+	expression ast.Expression,
+	expectedType sema.Type,
+) (
+	result Value,
+	err error,
+) {
+	atomic.AddInt32(&d.evaluating, 1)
+	defer atomic.AddInt32(&d.evaluating, -1)
+
+	// Evaluate the expression using a separate interpreter,
+	// which shares the state and the activation of the given interpreter.
+	// It is not registered as the interpreter of the location.
+	//
+	// The evaluation is not part of the debugged program,
+	// so computation and memory are not metered
+
+	evaluatorConfig := *interpreter.SharedState.Config
+	evaluatorConfig.OnMeterComputation = nil
+	evaluatorConfig.MemoryGauge = nil
+
+	evaluatorSharedState := *interpreter.SharedState
+	evaluatorSharedState.Config = &evaluatorConfig
+
+	evaluator := &Interpreter{
+		Program:     interpreter.Program,
+		Location:    interpreter.Location,
+		SharedState: &evaluatorSharedState,
+		Globals:     interpreter.Globals,
+	}
+
+	checker, err := sema.NewChecker(
+		nil,
+		interpreter.Location,
+		nil,
+		&sema.Config{
+			BaseValueActivation: debuggerValueActivation(evaluator, activation),
+			AccessCheckMode:     sema.AccessCheckModeNone,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	checker.VisitExpression(expression, expectedType)
+	checkerErr := checker.CheckerError()
+	if checkerErr != nil {
+		return nil, checkerErr
+	}
+
+	// The expression is evaluated using the checker's elaboration
+
+	evaluator.Program = ProgramFromChecker(checker)
+	evaluator.activations = activations.NewActivations[*Variable](evaluator)
+	evaluator.activations.Push(activation)
+
+	defer evaluator.RecoverErrors(func(internalErr error) {
+		err = internalErr
+	})
+
+	return evaluator.evalExpression(expression), nil
+}
+
+// debuggerValueActivation returns a value activation for the checker,
+// which declares the variables of the given activation.
+//
+// Globals are declared with their declared types, all other variables
+// with the types of their current values
+func debuggerValueActivation(
+	interpreter *Interpreter,
+	activation *VariableActivation,
+) *sema.VariableActivation {
+
+	globalValueActivation := sema.NewVariableActivation(sema.BaseValueActivation)
+
+	program := interpreter.Program
+	if program != nil && program.Elaboration != nil {
+		program.Elaboration.ForEachGlobalValue(func(name string, variable *sema.Variable) {
+			globalValueActivation.Set(name, variable)
+		})
+	}
+
+	valueActivation := sema.NewVariableActivation(globalValueActivation)
+
+	declare := func(name string, variable *Variable) error {
+		if valueActivation.Find(name) != nil ||
+			interpreter.Globals.Get(name) == variable {
+
+			// Shadowed or global
+			return nil
+		}
+
+		value := variable.GetValue()
+		if value == nil {
+			return nil
+		}
+
+		staticType := value.StaticType(interpreter)
+		if staticType == nil {
+			return nil
+		}
+
+		semaType, err := interpreter.ConvertStaticToSemaType(staticType)
+		if err != nil {
+			return nil
+		}
+
+		valueActivation.Set(name, &sema.Variable{
+			Identifier:      name,
+			Type:            semaType,
+			DeclarationKind: common.DeclarationKindConstant,
+			Access:          ast.AccessPublic,
+			IsConstant:      true,
+		})
+
+		return nil
+	}
+
+	// The default base activation is not included,
+	// its values are declared by the checker's base value activation.
+	// Values of the configured base activation, e.g. of the environment,
+	// are declared with the types of their values
+
+	for current := activation; current != nil && current != BaseActivation; current = current.Parent {
+		_ = current.ForEach(declare)
+	}
+
+	return valueActivation
+}
+
+// logMessage evaluates the expressions of the given log message,
+// and returns the concatenation of their values
+func (d *Debugger) logMessage(interpreter *Interpreter, parts []ast.Expression) string {
+	var builder strings.Builder
+
+	for _, part := range parts {
+		if stringExpression, ok := part.(*ast.StringExpression); ok {
+			builder.WriteString(stringExpression.Value)
+			continue
+		}
+
+		value, err := d.Evaluate(interpreter, part)
+		if err != nil {
+			_, _ = fmt.Fprintf(&builder, "<error: %s>", evaluationErrorMessage(err))
+		} else if stringValue, ok := value.(*StringValue); ok {
+			builder.WriteString(stringValue.Str)
+		} else {
+			builder.WriteString(value.String())
+		}
+	}
+
+	return builder.String()
+}
+
+// evaluationErrorMessage returns a short message for the given evaluation error,
+// without the location information included in parsing and checking errors
+func evaluationErrorMessage(err error) string {
+	parentErr, ok := err.(errors.ParentError)
+	if !ok {
+		return err.Error()
+	}
+
+	childErrors := parentErr.ChildErrors()
+	messages := make([]string, 0, len(childErrors))
+	for _, childErr := range childErrors {
+		messages = append(messages, childErr.Error())
+	}
+
+	return strings.Join(messages, ", ")
+}
@@ -0,0 +1,35 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"embed"
+
+	"github.com/onflow/cadence/runtime/errors"
+)
+
+// errorExplanations contains the explanations for the codes of the runtime errors,
+// one Markdown file per error code
+//
+//go:embed explanations
+var errorExplanations embed.FS
+
+func init() {
+	errors.RegisterErrorExplanations(errorExplanations, "explanations")
+}
@@ -138,10 +138,15 @@ type NotDeclaredError struct {
 }
 
 var _ errors.UserError = NotDeclaredError{}
+var _ errors.HasErrorCode = NotDeclaredError{}
 var _ errors.SecondaryError = NotDeclaredError{}
 
 func (NotDeclaredError) IsUserError() {}
 
+func (NotDeclaredError) ErrorCode() errors.ErrorCode {
+	return "R0001"
+}
+
 func (e NotDeclaredError) Error() string {
 	return fmt.Sprintf(
 		"cannot find %s in this scope: `%s`",
@@ -161,9 +166,14 @@ type NotInvokableError struct {
 }
 
 var _ errors.UserError = NotInvokableError{}
+var _ errors.HasErrorCode = NotInvokableError{}
 
 func (NotInvokableError) IsUserError() {}
 
+func (NotInvokableError) ErrorCode() errors.ErrorCode {
+	return "R0002"
+}
+
 func (e NotInvokableError) Error() string {
 	return fmt.Sprintf("cannot call value: %#+v", e.Value)
 }
@@ -176,9 +186,14 @@ type ArgumentCountError struct {
 }
 
 var _ errors.UserError = ArgumentCountError{}
+var _ errors.HasErrorCode = ArgumentCountError{}
 
 func (ArgumentCountError) IsUserError() {}
 
+func (ArgumentCountError) ErrorCode() errors.ErrorCode {
+	return "R0003"
+}
+
 func (e ArgumentCountError) Error() string {
 	return fmt.Sprintf(
 		"incorrect number of arguments: expected %d, got %d",
@@ -194,9 +209,14 @@ type TransactionNotDeclaredError struct {
 }
 
 var _ errors.UserError = TransactionNotDeclaredError{}
+var _ errors.HasErrorCode = TransactionNotDeclaredError{}
 
 func (TransactionNotDeclaredError) IsUserError() {}
 
+func (TransactionNotDeclaredError) ErrorCode() errors.ErrorCode {
+	return "R0004"
+}
+
 func (e TransactionNotDeclaredError) Error() string {
 	return fmt.Sprintf(
 		"cannot find transaction with index %d in this scope",
@@ -213,9 +233,14 @@ type ConditionError struct {
 }
 
 var _ errors.UserError = ConditionError{}
+var _ errors.HasErrorCode = ConditionError{}
 
 func (ConditionError) IsUserError() {}
 
+func (ConditionError) ErrorCode() errors.ErrorCode {
+	return "R0005"
+}
+
 func (e ConditionError) Error() string {
 	if e.Message == "" {
 		return fmt.Sprintf("%s failed", e.ConditionKind.Name())
@@ -230,9 +255,14 @@ type RedeclarationError struct {
 }
 
 var _ errors.UserError = RedeclarationError{}
+var _ errors.HasErrorCode = RedeclarationError{}
 
 func (RedeclarationError) IsUserError() {}
 
+func (RedeclarationError) ErrorCode() errors.ErrorCode {
+	return "R0006"
+}
+
 func (e RedeclarationError) Error() string {
 	return fmt.Sprintf("cannot redeclare: `%s` is already declared", e.Name)
 }
@@ -247,10 +277,15 @@ type DereferenceError struct {
 }
 
 var _ errors.UserError = DereferenceError{}
+var _ errors.HasErrorCode = DereferenceError{}
 var _ errors.SecondaryError = DereferenceError{}
 
 func (DereferenceError) IsUserError() {}
 
+func (DereferenceError) ErrorCode() errors.ErrorCode {
+	return "R0007"
+}
+
 func (e DereferenceError) Error() string {
 	return "dereference failed"
 }
@@ -278,9 +313,14 @@ type OverflowError struct {
 }
 
 var _ errors.UserError = OverflowError{}
+var _ errors.HasErrorCode = OverflowError{}
 
 func (OverflowError) IsUserError() {}
 
+func (OverflowError) ErrorCode() errors.ErrorCode {
+	return "R0008"
+}
+
 func (e OverflowError) Error() string {
 	return "overflow"
 }
@@ -292,9 +332,14 @@ type UnderflowError struct {
 }
 
 var _ errors.UserError = UnderflowError{}
+var _ errors.HasErrorCode = UnderflowError{}
 
 func (UnderflowError) IsUserError() {}
 
+func (UnderflowError) ErrorCode() errors.ErrorCode {
+	return "R0009"
+}
+
 func (e UnderflowError) Error() string {
 	return "underflow"
 }
@@ -306,9 +351,14 @@ type DivisionByZeroError struct {
 }
 
 var _ errors.UserError = DivisionByZeroError{}
+var _ errors.HasErrorCode = DivisionByZeroError{}
 
 func (DivisionByZeroError) IsUserError() {}
 
+func (DivisionByZeroError) ErrorCode() errors.ErrorCode {
+	return "R0010"
+}
+
 func (e DivisionByZeroError) Error() string {
 	return "division by zero"
 }
@@ -333,9 +383,14 @@ type DestroyedResourceError struct {
 }
 
 var _ errors.UserError = DestroyedResourceError{}
+var _ errors.HasErrorCode = DestroyedResourceError{}
 
 func (DestroyedResourceError) IsUserError() {}
 
+func (DestroyedResourceError) ErrorCode() errors.ErrorCode {
+	return "R0011"
+}
+
 func (e DestroyedResourceError) Error() string {
 	return "resource was destroyed and cannot be used anymore"
 }
@@ -346,9 +401,14 @@ type ForceAssignmentToNonNilResourceError struct {
 }
 
 var _ errors.UserError = ForceAssignmentToNonNilResourceError{}
+var _ errors.HasErrorCode = ForceAssignmentToNonNilResourceError{}
 
 func (ForceAssignmentToNonNilResourceError) IsUserError() {}
 
+func (ForceAssignmentToNonNilResourceError) ErrorCode() errors.ErrorCode {
+	return "R0012"
+}
+
 func (e ForceAssignmentToNonNilResourceError) Error() string {
 	return "force assignment to non-nil resource-typed value"
 }
@@ -359,9 +419,14 @@ type ForceNilError struct {
 }
 
 var _ errors.UserError = ForceNilError{}
+var _ errors.HasErrorCode = ForceNilError{}
 
 func (ForceNilError) IsUserError() {}
 
+func (ForceNilError) ErrorCode() errors.ErrorCode {
+	return "R0013"
+}
+
 func (e ForceNilError) Error() string {
 	return "unexpectedly found nil while forcing an Optional value"
 }
@@ -374,9 +439,14 @@ type ForceCastTypeMismatchError struct {
 }
 
 var _ errors.UserError = ForceCastTypeMismatchError{}
+var _ errors.HasErrorCode = ForceCastTypeMismatchError{}
 
 func (ForceCastTypeMismatchError) IsUserError() {}
 
+func (ForceCastTypeMismatchError) ErrorCode() errors.ErrorCode {
+	return "R0014"
+}
+
 func (e ForceCastTypeMismatchError) Error() string {
 	expected, actual := sema.ErrorMessageExpectedActualTypes(
 		e.ExpectedType,
@@ -398,9 +468,14 @@ type TypeMismatchError struct {
 }
 
 var _ errors.UserError = TypeMismatchError{}
+var _ errors.HasErrorCode = TypeMismatchError{}
 
 func (TypeMismatchError) IsUserError() {}
 
+func (TypeMismatchError) ErrorCode() errors.ErrorCode {
+	return "R0015"
+}
+
 func (e TypeMismatchError) Error() string {
 	expected, actual := sema.ErrorMessageExpectedActualTypes(
 		e.ExpectedType,
@@ -422,10 +497,15 @@ type InvalidPathDomainError struct {
 }
 
 var _ errors.UserError = InvalidPathDomainError{}
+var _ errors.HasErrorCode = InvalidPathDomainError{}
 var _ errors.SecondaryError = InvalidPathDomainError{}
 
 func (InvalidPathDomainError) IsUserError() {}
 
+func (InvalidPathDomainError) ErrorCode() errors.ErrorCode {
+	return "R0016"
+}
+
 func (e InvalidPathDomainError) Error() string {
 	return "invalid path domain"
 }
@@ -453,9 +533,14 @@ type OverwriteError struct {
 }
 
 var _ errors.UserError = OverwriteError{}
+var _ errors.HasErrorCode = OverwriteError{}
 
 func (OverwriteError) IsUserError() {}
 
+func (OverwriteError) ErrorCode() errors.ErrorCode {
+	return "R0017"
+}
+
 func (e OverwriteError) Error() string {
 	return fmt.Sprintf(
 		"failed to save object: path %s in account %s already stores an object",
@@ -472,9 +557,14 @@ type CyclicLinkError struct {
 }
 
 var _ errors.UserError = CyclicLinkError{}
+var _ errors.HasErrorCode = CyclicLinkError{}
 
 func (CyclicLinkError) IsUserError() {}
 
+func (CyclicLinkError) ErrorCode() errors.ErrorCode {
+	return "R0018"
+}
+
 func (e CyclicLinkError) Error() string {
 	var builder strings.Builder
 	for i, path := range e.Paths {
@@ -500,9 +590,14 @@ type ArrayIndexOutOfBoundsError struct {
 }
 
 var _ errors.UserError = ArrayIndexOutOfBoundsError{}
+var _ errors.HasErrorCode = ArrayIndexOutOfBoundsError{}
 
 func (ArrayIndexOutOfBoundsError) IsUserError() {}
 
+func (ArrayIndexOutOfBoundsError) ErrorCode() errors.ErrorCode {
+	return "R0019"
+}
+
 func (e ArrayIndexOutOfBoundsError) Error() string {
 	return fmt.Sprintf(
 		"array index out of bounds: %d, but size is %d",
@@ -520,9 +615,14 @@ type ArraySliceIndicesError struct {
 }
 
 var _ errors.UserError = ArraySliceIndicesError{}
+var _ errors.HasErrorCode = ArraySliceIndicesError{}
 
 func (ArraySliceIndicesError) IsUserError() {}
 
+func (ArraySliceIndicesError) ErrorCode() errors.ErrorCode {
+	return "R0020"
+}
+
 func (e ArraySliceIndicesError) Error() string {
 	return fmt.Sprintf(
 		"slice indices [%d:%d] are out of bounds (size %d)",
@@ -539,9 +639,14 @@ type InvalidSliceIndexError struct {
 }
 
 var _ errors.UserError = InvalidSliceIndexError{}
+var _ errors.HasErrorCode = InvalidSliceIndexError{}
 
 func (InvalidSliceIndexError) IsUserError() {}
 
+func (InvalidSliceIndexError) ErrorCode() errors.ErrorCode {
+	return "R0021"
+}
+
 func (e InvalidSliceIndexError) Error() string {
 	return fmt.Sprintf("invalid slice index: %d > %d", e.FromIndex, e.UpToIndex)
 }
@@ -554,9 +659,14 @@ type StringIndexOutOfBoundsError struct {
 }
 
 var _ errors.UserError = StringIndexOutOfBoundsError{}
+var _ errors.HasErrorCode = StringIndexOutOfBoundsError{}
 
 func (StringIndexOutOfBoundsError) IsUserError() {}
 
+func (StringIndexOutOfBoundsError) ErrorCode() errors.ErrorCode {
+	return "R0022"
+}
+
 func (e StringIndexOutOfBoundsError) Error() string {
 	return fmt.Sprintf(
 		"string index out of bounds: %d, but length is %d",
@@ -574,9 +684,14 @@ type StringSliceIndicesError struct {
 }
 
 var _ errors.UserError = StringSliceIndicesError{}
+var _ errors.HasErrorCode = StringSliceIndicesError{}
 
 func (StringSliceIndicesError) IsUserError() {}
 
+func (StringSliceIndicesError) ErrorCode() errors.ErrorCode {
+	return "R0023"
+}
+
 func (e StringSliceIndicesError) Error() string {
 	return fmt.Sprintf(
 		"string slice indices [%d:%d] are out of bounds (length %d)",
@@ -590,9 +705,14 @@ type EventEmissionUnavailableError struct {
 }
 
 var _ errors.UserError = EventEmissionUnavailableError{}
+var _ errors.HasErrorCode = EventEmissionUnavailableError{}
 
 func (EventEmissionUnavailableError) IsUserError() {}
 
+func (EventEmissionUnavailableError) ErrorCode() errors.ErrorCode {
+	return "R0024"
+}
+
 func (e EventEmissionUnavailableError) Error() string {
 	return "cannot emit event: event emission is unavailable in this configuration of Cadence"
 }
@@ -603,9 +723,14 @@ type UUIDUnavailableError struct {
 }
 
 var _ errors.UserError = UUIDUnavailableError{}
+var _ errors.HasErrorCode = UUIDUnavailableError{}
 
 func (UUIDUnavailableError) IsUserError() {}
 
+func (UUIDUnavailableError) ErrorCode() errors.ErrorCode {
+	return "R0025"
+}
+
 func (e UUIDUnavailableError) Error() string {
 	return "cannot get UUID: UUID access is unavailable in this configuration of Cadence"
 }
@@ -616,9 +741,14 @@ type TypeLoadingError struct {
 }
 
 var _ errors.UserError = TypeLoadingError{}
+var _ errors.HasErrorCode = TypeLoadingError{}
 
 func (TypeLoadingError) IsUserError() {}
 
+func (TypeLoadingError) ErrorCode() errors.ErrorCode {
+	return "R0026"
+}
+
 func (e TypeLoadingError) Error() string {
 	return fmt.Sprintf("failed to load type: %s", e.TypeID)
 }
@@ -630,9 +760,14 @@ type UseBeforeInitializationError struct {
 }
 
 var _ errors.UserError = UseBeforeInitializationError{}
+var _ errors.HasErrorCode = UseBeforeInitializationError{}
 
 func (UseBeforeInitializationError) IsUserError() {}
 
+func (UseBeforeInitializationError) ErrorCode() errors.ErrorCode {
+	return "R0027"
+}
+
 func (e UseBeforeInitializationError) Error() string {
 	return fmt.Sprintf("member `%s` is used before it has been initialized", e.Name)
 }
@@ -645,9 +780,14 @@ type InvocationArgumentTypeError struct {
 }
 
 var _ errors.UserError = InvocationArgumentTypeError{}
+var _ errors.HasErrorCode = InvocationArgumentTypeError{}
 
 func (InvocationArgumentTypeError) IsUserError() {}
 
+func (InvocationArgumentTypeError) ErrorCode() errors.ErrorCode {
+	return "R0028"
+}
+
 func (e InvocationArgumentTypeError) Error() string {
 	return fmt.Sprintf(
 		"invalid invocation with argument at index %d: expected `%s`",
@@ -725,9 +865,14 @@ type ContainerMutationError struct {
 }
 
 var _ errors.UserError = ContainerMutationError{}
+var _ errors.HasErrorCode = ContainerMutationError{}
 
 func (ContainerMutationError) IsUserError() {}
 
+func (ContainerMutationError) ErrorCode() errors.ErrorCode {
+	return "R0029"
+}
+
 func (e ContainerMutationError) Error() string {
 	return fmt.Sprintf(
 		"invalid container update: expected a subtype of `%s`, found `%s`",
@@ -742,9 +887,14 @@ type NonStorableValueError struct {
 }
 
 var _ errors.UserError = NonStorableValueError{}
+var _ errors.HasErrorCode = NonStorableValueError{}
 
 func (NonStorableValueError) IsUserError() {}
 
+func (NonStorableValueError) ErrorCode() errors.ErrorCode {
+	return "R0030"
+}
+
 func (e NonStorableValueError) Error() string {
 	return "cannot store non-storable value"
 }
@@ -755,9 +905,14 @@ type NonStorableStaticTypeError struct {
 }
 
 var _ errors.UserError = NonStorableStaticTypeError{}
+var _ errors.HasErrorCode = NonStorableStaticTypeError{}
 
 func (NonStorableStaticTypeError) IsUserError() {}
 
+func (NonStorableStaticTypeError) ErrorCode() errors.ErrorCode {
+	return "R0031"
+}
+
 func (e NonStorableStaticTypeError) Error() string {
 	return fmt.Sprintf(
 		"cannot store non-storable type: `%s`",
@@ -772,9 +927,14 @@ type InterfaceMissingLocationError struct {
 }
 
 var _ errors.UserError = InterfaceMissingLocationError{}
+var _ errors.HasErrorCode = InterfaceMissingLocationError{}
 
 func (InterfaceMissingLocationError) IsUserError() {}
 
+func (InterfaceMissingLocationError) ErrorCode() errors.ErrorCode {
+	return "R0032"
+}
+
 func (e InterfaceMissingLocationError) Error() string {
 	return fmt.Sprintf(
 		"tried to look up interface %s without a location",
@@ -792,9 +952,14 @@ type InvalidOperandsError struct {
 }
 
 var _ errors.UserError = InvalidOperandsError{}
+var _ errors.HasErrorCode = InvalidOperandsError{}
 
 func (InvalidOperandsError) IsUserError() {}
 
+func (InvalidOperandsError) ErrorCode() errors.ErrorCode {
+	return "R0033"
+}
+
 func (e InvalidOperandsError) Error() string {
 	var op string
 	if e.Operation == ast.OperationUnknown {
@@ -819,9 +984,14 @@ type InvalidPublicKeyError struct {
 }
 
 var _ errors.UserError = InvalidPublicKeyError{}
+var _ errors.HasErrorCode = InvalidPublicKeyError{}
 
 func (InvalidPublicKeyError) IsUserError() {}
 
+func (InvalidPublicKeyError) ErrorCode() errors.ErrorCode {
+	return "R0034"
+}
+
 func (e InvalidPublicKeyError) Error() string {
 	return fmt.Sprintf("invalid public key: %s, err: %s", e.PublicKey, e.Err)
 }
@@ -836,9 +1006,14 @@ type NonTransferableValueError struct {
 }
 
 var _ errors.UserError = NonTransferableValueError{}
+var _ errors.HasErrorCode = NonTransferableValueError{}
 
 func (NonTransferableValueError) IsUserError() {}
 
+func (NonTransferableValueError) ErrorCode() errors.ErrorCode {
+	return "R0035"
+}
+
 func (e NonTransferableValueError) Error() string {
 	return "cannot transfer non-transferable value"
 }
@@ -849,9 +1024,14 @@ type DuplicateKeyInResourceDictionaryError struct {
 }
 
 var _ errors.UserError = DuplicateKeyInResourceDictionaryError{}
+var _ errors.HasErrorCode = DuplicateKeyInResourceDictionaryError{}
 
 func (DuplicateKeyInResourceDictionaryError) IsUserError() {}
 
+func (DuplicateKeyInResourceDictionaryError) ErrorCode() errors.ErrorCode {
+	return "R0036"
+}
+
 func (e DuplicateKeyInResourceDictionaryError) Error() string {
 	return "duplicate key in resource dictionary"
 }
@@ -862,9 +1042,14 @@ type StorageMutatedDuringIterationError struct {
 }
 
 var _ errors.UserError = StorageMutatedDuringIterationError{}
+var _ errors.HasErrorCode = StorageMutatedDuringIterationError{}
 
 func (StorageMutatedDuringIterationError) IsUserError() {}
 
+func (StorageMutatedDuringIterationError) ErrorCode() errors.ErrorCode {
+	return "R0037"
+}
+
 func (StorageMutatedDuringIterationError) Error() string {
 	return "storage iteration continued after modifying storage"
 }
@@ -875,9 +1060,14 @@ type ContainerMutatedDuringIterationError struct {
 }
 
 var _ errors.UserError = ContainerMutatedDuringIterationError{}
+var _ errors.HasErrorCode = ContainerMutatedDuringIterationError{}
 
 func (ContainerMutatedDuringIterationError) IsUserError() {}
 
+func (ContainerMutatedDuringIterationError) ErrorCode() errors.ErrorCode {
+	return "R0038"
+}
+
 func (ContainerMutatedDuringIterationError) Error() string {
 	return "resource container modified during iteration"
 }
@@ -889,9 +1079,14 @@ type InvalidHexByteError struct {
 }
 
 var _ errors.UserError = InvalidHexByteError{}
+var _ errors.HasErrorCode = InvalidHexByteError{}
 
 func (InvalidHexByteError) IsUserError() {}
 
+func (InvalidHexByteError) ErrorCode() errors.ErrorCode {
+	return "R0039"
+}
+
 func (e InvalidHexByteError) Error() string {
 	return fmt.Sprintf("invalid byte in hex string: %x", e.Byte)
 }
@@ -902,9 +1097,14 @@ type InvalidHexLengthError struct {
 }
 
 var _ errors.UserError = InvalidHexLengthError{}
+var _ errors.HasErrorCode = InvalidHexLengthError{}
 
 func (InvalidHexLengthError) IsUserError() {}
 
+func (InvalidHexLengthError) ErrorCode() errors.ErrorCode {
+	return "R0040"
+}
+
 func (InvalidHexLengthError) Error() string {
 	return "hex string has non-even length"
 }
@@ -917,9 +1117,14 @@ type DuplicateAttachmentError struct {
 }
 
 var _ errors.UserError = DuplicateAttachmentError{}
+var _ errors.HasErrorCode = DuplicateAttachmentError{}
 
 func (DuplicateAttachmentError) IsUserError() {}
 
+func (DuplicateAttachmentError) ErrorCode() errors.ErrorCode {
+	return "R0041"
+}
+
 func (e DuplicateAttachmentError) Error() string {
 	return fmt.Sprintf(
 		"cannot attach %s to %s, as it already exists on that value",
@@ -935,9 +1140,14 @@ type AttachmentIterationMutationError struct {
 }
 
 var _ errors.UserError = AttachmentIterationMutationError{}
+var _ errors.HasErrorCode = AttachmentIterationMutationError{}
 
 func (AttachmentIterationMutationError) IsUserError() {}
 
+func (AttachmentIterationMutationError) ErrorCode() errors.ErrorCode {
+	return "R0042"
+}
+
 func (e AttachmentIterationMutationError) Error() string {
 	return fmt.Sprintf(
 		"cannot modify %s's attachments while iterating over them",
@@ -970,9 +1180,14 @@ type AccountLinkingForbiddenError struct {
 }
 
 var _ errors.UserError = AccountLinkingForbiddenError{}
+var _ errors.HasErrorCode = AccountLinkingForbiddenError{}
 
 func (AccountLinkingForbiddenError) IsUserError() {}
 
+func (AccountLinkingForbiddenError) ErrorCode() errors.ErrorCode {
+	return "R0043"
+}
+
 func (e AccountLinkingForbiddenError) Error() string {
 	return "account linking is not allowed"
 }
@@ -983,9 +1198,14 @@ type RecursiveTransferError struct {
 }
 
 var _ errors.UserError = RecursiveTransferError{}
+var _ errors.HasErrorCode = RecursiveTransferError{}
 
 func (RecursiveTransferError) IsUserError() {}
 
+func (RecursiveTransferError) ErrorCode() errors.ErrorCode {
+	return "R0044"
+}
+
 func (RecursiveTransferError) Error() string {
 	return "recursive transfer of value"
 }
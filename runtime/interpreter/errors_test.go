@@ -59,6 +59,6 @@ func TestErrorOutputIncludesLocationRage(t *testing.T) {
 				},
 			},
 		}.Error(),
-		"Execution failed:\nerror: dereference failed\n --> test:0:0\n",
+		"Execution failed:\nerror[R0007]: dereference failed\n --> test:0:0\n",
 	)
 }
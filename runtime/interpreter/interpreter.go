@@ -86,6 +86,23 @@ type OnLoopIterationFunc func(
 // OnFunctionInvocationFunc is a function that is triggered when a function is about to be invoked.
 type OnFunctionInvocationFunc func(inter *Interpreter)
 
+// OnInterpretedFunctionInvocationFunc is a function that is triggered
+// when an interpreted function is about to be invoked.
+type OnInterpretedFunctionInvocationFunc func(
+	inter *Interpreter,
+	function *InterpretedFunctionValue,
+)
+
+// OnBranchFunc is a function that is triggered when a branch of a branching element,
+// e.g. of an if statement, is about to be taken.
+// See BranchKind for the branches of each kind of element.
+type OnBranchFunc func(
+	inter *Interpreter,
+	kind BranchKind,
+	element ast.Element,
+	branch int,
+)
+
 // OnInvokedFunctionReturnFunc is a function that is triggered when an invoked function returned.
 type OnInvokedFunctionReturnFunc func(inter *Interpreter)
 
@@ -739,6 +756,7 @@ func (interpreter *Interpreter) functionDeclarationValue(
 
 	return NewInterpretedFunctionValue(
 		interpreter,
+		declaration,
 		declaration.ParameterList,
 		functionType,
 		lexicalScope,
@@ -839,6 +857,14 @@ func (interpreter *Interpreter) resultValue(returnValue Value, returnType sema.T
 	return NewEphemeralReferenceValue(interpreter, false, returnValue, returnType)
 }
 
+// reportBranch reports that the given branch of the given branching element is about to be taken
+func (interpreter *Interpreter) reportBranch(kind BranchKind, element ast.Element, branch int) {
+	onBranch := interpreter.SharedState.Config.OnBranch
+	if onBranch != nil {
+		onBranch(interpreter, kind, element, branch)
+	}
+}
+
 func (interpreter *Interpreter) visitConditions(conditions []*ast.Condition) {
 	for _, condition := range conditions {
 		interpreter.visitCondition(condition)
@@ -856,9 +882,12 @@ func (interpreter *Interpreter) visitCondition(condition *ast.Condition) {
 	value, valueOk := result.Value.(BoolValue)
 
 	if ok && valueOk && bool(value) {
+		interpreter.reportBranch(BranchKindCondition, condition.Test, 0)
 		return
 	}
 
+	interpreter.reportBranch(BranchKindCondition, condition.Test, 1)
+
 	var message string
 	if condition.Message != nil {
 		messageValue := interpreter.evalExpression(condition.Message)
@@ -1512,6 +1541,7 @@ func (interpreter *Interpreter) compositeInitializerFunction(
 
 	return NewInterpretedFunctionValue(
 		interpreter,
+		initializer.FunctionDeclaration,
 		parameterList,
 		functionType,
 		lexicalScope,
@@ -1555,6 +1585,7 @@ func (interpreter *Interpreter) compositeDestructorFunction(
 
 	return NewInterpretedFunctionValue(
 		interpreter,
+		destructor.FunctionDeclaration,
 		nil,
 		emptyFunctionType,
 		lexicalScope,
@@ -1669,6 +1700,7 @@ func (interpreter *Interpreter) compositeFunction(
 
 	return NewInterpretedFunctionValue(
 		interpreter,
+		functionDeclaration,
 		parameterList,
 		functionType,
 		lexicalScope,
@@ -4901,9 +4933,9 @@ func (interpreter *Interpreter) ReportComputation(compKind common.ComputationKin
 	}
 }
 
-// getMember gets the member value by the given identifier from the given Value depending on its type.
+// GetMember gets the member value by the given identifier from the given Value depending on its type.
 // May return nil if the member does not exist.
-func (interpreter *Interpreter) getMember(self Value, locationRange LocationRange, identifier string) Value {
+func (interpreter *Interpreter) GetMember(self Value, locationRange LocationRange, identifier string) Value {
 	var result Value
 	// When the accessed value has a type that supports the declaration of members
 	// or is a built-in type that has members (`MemberAccessibleValue`),
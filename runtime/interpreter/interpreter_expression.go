@@ -180,9 +180,11 @@ func (interpreter *Interpreter) memberExpressionGetterSetter(memberExpression *a
 			if isOptional {
 				switch typedTarget := target.(type) {
 				case NilValue:
+					interpreter.reportBranch(BranchKindOptionalChaining, memberExpression, 1)
 					return typedTarget
 
 				case *SomeValue:
+					interpreter.reportBranch(BranchKindOptionalChaining, memberExpression, 0)
 					target = typedTarget.InnerValue(interpreter, locationRange)
 
 				default:
@@ -194,7 +196,7 @@ func (interpreter *Interpreter) memberExpressionGetterSetter(memberExpression *a
 			if isNestedResourceMove {
 				resultValue = target.(MemberAccessibleValue).RemoveMember(interpreter, locationRange, identifier)
 			} else {
-				resultValue = interpreter.getMember(target, locationRange, identifier)
+				resultValue = interpreter.GetMember(target, locationRange, identifier)
 			}
 			if resultValue == nil && !allowMissing {
 				panic(UseBeforeInitializationError{
@@ -443,9 +445,11 @@ func (interpreter *Interpreter) VisitBinaryExpression(expression *ast.BinaryExpr
 
 		// only evaluate right-hand side if left-hand side is nil
 		if some, ok := leftValue.(*SomeValue); ok {
+			interpreter.reportBranch(BranchKindNilCoalescing, expression, 0)
 			return some.InnerValue(interpreter, locationRange)
 		}
 
+		interpreter.reportBranch(BranchKindNilCoalescing, expression, 1)
 		value := rightValue()
 
 		binaryExpressionTypes := interpreter.Program.Elaboration.BinaryExpressionTypes(expression)
@@ -870,8 +874,10 @@ func (interpreter *Interpreter) VisitConditionalExpression(expression *ast.Condi
 		panic(errors.NewUnreachableError())
 	}
 	if value {
+		interpreter.reportBranch(BranchKindConditional, expression, 0)
 		return interpreter.evalExpression(expression.Then)
 	} else {
+		interpreter.reportBranch(BranchKindConditional, expression, 1)
 		return interpreter.evalExpression(expression.Else)
 	}
 }
@@ -1059,6 +1065,7 @@ func (interpreter *Interpreter) VisitFunctionExpression(expression *ast.Function
 
 	return NewInterpretedFunctionValue(
 		interpreter,
+		nil,
 		expression.ParameterList,
 		functionType,
 		lexicalScope,
@@ -129,14 +129,19 @@ func (interpreter *Interpreter) invokeInterpretedFunction(
 	invocation Invocation,
 ) Value {
 
+	onInterpretedFunctionInvocation := interpreter.SharedState.Config.OnInterpretedFunctionInvocation
+	if onInterpretedFunctionInvocation != nil {
+		onInterpretedFunctionInvocation(interpreter, function)
+	}
+
+	interpreter.SharedState.callStack.Push(invocation)
+
 	// Start a new activation record.
 	// Lexical scope: use the function declaration's activation record,
 	// not the current one (which would be dynamic scope)
 	current := interpreter.activations.PushNewWithParent(function.Activation)
 	current.IsFunction = true
 
-	interpreter.SharedState.callStack.Push(invocation)
-
 	// Make `self` available, if any
 	if invocation.Self != nil {
 		interpreter.declareVariable(sema.SelfIdentifier, *invocation.Self)
@@ -109,15 +109,16 @@ func (interpreter *Interpreter) VisitContinueStatement(_ *ast.ContinueStatement)
 func (interpreter *Interpreter) VisitIfStatement(statement *ast.IfStatement) StatementResult {
 	switch test := statement.Test.(type) {
 	case ast.Expression:
-		return interpreter.visitIfStatementWithTestExpression(test, statement.Then, statement.Else)
+		return interpreter.visitIfStatementWithTestExpression(statement, test, statement.Then, statement.Else)
 	case *ast.VariableDeclaration:
-		return interpreter.visitIfStatementWithVariableDeclaration(test, statement.Then, statement.Else)
+		return interpreter.visitIfStatementWithVariableDeclaration(statement, test, statement.Then, statement.Else)
 	default:
 		panic(errors.NewUnreachableError())
 	}
 }
 
 func (interpreter *Interpreter) visitIfStatementWithTestExpression(
+	statement *ast.IfStatement,
 	test ast.Expression,
 	thenBlock, elseBlock *ast.Block,
 ) StatementResult {
@@ -128,8 +129,12 @@ func (interpreter *Interpreter) visitIfStatementWithTestExpression(
 	}
 
 	if value {
+		interpreter.reportBranch(BranchKindIf, statement, 0)
 		return interpreter.visitBlock(thenBlock)
-	} else if elseBlock != nil {
+	}
+
+	interpreter.reportBranch(BranchKindIf, statement, 1)
+	if elseBlock != nil {
 		return interpreter.visitBlock(elseBlock)
 	}
 
@@ -137,6 +142,7 @@ func (interpreter *Interpreter) visitIfStatementWithTestExpression(
 }
 
 func (interpreter *Interpreter) visitIfStatementWithVariableDeclaration(
+	statement *ast.IfStatement,
 	declaration *ast.VariableDeclaration,
 	thenBlock, elseBlock *ast.Block,
 ) StatementResult {
@@ -201,8 +207,12 @@ func (interpreter *Interpreter) visitIfStatementWithVariableDeclaration(
 			transferredUnwrappedValue,
 		)
 
+		interpreter.reportBranch(BranchKindIf, statement, 0)
 		return interpreter.visitBlock(thenBlock)
-	} else if elseBlock != nil {
+	}
+
+	interpreter.reportBranch(BranchKindIf, statement, 1)
+	if elseBlock != nil {
 		return interpreter.visitBlock(elseBlock)
 	}
 
@@ -216,9 +226,11 @@ func (interpreter *Interpreter) VisitSwitchStatement(switchStatement *ast.Switch
 		panic(errors.NewUnreachableError())
 	}
 
-	for _, switchCase := range switchStatement.Cases {
+	for caseIndex, switchCase := range switchStatement.Cases {
 
 		runStatements := func() StatementResult {
+			interpreter.reportBranch(BranchKindSwitch, switchStatement, caseIndex)
+
 			// NOTE: the new block ensures that a new scope is introduced
 
 			block := ast.NewBlock(
@@ -270,6 +282,8 @@ func (interpreter *Interpreter) VisitSwitchStatement(switchStatement *ast.Switch
 		// then try the next case
 	}
 
+	interpreter.reportBranch(BranchKindSwitch, switchStatement, len(switchStatement.Cases))
+
 	return nil
 }
 
@@ -194,3 +194,37 @@ func BenchmarkValueIsSubtypeOfSemaType(b *testing.B) {
 		assert.True(b, ok)
 	}
 }
+
+func BenchmarkCallStack(b *testing.B) {
+
+	const depth = 100
+
+	run := func(b *testing.B, debugger *Debugger) {
+		inter := newTestInterpreter(b)
+		inter.SharedState.Config.Debugger = debugger
+
+		invocation := NewInvocation(inter, nil, nil, nil, nil, nil, EmptyLocationRange)
+
+		callStack := &CallStack{}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < depth; j++ {
+				callStack.Push(invocation)
+			}
+			for j := 0; j < depth; j++ {
+				callStack.Pop()
+			}
+		}
+	}
+
+	b.Run("without debugger", func(b *testing.B) {
+		run(b, nil)
+	})
+
+	b.Run("with debugger", func(b *testing.B) {
+		run(b, NewDebugger())
+	})
+}
@@ -59,14 +59,47 @@ func NewInvocation(
 // CallStack is the stack of invocations (call stack).
 type CallStack struct {
 	Invocations []Invocation
+	// callerActivations are the current activations of the invoking interpreters
+	// at the time of the invocations, i.e. the activations of the callers' frames.
+	// They are only recorded when debugging, so they may be fewer than the invocations
+	callerActivations []*VariableActivation
 }
 
+// Push pushes the given invocation onto the call stack.
+// It must be called before the invoked function's activation is pushed
 func (i *CallStack) Push(invocation Invocation) {
 	i.Invocations = append(i.Invocations, invocation)
+
+	// The activations of the callers are only needed by the debugger
+
+	interpreter := invocation.Interpreter
+	if interpreter == nil || interpreter.SharedState.Config.Debugger == nil {
+		return
+	}
+
+	depth := len(i.Invocations)
+	for len(i.callerActivations) < depth-1 {
+		i.callerActivations = append(i.callerActivations, nil)
+	}
+	i.callerActivations = append(i.callerActivations, interpreter.activations.Current())
 }
 
 func (i *CallStack) Pop() {
 	depth := len(i.Invocations)
 	i.Invocations[depth-1] = Invocation{}
 	i.Invocations = i.Invocations[:depth-1]
+
+	if len(i.callerActivations) == depth {
+		i.callerActivations[depth-1] = nil
+		i.callerActivations = i.callerActivations[:depth-1]
+	}
+}
+
+// callerActivation returns the activation of the caller of the invocation at the given index,
+// or nil if it was not recorded
+func (i *CallStack) callerActivation(index int) *VariableActivation {
+	if index >= len(i.callerActivations) {
+		return nil
+	}
+	return i.callerActivations[index]
 }
@@ -19695,7 +19695,7 @@ func (v *StorageReferenceValue) GetMember(
 ) Value {
 	self := v.mustReferencedValue(interpreter, locationRange)
 
-	return interpreter.getMember(self, locationRange, name)
+	return interpreter.GetMember(self, locationRange, name)
 }
 
 func (v *StorageReferenceValue) RemoveMember(
@@ -20010,7 +20010,7 @@ func (v *EphemeralReferenceValue) GetMember(
 ) Value {
 	self := v.MustReferencedValue(interpreter, locationRange)
 
-	return interpreter.getMember(self, locationRange, name)
+	return interpreter.GetMember(self, locationRange, name)
 }
 
 func (v *EphemeralReferenceValue) RemoveMember(
@@ -147,7 +147,7 @@ func (v *AccountReferenceValue) GetMember(
 ) Value {
 	v.checkLink(interpreter, locationRange)
 	self := v.authAccount(interpreter)
-	return interpreter.getMember(self, locationRange, name)
+	return interpreter.GetMember(self, locationRange, name)
 }
 
 func (v *AccountReferenceValue) RemoveMember(
@@ -41,7 +41,10 @@ type FunctionValue interface {
 
 // InterpretedFunctionValue
 type InterpretedFunctionValue struct {
-	Interpreter      *Interpreter
+	Interpreter *Interpreter
+	// Declaration is the declaration of the function, if any.
+	// Function expressions have no declaration
+	Declaration      *ast.FunctionDeclaration
 	ParameterList    *ast.ParameterList
 	Type             *sema.FunctionType
 	Activation       *VariableActivation
@@ -53,6 +56,7 @@ type InterpretedFunctionValue struct {
 
 func NewInterpretedFunctionValue(
 	interpreter *Interpreter,
+	declaration *ast.FunctionDeclaration,
 	parameterList *ast.ParameterList,
 	functionType *sema.FunctionType,
 	lexicalScope *VariableActivation,
@@ -66,6 +70,7 @@ func NewInterpretedFunctionValue(
 
 	return &InterpretedFunctionValue{
 		Interpreter:      interpreter,
+		Declaration:      declaration,
 		ParameterList:    parameterList,
 		Type:             functionType,
 		Activation:       lexicalScope,
@@ -48,6 +48,8 @@ type Config struct {
 	NativeModifierEnabled bool
 	// TypeParametersEnabled determines if type parameters are enabled
 	TypeParametersEnabled bool
+	// TriviaEnabled determines if comments and blank lines are preserved as trivia of the program
+	TriviaEnabled bool
 }
 
 type parser struct {
@@ -667,6 +669,10 @@ func ParseProgramFromTokenStream(
 
 	program = ast.NewProgram(memoryGauge, declarations)
 
+	if config.TriviaEnabled {
+		program.AttachTrivia(collectTrivia(input))
+	}
+
 	return
 }
 
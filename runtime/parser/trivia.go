@@ -0,0 +1,88 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"bytes"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/parser/lexer"
+)
+
+// collectTrivia returns the comments and blank lines of the given token stream, in source order.
+//
+// The trivia is collected from the tokens, instead of while parsing,
+// as the parser may replay tokens when backtracking
+func collectTrivia(tokens lexer.TokenStream) []ast.Trivia {
+	input := tokens.Input()
+
+	cursor := tokens.Cursor()
+	defer tokens.Revert(cursor)
+
+	tokens.Revert(0)
+
+	var trivia []ast.Trivia
+
+	var blockCommentStartPos ast.Position
+	var blockCommentDepth int
+
+	for {
+		token := tokens.Next()
+
+		switch token.Type {
+		case lexer.TokenEOF:
+			return trivia
+
+		case lexer.TokenLineComment:
+			trivia = append(trivia, ast.Trivia{
+				Kind:  ast.TriviaKindLineComment,
+				Text:  string(token.Source(input)),
+				Range: token.Range,
+			})
+
+		case lexer.TokenBlockCommentStart:
+			if blockCommentDepth == 0 {
+				blockCommentStartPos = token.StartPos
+			}
+			blockCommentDepth++
+
+		case lexer.TokenBlockCommentEnd:
+			blockCommentDepth--
+			if blockCommentDepth == 0 {
+				trivia = append(trivia, ast.Trivia{
+					Kind: ast.TriviaKindBlockComment,
+					Text: string(input[blockCommentStartPos.Offset : token.EndPos.Offset+1]),
+					Range: ast.NewUnmeteredRange(
+						blockCommentStartPos,
+						token.EndPos,
+					),
+				})
+			}
+
+		case lexer.TokenSpace:
+			// Whitespace containing at least two newlines contains a blank line
+			if bytes.Count(token.Source(input), []byte{'\n'}) > 1 {
+				trivia = append(trivia, ast.Trivia{
+					Kind:  ast.TriviaKindBlankLine,
+					Range: token.Range,
+				})
+			}
+		}
+	}
+}
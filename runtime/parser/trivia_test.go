@@ -0,0 +1,191 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+)
+
+func TestParseTrivia(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `// leading
+/// doc
+fun foo() {
+    let x = 1 // trailing x
+
+    /* before y /* nested */ */ let y = 2
+    // end of block
+}
+
+fun bar() {
+    // dangling
+}
+// end of file
+`
+
+	program, err := ParseProgram(nil, []byte(code), Config{TriviaEnabled: true})
+	require.NoError(t, err)
+
+	trivia := program.Trivia()
+
+	texts := make([]string, 0, len(trivia))
+	for _, item := range trivia {
+		texts = append(texts, item.Text)
+	}
+
+	assert.Equal(t,
+		[]string{
+			"// leading",
+			"/// doc",
+			"// trailing x",
+			"",
+			"/* before y /* nested */ */",
+			"// end of block",
+			"",
+			"// dangling",
+			"// end of file",
+		},
+		texts,
+	)
+
+	assert.Equal(t,
+		ast.Trivia{
+			Kind: ast.TriviaKindBlockComment,
+			Text: "/* before y /* nested */ */",
+			Range: ast.Range{
+				StartPos: ast.Position{Offset: 64, Line: 6, Column: 4},
+				EndPos:   ast.Position{Offset: 90, Line: 6, Column: 30},
+			},
+		},
+		trivia[4],
+	)
+
+	assert.False(t, trivia[0].IsDocString())
+	assert.True(t, trivia[1].IsDocString())
+
+	declarations := program.Declarations()
+	require.Len(t, declarations, 2)
+
+	foo := declarations[0].(*ast.FunctionDeclaration)
+	bar := declarations[1].(*ast.FunctionDeclaration)
+
+	assert.Equal(t,
+		ast.ElementTrivia{
+			Leading: []ast.Trivia{trivia[0], trivia[1]},
+		},
+		program.ElementTrivia(foo),
+	)
+
+	statements := foo.FunctionBlock.Block.Statements
+	require.Len(t, statements, 2)
+
+	assert.Equal(t,
+		ast.ElementTrivia{
+			Trailing: []ast.Trivia{trivia[2]},
+		},
+		program.ElementTrivia(statements[0]),
+	)
+
+	assert.Equal(t,
+		ast.ElementTrivia{
+			Leading:  []ast.Trivia{trivia[3], trivia[4]},
+			Trailing: []ast.Trivia{trivia[5]},
+		},
+		program.ElementTrivia(statements[1]),
+	)
+
+	assert.Equal(t,
+		ast.ElementTrivia{
+			Leading:  []ast.Trivia{trivia[6]},
+			Trailing: []ast.Trivia{trivia[8]},
+		},
+		program.ElementTrivia(bar),
+	)
+
+	assert.Equal(t,
+		ast.ElementTrivia{
+			Dangling: []ast.Trivia{trivia[7]},
+		},
+		program.ElementTrivia(bar.FunctionBlock.Block),
+	)
+}
+
+func TestParseTriviaInExpression(t *testing.T) {
+
+	t.Parallel()
+
+	// The invocation with type arguments requires backtracking,
+	// the comments must only be collected once
+
+	const code = `
+      let x = foo</* type */ Int>(/* first */ 1, 2 /* second */)
+    `
+
+	program, err := ParseProgram(nil, []byte(code), Config{TriviaEnabled: true})
+	require.NoError(t, err)
+
+	trivia := program.Trivia()
+	require.Len(t, trivia, 3)
+
+	declaration := program.Declarations()[0].(*ast.VariableDeclaration)
+	invocation := declaration.Value.(*ast.InvocationExpression)
+	require.Len(t, invocation.Arguments, 2)
+
+	// The comment in the type arguments is attached to the following argument
+
+	assert.Equal(t,
+		ast.ElementTrivia{
+			Leading: []ast.Trivia{trivia[0], trivia[1]},
+		},
+		program.ElementTrivia(invocation.Arguments[0].Expression),
+	)
+
+	assert.Equal(t,
+		ast.ElementTrivia{
+			Trailing: []ast.Trivia{trivia[2]},
+		},
+		program.ElementTrivia(invocation.Arguments[1].Expression),
+	)
+}
+
+func TestParseTriviaDisabled(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      // comment
+      fun foo() {}
+    `
+
+	program, err := ParseProgram(nil, []byte(code), Config{})
+	require.NoError(t, err)
+
+	assert.Empty(t, program.Trivia())
+	assert.Equal(t,
+		ast.ElementTrivia{},
+		program.ElementTrivia(program.Declarations()[0]),
+	)
+}
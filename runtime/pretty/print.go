@@ -218,6 +218,11 @@ func (p ErrorPrettyPrinter) prettyPrintError(err error, location common.Location
 		prefix = secondaryError.Prefix()
 	}
 
+	// Show the error code, if any, e.g. `error[C0123]`
+	if hasErrorCode, ok := err.(errors.HasErrorCode); ok {
+		prefix = fmt.Sprintf("%s[%s]", prefix, hasErrorCode.ErrorCode())
+	}
+
 	p.writeString(FormatErrorMessage(prefix, err.Error(), p.useColor))
 
 	message := ""
@@ -0,0 +1,312 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package profiler
+
+import (
+	"compress/gzip"
+	"io"
+
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// Field numbers of the messages of the pprof profile format,
+// see https://github.com/google/pprof/blob/main/proto/profile.proto
+const (
+	profileSampleType        = 1
+	profileSample            = 2
+	profileMapping           = 3
+	profileLocation          = 4
+	profileFunction          = 5
+	profileStringTable       = 6
+	profileDefaultSampleType = 14
+
+	valueTypeType = 1
+	valueTypeUnit = 2
+
+	sampleLocationID = 1
+	sampleValue      = 2
+	sampleLabel      = 3
+
+	labelKey = 1
+	labelStr = 2
+
+	mappingID           = 1
+	mappingFilename     = 5
+	mappingHasFunctions = 7
+
+	locationID        = 1
+	locationMappingID = 2
+	locationLine      = 4
+
+	lineFunctionID = 1
+	lineLine       = 2
+
+	functionID         = 1
+	functionName       = 2
+	functionSystemName = 3
+	functionFilename   = 4
+)
+
+const (
+	computationSampleType = "computation"
+	computationUnit       = "intensity"
+	memorySampleType      = "memory"
+	memoryUnit            = "amount"
+
+	computationKindLabel = "computation_kind"
+	memoryKindLabel      = "memory_kind"
+
+	// mappingName is the name of the single mapping of the profile,
+	// which contains all functions
+	mappingName = "cadence"
+)
+
+// protobufBuffer is a minimal encoder of protocol buffer messages
+type protobufBuffer struct {
+	data []byte
+}
+
+func (b *protobufBuffer) varint(value uint64) {
+	for value >= 0x80 {
+		b.data = append(b.data, byte(value)|0x80)
+		value >>= 7
+	}
+	b.data = append(b.data, byte(value))
+}
+
+func (b *protobufBuffer) key(field int, wireType int) {
+	b.varint(uint64(field)<<3 | uint64(wireType))
+}
+
+func (b *protobufBuffer) uint64Field(field int, value uint64) {
+	if value == 0 {
+		return
+	}
+	b.key(field, 0)
+	b.varint(value)
+}
+
+func (b *protobufBuffer) int64Field(field int, value int64) {
+	b.uint64Field(field, uint64(value))
+}
+
+func (b *protobufBuffer) boolField(field int, value bool) {
+	if value {
+		b.uint64Field(field, 1)
+	}
+}
+
+func (b *protobufBuffer) bytesField(field int, value []byte) {
+	b.key(field, 2)
+	b.varint(uint64(len(value)))
+	b.data = append(b.data, value...)
+}
+
+func (b *protobufBuffer) packedUint64Field(field int, values []uint64) {
+	var packed protobufBuffer
+	for _, value := range values {
+		packed.varint(value)
+	}
+	b.bytesField(field, packed.data)
+}
+
+func (b *protobufBuffer) packedInt64Field(field int, values []int64) {
+	var packed protobufBuffer
+	for _, value := range values {
+		packed.varint(uint64(value))
+	}
+	b.bytesField(field, packed.data)
+}
+
+func (b *protobufBuffer) messageField(field int, encode func(message *protobufBuffer)) {
+	var message protobufBuffer
+	encode(&message)
+	b.bytesField(field, message.data)
+}
+
+type pprofLocationKey struct {
+	functionID uint64
+	line       int
+}
+
+type pprofFunctionKey struct {
+	name     string
+	filename string
+}
+
+// pprofEncoder encodes samples as a pprof profile
+type pprofEncoder struct {
+	profile     protobufBuffer
+	strings     map[string]int64
+	stringTable []string
+	functions   map[pprofFunctionKey]uint64
+	locations   map[pprofLocationKey]uint64
+}
+
+func newPprofEncoder() *pprofEncoder {
+	encoder := &pprofEncoder{
+		strings:   map[string]int64{},
+		functions: map[pprofFunctionKey]uint64{},
+		locations: map[pprofLocationKey]uint64{},
+	}
+	// The first string of the string table must be the empty string
+	encoder.string("")
+	return encoder
+}
+
+// string returns the index of the given string in the string table
+func (e *pprofEncoder) string(s string) int64 {
+	index, ok := e.strings[s]
+	if !ok {
+		index = int64(len(e.stringTable))
+		e.strings[s] = index
+		e.stringTable = append(e.stringTable, s)
+	}
+	return index
+}
+
+func (e *pprofEncoder) function(name string, filename string) uint64 {
+	key := pprofFunctionKey{
+		name:     name,
+		filename: filename,
+	}
+
+	id, ok := e.functions[key]
+	if !ok {
+		id = uint64(len(e.functions) + 1)
+		e.functions[key] = id
+
+		e.profile.messageField(profileFunction, func(function *protobufBuffer) {
+			function.uint64Field(functionID, id)
+			nameIndex := e.string(name)
+			function.int64Field(functionName, nameIndex)
+			function.int64Field(functionSystemName, nameIndex)
+			function.int64Field(functionFilename, e.string(filename))
+		})
+	}
+
+	return id
+}
+
+func (e *pprofEncoder) location(frame Frame) uint64 {
+	var filename string
+	if frame.Location != nil {
+		filename = frame.Location.String()
+	}
+
+	key := pprofLocationKey{
+		functionID: e.function(frame.Function, filename),
+		line:       frame.Line,
+	}
+
+	id, ok := e.locations[key]
+	if !ok {
+		id = uint64(len(e.locations) + 1)
+		e.locations[key] = id
+
+		e.profile.messageField(profileLocation, func(location *protobufBuffer) {
+			location.uint64Field(locationID, id)
+			location.uint64Field(locationMappingID, 1)
+			location.messageField(locationLine, func(line *protobufBuffer) {
+				line.uint64Field(lineFunctionID, key.functionID)
+				line.int64Field(lineLine, int64(key.line))
+			})
+		})
+	}
+
+	return id
+}
+
+func (e *pprofEncoder) sampleType(sampleType string, unit string) {
+	e.profile.messageField(profileSampleType, func(valueType *protobufBuffer) {
+		valueType.int64Field(valueTypeType, e.string(sampleType))
+		valueType.int64Field(valueTypeUnit, e.string(unit))
+	})
+}
+
+func (e *pprofEncoder) sample(sample Sample) {
+	locationIDs := make([]uint64, 0, len(sample.Stack))
+	for _, frame := range sample.Stack {
+		locationIDs = append(locationIDs, e.location(frame))
+	}
+
+	var label, kind string
+	if sample.MemoryKind != common.MemoryKindUnknown {
+		label = memoryKindLabel
+		kind = sample.MemoryKind.String()
+	} else {
+		label = computationKindLabel
+		kind = sample.ComputationKind.String()
+	}
+
+	e.profile.messageField(profileSample, func(message *protobufBuffer) {
+		message.packedUint64Field(sampleLocationID, locationIDs)
+		message.packedInt64Field(
+			sampleValue,
+			[]int64{
+				int64(sample.Computation),
+				int64(sample.Memory),
+			},
+		)
+		message.messageField(sampleLabel, func(message *protobufBuffer) {
+			message.int64Field(labelKey, e.string(label))
+			message.int64Field(labelStr, e.string(kind))
+		})
+	})
+}
+
+func (e *pprofEncoder) encode(samples []Sample) []byte {
+	e.sampleType(computationSampleType, computationUnit)
+	e.sampleType(memorySampleType, memoryUnit)
+
+	for _, sample := range samples {
+		e.sample(sample)
+	}
+
+	e.profile.messageField(profileMapping, func(mapping *protobufBuffer) {
+		mapping.uint64Field(mappingID, 1)
+		mapping.int64Field(mappingFilename, e.string(mappingName))
+		mapping.boolField(mappingHasFunctions, true)
+	})
+
+	e.profile.int64Field(profileDefaultSampleType, e.string(computationSampleType))
+
+	for _, s := range e.stringTable {
+		e.profile.bytesField(profileStringTable, []byte(s))
+	}
+
+	return e.profile.data
+}
+
+// WriteProfile writes the samples as a gzip-compressed pprof profile,
+// which can be opened with `go tool pprof` and flame graph viewers.
+//
+// The profile has two sample types, the computation intensity and the memory amount.
+// Each sample is labeled with its computation kind or memory kind,
+// e.g. `computation_kind=Statement` or `memory_kind=StringValue`
+func (p *Profiler) WriteProfile(w io.Writer) error {
+	data := newPprofEncoder().encode(p.Samples())
+
+	writer := gzip.NewWriter(w)
+	_, err := writer.Write(data)
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
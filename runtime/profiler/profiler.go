@@ -0,0 +1,339 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package profiler
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// Frame is a frame of a Cadence call stack
+type Frame struct {
+	Location common.Location
+	// Function is the qualified name of the function, e.g. `Vault.withdraw`
+	Function string
+	Line     int
+}
+
+// Sample is the computation or memory usage attributed to a call stack.
+//
+// A computation sample has a computation kind and the summed up intensity,
+// a memory sample has a memory kind and the summed up amount
+type Sample struct {
+	// Stack are the frames of the call stack, innermost first
+	Stack           []Frame
+	ComputationKind common.ComputationKind
+	Computation     uint64
+	MemoryKind      common.MemoryKind
+	Memory          uint64
+}
+
+type sampleKey struct {
+	stack           string
+	computationKind common.ComputationKind
+	memoryKind      common.MemoryKind
+}
+
+type functionKey struct {
+	location common.Location
+	position ast.Position
+}
+
+// frame is a frame of the current call stack.
+// Invoked host functions have no statements, so their frames stay unresolved,
+// and their usage is attributed to the call site in the invoking function
+type frame struct {
+	resolved bool
+	Frame
+}
+
+// Profiler attributes computation intensity and memory usage to Cadence call stacks.
+//
+// The profiler is installed into an interpreter configuration,
+// and tracks the call stack using the invocation, return, statement and loop iteration handlers.
+// The result can be written as a pprof profile, see WriteProfile
+type Profiler struct {
+	memoryGauge   common.MemoryGauge
+	frames        []frame
+	samples       map[sampleKey]*Sample
+	functionNames map[functionKey]string
+	// stackKey is the key of the current call stack,
+	// or the empty string if the call stack changed
+	stackKey string
+	// pendingStatementIntensity is the intensity metered for a statement
+	// before it is reported, so it can be attributed to the statement's line
+	pendingStatementIntensity uint
+	// pendingLoopIntensity is the intensity metered for a loop iteration
+	// before it is reported, so it can be attributed to the loop's line
+	pendingLoopIntensity uint
+}
+
+var _ common.MemoryGauge = &Profiler{}
+
+// NewProfiler returns a new profiler without any samples
+func NewProfiler() *Profiler {
+	return &Profiler{
+		samples:       map[sampleKey]*Sample{},
+		functionNames: map[functionKey]string{},
+	}
+}
+
+// Install installs the profiler into the given interpreter configuration.
+//
+// Existing handlers and the existing memory gauge are still called.
+// Only the memory usage metered by the interpreter is profiled,
+// the usage of parsing and checking is not
+func (p *Profiler) Install(config *interpreter.Config) {
+	p.memoryGauge = config.MemoryGauge
+	config.MemoryGauge = p
+
+	onStatement := config.OnStatement
+	config.OnStatement = func(inter *interpreter.Interpreter, statement ast.Statement) {
+		p.onStatement(inter, statement)
+		if onStatement != nil {
+			onStatement(inter, statement)
+		}
+	}
+
+	onLoopIteration := config.OnLoopIteration
+	config.OnLoopIteration = func(inter *interpreter.Interpreter, line int) {
+		p.onLoopIteration(line)
+		if onLoopIteration != nil {
+			onLoopIteration(inter, line)
+		}
+	}
+
+	onMeterComputation := config.OnMeterComputation
+	config.OnMeterComputation = func(compKind common.ComputationKind, intensity uint) {
+		p.onMeterComputation(compKind, intensity)
+		if onMeterComputation != nil {
+			onMeterComputation(compKind, intensity)
+		}
+	}
+
+	onFunctionInvocation := config.OnFunctionInvocation
+	config.OnFunctionInvocation = func(inter *interpreter.Interpreter) {
+		p.onFunctionInvocation()
+		if onFunctionInvocation != nil {
+			onFunctionInvocation(inter)
+		}
+	}
+
+	onInvokedFunctionReturn := config.OnInvokedFunctionReturn
+	config.OnInvokedFunctionReturn = func(inter *interpreter.Interpreter) {
+		p.onInvokedFunctionReturn()
+		if onInvokedFunctionReturn != nil {
+			onInvokedFunctionReturn(inter)
+		}
+	}
+}
+
+func (p *Profiler) onStatement(inter *interpreter.Interpreter, statement ast.Statement) {
+	// Statements of the program or of functions invoked by the host environment
+	// are executed without a reported invocation
+	if len(p.frames) == 0 {
+		p.frames = append(p.frames, frame{})
+	}
+
+	position := statement.StartPosition()
+
+	top := &p.frames[len(p.frames)-1]
+	top.resolved = true
+	top.Location = inter.Location
+	top.Function = p.functionName(inter, position)
+	top.Line = position.Line
+	p.stackKey = ""
+
+	if p.pendingStatementIntensity > 0 {
+		intensity := p.pendingStatementIntensity
+		p.pendingStatementIntensity = 0
+		p.addComputation(common.ComputationKindStatement, intensity)
+	}
+}
+
+func (p *Profiler) onLoopIteration(line int) {
+	if len(p.frames) > 0 {
+		top := &p.frames[len(p.frames)-1]
+		top.Line = line
+		p.stackKey = ""
+	}
+
+	if p.pendingLoopIntensity > 0 {
+		intensity := p.pendingLoopIntensity
+		p.pendingLoopIntensity = 0
+		p.addComputation(common.ComputationKindLoop, intensity)
+	}
+}
+
+// functionName returns the name of the function containing the given position
+// in the program of the given interpreter
+func (p *Profiler) functionName(inter *interpreter.Interpreter, position ast.Position) string {
+	key := functionKey{
+		location: inter.Location,
+		position: position,
+	}
+
+	name, ok := p.functionNames[key]
+	if !ok {
+		name = interpreter.Frame{
+			Interpreter: inter,
+			Location:    inter.Location,
+			Position:    position,
+		}.FunctionName()
+		p.functionNames[key] = name
+	}
+
+	return name
+}
+
+func (p *Profiler) onMeterComputation(compKind common.ComputationKind, intensity uint) {
+	// The computation of statements and loop iterations
+	// is metered before they are reported
+	switch compKind {
+	case common.ComputationKindStatement:
+		p.pendingStatementIntensity += intensity
+		return
+	case common.ComputationKindLoop:
+		p.pendingLoopIntensity += intensity
+		return
+	}
+
+	p.addComputation(compKind, intensity)
+}
+
+func (p *Profiler) onFunctionInvocation() {
+	p.frames = append(p.frames, frame{})
+	p.stackKey = ""
+}
+
+func (p *Profiler) onInvokedFunctionReturn() {
+	if len(p.frames) == 0 {
+		return
+	}
+	p.frames = p.frames[:len(p.frames)-1]
+	p.stackKey = ""
+}
+
+// MeterMemory attributes the given memory usage to the current call stack,
+// and meters it with the memory gauge of the interpreter configuration, if any
+func (p *Profiler) MeterMemory(usage common.MemoryUsage) error {
+	p.sample(sampleKey{memoryKind: usage.Kind}).Memory += usage.Amount
+
+	if p.memoryGauge != nil {
+		return p.memoryGauge.MeterMemory(usage)
+	}
+	return nil
+}
+
+func (p *Profiler) addComputation(compKind common.ComputationKind, intensity uint) {
+	p.sample(sampleKey{computationKind: compKind}).Computation += uint64(intensity)
+}
+
+// sample returns the sample for the current call stack and the kind of the given key
+func (p *Profiler) sample(key sampleKey) *Sample {
+	key.stack = p.currentStackKey()
+
+	sample, ok := p.samples[key]
+	if !ok {
+		sample = &Sample{
+			Stack:           p.currentStack(),
+			ComputationKind: key.computationKind,
+			MemoryKind:      key.memoryKind,
+		}
+		p.samples[key] = sample
+	}
+
+	return sample
+}
+
+// currentStack returns the resolved frames of the current call stack, innermost first
+func (p *Profiler) currentStack() []Frame {
+	stack := make([]Frame, 0, len(p.frames))
+	for i := len(p.frames) - 1; i >= 0; i-- {
+		frame := p.frames[i]
+		if !frame.resolved {
+			continue
+		}
+		stack = append(stack, frame.Frame)
+	}
+	return stack
+}
+
+func (p *Profiler) currentStackKey() string {
+	if p.stackKey != "" || len(p.frames) == 0 {
+		return p.stackKey
+	}
+
+	var builder strings.Builder
+	for _, frame := range p.frames {
+		if !frame.resolved {
+			continue
+		}
+		if frame.Location != nil {
+			builder.WriteString(frame.Location.ID())
+		}
+		builder.WriteByte(0)
+		builder.WriteString(frame.Function)
+		builder.WriteByte(0)
+		builder.WriteString(strconv.Itoa(frame.Line))
+		builder.WriteByte(0)
+	}
+	p.stackKey = builder.String()
+	return p.stackKey
+}
+
+// Samples returns the samples, sorted by call stack, innermost frame last,
+// and then by computation and memory kind
+func (p *Profiler) Samples() []Sample {
+	keys := make([]sampleKey, 0, len(p.samples))
+	for key := range p.samples { //nolint:maprange
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.stack != b.stack {
+			return a.stack < b.stack
+		}
+		if a.computationKind != b.computationKind {
+			return a.computationKind < b.computationKind
+		}
+		return a.memoryKind < b.memoryKind
+	})
+
+	samples := make([]Sample, 0, len(keys))
+	for _, key := range keys {
+		samples = append(samples, *p.samples[key])
+	}
+	return samples
+}
+
+// Reset removes all samples, and resets the call stack
+func (p *Profiler) Reset() {
+	p.frames = nil
+	p.samples = map[sampleKey]*Sample{}
+	p.stackKey = ""
+	p.pendingStatementIntensity = 0
+	p.pendingLoopIntensity = 0
+}
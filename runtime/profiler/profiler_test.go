@@ -0,0 +1,396 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package profiler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/parser"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+const testLocation = common.StringLocation("test")
+
+func prepareInterpreter(t *testing.T, code string, config *interpreter.Config) *interpreter.Interpreter {
+	program, err := parser.ParseProgram(nil, []byte(code), parser.Config{})
+	require.NoError(t, err)
+
+	checker, err := sema.NewChecker(
+		program,
+		testLocation,
+		nil,
+		&sema.Config{
+			AccessCheckMode: sema.AccessCheckModeNotSpecifiedUnrestricted,
+		},
+	)
+	require.NoError(t, err)
+
+	err = checker.Check()
+	require.NoError(t, err)
+
+	if config.Storage == nil {
+		config.Storage = interpreter.NewInMemoryStorage(nil)
+	}
+
+	inter, err := interpreter.NewInterpreter(
+		interpreter.ProgramFromChecker(checker),
+		testLocation,
+		config,
+	)
+	require.NoError(t, err)
+
+	err = inter.Interpret()
+	require.NoError(t, err)
+
+	return inter
+}
+
+const testCode = `
+  fun double(_ n: Int): Int {
+      return n * 2
+  }
+
+  fun main(): Int {
+      var sum = 0
+      var i = 0
+      while i < 3 {
+          sum = sum + double(i)
+          i = i + 1
+      }
+      return sum
+  }
+`
+
+func TestProfilerSamples(t *testing.T) {
+
+	t.Parallel()
+
+	profiler := NewProfiler()
+
+	config := &interpreter.Config{}
+	profiler.Install(config)
+
+	inter := prepareInterpreter(t, testCode, config)
+
+	result, err := inter.Invoke("main")
+	require.NoError(t, err)
+	assert.Equal(t, interpreter.NewUnmeteredIntValueFromInt64(6), result)
+
+	mainFrame := func(line int) Frame {
+		return Frame{
+			Location: testLocation,
+			Function: "main",
+			Line:     line,
+		}
+	}
+
+	doubleFrame := Frame{
+		Location: testLocation,
+		Function: "double",
+		Line:     3,
+	}
+
+	computation := map[common.ComputationKind]map[string]uint64{}
+	memory := map[string]uint64{}
+
+	stackName := func(stack []Frame) string {
+		var name string
+		for i := len(stack) - 1; i >= 0; i-- {
+			frame := stack[i]
+			name += fmt.Sprintf("/%s:%d", frame.Function, frame.Line)
+		}
+		return name
+	}
+
+	for _, sample := range profiler.Samples() {
+		name := stackName(sample.Stack)
+		if sample.MemoryKind != common.MemoryKindUnknown {
+			assert.Zero(t, sample.Computation)
+			memory[name] += sample.Memory
+			continue
+		}
+
+		assert.Zero(t, sample.Memory)
+		stacks, ok := computation[sample.ComputationKind]
+		if !ok {
+			stacks = map[string]uint64{}
+			computation[sample.ComputationKind] = stacks
+		}
+		stacks[name] += sample.Computation
+	}
+
+	// Statements are attributed to their own line
+	assert.Equal(
+		t,
+		map[string]uint64{
+			stackName([]Frame{mainFrame(7)}):               1,
+			stackName([]Frame{mainFrame(8)}):               1,
+			stackName([]Frame{mainFrame(9)}):               1,
+			stackName([]Frame{mainFrame(10)}):              3,
+			stackName([]Frame{doubleFrame, mainFrame(10)}): 3,
+			stackName([]Frame{mainFrame(11)}):              3,
+			stackName([]Frame{mainFrame(13)}):              1,
+		},
+		computation[common.ComputationKindStatement],
+	)
+
+	// Invocations are attributed to the call site
+	assert.Equal(
+		t,
+		map[string]uint64{
+			stackName([]Frame{mainFrame(10)}): 3,
+		},
+		computation[common.ComputationKindFunctionInvocation],
+	)
+
+	// Loop iterations are attributed to the loop
+	assert.Equal(
+		t,
+		map[string]uint64{
+			stackName([]Frame{mainFrame(9)}): 3,
+		},
+		computation[common.ComputationKindLoop],
+	)
+
+	// The memory used by the invoked function is attributed to it
+	assert.NotZero(t, memory[stackName([]Frame{doubleFrame, mainFrame(10)})])
+}
+
+func TestProfilerInstallKeepsHandlers(t *testing.T) {
+
+	t.Parallel()
+
+	var statements, computations, invocations, returns int
+	var memoryUsages int
+
+	config := &interpreter.Config{
+		MemoryGauge: testMemoryGauge(func(_ common.MemoryUsage) {
+			memoryUsages++
+		}),
+		OnStatement: func(_ *interpreter.Interpreter, _ ast.Statement) {
+			statements++
+		},
+		OnMeterComputation: func(_ common.ComputationKind, _ uint) {
+			computations++
+		},
+		OnFunctionInvocation: func(_ *interpreter.Interpreter) {
+			invocations++
+		},
+		OnInvokedFunctionReturn: func(_ *interpreter.Interpreter) {
+			returns++
+		},
+	}
+
+	NewProfiler().Install(config)
+
+	inter := prepareInterpreter(t, testCode, config)
+
+	_, err := inter.Invoke("main")
+	require.NoError(t, err)
+
+	assert.Equal(t, 13, statements)
+	assert.NotZero(t, computations)
+	assert.Equal(t, 3, invocations)
+	assert.Equal(t, 3, returns)
+	assert.NotZero(t, memoryUsages)
+}
+
+type testMemoryGauge func(usage common.MemoryUsage)
+
+func (g testMemoryGauge) MeterMemory(usage common.MemoryUsage) error {
+	g(usage)
+	return nil
+}
+
+// readVarint reads a varint from the given data, and advances it
+func readVarint(t *testing.T, data *[]byte) uint64 {
+	var value uint64
+	for shift := 0; ; shift += 7 {
+		require.NotEmpty(t, *data)
+		b := (*data)[0]
+		*data = (*data)[1:]
+		value |= uint64(b&0x7f) << shift
+		if b < 0x80 {
+			return value
+		}
+	}
+}
+
+// decodeProtobuf decodes the fields of the given protocol buffer message,
+// and returns the values of varint fields and length-delimited fields by field number
+func decodeProtobuf(t *testing.T, data []byte) (map[int][]uint64, map[int][][]byte) {
+	varints := map[int][]uint64{}
+	messages := map[int][][]byte{}
+
+	for len(data) > 0 {
+		key := readVarint(t, &data)
+		field := int(key >> 3)
+		switch key & 7 {
+		case 0:
+			varints[field] = append(varints[field], readVarint(t, &data))
+		case 2:
+			length := readVarint(t, &data)
+			require.GreaterOrEqual(t, uint64(len(data)), length)
+			messages[field] = append(messages[field], data[:length])
+			data = data[length:]
+		default:
+			require.Fail(t, "unexpected wire type")
+		}
+	}
+
+	return varints, messages
+}
+
+// decodePackedVarints decodes the given packed repeated varint field
+func decodePackedVarints(t *testing.T, data []byte) []uint64 {
+	var values []uint64
+	for len(data) > 0 {
+		values = append(values, readVarint(t, &data))
+	}
+	return values
+}
+
+func TestProfilerWriteProfile(t *testing.T) {
+
+	t.Parallel()
+
+	profiler := NewProfiler()
+
+	config := &interpreter.Config{}
+	profiler.Install(config)
+
+	inter := prepareInterpreter(t, testCode, config)
+
+	_, err := inter.Invoke("main")
+	require.NoError(t, err)
+
+	var buffer bytes.Buffer
+	err = profiler.WriteProfile(&buffer)
+	require.NoError(t, err)
+
+	reader, err := gzip.NewReader(&buffer)
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	varints, messages := decodeProtobuf(t, data)
+
+	var stringTable []string
+	for _, s := range messages[profileStringTable] {
+		stringTable = append(stringTable, string(s))
+	}
+	require.NotEmpty(t, stringTable)
+	assert.Equal(t, "", stringTable[0])
+
+	// Sample types
+
+	sampleTypes := make([]string, 0, 2)
+	for _, message := range messages[profileSampleType] {
+		fields, _ := decodeProtobuf(t, message)
+		sampleTypes = append(
+			sampleTypes,
+			stringTable[fields[valueTypeType][0]]+"/"+stringTable[fields[valueTypeUnit][0]],
+		)
+	}
+	assert.Equal(t, []string{"computation/intensity", "memory/amount"}, sampleTypes)
+
+	assert.Equal(
+		t,
+		"computation",
+		stringTable[varints[profileDefaultSampleType][0]],
+	)
+
+	// Functions
+
+	functionNames := map[uint64]string{}
+	for _, message := range messages[profileFunction] {
+		fields, _ := decodeProtobuf(t, message)
+		assert.Equal(t, "test", stringTable[fields[functionFilename][0]])
+		functionNames[fields[functionID][0]] = stringTable[fields[functionName][0]]
+	}
+	assert.ElementsMatch(t, []string{"main", "double"}, mapValues(functionNames))
+
+	// Locations
+
+	locationLines := map[uint64]string{}
+	for _, message := range messages[profileLocation] {
+		fields, lines := decodeProtobuf(t, message)
+		assert.Equal(t, []uint64{1}, fields[locationMappingID])
+		require.Len(t, lines[locationLine], 1)
+
+		lineFields, _ := decodeProtobuf(t, lines[locationLine][0])
+		locationLines[fields[locationID][0]] = fmt.Sprintf(
+			"%s:%d",
+			functionNames[lineFields[lineFunctionID][0]],
+			lineFields[lineLine][0],
+		)
+	}
+
+	// Samples
+
+	require.Len(t, messages[profileSample], len(profiler.Samples()))
+
+	var invocationStacks [][]string
+	for _, message := range messages[profileSample] {
+		_, fields := decodeProtobuf(t, message)
+
+		require.Len(t, fields[sampleLabel], 1)
+		labelFields, _ := decodeProtobuf(t, fields[sampleLabel][0])
+		label := stringTable[labelFields[labelKey][0]] + "=" +
+			stringTable[labelFields[labelStr][0]]
+
+		if label != "computation_kind=FunctionInvocation" {
+			continue
+		}
+
+		var stack []string
+		for _, id := range decodePackedVarints(t, fields[sampleLocationID][0]) {
+			stack = append(stack, locationLines[id])
+		}
+		invocationStacks = append(invocationStacks, stack)
+	}
+
+	assert.Equal(t, [][]string{{"main:10"}}, invocationStacks)
+
+	// Mapping
+
+	require.Len(t, messages[profileMapping], 1)
+	mappingFields, _ := decodeProtobuf(t, messages[profileMapping][0])
+	assert.Equal(t, "cadence", stringTable[mappingFields[mappingFilename][0]])
+	assert.Equal(t, []uint64{1}, mappingFields[mappingHasFunctions])
+}
+
+func mapValues(m map[uint64]string) []string {
+	values := make([]string, 0, len(m))
+	for _, value := range m { //nolint:maprange
+		values = append(values, value)
+	}
+	return values
+}
@@ -20,9 +20,12 @@ package runtime
 
 import (
 	"bytes"
+	goErrors "errors"
 	"fmt"
+	"io"
 	goRuntime "runtime"
 	"sort"
+	"strings"
 
 	"github.com/onflow/cadence"
 	"github.com/onflow/cadence/runtime/activations"
@@ -40,14 +43,41 @@ import (
 type REPL struct {
 	checker          *sema.Checker
 	inter            *interpreter.Interpreter
+	debugger         *interpreter.Debugger
 	OnError          func(err error, location Location, codes map[Location][]byte)
 	OnExpressionType func(sema.Type)
 	OnResult         func(interpreter.Value)
 	codes            map[Location][]byte
 	parserConfig     parser.Config
+	// declarations are the declarations of the session
+	declarations []ast.Declaration
+	// inputs are the inputs of the session which were accepted and evaluated successfully
+	inputs []string
+	// replaying is true while inputs are replayed, which suppresses results and logs
+	replaying bool
 }
 
 func NewREPL() (*REPL, error) {
+	return NewREPLWithDebugger(nil)
+}
+
+// NewREPLWithDebugger returns a new REPL which evaluates code with the given debugger.
+// The debugger's stops must be handled while code is accepted
+func NewREPLWithDebugger(debugger *interpreter.Debugger) (*REPL, error) {
+	repl := &REPL{
+		debugger: debugger,
+	}
+
+	err := repl.Reset()
+	if err != nil {
+		return nil, err
+	}
+
+	return repl, nil
+}
+
+// Reset discards all declarations, values, and inputs of the session
+func (r *REPL) Reset() error {
 
 	checkers := map[Location]*sema.Checker{}
 	codes := map[Location][]byte{}
@@ -62,7 +92,7 @@ func NewREPL() (*REPL, error) {
 		checkerConfig,
 	)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	var uuid uint64
@@ -72,7 +102,7 @@ func NewREPL() (*REPL, error) {
 	// necessary now due to log being looked up in the
 	// interpreter's activations instead of the checker
 	baseActivation := activations.NewActivation(nil, interpreter.BaseActivation)
-	interpreter.Declare(baseActivation, stdlib.NewLogFunction(cmd.StandardOutputLogger{}))
+	interpreter.Declare(baseActivation, stdlib.NewLogFunction(replLogger{repl: r}))
 
 	interpreterConfig := &interpreter.Config{
 		Storage: storage,
@@ -81,6 +111,7 @@ func NewREPL() (*REPL, error) {
 			return uuid, nil
 		},
 		BaseActivation: baseActivation,
+		Debugger:       r.debugger,
 	}
 
 	inter, err := interpreter.NewInterpreter(
@@ -89,15 +120,30 @@ func NewREPL() (*REPL, error) {
 		interpreterConfig,
 	)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	repl := &REPL{
-		checker: checker,
-		inter:   inter,
-		codes:   codes,
+	r.checker = checker
+	r.inter = inter
+	r.codes = codes
+	r.declarations = nil
+	r.inputs = nil
+
+	return nil
+}
+
+// replLogger logs to standard output, unless inputs are replayed
+type replLogger struct {
+	repl *REPL
+}
+
+var _ stdlib.Logger = replLogger{}
+
+func (l replLogger) ProgramLog(message string) error {
+	if l.repl.replaying {
+		return nil
 	}
-	return repl, nil
+	return cmd.StandardOutputLogger{}.ProgramLog(message)
 }
 
 func (r *REPL) onError(err error, location common.Location, codes map[Location][]byte) {
@@ -196,6 +242,8 @@ func (r *REPL) Accept(code []byte, eval bool) (inputIsComplete bool, err error)
 
 	r.codes[r.checker.Location] = append(currentCode[:], code...)
 
+	input := string(code)
+
 	defer func() {
 		if panicResult := recover(); panicResult != nil {
 
@@ -247,6 +295,8 @@ func (r *REPL) Accept(code []byte, eval bool) (inputIsComplete bool, err error)
 	inputIsComplete = isInputComplete(tokens)
 
 	if !inputIsComplete {
+		// The input is accepted again when it is complete
+		r.codes[r.checker.Location] = currentCode
 		return
 	}
 
@@ -279,6 +329,8 @@ func (r *REPL) Accept(code []byte, eval bool) (inputIsComplete bool, err error)
 				return
 			}
 
+			r.declarations = append(r.declarations, declaration)
+
 			if eval {
 				r.inter.VisitProgram(program)
 			}
@@ -317,6 +369,11 @@ func (r *REPL) Accept(code []byte, eval bool) (inputIsComplete bool, err error)
 		}
 	}
 
+	// Record the input, so the session can be replayed
+	if eval {
+		r.inputs = append(r.inputs, input)
+	}
+
 	return
 }
 
@@ -353,6 +410,72 @@ func (r *REPL) Suggestions() (result []REPLSuggestion) {
 	return
 }
 
+// FunctionLine returns the line of the first statement of the function
+// with the given name, which was declared in the session.
+// The name of a composite's function is qualified, e.g. `S.foo`
+func (r *REPL) FunctionLine(name string) (uint, error) {
+	var function *ast.FunctionDeclaration
+
+	declarations := r.declarations
+	identifiers := strings.Split(name, ".")
+
+	for i, identifier := range identifiers {
+		last := i == len(identifiers)-1
+
+		var next []ast.Declaration
+
+		for _, declaration := range declarations {
+
+			if last {
+				switch declaration := declaration.(type) {
+				case *ast.FunctionDeclaration:
+					if declaration.Identifier.Identifier == identifier {
+						function = declaration
+					}
+
+				case *ast.SpecialFunctionDeclaration:
+					if declaration.Kind.Keywords() == identifier {
+						function = declaration.FunctionDeclaration
+					}
+				}
+
+				if function != nil {
+					break
+				}
+
+				continue
+			}
+
+			declarationIdentifier := declaration.DeclarationIdentifier()
+			members := declaration.DeclarationMembers()
+			if declarationIdentifier != nil &&
+				declarationIdentifier.Identifier == identifier &&
+				members != nil {
+
+				next = members.Declarations()
+				break
+			}
+		}
+
+		declarations = next
+	}
+
+	if function == nil {
+		return 0, fmt.Errorf("unknown function: %s", name)
+	}
+
+	functionBlock := function.FunctionBlock
+	if functionBlock == nil ||
+		functionBlock.Block == nil ||
+		len(functionBlock.Block.Statements) == 0 {
+
+		return 0, fmt.Errorf("function has no statements: %s", name)
+	}
+
+	line := functionBlock.Block.Statements[0].StartPosition().Line
+	return uint(line), nil
+}
+
 func (r *REPL) GetGlobal(name string) interpreter.Value {
 	variable := r.inter.Globals.Get(name)
 	if variable == nil {
@@ -373,8 +496,145 @@ func (r *REPL) ExportValue(value interpreter.Value) (cadence.Value, error) {
 
 func (r *REPL) onResult(result interpreter.ExpressionResult) {
 	onResult := r.OnResult
-	if onResult == nil {
+	if onResult == nil || r.replaying {
 		return
 	}
 	onResult(result)
 }
+
+// Inputs returns the inputs of the session which were accepted and evaluated successfully
+func (r *REPL) Inputs() []string {
+	return r.inputs
+}
+
+// Save writes the inputs of the session to the given writer,
+// so the session can be loaded again
+func (r *REPL) Save(writer io.Writer) error {
+	for _, input := range r.inputs {
+		_, err := io.WriteString(writer, input)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load accepts and evaluates the given code of a saved session, line by line,
+// like it was entered in the session. Results and logs are not reported.
+//
+// Loading stops at the first input which fails
+func (r *REPL) Load(code []byte) error {
+	r.replaying = true
+	defer func() {
+		r.replaying = false
+	}()
+
+	var input []byte
+
+	for _, line := range bytes.SplitAfter(code, lineSep) {
+		if len(line) == 0 {
+			continue
+		}
+
+		input = append(input, line...)
+		if !bytes.HasSuffix(input, lineSep) {
+			input = append(input, lineSep...)
+		}
+
+		inputIsComplete, err := r.replay(input)
+		if err != nil {
+			return err
+		}
+
+		if inputIsComplete {
+			input = nil
+		}
+	}
+
+	if len(input) > 0 {
+		return fmt.Errorf("incomplete input: %s", bytes.TrimSpace(input))
+	}
+
+	return nil
+}
+
+// Undo rolls back the last input of the session, e.g. the last declaration,
+// by resetting the session and replaying all other inputs.
+//
+// If replaying fails, the session is restored to the state before the undo
+func (r *REPL) Undo() error {
+	count := len(r.inputs)
+	if count == 0 {
+		return goErrors.New("nothing to undo")
+	}
+
+	inputs := r.inputs[:count-1]
+
+	// Resetting replaces the checker and the interpreter,
+	// so the previous ones are unaffected by the replay and can be restored
+	previousState := r.state()
+
+	err := r.Reset()
+	if err != nil {
+		return err
+	}
+
+	r.replaying = true
+	defer func() {
+		r.replaying = false
+	}()
+
+	for _, input := range inputs {
+		_, err := r.replay([]byte(input))
+		if err != nil {
+			r.restoreState(previousState)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// replState is the state of a session, which is replaced when the session is reset
+type replState struct {
+	checker      *sema.Checker
+	inter        *interpreter.Interpreter
+	codes        map[Location][]byte
+	declarations []ast.Declaration
+	inputs       []string
+}
+
+func (r *REPL) state() replState {
+	return replState{
+		checker:      r.checker,
+		inter:        r.inter,
+		codes:        r.codes,
+		declarations: r.declarations,
+		inputs:       r.inputs,
+	}
+}
+
+func (r *REPL) restoreState(state replState) {
+	r.checker = state.checker
+	r.inter = state.inter
+	r.codes = state.codes
+	r.declarations = state.declarations
+	r.inputs = state.inputs
+}
+
+// replay accepts and evaluates the given input,
+// and returns an error if the input is complete but failed to evaluate
+func (r *REPL) replay(input []byte) (inputIsComplete bool, err error) {
+	count := len(r.inputs)
+
+	inputIsComplete, err = r.Accept(input, true)
+	if err != nil {
+		return false, err
+	}
+
+	if inputIsComplete && len(r.inputs) == count {
+		return false, fmt.Errorf("failed to evaluate input: %s", bytes.TrimSpace(input))
+	}
+
+	return inputIsComplete, nil
+}
@@ -0,0 +1,286 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+func TestREPLFunctionLine(t *testing.T) {
+
+	t.Parallel()
+
+	repl, err := NewREPL()
+	require.NoError(t, err)
+
+	accept := func(code string) {
+		_, err := repl.Accept([]byte(code), true)
+		require.NoError(t, err)
+	}
+
+	accept("fun foo(): Int {\n  let x = 1\n  return x\n}\n")
+	accept("struct S {\n  init() {}\n  fun bar() {\n    foo()\n  }\n}\n")
+
+	line, err := repl.FunctionLine("S.bar")
+	require.NoError(t, err)
+	require.Equal(t, uint(8), line)
+
+	line, err = repl.FunctionLine("foo")
+	require.NoError(t, err)
+	require.Equal(t, uint(2), line)
+
+	_, err = repl.FunctionLine("S.init")
+	require.EqualError(t, err, "function has no statements: S.init")
+
+	_, err = repl.FunctionLine("S.baz")
+	require.EqualError(t, err, "unknown function: S.baz")
+
+	_, err = repl.FunctionLine("bar")
+	require.EqualError(t, err, "unknown function: bar")
+}
+
+func TestREPLDebugger(t *testing.T) {
+
+	t.Parallel()
+
+	debugger := interpreter.NewDebugger()
+
+	repl, err := NewREPLWithDebugger(debugger)
+	require.NoError(t, err)
+
+	var results []string
+	repl.OnResult = func(value interpreter.Value) {
+		results = append(results, value.String())
+	}
+
+	_, err = repl.Accept([]byte("fun double(_ x: Int): Int {\n  return x * 2\n}\n"), true)
+	require.NoError(t, err)
+
+	line, err := repl.FunctionLine("double")
+	require.NoError(t, err)
+
+	debugger.AddBreakpoint(common.REPLLocation{}, line)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := repl.Accept([]byte("double(21)\n"), true)
+		require.NoError(t, err)
+	}()
+
+	stop := <-debugger.Stops()
+	require.Equal(t, 2, stop.Statement.StartPosition().Line)
+
+	value, err := debugger.Evaluate(stop.Interpreter, mustParseExpression(t, "x"))
+	require.NoError(t, err)
+	require.Equal(t, interpreter.NewUnmeteredIntValueFromInt64(21), value)
+
+	debugger.Continue()
+	<-done
+
+	require.Equal(t, []string{"42"}, results)
+}
+
+func TestREPLSaveAndLoad(t *testing.T) {
+
+	t.Parallel()
+
+	repl, err := NewREPL()
+	require.NoError(t, err)
+
+	var results []string
+	repl.OnResult = func(value interpreter.Value) {
+		results = append(results, value.String())
+	}
+
+	// Lines are accepted like the console does, until the input is complete
+
+	var code string
+	for _, line := range []string{
+		"fun add(_ a: Int, _ b: Int): Int {",
+		"  return a + b",
+		"}",
+		"let x = add(1, 2)",
+		"let y = [",
+		"  x * 2",
+		"]",
+	} {
+		code += line + "\n"
+		inputIsComplete, err := repl.Accept([]byte(code), true)
+		require.NoError(t, err)
+		if inputIsComplete {
+			code = ""
+		}
+	}
+
+	// Inputs which fail are not recorded
+
+	_, err = repl.Accept([]byte("let z = unknown\n"), true)
+	require.Error(t, err)
+
+	_, err = repl.Accept([]byte("y[0]\n"), true)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"6"}, results)
+
+	// Incomplete inputs are only recorded when they are complete
+
+	require.Equal(t,
+		[]string{
+			"fun add(_ a: Int, _ b: Int): Int {\n  return a + b\n}\n",
+			"let x = add(1, 2)\n",
+			"let y = [\n  x * 2\n]\n",
+			"y[0]\n",
+		},
+		repl.Inputs(),
+	)
+
+	var saved bytes.Buffer
+	err = repl.Save(&saved)
+	require.NoError(t, err)
+
+	loaded, err := NewREPL()
+	require.NoError(t, err)
+
+	var loadedResults []string
+	loaded.OnResult = func(value interpreter.Value) {
+		loadedResults = append(loadedResults, value.String())
+	}
+
+	err = loaded.Load(saved.Bytes())
+	require.NoError(t, err)
+
+	// Results are not reported when loading
+
+	require.Empty(t, loadedResults)
+	require.Equal(t, repl.Inputs(), loaded.Inputs())
+	require.Equal(t, "[6]", loaded.GetGlobal("y").String())
+
+	// Loading incomplete code fails
+
+	err = loaded.Load([]byte("fun foo() {\n"))
+	require.EqualError(t, err, "incomplete input: fun foo() {")
+}
+
+func TestREPLUndoAndReset(t *testing.T) {
+
+	t.Parallel()
+
+	repl, err := NewREPL()
+	require.NoError(t, err)
+
+	accept := func(code string) error {
+		_, err := repl.Accept([]byte(code), true)
+		return err
+	}
+
+	require.NoError(t, accept("let x = 1\n"))
+	require.NoError(t, accept("var y = x\n"))
+	require.NoError(t, accept("y = 2\n"))
+
+	// Undoing a statement rolls back its effects
+
+	err = repl.Undo()
+	require.NoError(t, err)
+
+	require.Equal(t,
+		interpreter.NewUnmeteredIntValueFromInt64(1),
+		repl.GetGlobal("y"),
+	)
+
+	// Undoing a declaration removes it, so it can be declared again
+
+	err = repl.Undo()
+	require.NoError(t, err)
+
+	require.Nil(t, repl.GetGlobal("y"))
+	require.NoError(t, accept("let y = \"y\"\n"))
+
+	require.Equal(t,
+		[]string{"let x = 1\n", "let y = \"y\"\n"},
+		repl.Inputs(),
+	)
+
+	// Resetting discards all declarations
+
+	err = repl.Reset()
+	require.NoError(t, err)
+
+	require.Nil(t, repl.GetGlobal("x"))
+	require.Empty(t, repl.Inputs())
+
+	err = repl.Undo()
+	require.EqualError(t, err, "nothing to undo")
+}
+
+func TestREPLUndoFailedReplay(t *testing.T) {
+
+	t.Parallel()
+
+	repl, err := NewREPL()
+	require.NoError(t, err)
+
+	repl.OnError = func(_ error, _ common.Location, _ map[common.Location][]byte) {}
+
+	accept := func(code string) error {
+		_, err := repl.Accept([]byte(code), true)
+		return err
+	}
+
+	require.NoError(t, accept("let x = 1\n"))
+	require.NoError(t, accept("var y = x\n"))
+	require.NoError(t, accept("y = 2\n"))
+
+	// Replace an input with one which fails when it is replayed,
+	// e.g. because it depends on state which changed since it was evaluated
+
+	repl.inputs[1] = "var y = 1 / 0\n"
+
+	err = repl.Undo()
+	require.EqualError(t, err, "failed to evaluate input: var y = 1 / 0")
+
+	// The session is restored to the state before the undo
+
+	require.Equal(t,
+		[]string{"let x = 1\n", "var y = 1 / 0\n", "y = 2\n"},
+		repl.Inputs(),
+	)
+	require.Equal(t,
+		interpreter.NewUnmeteredIntValueFromInt64(1),
+		repl.GetGlobal("x"),
+	)
+	require.Equal(t,
+		interpreter.NewUnmeteredIntValueFromInt64(2),
+		repl.GetGlobal("y"),
+	)
+
+	// The session can still be used
+
+	require.NoError(t, accept("let z = x + y\n"))
+	require.Equal(t,
+		interpreter.NewUnmeteredIntValueFromInt64(3),
+		repl.GetGlobal("z"),
+	)
+}
@@ -210,13 +210,15 @@ func reportMetric(
 
 // interpreterRuntime is an interpreter-based version of the Flow runtime.
 type interpreterRuntime struct {
-	defaultConfig Config
+	compiledPrograms *compiledPrograms
+	defaultConfig    Config
 }
 
 // NewInterpreterRuntime returns an interpreter-based version of the Flow runtime.
 func NewInterpreterRuntime(defaultConfig Config) Runtime {
 	return &interpreterRuntime{
-		defaultConfig: defaultConfig,
+		defaultConfig:    defaultConfig,
+		compiledPrograms: newCompiledPrograms(),
 	}
 }
 
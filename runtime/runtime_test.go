@@ -7969,7 +7969,7 @@ func TestRuntimeErrorExcerpts(t *testing.T) {
 	RequireError(t, err)
 
 	errorString := `Execution failed:
-error: unexpectedly found nil while forcing an Optional value
+error[R0013]: unexpectedly found nil while forcing an Optional value
   --> 0000000000000000000000000000000000000000000000000000000000000000:9:15
    |
  9 |         return a
@@ -8021,7 +8021,7 @@ func TestRuntimeErrorExcerptsMultiline(t *testing.T) {
 	RequireError(t, err)
 
 	errorString := `Execution failed:
-error: unexpectedly found nil while forcing an Optional value
+error[R0013]: unexpectedly found nil while forcing an Optional value
   --> 0000000000000000000000000000000000000000000000000000000000000000:9:15
    |
  9 |         return a
@@ -22,8 +22,10 @@ import (
 	"sync"
 
 	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/compiler/bytecode"
 	"github.com/onflow/cadence/runtime/interpreter"
 	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/vm"
 )
 
 type interpreterScriptExecutorPreparation struct {
@@ -242,6 +244,43 @@ func (executor *interpreterScriptExecutor) scriptExecutionFunction() InterpretFu
 			return nil, err
 		}
 
+		if executor.runtime.defaultConfig.ScriptBytecodeVMEnabled {
+			compiledProgram, ok := executor.runtime.compiledPrograms.get(executor.program)
+			if ok {
+				return executor.executeCompiled(inter, compiledProgram, values)
+			}
+		}
+
 		return inter.Invoke(sema.FunctionEntryPointName, values...)
 	}
 }
+
+// executeCompiled executes the entry point of the compiled program in the bytecode VM
+func (executor *interpreterScriptExecutor) executeCompiled(
+	inter *interpreter.Interpreter,
+	program *bytecode.Program,
+	arguments []interpreter.Value,
+) (
+	interpreter.Value,
+	error,
+) {
+	machine, err := vm.NewBytecodeVM(program, inter)
+	if err != nil {
+		return nil, err
+	}
+
+	// The compiled code expects arguments to have the parameter types,
+	// so convert and box them, like the interpreter does when invoking a function
+
+	parameters := executor.functionEntryPointType.Parameters
+	for i, argument := range arguments {
+		arguments[i] = inter.ConvertAndBox(
+			interpreter.EmptyLocationRange,
+			argument,
+			inter.MustSemaTypeOfValue(argument),
+			parameters[i].TypeAnnotation.Type,
+		)
+	}
+
+	return machine.Invoke(sema.FunctionEntryPointName, arguments...)
+}
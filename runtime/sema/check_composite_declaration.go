@@ -1915,6 +1915,7 @@ func (checker *Checker) enumMembersAndOrigins(
 					Access:          enumCase.Access,
 					Explanation:     "enum cases must be public",
 					Pos:             enumCase.StartPos,
+					Kind:            AccessModifierErrorKindMustBePublic,
 				},
 			)
 		}
@@ -139,9 +139,13 @@ func (checker *Checker) VisitExpressionStatement(statement *ast.ExpressionStatem
 	ty := checker.VisitExpression(expression, nil)
 
 	if ty.IsResourceType() {
+		// The resource could be destroyed by destroying the result of the expression
+		destroyPos := expression.StartPosition()
+
 		checker.report(
 			&ResourceLossError{
-				Range: ast.NewRangeFromPositioned(checker.memoryGauge, expression),
+				DestroyPos: &destroyPos,
+				Range:      ast.NewRangeFromPositioned(checker.memoryGauge, expression),
 			},
 		)
 	}
@@ -18,7 +18,10 @@
 
 package sema
 
-import "github.com/onflow/cadence/runtime/ast"
+import (
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+)
 
 func (checker *Checker) VisitReturnStatement(statement *ast.ReturnStatement) (_ struct{}) {
 	functionActivation := checker.functionActivations.Current()
@@ -31,7 +34,12 @@ func (checker *Checker) VisitReturnStatement(statement *ast.ReturnStatement) (_
 		// The function activation's value activation depth is where the *function* is declared ("parent scope"),
 		// and two value activation scopes are defined for the function itself: for the parameters and the body.
 
-		checker.checkResourceLoss(functionActivation.ValueActivationDepth + 1)
+		checker.checkResourceLoss(
+			functionActivation.ValueActivationDepth+1,
+			func(common.MemoryGauge) ast.Position {
+				return statement.StartPos
+			},
+		)
 		functionActivation.ReturnInfo.MaybeReturned = true
 		functionActivation.ReturnInfo.DefinitelyReturned = true
 	}()
@@ -81,6 +81,8 @@ type ImportHandlerFunc func(checker *Checker, importedLocation common.Location,
 
 type MemberAccountAccessHandlerFunc func(checker *Checker, memberLocation common.Location) bool
 
+type ImportSuggestionHandlerFunc func(checker *Checker, identifier string) []common.Location
+
 type ContractValueHandlerFunc func(
 	checker *Checker,
 	declaration *ast.CompositeDeclaration,
@@ -741,14 +743,14 @@ func (checker *Checker) findAndCheckValueVariable(identifierExpression *ast.Iden
 	identifier := identifierExpression.Identifier
 	variable := checker.valueActivations.Find(identifier.Identifier)
 	if variable == nil {
-		checker.report(
-			&NotDeclaredError{
-				ExpectedKind: common.DeclarationKindVariable,
-				Name:         identifier.Identifier,
-				Expression:   identifierExpression,
-				Pos:          identifier.StartPosition(),
-			},
-		)
+		err := &NotDeclaredError{
+			ExpectedKind: common.DeclarationKindVariable,
+			Name:         identifier.Identifier,
+			Expression:   identifierExpression,
+			Pos:          identifier.StartPosition(),
+		}
+		checker.suggestImports(err)
+		checker.report(err)
 		return nil
 	}
 
@@ -1144,6 +1146,29 @@ func (checker *Checker) convertConstantSizedType(t *ast.ConstantSizedType) Type
 	}
 }
 
+// suggestImports sets the locations which declare the undeclared identifier of the given error,
+// and which could be imported, if an import suggestion handler is configured
+func (checker *Checker) suggestImports(err *NotDeclaredError) {
+	handler := checker.Config.ImportSuggestionHandler
+	if handler == nil {
+		return
+	}
+
+	locations := handler(checker, err.Name)
+	if len(locations) == 0 {
+		return
+	}
+
+	err.SuggestedImportLocations = locations
+
+	importDeclarations := checker.Program.ImportDeclarations()
+	if len(importDeclarations) > 0 {
+		lastImportDeclaration := importDeclarations[len(importDeclarations)-1]
+		endPos := lastImportDeclaration.EndPosition(checker.memoryGauge)
+		err.ImportPos = &endPos
+	}
+}
+
 func (checker *Checker) convertVariableSizedType(t *ast.VariableSizedType) Type {
 	elementType := checker.ConvertType(t.Type)
 	return &VariableSizedType{
@@ -1154,13 +1179,13 @@ func (checker *Checker) convertVariableSizedType(t *ast.VariableSizedType) Type
 func (checker *Checker) findAndCheckTypeVariable(identifier ast.Identifier, recordOccurrence bool) *Variable {
 	variable := checker.typeActivations.Find(identifier.Identifier)
 	if variable == nil {
-		checker.report(
-			&NotDeclaredError{
-				ExpectedKind: common.DeclarationKindType,
-				Name:         identifier.Identifier,
-				Pos:          identifier.StartPosition(),
-			},
-		)
+		err := &NotDeclaredError{
+			ExpectedKind: common.DeclarationKindType,
+			Name:         identifier.Identifier,
+			Pos:          identifier.StartPosition(),
+		}
+		checker.suggestImports(err)
+		checker.report(err)
 
 		return nil
 	}
@@ -1333,7 +1358,7 @@ func (checker *Checker) enterValueScope() {
 
 func (checker *Checker) leaveValueScope(getEndPosition EndPositionGetter, checkResourceLoss bool) {
 	if checkResourceLoss {
-		checker.checkResourceLoss(checker.valueActivations.Depth())
+		checker.checkResourceLoss(checker.valueActivations.Depth(), getEndPosition)
 	}
 
 	checker.valueActivations.Leave(getEndPosition)
@@ -1344,8 +1369,10 @@ func (checker *Checker) leaveValueScope(getEndPosition EndPositionGetter, checkR
 //    when detecting resource use after invalidation in loops
 
 // checkResourceLoss reports an error if there is a variable in the current scope
-// that has a resource type and which was not moved or destroyed
-func (checker *Checker) checkResourceLoss(depth int) {
+// that has a resource type and which was not moved or destroyed.
+// The optional destroy position getter returns the position before which
+// the lost resources could be destroyed, e.g. the end of the block
+func (checker *Checker) checkResourceLoss(depth int, getDestroyPosition EndPositionGetter) {
 
 	returnInfo := checker.functionActivations.Current().ReturnInfo
 	if returnInfo.IsUnreachable() {
@@ -1358,8 +1385,16 @@ func (checker *Checker) checkResourceLoss(depth int) {
 			variable.DeclarationKind != common.DeclarationKindSelf &&
 			!checker.resources.Get(Resource{Variable: variable}).DefinitivelyInvalidated() {
 
+			var destroyPos *ast.Position
+			if getDestroyPosition != nil {
+				pos := getDestroyPosition(checker.memoryGauge)
+				destroyPos = &pos
+			}
+
 			checker.report(
 				&ResourceLossError{
+					Variable:   name,
+					DestroyPos: destroyPos,
 					Range: ast.NewRange(
 						checker.memoryGauge,
 						*variable.Pos,
@@ -1663,6 +1698,8 @@ func (checker *Checker) ResetErrors() {
 
 const invalidTypeDeclarationAccessModifierExplanation = "type declarations must be public"
 
+const localDeclarationAccessModifierExplanation = "local declarations may not have an access modifier"
+
 func (checker *Checker) checkDeclarationAccessModifier(
 	access ast.Access,
 	declarationKind common.DeclarationKind,
@@ -1675,9 +1712,10 @@ func (checker *Checker) checkDeclarationAccessModifier(
 			checker.report(
 				&InvalidAccessModifierError{
 					Access:          access,
-					Explanation:     "local declarations may not have an access modifier",
+					Explanation:     localDeclarationAccessModifierExplanation,
 					DeclarationKind: declarationKind,
 					Pos:             startPos,
+					Kind:            AccessModifierErrorKindLocalDeclaration,
 				},
 			)
 		}
@@ -1692,11 +1730,13 @@ func (checker *Checker) checkDeclarationAccessModifier(
 
 			if isConstant || isTypeDeclaration {
 				var explanation string
+				kind := AccessModifierErrorKindUnknown
 				switch {
 				case isConstant:
 					explanation = "constants can never be set"
 				case isTypeDeclaration:
 					explanation = invalidTypeDeclarationAccessModifierExplanation
+					kind = AccessModifierErrorKindMustBePublic
 				}
 
 				checker.report(
@@ -1705,6 +1745,7 @@ func (checker *Checker) checkDeclarationAccessModifier(
 						Explanation:     explanation,
 						DeclarationKind: declarationKind,
 						Pos:             startPos,
+						Kind:            kind,
 					},
 				)
 			}
@@ -1720,6 +1761,7 @@ func (checker *Checker) checkDeclarationAccessModifier(
 						Explanation:     invalidTypeDeclarationAccessModifierExplanation,
 						DeclarationKind: declarationKind,
 						Pos:             startPos,
+						Kind:            AccessModifierErrorKindMustBePublic,
 					},
 				)
 			}
@@ -1736,6 +1778,7 @@ func (checker *Checker) checkDeclarationAccessModifier(
 						Explanation:     invalidTypeDeclarationAccessModifierExplanation,
 						DeclarationKind: declarationKind,
 						Pos:             startPos,
+						Kind:            AccessModifierErrorKindMustBePublic,
 					},
 				)
 			}
@@ -1752,6 +1795,7 @@ func (checker *Checker) checkDeclarationAccessModifier(
 						DeclarationKind: declarationKind,
 						Explanation:     invalidTypeDeclarationAccessModifierExplanation,
 						Pos:             startPos,
+						Kind:            AccessModifierErrorKindMustBePublic,
 					},
 				)
 			}
@@ -1759,10 +1803,16 @@ func (checker *Checker) checkDeclarationAccessModifier(
 			// In strict mode, access modifiers must be given
 
 			if checker.Config.AccessCheckMode == AccessCheckModeStrict {
+				kind := AccessModifierErrorKindUnknown
+				if isTypeDeclaration {
+					kind = AccessModifierErrorKindMustBePublic
+				}
+
 				checker.report(
 					&MissingAccessModifierError{
 						DeclarationKind: declarationKind,
 						Pos:             startPos,
+						Kind:            kind,
 					},
 				)
 			}
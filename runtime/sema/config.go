@@ -34,6 +34,9 @@ type Config struct {
 	CheckHandler CheckHandlerFunc
 	// LocationHandler is used to resolve locations
 	LocationHandler LocationHandlerFunc
+	// ImportSuggestionHandler is used to find the locations which declare an undeclared identifier,
+	// so imports can be suggested as fixes
+	ImportSuggestionHandler ImportSuggestionHandlerFunc
 	// AccessCheckMode is the mode for access control checks.
 	// It determines how access modifiers how existing and missing acess modifiers are treated
 	AccessCheckMode AccessCheckMode
@@ -102,12 +102,17 @@ type InvalidPragmaError struct {
 
 var _ SemanticError = &InvalidPragmaError{}
 var _ errors.UserError = &InvalidPragmaError{}
+var _ errors.HasErrorCode = &InvalidPragmaError{}
 var _ errors.SecondaryError = &InvalidPragmaError{}
 
 func (*InvalidPragmaError) isSemanticError() {}
 
 func (*InvalidPragmaError) IsUserError() {}
 
+func (*InvalidPragmaError) ErrorCode() errors.ErrorCode {
+	return "C0001"
+}
+
 func (e *InvalidPragmaError) Error() string {
 	return "invalid pragma"
 }
@@ -171,11 +176,16 @@ type RedeclarationError struct {
 
 var _ SemanticError = &RedeclarationError{}
 var _ errors.UserError = &RedeclarationError{}
+var _ errors.HasErrorCode = &RedeclarationError{}
 
 func (*RedeclarationError) isSemanticError() {}
 
 func (*RedeclarationError) IsUserError() {}
 
+func (*RedeclarationError) ErrorCode() errors.ErrorCode {
+	return "C0002"
+}
+
 func (e *RedeclarationError) Error() string {
 	return fmt.Sprintf(
 		"cannot redeclare %s: `%s` is already declared",
@@ -229,16 +239,26 @@ type NotDeclaredError struct {
 	Name         string
 	Pos          ast.Position
 	ExpectedKind common.DeclarationKind
+	// SuggestedImportLocations are the locations which declare the identifier, and which could be imported
+	SuggestedImportLocations []common.Location
+	// ImportPos is the end position of the last import declaration of the program, if any
+	ImportPos *ast.Position
 }
 
 var _ SemanticError = &NotDeclaredError{}
 var _ errors.UserError = &NotDeclaredError{}
+var _ errors.HasErrorCode = &NotDeclaredError{}
 var _ errors.SecondaryError = &NotDeclaredError{}
+var _ HasSuggestedFixes = &NotDeclaredError{}
 
 func (*NotDeclaredError) isSemanticError() {}
 
 func (*NotDeclaredError) IsUserError() {}
 
+func (*NotDeclaredError) ErrorCode() errors.ErrorCode {
+	return "C0003"
+}
+
 func (e *NotDeclaredError) Error() string {
 	return fmt.Sprintf(
 		"cannot find %s in this scope: `%s`",
@@ -260,6 +280,68 @@ func (e *NotDeclaredError) EndPosition(memoryGauge common.MemoryGauge) ast.Posit
 	return e.Pos.Shifted(memoryGauge, length-1)
 }
 
+func (e *NotDeclaredError) SuggestFixes(_ string) []SuggestedFix {
+	var fixes []SuggestedFix
+
+	for _, location := range e.SuggestedImportLocations {
+		declaration := importDeclarationCode(e.Name, location)
+		if declaration == "" {
+			continue
+		}
+
+		// Insert the import declaration after the last import declaration,
+		// or at the start of the program, if there are no import declarations
+
+		var edit TextEdit
+		if e.ImportPos != nil {
+			insertionPos := e.ImportPos.Shifted(nil, 1)
+			edit = TextEdit{
+				Insertion: "\n" + declaration,
+				Range:     ast.NewUnmeteredRange(insertionPos, insertionPos),
+			}
+		} else {
+			insertionPos := ast.Position{Line: 1}
+			edit = TextEdit{
+				Insertion: declaration + "\n\n",
+				Range:     ast.NewUnmeteredRange(insertionPos, insertionPos),
+			}
+		}
+
+		fixes = append(
+			fixes,
+			SuggestedFix{
+				Message:   fmt.Sprintf("add `%s`", declaration),
+				TextEdits: []TextEdit{edit},
+			},
+		)
+	}
+
+	return fixes
+}
+
+// importDeclarationCode returns the code of a declaration
+// which imports the given identifier from the given location,
+// or an empty string if the location cannot be imported from
+func importDeclarationCode(identifier string, location common.Location) string {
+	var locationCode string
+
+	switch location := location.(type) {
+	case common.AddressLocation:
+		locationCode = location.Address.ShortHexWithPrefix()
+
+	case common.StringLocation:
+		locationCode = ast.QuoteString(string(location))
+
+	case common.IdentifierLocation:
+		locationCode = string(location)
+
+	default:
+		return ""
+	}
+
+	return fmt.Sprintf("import %s from %s", identifier, locationCode)
+}
+
 // AssignmentToConstantError
 
 type AssignmentToConstantError struct {
@@ -269,12 +351,17 @@ type AssignmentToConstantError struct {
 
 var _ SemanticError = &AssignmentToConstantError{}
 var _ errors.UserError = &AssignmentToConstantError{}
+var _ errors.HasErrorCode = &AssignmentToConstantError{}
 var _ errors.SecondaryError = &AssignmentToConstantError{}
 
 func (*AssignmentToConstantError) isSemanticError() {}
 
 func (*AssignmentToConstantError) IsUserError() {}
 
+func (*AssignmentToConstantError) ErrorCode() errors.ErrorCode {
+	return "C0004"
+}
+
 func (e *AssignmentToConstantError) Error() string {
 	return fmt.Sprintf("cannot assign to constant: `%s`", e.Name)
 }
@@ -294,12 +381,17 @@ type TypeMismatchError struct {
 
 var _ SemanticError = &TypeMismatchError{}
 var _ errors.UserError = &TypeMismatchError{}
+var _ errors.HasErrorCode = &TypeMismatchError{}
 var _ errors.SecondaryError = &TypeMismatchError{}
 
 func (*TypeMismatchError) isSemanticError() {}
 
 func (*TypeMismatchError) IsUserError() {}
 
+func (*TypeMismatchError) ErrorCode() errors.ErrorCode {
+	return "C0005"
+}
+
 func (e *TypeMismatchError) Error() string {
 	return "mismatched types"
 }
@@ -327,12 +419,17 @@ type TypeMismatchWithDescriptionError struct {
 
 var _ SemanticError = &TypeMismatchWithDescriptionError{}
 var _ errors.UserError = &TypeMismatchWithDescriptionError{}
+var _ errors.HasErrorCode = &TypeMismatchWithDescriptionError{}
 var _ errors.SecondaryError = &TypeMismatchWithDescriptionError{}
 
 func (*TypeMismatchWithDescriptionError) isSemanticError() {}
 
 func (*TypeMismatchWithDescriptionError) IsUserError() {}
 
+func (*TypeMismatchWithDescriptionError) ErrorCode() errors.ErrorCode {
+	return "C0006"
+}
+
 func (e *TypeMismatchWithDescriptionError) Error() string {
 	return "mismatched types"
 }
@@ -354,11 +451,16 @@ type NotIndexableTypeError struct {
 
 var _ SemanticError = &NotIndexableTypeError{}
 var _ errors.UserError = &NotIndexableTypeError{}
+var _ errors.HasErrorCode = &NotIndexableTypeError{}
 
 func (*NotIndexableTypeError) isSemanticError() {}
 
 func (*NotIndexableTypeError) IsUserError() {}
 
+func (*NotIndexableTypeError) ErrorCode() errors.ErrorCode {
+	return "C0007"
+}
+
 func (e *NotIndexableTypeError) Error() string {
 	return fmt.Sprintf(
 		"cannot index into value which has type: `%s`",
@@ -375,11 +477,16 @@ type NotIndexingAssignableTypeError struct {
 
 var _ SemanticError = &NotIndexingAssignableTypeError{}
 var _ errors.UserError = &NotIndexingAssignableTypeError{}
+var _ errors.HasErrorCode = &NotIndexingAssignableTypeError{}
 
 func (*NotIndexingAssignableTypeError) isSemanticError() {}
 
 func (*NotIndexingAssignableTypeError) IsUserError() {}
 
+func (*NotIndexingAssignableTypeError) ErrorCode() errors.ErrorCode {
+	return "C0008"
+}
+
 func (e *NotIndexingAssignableTypeError) Error() string {
 	return fmt.Sprintf(
 		"cannot assign into value which has type: `%s`",
@@ -396,11 +503,16 @@ type NotEquatableTypeError struct {
 
 var _ SemanticError = &NotEquatableTypeError{}
 var _ errors.UserError = &NotEquatableTypeError{}
+var _ errors.HasErrorCode = &NotEquatableTypeError{}
 
 func (*NotEquatableTypeError) isSemanticError() {}
 
 func (*NotEquatableTypeError) IsUserError() {}
 
+func (*NotEquatableTypeError) ErrorCode() errors.ErrorCode {
+	return "C0009"
+}
+
 func (e *NotEquatableTypeError) Error() string {
 	return fmt.Sprintf(
 		"cannot compare value which has type: `%s`",
@@ -417,11 +529,16 @@ type NotCallableError struct {
 
 var _ SemanticError = &NotCallableError{}
 var _ errors.UserError = &NotCallableError{}
+var _ errors.HasErrorCode = &NotCallableError{}
 
 func (*NotCallableError) isSemanticError() {}
 
 func (*NotCallableError) IsUserError() {}
 
+func (*NotCallableError) ErrorCode() errors.ErrorCode {
+	return "C0010"
+}
+
 func (e *NotCallableError) Error() string {
 	return fmt.Sprintf("cannot call type: `%s`",
 		e.Type.QualifiedString(),
@@ -438,12 +555,17 @@ type InsufficientArgumentsError struct {
 
 var _ SemanticError = &InsufficientArgumentsError{}
 var _ errors.UserError = &InsufficientArgumentsError{}
+var _ errors.HasErrorCode = &InsufficientArgumentsError{}
 var _ errors.SecondaryError = &InsufficientArgumentsError{}
 
 func (*InsufficientArgumentsError) isSemanticError() {}
 
 func (*InsufficientArgumentsError) IsUserError() {}
 
+func (*InsufficientArgumentsError) ErrorCode() errors.ErrorCode {
+	return "C0011"
+}
+
 func (e *InsufficientArgumentsError) Error() string {
 	return "too few arguments"
 }
@@ -466,12 +588,17 @@ type ExcessiveArgumentsError struct {
 
 var _ SemanticError = &ExcessiveArgumentsError{}
 var _ errors.UserError = &ExcessiveArgumentsError{}
+var _ errors.HasErrorCode = &ExcessiveArgumentsError{}
 var _ errors.SecondaryError = &ExcessiveArgumentsError{}
 
 func (*ExcessiveArgumentsError) isSemanticError() {}
 
 func (*ExcessiveArgumentsError) IsUserError() {}
 
+func (*ExcessiveArgumentsError) ErrorCode() errors.ErrorCode {
+	return "C0012"
+}
+
 func (e *ExcessiveArgumentsError) Error() string {
 	return "too many arguments"
 }
@@ -495,12 +622,17 @@ type MissingArgumentLabelError struct {
 
 var _ SemanticError = &MissingArgumentLabelError{}
 var _ errors.UserError = &MissingArgumentLabelError{}
+var _ errors.HasErrorCode = &MissingArgumentLabelError{}
 var _ HasSuggestedFixes = &MissingArgumentLabelError{}
 
 func (*MissingArgumentLabelError) isSemanticError() {}
 
 func (*MissingArgumentLabelError) IsUserError() {}
 
+func (*MissingArgumentLabelError) ErrorCode() errors.ErrorCode {
+	return "C0013"
+}
+
 func (e *MissingArgumentLabelError) Error() string {
 	return fmt.Sprintf(
 		"missing argument label: `%s`",
@@ -535,6 +667,7 @@ type IncorrectArgumentLabelError struct {
 
 var _ SemanticError = &IncorrectArgumentLabelError{}
 var _ errors.UserError = &IncorrectArgumentLabelError{}
+var _ errors.HasErrorCode = &IncorrectArgumentLabelError{}
 var _ errors.SecondaryError = &IncorrectArgumentLabelError{}
 var _ HasSuggestedFixes = &IncorrectArgumentLabelError{}
 
@@ -542,6 +675,10 @@ func (*IncorrectArgumentLabelError) isSemanticError() {}
 
 func (*IncorrectArgumentLabelError) IsUserError() {}
 
+func (*IncorrectArgumentLabelError) ErrorCode() errors.ErrorCode {
+	return "C0014"
+}
+
 func (e *IncorrectArgumentLabelError) Error() string {
 	return "incorrect argument label"
 }
@@ -613,12 +750,17 @@ type InvalidUnaryOperandError struct {
 
 var _ SemanticError = &InvalidUnaryOperandError{}
 var _ errors.UserError = &InvalidUnaryOperandError{}
+var _ errors.HasErrorCode = &InvalidUnaryOperandError{}
 var _ errors.SecondaryError = &InvalidUnaryOperandError{}
 
 func (*InvalidUnaryOperandError) isSemanticError() {}
 
 func (*InvalidUnaryOperandError) IsUserError() {}
 
+func (*InvalidUnaryOperandError) ErrorCode() errors.ErrorCode {
+	return "C0015"
+}
+
 func (e *InvalidUnaryOperandError) Error() string {
 	return fmt.Sprintf(
 		"cannot apply unary operation %s to type",
@@ -651,12 +793,17 @@ type InvalidBinaryOperandError struct {
 
 var _ SemanticError = &InvalidBinaryOperandError{}
 var _ errors.UserError = &InvalidBinaryOperandError{}
+var _ errors.HasErrorCode = &InvalidBinaryOperandError{}
 var _ errors.SecondaryError = &InvalidBinaryOperandError{}
 
 func (*InvalidBinaryOperandError) isSemanticError() {}
 
 func (*InvalidBinaryOperandError) IsUserError() {}
 
+func (*InvalidBinaryOperandError) ErrorCode() errors.ErrorCode {
+	return "C0016"
+}
+
 func (e *InvalidBinaryOperandError) Error() string {
 	return fmt.Sprintf(
 		"cannot apply binary operation %s to %s-hand type",
@@ -689,11 +836,16 @@ type InvalidBinaryOperandsError struct {
 
 var _ SemanticError = &InvalidBinaryOperandsError{}
 var _ errors.UserError = &InvalidBinaryOperandsError{}
+var _ errors.HasErrorCode = &InvalidBinaryOperandsError{}
 
 func (*InvalidBinaryOperandsError) isSemanticError() {}
 
 func (*InvalidBinaryOperandsError) IsUserError() {}
 
+func (*InvalidBinaryOperandsError) ErrorCode() errors.ErrorCode {
+	return "C0017"
+}
+
 func (e *InvalidBinaryOperandsError) Error() string {
 	return fmt.Sprintf(
 		"cannot apply binary operation %s to types: `%s`, `%s`",
@@ -732,12 +884,17 @@ type ControlStatementError struct {
 
 var _ SemanticError = &ControlStatementError{}
 var _ errors.UserError = &ControlStatementError{}
+var _ errors.HasErrorCode = &ControlStatementError{}
 var _ errors.SecondaryError = &ControlStatementError{}
 
 func (*ControlStatementError) isSemanticError() {}
 
 func (*ControlStatementError) IsUserError() {}
 
+func (*ControlStatementError) ErrorCode() errors.ErrorCode {
+	return "C0018"
+}
+
 func (e *ControlStatementError) Error() string {
 	return fmt.Sprintf(
 		"invalid control statement: `%s`",
@@ -757,6 +914,22 @@ func (e *ControlStatementError) SecondaryError() string {
 	)
 }
 
+// AccessModifierErrorKind is the kind of an invalid or missing access modifier,
+// which determines how the access modifier can be fixed
+type AccessModifierErrorKind uint8
+
+const (
+	// AccessModifierErrorKindUnknown is the kind of access modifier errors
+	// which may be fixed using one of several access modifiers
+	AccessModifierErrorKindUnknown AccessModifierErrorKind = iota
+	// AccessModifierErrorKindLocalDeclaration is the kind of access modifier errors
+	// of local declarations, which may not have an access modifier
+	AccessModifierErrorKindLocalDeclaration
+	// AccessModifierErrorKindMustBePublic is the kind of access modifier errors
+	// of declarations which must be public
+	AccessModifierErrorKindMustBePublic
+)
+
 // InvalidAccessModifierError
 
 type InvalidAccessModifierError struct {
@@ -764,15 +937,22 @@ type InvalidAccessModifierError struct {
 	Pos             ast.Position
 	DeclarationKind common.DeclarationKind
 	Access          ast.Access
+	Kind            AccessModifierErrorKind
 }
 
 var _ SemanticError = &InvalidAccessModifierError{}
 var _ errors.UserError = &InvalidAccessModifierError{}
+var _ errors.HasErrorCode = &InvalidAccessModifierError{}
+var _ HasSuggestedFixes = &InvalidAccessModifierError{}
 
 func (*InvalidAccessModifierError) isSemanticError() {}
 
 func (*InvalidAccessModifierError) IsUserError() {}
 
+func (*InvalidAccessModifierError) ErrorCode() errors.ErrorCode {
+	return "C0019"
+}
+
 func (e *InvalidAccessModifierError) Error() string {
 	var explanation string
 	if e.Explanation != "" {
@@ -808,21 +988,115 @@ func (e *InvalidAccessModifierError) EndPosition(memoryGauge common.MemoryGauge)
 	return e.Pos.Shifted(memoryGauge, length-1)
 }
 
+func (e *InvalidAccessModifierError) SuggestFixes(code string) []SuggestedFix {
+	if e.Access == ast.AccessNotSpecified {
+		return nil
+	}
+
+	length := accessModifierLength(code, e.Pos.Offset, e.Access)
+	if length == 0 {
+		return nil
+	}
+
+	switch e.Kind {
+	case AccessModifierErrorKindLocalDeclaration:
+		// Local declarations may not have any access modifier, so remove it,
+		// including the whitespace which separates it from the declaration
+
+		for offset := e.Pos.Offset + length; offset < len(code) && code[offset] == ' '; offset++ {
+			length++
+		}
+
+		return []SuggestedFix{
+			{
+				Message: "remove access modifier",
+				TextEdits: []TextEdit{
+					{
+						Replacement: "",
+						Range: ast.NewUnmeteredRange(
+							e.Pos,
+							e.Pos.Shifted(nil, length-1),
+						),
+					},
+				},
+			},
+		}
+
+	case AccessModifierErrorKindMustBePublic:
+		return []SuggestedFix{
+			{
+				Message: fmt.Sprintf("replace access modifier with `%s`", publicAccessModifier),
+				TextEdits: []TextEdit{
+					{
+						Replacement: publicAccessModifier,
+						Range: ast.NewUnmeteredRange(
+							e.Pos,
+							e.Pos.Shifted(nil, length-1),
+						),
+					},
+				},
+			},
+		}
+	}
+
+	// Any of several access modifiers may be valid,
+	// so the intended one cannot be suggested
+
+	return nil
+}
+
+// publicAccessModifier is the access modifier which is suggested for declarations which must be public
+const publicAccessModifier = "access(all)"
+
+// accessModifierLength returns the length of the access modifier at the given offset in the code,
+// which may be written using its keyword, e.g. `pub`, or using the `access` keyword, e.g. `access(all)`.
+// It returns 0 if the code at the offset is not the access modifier
+func accessModifierLength(code string, offset int, access ast.Access) int {
+	if offset < 0 || offset > len(code) {
+		return 0
+	}
+
+	code = code[offset:]
+
+	const accessKeyword = "access("
+	if strings.HasPrefix(code, accessKeyword) {
+		end := strings.IndexByte(code, ')')
+		if end < 0 {
+			return 0
+		}
+		return end + 1
+	}
+
+	keyword := access.Keyword()
+	if keyword == "" || !strings.HasPrefix(code, keyword) {
+		return 0
+	}
+
+	return len(keyword)
+}
+
 // MissingAccessModifierError
 
 type MissingAccessModifierError struct {
 	Explanation     string
 	Pos             ast.Position
 	DeclarationKind common.DeclarationKind
+	Kind            AccessModifierErrorKind
 }
 
 var _ errors.UserError = &MissingAccessModifierError{}
+var _ errors.HasErrorCode = &MissingAccessModifierError{}
 var _ SemanticError = &MissingAccessModifierError{}
+var _ HasSuggestedFixes = &MissingAccessModifierError{}
 
 func (*MissingAccessModifierError) isSemanticError() {}
 
 func (*MissingAccessModifierError) IsUserError() {}
 
+func (*MissingAccessModifierError) ErrorCode() errors.ErrorCode {
+	return "C0020"
+}
+
 func (e *MissingAccessModifierError) Error() string {
 	var explanation string
 	if e.Explanation != "" {
@@ -844,6 +1118,28 @@ func (e *MissingAccessModifierError) EndPosition(common.MemoryGauge) ast.Positio
 	return e.Pos
 }
 
+func (e *MissingAccessModifierError) SuggestFixes(_ string) []SuggestedFix {
+	// Only suggest to insert the public access modifier if it is the only valid one,
+	// other declarations should be given the most restrictive access possible,
+	// which cannot be determined here
+
+	if e.Kind != AccessModifierErrorKindMustBePublic {
+		return nil
+	}
+
+	return []SuggestedFix{
+		{
+			Message: fmt.Sprintf("insert access modifier `%s`", publicAccessModifier),
+			TextEdits: []TextEdit{
+				{
+					Insertion: publicAccessModifier + " ",
+					Range:     ast.NewUnmeteredRange(e.Pos, e.Pos),
+				},
+			},
+		},
+	}
+}
+
 // InvalidStaticModifierError
 
 type InvalidStaticModifierError struct {
@@ -852,11 +1148,16 @@ type InvalidStaticModifierError struct {
 
 var _ SemanticError = &InvalidStaticModifierError{}
 var _ errors.UserError = &InvalidStaticModifierError{}
+var _ errors.HasErrorCode = &InvalidStaticModifierError{}
 
 func (*InvalidStaticModifierError) isSemanticError() {}
 
 func (*InvalidStaticModifierError) IsUserError() {}
 
+func (*InvalidStaticModifierError) ErrorCode() errors.ErrorCode {
+	return "C0021"
+}
+
 func (e *InvalidStaticModifierError) Error() string {
 	return "invalid static modifier for declaration"
 }
@@ -869,11 +1170,16 @@ type InvalidNativeModifierError struct {
 
 var _ SemanticError = &InvalidNativeModifierError{}
 var _ errors.UserError = &InvalidNativeModifierError{}
+var _ errors.HasErrorCode = &InvalidNativeModifierError{}
 
 func (*InvalidNativeModifierError) isSemanticError() {}
 
 func (*InvalidNativeModifierError) IsUserError() {}
 
+func (*InvalidNativeModifierError) ErrorCode() errors.ErrorCode {
+	return "C0022"
+}
+
 func (e *InvalidNativeModifierError) Error() string {
 	return "invalid native modifier for declaration"
 }
@@ -886,11 +1192,16 @@ type NativeFunctionWithImplementationError struct {
 
 var _ SemanticError = &NativeFunctionWithImplementationError{}
 var _ errors.UserError = &NativeFunctionWithImplementationError{}
+var _ errors.HasErrorCode = &NativeFunctionWithImplementationError{}
 
 func (*NativeFunctionWithImplementationError) isSemanticError() {}
 
 func (*NativeFunctionWithImplementationError) IsUserError() {}
 
+func (*NativeFunctionWithImplementationError) ErrorCode() errors.ErrorCode {
+	return "C0023"
+}
+
 func (e *NativeFunctionWithImplementationError) Error() string {
 	return "native function must not have an implementation"
 }
@@ -904,11 +1215,16 @@ type InvalidNameError struct {
 
 var _ SemanticError = &InvalidNameError{}
 var _ errors.UserError = &InvalidNameError{}
+var _ errors.HasErrorCode = &InvalidNameError{}
 
 func (*InvalidNameError) isSemanticError() {}
 
 func (*InvalidNameError) IsUserError() {}
 
+func (*InvalidNameError) ErrorCode() errors.ErrorCode {
+	return "C0024"
+}
+
 func (e *InvalidNameError) Error() string {
 	return fmt.Sprintf("invalid name: `%s`", e.Name)
 }
@@ -930,11 +1246,16 @@ type UnknownSpecialFunctionError struct {
 
 var _ SemanticError = &UnknownSpecialFunctionError{}
 var _ errors.UserError = &UnknownSpecialFunctionError{}
+var _ errors.HasErrorCode = &UnknownSpecialFunctionError{}
 
 func (*UnknownSpecialFunctionError) isSemanticError() {}
 
 func (*UnknownSpecialFunctionError) IsUserError() {}
 
+func (*UnknownSpecialFunctionError) ErrorCode() errors.ErrorCode {
+	return "C0025"
+}
+
 func (e *UnknownSpecialFunctionError) Error() string {
 	return "unknown special function. did you mean `init`, `destroy`, or forget the `fun` keyword?"
 }
@@ -956,11 +1277,16 @@ type InvalidVariableKindError struct {
 
 var _ SemanticError = &InvalidVariableKindError{}
 var _ errors.UserError = &InvalidVariableKindError{}
+var _ errors.HasErrorCode = &InvalidVariableKindError{}
 
 func (*InvalidVariableKindError) isSemanticError() {}
 
 func (*InvalidVariableKindError) IsUserError() {}
 
+func (*InvalidVariableKindError) ErrorCode() errors.ErrorCode {
+	return "C0026"
+}
+
 func (e *InvalidVariableKindError) Error() string {
 	if e.Kind == ast.VariableKindNotSpecified {
 		return "missing variable kind"
@@ -978,11 +1304,16 @@ type InvalidDeclarationError struct {
 
 var _ SemanticError = &InvalidDeclarationError{}
 var _ errors.UserError = &InvalidDeclarationError{}
+var _ errors.HasErrorCode = &InvalidDeclarationError{}
 
 func (*InvalidDeclarationError) isSemanticError() {}
 
 func (*InvalidDeclarationError) IsUserError() {}
 
+func (*InvalidDeclarationError) ErrorCode() errors.ErrorCode {
+	return "C0027"
+}
+
 func (e *InvalidDeclarationError) Error() string {
 	if e.Identifier != "" {
 		return fmt.Sprintf(
@@ -1005,11 +1336,16 @@ type MissingInitializerError struct {
 
 var _ SemanticError = &MissingInitializerError{}
 var _ errors.UserError = &MissingInitializerError{}
+var _ errors.HasErrorCode = &MissingInitializerError{}
 
 func (*MissingInitializerError) isSemanticError() {}
 
 func (*MissingInitializerError) IsUserError() {}
 
+func (*MissingInitializerError) ErrorCode() errors.ErrorCode {
+	return "C0028"
+}
+
 func (e *MissingInitializerError) Error() string {
 	return fmt.Sprintf(
 		"missing initializer for field `%s` in type `%s`",
@@ -1039,6 +1375,7 @@ type NotDeclaredMemberError struct {
 
 var _ SemanticError = &NotDeclaredMemberError{}
 var _ errors.UserError = &NotDeclaredMemberError{}
+var _ errors.HasErrorCode = &NotDeclaredMemberError{}
 var _ errors.SecondaryError = &NotDeclaredMemberError{}
 var _ HasSuggestedFixes = &NotDeclaredMemberError{}
 
@@ -1046,6 +1383,10 @@ func (*NotDeclaredMemberError) isSemanticError() {}
 
 func (*NotDeclaredMemberError) IsUserError() {}
 
+func (*NotDeclaredMemberError) ErrorCode() errors.ErrorCode {
+	return "C0029"
+}
+
 func (e *NotDeclaredMemberError) Error() string {
 	return fmt.Sprintf(
 		"value of type `%s` has no member `%s`",
@@ -1153,11 +1494,16 @@ type AssignmentToConstantMemberError struct {
 
 var _ SemanticError = &AssignmentToConstantMemberError{}
 var _ errors.UserError = &AssignmentToConstantMemberError{}
+var _ errors.HasErrorCode = &AssignmentToConstantMemberError{}
 
 func (*AssignmentToConstantMemberError) isSemanticError() {}
 
 func (*AssignmentToConstantMemberError) IsUserError() {}
 
+func (*AssignmentToConstantMemberError) ErrorCode() errors.ErrorCode {
+	return "C0030"
+}
+
 func (e *AssignmentToConstantMemberError) Error() string {
 	return fmt.Sprintf("cannot assign to constant member: `%s`", e.Name)
 }
@@ -1170,11 +1516,16 @@ type FieldReinitializationError struct {
 
 var _ SemanticError = &FieldReinitializationError{}
 var _ errors.UserError = &FieldReinitializationError{}
+var _ errors.HasErrorCode = &FieldReinitializationError{}
 
 func (*FieldReinitializationError) isSemanticError() {}
 
 func (*FieldReinitializationError) IsUserError() {}
 
+func (*FieldReinitializationError) ErrorCode() errors.ErrorCode {
+	return "C0031"
+}
+
 func (e *FieldReinitializationError) Error() string {
 	return fmt.Sprintf("invalid reinitialization of field: `%s`", e.Name)
 }
@@ -1188,12 +1539,17 @@ type FieldUninitializedError struct {
 
 var _ SemanticError = &FieldUninitializedError{}
 var _ errors.UserError = &FieldUninitializedError{}
+var _ errors.HasErrorCode = &FieldUninitializedError{}
 var _ errors.SecondaryError = &FieldUninitializedError{}
 
 func (*FieldUninitializedError) isSemanticError() {}
 
 func (*FieldUninitializedError) IsUserError() {}
 
+func (*FieldUninitializedError) ErrorCode() errors.ErrorCode {
+	return "C0032"
+}
+
 func (e *FieldUninitializedError) Error() string {
 	return fmt.Sprintf(
 		"missing initialization of field `%s` in type `%s`",
@@ -1233,12 +1589,17 @@ type FieldTypeNotStorableError struct {
 
 var _ SemanticError = &FieldTypeNotStorableError{}
 var _ errors.UserError = &FieldTypeNotStorableError{}
+var _ errors.HasErrorCode = &FieldTypeNotStorableError{}
 var _ errors.SecondaryError = &FieldTypeNotStorableError{}
 
 func (*FieldTypeNotStorableError) isSemanticError() {}
 
 func (*FieldTypeNotStorableError) IsUserError() {}
 
+func (*FieldTypeNotStorableError) ErrorCode() errors.ErrorCode {
+	return "C0033"
+}
+
 func (e *FieldTypeNotStorableError) Error() string {
 	return fmt.Sprintf(
 		"field %s has non-storable type: %s",
@@ -1268,11 +1629,16 @@ type FunctionExpressionInConditionError struct {
 
 var _ SemanticError = &FunctionExpressionInConditionError{}
 var _ errors.UserError = &FunctionExpressionInConditionError{}
+var _ errors.HasErrorCode = &FunctionExpressionInConditionError{}
 
 func (*FunctionExpressionInConditionError) isSemanticError() {}
 
 func (*FunctionExpressionInConditionError) IsUserError() {}
 
+func (*FunctionExpressionInConditionError) ErrorCode() errors.ErrorCode {
+	return "C0034"
+}
+
 func (e *FunctionExpressionInConditionError) Error() string {
 	return "condition contains function"
 }
@@ -1286,11 +1652,16 @@ type MissingReturnValueError struct {
 
 var _ SemanticError = &MissingReturnValueError{}
 var _ errors.UserError = &MissingReturnValueError{}
+var _ errors.HasErrorCode = &MissingReturnValueError{}
 
 func (*MissingReturnValueError) isSemanticError() {}
 
 func (*MissingReturnValueError) IsUserError() {}
 
+func (*MissingReturnValueError) ErrorCode() errors.ErrorCode {
+	return "C0035"
+}
+
 func (e *MissingReturnValueError) Error() string {
 	var typeDescription string
 	if e.ExpectedValueType.IsInvalidType() {
@@ -1315,11 +1686,16 @@ type InvalidImplementationError struct {
 
 var _ SemanticError = &InvalidImplementationError{}
 var _ errors.UserError = &InvalidImplementationError{}
+var _ errors.HasErrorCode = &InvalidImplementationError{}
 
 func (*InvalidImplementationError) isSemanticError() {}
 
 func (*InvalidImplementationError) IsUserError() {}
 
+func (*InvalidImplementationError) ErrorCode() errors.ErrorCode {
+	return "C0036"
+}
+
 func (e *InvalidImplementationError) Error() string {
 	return fmt.Sprintf(
 		"cannot implement %s in %s",
@@ -1345,11 +1721,16 @@ type InvalidConformanceError struct {
 
 var _ SemanticError = &InvalidConformanceError{}
 var _ errors.UserError = &InvalidConformanceError{}
+var _ errors.HasErrorCode = &InvalidConformanceError{}
 
 func (*InvalidConformanceError) isSemanticError() {}
 
 func (*InvalidConformanceError) IsUserError() {}
 
+func (*InvalidConformanceError) ErrorCode() errors.ErrorCode {
+	return "C0037"
+}
+
 func (e *InvalidConformanceError) Error() string {
 	return fmt.Sprintf(
 		"cannot conform to non-interface type: `%s`",
@@ -1366,12 +1747,17 @@ type InvalidEnumRawTypeError struct {
 
 var _ SemanticError = &InvalidEnumRawTypeError{}
 var _ errors.UserError = &InvalidEnumRawTypeError{}
+var _ errors.HasErrorCode = &InvalidEnumRawTypeError{}
 var _ errors.SecondaryError = &InvalidEnumRawTypeError{}
 
 func (*InvalidEnumRawTypeError) isSemanticError() {}
 
 func (*InvalidEnumRawTypeError) IsUserError() {}
 
+func (*InvalidEnumRawTypeError) ErrorCode() errors.ErrorCode {
+	return "C0038"
+}
+
 func (e *InvalidEnumRawTypeError) Error() string {
 	return fmt.Sprintf(
 		"invalid enum raw type: `%s`",
@@ -1391,11 +1777,16 @@ type MissingEnumRawTypeError struct {
 
 var _ SemanticError = &MissingEnumRawTypeError{}
 var _ errors.UserError = &MissingEnumRawTypeError{}
+var _ errors.HasErrorCode = &MissingEnumRawTypeError{}
 
 func (*MissingEnumRawTypeError) isSemanticError() {}
 
 func (*MissingEnumRawTypeError) IsUserError() {}
 
+func (*MissingEnumRawTypeError) ErrorCode() errors.ErrorCode {
+	return "C0039"
+}
+
 func (e *MissingEnumRawTypeError) Error() string {
 	return "missing enum raw type"
 }
@@ -1416,11 +1807,16 @@ type InvalidEnumConformancesError struct {
 
 var _ SemanticError = &InvalidEnumConformancesError{}
 var _ errors.UserError = &InvalidEnumConformancesError{}
+var _ errors.HasErrorCode = &InvalidEnumConformancesError{}
 
 func (*InvalidEnumConformancesError) isSemanticError() {}
 
 func (*InvalidEnumConformancesError) IsUserError() {}
 
+func (*InvalidEnumConformancesError) ErrorCode() errors.ErrorCode {
+	return "C0040"
+}
+
 func (e *InvalidEnumConformancesError) Error() string {
 	return "enums cannot conform to interfaces"
 }
@@ -1452,12 +1848,17 @@ type ConformanceError struct {
 
 var _ SemanticError = &ConformanceError{}
 var _ errors.UserError = &ConformanceError{}
+var _ errors.HasErrorCode = &ConformanceError{}
 var _ errors.SecondaryError = &ConformanceError{}
 
 func (*ConformanceError) isSemanticError() {}
 
 func (*ConformanceError) IsUserError() {}
 
+func (*ConformanceError) ErrorCode() errors.ErrorCode {
+	return "C0041"
+}
+
 func (e *ConformanceError) Error() string {
 	var interfaceDescription string
 	if e.InterfaceTypeIsTypeRequirement {
@@ -1561,11 +1962,16 @@ type DuplicateConformanceError struct {
 
 var _ SemanticError = &DuplicateConformanceError{}
 var _ errors.UserError = &DuplicateConformanceError{}
+var _ errors.HasErrorCode = &DuplicateConformanceError{}
 
 func (*DuplicateConformanceError) isSemanticError() {}
 
 func (*DuplicateConformanceError) IsUserError() {}
 
+func (*DuplicateConformanceError) ErrorCode() errors.ErrorCode {
+	return "C0042"
+}
+
 func (e *DuplicateConformanceError) Error() string {
 	return fmt.Sprintf(
 		"%s `%s` repeats conformance to %s `%s`",
@@ -1585,11 +1991,16 @@ type MultipleInterfaceDefaultImplementationsError struct {
 
 var _ SemanticError = &MultipleInterfaceDefaultImplementationsError{}
 var _ errors.UserError = &MultipleInterfaceDefaultImplementationsError{}
+var _ errors.HasErrorCode = &MultipleInterfaceDefaultImplementationsError{}
 
 func (*MultipleInterfaceDefaultImplementationsError) isSemanticError() {}
 
 func (*MultipleInterfaceDefaultImplementationsError) IsUserError() {}
 
+func (*MultipleInterfaceDefaultImplementationsError) ErrorCode() errors.ErrorCode {
+	return "C0043"
+}
+
 func (e *MultipleInterfaceDefaultImplementationsError) Error() string {
 	return fmt.Sprintf(
 		"%s `%s` has multiple interface default implementations for function `%s`",
@@ -1608,11 +2019,16 @@ type SpecialFunctionDefaultImplementationError struct {
 
 var _ SemanticError = &SpecialFunctionDefaultImplementationError{}
 var _ errors.UserError = &SpecialFunctionDefaultImplementationError{}
+var _ errors.HasErrorCode = &SpecialFunctionDefaultImplementationError{}
 
 func (*SpecialFunctionDefaultImplementationError) isSemanticError() {}
 
 func (*SpecialFunctionDefaultImplementationError) IsUserError() {}
 
+func (*SpecialFunctionDefaultImplementationError) ErrorCode() errors.ErrorCode {
+	return "C0044"
+}
+
 func (e *SpecialFunctionDefaultImplementationError) Error() string {
 	return fmt.Sprintf(
 		"%s may not be defined as a default function on %s %s",
@@ -1639,11 +2055,16 @@ type DefaultFunctionConflictError struct {
 
 var _ SemanticError = &DefaultFunctionConflictError{}
 var _ errors.UserError = &DefaultFunctionConflictError{}
+var _ errors.HasErrorCode = &DefaultFunctionConflictError{}
 
 func (*DefaultFunctionConflictError) isSemanticError() {}
 
 func (*DefaultFunctionConflictError) IsUserError() {}
 
+func (*DefaultFunctionConflictError) ErrorCode() errors.ErrorCode {
+	return "C0045"
+}
+
 func (e *DefaultFunctionConflictError) Error() string {
 	return fmt.Sprintf(
 		"%s `%s` has conflicting requirements for function `%s` ",
@@ -1662,11 +2083,16 @@ type MissingConformanceError struct {
 
 var _ SemanticError = &MissingConformanceError{}
 var _ errors.UserError = &MissingConformanceError{}
+var _ errors.HasErrorCode = &MissingConformanceError{}
 
 func (*MissingConformanceError) isSemanticError() {}
 
 func (*MissingConformanceError) IsUserError() {}
 
+func (*MissingConformanceError) ErrorCode() errors.ErrorCode {
+	return "C0046"
+}
+
 func (e *MissingConformanceError) Error() string {
 	return fmt.Sprintf(
 		"%s `%s` is missing a declaration to required conformance to %s `%s`",
@@ -1686,11 +2112,16 @@ type UnresolvedImportError struct {
 
 var _ SemanticError = &UnresolvedImportError{}
 var _ errors.UserError = &UnresolvedImportError{}
+var _ errors.HasErrorCode = &UnresolvedImportError{}
 
 func (*UnresolvedImportError) isSemanticError() {}
 
 func (*UnresolvedImportError) IsUserError() {}
 
+func (*UnresolvedImportError) ErrorCode() errors.ErrorCode {
+	return "C0047"
+}
+
 func (e *UnresolvedImportError) Error() string {
 	return fmt.Sprintf("import could not be resolved: %s", e.ImportLocation)
 }
@@ -1706,12 +2137,17 @@ type NotExportedError struct {
 
 var _ SemanticError = &NotExportedError{}
 var _ errors.UserError = &NotExportedError{}
+var _ errors.HasErrorCode = &NotExportedError{}
 var _ errors.SecondaryError = &NotExportedError{}
 
 func (*NotExportedError) isSemanticError() {}
 
 func (*NotExportedError) IsUserError() {}
 
+func (*NotExportedError) ErrorCode() errors.ErrorCode {
+	return "C0048"
+}
+
 func (e *NotExportedError) Error() string {
 	return fmt.Sprintf(
 		"cannot find declaration `%s` in `%s`",
@@ -1785,11 +2221,16 @@ type AlwaysFailingNonResourceCastingTypeError struct {
 
 var _ SemanticError = &AlwaysFailingNonResourceCastingTypeError{}
 var _ errors.UserError = &AlwaysFailingNonResourceCastingTypeError{}
+var _ errors.HasErrorCode = &AlwaysFailingNonResourceCastingTypeError{}
 
 func (*AlwaysFailingNonResourceCastingTypeError) isSemanticError() {}
 
 func (*AlwaysFailingNonResourceCastingTypeError) IsUserError() {}
 
+func (*AlwaysFailingNonResourceCastingTypeError) ErrorCode() errors.ErrorCode {
+	return "C0049"
+}
+
 func (e *AlwaysFailingNonResourceCastingTypeError) Error() string {
 	return fmt.Sprintf(
 		"cast of value of resource-type `%s` to non-resource type `%s` will always fail",
@@ -1808,11 +2249,16 @@ type AlwaysFailingResourceCastingTypeError struct {
 
 var _ SemanticError = &AlwaysFailingResourceCastingTypeError{}
 var _ errors.UserError = &AlwaysFailingResourceCastingTypeError{}
+var _ errors.HasErrorCode = &AlwaysFailingResourceCastingTypeError{}
 
 func (*AlwaysFailingResourceCastingTypeError) isSemanticError() {}
 
 func (*AlwaysFailingResourceCastingTypeError) IsUserError() {}
 
+func (*AlwaysFailingResourceCastingTypeError) ErrorCode() errors.ErrorCode {
+	return "C0050"
+}
+
 func (e *AlwaysFailingResourceCastingTypeError) Error() string {
 	return fmt.Sprintf(
 		"cast of value of non-resource-type `%s` to resource type `%s` will always fail",
@@ -1830,11 +2276,16 @@ type UnsupportedOverloadingError struct {
 
 var _ SemanticError = &UnsupportedOverloadingError{}
 var _ errors.UserError = &UnsupportedOverloadingError{}
+var _ errors.HasErrorCode = &UnsupportedOverloadingError{}
 
 func (*UnsupportedOverloadingError) isSemanticError() {}
 
 func (*UnsupportedOverloadingError) IsUserError() {}
 
+func (*UnsupportedOverloadingError) ErrorCode() errors.ErrorCode {
+	return "C0051"
+}
+
 func (e *UnsupportedOverloadingError) Error() string {
 	return fmt.Sprintf(
 		"%s overloading is not supported yet",
@@ -1852,12 +2303,17 @@ type CompositeKindMismatchError struct {
 
 var _ SemanticError = &CompositeKindMismatchError{}
 var _ errors.UserError = &CompositeKindMismatchError{}
+var _ errors.HasErrorCode = &CompositeKindMismatchError{}
 var _ errors.SecondaryError = &CompositeKindMismatchError{}
 
 func (*CompositeKindMismatchError) isSemanticError() {}
 
 func (*CompositeKindMismatchError) IsUserError() {}
 
+func (*CompositeKindMismatchError) ErrorCode() errors.ErrorCode {
+	return "C0052"
+}
+
 func (e *CompositeKindMismatchError) Error() string {
 	return "mismatched composite kinds"
 }
@@ -1881,10 +2337,15 @@ type InvalidIntegerLiteralRangeError struct {
 
 var _ SemanticError = &InvalidIntegerLiteralRangeError{}
 var _ errors.UserError = &InvalidIntegerLiteralRangeError{}
+var _ errors.HasErrorCode = &InvalidIntegerLiteralRangeError{}
 var _ errors.SecondaryError = &InvalidIntegerLiteralRangeError{}
 
 func (*InvalidIntegerLiteralRangeError) IsUserError() {}
 
+func (*InvalidIntegerLiteralRangeError) ErrorCode() errors.ErrorCode {
+	return "C0053"
+}
+
 func (*InvalidIntegerLiteralRangeError) isSemanticError() {}
 
 func (e *InvalidIntegerLiteralRangeError) Error() string {
@@ -1908,11 +2369,16 @@ type InvalidAddressLiteralError struct {
 
 var _ SemanticError = &InvalidAddressLiteralError{}
 var _ errors.UserError = &InvalidAddressLiteralError{}
+var _ errors.HasErrorCode = &InvalidAddressLiteralError{}
 
 func (*InvalidAddressLiteralError) isSemanticError() {}
 
 func (*InvalidAddressLiteralError) IsUserError() {}
 
+func (*InvalidAddressLiteralError) ErrorCode() errors.ErrorCode {
+	return "C0054"
+}
+
 func (e *InvalidAddressLiteralError) Error() string {
 	return "invalid address"
 }
@@ -1930,12 +2396,17 @@ type InvalidFixedPointLiteralRangeError struct {
 
 var _ SemanticError = &InvalidFixedPointLiteralRangeError{}
 var _ errors.UserError = &InvalidFixedPointLiteralRangeError{}
+var _ errors.HasErrorCode = &InvalidFixedPointLiteralRangeError{}
 var _ errors.SecondaryError = &InvalidFixedPointLiteralRangeError{}
 
 func (*InvalidFixedPointLiteralRangeError) isSemanticError() {}
 
 func (*InvalidFixedPointLiteralRangeError) IsUserError() {}
 
+func (*InvalidFixedPointLiteralRangeError) ErrorCode() errors.ErrorCode {
+	return "C0055"
+}
+
 func (e *InvalidFixedPointLiteralRangeError) Error() string {
 	return "fixed-point literal out of range"
 }
@@ -1961,12 +2432,17 @@ type InvalidFixedPointLiteralScaleError struct {
 
 var _ SemanticError = &InvalidFixedPointLiteralScaleError{}
 var _ errors.UserError = &InvalidFixedPointLiteralScaleError{}
+var _ errors.HasErrorCode = &InvalidFixedPointLiteralScaleError{}
 var _ errors.SecondaryError = &InvalidFixedPointLiteralScaleError{}
 
 func (*InvalidFixedPointLiteralScaleError) isSemanticError() {}
 
 func (*InvalidFixedPointLiteralScaleError) IsUserError() {}
 
+func (*InvalidFixedPointLiteralScaleError) ErrorCode() errors.ErrorCode {
+	return "C0056"
+}
+
 func (e *InvalidFixedPointLiteralScaleError) Error() string {
 	return "fixed-point literal scale out of range"
 }
@@ -1987,11 +2463,16 @@ type MissingReturnStatementError struct {
 
 var _ SemanticError = &MissingReturnStatementError{}
 var _ errors.UserError = &MissingReturnStatementError{}
+var _ errors.HasErrorCode = &MissingReturnStatementError{}
 
 func (*MissingReturnStatementError) isSemanticError() {}
 
 func (*MissingReturnStatementError) IsUserError() {}
 
+func (*MissingReturnStatementError) ErrorCode() errors.ErrorCode {
+	return "C0057"
+}
+
 func (e *MissingReturnStatementError) Error() string {
 	return "missing return statement"
 }
@@ -2004,11 +2485,16 @@ type UnsupportedOptionalChainingAssignmentError struct {
 
 var _ SemanticError = &UnsupportedOptionalChainingAssignmentError{}
 var _ errors.UserError = &UnsupportedOptionalChainingAssignmentError{}
+var _ errors.HasErrorCode = &UnsupportedOptionalChainingAssignmentError{}
 
 func (*UnsupportedOptionalChainingAssignmentError) isSemanticError() {}
 
 func (*UnsupportedOptionalChainingAssignmentError) IsUserError() {}
 
+func (*UnsupportedOptionalChainingAssignmentError) ErrorCode() errors.ErrorCode {
+	return "C0058"
+}
+
 func (e *UnsupportedOptionalChainingAssignmentError) Error() string {
 	return "cannot assign to optional chaining expression"
 }
@@ -2021,11 +2507,16 @@ type MissingResourceAnnotationError struct {
 
 var _ SemanticError = &MissingResourceAnnotationError{}
 var _ errors.UserError = &MissingResourceAnnotationError{}
+var _ errors.HasErrorCode = &MissingResourceAnnotationError{}
 
 func (*MissingResourceAnnotationError) isSemanticError() {}
 
 func (*MissingResourceAnnotationError) IsUserError() {}
 
+func (*MissingResourceAnnotationError) ErrorCode() errors.ErrorCode {
+	return "C0059"
+}
+
 func (e *MissingResourceAnnotationError) Error() string {
 	return fmt.Sprintf(
 		"missing resource annotation: `%s`",
@@ -2041,11 +2532,16 @@ type InvalidNestedResourceMoveError struct {
 
 var _ SemanticError = &InvalidNestedResourceMoveError{}
 var _ errors.UserError = &InvalidNestedResourceMoveError{}
+var _ errors.HasErrorCode = &InvalidNestedResourceMoveError{}
 
 func (*InvalidNestedResourceMoveError) isSemanticError() {}
 
 func (*InvalidNestedResourceMoveError) IsUserError() {}
 
+func (*InvalidNestedResourceMoveError) ErrorCode() errors.ErrorCode {
+	return "C0060"
+}
+
 func (e *InvalidNestedResourceMoveError) Error() string {
 	return "cannot move nested resource"
 }
@@ -2058,11 +2554,16 @@ type InvalidResourceAnnotationError struct {
 
 var _ SemanticError = &InvalidResourceAnnotationError{}
 var _ errors.UserError = &InvalidResourceAnnotationError{}
+var _ errors.HasErrorCode = &InvalidResourceAnnotationError{}
 
 func (*InvalidResourceAnnotationError) isSemanticError() {}
 
 func (*InvalidResourceAnnotationError) IsUserError() {}
 
+func (*InvalidResourceAnnotationError) ErrorCode() errors.ErrorCode {
+	return "C0061"
+}
+
 func (e *InvalidResourceAnnotationError) Error() string {
 	return fmt.Sprintf(
 		"invalid resource annotation: `%s`",
@@ -2080,12 +2581,17 @@ type InvalidInterfaceTypeError struct {
 
 var _ SemanticError = &InvalidInterfaceTypeError{}
 var _ errors.UserError = &InvalidInterfaceTypeError{}
+var _ errors.HasErrorCode = &InvalidInterfaceTypeError{}
 var _ errors.SecondaryError = &InvalidInterfaceTypeError{}
 
 func (*InvalidInterfaceTypeError) isSemanticError() {}
 
 func (*InvalidInterfaceTypeError) IsUserError() {}
 
+func (*InvalidInterfaceTypeError) ErrorCode() errors.ErrorCode {
+	return "C0062"
+}
+
 func (e *InvalidInterfaceTypeError) Error() string {
 	return "invalid use of interface as type"
 }
@@ -2107,11 +2613,16 @@ type InvalidInterfaceDeclarationError struct {
 
 var _ SemanticError = &InvalidInterfaceDeclarationError{}
 var _ errors.UserError = &InvalidInterfaceDeclarationError{}
+var _ errors.HasErrorCode = &InvalidInterfaceDeclarationError{}
 
 func (*InvalidInterfaceDeclarationError) isSemanticError() {}
 
 func (*InvalidInterfaceDeclarationError) IsUserError() {}
 
+func (*InvalidInterfaceDeclarationError) ErrorCode() errors.ErrorCode {
+	return "C0063"
+}
+
 func (e *InvalidInterfaceDeclarationError) Error() string {
 	return fmt.Sprintf(
 		"%s interfaces are not supported",
@@ -2129,12 +2640,18 @@ type IncorrectTransferOperationError struct {
 
 var _ SemanticError = &IncorrectTransferOperationError{}
 var _ errors.UserError = &IncorrectTransferOperationError{}
+var _ errors.HasErrorCode = &IncorrectTransferOperationError{}
 var _ errors.SecondaryError = &IncorrectTransferOperationError{}
+var _ HasSuggestedFixes = &IncorrectTransferOperationError{}
 
 func (*IncorrectTransferOperationError) isSemanticError() {}
 
 func (*IncorrectTransferOperationError) IsUserError() {}
 
+func (*IncorrectTransferOperationError) ErrorCode() errors.ErrorCode {
+	return "C0064"
+}
+
 func (e *IncorrectTransferOperationError) Error() string {
 	return "incorrect transfer operation"
 }
@@ -2146,6 +2663,22 @@ func (e *IncorrectTransferOperationError) SecondaryError() string {
 	)
 }
 
+func (e *IncorrectTransferOperationError) SuggestFixes(_ string) []SuggestedFix {
+	operator := e.ExpectedOperation.Operator()
+
+	return []SuggestedFix{
+		{
+			Message: fmt.Sprintf("replace with `%s`", operator),
+			TextEdits: []TextEdit{
+				{
+					Replacement: operator,
+					Range:       e.Range,
+				},
+			},
+		},
+	}
+}
+
 // InvalidConstructionError
 
 type InvalidConstructionError struct {
@@ -2154,11 +2687,16 @@ type InvalidConstructionError struct {
 
 var _ SemanticError = &InvalidConstructionError{}
 var _ errors.UserError = &InvalidConstructionError{}
+var _ errors.HasErrorCode = &InvalidConstructionError{}
 
 func (*InvalidConstructionError) isSemanticError() {}
 
 func (*InvalidConstructionError) IsUserError() {}
 
+func (*InvalidConstructionError) ErrorCode() errors.ErrorCode {
+	return "C0065"
+}
+
 func (e *InvalidConstructionError) Error() string {
 	return "cannot create value: not a resource"
 }
@@ -2171,11 +2709,16 @@ type InvalidDestructionError struct {
 
 var _ SemanticError = &InvalidDestructionError{}
 var _ errors.UserError = &InvalidDestructionError{}
+var _ errors.HasErrorCode = &InvalidDestructionError{}
 
 func (*InvalidDestructionError) isSemanticError() {}
 
 func (*InvalidDestructionError) IsUserError() {}
 
+func (*InvalidDestructionError) ErrorCode() errors.ErrorCode {
+	return "C0066"
+}
+
 func (e *InvalidDestructionError) Error() string {
 	return "cannot destroy value: not a resource"
 }
@@ -2183,20 +2726,116 @@ func (e *InvalidDestructionError) Error() string {
 // ResourceLossError
 
 type ResourceLossError struct {
+	// DestroyPos is the position at which the lost resource could be destroyed, if known:
+	// For a variable, the position before which a statement which destroys it could be inserted;
+	// otherwise, the start of the expression which results in the resource
+	DestroyPos *ast.Position
+	// Variable is the name of the variable which has the lost resource, if any
+	Variable string
 	ast.Range
 }
 
 var _ SemanticError = &ResourceLossError{}
 var _ errors.UserError = &ResourceLossError{}
+var _ errors.HasErrorCode = &ResourceLossError{}
+var _ HasSuggestedFixes = &ResourceLossError{}
 
 func (*ResourceLossError) isSemanticError() {}
 
 func (*ResourceLossError) IsUserError() {}
 
+func (*ResourceLossError) ErrorCode() errors.ErrorCode {
+	return "C0067"
+}
+
 func (e *ResourceLossError) Error() string {
 	return "loss of resource"
 }
 
+func (e *ResourceLossError) SuggestFixes(code string) []SuggestedFix {
+	if e.DestroyPos == nil {
+		return nil
+	}
+
+	destroyPos := *e.DestroyPos
+
+	if e.Variable == "" {
+		return []SuggestedFix{
+			{
+				Message: "destroy the resource",
+				TextEdits: []TextEdit{
+					{
+						Insertion: "destroy ",
+						Range:     ast.NewUnmeteredRange(destroyPos, destroyPos),
+					},
+				},
+			},
+		}
+	}
+
+	// Insert a statement which destroys the variable on a new line,
+	// before the statement at which the resource is lost, or before the end of the block.
+	// Only suggest the fix if nothing but whitespace precedes the destroy position on its line
+
+	if destroyPos.Offset > len(code) {
+		return nil
+	}
+
+	lineStartOffset := destroyPos.Offset
+	for lineStartOffset > 0 && isIndentation(code[lineStartOffset-1]) {
+		lineStartOffset--
+	}
+	if lineStartOffset > 0 && code[lineStartOffset-1] != '\n' {
+		return nil
+	}
+
+	indentation := code[lineStartOffset:destroyPos.Offset]
+
+	// If the resource is lost at the end of the block,
+	// indent the statement like the declaration of the variable
+
+	if destroyPos.Offset < len(code) && code[destroyPos.Offset] == '}' {
+		indentation = lineIndentation(code, e.StartPos.Offset)
+	}
+
+	insertionPos := ast.Position{
+		Offset: lineStartOffset,
+		Line:   destroyPos.Line,
+	}
+
+	return []SuggestedFix{
+		{
+			Message: fmt.Sprintf("destroy `%s`", e.Variable),
+			TextEdits: []TextEdit{
+				{
+					Insertion: fmt.Sprintf("%sdestroy %s\n", indentation, e.Variable),
+					Range:     ast.NewUnmeteredRange(insertionPos, insertionPos),
+				},
+			},
+		},
+	}
+}
+
+func isIndentation(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+// lineIndentation returns the indentation of the line which contains the given offset
+func lineIndentation(code string, offset int) string {
+	if offset > len(code) {
+		return ""
+	}
+
+	lineStartOffset := strings.LastIndexByte(code[:offset], '\n') + 1
+
+	lineEndOffset := lineStartOffset
+	for lineEndOffset < len(code) && isIndentation(code[lineEndOffset]) {
+		lineEndOffset++
+	}
+
+	return code[lineStartOffset:lineEndOffset]
+}
+
 // ResourceUseAfterInvalidationError
 
 type ResourceUseAfterInvalidationError struct {
@@ -2206,12 +2845,17 @@ type ResourceUseAfterInvalidationError struct {
 
 var _ SemanticError = &ResourceUseAfterInvalidationError{}
 var _ errors.UserError = &ResourceUseAfterInvalidationError{}
+var _ errors.HasErrorCode = &ResourceUseAfterInvalidationError{}
 var _ errors.SecondaryError = &ResourceUseAfterInvalidationError{}
 
 func (*ResourceUseAfterInvalidationError) isSemanticError() {}
 
 func (*ResourceUseAfterInvalidationError) IsUserError() {}
 
+func (*ResourceUseAfterInvalidationError) ErrorCode() errors.ErrorCode {
+	return "C0068"
+}
+
 func (e *ResourceUseAfterInvalidationError) Error() string {
 	return fmt.Sprintf(
 		"use of previously %s resource",
@@ -2261,12 +2905,17 @@ type MissingCreateError struct {
 
 var _ SemanticError = &MissingCreateError{}
 var _ errors.UserError = &MissingCreateError{}
+var _ errors.HasErrorCode = &MissingCreateError{}
 var _ errors.SecondaryError = &MissingCreateError{}
 
 func (*MissingCreateError) isSemanticError() {}
 
 func (*MissingCreateError) IsUserError() {}
 
+func (*MissingCreateError) ErrorCode() errors.ErrorCode {
+	return "C0069"
+}
+
 func (e *MissingCreateError) Error() string {
 	return "cannot create resource"
 }
@@ -2283,11 +2932,17 @@ type MissingMoveOperationError struct {
 
 var _ SemanticError = &MissingMoveOperationError{}
 var _ errors.UserError = &MissingMoveOperationError{}
+var _ errors.HasErrorCode = &MissingMoveOperationError{}
+var _ HasSuggestedFixes = &MissingMoveOperationError{}
 
 func (*MissingMoveOperationError) isSemanticError() {}
 
 func (*MissingMoveOperationError) IsUserError() {}
 
+func (*MissingMoveOperationError) ErrorCode() errors.ErrorCode {
+	return "C0070"
+}
+
 func (e *MissingMoveOperationError) Error() string {
 	return "missing move operation: `<-`"
 }
@@ -2300,6 +2955,20 @@ func (e *MissingMoveOperationError) EndPosition(common.MemoryGauge) ast.Position
 	return e.Pos
 }
 
+func (e *MissingMoveOperationError) SuggestFixes(_ string) []SuggestedFix {
+	return []SuggestedFix{
+		{
+			Message: "insert move operator",
+			TextEdits: []TextEdit{
+				{
+					Insertion: "<-",
+					Range:     ast.NewUnmeteredRange(e.Pos, e.Pos),
+				},
+			},
+		},
+	}
+}
+
 // InvalidMoveOperationError
 
 type InvalidMoveOperationError struct {
@@ -2308,12 +2977,18 @@ type InvalidMoveOperationError struct {
 
 var _ SemanticError = &InvalidMoveOperationError{}
 var _ errors.UserError = &InvalidMoveOperationError{}
+var _ errors.HasErrorCode = &InvalidMoveOperationError{}
 var _ errors.SecondaryError = &InvalidMoveOperationError{}
+var _ HasSuggestedFixes = &InvalidMoveOperationError{}
 
 func (*InvalidMoveOperationError) isSemanticError() {}
 
 func (*InvalidMoveOperationError) IsUserError() {}
 
+func (*InvalidMoveOperationError) ErrorCode() errors.ErrorCode {
+	return "C0071"
+}
+
 func (e *InvalidMoveOperationError) Error() string {
 	return "invalid move operation for non-resource"
 }
@@ -2322,6 +2997,26 @@ func (e *InvalidMoveOperationError) SecondaryError() string {
 	return "unexpected `<-`"
 }
 
+func (e *InvalidMoveOperationError) SuggestFixes(_ string) []SuggestedFix {
+	// The range ends at the start of the moved expression,
+	// so remove everything before it
+
+	return []SuggestedFix{
+		{
+			Message: "remove move operator",
+			TextEdits: []TextEdit{
+				{
+					Replacement: "",
+					Range: ast.NewUnmeteredRange(
+						e.StartPos,
+						e.EndPos.Shifted(nil, -1),
+					),
+				},
+			},
+		},
+	}
+}
+
 // ResourceCapturingError
 
 type ResourceCapturingError struct {
@@ -2331,11 +3026,16 @@ type ResourceCapturingError struct {
 
 var _ SemanticError = &ResourceCapturingError{}
 var _ errors.UserError = &ResourceCapturingError{}
+var _ errors.HasErrorCode = &ResourceCapturingError{}
 
 func (*ResourceCapturingError) isSemanticError() {}
 
 func (*ResourceCapturingError) IsUserError() {}
 
+func (*ResourceCapturingError) ErrorCode() errors.ErrorCode {
+	return "C0072"
+}
+
 func (e *ResourceCapturingError) Error() string {
 	return fmt.Sprintf("cannot capture resource in closure: `%s`", e.Name)
 }
@@ -2359,11 +3059,16 @@ type InvalidResourceFieldError struct {
 
 var _ SemanticError = &InvalidResourceFieldError{}
 var _ errors.UserError = &InvalidResourceFieldError{}
+var _ errors.HasErrorCode = &InvalidResourceFieldError{}
 
 func (*InvalidResourceFieldError) isSemanticError() {}
 
 func (*InvalidResourceFieldError) IsUserError() {}
 
+func (*InvalidResourceFieldError) ErrorCode() errors.ErrorCode {
+	return "C0073"
+}
+
 func (e *InvalidResourceFieldError) Error() string {
 	return fmt.Sprintf(
 		"invalid resource field in %s: `%s`",
@@ -2390,12 +3095,17 @@ type InvalidSwapExpressionError struct {
 
 var _ SemanticError = &InvalidSwapExpressionError{}
 var _ errors.UserError = &InvalidSwapExpressionError{}
+var _ errors.HasErrorCode = &InvalidSwapExpressionError{}
 var _ errors.SecondaryError = &InvalidSwapExpressionError{}
 
 func (*InvalidSwapExpressionError) isSemanticError() {}
 
 func (*InvalidSwapExpressionError) IsUserError() {}
 
+func (*InvalidSwapExpressionError) ErrorCode() errors.ErrorCode {
+	return "C0074"
+}
+
 func (e *InvalidSwapExpressionError) Error() string {
 	return fmt.Sprintf(
 		"invalid %s-hand side of swap",
@@ -2416,11 +3126,16 @@ type InvalidEventParameterTypeError struct {
 
 var _ SemanticError = &InvalidEventParameterTypeError{}
 var _ errors.UserError = &InvalidEventParameterTypeError{}
+var _ errors.HasErrorCode = &InvalidEventParameterTypeError{}
 
 func (*InvalidEventParameterTypeError) isSemanticError() {}
 
 func (*InvalidEventParameterTypeError) IsUserError() {}
 
+func (*InvalidEventParameterTypeError) ErrorCode() errors.ErrorCode {
+	return "C0075"
+}
+
 func (e *InvalidEventParameterTypeError) Error() string {
 	return fmt.Sprintf(
 		"unsupported event parameter type: `%s`",
@@ -2436,11 +3151,16 @@ type InvalidEventUsageError struct {
 
 var _ SemanticError = &InvalidEventUsageError{}
 var _ errors.UserError = &InvalidEventUsageError{}
+var _ errors.HasErrorCode = &InvalidEventUsageError{}
 
 func (*InvalidEventUsageError) isSemanticError() {}
 
 func (*InvalidEventUsageError) IsUserError() {}
 
+func (*InvalidEventUsageError) ErrorCode() errors.ErrorCode {
+	return "C0076"
+}
+
 func (e *InvalidEventUsageError) Error() string {
 	return "events can only be invoked in an `emit` statement"
 }
@@ -2454,11 +3174,16 @@ type EmitNonEventError struct {
 
 var _ SemanticError = &EmitNonEventError{}
 var _ errors.UserError = &EmitNonEventError{}
+var _ errors.HasErrorCode = &EmitNonEventError{}
 
 func (*EmitNonEventError) isSemanticError() {}
 
 func (*EmitNonEventError) IsUserError() {}
 
+func (*EmitNonEventError) ErrorCode() errors.ErrorCode {
+	return "C0077"
+}
+
 func (e *EmitNonEventError) Error() string {
 	return fmt.Sprintf(
 		"cannot emit non-event type: `%s`",
@@ -2475,11 +3200,16 @@ type EmitImportedEventError struct {
 
 var _ SemanticError = &EmitImportedEventError{}
 var _ errors.UserError = &EmitImportedEventError{}
+var _ errors.HasErrorCode = &EmitImportedEventError{}
 
 func (*EmitImportedEventError) isSemanticError() {}
 
 func (*EmitImportedEventError) IsUserError() {}
 
+func (*EmitImportedEventError) ErrorCode() errors.ErrorCode {
+	return "C0078"
+}
+
 func (e *EmitImportedEventError) Error() string {
 	return fmt.Sprintf(
 		"cannot emit imported event type: `%s`",
@@ -2495,12 +3225,17 @@ type InvalidResourceAssignmentError struct {
 
 var _ SemanticError = &InvalidResourceAssignmentError{}
 var _ errors.UserError = &InvalidResourceAssignmentError{}
+var _ errors.HasErrorCode = &InvalidResourceAssignmentError{}
 var _ errors.SecondaryError = &InvalidResourceAssignmentError{}
 
 func (*InvalidResourceAssignmentError) isSemanticError() {}
 
 func (*InvalidResourceAssignmentError) IsUserError() {}
 
+func (*InvalidResourceAssignmentError) ErrorCode() errors.ErrorCode {
+	return "C0079"
+}
+
 func (e *InvalidResourceAssignmentError) Error() string {
 	return "cannot assign to resource-typed target"
 }
@@ -2517,11 +3252,16 @@ type InvalidDestructorError struct {
 
 var _ SemanticError = &InvalidDestructorError{}
 var _ errors.UserError = &InvalidDestructorError{}
+var _ errors.HasErrorCode = &InvalidDestructorError{}
 
 func (*InvalidDestructorError) isSemanticError() {}
 
 func (*InvalidDestructorError) IsUserError() {}
 
+func (*InvalidDestructorError) ErrorCode() errors.ErrorCode {
+	return "C0080"
+}
+
 func (e *InvalidDestructorError) Error() string {
 	return "cannot declare destructor for non-resource"
 }
@@ -2536,11 +3276,16 @@ type MissingDestructorError struct {
 
 var _ SemanticError = &MissingDestructorError{}
 var _ errors.UserError = &MissingDestructorError{}
+var _ errors.HasErrorCode = &MissingDestructorError{}
 
 func (*MissingDestructorError) isSemanticError() {}
 
 func (*MissingDestructorError) IsUserError() {}
 
+func (*MissingDestructorError) ErrorCode() errors.ErrorCode {
+	return "C0081"
+}
+
 func (e *MissingDestructorError) Error() string {
 	return fmt.Sprintf(
 		"missing destructor for resource field `%s` in type `%s`",
@@ -2565,12 +3310,17 @@ type InvalidDestructorParametersError struct {
 
 var _ SemanticError = &InvalidDestructorParametersError{}
 var _ errors.UserError = &InvalidDestructorParametersError{}
+var _ errors.HasErrorCode = &InvalidDestructorParametersError{}
 var _ errors.SecondaryError = &InvalidDestructorParametersError{}
 
 func (*InvalidDestructorParametersError) isSemanticError() {}
 
 func (*InvalidDestructorParametersError) IsUserError() {}
 
+func (*InvalidDestructorParametersError) ErrorCode() errors.ErrorCode {
+	return "C0082"
+}
+
 func (e *InvalidDestructorParametersError) Error() string {
 	return "invalid parameters for destructor"
 }
@@ -2589,12 +3339,17 @@ type ResourceFieldNotInvalidatedError struct {
 
 var _ SemanticError = &ResourceFieldNotInvalidatedError{}
 var _ errors.UserError = &ResourceFieldNotInvalidatedError{}
+var _ errors.HasErrorCode = &ResourceFieldNotInvalidatedError{}
 var _ errors.SecondaryError = &ResourceFieldNotInvalidatedError{}
 
 func (*ResourceFieldNotInvalidatedError) isSemanticError() {}
 
 func (*ResourceFieldNotInvalidatedError) IsUserError() {}
 
+func (*ResourceFieldNotInvalidatedError) ErrorCode() errors.ErrorCode {
+	return "C0083"
+}
+
 func (e *ResourceFieldNotInvalidatedError) Error() string {
 	return fmt.Sprintf(
 		"field `%s` of type `%s` is not invalidated (moved or destroyed)",
@@ -2625,11 +3380,16 @@ type UninitializedFieldAccessError struct {
 
 var _ SemanticError = &UninitializedFieldAccessError{}
 var _ errors.UserError = &UninitializedFieldAccessError{}
+var _ errors.HasErrorCode = &UninitializedFieldAccessError{}
 
 func (*UninitializedFieldAccessError) isSemanticError() {}
 
 func (*UninitializedFieldAccessError) IsUserError() {}
 
+func (*UninitializedFieldAccessError) ErrorCode() errors.ErrorCode {
+	return "C0084"
+}
+
 func (e *UninitializedFieldAccessError) Error() string {
 	return fmt.Sprintf(
 		"cannot access uninitialized field: `%s`",
@@ -2654,12 +3414,17 @@ type UnreachableStatementError struct {
 
 var _ SemanticError = &UnreachableStatementError{}
 var _ errors.UserError = &UnreachableStatementError{}
+var _ errors.HasErrorCode = &UnreachableStatementError{}
 var _ errors.SecondaryError = &UnreachableStatementError{}
 
 func (*UnreachableStatementError) isSemanticError() {}
 
 func (*UnreachableStatementError) IsUserError() {}
 
+func (*UnreachableStatementError) ErrorCode() errors.ErrorCode {
+	return "C0085"
+}
+
 func (e *UnreachableStatementError) Error() string {
 	return "unreachable statement"
 }
@@ -2677,11 +3442,16 @@ type UninitializedUseError struct {
 
 var _ SemanticError = &UninitializedUseError{}
 var _ errors.UserError = &UninitializedUseError{}
+var _ errors.HasErrorCode = &UninitializedUseError{}
 
 func (*UninitializedUseError) isSemanticError() {}
 
 func (*UninitializedUseError) IsUserError() {}
 
+func (*UninitializedUseError) ErrorCode() errors.ErrorCode {
+	return "C0086"
+}
+
 func (e *UninitializedUseError) Error() string {
 	return fmt.Sprintf(
 		"cannot use incompletely initialized value: `%s`",
@@ -2708,11 +3478,16 @@ type InvalidResourceArrayMemberError struct {
 
 var _ SemanticError = &InvalidResourceArrayMemberError{}
 var _ errors.UserError = &InvalidResourceArrayMemberError{}
+var _ errors.HasErrorCode = &InvalidResourceArrayMemberError{}
 
 func (*InvalidResourceArrayMemberError) isSemanticError() {}
 
 func (*InvalidResourceArrayMemberError) IsUserError() {}
 
+func (*InvalidResourceArrayMemberError) ErrorCode() errors.ErrorCode {
+	return "C0087"
+}
+
 func (e *InvalidResourceArrayMemberError) Error() string {
 	return fmt.Sprintf(
 		"%s `%s` is not available for resource arrays",
@@ -2731,11 +3506,16 @@ type InvalidResourceDictionaryMemberError struct {
 
 var _ SemanticError = &InvalidResourceDictionaryMemberError{}
 var _ errors.UserError = &InvalidResourceDictionaryMemberError{}
+var _ errors.HasErrorCode = &InvalidResourceDictionaryMemberError{}
 
 func (*InvalidResourceDictionaryMemberError) isSemanticError() {}
 
 func (*InvalidResourceDictionaryMemberError) IsUserError() {}
 
+func (*InvalidResourceDictionaryMemberError) ErrorCode() errors.ErrorCode {
+	return "C0088"
+}
+
 func (e *InvalidResourceDictionaryMemberError) Error() string {
 	return fmt.Sprintf(
 		"%s `%s` is not available for resource dictionaries",
@@ -2754,11 +3534,16 @@ type InvalidResourceOptionalMemberError struct {
 
 var _ SemanticError = &InvalidResourceOptionalMemberError{}
 var _ errors.UserError = &InvalidResourceOptionalMemberError{}
+var _ errors.HasErrorCode = &InvalidResourceOptionalMemberError{}
 
 func (*InvalidResourceOptionalMemberError) isSemanticError() {}
 
 func (*InvalidResourceOptionalMemberError) IsUserError() {}
 
+func (*InvalidResourceOptionalMemberError) ErrorCode() errors.ErrorCode {
+	return "C0089"
+}
+
 func (e *InvalidResourceOptionalMemberError) Error() string {
 	return fmt.Sprintf(
 		"%s `%s` is not available for resource optionals",
@@ -2776,12 +3561,17 @@ type NonReferenceTypeReferenceError struct {
 
 var _ SemanticError = &NonReferenceTypeReferenceError{}
 var _ errors.UserError = &NonReferenceTypeReferenceError{}
+var _ errors.HasErrorCode = &NonReferenceTypeReferenceError{}
 var _ errors.SecondaryError = &NonReferenceTypeReferenceError{}
 
 func (*NonReferenceTypeReferenceError) isSemanticError() {}
 
 func (*NonReferenceTypeReferenceError) IsUserError() {}
 
+func (*NonReferenceTypeReferenceError) ErrorCode() errors.ErrorCode {
+	return "C0090"
+}
+
 func (e *NonReferenceTypeReferenceError) Error() string {
 	return "cannot create reference"
 }
@@ -2802,11 +3592,16 @@ type InvalidResourceCreationError struct {
 
 var _ SemanticError = &InvalidResourceCreationError{}
 var _ errors.UserError = &InvalidResourceCreationError{}
+var _ errors.HasErrorCode = &InvalidResourceCreationError{}
 
 func (*InvalidResourceCreationError) isSemanticError() {}
 
 func (*InvalidResourceCreationError) IsUserError() {}
 
+func (*InvalidResourceCreationError) ErrorCode() errors.ErrorCode {
+	return "C0091"
+}
+
 func (e *InvalidResourceCreationError) Error() string {
 	return fmt.Sprintf(
 		"cannot create resource type outside of containing contract: `%s`",
@@ -2823,12 +3618,17 @@ type NonResourceTypeError struct {
 
 var _ SemanticError = &NonResourceTypeError{}
 var _ errors.UserError = &NonResourceTypeError{}
+var _ errors.HasErrorCode = &NonResourceTypeError{}
 var _ errors.SecondaryError = &NonResourceTypeError{}
 
 func (*NonResourceTypeError) isSemanticError() {}
 
 func (*NonResourceTypeError) IsUserError() {}
 
+func (*NonResourceTypeError) ErrorCode() errors.ErrorCode {
+	return "C0092"
+}
+
 func (e *NonResourceTypeError) Error() string {
 	return "invalid type"
 }
@@ -2848,11 +3648,16 @@ type InvalidAssignmentTargetError struct {
 
 var _ SemanticError = &InvalidAssignmentTargetError{}
 var _ errors.UserError = &InvalidAssignmentTargetError{}
+var _ errors.HasErrorCode = &InvalidAssignmentTargetError{}
 
 func (*InvalidAssignmentTargetError) isSemanticError() {}
 
 func (*InvalidAssignmentTargetError) IsUserError() {}
 
+func (*InvalidAssignmentTargetError) ErrorCode() errors.ErrorCode {
+	return "C0093"
+}
+
 func (e *InvalidAssignmentTargetError) Error() string {
 	return "cannot assign to unassignable expression"
 }
@@ -2865,11 +3670,16 @@ type ResourceMethodBindingError struct {
 
 var _ SemanticError = &ResourceMethodBindingError{}
 var _ errors.UserError = &ResourceMethodBindingError{}
+var _ errors.HasErrorCode = &ResourceMethodBindingError{}
 
 func (*ResourceMethodBindingError) isSemanticError() {}
 
 func (*ResourceMethodBindingError) IsUserError() {}
 
+func (*ResourceMethodBindingError) ErrorCode() errors.ErrorCode {
+	return "C0094"
+}
+
 func (e *ResourceMethodBindingError) Error() string {
 	return "cannot create bound method for resource"
 }
@@ -2883,11 +3693,16 @@ type InvalidDictionaryKeyTypeError struct {
 
 var _ SemanticError = &InvalidDictionaryKeyTypeError{}
 var _ errors.UserError = &InvalidDictionaryKeyTypeError{}
+var _ errors.HasErrorCode = &InvalidDictionaryKeyTypeError{}
 
 func (*InvalidDictionaryKeyTypeError) isSemanticError() {}
 
 func (*InvalidDictionaryKeyTypeError) IsUserError() {}
 
+func (*InvalidDictionaryKeyTypeError) ErrorCode() errors.ErrorCode {
+	return "C0095"
+}
+
 func (e *InvalidDictionaryKeyTypeError) Error() string {
 	return fmt.Sprintf(
 		"cannot use type as dictionary key type: `%s`",
@@ -2903,11 +3718,16 @@ type MissingFunctionBodyError struct {
 
 var _ SemanticError = &MissingFunctionBodyError{}
 var _ errors.UserError = &MissingFunctionBodyError{}
+var _ errors.HasErrorCode = &MissingFunctionBodyError{}
 
 func (*MissingFunctionBodyError) isSemanticError() {}
 
 func (*MissingFunctionBodyError) IsUserError() {}
 
+func (*MissingFunctionBodyError) ErrorCode() errors.ErrorCode {
+	return "C0096"
+}
+
 func (e *MissingFunctionBodyError) Error() string {
 	return "missing function implementation"
 }
@@ -2929,11 +3749,16 @@ type InvalidOptionalChainingError struct {
 
 var _ SemanticError = &InvalidOptionalChainingError{}
 var _ errors.UserError = &InvalidOptionalChainingError{}
+var _ errors.HasErrorCode = &InvalidOptionalChainingError{}
 
 func (*InvalidOptionalChainingError) isSemanticError() {}
 
 func (*InvalidOptionalChainingError) IsUserError() {}
 
+func (*InvalidOptionalChainingError) ErrorCode() errors.ErrorCode {
+	return "C0097"
+}
+
 func (e *InvalidOptionalChainingError) Error() string {
 	return fmt.Sprintf(
 		"cannot use optional chaining: type `%s` is not optional",
@@ -2952,11 +3777,16 @@ type InvalidAccessError struct {
 
 var _ SemanticError = &InvalidAccessError{}
 var _ errors.UserError = &InvalidAccessError{}
+var _ errors.HasErrorCode = &InvalidAccessError{}
 
 func (*InvalidAccessError) isSemanticError() {}
 
 func (*InvalidAccessError) IsUserError() {}
 
+func (*InvalidAccessError) ErrorCode() errors.ErrorCode {
+	return "C0098"
+}
+
 func (e *InvalidAccessError) Error() string {
 	return fmt.Sprintf(
 		"cannot access `%s`: %s has %s access",
@@ -2977,12 +3807,17 @@ type InvalidAssignmentAccessError struct {
 
 var _ SemanticError = &InvalidAssignmentAccessError{}
 var _ errors.UserError = &InvalidAssignmentAccessError{}
+var _ errors.HasErrorCode = &InvalidAssignmentAccessError{}
 var _ errors.SecondaryError = &InvalidAssignmentAccessError{}
 
 func (*InvalidAssignmentAccessError) isSemanticError() {}
 
 func (*InvalidAssignmentAccessError) IsUserError() {}
 
+func (*InvalidAssignmentAccessError) ErrorCode() errors.ErrorCode {
+	return "C0099"
+}
+
 func (e *InvalidAssignmentAccessError) Error() string {
 	return fmt.Sprintf(
 		"cannot assign to `%s`: %s has %s access",
@@ -3008,12 +3843,17 @@ type InvalidCharacterLiteralError struct {
 
 var _ SemanticError = &InvalidCharacterLiteralError{}
 var _ errors.UserError = &InvalidCharacterLiteralError{}
+var _ errors.HasErrorCode = &InvalidCharacterLiteralError{}
 var _ errors.SecondaryError = &InvalidCharacterLiteralError{}
 
 func (*InvalidCharacterLiteralError) isSemanticError() {}
 
 func (*InvalidCharacterLiteralError) IsUserError() {}
 
+func (*InvalidCharacterLiteralError) ErrorCode() errors.ErrorCode {
+	return "C0100"
+}
+
 func (e *InvalidCharacterLiteralError) Error() string {
 	return "character literal has invalid length"
 }
@@ -3032,11 +3872,16 @@ type InvalidFailableResourceDowncastOutsideOptionalBindingError struct {
 
 var _ SemanticError = &InvalidFailableResourceDowncastOutsideOptionalBindingError{}
 var _ errors.UserError = &InvalidFailableResourceDowncastOutsideOptionalBindingError{}
+var _ errors.HasErrorCode = &InvalidFailableResourceDowncastOutsideOptionalBindingError{}
 
 func (*InvalidFailableResourceDowncastOutsideOptionalBindingError) isSemanticError() {}
 
 func (*InvalidFailableResourceDowncastOutsideOptionalBindingError) IsUserError() {}
 
+func (*InvalidFailableResourceDowncastOutsideOptionalBindingError) ErrorCode() errors.ErrorCode {
+	return "C0101"
+}
+
 func (e *InvalidFailableResourceDowncastOutsideOptionalBindingError) Error() string {
 	return "cannot failably downcast resource type outside of optional binding"
 }
@@ -3049,12 +3894,17 @@ type InvalidNonIdentifierFailableResourceDowncast struct {
 
 var _ SemanticError = &InvalidNonIdentifierFailableResourceDowncast{}
 var _ errors.UserError = &InvalidNonIdentifierFailableResourceDowncast{}
+var _ errors.HasErrorCode = &InvalidNonIdentifierFailableResourceDowncast{}
 var _ errors.SecondaryError = &InvalidNonIdentifierFailableResourceDowncast{}
 
 func (*InvalidNonIdentifierFailableResourceDowncast) isSemanticError() {}
 
 func (*InvalidNonIdentifierFailableResourceDowncast) IsUserError() {}
 
+func (*InvalidNonIdentifierFailableResourceDowncast) ErrorCode() errors.ErrorCode {
+	return "C0102"
+}
+
 func (e *InvalidNonIdentifierFailableResourceDowncast) Error() string {
 	return "cannot failably downcast non-identifier resource"
 }
@@ -3071,11 +3921,16 @@ type ReadOnlyTargetAssignmentError struct {
 
 var _ SemanticError = &ReadOnlyTargetAssignmentError{}
 var _ errors.UserError = &ReadOnlyTargetAssignmentError{}
+var _ errors.HasErrorCode = &ReadOnlyTargetAssignmentError{}
 
 func (*ReadOnlyTargetAssignmentError) isSemanticError() {}
 
 func (*ReadOnlyTargetAssignmentError) IsUserError() {}
 
+func (*ReadOnlyTargetAssignmentError) ErrorCode() errors.ErrorCode {
+	return "C0103"
+}
+
 func (e *ReadOnlyTargetAssignmentError) Error() string {
 	return "cannot assign to read-only target"
 }
@@ -3089,12 +3944,17 @@ type InvalidTransactionBlockError struct {
 
 var _ SemanticError = &InvalidTransactionBlockError{}
 var _ errors.UserError = &InvalidTransactionBlockError{}
+var _ errors.HasErrorCode = &InvalidTransactionBlockError{}
 var _ errors.SecondaryError = &InvalidTransactionBlockError{}
 
 func (*InvalidTransactionBlockError) isSemanticError() {}
 
 func (*InvalidTransactionBlockError) IsUserError() {}
 
+func (*InvalidTransactionBlockError) ErrorCode() errors.ErrorCode {
+	return "C0104"
+}
+
 func (e *InvalidTransactionBlockError) Error() string {
 	return "invalid transaction block"
 }
@@ -3124,11 +3984,16 @@ type TransactionMissingPrepareError struct {
 
 var _ SemanticError = &TransactionMissingPrepareError{}
 var _ errors.UserError = &TransactionMissingPrepareError{}
+var _ errors.HasErrorCode = &TransactionMissingPrepareError{}
 
 func (*TransactionMissingPrepareError) isSemanticError() {}
 
 func (*TransactionMissingPrepareError) IsUserError() {}
 
+func (*TransactionMissingPrepareError) ErrorCode() errors.ErrorCode {
+	return "C0105"
+}
+
 func (e *TransactionMissingPrepareError) Error() string {
 	return fmt.Sprintf(
 		"transaction missing prepare function for field `%s`",
@@ -3154,11 +4019,16 @@ type InvalidResourceTransactionParameterError struct {
 
 var _ SemanticError = &InvalidResourceTransactionParameterError{}
 var _ errors.UserError = &InvalidResourceTransactionParameterError{}
+var _ errors.HasErrorCode = &InvalidResourceTransactionParameterError{}
 
 func (*InvalidResourceTransactionParameterError) isSemanticError() {}
 
 func (*InvalidResourceTransactionParameterError) IsUserError() {}
 
+func (*InvalidResourceTransactionParameterError) ErrorCode() errors.ErrorCode {
+	return "C0106"
+}
+
 func (e *InvalidResourceTransactionParameterError) Error() string {
 	return fmt.Sprintf(
 		"transaction parameter must not be resource type: `%s`",
@@ -3175,11 +4045,16 @@ type InvalidNonImportableTransactionParameterTypeError struct {
 
 var _ SemanticError = &InvalidNonImportableTransactionParameterTypeError{}
 var _ errors.UserError = &InvalidNonImportableTransactionParameterTypeError{}
+var _ errors.HasErrorCode = &InvalidNonImportableTransactionParameterTypeError{}
 
 func (*InvalidNonImportableTransactionParameterTypeError) isSemanticError() {}
 
 func (*InvalidNonImportableTransactionParameterTypeError) IsUserError() {}
 
+func (*InvalidNonImportableTransactionParameterTypeError) ErrorCode() errors.ErrorCode {
+	return "C0107"
+}
+
 func (e *InvalidNonImportableTransactionParameterTypeError) Error() string {
 	return fmt.Sprintf(
 		"transaction parameter must be importable: `%s`",
@@ -3197,11 +4072,16 @@ type InvalidTransactionFieldAccessModifierError struct {
 
 var _ SemanticError = &InvalidTransactionFieldAccessModifierError{}
 var _ errors.UserError = &InvalidTransactionFieldAccessModifierError{}
+var _ errors.HasErrorCode = &InvalidTransactionFieldAccessModifierError{}
 
 func (*InvalidTransactionFieldAccessModifierError) isSemanticError() {}
 
 func (*InvalidTransactionFieldAccessModifierError) IsUserError() {}
 
+func (*InvalidTransactionFieldAccessModifierError) ErrorCode() errors.ErrorCode {
+	return "C0108"
+}
+
 func (e *InvalidTransactionFieldAccessModifierError) Error() string {
 	return fmt.Sprintf(
 		"access modifier not allowed for transaction field `%s`: `%s`",
@@ -3228,11 +4108,16 @@ type InvalidTransactionPrepareParameterTypeError struct {
 
 var _ SemanticError = &InvalidTransactionPrepareParameterTypeError{}
 var _ errors.UserError = &InvalidTransactionPrepareParameterTypeError{}
+var _ errors.HasErrorCode = &InvalidTransactionPrepareParameterTypeError{}
 
 func (*InvalidTransactionPrepareParameterTypeError) isSemanticError() {}
 
 func (*InvalidTransactionPrepareParameterTypeError) IsUserError() {}
 
+func (*InvalidTransactionPrepareParameterTypeError) ErrorCode() errors.ErrorCode {
+	return "C0109"
+}
+
 func (e *InvalidTransactionPrepareParameterTypeError) Error() string {
 	return fmt.Sprintf(
 		"prepare parameter must be of type `%s`, not `%s`",
@@ -3251,11 +4136,16 @@ type InvalidNestedDeclarationError struct {
 
 var _ SemanticError = &InvalidNestedDeclarationError{}
 var _ errors.UserError = &InvalidNestedDeclarationError{}
+var _ errors.HasErrorCode = &InvalidNestedDeclarationError{}
 
 func (*InvalidNestedDeclarationError) isSemanticError() {}
 
 func (*InvalidNestedDeclarationError) IsUserError() {}
 
+func (*InvalidNestedDeclarationError) ErrorCode() errors.ErrorCode {
+	return "C0110"
+}
+
 func (e *InvalidNestedDeclarationError) Error() string {
 	return fmt.Sprintf(
 		"%s declarations cannot be nested inside %s declarations",
@@ -3272,11 +4162,16 @@ type InvalidNestedTypeError struct {
 
 var _ SemanticError = &InvalidNestedTypeError{}
 var _ errors.UserError = &InvalidNestedTypeError{}
+var _ errors.HasErrorCode = &InvalidNestedTypeError{}
 
 func (*InvalidNestedTypeError) isSemanticError() {}
 
 func (*InvalidNestedTypeError) IsUserError() {}
 
+func (*InvalidNestedTypeError) ErrorCode() errors.ErrorCode {
+	return "C0111"
+}
+
 func (e *InvalidNestedTypeError) Error() string {
 	return fmt.Sprintf(
 		"type does not support nested types: `%s`",
@@ -3301,11 +4196,16 @@ type InvalidEnumCaseError struct {
 
 var _ SemanticError = &InvalidEnumCaseError{}
 var _ errors.UserError = &InvalidEnumCaseError{}
+var _ errors.HasErrorCode = &InvalidEnumCaseError{}
 
 func (*InvalidEnumCaseError) isSemanticError() {}
 
 func (*InvalidEnumCaseError) IsUserError() {}
 
+func (*InvalidEnumCaseError) ErrorCode() errors.ErrorCode {
+	return "C0112"
+}
+
 func (e *InvalidEnumCaseError) Error() string {
 	return fmt.Sprintf(
 		"%s declaration does not allow enum cases",
@@ -3322,11 +4222,16 @@ type InvalidNonEnumCaseError struct {
 
 var _ SemanticError = &InvalidNonEnumCaseError{}
 var _ errors.UserError = &InvalidNonEnumCaseError{}
+var _ errors.HasErrorCode = &InvalidNonEnumCaseError{}
 
 func (*InvalidNonEnumCaseError) isSemanticError() {}
 
 func (*InvalidNonEnumCaseError) IsUserError() {}
 
+func (*InvalidNonEnumCaseError) ErrorCode() errors.ErrorCode {
+	return "C0113"
+}
+
 func (e *InvalidNonEnumCaseError) Error() string {
 	return fmt.Sprintf(
 		"%s declaration only allows enum cases",
@@ -3344,12 +4249,17 @@ type DeclarationKindMismatchError struct {
 
 var _ SemanticError = &DeclarationKindMismatchError{}
 var _ errors.UserError = &DeclarationKindMismatchError{}
+var _ errors.HasErrorCode = &DeclarationKindMismatchError{}
 var _ errors.SecondaryError = &DeclarationKindMismatchError{}
 
 func (*DeclarationKindMismatchError) isSemanticError() {}
 
 func (*DeclarationKindMismatchError) IsUserError() {}
 
+func (*DeclarationKindMismatchError) ErrorCode() errors.ErrorCode {
+	return "C0114"
+}
+
 func (e *DeclarationKindMismatchError) Error() string {
 	return "mismatched declarations"
 }
@@ -3371,11 +4281,16 @@ type InvalidTopLevelDeclarationError struct {
 
 var _ SemanticError = &InvalidTopLevelDeclarationError{}
 var _ errors.UserError = &InvalidTopLevelDeclarationError{}
+var _ errors.HasErrorCode = &InvalidTopLevelDeclarationError{}
 
 func (*InvalidTopLevelDeclarationError) isSemanticError() {}
 
 func (*InvalidTopLevelDeclarationError) IsUserError() {}
 
+func (*InvalidTopLevelDeclarationError) ErrorCode() errors.ErrorCode {
+	return "C0115"
+}
+
 func (e *InvalidTopLevelDeclarationError) Error() string {
 	return fmt.Sprintf(
 		"%s declarations are not valid at the top-level",
@@ -3392,11 +4307,16 @@ type InvalidSelfInvalidationError struct {
 
 var _ SemanticError = &InvalidSelfInvalidationError{}
 var _ errors.UserError = &InvalidSelfInvalidationError{}
+var _ errors.HasErrorCode = &InvalidSelfInvalidationError{}
 
 func (*InvalidSelfInvalidationError) isSemanticError() {}
 
 func (*InvalidSelfInvalidationError) IsUserError() {}
 
+func (*InvalidSelfInvalidationError) ErrorCode() errors.ErrorCode {
+	return "C0116"
+}
+
 func (e *InvalidSelfInvalidationError) Error() string {
 	var action string
 	switch e.InvalidationKind {
@@ -3427,11 +4347,16 @@ type InvalidMoveError struct {
 
 var _ SemanticError = &InvalidMoveError{}
 var _ errors.UserError = &InvalidMoveError{}
+var _ errors.HasErrorCode = &InvalidMoveError{}
 
 func (*InvalidMoveError) isSemanticError() {}
 
 func (*InvalidMoveError) IsUserError() {}
 
+func (*InvalidMoveError) ErrorCode() errors.ErrorCode {
+	return "C0117"
+}
+
 func (e *InvalidMoveError) Error() string {
 	return fmt.Sprintf(
 		"cannot move %s: `%s`",
@@ -3459,12 +4384,17 @@ type ConstantSizedArrayLiteralSizeError struct {
 
 var _ SemanticError = &ConstantSizedArrayLiteralSizeError{}
 var _ errors.UserError = &ConstantSizedArrayLiteralSizeError{}
+var _ errors.HasErrorCode = &ConstantSizedArrayLiteralSizeError{}
 var _ errors.SecondaryError = &ConstantSizedArrayLiteralSizeError{}
 
 func (*ConstantSizedArrayLiteralSizeError) isSemanticError() {}
 
 func (*ConstantSizedArrayLiteralSizeError) IsUserError() {}
 
+func (*ConstantSizedArrayLiteralSizeError) ErrorCode() errors.ErrorCode {
+	return "C0118"
+}
+
 func (e *ConstantSizedArrayLiteralSizeError) Error() string {
 	return "incorrect number of array literal elements"
 }
@@ -3486,11 +4416,16 @@ type InvalidRestrictedTypeError struct {
 
 var _ SemanticError = &InvalidRestrictedTypeError{}
 var _ errors.UserError = &InvalidRestrictedTypeError{}
+var _ errors.HasErrorCode = &InvalidRestrictedTypeError{}
 
 func (*InvalidRestrictedTypeError) isSemanticError() {}
 
 func (*InvalidRestrictedTypeError) IsUserError() {}
 
+func (*InvalidRestrictedTypeError) ErrorCode() errors.ErrorCode {
+	return "C0119"
+}
+
 func (e *InvalidRestrictedTypeError) Error() string {
 	return fmt.Sprintf(
 		"cannot restrict type: `%s`",
@@ -3507,11 +4442,16 @@ type InvalidRestrictionTypeError struct {
 
 var _ SemanticError = &InvalidRestrictionTypeError{}
 var _ errors.UserError = &InvalidRestrictionTypeError{}
+var _ errors.HasErrorCode = &InvalidRestrictionTypeError{}
 
 func (*InvalidRestrictionTypeError) isSemanticError() {}
 
 func (*InvalidRestrictionTypeError) IsUserError() {}
 
+func (*InvalidRestrictionTypeError) ErrorCode() errors.ErrorCode {
+	return "C0120"
+}
+
 func (e *InvalidRestrictionTypeError) Error() string {
 	return fmt.Sprintf(
 		"cannot restrict using non-resource/structure interface type: `%s`",
@@ -3529,11 +4469,16 @@ type RestrictionCompositeKindMismatchError struct {
 
 var _ SemanticError = &RestrictionCompositeKindMismatchError{}
 var _ errors.UserError = &RestrictionCompositeKindMismatchError{}
+var _ errors.HasErrorCode = &RestrictionCompositeKindMismatchError{}
 
 func (*RestrictionCompositeKindMismatchError) isSemanticError() {}
 
 func (*RestrictionCompositeKindMismatchError) IsUserError() {}
 
+func (*RestrictionCompositeKindMismatchError) ErrorCode() errors.ErrorCode {
+	return "C0121"
+}
+
 func (e *RestrictionCompositeKindMismatchError) Error() string {
 	return fmt.Sprintf(
 		"interface kind %s does not match previous interface kind %s",
@@ -3551,11 +4496,16 @@ type InvalidRestrictionTypeDuplicateError struct {
 
 var _ SemanticError = &InvalidRestrictionTypeDuplicateError{}
 var _ errors.UserError = &InvalidRestrictionTypeDuplicateError{}
+var _ errors.HasErrorCode = &InvalidRestrictionTypeDuplicateError{}
 
 func (*InvalidRestrictionTypeDuplicateError) isSemanticError() {}
 
 func (*InvalidRestrictionTypeDuplicateError) IsUserError() {}
 
+func (*InvalidRestrictionTypeDuplicateError) ErrorCode() errors.ErrorCode {
+	return "C0122"
+}
+
 func (e *InvalidRestrictionTypeDuplicateError) Error() string {
 	return fmt.Sprintf(
 		"duplicate restriction: `%s`",
@@ -3572,11 +4522,16 @@ type InvalidNonConformanceRestrictionError struct {
 
 var _ SemanticError = &InvalidNonConformanceRestrictionError{}
 var _ errors.UserError = &InvalidNonConformanceRestrictionError{}
+var _ errors.HasErrorCode = &InvalidNonConformanceRestrictionError{}
 
 func (*InvalidNonConformanceRestrictionError) isSemanticError() {}
 
 func (*InvalidNonConformanceRestrictionError) IsUserError() {}
 
+func (*InvalidNonConformanceRestrictionError) ErrorCode() errors.ErrorCode {
+	return "C0123"
+}
+
 func (e *InvalidNonConformanceRestrictionError) Error() string {
 	return fmt.Sprintf(
 		"restricted type does not conform to restricting type: `%s`",
@@ -3593,11 +4548,16 @@ type InvalidRestrictedTypeMemberAccessError struct {
 
 var _ SemanticError = &InvalidRestrictedTypeMemberAccessError{}
 var _ errors.UserError = &InvalidRestrictedTypeMemberAccessError{}
+var _ errors.HasErrorCode = &InvalidRestrictedTypeMemberAccessError{}
 
 func (*InvalidRestrictedTypeMemberAccessError) isSemanticError() {}
 
 func (*InvalidRestrictedTypeMemberAccessError) IsUserError() {}
 
+func (*InvalidRestrictedTypeMemberAccessError) ErrorCode() errors.ErrorCode {
+	return "C0124"
+}
+
 func (e *InvalidRestrictedTypeMemberAccessError) Error() string {
 	return fmt.Sprintf("member of restricted type is not accessible: %s", e.Name)
 }
@@ -3613,11 +4573,16 @@ type RestrictionMemberClashError struct {
 
 var _ SemanticError = &RestrictionMemberClashError{}
 var _ errors.UserError = &RestrictionMemberClashError{}
+var _ errors.HasErrorCode = &RestrictionMemberClashError{}
 
 func (*RestrictionMemberClashError) isSemanticError() {}
 
 func (*RestrictionMemberClashError) IsUserError() {}
 
+func (*RestrictionMemberClashError) ErrorCode() errors.ErrorCode {
+	return "C0125"
+}
+
 func (e *RestrictionMemberClashError) Error() string {
 	return fmt.Sprintf(
 		"restriction has member clash with previous restriction `%s`: %s",
@@ -3634,11 +4599,16 @@ type AmbiguousRestrictedTypeError struct {
 
 var _ SemanticError = &AmbiguousRestrictedTypeError{}
 var _ errors.UserError = &AmbiguousRestrictedTypeError{}
+var _ errors.HasErrorCode = &AmbiguousRestrictedTypeError{}
 
 func (*AmbiguousRestrictedTypeError) isSemanticError() {}
 
 func (*AmbiguousRestrictedTypeError) IsUserError() {}
 
+func (*AmbiguousRestrictedTypeError) ErrorCode() errors.ErrorCode {
+	return "C0126"
+}
+
 func (e *AmbiguousRestrictedTypeError) Error() string {
 	return "ambiguous restricted type"
 }
@@ -3661,12 +4631,17 @@ type InvalidPathIdentifierError struct {
 
 var _ SemanticError = &InvalidPathDomainError{}
 var _ errors.UserError = &InvalidPathDomainError{}
+var _ errors.HasErrorCode = &InvalidPathDomainError{}
 var _ errors.SecondaryError = &InvalidPathDomainError{}
 
 func (*InvalidPathDomainError) isSemanticError() {}
 
 func (*InvalidPathDomainError) IsUserError() {}
 
+func (*InvalidPathDomainError) ErrorCode() errors.ErrorCode {
+	return "C0127"
+}
+
 func (e *InvalidPathIdentifierError) Error() string {
 	return fmt.Sprintf("invalid path identifier %s", e.ActualIdentifier)
 }
@@ -3699,12 +4674,17 @@ type InvalidTypeArgumentCountError struct {
 
 var _ SemanticError = &InvalidTypeArgumentCountError{}
 var _ errors.UserError = &InvalidTypeArgumentCountError{}
+var _ errors.HasErrorCode = &InvalidTypeArgumentCountError{}
 var _ errors.SecondaryError = &InvalidTypeArgumentCountError{}
 
 func (e *InvalidTypeArgumentCountError) isSemanticError() {}
 
 func (*InvalidTypeArgumentCountError) IsUserError() {}
 
+func (*InvalidTypeArgumentCountError) ErrorCode() errors.ErrorCode {
+	return "C0128"
+}
+
 func (e *InvalidTypeArgumentCountError) Error() string {
 	return "incorrect number of type arguments"
 }
@@ -3726,11 +4706,16 @@ type TypeParameterTypeInferenceError struct {
 
 var _ SemanticError = &TypeParameterTypeInferenceError{}
 var _ errors.UserError = &TypeParameterTypeInferenceError{}
+var _ errors.HasErrorCode = &TypeParameterTypeInferenceError{}
 
 func (e *TypeParameterTypeInferenceError) isSemanticError() {}
 
 func (*TypeParameterTypeInferenceError) IsUserError() {}
 
+func (*TypeParameterTypeInferenceError) ErrorCode() errors.ErrorCode {
+	return "C0129"
+}
+
 func (e *TypeParameterTypeInferenceError) Error() string {
 	return fmt.Sprintf(
 		"cannot infer type parameter: `%s`",
@@ -3748,12 +4733,17 @@ type InvalidConstantSizedTypeBaseError struct {
 
 var _ SemanticError = &InvalidConstantSizedTypeBaseError{}
 var _ errors.UserError = &InvalidConstantSizedTypeBaseError{}
+var _ errors.HasErrorCode = &InvalidConstantSizedTypeBaseError{}
 var _ errors.SecondaryError = &InvalidConstantSizedTypeBaseError{}
 
 func (e *InvalidConstantSizedTypeBaseError) isSemanticError() {}
 
 func (*InvalidConstantSizedTypeBaseError) IsUserError() {}
 
+func (*InvalidConstantSizedTypeBaseError) ErrorCode() errors.ErrorCode {
+	return "C0130"
+}
+
 func (e *InvalidConstantSizedTypeBaseError) Error() string {
 	return "invalid base for constant sized type size"
 }
@@ -3777,12 +4767,17 @@ type InvalidConstantSizedTypeSizeError struct {
 
 var _ SemanticError = &InvalidConstantSizedTypeSizeError{}
 var _ errors.UserError = &InvalidConstantSizedTypeSizeError{}
+var _ errors.HasErrorCode = &InvalidConstantSizedTypeSizeError{}
 var _ errors.SecondaryError = &InvalidConstantSizedTypeSizeError{}
 
 func (*InvalidConstantSizedTypeSizeError) isSemanticError() {}
 
 func (*InvalidConstantSizedTypeSizeError) IsUserError() {}
 
+func (*InvalidConstantSizedTypeSizeError) ErrorCode() errors.ErrorCode {
+	return "C0131"
+}
+
 func (e *InvalidConstantSizedTypeSizeError) Error() string {
 	return "invalid size for constant sized type"
 }
@@ -3804,11 +4799,16 @@ type UnsupportedResourceForLoopError struct {
 
 var _ SemanticError = &UnsupportedResourceForLoopError{}
 var _ errors.UserError = &UnsupportedResourceForLoopError{}
+var _ errors.HasErrorCode = &UnsupportedResourceForLoopError{}
 
 func (*UnsupportedResourceForLoopError) isSemanticError() {}
 
 func (*UnsupportedResourceForLoopError) IsUserError() {}
 
+func (*UnsupportedResourceForLoopError) ErrorCode() errors.ErrorCode {
+	return "C0132"
+}
+
 func (e *UnsupportedResourceForLoopError) Error() string {
 	return "cannot loop over resources"
 }
@@ -3824,12 +4824,17 @@ type TypeParameterTypeMismatchError struct {
 
 var _ SemanticError = &TypeParameterTypeMismatchError{}
 var _ errors.UserError = &TypeParameterTypeMismatchError{}
+var _ errors.HasErrorCode = &TypeParameterTypeMismatchError{}
 var _ errors.SecondaryError = &TypeParameterTypeMismatchError{}
 
 func (*TypeParameterTypeMismatchError) isSemanticError() {}
 
 func (*TypeParameterTypeMismatchError) IsUserError() {}
 
+func (*TypeParameterTypeMismatchError) ErrorCode() errors.ErrorCode {
+	return "C0133"
+}
+
 func (e *TypeParameterTypeMismatchError) Error() string {
 	return "mismatched types for type parameter"
 }
@@ -3857,12 +4862,17 @@ type UnparameterizedTypeInstantiationError struct {
 
 var _ SemanticError = &UnparameterizedTypeInstantiationError{}
 var _ errors.UserError = &UnparameterizedTypeInstantiationError{}
+var _ errors.HasErrorCode = &UnparameterizedTypeInstantiationError{}
 var _ errors.SecondaryError = &UnparameterizedTypeInstantiationError{}
 
 func (*UnparameterizedTypeInstantiationError) isSemanticError() {}
 
 func (*UnparameterizedTypeInstantiationError) IsUserError() {}
 
+func (*UnparameterizedTypeInstantiationError) ErrorCode() errors.ErrorCode {
+	return "C0134"
+}
+
 func (e *UnparameterizedTypeInstantiationError) Error() string {
 	return "cannot instantiate non-parameterized type"
 }
@@ -3883,11 +4893,16 @@ type TypeAnnotationRequiredError struct {
 
 var _ SemanticError = &TypeAnnotationRequiredError{}
 var _ errors.UserError = &TypeAnnotationRequiredError{}
+var _ errors.HasErrorCode = &TypeAnnotationRequiredError{}
 
 func (*TypeAnnotationRequiredError) isSemanticError() {}
 
 func (*TypeAnnotationRequiredError) IsUserError() {}
 
+func (*TypeAnnotationRequiredError) ErrorCode() errors.ErrorCode {
+	return "C0135"
+}
+
 func (e *TypeAnnotationRequiredError) Error() string {
 	if e.Cause != "" {
 		return fmt.Sprintf(
@@ -3915,11 +4930,16 @@ type CyclicImportsError struct {
 
 var _ SemanticError = &CyclicImportsError{}
 var _ errors.UserError = &CyclicImportsError{}
+var _ errors.HasErrorCode = &CyclicImportsError{}
 
 func (*CyclicImportsError) isSemanticError() {}
 
 func (*CyclicImportsError) IsUserError() {}
 
+func (*CyclicImportsError) ErrorCode() errors.ErrorCode {
+	return "C0136"
+}
+
 func (e *CyclicImportsError) Error() string {
 	return fmt.Sprintf("cyclic import of `%s`", e.Location)
 }
@@ -3932,11 +4952,16 @@ type SwitchDefaultPositionError struct {
 
 var _ SemanticError = &SwitchDefaultPositionError{}
 var _ errors.UserError = &SwitchDefaultPositionError{}
+var _ errors.HasErrorCode = &SwitchDefaultPositionError{}
 
 func (*SwitchDefaultPositionError) isSemanticError() {}
 
 func (*SwitchDefaultPositionError) IsUserError() {}
 
+func (*SwitchDefaultPositionError) ErrorCode() errors.ErrorCode {
+	return "C0137"
+}
+
 func (e *SwitchDefaultPositionError) Error() string {
 	return "the 'default' case must appear at the end of a 'switch' statement"
 }
@@ -3949,11 +4974,16 @@ type MissingSwitchCaseStatementsError struct {
 
 var _ SemanticError = &MissingSwitchCaseStatementsError{}
 var _ errors.UserError = &MissingSwitchCaseStatementsError{}
+var _ errors.HasErrorCode = &MissingSwitchCaseStatementsError{}
 
 func (*MissingSwitchCaseStatementsError) isSemanticError() {}
 
 func (*MissingSwitchCaseStatementsError) IsUserError() {}
 
+func (*MissingSwitchCaseStatementsError) ErrorCode() errors.ErrorCode {
+	return "C0138"
+}
+
 func (e *MissingSwitchCaseStatementsError) Error() string {
 	return "switch cases must have at least one statement"
 }
@@ -3973,9 +5003,14 @@ type MissingEntryPointError struct {
 }
 
 var _ errors.UserError = &MissingEntryPointError{}
+var _ errors.HasErrorCode = &MissingEntryPointError{}
 
 func (*MissingEntryPointError) IsUserError() {}
 
+func (*MissingEntryPointError) ErrorCode() errors.ErrorCode {
+	return "C0139"
+}
+
 func (e *MissingEntryPointError) Error() string {
 	return fmt.Sprintf("missing entry point: expected '%s'", e.Expected)
 }
@@ -3987,9 +5022,14 @@ type InvalidEntryPointTypeError struct {
 }
 
 var _ errors.UserError = &InvalidEntryPointTypeError{}
+var _ errors.HasErrorCode = &InvalidEntryPointTypeError{}
 
 func (*InvalidEntryPointTypeError) IsUserError() {}
 
+func (*InvalidEntryPointTypeError) ErrorCode() errors.ErrorCode {
+	return "C0140"
+}
+
 func (e *InvalidEntryPointTypeError) Error() string {
 	return fmt.Sprintf(
 		"invalid entry point type: `%s`",
@@ -4008,12 +5048,17 @@ type ExternalMutationError struct {
 
 var _ SemanticError = &ExternalMutationError{}
 var _ errors.UserError = &ExternalMutationError{}
+var _ errors.HasErrorCode = &ExternalMutationError{}
 var _ errors.SecondaryError = &ExternalMutationError{}
 
 func (*ExternalMutationError) isSemanticError() {}
 
 func (*ExternalMutationError) IsUserError() {}
 
+func (*ExternalMutationError) ErrorCode() errors.ErrorCode {
+	return "C0141"
+}
+
 func (e *ExternalMutationError) Error() string {
 	return fmt.Sprintf(
 		"cannot mutate `%s`: %s is only mutable inside `%s`",
@@ -4041,11 +5086,16 @@ type InvalidBaseTypeError struct {
 
 var _ SemanticError = &InvalidBaseTypeError{}
 var _ errors.UserError = &InvalidBaseTypeError{}
+var _ errors.HasErrorCode = &InvalidBaseTypeError{}
 
 func (*InvalidBaseTypeError) isSemanticError() {}
 
 func (*InvalidBaseTypeError) IsUserError() {}
 
+func (*InvalidBaseTypeError) ErrorCode() errors.ErrorCode {
+	return "C0142"
+}
+
 func (e *InvalidBaseTypeError) Error() string {
 	return fmt.Sprintf(
 		"cannot use `%s` as the base type for attachment `%s`",
@@ -4062,11 +5112,16 @@ type InvalidAttachmentAnnotationError struct {
 
 var _ SemanticError = &InvalidAttachmentAnnotationError{}
 var _ errors.UserError = &InvalidAttachmentAnnotationError{}
+var _ errors.HasErrorCode = &InvalidAttachmentAnnotationError{}
 
 func (*InvalidAttachmentAnnotationError) isSemanticError() {}
 
 func (*InvalidAttachmentAnnotationError) IsUserError() {}
 
+func (*InvalidAttachmentAnnotationError) ErrorCode() errors.ErrorCode {
+	return "C0143"
+}
+
 func (e *InvalidAttachmentAnnotationError) Error() string {
 	return "cannot refer directly to attachment type"
 }
@@ -4079,11 +5134,16 @@ type InvalidAttachmentUsageError struct {
 
 var _ SemanticError = &InvalidAttachmentUsageError{}
 var _ errors.UserError = &InvalidAttachmentUsageError{}
+var _ errors.HasErrorCode = &InvalidAttachmentUsageError{}
 
 func (*InvalidAttachmentUsageError) isSemanticError() {}
 
 func (*InvalidAttachmentUsageError) IsUserError() {}
 
+func (*InvalidAttachmentUsageError) ErrorCode() errors.ErrorCode {
+	return "C0144"
+}
+
 func (*InvalidAttachmentUsageError) Error() string {
 	return "cannot construct attachment outside of an `attach` expression"
 }
@@ -4097,11 +5157,16 @@ type AttachNonAttachmentError struct {
 
 var _ SemanticError = &AttachNonAttachmentError{}
 var _ errors.UserError = &AttachNonAttachmentError{}
+var _ errors.HasErrorCode = &AttachNonAttachmentError{}
 
 func (*AttachNonAttachmentError) isSemanticError() {}
 
 func (*AttachNonAttachmentError) IsUserError() {}
 
+func (*AttachNonAttachmentError) ErrorCode() errors.ErrorCode {
+	return "C0145"
+}
+
 func (e *AttachNonAttachmentError) Error() string {
 	return fmt.Sprintf(
 		"cannot attach non-attachment type: `%s`",
@@ -4117,11 +5182,16 @@ type AttachToInvalidTypeError struct {
 
 var _ SemanticError = &AttachToInvalidTypeError{}
 var _ errors.UserError = &AttachToInvalidTypeError{}
+var _ errors.HasErrorCode = &AttachToInvalidTypeError{}
 
 func (*AttachToInvalidTypeError) isSemanticError() {}
 
 func (*AttachToInvalidTypeError) IsUserError() {}
 
+func (*AttachToInvalidTypeError) ErrorCode() errors.ErrorCode {
+	return "C0146"
+}
+
 func (e *AttachToInvalidTypeError) Error() string {
 	return fmt.Sprintf(
 		"cannot attach attachment to type `%s`, as it is not valid for this base type",
@@ -4138,11 +5208,16 @@ type InvalidAttachmentRemoveError struct {
 
 var _ SemanticError = &InvalidAttachmentRemoveError{}
 var _ errors.UserError = &InvalidAttachmentRemoveError{}
+var _ errors.HasErrorCode = &InvalidAttachmentRemoveError{}
 
 func (*InvalidAttachmentRemoveError) isSemanticError() {}
 
 func (*InvalidAttachmentRemoveError) IsUserError() {}
 
+func (*InvalidAttachmentRemoveError) ErrorCode() errors.ErrorCode {
+	return "C0147"
+}
+
 func (e *InvalidAttachmentRemoveError) Error() string {
 	if e.BaseType == nil {
 		return fmt.Sprintf(
@@ -4166,11 +5241,16 @@ type InvalidTypeIndexingError struct {
 
 var _ SemanticError = &InvalidTypeIndexingError{}
 var _ errors.UserError = &InvalidTypeIndexingError{}
+var _ errors.HasErrorCode = &InvalidTypeIndexingError{}
 
 func (*InvalidTypeIndexingError) isSemanticError() {}
 
 func (*InvalidTypeIndexingError) IsUserError() {}
 
+func (*InvalidTypeIndexingError) ErrorCode() errors.ErrorCode {
+	return "C0148"
+}
+
 func (e *InvalidTypeIndexingError) Error() string {
 	return fmt.Sprintf(
 		"cannot index `%s` with `%s`, as it is not an valid type index for this type",
@@ -4186,11 +5266,16 @@ type AttachmentsNotEnabledError struct {
 
 var _ SemanticError = &AttachmentsNotEnabledError{}
 var _ errors.UserError = &AttachmentsNotEnabledError{}
+var _ errors.HasErrorCode = &AttachmentsNotEnabledError{}
 
 func (*AttachmentsNotEnabledError) isSemanticError() {}
 
 func (*AttachmentsNotEnabledError) IsUserError() {}
 
+func (*AttachmentsNotEnabledError) ErrorCode() errors.ErrorCode {
+	return "C0149"
+}
+
 func (e *AttachmentsNotEnabledError) Error() string {
 	return "attachments are not enabled and cannot be used in this environment"
 }
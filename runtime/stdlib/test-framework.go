@@ -22,6 +22,7 @@ package stdlib
 import (
 	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
 )
 
 // TestFramework & Blockchain are the interfaces to be implemented by
@@ -84,6 +85,31 @@ type Blockchain interface {
 	LoadSnapshot(string) error
 }
 
+// ContractFunctionMocker is optionally implemented by blockchains
+// which support test doubles, i.e. replacing the functions of deployed contracts
+type ContractFunctionMocker interface {
+	// MockContractFunction replaces the function with the given name of the given deployed contract.
+	// Each call of the function calls the given handler instead
+	MockContractFunction(
+		inter *interpreter.Interpreter,
+		contractType *sema.CompositeType,
+		functionName string,
+		handler ContractFunctionHandler,
+	) error
+
+	// UnmockContractFunction restores the function with the given name of the given deployed contract
+	UnmockContractFunction(
+		contractType *sema.CompositeType,
+		functionName string,
+	)
+}
+
+// ContractFunctionHandler handles a call of a mocked contract function.
+// The arguments of the call are imported into the interpreter passed to MockContractFunction.
+// The returned value is the result of the call.
+// If the handler returns nil, the original function is called
+type ContractFunctionHandler func(arguments []interpreter.Value) interpreter.Value
+
 type ScriptResult struct {
 	Value interpreter.Value
 	Error error
@@ -945,6 +945,135 @@ func newTestTypeBeLessThanFunction(
 	)
 }
 
+// 'Test.expectCalledWith' function
+
+const testTypeExpectCalledWithFunctionDocString = `
+Fails the test-case if the function with the given name of the deployed contract with the given type
+was not called with the given arguments.
+`
+
+const testTypeExpectCalledWithFunctionName = "expectCalledWith"
+
+const testTypeCallsFunctionName = "calls"
+
+var testTypeExpectCalledWithFunctionType = &sema.FunctionType{
+	Parameters: []sema.Parameter{
+		{
+			Label:      sema.ArgumentLabelNotRequired,
+			Identifier: "type",
+			TypeAnnotation: sema.NewTypeAnnotation(
+				sema.MetaType,
+			),
+		},
+		{
+			Identifier: "function",
+			TypeAnnotation: sema.NewTypeAnnotation(
+				sema.StringType,
+			),
+		},
+		{
+			Identifier: "arguments",
+			TypeAnnotation: sema.NewTypeAnnotation(
+				&sema.VariableSizedType{
+					Type: sema.AnyStructType,
+				},
+			),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		sema.VoidType,
+	),
+}
+
+var testTypeExpectCalledWithFunction = interpreter.NewUnmeteredHostFunctionValue(
+	testTypeExpectCalledWithFunctionType,
+	func(invocation interpreter.Invocation) interpreter.Value {
+		inter := invocation.Interpreter
+		locationRange := invocation.LocationRange
+
+		functionName, ok := invocation.Arguments[1].(*interpreter.StringValue)
+		if !ok {
+			panic(errors.NewUnreachableError())
+		}
+
+		expectedArguments, err := arrayValueToSlice(inter, invocation.Arguments[2])
+		if err != nil {
+			panic(err)
+		}
+
+		// Get the recorded calls using 'Test.calls'
+
+		testContract := *invocation.Self
+
+		callsFunction, ok := testContract.GetMember(
+			inter,
+			locationRange,
+			testTypeCallsFunctionName,
+		).(interpreter.FunctionValue)
+		if !ok {
+			panic(errors.NewUnreachableError())
+		}
+
+		calls, err := inter.InvokeExternally(
+			callsFunction,
+			callsFunction.FunctionType(),
+			invocation.Arguments[:2],
+		)
+		if err != nil {
+			panic(err)
+		}
+
+		callValues, err := arrayValueToSlice(inter, calls)
+		if err != nil {
+			panic(err)
+		}
+
+		for _, call := range callValues {
+			arguments, err := arrayValueToSlice(inter, call)
+			if err != nil {
+				panic(err)
+			}
+
+			if valuesEqual(inter, locationRange, expectedArguments, arguments) {
+				return interpreter.Void
+			}
+		}
+
+		message := fmt.Sprintf(
+			"expected function %s to be called with arguments %s, but the calls were: %s",
+			functionName.Str,
+			invocation.Arguments[2],
+			calls,
+		)
+		panic(AssertionError{
+			Message:       message,
+			LocationRange: locationRange,
+		})
+	},
+)
+
+// valuesEqual returns true if the given values are pairwise equal.
+// Unlike arrays, the values may have different static types
+func valuesEqual(
+	inter *interpreter.Interpreter,
+	locationRange interpreter.LocationRange,
+	values []interpreter.Value,
+	others []interpreter.Value,
+) bool {
+	if len(values) != len(others) {
+		return false
+	}
+
+	for i, value := range values {
+		equatableValue, ok := value.(interpreter.EquatableValue)
+		if !ok || !equatableValue.Equal(inter, locationRange, others[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func newTestContractType() *TestContractType {
 
 	program, err := parser.ParseProgram(
@@ -1193,6 +1322,18 @@ func newTestContractType() *TestContractType {
 	ty.expectFailureFunction = newTestTypeExpectFailureFunction(
 		expectFailureFunctionType,
 	)
+
+	// Test.expectCalledWith()
+	compositeType.Members.Set(
+		testTypeExpectCalledWithFunctionName,
+		sema.NewUnmeteredPublicFunctionMember(
+			compositeType,
+			testTypeExpectCalledWithFunctionName,
+			testTypeExpectCalledWithFunctionType,
+			testTypeExpectCalledWithFunctionDocString,
+		),
+	)
+
 	compositeType.ResolveMembers()
 
 	return ty
@@ -1280,5 +1421,8 @@ func (t *TestContractType) NewTestContract(
 	compositeValue.Functions[testTypeBeLessThanFunctionName] = t.beLessThanFunction
 	compositeValue.Functions[testExpectFailureFunctionName] = t.expectFailureFunction
 
+	// Inject natively implemented assertions of test doubles
+	compositeValue.Functions[testTypeExpectCalledWithFunctionName] = testTypeExpectCalledWithFunction
+
 	return compositeValue, nil
 }
@@ -51,6 +51,11 @@ type testEmulatorBackendType struct {
 	createSnapshotFunctionType         *sema.FunctionType
 	loadSnapshotFunctionType           *sema.FunctionType
 	getAccountFunctionType             *sema.FunctionType
+	mockFunctionType                   *sema.FunctionType
+	stubFunctionType                   *sema.FunctionType
+	spyFunctionType                    *sema.FunctionType
+	unmockFunctionType                 *sema.FunctionType
+	callsFunctionType                  *sema.FunctionType
 }
 
 func newTestEmulatorBackendType(
@@ -126,6 +131,31 @@ func newTestEmulatorBackendType(
 		testEmulatorBackendTypeGetAccountFunctionName,
 	)
 
+	mockFunctionType := interfaceFunctionType(
+		blockchainBackendInterfaceType,
+		testEmulatorBackendTypeMockFunctionName,
+	)
+
+	stubFunctionType := interfaceFunctionType(
+		blockchainBackendInterfaceType,
+		testEmulatorBackendTypeStubFunctionName,
+	)
+
+	spyFunctionType := interfaceFunctionType(
+		blockchainBackendInterfaceType,
+		testEmulatorBackendTypeSpyFunctionName,
+	)
+
+	unmockFunctionType := interfaceFunctionType(
+		blockchainBackendInterfaceType,
+		testEmulatorBackendTypeUnmockFunctionName,
+	)
+
+	callsFunctionType := interfaceFunctionType(
+		blockchainBackendInterfaceType,
+		testEmulatorBackendTypeCallsFunctionName,
+	)
+
 	compositeType := &sema.CompositeType{
 		Identifier: testEmulatorBackendTypeName,
 		Kind:       common.CompositeKindStructure,
@@ -220,6 +250,36 @@ func newTestEmulatorBackendType(
 			getAccountFunctionType,
 			testEmulatorBackendTypeGetAccountFunctionDocString,
 		),
+		sema.NewUnmeteredPublicFunctionMember(
+			compositeType,
+			testEmulatorBackendTypeMockFunctionName,
+			mockFunctionType,
+			testEmulatorBackendTypeMockFunctionDocString,
+		),
+		sema.NewUnmeteredPublicFunctionMember(
+			compositeType,
+			testEmulatorBackendTypeStubFunctionName,
+			stubFunctionType,
+			testEmulatorBackendTypeStubFunctionDocString,
+		),
+		sema.NewUnmeteredPublicFunctionMember(
+			compositeType,
+			testEmulatorBackendTypeSpyFunctionName,
+			spyFunctionType,
+			testEmulatorBackendTypeSpyFunctionDocString,
+		),
+		sema.NewUnmeteredPublicFunctionMember(
+			compositeType,
+			testEmulatorBackendTypeUnmockFunctionName,
+			unmockFunctionType,
+			testEmulatorBackendTypeUnmockFunctionDocString,
+		),
+		sema.NewUnmeteredPublicFunctionMember(
+			compositeType,
+			testEmulatorBackendTypeCallsFunctionName,
+			callsFunctionType,
+			testEmulatorBackendTypeCallsFunctionDocString,
+		),
 	}
 
 	compositeType.Members = sema.MembersAsMap(members)
@@ -241,6 +301,11 @@ func newTestEmulatorBackendType(
 		createSnapshotFunctionType:         createSnapshotFunctionType,
 		loadSnapshotFunctionType:           loadSnapshotFunctionType,
 		getAccountFunctionType:             getAccountFunctionType,
+		mockFunctionType:                   mockFunctionType,
+		stubFunctionType:                   stubFunctionType,
+		spyFunctionType:                    spyFunctionType,
+		unmockFunctionType:                 unmockFunctionType,
+		callsFunctionType:                  callsFunctionType,
 	}
 }
 
@@ -792,11 +857,229 @@ func (t *testEmulatorBackendType) newLoadSnapshotFunction(
 	)
 }
 
+// 'Emulator.mock' function
+
+const testEmulatorBackendTypeMockFunctionName = "mock"
+
+const testEmulatorBackendTypeMockFunctionDocString = `
+Replaces the function with the given name of the deployed contract
+with the given type by the given replacement function,
+and records the calls of the function.
+`
+
+func (t *testEmulatorBackendType) newMockFunction(
+	mocks *contractFunctionMocks,
+) *interpreter.HostFunctionValue {
+	return interpreter.NewUnmeteredHostFunctionValue(
+		t.mockFunctionType,
+		func(invocation interpreter.Invocation) interpreter.Value {
+			inter := invocation.Interpreter
+			locationRange := invocation.LocationRange
+
+			functionName, ok := invocation.Arguments[1].(*interpreter.StringValue)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+
+			replacementValue := invocation.Arguments[2]
+
+			err := mocks.mock(
+				inter,
+				locationRange,
+				invocation.Arguments[0],
+				functionName.Str,
+				func(functionType *sema.FunctionType) (ContractFunctionHandler, error) {
+					replacement, ok := replacementValue.(interpreter.FunctionValue)
+					if !ok || !inter.IsSubTypeOfSemaType(replacement.StaticType(inter), functionType) {
+						return nil, errors.NewDefaultUserError(
+							"cannot mock function %s: replacement must be a function of type %s",
+							functionName.Str,
+							functionType.QualifiedString(),
+						)
+					}
+
+					return func(arguments []interpreter.Value) interpreter.Value {
+						result, err := inter.InvokeExternally(
+							replacement,
+							replacement.FunctionType(),
+							arguments,
+						)
+						if err != nil {
+							panic(err)
+						}
+						return result
+					}, nil
+				},
+			)
+
+			return newErrorValue(inter, err)
+		},
+	)
+}
+
+// 'Emulator.stub' function
+
+const testEmulatorBackendTypeStubFunctionName = "stub"
+
+const testEmulatorBackendTypeStubFunctionDocString = `
+Replaces the function with the given name of the deployed contract
+with the given type by a function which returns the given value,
+and records the calls of the function.
+`
+
+func (t *testEmulatorBackendType) newStubFunction(
+	mocks *contractFunctionMocks,
+) *interpreter.HostFunctionValue {
+	return interpreter.NewUnmeteredHostFunctionValue(
+		t.stubFunctionType,
+		func(invocation interpreter.Invocation) interpreter.Value {
+			inter := invocation.Interpreter
+			locationRange := invocation.LocationRange
+
+			functionName, ok := invocation.Arguments[1].(*interpreter.StringValue)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+
+			returnValue := invocation.Arguments[2]
+
+			err := mocks.mock(
+				inter,
+				locationRange,
+				invocation.Arguments[0],
+				functionName.Str,
+				func(functionType *sema.FunctionType) (ContractFunctionHandler, error) {
+					returnType := functionType.ReturnTypeAnnotation.Type
+
+					if !inter.IsSubTypeOfSemaType(returnValue.StaticType(inter), returnType) {
+						return nil, errors.NewDefaultUserError(
+							"cannot stub function %s: return value must have type %s",
+							functionName.Str,
+							returnType.QualifiedString(),
+						)
+					}
+
+					boxedReturnValue := inter.BoxOptional(locationRange, returnValue, returnType)
+
+					return func(_ []interpreter.Value) interpreter.Value {
+						return copyValues(inter, locationRange, []interpreter.Value{boxedReturnValue})[0]
+					}, nil
+				},
+			)
+
+			return newErrorValue(inter, err)
+		},
+	)
+}
+
+// 'Emulator.spy' function
+
+const testEmulatorBackendTypeSpyFunctionName = "spy"
+
+const testEmulatorBackendTypeSpyFunctionDocString = `
+Records the calls of the function with the given name
+of the deployed contract with the given type.
+`
+
+func (t *testEmulatorBackendType) newSpyFunction(
+	mocks *contractFunctionMocks,
+) *interpreter.HostFunctionValue {
+	return interpreter.NewUnmeteredHostFunctionValue(
+		t.spyFunctionType,
+		func(invocation interpreter.Invocation) interpreter.Value {
+			inter := invocation.Interpreter
+
+			functionName, ok := invocation.Arguments[1].(*interpreter.StringValue)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+
+			err := mocks.mock(
+				inter,
+				invocation.LocationRange,
+				invocation.Arguments[0],
+				functionName.Str,
+				func(_ *sema.FunctionType) (ContractFunctionHandler, error) {
+					// The original function is called
+					return func(_ []interpreter.Value) interpreter.Value {
+						return nil
+					}, nil
+				},
+			)
+
+			return newErrorValue(inter, err)
+		},
+	)
+}
+
+// 'Emulator.unmock' function
+
+const testEmulatorBackendTypeUnmockFunctionName = "unmock"
+
+const testEmulatorBackendTypeUnmockFunctionDocString = `
+Restores the function with the given name
+of the deployed contract with the given type.
+`
+
+func (t *testEmulatorBackendType) newUnmockFunction(
+	mocks *contractFunctionMocks,
+) *interpreter.HostFunctionValue {
+	return interpreter.NewUnmeteredHostFunctionValue(
+		t.unmockFunctionType,
+		func(invocation interpreter.Invocation) interpreter.Value {
+			functionName, ok := invocation.Arguments[1].(*interpreter.StringValue)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+
+			mocks.unmock(
+				invocation.Interpreter,
+				invocation.Arguments[0],
+				functionName.Str,
+			)
+
+			return interpreter.Void
+		},
+	)
+}
+
+// 'Emulator.calls' function
+
+const testEmulatorBackendTypeCallsFunctionName = "calls"
+
+const testEmulatorBackendTypeCallsFunctionDocString = `
+Returns the arguments of the recorded calls of the function
+with the given name of the deployed contract with the given type.
+`
+
+func (t *testEmulatorBackendType) newCallsFunction(
+	mocks *contractFunctionMocks,
+) *interpreter.HostFunctionValue {
+	return interpreter.NewUnmeteredHostFunctionValue(
+		t.callsFunctionType,
+		func(invocation interpreter.Invocation) interpreter.Value {
+			functionName, ok := invocation.Arguments[1].(*interpreter.StringValue)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+
+			return mocks.callsValue(
+				invocation.Interpreter,
+				invocation.LocationRange,
+				invocation.Arguments[0],
+				functionName.Str,
+			)
+		},
+	)
+}
+
 func (t *testEmulatorBackendType) newEmulatorBackend(
 	inter *interpreter.Interpreter,
 	blockchain Blockchain,
 	locationRange interpreter.LocationRange,
 ) *interpreter.CompositeValue {
+	mocks := newContractFunctionMocks(blockchain)
+
 	var fields = []interpreter.CompositeField{
 		{
 			Name:  testEmulatorBackendTypeExecuteScriptFunctionName,
@@ -853,6 +1136,26 @@ func (t *testEmulatorBackendType) newEmulatorBackend(
 			Name:  testEmulatorBackendTypeGetAccountFunctionName,
 			Value: t.newGetAccountFunction(blockchain),
 		},
+		{
+			Name:  testEmulatorBackendTypeMockFunctionName,
+			Value: t.newMockFunction(mocks),
+		},
+		{
+			Name:  testEmulatorBackendTypeStubFunctionName,
+			Value: t.newStubFunction(mocks),
+		},
+		{
+			Name:  testEmulatorBackendTypeSpyFunctionName,
+			Value: t.newSpyFunction(mocks),
+		},
+		{
+			Name:  testEmulatorBackendTypeUnmockFunctionName,
+			Value: t.newUnmockFunction(mocks),
+		},
+		{
+			Name:  testEmulatorBackendTypeCallsFunctionName,
+			Value: t.newCallsFunction(mocks),
+		},
 	}
 
 	// TODO: Use SimpleCompositeValue
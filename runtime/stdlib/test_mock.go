@@ -0,0 +1,266 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stdlib
+
+import (
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// Test doubles.
+//
+// Functions of deployed contracts can be replaced by functions of the test (mocks),
+// by functions which return a fixed value (stubs), or observed without replacing them (spies).
+// The calls of the functions are recorded, so tests can make assertions about them.
+
+// contractFunctionKey identifies a function of a deployed contract
+type contractFunctionKey struct {
+	contractTypeID common.TypeID
+	functionName   string
+}
+
+// contractFunctionCalls are the recorded arguments of the calls of a mocked contract function
+type contractFunctionCalls struct {
+	arguments [][]interpreter.Value
+}
+
+// contractFunctionMocks are the mocked functions of the deployed contracts of a blockchain
+type contractFunctionMocks struct {
+	blockchain Blockchain
+	calls      map[contractFunctionKey]*contractFunctionCalls
+}
+
+func newContractFunctionMocks(blockchain Blockchain) *contractFunctionMocks {
+	return &contractFunctionMocks{
+		blockchain: blockchain,
+		calls:      map[contractFunctionKey]*contractFunctionCalls{},
+	}
+}
+
+// contractFunction returns the contract type and the function type
+// of the function with the given name of the contract with the given type
+func contractFunction(
+	inter *interpreter.Interpreter,
+	typeValue interpreter.Value,
+	functionName string,
+) (
+	*sema.CompositeType,
+	*sema.FunctionType,
+	error,
+) {
+	staticType, ok := typeValue.(interpreter.TypeValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	if staticType.Type == nil {
+		return nil, nil, errors.NewDefaultUserError("cannot mock function of unknown type")
+	}
+
+	semaType := inter.MustConvertStaticToSemaType(staticType.Type)
+
+	contractType, ok := semaType.(*sema.CompositeType)
+	if !ok || contractType.Kind != common.CompositeKindContract {
+		return nil, nil, errors.NewDefaultUserError(
+			"cannot mock function of type %s: not a contract type",
+			semaType.QualifiedString(),
+		)
+	}
+
+	member, ok := contractType.Members.Get(functionName)
+	if !ok || member.DeclarationKind != common.DeclarationKindFunction {
+		return nil, nil, errors.NewDefaultUserError(
+			"cannot mock function %s: contract %s has no function with this name",
+			functionName,
+			contractType.QualifiedString(),
+		)
+	}
+
+	functionType := getFunctionTypeFromMember(member, functionName)
+
+	// The arguments and results of calls are copied between the contract and the test,
+	// which is not possible for resources
+
+	for _, parameter := range functionType.Parameters {
+		if parameter.TypeAnnotation.Type.IsResourceType() {
+			return nil, nil, errors.NewDefaultUserError(
+				"cannot mock function %s: resources cannot be passed to mocked functions",
+				functionName,
+			)
+		}
+	}
+
+	if functionType.ReturnTypeAnnotation.Type.IsResourceType() {
+		return nil, nil, errors.NewDefaultUserError(
+			"cannot mock function %s: resources cannot be returned from mocked functions",
+			functionName,
+		)
+	}
+
+	return contractType, functionType, nil
+}
+
+// mock replaces the function with the given name of the contract with the given type.
+// The given function returns the handler for the calls of the function, given the function's type.
+// The calls of the function are recorded before they are handled
+func (m *contractFunctionMocks) mock(
+	inter *interpreter.Interpreter,
+	locationRange interpreter.LocationRange,
+	typeValue interpreter.Value,
+	functionName string,
+	newHandler func(functionType *sema.FunctionType) (ContractFunctionHandler, error),
+) error {
+	mocker, ok := m.blockchain.(ContractFunctionMocker)
+	if !ok {
+		return errors.NewDefaultUserError("cannot mock function %s: blockchain does not support mocks", functionName)
+	}
+
+	contractType, functionType, err := contractFunction(inter, typeValue, functionName)
+	if err != nil {
+		return err
+	}
+
+	handler, err := newHandler(functionType)
+	if err != nil {
+		return err
+	}
+
+	calls := &contractFunctionCalls{}
+
+	m.calls[contractFunctionKey{
+		contractTypeID: contractType.ID(),
+		functionName:   functionName,
+	}] = calls
+
+	return mocker.MockContractFunction(
+		inter,
+		contractType,
+		functionName,
+		func(arguments []interpreter.Value) interpreter.Value {
+			calls.arguments = append(
+				calls.arguments,
+				copyValues(inter, locationRange, arguments),
+			)
+
+			return handler(arguments)
+		},
+	)
+}
+
+// unmock restores the function with the given name of the contract with the given type.
+// The recorded calls are kept
+func (m *contractFunctionMocks) unmock(
+	inter *interpreter.Interpreter,
+	typeValue interpreter.Value,
+	functionName string,
+) {
+	mocker, ok := m.blockchain.(ContractFunctionMocker)
+	if !ok {
+		return
+	}
+
+	contractType, _, err := contractFunction(inter, typeValue, functionName)
+	if err != nil {
+		panic(err)
+	}
+
+	mocker.UnmockContractFunction(contractType, functionName)
+}
+
+// callsValue returns the arguments of the recorded calls of the function with the given name
+// of the contract with the given type, as an array of arrays
+func (m *contractFunctionMocks) callsValue(
+	inter *interpreter.Interpreter,
+	locationRange interpreter.LocationRange,
+	typeValue interpreter.Value,
+	functionName string,
+) interpreter.Value {
+	contractType, _, err := contractFunction(inter, typeValue, functionName)
+	if err != nil {
+		panic(err)
+	}
+
+	calls, ok := m.calls[contractFunctionKey{
+		contractTypeID: contractType.ID(),
+		functionName:   functionName,
+	}]
+	if !ok {
+		panic(errors.NewDefaultUserError(
+			"cannot get calls of function %s: function is not mocked",
+			functionName,
+		))
+	}
+
+	argumentsType := interpreter.NewVariableSizedStaticType(
+		inter,
+		interpreter.NewPrimitiveStaticType(
+			inter,
+			interpreter.PrimitiveStaticTypeAnyStruct,
+		),
+	)
+
+	callValues := make([]interpreter.Value, 0, len(calls.arguments))
+	for _, arguments := range calls.arguments {
+		callValues = append(
+			callValues,
+			interpreter.NewArrayValue(
+				inter,
+				locationRange,
+				argumentsType,
+				common.ZeroAddress,
+				copyValues(inter, locationRange, arguments)...,
+			),
+		)
+	}
+
+	return interpreter.NewArrayValue(
+		inter,
+		locationRange,
+		interpreter.NewVariableSizedStaticType(inter, argumentsType),
+		common.ZeroAddress,
+		callValues...,
+	)
+}
+
+// copyValues returns copies of the given values, which are not owned by any container
+func copyValues(
+	inter *interpreter.Interpreter,
+	locationRange interpreter.LocationRange,
+	values []interpreter.Value,
+) []interpreter.Value {
+	copies := make([]interpreter.Value, 0, len(values))
+	for _, value := range values {
+		copies = append(
+			copies,
+			value.Transfer(
+				inter,
+				locationRange,
+				atree.Address{},
+				false,
+				nil,
+				nil,
+			),
+		)
+	}
+	return copies
+}
@@ -2648,6 +2648,306 @@ func TestBlockchain(t *testing.T) {
 	// TODO: Add more tests for the remaining functions.
 }
 
+func TestTestContractFunctionMocks(t *testing.T) {
+
+	t.Parallel()
+
+	const oracleContract = `
+        import Test
+
+        pub contract Oracle {
+
+            pub resource R {}
+
+            pub fun price(_ symbol: String): UFix64 {
+                return 1.0
+            }
+
+            pub fun maybe(): Int? {
+                return nil
+            }
+
+            pub fun take(_ r: @R) {
+                destroy r
+            }
+        }
+    `
+
+	newInterpreter := func(
+		t *testing.T,
+		script string,
+		blockchain stdlib.Blockchain,
+	) *interpreter.Interpreter {
+		testFramework := &mockedTestFramework{
+			emulatorBackend: func() stdlib.Blockchain {
+				return blockchain
+			},
+		}
+
+		inter, err := newTestContractInterpreterWithTestFramework(t, oracleContract+script, testFramework)
+		require.NoError(t, err)
+
+		return inter
+	}
+
+	t.Run("mock", func(t *testing.T) {
+		t.Parallel()
+
+		const script = `
+            pub fun test() {
+                Test.mock(Type<Oracle>(), function: "price", with: fun (symbol: String): UFix64 {
+                    return 2.0
+                })
+            }
+
+            pub fun testCalls() {
+                Test.expectCalled(Type<Oracle>(), function: "price")
+                Test.expectCalledTimes(Type<Oracle>(), function: "price", times: 1)
+                Test.expectCalledWith(Type<Oracle>(), function: "price", arguments: ["FLOW"])
+
+                let calls: [[AnyStruct]] = [["FLOW"]]
+                Test.assertEqual(calls, Test.calls(Type<Oracle>(), function: "price"))
+            }
+
+            pub fun testNotCalledWith() {
+                Test.expectCalledWith(Type<Oracle>(), function: "price", arguments: ["BTC"])
+            }
+        `
+
+		var handler stdlib.ContractFunctionHandler
+
+		blockchain := &mockedContractFunctionMocker{
+			mockContractFunction: func(
+				_ *interpreter.Interpreter,
+				contractType *sema.CompositeType,
+				functionName string,
+				contractFunctionHandler stdlib.ContractFunctionHandler,
+			) error {
+				assert.Equal(t, "Oracle", contractType.Identifier)
+				assert.Equal(t, "price", functionName)
+				handler = contractFunctionHandler
+				return nil
+			},
+		}
+
+		inter := newInterpreter(t, script, blockchain)
+
+		_, err := inter.Invoke("test")
+		require.NoError(t, err)
+		require.NotNil(t, handler)
+
+		_, err = inter.Invoke("testNotCalledWith")
+		require.ErrorContains(t, err, "expected function price to be called with arguments")
+
+		result := handler([]interpreter.Value{
+			interpreter.NewUnmeteredStringValue("FLOW"),
+		})
+		assert.Equal(t, interpreter.NewUnmeteredUFix64ValueWithInteger(2, interpreter.EmptyLocationRange), result)
+
+		_, err = inter.Invoke("testCalls")
+		require.NoError(t, err)
+	})
+
+	t.Run("stub", func(t *testing.T) {
+		t.Parallel()
+
+		const script = `
+            pub fun test() {
+                Test.stub(Type<Oracle>(), function: "maybe", returnValue: 42)
+            }
+        `
+
+		var handler stdlib.ContractFunctionHandler
+
+		blockchain := &mockedContractFunctionMocker{
+			mockContractFunction: func(
+				_ *interpreter.Interpreter,
+				_ *sema.CompositeType,
+				_ string,
+				contractFunctionHandler stdlib.ContractFunctionHandler,
+			) error {
+				handler = contractFunctionHandler
+				return nil
+			},
+		}
+
+		inter := newInterpreter(t, script, blockchain)
+
+		_, err := inter.Invoke("test")
+		require.NoError(t, err)
+		require.NotNil(t, handler)
+
+		// The return value is boxed, as the function returns an optional
+		result := handler(nil)
+		assert.Equal(
+			t,
+			interpreter.NewUnmeteredSomeValueNonCopying(interpreter.NewUnmeteredIntValueFromInt64(42)),
+			result,
+		)
+	})
+
+	t.Run("spy and unmock", func(t *testing.T) {
+		t.Parallel()
+
+		const script = `
+            pub fun test() {
+                Test.spy(Type<Oracle>(), function: "price")
+            }
+
+            pub fun testUnmock() {
+                Test.unmock(Type<Oracle>(), function: "price")
+                Test.expectCalledTimes(Type<Oracle>(), function: "price", times: 2)
+            }
+        `
+
+		var handler stdlib.ContractFunctionHandler
+		unmocked := false
+
+		blockchain := &mockedContractFunctionMocker{
+			mockContractFunction: func(
+				_ *interpreter.Interpreter,
+				_ *sema.CompositeType,
+				_ string,
+				contractFunctionHandler stdlib.ContractFunctionHandler,
+			) error {
+				handler = contractFunctionHandler
+				return nil
+			},
+			unmockContractFunction: func(contractType *sema.CompositeType, functionName string) {
+				assert.Equal(t, "Oracle", contractType.Identifier)
+				assert.Equal(t, "price", functionName)
+				unmocked = true
+			},
+		}
+
+		inter := newInterpreter(t, script, blockchain)
+
+		_, err := inter.Invoke("test")
+		require.NoError(t, err)
+		require.NotNil(t, handler)
+
+		// The original function is called
+		for _, symbol := range []string{"FLOW", "BTC"} {
+			result := handler([]interpreter.Value{
+				interpreter.NewUnmeteredStringValue(symbol),
+			})
+			assert.Nil(t, result)
+		}
+
+		_, err = inter.Invoke("testUnmock")
+		require.NoError(t, err)
+		assert.True(t, unmocked)
+	})
+
+	t.Run("not called", func(t *testing.T) {
+		t.Parallel()
+
+		const script = `
+            pub fun test() {
+                Test.spy(Type<Oracle>(), function: "price")
+                Test.expectNotCalled(Type<Oracle>(), function: "price")
+                Test.expectCalled(Type<Oracle>(), function: "price")
+            }
+        `
+
+		blockchain := &mockedContractFunctionMocker{
+			mockContractFunction: func(
+				_ *interpreter.Interpreter,
+				_ *sema.CompositeType,
+				_ string,
+				_ stdlib.ContractFunctionHandler,
+			) error {
+				return nil
+			},
+		}
+
+		inter := newInterpreter(t, script, blockchain)
+
+		_, err := inter.Invoke("test")
+		require.ErrorContains(t, err, "expected function price to be called")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		test := func(code string, expectedError string) {
+
+			t.Run(code, func(t *testing.T) {
+				t.Parallel()
+
+				script := fmt.Sprintf(
+					`
+                      pub fun test() {
+                          %s
+                      }
+                    `,
+					code,
+				)
+
+				blockchain := &mockedContractFunctionMocker{
+					mockContractFunction: func(
+						_ *interpreter.Interpreter,
+						_ *sema.CompositeType,
+						_ string,
+						_ stdlib.ContractFunctionHandler,
+					) error {
+						return nil
+					},
+				}
+
+				inter := newInterpreter(t, script, blockchain)
+
+				_, err := inter.Invoke("test")
+				require.ErrorContains(t, err, expectedError)
+			})
+		}
+
+		test(
+			`Test.mock(Type<Oracle>(), function: "price", with: fun (): UFix64 { return 1.0 })`,
+			"replacement must be a function of type",
+		)
+		test(
+			`Test.mock(Type<Oracle>(), function: "price", with: 1)`,
+			"replacement must be a function of type",
+		)
+		test(
+			`Test.stub(Type<Oracle>(), function: "price", returnValue: "1.0")`,
+			"return value must have type UFix64",
+		)
+		test(
+			`Test.stub(Type<Oracle>(), function: "unknown", returnValue: 1)`,
+			"contract Oracle has no function with this name",
+		)
+		test(
+			`Test.stub(Type<Int>(), function: "price", returnValue: 1.0)`,
+			"not a contract type",
+		)
+		test(
+			`Test.spy(Type<Oracle>(), function: "take")`,
+			"resources cannot be passed to mocked functions",
+		)
+		test(
+			`Test.calls(Type<Oracle>(), function: "price")`,
+			"function is not mocked",
+		)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		t.Parallel()
+
+		const script = `
+            pub fun test() {
+                Test.spy(Type<Oracle>(), function: "price")
+            }
+        `
+
+		inter := newInterpreter(t, script, &mockedBlockchain{})
+
+		_, err := inter.Invoke("test")
+		require.ErrorContains(t, err, "blockchain does not support mocks")
+	})
+}
+
 type mockedTestFramework struct {
 	emulatorBackend func() stdlib.Blockchain
 	readFile        func(s string) (string, error)
@@ -2828,3 +3128,40 @@ func (m mockedBlockchain) LoadSnapshot(name string) error {
 
 	return m.loadSnapshot(name)
 }
+
+type mockedContractFunctionMocker struct {
+	mockedBlockchain
+	mockContractFunction func(
+		inter *interpreter.Interpreter,
+		contractType *sema.CompositeType,
+		functionName string,
+		handler stdlib.ContractFunctionHandler,
+	) error
+	unmockContractFunction func(contractType *sema.CompositeType, functionName string)
+}
+
+var _ stdlib.ContractFunctionMocker = &mockedContractFunctionMocker{}
+
+func (m mockedContractFunctionMocker) MockContractFunction(
+	inter *interpreter.Interpreter,
+	contractType *sema.CompositeType,
+	functionName string,
+	handler stdlib.ContractFunctionHandler,
+) error {
+	if m.mockContractFunction == nil {
+		panic("'MockContractFunction' is not implemented")
+	}
+
+	return m.mockContractFunction(inter, contractType, functionName, handler)
+}
+
+func (m mockedContractFunctionMocker) UnmockContractFunction(
+	contractType *sema.CompositeType,
+	functionName string,
+) {
+	if m.unmockContractFunction == nil {
+		panic("'UnmockContractFunction' is not implemented")
+	}
+
+	m.unmockContractFunction(contractType, functionName)
+}
@@ -0,0 +1,296 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package testrunner
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// Suite contains the results of the tests of a test file.
+// If the file could not be parsed or checked, the error is set
+type Suite struct {
+	Location common.Location
+	Results  Results
+	Error    error
+}
+
+// Report contains the results of the tests of multiple test files
+type Report struct {
+	Suites []Suite
+}
+
+func NewReport() *Report {
+	return &Report{}
+}
+
+// Add adds the results of the tests of the test file with the given location,
+// as returned by TestRunner.RunTests
+func (r *Report) Add(location common.Location, results Results, err error) {
+	r.Suites = append(r.Suites, Suite{
+		Location: location,
+		Results:  results,
+		Error:    err,
+	})
+}
+
+// Tests returns the number of run tests
+func (r *Report) Tests() int {
+	tests := 0
+	for _, suite := range r.Suites {
+		tests += len(suite.Results)
+	}
+	return tests
+}
+
+// Failures returns the number of failed tests
+func (r *Report) Failures() int {
+	failures := 0
+	for _, suite := range r.Suites {
+		failures += suite.Results.Failed()
+	}
+	return failures
+}
+
+// Errors returns the number of test files which could not be parsed or checked
+func (r *Report) Errors() int {
+	errs := 0
+	for _, suite := range r.Suites {
+		if suite.Error != nil {
+			errs++
+		}
+	}
+	return errs
+}
+
+// Passed returns true if all test files could be checked, and all tests passed
+func (r *Report) Passed() bool {
+	return r.Failures() == 0 && r.Errors() == 0
+}
+
+func (r *Report) duration() time.Duration {
+	var duration time.Duration
+	for _, suite := range r.Suites {
+		duration += suite.duration()
+	}
+	return duration
+}
+
+func (s Suite) duration() time.Duration {
+	var duration time.Duration
+	for _, result := range s.Results {
+		duration += result.Duration
+	}
+	return duration
+}
+
+// errorMessage returns the message of the underlying error of the given error,
+// without code excerpts and stack trace
+func errorMessage(err error) string {
+	for {
+		switch typedErr := err.(type) {
+		case runtime.Error:
+			err = typedErr.Err
+		case interpreter.Error:
+			err = typedErr.Err
+		default:
+			return err.Error()
+		}
+	}
+}
+
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Errors     int              `xml:"errors,attr"`
+	Time       string           `xml:"time,attr"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Time      string          `xml:"time,attr"`
+	Error     *junitFailure   `xml:"error,omitempty"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message  string `xml:"message,attr"`
+	Contents string `xml:",chardata"`
+}
+
+func junitTime(duration time.Duration) string {
+	return strconv.FormatFloat(duration.Seconds(), 'f', 3, 64)
+}
+
+// MarshalJUnit serializes the report as JUnit XML.
+//
+// Each test file is a test suite, and each test is a test case.
+// Failures contain the error message, and the error with code excerpts and stack trace
+func (r *Report) MarshalJUnit() ([]byte, error) {
+	testSuites := junitTestSuites{
+		Tests:      r.Tests(),
+		Failures:   r.Failures(),
+		Errors:     r.Errors(),
+		Time:       junitTime(r.duration()),
+		TestSuites: make([]junitTestSuite, 0, len(r.Suites)),
+	}
+
+	for _, suite := range r.Suites {
+		name := suite.Location.String()
+
+		testSuite := junitTestSuite{
+			Name:      name,
+			Tests:     len(suite.Results),
+			Failures:  suite.Results.Failed(),
+			Time:      junitTime(suite.duration()),
+			TestCases: make([]junitTestCase, 0, len(suite.Results)),
+		}
+
+		if suite.Error != nil {
+			testSuite.Errors = 1
+			testSuite.Error = &junitFailure{
+				Message:  errorMessage(suite.Error),
+				Contents: suite.Error.Error(),
+			}
+		}
+
+		for _, result := range suite.Results {
+			testCase := junitTestCase{
+				Name:      result.TestName,
+				ClassName: name,
+				Time:      junitTime(result.Duration),
+				SystemOut: strings.Join(result.Logs, "\n"),
+			}
+
+			if result.Error != nil {
+				testCase.Failure = &junitFailure{
+					Message:  errorMessage(result.Error),
+					Contents: result.Error.Error(),
+				}
+			}
+
+			testSuite.TestCases = append(testSuite.TestCases, testCase)
+		}
+
+		testSuites.TestSuites = append(testSuites.TestSuites, testSuite)
+	}
+
+	data, err := xml.MarshalIndent(testSuites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	data = append([]byte(xml.Header), data...)
+	return append(data, '\n'), nil
+}
+
+type jsonReport struct {
+	Tests    int         `json:"tests"`
+	Failures int         `json:"failures"`
+	Errors   int         `json:"errors"`
+	Suites   []jsonSuite `json:"suites"`
+}
+
+type jsonSuite struct {
+	Location string       `json:"location"`
+	Error    *jsonError   `json:"error,omitempty"`
+	Tests    []jsonResult `json:"tests"`
+}
+
+type jsonResult struct {
+	Name    string     `json:"name"`
+	Passed  bool       `json:"passed"`
+	Elapsed float64    `json:"elapsed"`
+	Error   *jsonError `json:"error,omitempty"`
+	Logs    []string   `json:"logs"`
+}
+
+type jsonError struct {
+	Message string `json:"message"`
+	Details string `json:"details"`
+}
+
+func newJSONError(err error) *jsonError {
+	if err == nil {
+		return nil
+	}
+	return &jsonError{
+		Message: errorMessage(err),
+		Details: err.Error(),
+	}
+}
+
+// MarshalJSON serializes the report as JSON.
+// Locations are serialized like in the other formats, i.e. test files by their path,
+// and durations in seconds
+func (r *Report) MarshalJSON() ([]byte, error) {
+	report := jsonReport{
+		Tests:    r.Tests(),
+		Failures: r.Failures(),
+		Errors:   r.Errors(),
+		Suites:   make([]jsonSuite, 0, len(r.Suites)),
+	}
+
+	for _, suite := range r.Suites {
+		jsonSuite := jsonSuite{
+			Location: suite.Location.String(),
+			Error:    newJSONError(suite.Error),
+			Tests:    make([]jsonResult, 0, len(suite.Results)),
+		}
+
+		for _, result := range suite.Results {
+			logs := result.Logs
+			if logs == nil {
+				logs = []string{}
+			}
+
+			jsonSuite.Tests = append(jsonSuite.Tests, jsonResult{
+				Name:    result.TestName,
+				Passed:  result.Passed(),
+				Elapsed: result.Duration.Seconds(),
+				Error:   newJSONError(result.Error),
+				Logs:    logs,
+			})
+		}
+
+		report.Suites = append(report.Suites, jsonSuite)
+	}
+
+	return json.Marshal(report)
+}
@@ -0,0 +1,528 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package testrunner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/activations"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/emulator"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/parser"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+)
+
+// testFunctionPrefix is the prefix of the names of test functions
+const testFunctionPrefix = "test"
+
+// setupFunctionName is the name of the function which is run before each test
+const setupFunctionName = "setup"
+
+// tearDownFunctionName is the name of the function which is run after each test
+const tearDownFunctionName = "tearDown"
+
+// Result is the result of running a test function
+type Result struct {
+	Location common.Location
+	TestName string
+	// Error is the error which failed the test, if any
+	Error    error
+	Duration time.Duration
+	// Logs are the messages logged by the test
+	Logs []string
+}
+
+func (r Result) Passed() bool {
+	return r.Error == nil
+}
+
+type Results []Result
+
+// Failed returns the number of failed tests
+func (r Results) Failed() int {
+	failed := 0
+	for _, result := range r {
+		if !result.Passed() {
+			failed++
+		}
+	}
+	return failed
+}
+
+// TestRunner runs the tests of Cadence test files.
+//
+// The test functions of a file are its top-level functions without parameters,
+// whose names start with `test`. Each test is run in a new interpreter,
+// i.e. with isolated state: the program is interpreted, the `setup` function
+// is invoked, if any, followed by the test function and the `tearDown` function, if any.
+//
+// Test files can import the `Test` contract, the `Crypto` contract,
+// and other files, by their path.
+// Relative paths of imported files, read files, and deployed contracts
+// are resolved against the directory of the test file
+type TestRunner struct {
+	// TestFilter, if set, selects the tests which are run by their name
+	TestFilter *regexp.Regexp
+	// CoverageReport, if set, collects the coverage of the tests.
+	// The test files and the `Test` and `Crypto` contracts are excluded
+	CoverageReport *runtime.CoverageReport
+	// EmulatorBackend, if set, is called to get the blockchain for each test.
+	// By default, each test uses a new in-memory blockchain, see emulator.Blockchain
+	EmulatorBackend func() stdlib.Blockchain
+}
+
+// contractProgramProvider is implemented by blockchains which provide the programs of deployed contracts,
+// so values of the contracts' types, e.g. events, can be used in tests
+type contractProgramProvider interface {
+	Program(location common.AddressLocation) (*interpreter.Program, error)
+}
+
+var _ contractProgramProvider = &emulator.Blockchain{}
+
+func NewTestRunner() *TestRunner {
+	return &TestRunner{}
+}
+
+// TestFunctionNames returns the names of the test functions of the given program,
+// in declaration order
+func TestFunctionNames(program *ast.Program) []string {
+	var names []string
+
+	for _, declaration := range program.FunctionDeclarations() {
+		name := declaration.Identifier.Identifier
+		if !strings.HasPrefix(name, testFunctionPrefix) {
+			continue
+		}
+
+		parameterList := declaration.ParameterList
+		if parameterList != nil && len(parameterList.Parameters) > 0 {
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// RunTests runs the tests of the test file with the given location and code.
+//
+// An error is returned if the file cannot be parsed or checked.
+// Failed tests are reported in the results
+func (r *TestRunner) RunTests(location common.Location, code []byte) (Results, error) {
+	file, err := r.newTestFile(location, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.CoverageReport != nil {
+		r.CoverageReport.ExcludeLocation(location)
+		r.CoverageReport.ExcludeLocation(stdlib.TestContractLocation)
+		r.CoverageReport.ExcludeLocation(stdlib.CryptoCheckerLocation)
+	}
+
+	var results Results
+
+	for _, name := range TestFunctionNames(file.program) {
+		if r.TestFilter != nil && !r.TestFilter.MatchString(name) {
+			continue
+		}
+
+		results = append(results, file.run(name))
+	}
+
+	return results, nil
+}
+
+// testFile is a checked test file, whose tests can be run.
+//
+// The test file provides the `log` function and the test framework to its tests
+type testFile struct {
+	runner   *TestRunner
+	location common.Location
+	codes    map[common.Location][]byte
+	checkers map[common.Location]*sema.Checker
+	program  *ast.Program
+	checker  *sema.Checker
+	// logs are the messages logged by the running test
+	logs        []string
+	logFunction stdlib.StandardLibraryValue
+	// blockchain is the blockchain of the running test, if any
+	blockchain stdlib.Blockchain
+}
+
+var _ stdlib.Logger = &testFile{}
+var _ stdlib.TestFramework = &testFile{}
+
+func (r *TestRunner) newTestFile(location common.Location, code []byte) (*testFile, error) {
+	file := &testFile{
+		runner:   r,
+		location: location,
+		codes:    map[common.Location][]byte{},
+		checkers: map[common.Location]*sema.Checker{},
+	}
+	file.logFunction = stdlib.NewLogFunction(file)
+
+	checker, err := file.check(location, code, nil)
+	if err != nil {
+		return nil, file.newError(err)
+	}
+
+	file.checker = checker
+	file.program = checker.Program
+
+	return file, nil
+}
+
+func (f *testFile) newError(err error) error {
+	return runtime.Error{
+		Err:      err,
+		Location: f.location,
+		Codes:    f.codes,
+	}
+}
+
+func (f *testFile) ProgramLog(message string) error {
+	f.logs = append(f.logs, message)
+	return nil
+}
+
+func (f *testFile) ReadFile(path string) (string, error) {
+	content, err := os.ReadFile(f.resolvePath(path))
+	if err != nil {
+		return "", errors.NewDefaultUserError("cannot read file: %s", err)
+	}
+	return string(content), nil
+}
+
+func (f *testFile) EmulatorBackend() stdlib.Blockchain {
+	if f.runner.EmulatorBackend != nil {
+		f.blockchain = f.runner.EmulatorBackend()
+	} else {
+		blockchain := emulator.NewBlockchainWithConfig(runtime.Config{
+			AtreeValidationEnabled: true,
+			CoverageReport:         f.runner.CoverageReport,
+		})
+		blockchain.SetDirectory(f.directory())
+		f.blockchain = blockchain
+	}
+	return f.blockchain
+}
+
+// directory returns the directory of the test file,
+// which relative paths of imported files, read files, and contracts are resolved against.
+// It returns an empty string if the test file is not a file
+func (f *testFile) directory() string {
+	stringLocation, ok := f.location.(common.StringLocation)
+	if !ok {
+		return ""
+	}
+	return filepath.Dir(string(stringLocation))
+}
+
+// resolvePath resolves the given path relative to the directory of the test file,
+// unless the path is absolute
+func (f *testFile) resolvePath(path string) string {
+	directory := f.directory()
+	if directory == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(directory, path)
+}
+
+// check parses and checks the program with the given location and code.
+// Programs imported by the checked program are parsed and checked, too
+func (f *testFile) check(
+	location common.Location,
+	code []byte,
+	importingChecker *sema.Checker,
+) (*sema.Checker, error) {
+	f.codes[location] = code
+
+	program, err := parser.ParseProgram(nil, code, parser.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	var checker *sema.Checker
+	if importingChecker == nil {
+		checker, err = sema.NewChecker(program, location, nil, f.newCheckerConfig())
+	} else {
+		checker, err = importingChecker.SubChecker(program, location)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f.checkers[location] = checker
+
+	err = checker.Check()
+	if err != nil {
+		return nil, err
+	}
+
+	return checker, nil
+}
+
+func (f *testFile) newCheckerConfig() *sema.Config {
+	baseValueActivation := sema.NewVariableActivation(sema.BaseValueActivation)
+	baseValueActivation.DeclareValue(stdlib.AssertFunction)
+	baseValueActivation.DeclareValue(stdlib.PanicFunction)
+	baseValueActivation.DeclareValue(f.logFunction)
+
+	return &sema.Config{
+		BaseValueActivation:  baseValueActivation,
+		AccessCheckMode:      sema.AccessCheckModeStrict,
+		ImportHandler:        f.importChecker,
+		ContractValueHandler: stdlib.TestCheckerContractValueHandler,
+	}
+}
+
+func (f *testFile) importChecker(
+	checker *sema.Checker,
+	importedLocation common.Location,
+	_ ast.Range,
+) (sema.Import, error) {
+
+	switch importedLocation {
+	case stdlib.TestContractLocation:
+		return sema.ElaborationImport{
+			Elaboration: stdlib.GetTestContractType().Checker.Elaboration,
+		}, nil
+
+	case stdlib.CryptoCheckerLocation:
+		return sema.ElaborationImport{
+			Elaboration: stdlib.CryptoChecker().Elaboration,
+		}, nil
+	}
+
+	importedChecker, ok := f.checkers[importedLocation]
+	if !ok {
+		stringLocation, ok := importedLocation.(common.StringLocation)
+		if !ok {
+			return nil, fmt.Errorf(
+				"cannot import `%s`. only files and the Test and Crypto contracts are supported",
+				importedLocation,
+			)
+		}
+
+		code, err := os.ReadFile(f.resolvePath(string(stringLocation)))
+		if err != nil {
+			return nil, err
+		}
+
+		importedChecker, err = f.check(importedLocation, code, checker)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sema.ElaborationImport{
+		Elaboration: importedChecker.Elaboration,
+	}, nil
+}
+
+// run runs the test with the given name
+func (f *testFile) run(name string) Result {
+	f.logs = nil
+	f.blockchain = nil
+
+	start := time.Now()
+	err := f.runTest(name)
+	duration := time.Since(start)
+
+	result := Result{
+		Location: f.location,
+		TestName: name,
+		Duration: duration,
+		Logs:     f.logs,
+	}
+	if err != nil {
+		result.Error = f.newError(err)
+	}
+
+	return result
+}
+
+func (f *testFile) runTest(name string) error {
+	inter, err := interpreter.NewInterpreter(
+		interpreter.ProgramFromChecker(f.checker),
+		f.location,
+		f.newInterpreterConfig(),
+	)
+	if err != nil {
+		return err
+	}
+
+	err = inter.Interpret()
+	if err != nil {
+		return err
+	}
+
+	if inter.Globals.Contains(setupFunctionName) {
+		_, err = inter.Invoke(setupFunctionName)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = inter.Invoke(name)
+
+	// The test is torn down even if it failed,
+	// but the failure of the test is reported
+	if inter.Globals.Contains(tearDownFunctionName) {
+		_, tearDownErr := inter.Invoke(tearDownFunctionName)
+		if err == nil {
+			err = tearDownErr
+		}
+	}
+
+	return err
+}
+
+func (f *testFile) newInterpreterConfig() *interpreter.Config {
+	var uuid uint64
+
+	baseActivation := activations.NewActivation(nil, interpreter.BaseActivation)
+	interpreter.Declare(baseActivation, stdlib.AssertFunction)
+	interpreter.Declare(baseActivation, stdlib.PanicFunction)
+	interpreter.Declare(baseActivation, f.logFunction)
+
+	config := &interpreter.Config{
+		Storage:               interpreter.NewInMemoryStorage(nil),
+		BaseActivation:        baseActivation,
+		ImportLocationHandler: f.importInterpreter,
+		ContractValueHandler:  stdlib.NewTestInterpreterContractValueHandler(f),
+		UUIDHandler: func() (uint64, error) {
+			uuid++
+			return uuid, nil
+		},
+	}
+
+	coverageReport := f.runner.CoverageReport
+	if coverageReport != nil {
+		config.OnStatement = func(inter *interpreter.Interpreter, statement ast.Statement) {
+			inspectProgram(coverageReport, inter)
+			coverageReport.AddLineHit(inter.Location, statement.StartPosition().Line)
+		}
+
+		config.OnBranch = func(
+			inter *interpreter.Interpreter,
+			kind interpreter.BranchKind,
+			element ast.Element,
+			branch int,
+		) {
+			inspectProgram(coverageReport, inter)
+			coverageReport.AddBranchHit(inter.Location, kind, element, branch)
+		}
+
+		config.OnInterpretedFunctionInvocation = func(
+			_ *interpreter.Interpreter,
+			function *interpreter.InterpretedFunctionValue,
+		) {
+			declaration := function.Declaration
+			if declaration == nil {
+				return
+			}
+
+			// The function is declared in the program of its own interpreter,
+			// which may differ from the invoking interpreter
+			inter := function.Interpreter
+			inspectProgram(coverageReport, inter)
+			coverageReport.AddFunctionHit(inter.Location, declaration)
+		}
+	}
+
+	return config
+}
+
+func inspectProgram(coverageReport *runtime.CoverageReport, inter *interpreter.Interpreter) {
+	location := inter.Location
+	if !coverageReport.IsLocationInspected(location) {
+		coverageReport.InspectProgram(location, inter.Program.Program)
+	}
+}
+
+func (f *testFile) importInterpreter(inter *interpreter.Interpreter, location common.Location) interpreter.Import {
+	var checker *sema.Checker
+
+	switch location {
+	case stdlib.TestContractLocation:
+		checker = stdlib.GetTestContractType().Checker
+
+	case stdlib.CryptoCheckerLocation:
+		checker = stdlib.CryptoChecker()
+
+	default:
+		var ok bool
+		checker, ok = f.checkers[location]
+		if !ok {
+			return f.importContract(inter, location)
+		}
+	}
+
+	subInterpreter, err := inter.NewSubInterpreter(
+		interpreter.ProgramFromChecker(checker),
+		location,
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	return interpreter.InterpreterImport{
+		Interpreter: subInterpreter,
+	}
+}
+
+// importContract imports the contract with the given location from the blockchain of the running test
+func (f *testFile) importContract(inter *interpreter.Interpreter, location common.Location) interpreter.Import {
+	addressLocation, ok := location.(common.AddressLocation)
+	if !ok {
+		panic(errors.NewUnexpectedError("cannot import unchecked location: %s", location))
+	}
+
+	provider, ok := f.blockchain.(contractProgramProvider)
+	if !ok {
+		panic(errors.NewDefaultUserError("cannot import contract from blockchain: %s", location))
+	}
+
+	program, err := provider.Program(addressLocation)
+	if err != nil {
+		panic(err)
+	}
+
+	subInterpreter, err := inter.NewSubInterpreter(program, location)
+	if err != nil {
+		panic(err)
+	}
+
+	return interpreter.InterpreterImport{
+		Interpreter: subInterpreter,
+	}
+}
@@ -0,0 +1,695 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package testrunner
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/parser"
+)
+
+func TestTestFunctionNames(t *testing.T) {
+
+	t.Parallel()
+
+	program, err := parser.ParseProgram(
+		nil,
+		[]byte(`
+          pub fun setup() {}
+
+          pub fun testB() {}
+
+          pub fun helper() {}
+
+          pub fun testWithParameter(_ x: Int) {}
+
+          pub fun testA() {}
+
+          pub fun tearDown() {}
+        `),
+		parser.Config{},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		[]string{"testB", "testA"},
+		TestFunctionNames(program),
+	)
+}
+
+func TestTestRunnerRunTests(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      import Test
+
+      pub var counter = 0
+
+      pub fun setup() {
+          counter = counter + 1
+          log("setup")
+      }
+
+      pub fun tearDown() {
+          log("tearDown")
+      }
+
+      pub fun testPass() {
+          Test.assertEqual(1, counter)
+          counter = 10
+      }
+
+      pub fun testIsolated() {
+          Test.assertEqual(1, counter)
+      }
+
+      pub fun testFail() {
+          Test.assertEqual(2, counter)
+      }
+
+      pub fun testPanic() {
+          fail()
+      }
+
+      pub fun fail() {
+          panic("broken")
+      }
+    `
+
+	location := common.StringLocation("math_test.cdc")
+
+	results, err := NewTestRunner().RunTests(location, []byte(code))
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	assert.Equal(t, 2, results.Failed())
+
+	names := make([]string, 0, len(results))
+	for _, result := range results {
+		names = append(names, result.TestName)
+		assert.Equal(t, location, result.Location)
+		assert.Equal(t, []string{`"setup"`, `"tearDown"`}, result.Logs)
+	}
+	assert.Equal(t,
+		[]string{"testPass", "testIsolated", "testFail", "testPanic"},
+		names,
+	)
+
+	assert.True(t, results[0].Passed())
+	assert.True(t, results[1].Passed())
+
+	require.Error(t, results[2].Error)
+	assert.ErrorAs(t, results[2].Error, &runtime.Error{})
+	assert.Equal(t,
+		"assertion failed: not equal: expected: 2, actual: 1",
+		errorMessage(results[2].Error),
+	)
+
+	// The error of the panicking test includes the stack trace
+
+	require.Error(t, results[3].Error)
+	assert.Equal(t, "panic: broken", errorMessage(results[3].Error))
+	assert.Contains(t, results[3].Error.Error(), "fail()")
+	assert.Contains(t, results[3].Error.Error(), `panic("broken")`)
+}
+
+func TestTestRunnerTestFilter(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      pub fun testAdd() {}
+
+      pub fun testSubtract() {}
+
+      pub fun testAddMore() {}
+    `
+
+	runner := NewTestRunner()
+	runner.TestFilter = regexp.MustCompile("^testAdd")
+
+	results, err := runner.RunTests(common.StringLocation("test.cdc"), []byte(code))
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "testAdd", results[0].TestName)
+	assert.Equal(t, "testAddMore", results[1].TestName)
+}
+
+func TestTestRunnerSetupFailure(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      pub fun setup() {
+          panic("no setup")
+      }
+
+      pub fun tearDown() {
+          log("tearDown")
+      }
+
+      pub fun testA() {
+          log("test")
+      }
+    `
+
+	results, err := NewTestRunner().RunTests(common.StringLocation("test.cdc"), []byte(code))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	assert.Equal(t, "panic: no setup", errorMessage(results[0].Error))
+	assert.Empty(t, results[0].Logs)
+}
+
+func TestTestRunnerCheckError(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      pub fun testA() {
+          let x: Int = "one"
+      }
+    `
+
+	results, err := NewTestRunner().RunTests(common.StringLocation("test.cdc"), []byte(code))
+	require.Error(t, err)
+	assert.Nil(t, results)
+
+	assert.ErrorAs(t, err, &runtime.Error{})
+	assert.Contains(t, err.Error(), `let x: Int = "one"`)
+}
+
+func TestTestRunnerImportAndCoverage(t *testing.T) {
+
+	t.Parallel()
+
+	directory := t.TempDir()
+
+	contractPath := filepath.Join(directory, "math.cdc")
+	err := os.WriteFile(
+		contractPath,
+		[]byte(`
+          pub fun abs(_ x: Int): Int {
+              if x < 0 {
+                  return -x
+              }
+              return x
+          }
+        `),
+		0644,
+	)
+	require.NoError(t, err)
+
+	code := []byte(`
+      import Test
+      import "` + contractPath + `"
+
+      pub fun testAbs() {
+          Test.assertEqual(1, abs(1))
+      }
+    `)
+
+	coverageReport := runtime.NewCoverageReport()
+
+	runner := NewTestRunner()
+	runner.CoverageReport = coverageReport
+
+	location := common.StringLocation(filepath.Join(directory, "math_test.cdc"))
+
+	results, err := runner.RunTests(location, code)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Error)
+
+	contractLocation := common.StringLocation(contractPath)
+
+	assert.Equal(t, 1, coverageReport.TotalLocations())
+	assert.True(t, coverageReport.IsLocationExcluded(location))
+
+	locationCoverage := coverageReport.Coverage[contractLocation]
+	assert.Equal(t, 3, locationCoverage.Statements)
+	assert.Equal(t, 2, locationCoverage.CoveredLines())
+	assert.Equal(t, "66.7%", locationCoverage.Percentage())
+}
+
+func TestTestRunnerRelativePaths(t *testing.T) {
+
+	t.Parallel()
+
+	// The test file is in another directory than the working directory,
+	// and refers to the files next to it by relative paths
+
+	directory := t.TempDir()
+
+	err := os.WriteFile(
+		filepath.Join(directory, "math.cdc"),
+		[]byte(`
+          pub fun double(_ x: Int): Int {
+              return x * 2
+          }
+        `),
+		0644,
+	)
+	require.NoError(t, err)
+
+	err = os.WriteFile(
+		filepath.Join(directory, "Counter.cdc"),
+		[]byte(`
+          pub contract Counter {
+              pub let count: Int
+
+              init() {
+                  self.count = 1
+              }
+          }
+        `),
+		0644,
+	)
+	require.NoError(t, err)
+
+	err = os.WriteFile(
+		filepath.Join(directory, "data.txt"),
+		[]byte("data"),
+		0644,
+	)
+	require.NoError(t, err)
+
+	code := []byte(`
+      import Test
+      import "math.cdc"
+
+      pub fun testRelativePaths() {
+          Test.assertEqual(4, double(2))
+          Test.assertEqual("data", Test.readFile("data.txt"))
+
+          let err = Test.deployContract(name: "Counter", path: "Counter.cdc", arguments: [])
+          Test.expect(err, Test.beNil())
+      }
+    `)
+
+	runner := NewTestRunner()
+
+	location := common.StringLocation(filepath.Join(directory, "paths_test.cdc"))
+
+	results, err := runner.RunTests(location, code)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Error)
+}
+
+func TestTestRunnerBlockchain(t *testing.T) {
+
+	t.Parallel()
+
+	directory := t.TempDir()
+
+	contractPath := filepath.Join(directory, "Counter.cdc")
+	err := os.WriteFile(
+		contractPath,
+		[]byte(`
+          pub contract Counter {
+
+              pub event Incremented(count: Int)
+
+              pub var count: Int
+
+              init(start: Int) {
+                  self.count = start
+              }
+
+              pub fun increment() {
+                  self.count = self.count + 1
+                  log(self.count)
+                  emit Incremented(count: self.count)
+              }
+          }
+        `),
+		0644,
+	)
+	require.NoError(t, err)
+
+	code := []byte(`
+      import Test
+
+      pub fun setup() {
+          let err = Test.deployContract(name: "Counter", path: "` + contractPath + `", arguments: [40])
+          Test.expect(err, Test.beNil())
+      }
+
+      pub fun count(): Int {
+          let scriptResult = Test.executeScript(
+              "import Counter from 0x1 pub fun main(x: Int): Int { return Counter.count + x }",
+              [0]
+          )
+          Test.expect(scriptResult, Test.beSucceeded())
+          return scriptResult.returnValue! as! Int
+      }
+
+      pub fun increment(): Test.TransactionResult {
+          let account = Test.createAccount()
+          return Test.executeTransaction(Test.Transaction(
+              code: "import Counter from 0x1 transaction { prepare(signer: AuthAccount) { Counter.increment() } }",
+              authorizers: [account.address],
+              signers: [account],
+              arguments: []
+          ))
+      }
+
+      pub fun height(): UInt64 {
+          let scriptResult = Test.executeScript("pub fun main(): UInt64 { return getCurrentBlock().height }", [])
+          return scriptResult.returnValue! as! UInt64
+      }
+
+      pub fun testIncrement() {
+          Test.assertEqual(40, count())
+          Test.expect(increment(), Test.beSucceeded())
+          Test.assertEqual(41, count())
+          Test.assertEqual(["41"], Test.logs())
+
+          let events = Test.eventsOfType(CompositeType("A.0000000000000001.Counter.Incremented")!)
+          Test.assertEqual(1, events.length)
+      }
+
+      pub fun testMissingSigner() {
+          let account = Test.createAccount()
+          let txResult = Test.executeTransaction(Test.Transaction(
+              code: "transaction { prepare(signer: AuthAccount) {} }",
+              authorizers: [account.address],
+              signers: [],
+              arguments: []
+          ))
+          Test.expect(txResult, Test.beFailed())
+      }
+
+      pub fun testResetAndSnapshots() {
+          let deployed = height()
+          increment()
+          Test.assertEqual(41, count())
+
+          Test.createSnapshot(name: "incremented")
+          Test.reset(to: deployed)
+          Test.assertEqual(40, count())
+
+          Test.loadSnapshot(name: "incremented")
+          Test.assertEqual(41, count())
+      }
+
+      pub fun testMoveTime() {
+          let script = "pub fun main(): UFix64 { return getCurrentBlock().timestamp }"
+          let before = Test.executeScript(script, []).returnValue! as! UFix64
+          Test.moveTime(by: 3600.0)
+          let after = Test.executeScript(script, []).returnValue! as! UFix64
+          Test.assertEqual(3600.0, after - before)
+      }
+    `)
+
+	coverageReport := runtime.NewCoverageReport()
+
+	runner := NewTestRunner()
+	runner.CoverageReport = coverageReport
+
+	results, err := runner.RunTests(common.StringLocation("counter_test.cdc"), code)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	for _, result := range results {
+		assert.NoError(t, result.Error, result.TestName)
+	}
+
+	contractLocation := common.NewAddressLocation(nil, common.MustBytesToAddress([]byte{0x1}), "Counter")
+
+	locationCoverage := coverageReport.Coverage[contractLocation]
+	require.NotNil(t, locationCoverage)
+	assert.Equal(t, "100.0%", locationCoverage.Percentage())
+}
+
+func TestTestRunnerContractFunctionMocks(t *testing.T) {
+
+	t.Parallel()
+
+	directory := t.TempDir()
+
+	oraclePath := filepath.Join(directory, "Oracle.cdc")
+	err := os.WriteFile(
+		oraclePath,
+		[]byte(`
+          pub contract Oracle {
+              pub fun price(_ symbol: String): UFix64 {
+                  return 1.0
+              }
+          }
+        `),
+		0644,
+	)
+	require.NoError(t, err)
+
+	consumerPath := filepath.Join(directory, "Consumer.cdc")
+	err = os.WriteFile(
+		consumerPath,
+		[]byte(`
+          import Oracle from 0x1
+
+          pub contract Consumer {
+              pub fun value(_ symbol: String, amount: UFix64): UFix64 {
+                  return Oracle.price(symbol) * amount
+              }
+          }
+        `),
+		0644,
+	)
+	require.NoError(t, err)
+
+	code := []byte(`
+      import Test
+
+      pub fun setup() {
+          Test.expect(Test.deployContract(name: "Oracle", path: "` + oraclePath + `", arguments: []), Test.beNil())
+          Test.expect(Test.deployContract(name: "Consumer", path: "` + consumerPath + `", arguments: []), Test.beNil())
+      }
+
+      pub fun oracle(): Type {
+          return CompositeType("A.0000000000000001.Oracle")!
+      }
+
+      pub fun value(_ symbol: String): UFix64 {
+          let scriptResult = Test.executeScript(
+              "import Consumer from 0x1 pub fun main(s: String): UFix64 { return Consumer.value(s, amount: 10.0) }",
+              [symbol]
+          )
+          Test.expect(scriptResult, Test.beSucceeded())
+          return scriptResult.returnValue! as! UFix64
+      }
+
+      pub fun testMock() {
+          Test.mock(oracle(), function: "price", with: fun (symbol: String): UFix64 {
+              if symbol == "FLOW" {
+                  return 2.0
+              }
+              return 3.0
+          })
+
+          Test.assertEqual(20.0, value("FLOW"))
+          Test.assertEqual(30.0, value("BTC"))
+
+          Test.expectCalledTimes(oracle(), function: "price", times: 2)
+          Test.expectCalledWith(oracle(), function: "price", arguments: ["BTC"])
+      }
+
+      pub fun testStub() {
+          Test.stub(oracle(), function: "price", returnValue: 5.0)
+          Test.assertEqual(50.0, value("FLOW"))
+          Test.expectCalled(oracle(), function: "price")
+      }
+
+      pub fun testSpyAndUnmock() {
+          Test.spy(oracle(), function: "price")
+          Test.assertEqual(10.0, value("FLOW"))
+
+          let calls: [[AnyStruct]] = [["FLOW"]]
+          Test.assertEqual(calls, Test.calls(oracle(), function: "price"))
+
+          Test.unmock(oracle(), function: "price")
+          Test.assertEqual(10.0, value("FLOW"))
+          Test.expectCalledTimes(oracle(), function: "price", times: 1)
+      }
+
+      pub fun testReplacementFails() {
+          Test.mock(oracle(), function: "price", with: fun (symbol: String): UFix64 {
+              panic("oracle is down")
+          })
+
+          let scriptResult = Test.executeScript(
+              "import Consumer from 0x1 pub fun main(): UFix64 { return Consumer.value(\"FLOW\", amount: 1.0) }",
+              []
+          )
+          Test.assertError(scriptResult, errorMessage: "oracle is down")
+      }
+
+      pub fun testNotCalled() {
+          Test.spy(oracle(), function: "price")
+          Test.expectCalled(oracle(), function: "price")
+      }
+    `)
+
+	results, err := NewTestRunner().RunTests(common.StringLocation("consumer_test.cdc"), code)
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+
+	for _, result := range results[:4] {
+		assert.NoError(t, result.Error, result.TestName)
+	}
+
+	assert.Equal(t, "testNotCalled", results[4].TestName)
+	require.ErrorContains(t, results[4].Error, "expected function price to be called")
+}
+
+func newTestReport() *Report {
+	report := NewReport()
+
+	report.Add(
+		common.StringLocation("a_test.cdc"),
+		Results{
+			{
+				Location: common.StringLocation("a_test.cdc"),
+				TestName: "testPass",
+				Duration: 1500 * time.Millisecond,
+				Logs:     []string{`"hello"`},
+			},
+			{
+				Location: common.StringLocation("a_test.cdc"),
+				TestName: "testFail",
+				Duration: 500 * time.Millisecond,
+				Error:    errors.New("assertion failed"),
+			},
+		},
+		nil,
+	)
+
+	report.Add(
+		common.StringLocation("b_test.cdc"),
+		nil,
+		errors.New("cannot check"),
+	)
+
+	return report
+}
+
+func TestReport(t *testing.T) {
+
+	t.Parallel()
+
+	report := newTestReport()
+
+	assert.Equal(t, 2, report.Tests())
+	assert.Equal(t, 1, report.Failures())
+	assert.Equal(t, 1, report.Errors())
+	assert.False(t, report.Passed())
+
+	assert.True(t, NewReport().Passed())
+}
+
+func TestReportMarshalJUnit(t *testing.T) {
+
+	t.Parallel()
+
+	data, err := newTestReport().MarshalJUnit()
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		`<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="2" failures="1" errors="1" time="2.000">
+  <testsuite name="a_test.cdc" tests="2" failures="1" errors="0" time="2.000">
+    <testcase name="testPass" classname="a_test.cdc" time="1.500">
+      <system-out>&#34;hello&#34;</system-out>
+    </testcase>
+    <testcase name="testFail" classname="a_test.cdc" time="0.500">
+      <failure message="assertion failed">assertion failed</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="b_test.cdc" tests="0" failures="0" errors="1" time="0.000">
+    <error message="cannot check">cannot check</error>
+  </testsuite>
+</testsuites>
+`,
+		string(data),
+	)
+}
+
+func TestReportMarshalJSON(t *testing.T) {
+
+	t.Parallel()
+
+	data, err := json.Marshal(newTestReport())
+	require.NoError(t, err)
+
+	assert.JSONEq(t,
+		`
+          {
+            "tests": 2,
+            "failures": 1,
+            "errors": 1,
+            "suites": [
+              {
+                "location": "a_test.cdc",
+                "tests": [
+                  {
+                    "name": "testPass",
+                    "passed": true,
+                    "elapsed": 1.5,
+                    "logs": ["\"hello\""]
+                  },
+                  {
+                    "name": "testFail",
+                    "passed": false,
+                    "elapsed": 0.5,
+                    "error": {
+                      "message": "assertion failed",
+                      "details": "assertion failed"
+                    },
+                    "logs": []
+                  }
+                ]
+              },
+              {
+                "location": "b_test.cdc",
+                "error": {
+                  "message": "cannot check",
+                  "details": "cannot check"
+                },
+                "tests": []
+              }
+            ]
+          }
+        `,
+		string(data),
+	)
+}
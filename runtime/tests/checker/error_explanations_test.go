@@ -0,0 +1,136 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/parser"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestCheckErrorExplanationExamples(t *testing.T) {
+
+	t.Parallel()
+
+	baseValueActivation := sema.NewVariableActivation(sema.BaseValueActivation)
+	for _, value := range stdlib.DefaultStandardLibraryValues(nil) {
+		baseValueActivation.DeclareValue(value)
+	}
+
+	// Examples may import the contract `Example` from address 0x1
+
+	const exampleContract = `
+      pub contract Example {
+
+          pub event Greeted(name: String)
+
+          pub resource Vault {}
+
+          pub fun greet(name: String): String {
+              emit Greeted(name: name)
+              return "Hello, ".concat(name)
+          }
+
+          init() {}
+      }
+    `
+
+	exampleContractLocation := common.AddressLocation{
+		Address: common.MustBytesToAddress([]byte{0x1}),
+	}
+
+	var check func(t *testing.T, code string, location common.Location) (*sema.Checker, error)
+	check = func(t *testing.T, code string, location common.Location) (*sema.Checker, error) {
+		return ParseAndCheckWithOptions(t,
+			code,
+			ParseAndCheckOptions{
+				Location: location,
+				ParseOptions: parser.Config{
+					StaticModifierEnabled: true,
+					NativeModifierEnabled: true,
+				},
+				Config: &sema.Config{
+					AccessCheckMode:              sema.AccessCheckModeStrict,
+					BaseValueActivation:          baseValueActivation,
+					AttachmentsEnabled:           true,
+					AccountLinkingEnabled:        true,
+					CapabilityControllersEnabled: true,
+					ImportHandler: func(_ *sema.Checker, location common.Location, _ ast.Range) (sema.Import, error) {
+						if location != exampleContractLocation {
+							return nil, nil
+						}
+
+						checker, err := check(t, exampleContract, exampleContractLocation)
+						if err != nil {
+							return nil, err
+						}
+
+						return sema.ElaborationImport{
+							Elaboration: checker.Elaboration,
+						}, nil
+					},
+				},
+			},
+		)
+	}
+
+	for _, explanation := range errors.ErrorExplanations() {
+
+		code := explanation.Code
+		if !strings.HasPrefix(string(code), "C") {
+			continue
+		}
+
+		examples := utils.ErrorExplanationExamples(explanation)
+
+		t.Run(string(code), func(t *testing.T) {
+
+			t.Parallel()
+
+			require.NotEmpty(t, examples)
+
+			for i, example := range examples {
+				if example.Ignore {
+					continue
+				}
+
+				_, err := check(t, example.Code, utils.TestLocation)
+
+				// The first example is the erroneous code,
+				// all following examples are fixed code
+
+				if i == 0 {
+					require.Error(t, err)
+					require.Contains(t, utils.ErrorCodes(err), code)
+				} else {
+					require.NoError(t, err, "example %d", i+1)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,428 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package differential implements differential testing
+// of the interpreter and the compiler.
+//
+// Each function of a program is executed both by the interpreter,
+// and compiled to WebAssembly and executed by the pure-Go WebAssembly executor.
+// The results, errors, and metered computation of both executions are compared.
+package differential
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/compiler"
+	"github.com/onflow/cadence/runtime/compiler/ir"
+	"github.com/onflow/cadence/runtime/compiler/wasm"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/parser"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/tests/utils"
+	"github.com/onflow/cadence/vm"
+)
+
+// Result is the result of executing a function
+type Result struct {
+	Value interpreter.Value
+	Err   error
+	// Computation is the metered computation, by kind.
+	// Statements are only metered by the interpreter,
+	// and WebAssembly instructions are only metered by compiled code,
+	// so they are not included
+	Computation map[common.ComputationKind]uint
+	// interpreter is the interpreter which created the value
+	interpreter *interpreter.Interpreter
+}
+
+func (r Result) String() string {
+	if r.Err != nil {
+		return fmt.Sprintf("error %s (%s), computation %v", errorKind(r.Err), r.Err, r.Computation)
+	}
+	return fmt.Sprintf("value %s, computation %v", r.valueString(), r.Computation)
+}
+
+// Mismatch is a difference between the interpreted and the compiled execution of a function
+type Mismatch struct {
+	Function    string
+	Arguments   []interpreter.Value
+	Interpreted Result
+	Compiled    Result
+	Reason      string
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf(
+		"%s(%v): %s\n  interpreted: %s\n  compiled:    %s",
+		m.Function,
+		m.Arguments,
+		m.Reason,
+		m.Interpreted,
+		m.Compiled,
+	)
+}
+
+// Check parses and checks the given program
+func Check(code string) (*sema.Checker, error) {
+	program, err := parser.ParseProgram(nil, []byte(code), parser.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	checker, err := sema.NewChecker(
+		program,
+		utils.TestLocation,
+		nil,
+		&sema.Config{
+			AccessCheckMode:            sema.AccessCheckModeNotSpecifiedUnrestricted,
+			ExtendedElaborationEnabled: true,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checker.Check()
+	if err != nil {
+		return nil, err
+	}
+
+	return checker, nil
+}
+
+// Compile compiles all functions of the checked program to a WebAssembly binary.
+// It returns false if the program uses features which are not supported by the compiler yet
+func Compile(checker *sema.Checker) (_ []byte, ok bool, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			if _, isUnsupported := recovered.(compiler.UnsupportedError); !isUnsupported {
+				panic(recovered)
+			}
+			ok = false
+		}
+	}()
+
+	comp := compiler.NewCompiler(checker)
+	comp.InterpretedComposites = true
+	funcs := comp.VisitProgram(checker.Program).([]*ir.Func)
+
+	module := compiler.GenerateWasm(funcs)
+
+	var buf wasm.Buffer
+	err = wasm.NewWASMWriter(&buf).WriteModule(module)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return buf.Bytes(), true, nil
+}
+
+// ComputationLimit is the maximum computation of an execution.
+// It ensures that programs which do not terminate,
+// e.g. generated or minimized programs, fail eventually
+const ComputationLimit = 100_000
+
+// ComputationLimitExceededError is reported when an execution exceeds the computation limit
+type ComputationLimitExceededError struct{}
+
+var _ errors.UserError = ComputationLimitExceededError{}
+
+func (ComputationLimitExceededError) IsUserError() {}
+
+func (ComputationLimitExceededError) Error() string {
+	return fmt.Sprintf("computation limit exceeded: %d", ComputationLimit)
+}
+
+// newInterpreter returns a new interpreter for the checked program,
+// which has declared the program's global declarations,
+// and which meters computation into the given map
+func newInterpreter(checker *sema.Checker, computation map[common.ComputationKind]uint) (*interpreter.Interpreter, error) {
+
+	var uuid uint64
+
+	metering := false
+	var total uint
+
+	inter, err := interpreter.NewInterpreter(
+		interpreter.ProgramFromChecker(checker),
+		checker.Location,
+		&interpreter.Config{
+			Storage: interpreter.NewInMemoryStorage(nil),
+			UUIDHandler: func() (uint64, error) {
+				uuid++
+				return uuid, nil
+			},
+			OnMeterComputation: func(compKind common.ComputationKind, intensity uint) {
+				if !metering {
+					return
+				}
+
+				// Only the compiled code executes WebAssembly instructions,
+				// all other kinds of computation must be equal
+				if compKind == common.ComputationKindWasmInstruction {
+					return
+				}
+				computation[compKind] += intensity
+
+				total += intensity
+				if total > ComputationLimit {
+					panic(ComputationLimitExceededError{})
+				}
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = inter.Interpret()
+	if err != nil {
+		return nil, err
+	}
+
+	// Only meter the execution of the function, not the program's global declarations
+
+	metering = true
+
+	return inter, nil
+}
+
+// Interpret executes the function of the checked program with the given arguments using the interpreter
+func Interpret(checker *sema.Checker, function string, arguments []interpreter.Value) (Result, error) {
+	computation := map[common.ComputationKind]uint{}
+
+	inter, err := newInterpreter(checker, computation)
+	if err != nil {
+		return Result{}, err
+	}
+
+	value, err := inter.Invoke(function, arguments...)
+
+	return Result{
+		Value:       value,
+		Err:         err,
+		Computation: computation,
+		interpreter: inter,
+	}, nil
+}
+
+// Execute executes the function of the compiled program with the given arguments
+// using the pure-Go WebAssembly executor
+func Execute(checker *sema.Checker, binary []byte, function string, arguments []interpreter.Value) (Result, error) {
+	computation := map[common.ComputationKind]uint{}
+
+	inter, err := newInterpreter(checker, computation)
+	if err != nil {
+		return Result{}, err
+	}
+
+	machine, err := vm.NewExecutorVM(binary, inter)
+	if err != nil {
+		return Result{}, err
+	}
+
+	value, err := machine.Invoke(function, arguments...)
+
+	return Result{
+		Value:       value,
+		Err:         err,
+		Computation: computation,
+		interpreter: inter,
+	}, nil
+}
+
+// Compare executes all functions of the given program both ways, and returns all mismatches.
+// It returns false if the program uses features which are not supported by the compiler yet
+func Compare(code string) (_ []Mismatch, ok bool, err error) {
+
+	checker, err := Check(code)
+	if err != nil {
+		return nil, false, err
+	}
+
+	binary, ok, err := Compile(checker)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+
+	var mismatches []Mismatch
+
+	for _, declaration := range checker.Program.FunctionDeclarations() {
+		argumentSets, ok := functionArguments(declaration)
+		if !ok {
+			continue
+		}
+
+		function := declaration.Identifier.Identifier
+
+		for _, arguments := range argumentSets {
+
+			interpreted, err := Interpret(checker, function, arguments())
+			if err != nil {
+				return nil, false, err
+			}
+
+			compiled, err := Execute(checker, binary, function, arguments())
+			if err != nil {
+				return nil, false, err
+			}
+
+			reason := compareResults(interpreted, compiled)
+			if reason == "" {
+				continue
+			}
+
+			mismatches = append(
+				mismatches,
+				Mismatch{
+					Function:    function,
+					Arguments:   arguments(),
+					Interpreted: interpreted,
+					Compiled:    compiled,
+					Reason:      reason,
+				},
+			)
+		}
+	}
+
+	return mismatches, true, nil
+}
+
+// compareResults returns the reason why the results differ,
+// or an empty string if they are equal
+func compareResults(interpreted, compiled Result) string {
+	switch {
+	case interpreted.Err != nil && compiled.Err == nil:
+		return "only the interpreted execution failed"
+
+	case interpreted.Err == nil && compiled.Err != nil:
+		return "only the compiled execution failed"
+
+	case interpreted.Err != nil:
+		// The compiled code does not have position information,
+		// so only compare the kind of the errors
+		if errorKind(interpreted.Err) != errorKind(compiled.Err) {
+			return "errors differ"
+		}
+
+	default:
+		if interpreted.valueString() != compiled.valueString() {
+			return "values differ"
+		}
+	}
+
+	if !computationEqual(interpreted.Computation, compiled.Computation) {
+		return "computation differs"
+	}
+
+	return ""
+}
+
+// errorKind returns the kind of the given error,
+// i.e. the type of the error, without any wrapping interpreter error
+func errorKind(err error) string {
+	if interpreterErr, ok := err.(interpreter.Error); ok {
+		err = interpreterErr.Err
+	}
+	if positionedErr, ok := err.(interpreter.PositionedError); ok {
+		err = positionedErr.Err
+	}
+	return fmt.Sprintf("%T", err)
+}
+
+func (r Result) valueString() string {
+	if r.Value == nil {
+		return "<nil>"
+	}
+	// The static type is included, so e.g. values of different integer types differ
+	return fmt.Sprintf("%s: %s", r.Value.StaticType(r.interpreter), r.Value)
+}
+
+func computationEqual(a, b map[common.ComputationKind]uint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for kind, intensity := range a { // nolint:maprange
+		if b[kind] != intensity {
+			return false
+		}
+	}
+	return true
+}
+
+// argumentValues are the values passed for parameters, by parameter type
+var argumentValues = map[string][]func() interpreter.Value{
+	"Int": {
+		func() interpreter.Value { return interpreter.NewUnmeteredIntValueFromInt64(0) },
+		func() interpreter.Value { return interpreter.NewUnmeteredIntValueFromInt64(1) },
+		func() interpreter.Value { return interpreter.NewUnmeteredIntValueFromInt64(-3) },
+		func() interpreter.Value { return interpreter.NewUnmeteredIntValueFromInt64(10) },
+	},
+	"Bool": {
+		func() interpreter.Value { return interpreter.TrueValue },
+		func() interpreter.Value { return interpreter.FalseValue },
+	},
+	"String": {
+		func() interpreter.Value { return interpreter.NewUnmeteredStringValue("") },
+		func() interpreter.Value { return interpreter.NewUnmeteredStringValue("abc") },
+	},
+}
+
+// functionArguments returns the sets of arguments the given function is invoked with.
+// Each set is a function, so every execution gets new values.
+// It returns false if the function has parameters of types which are not supported
+func functionArguments(declaration *ast.FunctionDeclaration) ([]func() []interpreter.Value, bool) {
+	if declaration.TypeParameterList != nil && !declaration.TypeParameterList.IsEmpty() {
+		return nil, false
+	}
+
+	var parameterValues [][]func() interpreter.Value
+	setCount := 1
+
+	if declaration.ParameterList != nil {
+		for _, parameter := range declaration.ParameterList.Parameters {
+			values, ok := argumentValues[parameter.TypeAnnotation.Type.String()]
+			if !ok {
+				return nil, false
+			}
+			parameterValues = append(parameterValues, values)
+			if len(values) > setCount {
+				setCount = len(values)
+			}
+		}
+	}
+
+	// Instead of all combinations, pass each parameter's values in turn
+
+	argumentSets := make([]func() []interpreter.Value, setCount)
+	for i := 0; i < setCount; i++ {
+		setIndex := i
+		argumentSets[i] = func() []interpreter.Value {
+			arguments := make([]interpreter.Value, len(parameterValues))
+			for j, values := range parameterValues {
+				arguments[j] = values[setIndex%len(values)]()
+			}
+			return arguments
+		}
+	}
+
+	return argumentSets, true
+}
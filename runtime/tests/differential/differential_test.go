@@ -0,0 +1,248 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package differential
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+)
+
+var generatedProgramCount = flag.Int(
+	"generatedProgramCount",
+	100,
+	"number of generated programs to compare",
+)
+
+// requireNoMismatches compares the given program,
+// and reports each mismatch with a minimized reproducer
+func requireNoMismatches(t *testing.T, code string) (ok bool) {
+	mismatches, ok, err := Compare(code)
+	require.NoError(t, err, code)
+
+	for _, mismatch := range mismatches {
+		t.Errorf(
+			"mismatch: %s\n\nreproducer:\n%s\n\nprogram:\n%s",
+			mismatch,
+			Reproducer(code, mismatch),
+			code,
+		)
+	}
+
+	return ok
+}
+
+func TestCorpus(t *testing.T) {
+
+	t.Parallel()
+
+	paths, err := filepath.Glob(filepath.Join("testdata", "*.cdc"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		path := path
+
+		t.Run(filepath.Base(path), func(t *testing.T) {
+
+			t.Parallel()
+
+			code, err := os.ReadFile(path)
+			require.NoError(t, err)
+
+			ok := requireNoMismatches(t, string(code))
+			require.True(t, ok, "program is not supported by the compiler")
+		})
+	}
+}
+
+func TestGenerated(t *testing.T) {
+
+	t.Parallel()
+
+	for seed := 0; seed < *generatedProgramCount; seed++ {
+		code := NewGenerator(int64(seed)).Program()
+
+		// Generated programs must be supported by the compiler
+		ok := requireNoMismatches(t, code)
+		require.True(t, ok, code)
+	}
+}
+
+func TestGeneratorDeterminism(t *testing.T) {
+
+	t.Parallel()
+
+	require.Equal(t,
+		NewGenerator(42).Program(),
+		NewGenerator(42).Program(),
+	)
+}
+
+func TestCompareMismatch(t *testing.T) {
+
+	t.Parallel()
+
+	// Comparing the results of different functions simulates a mismatch
+
+	checker, err := Check(`
+      fun a(): Int { return 1 }
+      fun b(): Int { return 2 }
+    `)
+	require.NoError(t, err)
+
+	binary, ok, err := Compile(checker)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	interpreted, err := Interpret(checker, "a", nil)
+	require.NoError(t, err)
+
+	compiled, err := Execute(checker, binary, "b", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "values differ", compareResults(interpreted, compiled))
+	assert.Equal(t, "", compareResults(interpreted, interpreted))
+}
+
+func TestCompareStatementComputation(t *testing.T) {
+
+	t.Parallel()
+
+	// Functions which return the same value,
+	// but execute a different number of statements
+
+	checker, err := Check(`
+      fun a(): Int { return 1 }
+      fun b(): Int {
+          let x = 1
+          return x
+      }
+    `)
+	require.NoError(t, err)
+
+	binary, ok, err := Compile(checker)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	interpreted, err := Interpret(checker, "a", nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), interpreted.Computation[common.ComputationKindStatement])
+
+	compiled, err := Execute(checker, binary, "b", nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), compiled.Computation[common.ComputationKindStatement])
+
+	assert.Equal(t, "computation differs", compareResults(interpreted, compiled))
+}
+
+func TestComputationLimit(t *testing.T) {
+
+	t.Parallel()
+
+	mismatches, ok, err := Compare(`
+      fun loop(): Int {
+          while true {}
+          return 0
+      }
+    `)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Empty(t, mismatches)
+
+	checker, err := Check(`
+      fun loop(): Int {
+          while true {}
+          return 0
+      }
+    `)
+	require.NoError(t, err)
+
+	result, err := Interpret(checker, "loop", nil)
+	require.NoError(t, err)
+	require.ErrorAs(t, result.Err, &ComputationLimitExceededError{})
+}
+
+func TestUnsupported(t *testing.T) {
+
+	t.Parallel()
+
+	_, ok, err := Compare(`
+      fun test(): Int {
+          let f = fun (): Int { return 1 }
+          return f()
+      }
+    `)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestMinimize(t *testing.T) {
+
+	t.Parallel()
+
+	code := `
+      fun unrelated(): Int {
+          return 1
+      }
+
+      fun test(): Int {
+          var x = 1
+          var y = 2
+          if x < y {
+              y = 3
+          }
+          x = x / 0
+          return y
+      }
+    `
+
+	// A program is interesting if it still fails with a division by zero
+
+	minimized := Minimize(code, func(candidate string) bool {
+		checker, err := Check(candidate)
+		if err != nil {
+			return false
+		}
+		result, err := Interpret(checker, "test", nil)
+		return err == nil &&
+			result.Err != nil &&
+			strings.Contains(result.Err.Error(), "division by zero")
+	})
+
+	require.Equal(t,
+		`
+
+      fun test(): Int {
+          var x = 1
+          var y = 2
+          x = x / 0
+          return y
+      }
+    `,
+		minimized,
+	)
+}
@@ -0,0 +1,325 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package differential
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// Generator generates random, well-typed, terminating programs.
+//
+// The programs consist of functions with integer parameters and results,
+// which use variables, arithmetic, comparisons, conditionals, loops, and calls.
+// Generation is deterministic for a given seed
+type Generator struct {
+	rand      *rand.Rand
+	builder   strings.Builder
+	functions []generatedFunction
+	indent    int
+	// nextName is used to generate unique names
+	nextName int
+}
+
+type generatedFunction struct {
+	name           string
+	parameterCount int
+}
+
+// scope are the variables in scope
+type scope struct {
+	// readable are all variables which can be read
+	readable []string
+	// assignable are the variables which can be assigned,
+	// i.e. not parameters or loop counters
+	assignable []string
+}
+
+func (s scope) declare(name string, assignable bool) scope {
+	result := scope{
+		readable:   append(s.readable[:len(s.readable):len(s.readable)], name),
+		assignable: s.assignable,
+	}
+	if assignable {
+		result.assignable = append(s.assignable[:len(s.assignable):len(s.assignable)], name)
+	}
+	return result
+}
+
+const (
+	maxFunctionCount  = 4
+	maxParameterCount = 2
+	maxStatementCount = 4
+	maxBlockDepth     = 2
+	maxExpressionSize = 3
+	maxLoopCount      = 5
+)
+
+func NewGenerator(seed int64) *Generator {
+	return &Generator{
+		rand: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Program generates a new program
+func (g *Generator) Program() string {
+	g.builder.Reset()
+	g.functions = nil
+	g.nextName = 0
+
+	functionCount := 1 + g.rand.Intn(maxFunctionCount)
+	for i := 0; i < functionCount; i++ {
+		g.function()
+	}
+
+	return g.builder.String()
+}
+
+func (g *Generator) name(prefix string) string {
+	name := fmt.Sprintf("%s%d", prefix, g.nextName)
+	g.nextName++
+	return name
+}
+
+func (g *Generator) line(format string, arguments ...any) {
+	g.builder.WriteString(strings.Repeat("    ", g.indent))
+	g.builder.WriteString(fmt.Sprintf(format, arguments...))
+	g.builder.WriteByte('\n')
+}
+
+func (g *Generator) function() {
+	name := g.name("f")
+	parameterCount := g.rand.Intn(maxParameterCount + 1)
+
+	var s scope
+	parameters := make([]string, parameterCount)
+	for i := range parameters {
+		parameter := g.name("p")
+		parameters[i] = fmt.Sprintf("_ %s: Int", parameter)
+		s = s.declare(parameter, false)
+	}
+
+	g.line("fun %s(%s): Int {", name, strings.Join(parameters, ", "))
+	g.indent++
+
+	if parameterCount > 0 && g.rand.Intn(4) == 0 {
+		g.line("pre { %s }", g.boolExpression(s, maxExpressionSize))
+	}
+
+	s = g.statements(s, 0)
+	g.line("return %s", g.intExpression(s, maxExpressionSize))
+
+	g.indent--
+	g.line("}")
+	g.line("")
+
+	// Only declare the function after its body, so functions are not recursive
+	g.functions = append(
+		g.functions,
+		generatedFunction{
+			name:           name,
+			parameterCount: parameterCount,
+		},
+	)
+}
+
+func (g *Generator) statements(s scope, depth int) scope {
+	count := g.rand.Intn(maxStatementCount + 1)
+	for i := 0; i < count; i++ {
+		s = g.statement(s, depth)
+	}
+	return s
+}
+
+func (g *Generator) block(s scope, depth int, prefix string) {
+	g.line("%s {", prefix)
+	g.indent++
+	g.statements(s, depth+1)
+	g.indent--
+	g.line("}")
+}
+
+func (g *Generator) statement(s scope, depth int) scope {
+	kind := g.rand.Intn(6)
+	if depth >= maxBlockDepth {
+		// Only simple statements
+		kind %= 2
+	}
+
+	switch kind {
+	case 0:
+		name := g.name("v")
+		g.line("var %s = %s", name, g.intExpression(s, maxExpressionSize))
+		return s.declare(name, true)
+
+	case 1:
+		if len(s.assignable) == 0 {
+			return g.statement(s, maxBlockDepth)
+		}
+		name := s.assignable[g.rand.Intn(len(s.assignable))]
+		g.line("%s = %s", name, g.intExpression(s, maxExpressionSize))
+
+	case 2:
+		g.block(s, depth, fmt.Sprintf("if %s", g.boolExpression(s, maxExpressionSize)))
+		if g.rand.Intn(2) == 0 {
+			g.block(s, depth, "else")
+		}
+
+	case 3:
+		// A while loop with a counter, which is incremented first,
+		// so continue statements do not prevent termination
+		counter := g.name("i")
+		g.line("var %s = 0", counter)
+		g.line("while %s < %d {", counter, g.rand.Intn(maxLoopCount))
+		g.indent++
+		g.line("%s = %s + 1", counter, counter)
+		g.loopBody(s.declare(counter, false), depth)
+		g.indent--
+		g.line("}")
+
+	case 4:
+		// The array is declared with an explicit type,
+		// as the element type of an empty array literal cannot be inferred
+		array := g.name("a")
+		elements := make([]string, g.rand.Intn(maxLoopCount))
+		for i := range elements {
+			elements[i] = g.intLiteral()
+		}
+		g.line("let %s: [Int] = [%s]", array, strings.Join(elements, ", "))
+		element := g.name("e")
+		g.line("for %s in %s {", element, array)
+		g.indent++
+		g.loopBody(s.declare(element, false), depth)
+		g.indent--
+		g.line("}")
+
+	case 5:
+		// Returns are conditional, as statements after a return are unreachable,
+		// which is rejected by the checker
+		g.line(
+			"if %s { return %s }",
+			g.boolExpression(s, 1),
+			g.intExpression(s, maxExpressionSize),
+		)
+	}
+
+	return s
+}
+
+func (g *Generator) loopBody(s scope, depth int) {
+	s = g.statements(s, depth+1)
+
+	switch g.rand.Intn(4) {
+	case 0:
+		g.line("if %s { break }", g.boolExpression(s, 1))
+	case 1:
+		g.line("if %s { continue }", g.boolExpression(s, 1))
+	}
+
+	g.statements(s, depth+1)
+}
+
+func (g *Generator) intLiteral() string {
+	return fmt.Sprint(g.rand.Intn(25) - 5)
+}
+
+func (g *Generator) intExpression(s scope, size int) string {
+	if size <= 0 {
+		if len(s.readable) > 0 && g.rand.Intn(3) > 0 {
+			return s.readable[g.rand.Intn(len(s.readable))]
+		}
+		return g.intLiteral()
+	}
+
+	switch g.rand.Intn(6) {
+	case 0:
+		return g.intExpression(s, 0)
+
+	case 1, 2:
+		operators := []string{"+", "-", "*", "/", "%"}
+		operator := operators[g.rand.Intn(len(operators))]
+		return fmt.Sprintf(
+			"(%s %s %s)",
+			g.intExpression(s, size-1),
+			operator,
+			g.intExpression(s, size-1),
+		)
+
+	case 3:
+		return fmt.Sprintf(
+			"(%s ? %s : %s)",
+			g.boolExpression(s, size-1),
+			g.intExpression(s, size-1),
+			g.intExpression(s, size-1),
+		)
+
+	case 4:
+		if len(g.functions) == 0 {
+			return g.intExpression(s, 0)
+		}
+		function := g.functions[g.rand.Intn(len(g.functions))]
+		arguments := make([]string, function.parameterCount)
+		for i := range arguments {
+			arguments[i] = g.intExpression(s, size-1)
+		}
+		return fmt.Sprintf("%s(%s)", function.name, strings.Join(arguments, ", "))
+
+	default:
+		return fmt.Sprintf("-(%s)", g.intExpression(s, 0))
+	}
+}
+
+func (g *Generator) boolExpression(s scope, size int) string {
+	if size <= 0 {
+		return fmt.Sprint(g.rand.Intn(2) == 0)
+	}
+
+	switch g.rand.Intn(4) {
+	case 0:
+		operators := []string{"<", "<=", ">", ">=", "==", "!="}
+		operator := operators[g.rand.Intn(len(operators))]
+		return fmt.Sprintf(
+			"%s %s %s",
+			g.intExpression(s, size-1),
+			operator,
+			g.intExpression(s, size-1),
+		)
+
+	case 1:
+		return fmt.Sprintf("!(%s)", g.boolExpression(s, size-1))
+
+	case 2:
+		operators := []string{"&&", "||"}
+		operator := operators[g.rand.Intn(len(operators))]
+		return fmt.Sprintf(
+			"(%s) %s (%s)",
+			g.boolExpression(s, size-1),
+			operator,
+			g.boolExpression(s, size-1),
+		)
+
+	default:
+		return fmt.Sprintf(
+			"%s < %s",
+			g.intExpression(s, 0),
+			g.intExpression(s, 0),
+		)
+	}
+}
@@ -0,0 +1,141 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package differential
+
+import (
+	"sort"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/parser"
+)
+
+// Reproducer returns a minimized version of the given program,
+// which still has the given mismatch
+func Reproducer(code string, mismatch Mismatch) string {
+	return Minimize(code, func(candidate string) (interesting bool) {
+		// Candidates might crash the compiler, which is a different problem
+		defer func() {
+			if recover() != nil {
+				interesting = false
+			}
+		}()
+
+		mismatches, ok, err := Compare(candidate)
+		if err != nil || !ok {
+			return false
+		}
+
+		for _, candidateMismatch := range mismatches {
+			if candidateMismatch.Function == mismatch.Function &&
+				candidateMismatch.Reason == mismatch.Reason {
+
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// Minimize reduces the given program while it is still interesting,
+// by removing declarations and statements.
+//
+// Larger elements are removed first, and removals are repeated until
+// no element can be removed anymore, i.e. the result is 1-minimal
+// with regard to declarations and statements
+func Minimize(code string, interesting func(code string) bool) string {
+	for {
+		removed := false
+
+		for _, r := range removableRanges(code) {
+			candidate := code[:r.start] + code[r.end:]
+			if interesting(candidate) {
+				code = candidate
+				removed = true
+				// Offsets of the remaining elements changed
+				break
+			}
+		}
+
+		if !removed {
+			return code
+		}
+	}
+}
+
+type sourceRange struct {
+	start, end int
+}
+
+// removableRanges returns the source ranges of all declarations and statements of the given program,
+// from the largest to the smallest
+func removableRanges(code string) []sourceRange {
+	program, err := parser.ParseProgram(nil, []byte(code), parser.Config{})
+	if err != nil {
+		return nil
+	}
+
+	var ranges []sourceRange
+
+	ast.Inspect(program, func(element ast.Element) bool {
+		switch element.(type) {
+		case ast.Statement, ast.Declaration:
+			r := sourceRange{
+				start: element.StartPosition().Offset,
+				end:   element.EndPosition(nil).Offset + 1,
+			}
+			ranges = append(ranges, lineRange(code, r))
+		}
+		return true
+	})
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].end-ranges[i].start > ranges[j].end-ranges[j].start
+	})
+
+	return ranges
+}
+
+// lineRange extends the given range to the whole lines it spans,
+// if the lines contain nothing else, so removals do not leave blank lines
+func lineRange(code string, r sourceRange) sourceRange {
+	start := r.start
+	for start > 0 && (code[start-1] == ' ' || code[start-1] == '\t') {
+		start--
+	}
+	if start > 0 && code[start-1] != '\n' {
+		return r
+	}
+
+	end := r.end
+	for end < len(code) && (code[end] == ' ' || code[end] == '\t') {
+		end++
+	}
+	if end < len(code) {
+		if code[end] != '\n' {
+			return r
+		}
+		end++
+	}
+
+	return sourceRange{
+		start: start,
+		end:   end,
+	}
+}
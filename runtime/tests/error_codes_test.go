@@ -0,0 +1,129 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/errors"
+	_ "github.com/onflow/cadence/runtime/interpreter"
+	_ "github.com/onflow/cadence/runtime/sema"
+)
+
+// errorCodeDeclarations parses the Go file with the given path,
+// and returns the names of the user error types it declares,
+// and the error codes returned by their ErrorCode functions
+func errorCodeDeclarations(t *testing.T, path string) (userErrors map[string]struct{}, codes map[string]string) {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	require.NoError(t, err)
+
+	userErrors = map[string]struct{}{}
+	codes = map[string]string{}
+
+	for _, declaration := range file.Decls {
+		function, ok := declaration.(*ast.FuncDecl)
+		if !ok || function.Recv == nil {
+			continue
+		}
+
+		receiverType := function.Recv.List[0].Type
+		if starExpr, ok := receiverType.(*ast.StarExpr); ok {
+			receiverType = starExpr.X
+		}
+		typeName := receiverType.(*ast.Ident).Name
+
+		switch function.Name.Name {
+		case "IsUserError":
+			userErrors[typeName] = struct{}{}
+
+		case "ErrorCode":
+			statements := function.Body.List
+			require.Len(t, statements, 1, typeName)
+			returnStatement := statements[0].(*ast.ReturnStmt)
+			literal := returnStatement.Results[0].(*ast.BasicLit)
+			code, err := strconv.Unquote(literal.Value)
+			require.NoError(t, err)
+			codes[typeName] = code
+		}
+	}
+
+	return
+}
+
+// TestErrorCodes checks that all user errors of the checker and the interpreter
+// have a unique error code, and that all error codes have an explanation
+func TestErrorCodes(t *testing.T) {
+	t.Parallel()
+
+	// Wrapper errors report the codes of the errors they wrap,
+	// hence, skip them from the check.
+	wrapperErrors := map[string]struct{}{
+		"CheckerError":         {},
+		"ImportedProgramError": {},
+		"PositionedError":      {},
+	}
+
+	declaredCodes := map[errors.ErrorCode]string{}
+
+	for _, path := range []string{
+		"../sema/errors.go",
+		"../interpreter/errors.go",
+	} {
+		userErrors, codes := errorCodeDeclarations(t, path)
+
+		for typeName := range userErrors { //nolint:maprange
+			if _, ok := wrapperErrors[typeName]; ok {
+				continue
+			}
+			_, ok := codes[typeName]
+			assert.True(t, ok, "%s: user error %s has no error code", path, typeName)
+		}
+
+		for typeName, code := range codes { //nolint:maprange
+			errorCode := errors.ErrorCode(code)
+
+			if otherTypeName, ok := declaredCodes[errorCode]; ok {
+				assert.Failf(t,
+					"duplicate error code",
+					"%s: %s and %s have the same error code %s",
+					path,
+					otherTypeName,
+					typeName,
+					code,
+				)
+			}
+			declaredCodes[errorCode] = typeName
+
+			_, ok := errors.LookupErrorExplanation(errorCode)
+			assert.True(t, ok, "%s: no explanation for error code %s of %s", path, code, typeName)
+		}
+	}
+
+	for _, explanation := range errors.ErrorExplanations() {
+		_, ok := declaredCodes[explanation.Code]
+		assert.True(t, ok, "explanation for unused error code %s", explanation.Code)
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/activations"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestInterpretErrorExplanationExamples(t *testing.T) {
+
+	t.Parallel()
+
+	// interpret runs the given example.
+	// Scripts are run by invoking the `main` function,
+	// transactions are run with the account 0x1 as the signer.
+
+	interpret := func(t *testing.T, code string) error {
+
+		baseValueActivation := sema.NewVariableActivation(sema.BaseValueActivation)
+		baseActivation := activations.NewActivation(nil, interpreter.BaseActivation)
+		for _, valueDeclaration := range stdlib.DefaultStandardLibraryValues(nil) {
+			baseValueActivation.DeclareValue(valueDeclaration)
+			interpreter.Declare(baseActivation, valueDeclaration)
+		}
+
+		inter, err := parseCheckAndInterpretWithOptions(t,
+			code,
+			ParseCheckAndInterpretOptions{
+				CheckerConfig: &sema.Config{
+					BaseValueActivation: baseValueActivation,
+					AttachmentsEnabled:  true,
+				},
+				Config: &interpreter.Config{
+					BaseActivation:       baseActivation,
+					ContractValueHandler: makeContractValueHandler(nil, nil, nil),
+					AuthAccountHandler: func(address interpreter.AddressValue) interpreter.Value {
+						return newTestAuthAccountValue(nil, address)
+					},
+				},
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		if len(inter.Program.Program.TransactionDeclarations()) > 0 {
+			signer := newTestAuthAccountValue(
+				nil,
+				interpreter.NewUnmeteredAddressValueFromBytes([]byte{0x1}),
+			)
+			return inter.InvokeTransaction(0, signer)
+		}
+
+		_, err = inter.Invoke("main")
+		return err
+	}
+
+	for _, explanation := range errors.ErrorExplanations() {
+
+		code := explanation.Code
+		if !strings.HasPrefix(string(code), "R") {
+			continue
+		}
+
+		examples := utils.ErrorExplanationExamples(explanation)
+
+		t.Run(string(code), func(t *testing.T) {
+
+			t.Parallel()
+
+			for i, example := range examples {
+				if example.Ignore {
+					continue
+				}
+
+				err := interpret(t, example.Code)
+
+				// The first example is the erroneous code,
+				// all following examples are fixed code
+
+				if i == 0 {
+					require.Error(t, err)
+					require.Contains(t, utils.ErrorCodes(err), code)
+				} else {
+					require.NoError(t, err, "example %d", i+1)
+				}
+			}
+		})
+	}
+}
@@ -8654,7 +8654,7 @@ func TestInterpretContractAccountFieldUse(t *testing.T) {
 			},
 		)
 		require.Error(t, err)
-		assert.ErrorContains(t, err, "error: member `account` is used before it has been initialized")
+		assert.ErrorContains(t, err, "error[R0027]: member `account` is used before it has been initialized")
 	})
 }
 
@@ -0,0 +1,116 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	goerrors "errors"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/errors"
+)
+
+// ErrorExplanationExample is a Cadence code example of an error explanation
+type ErrorExplanationExample struct {
+	Code string
+	// Ignore is true if the example cannot be checked standalone,
+	// e.g. because it imports other programs
+	Ignore bool
+}
+
+const errorExplanationExampleFence = "```"
+const errorExplanationExampleLanguage = "cadence"
+const errorExplanationExampleIgnoreAttribute = "ignore"
+
+// ErrorExplanationExamples returns the Cadence code examples of the given error explanation,
+// i.e. the contents of the fenced code blocks with the language `cadence`.
+//
+// By convention, the first example is the erroneous code,
+// and all following examples are fixed code
+func ErrorExplanationExamples(explanation errors.ErrorExplanation) []ErrorExplanationExample {
+	var examples []ErrorExplanationExample
+
+	var current *ErrorExplanationExample
+	var code strings.Builder
+
+	for _, line := range strings.Split(explanation.Explanation, "\n") {
+		trimmedLine := strings.TrimSpace(line)
+
+		if current != nil {
+			if trimmedLine == errorExplanationExampleFence {
+				current.Code = code.String()
+				examples = append(examples, *current)
+				current = nil
+				code.Reset()
+				continue
+			}
+
+			code.WriteString(line)
+			code.WriteByte('\n')
+			continue
+		}
+
+		info, ok := strings.CutPrefix(trimmedLine, errorExplanationExampleFence)
+		if !ok {
+			continue
+		}
+
+		attributes := strings.Split(info, ",")
+		if attributes[0] != errorExplanationExampleLanguage {
+			continue
+		}
+
+		current = &ErrorExplanationExample{}
+		for _, attribute := range attributes[1:] {
+			if attribute == errorExplanationExampleIgnoreAttribute {
+				current.Ignore = true
+			}
+		}
+	}
+
+	return examples
+}
+
+// ErrorCodes returns the error codes of the given error,
+// and of all errors it wraps or contains
+func ErrorCodes(err error) []errors.ErrorCode {
+	var codes []errors.ErrorCode
+
+	var walk func(err error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+
+		if hasErrorCode, ok := err.(errors.HasErrorCode); ok {
+			codes = append(codes, hasErrorCode.ErrorCode())
+		}
+
+		if parentError, ok := err.(errors.ParentError); ok {
+			for _, childError := range parentError.ChildErrors() {
+				walk(childError)
+			}
+		}
+
+		walk(goerrors.Unwrap(err))
+	}
+
+	walk(err)
+
+	return codes
+}
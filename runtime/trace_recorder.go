@@ -0,0 +1,418 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// TraceSpan is a span recorded by a TraceRecorder,
+// e.g. the invocation of a function, or the transfer of a composite value
+type TraceSpan struct {
+	// Operation is the traced operation, e.g. `function.foo` or `composite.transfer`
+	Operation  string
+	Location   common.Location
+	Start      time.Time
+	Duration   time.Duration
+	Attributes []attribute.KeyValue
+	// Parent is the index of the enclosing span, or -1 if the span is a root span
+	Parent int
+}
+
+// End returns the time the span ended
+func (s TraceSpan) End() time.Time {
+	return s.Start.Add(s.Duration)
+}
+
+// Category returns the category of the span's operation, e.g. `function` or `composite`
+func (s TraceSpan) Category() string {
+	category, _, _ := strings.Cut(s.Operation, ".")
+	return category
+}
+
+// TraceRecorder records the spans which are reported when tracing is enabled,
+// and exports them as a Chrome trace, or as OTLP-JSON.
+//
+// Spans are only reported when they end, so their start is derived from their duration,
+// and the nesting of spans is derived from their start and end
+type TraceRecorder struct {
+	spans []TraceSpan
+	// TraceID is the ID of the trace in OTLP exports
+	TraceID [16]byte
+	// now returns the current time
+	now func() time.Time
+}
+
+// NewTraceRecorder creates and returns a *TraceRecorder with a random trace ID
+func NewTraceRecorder() *TraceRecorder {
+	recorder := &TraceRecorder{
+		now: time.Now,
+	}
+	_, _ = rand.Read(recorder.TraceID[:])
+	return recorder
+}
+
+// RecordTrace records a span for the given operation, which just ended.
+// It has the same signature as Interface.RecordTrace,
+// so host environments may also delegate to it
+func (r *TraceRecorder) RecordTrace(
+	operation string,
+	location common.Location,
+	duration time.Duration,
+	attrs []attribute.KeyValue,
+) {
+	r.spans = append(r.spans, TraceSpan{
+		Operation:  operation,
+		Location:   location,
+		Start:      r.now().Add(-duration),
+		Duration:   duration,
+		Attributes: attrs,
+	})
+}
+
+// Reset removes all recorded spans
+func (r *TraceRecorder) Reset() {
+	r.spans = nil
+}
+
+// Spans returns the recorded spans, sorted by their start,
+// with enclosing spans before the spans they enclose.
+//
+// The parent of a span is the innermost span which encloses it
+func (r *TraceRecorder) Spans() []TraceSpan {
+	spans := make([]TraceSpan, len(r.spans))
+	copy(spans, r.spans)
+
+	// Spans are recorded when they end, so an enclosing span
+	// with the same start and end is recorded after the spans it encloses
+	indices := make([]int, len(spans))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.Slice(indices, func(i, j int) bool {
+		a, b := spans[indices[i]], spans[indices[j]]
+		if !a.Start.Equal(b.Start) {
+			return a.Start.Before(b.Start)
+		}
+		if !a.End().Equal(b.End()) {
+			return a.End().After(b.End())
+		}
+		return indices[i] > indices[j]
+	})
+
+	sorted := make([]TraceSpan, 0, len(spans))
+	var enclosing []int
+	for _, index := range indices {
+		span := spans[index]
+
+		for len(enclosing) > 0 {
+			parent := sorted[enclosing[len(enclosing)-1]]
+			if !span.End().After(parent.End()) {
+				break
+			}
+			enclosing = enclosing[:len(enclosing)-1]
+		}
+
+		span.Parent = -1
+		if len(enclosing) > 0 {
+			span.Parent = enclosing[len(enclosing)-1]
+		}
+
+		enclosing = append(enclosing, len(sorted))
+		sorted = append(sorted, span)
+	}
+
+	return sorted
+}
+
+func traceAttributeValue(value attribute.Value) any {
+	switch value.Type() {
+	case attribute.BOOL:
+		return value.AsBool()
+	case attribute.INT64:
+		return value.AsInt64()
+	case attribute.FLOAT64:
+		return value.AsFloat64()
+	case attribute.STRING:
+		return value.AsString()
+	default:
+		return value.Emit()
+	}
+}
+
+type chromeTraceEvent struct {
+	Name      string         `json:"name"`
+	Category  string         `json:"cat"`
+	Phase     string         `json:"ph"`
+	Timestamp float64        `json:"ts"`
+	Duration  float64        `json:"dur"`
+	ProcessID int            `json:"pid"`
+	ThreadID  int            `json:"tid"`
+	Args      map[string]any `json:"args,omitempty"`
+}
+
+type chromeTrace struct {
+	TraceEvents     []chromeTraceEvent `json:"traceEvents"`
+	DisplayTimeUnit string             `json:"displayTimeUnit"`
+}
+
+// MarshalChromeTrace returns the recorded spans in the Chrome trace event format,
+// which can be opened in timeline viewers like Perfetto or `chrome://tracing`.
+//
+// Each span is a complete event, with timestamps in microseconds since the start of the first span.
+// Nested spans are shown nested, as all events are on the same thread.
+// The location and the attributes of the span are the arguments of the event
+func (r *TraceRecorder) MarshalChromeTrace() ([]byte, error) {
+	spans := r.Spans()
+
+	events := make([]chromeTraceEvent, 0, len(spans))
+
+	var origin time.Time
+	if len(spans) > 0 {
+		origin = spans[0].Start
+	}
+
+	for _, span := range spans {
+		args := make(map[string]any, len(span.Attributes)+1)
+		if span.Location != nil {
+			args["location"] = span.Location.ID()
+		}
+		for _, attr := range span.Attributes {
+			args[string(attr.Key)] = traceAttributeValue(attr.Value)
+		}
+
+		events = append(events, chromeTraceEvent{
+			Name:      span.Operation,
+			Category:  span.Category(),
+			Phase:     "X",
+			Timestamp: float64(span.Start.Sub(origin).Nanoseconds()) / 1000,
+			Duration:  float64(span.Duration.Nanoseconds()) / 1000,
+			ProcessID: 1,
+			ThreadID:  1,
+			Args:      args,
+		})
+	}
+
+	return json.Marshal(chromeTrace{
+		TraceEvents:     events,
+		DisplayTimeUnit: "ns",
+	})
+}
+
+type otlpAnyValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	IntValue    *string         `json:"intValue,omitempty"`
+	DoubleValue *float64        `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []otlpAnyValue `json:"values"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpTrace struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+// otlpSpanKindInternal is the kind of spans which represent internal operations
+const otlpSpanKindInternal = 1
+
+// otlpServiceName is the name of the service and the instrumentation scope in OTLP exports
+const otlpServiceName = "cadence"
+
+func newOTLPStringValue(s string) otlpAnyValue {
+	return otlpAnyValue{StringValue: &s}
+}
+
+func newOTLPAnyValue(value attribute.Value) otlpAnyValue {
+	switch value.Type() {
+	case attribute.BOOL:
+		b := value.AsBool()
+		return otlpAnyValue{BoolValue: &b}
+
+	case attribute.INT64:
+		// 64-bit integers are encoded as strings in OTLP-JSON
+		i := strconv.FormatInt(value.AsInt64(), 10)
+		return otlpAnyValue{IntValue: &i}
+
+	case attribute.FLOAT64:
+		f := value.AsFloat64()
+		return otlpAnyValue{DoubleValue: &f}
+
+	case attribute.STRING:
+		return newOTLPStringValue(value.AsString())
+
+	case attribute.BOOLSLICE:
+		var values []otlpAnyValue
+		for _, b := range value.AsBoolSlice() {
+			values = append(values, newOTLPAnyValue(attribute.BoolValue(b)))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+
+	case attribute.INT64SLICE:
+		var values []otlpAnyValue
+		for _, i := range value.AsInt64Slice() {
+			values = append(values, newOTLPAnyValue(attribute.Int64Value(i)))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+
+	case attribute.FLOAT64SLICE:
+		var values []otlpAnyValue
+		for _, f := range value.AsFloat64Slice() {
+			values = append(values, newOTLPAnyValue(attribute.Float64Value(f)))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+
+	case attribute.STRINGSLICE:
+		var values []otlpAnyValue
+		for _, s := range value.AsStringSlice() {
+			values = append(values, newOTLPStringValue(s))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+
+	default:
+		return newOTLPStringValue(value.Emit())
+	}
+}
+
+// otlpSpanID returns the ID of the span with the given index
+func otlpSpanID(index int) string {
+	var id [8]byte
+	binary.BigEndian.PutUint64(id[:], uint64(index)+1)
+	return hex.EncodeToString(id[:])
+}
+
+func otlpUnixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// MarshalOTLP returns the recorded spans in the OTLP-JSON format,
+// i.e. the JSON encoding of an OpenTelemetry trace export request,
+// which can be sent to an OpenTelemetry collector, or opened in trace viewers.
+//
+// All spans belong to the trace with the recorder's trace ID,
+// and nested spans refer to their enclosing span as parent.
+// The location and the attributes of the span are the attributes of the OTLP span
+func (r *TraceRecorder) MarshalOTLP() ([]byte, error) {
+	spans := r.Spans()
+
+	traceID := hex.EncodeToString(r.TraceID[:])
+
+	otlpSpans := make([]otlpSpan, 0, len(spans))
+	for index, span := range spans {
+		attributes := make([]otlpKeyValue, 0, len(span.Attributes)+1)
+		if span.Location != nil {
+			attributes = append(attributes, otlpKeyValue{
+				Key:   "location",
+				Value: newOTLPStringValue(span.Location.ID()),
+			})
+		}
+		for _, attr := range span.Attributes {
+			attributes = append(attributes, otlpKeyValue{
+				Key:   string(attr.Key),
+				Value: newOTLPAnyValue(attr.Value),
+			})
+		}
+
+		var parentSpanID string
+		if span.Parent >= 0 {
+			parentSpanID = otlpSpanID(span.Parent)
+		}
+
+		otlpSpans = append(otlpSpans, otlpSpan{
+			TraceID:           traceID,
+			SpanID:            otlpSpanID(index),
+			ParentSpanID:      parentSpanID,
+			Name:              span.Operation,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: otlpUnixNano(span.Start),
+			EndTimeUnixNano:   otlpUnixNano(span.End()),
+			Attributes:        attributes,
+		})
+	}
+
+	return json.Marshal(otlpTrace{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource: otlpResource{
+					Attributes: []otlpKeyValue{
+						{
+							Key:   "service.name",
+							Value: newOTLPStringValue(otlpServiceName),
+						},
+					},
+				},
+				ScopeSpans: []otlpScopeSpans{
+					{
+						Scope: otlpScope{
+							Name: otlpServiceName,
+						},
+						Spans: otlpSpans,
+					},
+				},
+			},
+		},
+	})
+}
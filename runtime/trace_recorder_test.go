@@ -0,0 +1,401 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// newTestTraceRecorder returns a trace recorder with a fixed trace ID,
+// whose clock returns the times of the given offsets from the Unix epoch
+func newTestTraceRecorder(offsets ...time.Duration) *TraceRecorder {
+	recorder := NewTraceRecorder()
+	recorder.TraceID = [16]byte{0x1, 0x2, 0x3, 0x4, 0x5, 0x6, 0x7, 0x8, 0x9, 0xa, 0xb, 0xc, 0xd, 0xe, 0xf, 0x10}
+	recorder.now = func() time.Time {
+		offset := offsets[0]
+		offsets = offsets[1:]
+		return time.Unix(0, 0).Add(offset)
+	}
+	return recorder
+}
+
+func recordTestSpans(recorder *TraceRecorder) {
+	location := common.StringLocation("test")
+
+	// Spans are reported when they end, so nested spans are reported first:
+	//
+	// function.foo [0, 10)
+	//   composite.construct [2, 4)
+	//   array.transfer [5, 9)
+	//     composite.transfer [6, 8)
+	// function.bar [12, 13)
+
+	recorder.RecordTrace(
+		"composite.construct",
+		location,
+		2*time.Microsecond,
+		[]attribute.KeyValue{
+			attribute.String("typeID", "S.test.Foo"),
+		},
+	)
+	recorder.RecordTrace(
+		"composite.transfer",
+		location,
+		2*time.Microsecond,
+		nil,
+	)
+	recorder.RecordTrace(
+		"array.transfer",
+		location,
+		4*time.Microsecond,
+		[]attribute.KeyValue{
+			attribute.Int("count", 1),
+		},
+	)
+	recorder.RecordTrace(
+		"function.foo",
+		location,
+		10*time.Microsecond,
+		nil,
+	)
+	recorder.RecordTrace(
+		"function.bar",
+		nil,
+		1*time.Microsecond,
+		nil,
+	)
+}
+
+func TestTraceRecorderSpans(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := newTestTraceRecorder(
+		4*time.Microsecond,
+		8*time.Microsecond,
+		9*time.Microsecond,
+		10*time.Microsecond,
+		13*time.Microsecond,
+	)
+	recordTestSpans(recorder)
+
+	spans := recorder.Spans()
+
+	type span struct {
+		operation string
+		start     time.Duration
+		parent    int
+	}
+
+	actual := make([]span, 0, len(spans))
+	for _, s := range spans {
+		actual = append(actual, span{
+			operation: s.Operation,
+			start:     s.Start.Sub(time.Unix(0, 0)),
+			parent:    s.Parent,
+		})
+	}
+
+	assert.Equal(
+		t,
+		[]span{
+			{operation: "function.foo", start: 0, parent: -1},
+			{operation: "composite.construct", start: 2 * time.Microsecond, parent: 0},
+			{operation: "array.transfer", start: 5 * time.Microsecond, parent: 0},
+			{operation: "composite.transfer", start: 6 * time.Microsecond, parent: 2},
+			{operation: "function.bar", start: 12 * time.Microsecond, parent: -1},
+		},
+		actual,
+	)
+
+	assert.Equal(t, "function", spans[0].Category())
+	assert.Equal(t, time.Unix(0, 0).Add(10*time.Microsecond), spans[0].End())
+
+	recorder.Reset()
+	assert.Empty(t, recorder.Spans())
+}
+
+func TestTraceRecorderSpansWithSameBounds(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := newTestTraceRecorder(
+		2*time.Microsecond,
+		2*time.Microsecond,
+	)
+
+	// The enclosing span is reported last
+	recorder.RecordTrace("composite.transfer", nil, 2*time.Microsecond, nil)
+	recorder.RecordTrace("function.foo", nil, 2*time.Microsecond, nil)
+
+	spans := recorder.Spans()
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "function.foo", spans[0].Operation)
+	assert.Equal(t, -1, spans[0].Parent)
+	assert.Equal(t, "composite.transfer", spans[1].Operation)
+	assert.Equal(t, 0, spans[1].Parent)
+}
+
+func TestTraceRecorderMarshalChromeTrace(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := newTestTraceRecorder(
+		4*time.Microsecond,
+		8*time.Microsecond,
+		9*time.Microsecond,
+		10*time.Microsecond,
+		13*time.Microsecond,
+	)
+	recordTestSpans(recorder)
+
+	actual, err := recorder.MarshalChromeTrace()
+	require.NoError(t, err)
+
+	expected := `
+	  {
+	    "traceEvents": [
+	      {
+	        "name": "function.foo",
+	        "cat": "function",
+	        "ph": "X",
+	        "ts": 0,
+	        "dur": 10,
+	        "pid": 1,
+	        "tid": 1,
+	        "args": {"location": "S.test"}
+	      },
+	      {
+	        "name": "composite.construct",
+	        "cat": "composite",
+	        "ph": "X",
+	        "ts": 2,
+	        "dur": 2,
+	        "pid": 1,
+	        "tid": 1,
+	        "args": {"location": "S.test", "typeID": "S.test.Foo"}
+	      },
+	      {
+	        "name": "array.transfer",
+	        "cat": "array",
+	        "ph": "X",
+	        "ts": 5,
+	        "dur": 4,
+	        "pid": 1,
+	        "tid": 1,
+	        "args": {"location": "S.test", "count": 1}
+	      },
+	      {
+	        "name": "composite.transfer",
+	        "cat": "composite",
+	        "ph": "X",
+	        "ts": 6,
+	        "dur": 2,
+	        "pid": 1,
+	        "tid": 1,
+	        "args": {"location": "S.test"}
+	      },
+	      {
+	        "name": "function.bar",
+	        "cat": "function",
+	        "ph": "X",
+	        "ts": 12,
+	        "dur": 1,
+	        "pid": 1,
+	        "tid": 1
+	      }
+	    ],
+	    "displayTimeUnit": "ns"
+	  }
+	`
+	require.JSONEq(t, expected, string(actual))
+}
+
+func TestTraceRecorderMarshalOTLP(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := newTestTraceRecorder(
+		4*time.Microsecond,
+		8*time.Microsecond,
+		9*time.Microsecond,
+		10*time.Microsecond,
+		13*time.Microsecond,
+	)
+	recordTestSpans(recorder)
+
+	actual, err := recorder.MarshalOTLP()
+	require.NoError(t, err)
+
+	expected := `
+	  {
+	    "resourceSpans": [
+	      {
+	        "resource": {
+	          "attributes": [
+	            {"key": "service.name", "value": {"stringValue": "cadence"}}
+	          ]
+	        },
+	        "scopeSpans": [
+	          {
+	            "scope": {"name": "cadence"},
+	            "spans": [
+	              {
+	                "traceId": "0102030405060708090a0b0c0d0e0f10",
+	                "spanId": "0000000000000001",
+	                "name": "function.foo",
+	                "kind": 1,
+	                "startTimeUnixNano": "0",
+	                "endTimeUnixNano": "10000",
+	                "attributes": [
+	                  {"key": "location", "value": {"stringValue": "S.test"}}
+	                ]
+	              },
+	              {
+	                "traceId": "0102030405060708090a0b0c0d0e0f10",
+	                "spanId": "0000000000000002",
+	                "parentSpanId": "0000000000000001",
+	                "name": "composite.construct",
+	                "kind": 1,
+	                "startTimeUnixNano": "2000",
+	                "endTimeUnixNano": "4000",
+	                "attributes": [
+	                  {"key": "location", "value": {"stringValue": "S.test"}},
+	                  {"key": "typeID", "value": {"stringValue": "S.test.Foo"}}
+	                ]
+	              },
+	              {
+	                "traceId": "0102030405060708090a0b0c0d0e0f10",
+	                "spanId": "0000000000000003",
+	                "parentSpanId": "0000000000000001",
+	                "name": "array.transfer",
+	                "kind": 1,
+	                "startTimeUnixNano": "5000",
+	                "endTimeUnixNano": "9000",
+	                "attributes": [
+	                  {"key": "location", "value": {"stringValue": "S.test"}},
+	                  {"key": "count", "value": {"intValue": "1"}}
+	                ]
+	              },
+	              {
+	                "traceId": "0102030405060708090a0b0c0d0e0f10",
+	                "spanId": "0000000000000004",
+	                "parentSpanId": "0000000000000003",
+	                "name": "composite.transfer",
+	                "kind": 1,
+	                "startTimeUnixNano": "6000",
+	                "endTimeUnixNano": "8000",
+	                "attributes": [
+	                  {"key": "location", "value": {"stringValue": "S.test"}}
+	                ]
+	              },
+	              {
+	                "traceId": "0102030405060708090a0b0c0d0e0f10",
+	                "spanId": "0000000000000005",
+	                "name": "function.bar",
+	                "kind": 1,
+	                "startTimeUnixNano": "12000",
+	                "endTimeUnixNano": "13000"
+	              }
+	            ]
+	          }
+	        ]
+	      }
+	    ]
+	  }
+	`
+	require.JSONEq(t, expected, string(actual))
+}
+
+func TestRuntimeTraceRecorder(t *testing.T) {
+
+	t.Parallel()
+
+	traceRecorder := NewTraceRecorder()
+
+	runtime := NewInterpreterRuntime(Config{
+		TraceRecorder: traceRecorder,
+	})
+
+	script := []byte(`
+	  pub struct Foo {}
+
+	  pub fun makeFoo(): Foo {
+	    return Foo()
+	  }
+
+	  pub fun main() {
+	    makeFoo()
+	  }
+	`)
+
+	var hostTraces int
+	runtimeInterface := &testRuntimeInterface{
+		recordTrace: func(_ string, _ Location, _ time.Duration, _ []attribute.KeyValue) {
+			hostTraces++
+		},
+	}
+
+	_, err := runtime.ExecuteScript(
+		Script{
+			Source: script,
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  common.ScriptLocation{},
+		},
+	)
+	require.NoError(t, err)
+
+	// Tracing is not enabled, so the host environment does not get any traces
+	assert.Zero(t, hostTraces)
+
+	spans := traceRecorder.Spans()
+
+	indices := map[string]int{}
+	for i, span := range spans {
+		if _, ok := indices[span.Operation]; !ok {
+			indices[span.Operation] = i
+		}
+	}
+
+	require.Contains(t, indices, "function.makeFoo")
+	require.Contains(t, indices, "function.Foo")
+	require.Contains(t, indices, "composite.construct")
+
+	makeFoo := indices["function.makeFoo"]
+	constructor := indices["function.Foo"]
+	construct := indices["composite.construct"]
+
+	assert.Equal(t, makeFoo, spans[constructor].Parent)
+	assert.Equal(t, constructor, spans[construct].Parent)
+	assert.Contains(
+		t,
+		spans[construct].Attributes,
+		attribute.String("typeID", "s.0000000000000000000000000000000000000000000000000000000000000000.Foo"),
+	)
+}
@@ -22,9 +22,12 @@ import (
 	"sync"
 
 	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/compiler"
+	"github.com/onflow/cadence/runtime/compiler/bytecode"
 	"github.com/onflow/cadence/runtime/errors"
 	"github.com/onflow/cadence/runtime/interpreter"
 	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/vm"
 )
 
 type interpreterTransactionExecutorPreparation struct {
@@ -265,7 +268,43 @@ func (executor *interpreterTransactionExecutor) transactionExecutionFunction(
 		}
 
 		values = append(values, authorizerValues(inter)...)
+
+		if executor.runtime.defaultConfig.ScriptBytecodeVMEnabled {
+			compiledProgram, ok := executor.runtime.compiledPrograms.get(executor.program)
+			if ok {
+				return nil, executor.executeCompiled(inter, compiledProgram, values)
+			}
+		}
+
 		err = inter.InvokeTransaction(0, values...)
 		return nil, err
 	}
 }
+
+// executeCompiled executes the compiled transaction in the bytecode VM
+func (executor *interpreterTransactionExecutor) executeCompiled(
+	inter *interpreter.Interpreter,
+	program *bytecode.Program,
+	arguments []interpreter.Value,
+) error {
+	machine, err := vm.NewBytecodeVM(program, inter)
+	if err != nil {
+		return err
+	}
+
+	// The compiled code expects arguments to have the parameter types,
+	// so convert and box them, like the interpreter does when invoking a function
+
+	parameters := executor.transactionType.EntryPointFunctionType().Parameters
+	for i, argument := range arguments {
+		arguments[i] = inter.ConvertAndBox(
+			interpreter.EmptyLocationRange,
+			argument,
+			inter.MustSemaTypeOfValue(argument),
+			parameters[i].TypeAnnotation.Type,
+		)
+	}
+
+	_, err = machine.Invoke(compiler.TransactionFunctionName, arguments...)
+	return err
+}
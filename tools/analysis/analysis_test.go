@@ -260,6 +260,64 @@ func TestCheckError(t *testing.T) {
 	require.ErrorAs(t, err, &checkerError)
 }
 
+func TestHandleCheckerError(t *testing.T) {
+
+	t.Parallel()
+
+	contractAddress := common.MustBytesToAddress([]byte{0x1})
+	contractLocation := common.AddressLocation{
+		Address: contractAddress,
+		Name:    "ContractA",
+	}
+	const contractCode = `
+      pub contract ContractA {
+	    init() {
+	      X
+	    }
+	  }
+	`
+
+	var handledErr error
+
+	config := &analysis.Config{
+		Mode: analysis.NeedTypes | analysis.NeedPositionInfo,
+		ResolveCode: func(
+			location common.Location,
+			importingLocation common.Location,
+			importRange ast.Range,
+		) ([]byte, error) {
+			switch location {
+			case contractLocation:
+				return []byte(contractCode), nil
+
+			default:
+				require.FailNow(t,
+					"import of unknown location: %s",
+					"location: %s",
+					location,
+				)
+				return nil, nil
+			}
+		},
+		HandleCheckerError: func(err analysis.ParsingCheckingError, checker *sema.Checker) error {
+			require.NotNil(t, checker)
+			handledErr = err
+			return nil
+		},
+	}
+
+	programs, err := analysis.Load(config, contractLocation)
+	require.NoError(t, err)
+
+	var checkerError *sema.CheckerError
+	require.ErrorAs(t, handledErr, &checkerError)
+
+	program := programs[contractLocation]
+	require.NotNil(t, program)
+	require.NotNil(t, program.Elaboration)
+	require.NotNil(t, program.PositionInfo)
+}
+
 func TestStdlib(t *testing.T) {
 
 	t.Parallel()
@@ -377,3 +435,74 @@ func TestCyclicImports(t *testing.T) {
 	errs = checker.RequireCheckerErrors(t, nestedCheckerErr, 1)
 	require.IsType(t, &sema.CyclicImportsError{}, errs[0])
 }
+
+func TestLanguageFeatures(t *testing.T) {
+
+	t.Parallel()
+
+	scriptLocation := common.ScriptLocation{}
+
+	const code = `
+      pub resource R {}
+
+      pub attachment A for R {}
+
+      pub fun main(account: AuthAccount) {
+          account.linkAccount(/private/account)
+          account.capabilities.get<&Int>(/public/x)
+      }
+	`
+
+	newConfig := func() *analysis.Config {
+		return &analysis.Config{
+			Mode: analysis.NeedTypes,
+			ResolveCode: func(
+				location common.Location,
+				importingLocation common.Location,
+				importRange ast.Range,
+			) ([]byte, error) {
+				switch location {
+				case scriptLocation:
+					return []byte(code), nil
+
+				default:
+					require.FailNow(t,
+						"import of unknown location: %s",
+						"location: %s",
+						location,
+					)
+					return nil, nil
+				}
+			},
+		}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := analysis.Load(newConfig(), scriptLocation)
+		require.Error(t, err)
+
+		var checkerError *sema.CheckerError
+		require.ErrorAs(t, err, &checkerError)
+
+		errs := checker.RequireCheckerErrors(t, checkerError, 3)
+		require.IsType(t, &sema.AttachmentsNotEnabledError{}, errs[0])
+		require.IsType(t, &sema.NotDeclaredMemberError{}, errs[1])
+		require.IsType(t, &sema.NotDeclaredMemberError{}, errs[2])
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		config := newConfig()
+		config.AccountLinkingEnabled = true
+		config.AttachmentsEnabled = true
+		config.CapabilityControllersEnabled = true
+
+		_, err := analysis.Load(config, scriptLocation)
+		require.NoError(t, err)
+	})
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
 )
 
 // A Config specifies details about how programs should be loaded.
@@ -38,8 +39,19 @@ type Config struct {
 		importingLocation common.Location,
 		importRange ast.Range,
 	) ([]byte, error)
+	// HandleCheckerError is called when checking a program fails.
+	// If it returns nil, the program is loaded despite the errors,
+	// otherwise the returned error is returned by Load.
+	// By default, checker errors are returned
+	HandleCheckerError func(err ParsingCheckingError, checker *sema.Checker) error
 	// Mode controls the level of information returned for each program
 	Mode LoadMode
+	// AccountLinkingEnabled determines if account linking is enabled when checking programs
+	AccountLinkingEnabled bool
+	// AttachmentsEnabled determines if attachments are enabled when checking programs
+	AttachmentsEnabled bool
+	// CapabilityControllersEnabled determines if capability controllers are enabled when checking programs
+	CapabilityControllersEnabled bool
 }
 
 func NewSimpleConfig(
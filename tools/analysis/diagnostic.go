@@ -21,6 +21,7 @@ package analysis
 import (
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
 	"github.com/onflow/cadence/runtime/sema"
 )
 
@@ -28,12 +29,47 @@ type SuggestedFix = sema.SuggestedFix
 
 type TextEdit = sema.TextEdit
 
+// Severity is the severity of a diagnostic
+type Severity uint8
+
+const (
+	// SeverityWarning is the severity of diagnostics reported by analyzers, unless specified otherwise
+	SeverityWarning Severity = iota
+	// SeverityError is the severity of parsing and checking errors
+	SeverityError
+	// SeverityInfo is the severity of informational diagnostics
+	SeverityInfo
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityInfo:
+		return "info"
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
 type Diagnostic struct {
 	Location         common.Location
 	Category         string
+	ErrorType        string
 	Message          string
 	SecondaryMessage string
 	SuggestedFixes   []SuggestedFix
+	Notes            []DiagnosticNote
+	ast.Range
+	Severity Severity
+}
+
+// DiagnosticNote is an additional message of a diagnostic,
+// which may refer to a different range of the code
+type DiagnosticNote struct {
+	Message string
 	ast.Range
 }
 
@@ -19,10 +19,14 @@
 package analysis
 
 import (
+	"reflect"
+
 	"golang.org/x/xerrors"
 
+	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/sema"
 )
 
 type ParsingCheckingError struct {
@@ -46,3 +50,97 @@ func (e ParsingCheckingError) ImportLocation() common.Location {
 func (e ParsingCheckingError) ChildErrors() []error {
 	return []error{e.error}
 }
+
+// ErrorDiagnostics returns the diagnostics for the given parsing or checking error,
+// which occurred in the program with the given location.
+//
+// Parent errors, e.g. checker errors, are flattened into the diagnostics for their child errors.
+// The codes of the programs are used to determine the suggested fixes
+func ErrorDiagnostics(
+	err error,
+	location common.Location,
+	codes map[common.Location][]byte,
+) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	var addDiagnostics func(err error, location common.Location)
+	addDiagnostics = func(err error, location common.Location) {
+
+		if err, ok := err.(common.HasLocation); ok {
+			importLocation := err.ImportLocation()
+			if importLocation != nil {
+				location = importLocation
+			}
+		}
+
+		if err, ok := err.(errors.ParentError); ok {
+			for _, childErr := range err.ChildErrors() {
+				addDiagnostics(childErr, location)
+			}
+			return
+		}
+
+		diagnostics = append(
+			diagnostics,
+			errorDiagnostic(err, location, codes[location]),
+		)
+	}
+
+	addDiagnostics(err, location)
+
+	return diagnostics
+}
+
+func errorDiagnostic(err error, location common.Location, code []byte) Diagnostic {
+	diagnostic := Diagnostic{
+		Location:  location,
+		Category:  errorCategory(err),
+		ErrorType: errorType(err),
+		Message:   err.Error(),
+		Severity:  SeverityError,
+	}
+
+	if positioned, ok := err.(ast.HasPosition); ok {
+		diagnostic.Range = ast.NewUnmeteredRangeFromPositioned(positioned)
+	}
+
+	if secondaryError, ok := err.(errors.SecondaryError); ok {
+		diagnostic.SecondaryMessage = secondaryError.SecondaryError()
+	}
+
+	if errorNotes, ok := err.(errors.ErrorNotes); ok {
+		for _, errorNote := range errorNotes.ErrorNotes() {
+			note := DiagnosticNote{
+				Message: errorNote.Message(),
+			}
+			if positioned, ok := errorNote.(ast.HasPosition); ok {
+				note.Range = ast.NewUnmeteredRangeFromPositioned(positioned)
+			}
+			diagnostic.Notes = append(diagnostic.Notes, note)
+		}
+	}
+
+	if hasSuggestedFixes, ok := err.(sema.HasSuggestedFixes); ok {
+		diagnostic.SuggestedFixes = hasSuggestedFixes.SuggestFixes(string(code))
+	}
+
+	return diagnostic
+}
+
+// errorCategory returns the category of the given error,
+// i.e. its stable error code, e.g. `C0001`, or its type if it has no code
+func errorCategory(err error) string {
+	if hasErrorCode, ok := err.(errors.HasErrorCode); ok {
+		return string(hasErrorCode.ErrorCode())
+	}
+	return errorType(err)
+}
+
+// errorType returns the name of the type of the given error, e.g. `NotDeclaredError`
+func errorType(err error) string {
+	ty := reflect.TypeOf(err)
+	for ty.Kind() == reflect.Pointer {
+		ty = ty.Elem()
+	}
+	return ty.Name()
+}
@@ -0,0 +1,170 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// textEditSpan is the part of the code which a text edit replaces
+type textEditSpan struct {
+	text  string
+	start int
+	end   int
+}
+
+// newTextEditSpan returns the span of the given text edit:
+// An edit with an insertion inserts it at its start position,
+// any other edit replaces its (inclusive) range with its replacement
+func newTextEditSpan(edit TextEdit) textEditSpan {
+	if edit.Insertion != "" {
+		return textEditSpan{
+			start: edit.StartPos.Offset,
+			end:   edit.StartPos.Offset,
+			text:  edit.Insertion,
+		}
+	}
+
+	return textEditSpan{
+		start: edit.StartPos.Offset,
+		end:   edit.EndPos.Offset + 1,
+		text:  edit.Replacement,
+	}
+}
+
+func (s textEditSpan) overlaps(other textEditSpan) bool {
+	return s.start < other.end && other.start < s.end
+}
+
+// MergeFixes merges the text edits of the given suggested fixes, in order.
+// A fix is only merged if none of its edits overlap with the edits of the previously merged fixes,
+// so either all or none of the edits of a fix are merged. Duplicate edits are only merged once.
+//
+// It returns the merged edits, sorted by position,
+// and which of the given fixes were merged
+func MergeFixes(fixes []SuggestedFix) (edits []TextEdit, merged []bool) {
+	merged = make([]bool, len(fixes))
+
+	var spans []textEditSpan
+
+	for i, fix := range fixes {
+		var newEdits []TextEdit
+		var newSpans []textEditSpan
+		conflict := false
+
+	Edits:
+		for _, edit := range fix.TextEdits {
+			span := newTextEditSpan(edit)
+
+			for _, other := range spans {
+				if span == other {
+					continue Edits
+				}
+				if span.overlaps(other) {
+					conflict = true
+					break Edits
+				}
+			}
+
+			newEdits = append(newEdits, edit)
+			newSpans = append(newSpans, span)
+		}
+
+		if conflict {
+			continue
+		}
+
+		edits = append(edits, newEdits...)
+		spans = append(spans, newSpans...)
+		merged[i] = true
+	}
+
+	sortTextEdits(edits)
+
+	return edits, merged
+}
+
+// sortTextEdits sorts the given text edits by position.
+// Insertions are sorted before replacements at the same position,
+// and the order of insertions at the same position is preserved
+func sortTextEdits(edits []TextEdit) {
+	sort.SliceStable(edits, func(i, j int) bool {
+		a := newTextEditSpan(edits[i])
+		b := newTextEditSpan(edits[j])
+		if a.start != b.start {
+			return a.start < b.start
+		}
+		return a.end < b.end
+	})
+}
+
+// ApplyTextEdits applies the given text edits to the given code, and returns the resulting code.
+// The edits must not overlap, e.g. they should be merged using MergeFixes
+func ApplyTextEdits(code []byte, edits []TextEdit) ([]byte, error) {
+	sortedEdits := make([]TextEdit, len(edits))
+	copy(sortedEdits, edits)
+	sortTextEdits(sortedEdits)
+
+	var result bytes.Buffer
+	result.Grow(len(code))
+
+	offset := 0
+
+	for _, edit := range sortedEdits {
+		span := newTextEditSpan(edit)
+
+		if span.start < offset {
+			return nil, fmt.Errorf(
+				"overlapping text edit at offset %d",
+				span.start,
+			)
+		}
+
+		if span.end > len(code) || span.start > span.end {
+			return nil, fmt.Errorf(
+				"invalid text edit at offset %d",
+				span.start,
+			)
+		}
+
+		result.Write(code[offset:span.start])
+		result.WriteString(span.text)
+		offset = span.end
+	}
+
+	result.Write(code[offset:])
+
+	return result.Bytes(), nil
+}
+
+// ApplyFixes merges the given suggested fixes using MergeFixes,
+// and applies the merged text edits to the given code.
+// It returns the resulting code, and which of the given fixes were applied
+func ApplyFixes(code []byte, fixes []SuggestedFix) ([]byte, []bool, error) {
+	edits, merged := MergeFixes(fixes)
+
+	result, err := ApplyTextEdits(code, edits)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result, merged, nil
+}
@@ -0,0 +1,512 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/tests/checker"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+func offsetRange(start, end int) ast.Range {
+	return ast.NewUnmeteredRange(
+		ast.Position{Offset: start},
+		ast.Position{Offset: end},
+	)
+}
+
+func TestApplyTextEdits(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("insertions and replacements", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, err := analysis.ApplyTextEdits(
+			[]byte("let x = f(a)"),
+			[]analysis.TextEdit{
+				{
+					Replacement: "y",
+					Range:       offsetRange(4, 4),
+				},
+				{
+					Insertion: "<-",
+					Range:     offsetRange(8, 8),
+				},
+				{
+					Insertion: "label: ",
+					Range:     offsetRange(10, 10),
+				},
+				{
+					Replacement: "<-",
+					Range:       offsetRange(6, 6),
+				},
+			},
+		)
+		require.NoError(t, err)
+		require.Equal(t, "let y <- <-f(label: a)", string(result))
+	})
+
+	t.Run("removal", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, err := analysis.ApplyTextEdits(
+			[]byte("let x = <- 1"),
+			[]analysis.TextEdit{
+				{
+					Replacement: "",
+					Range:       offsetRange(8, 10),
+				},
+			},
+		)
+		require.NoError(t, err)
+		require.Equal(t, "let x = 1", string(result))
+	})
+
+	t.Run("overlapping", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := analysis.ApplyTextEdits(
+			[]byte("let x = 1"),
+			[]analysis.TextEdit{
+				{
+					Replacement: "var",
+					Range:       offsetRange(0, 2),
+				},
+				{
+					Insertion: "y",
+					Range:     offsetRange(1, 1),
+				},
+			},
+		)
+		require.ErrorContains(t, err, "overlapping text edit at offset 1")
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := analysis.ApplyTextEdits(
+			[]byte("let x = 1"),
+			[]analysis.TextEdit{
+				{
+					Replacement: "2",
+					Range:       offsetRange(9, 9),
+				},
+			},
+		)
+		require.ErrorContains(t, err, "invalid text edit at offset 9")
+	})
+}
+
+func TestMergeFixes(t *testing.T) {
+
+	t.Parallel()
+
+	code := []byte("let x = f(a)")
+
+	fixes := []analysis.SuggestedFix{
+		{
+			Message: "rename",
+			TextEdits: []analysis.TextEdit{
+				{
+					Replacement: "y",
+					Range:       offsetRange(4, 4),
+				},
+			},
+		},
+		{
+			Message: "replace declaration",
+			TextEdits: []analysis.TextEdit{
+				{
+					Replacement: "var z",
+					Range:       offsetRange(0, 4),
+				},
+				{
+					Insertion: "b, ",
+					Range:     offsetRange(10, 10),
+				},
+			},
+		},
+		{
+			Message: "insert labels",
+			TextEdits: []analysis.TextEdit{
+				{
+					Insertion: "label: ",
+					Range:     offsetRange(10, 10),
+				},
+				{
+					Replacement: "y",
+					Range:       offsetRange(4, 4),
+				},
+			},
+		},
+		{
+			Message: "insert more",
+			TextEdits: []analysis.TextEdit{
+				{
+					Insertion: "other: ",
+					Range:     offsetRange(10, 10),
+				},
+			},
+		},
+	}
+
+	edits, merged := analysis.MergeFixes(fixes)
+	require.Equal(t, []bool{true, false, true, true}, merged)
+	require.Len(t, edits, 3)
+
+	result, err := analysis.ApplyTextEdits(code, edits)
+	require.NoError(t, err)
+	require.Equal(t, "let y = f(label: other: a)", string(result))
+
+	result, applied, err := analysis.ApplyFixes(code, fixes)
+	require.NoError(t, err)
+	require.Equal(t, merged, applied)
+	require.Equal(t, "let y = f(label: other: a)", string(result))
+}
+
+// applyCheckerErrorFixes checks the given code,
+// and applies the first suggested fix of each checker error
+func applyCheckerErrorFixes(t *testing.T, code string, config *sema.Config) string {
+	if config == nil {
+		config = &sema.Config{}
+	}
+
+	_, err := checker.ParseAndCheckWithOptions(t,
+		code,
+		checker.ParseAndCheckOptions{
+			Config: config,
+		},
+	)
+	require.Error(t, err)
+
+	var checkerErr *sema.CheckerError
+	require.ErrorAs(t, err, &checkerErr)
+
+	var fixes []analysis.SuggestedFix
+	for _, childErr := range checkerErr.Errors {
+		hasFixes, ok := childErr.(sema.HasSuggestedFixes)
+		if !ok {
+			continue
+		}
+		suggestedFixes := hasFixes.SuggestFixes(code)
+		if len(suggestedFixes) == 0 {
+			continue
+		}
+		fixes = append(fixes, suggestedFixes[0])
+	}
+
+	result, _, err := analysis.ApplyFixes([]byte(code), fixes)
+	require.NoError(t, err)
+
+	return string(result)
+}
+
+func TestCheckerErrorFixes(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("missing move operation", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := applyCheckerErrorFixes(t,
+			`
+              resource R {}
+
+              fun consume(_ r: @R) {
+                  destroy r
+              }
+
+              fun test() {
+                  let r <- create R()
+                  consume(r)
+              }
+            `,
+			nil,
+		)
+
+		require.Contains(t, result, "consume(<-r)")
+	})
+
+	t.Run("incorrect transfer operation", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := applyCheckerErrorFixes(t,
+			`
+              resource R {}
+
+              fun test() {
+                  let r = create R()
+                  destroy r
+              }
+            `,
+			nil,
+		)
+
+		require.Contains(t, result, "let r <- create R()")
+	})
+
+	t.Run("invalid move operation", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := applyCheckerErrorFixes(t,
+			`
+              let x = <- 1
+            `,
+			nil,
+		)
+
+		require.Contains(t, result, "let x = 1")
+	})
+
+	t.Run("lost resource of expression statement", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := applyCheckerErrorFixes(t,
+			`
+              resource R {}
+
+              fun test() {
+                  create R()
+              }
+            `,
+			nil,
+		)
+
+		require.Contains(t, result, "destroy create R()")
+	})
+
+	t.Run("lost resource of variable at end of block", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := applyCheckerErrorFixes(t,
+			`
+              resource R {}
+
+              fun test() {
+                  let r <- create R()
+              }
+            `,
+			nil,
+		)
+
+		require.Contains(t,
+			result,
+			"                  let r <- create R()\n"+
+				"                  destroy r\n"+
+				"              }",
+		)
+	})
+
+	t.Run("lost resource of variable at return", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := applyCheckerErrorFixes(t,
+			`
+              resource R {}
+
+              fun test(): Int {
+                  let r <- create R()
+                  if true {
+                      return 1
+                  }
+                  destroy r
+                  return 2
+              }
+            `,
+			nil,
+		)
+
+		require.Contains(t,
+			result,
+			"                  if true {\n"+
+				"                      destroy r\n"+
+				"                      return 1\n",
+		)
+	})
+
+	t.Run("access modifier of local declaration", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := applyCheckerErrorFixes(t,
+			`
+              fun test() {
+                  pub let x = 1
+                  access(all) let y = 2
+              }
+            `,
+			nil,
+		)
+
+		require.Contains(t, result, "                  let x = 1\n")
+		require.Contains(t, result, "                  let y = 2\n")
+	})
+
+	t.Run("invalid access modifier of type declaration", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := applyCheckerErrorFixes(t,
+			`
+              priv struct S {}
+            `,
+			nil,
+		)
+
+		require.Contains(t, result, "access(all) struct S {}")
+	})
+
+	t.Run("invalid access modifier of constant", func(t *testing.T) {
+
+		t.Parallel()
+
+		// Several access modifiers are valid for the field,
+		// so no fix is suggested
+
+		const code = `
+              pub struct S {
+                  pub(set) let x: Int
+
+                  init() {
+                      self.x = 1
+                  }
+              }
+            `
+
+		result := applyCheckerErrorFixes(t, code, nil)
+
+		require.Equal(t, code, result)
+	})
+
+	t.Run("missing access modifier of type declaration", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := applyCheckerErrorFixes(t,
+			`
+              struct S {}
+            `,
+			&sema.Config{
+				AccessCheckMode: sema.AccessCheckModeStrict,
+			},
+		)
+
+		require.Contains(t, result, "access(all) struct S {}")
+	})
+
+	t.Run("missing access modifier of function", func(t *testing.T) {
+
+		t.Parallel()
+
+		// Several access modifiers are valid for the function,
+		// and the least restrictive one should not be suggested
+
+		const code = `
+              fun test() {}
+            `
+
+		result := applyCheckerErrorFixes(t,
+			code,
+			&sema.Config{
+				AccessCheckMode: sema.AccessCheckModeStrict,
+			},
+		)
+
+		require.Equal(t, code, result)
+	})
+
+	t.Run("missing import", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := applyCheckerErrorFixes(t,
+			`pub fun test(): Test.S {
+                  return Test.S()
+              }
+            `,
+			&sema.Config{
+				ImportSuggestionHandler: func(_ *sema.Checker, identifier string) []common.Location {
+					if identifier != "Test" {
+						return nil
+					}
+					return []common.Location{
+						common.AddressLocation{
+							Address: common.MustBytesToAddress([]byte{0x1}),
+							Name:    "Test",
+						},
+					}
+				},
+			},
+		)
+
+		require.Equal(t,
+			"import Test from 0x1\n\n"+
+				`pub fun test(): Test.S {
+                  return Test.S()
+              }
+            `,
+			result,
+		)
+	})
+}
+
+func TestNotDeclaredErrorImportFix(t *testing.T) {
+
+	t.Parallel()
+
+	code := "import A from 0x1\nlet x = B.y\n"
+
+	importPos := ast.Position{Offset: 16, Line: 1, Column: 16}
+
+	notDeclaredErr := &sema.NotDeclaredError{
+		Name:      "B",
+		Pos:       ast.Position{Offset: 26, Line: 2, Column: 8},
+		ImportPos: &importPos,
+		SuggestedImportLocations: []common.Location{
+			common.StringLocation("b.cdc"),
+			common.IdentifierLocation("B"),
+		},
+	}
+
+	fixes := notDeclaredErr.SuggestFixes(code)
+	require.Len(t, fixes, 2)
+	require.Equal(t, "add `import B from \"b.cdc\"`", fixes[0].Message)
+	require.Equal(t, "add `import B from B`", fixes[1].Message)
+
+	result, err := analysis.ApplyTextEdits([]byte(code), fixes[0].TextEdits)
+	require.NoError(t, err)
+	require.Equal(t, "import A from 0x1\nimport B from \"b.cdc\"\nlet x = B.y\n", string(result))
+}
@@ -30,7 +30,9 @@ type Program struct {
 	Location    common.Location
 	Program     *ast.Program
 	Elaboration *sema.Elaboration
-	Code        []byte
+	// PositionInfo is only available if the program was loaded with NeedPositionInfo
+	PositionInfo *sema.PositionInfo
+	Code         []byte
 }
 
 // Run runs the given DAG of analyzers in parallel
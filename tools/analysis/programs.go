@@ -73,18 +73,29 @@ func (programs Programs) load(
 	}
 
 	var elaboration *sema.Elaboration
+	var positionInfo *sema.PositionInfo
 	if config.Mode&NeedTypes != 0 {
-		elaboration, err = programs.check(config, program, location, seenImports)
+		var checker *sema.Checker
+		checker, err = programs.check(config, program, location, seenImports)
 		if err != nil {
-			return wrapError(err)
+			if checker == nil || config.HandleCheckerError == nil {
+				return wrapError(err)
+			}
+			err = config.HandleCheckerError(wrapError(err), checker)
+			if err != nil {
+				return err
+			}
 		}
+		elaboration = checker.Elaboration
+		positionInfo = checker.PositionInfo
 	}
 
 	programs[location] = &Program{
-		Location:    location,
-		Code:        code,
-		Program:     program,
-		Elaboration: elaboration,
+		Location:     location,
+		Code:         code,
+		Program:      program,
+		Elaboration:  elaboration,
+		PositionInfo: positionInfo,
 	}
 
 	return nil
@@ -96,7 +107,7 @@ func (programs Programs) check(
 	location common.Location,
 	seenImports importResolutionResults,
 ) (
-	*sema.Elaboration,
+	*sema.Checker,
 	error,
 ) {
 	baseValueActivation := sema.NewVariableActivation(sema.BaseValueActivation)
@@ -114,8 +125,11 @@ func (programs Programs) check(
 			LocationHandler: sema.AddressLocationHandlerFunc(
 				config.ResolveAddressContractNames,
 			),
-			PositionInfoEnabled:        config.Mode&NeedPositionInfo != 0,
-			ExtendedElaborationEnabled: config.Mode&NeedExtendedElaboration != 0,
+			PositionInfoEnabled:          config.Mode&NeedPositionInfo != 0,
+			ExtendedElaborationEnabled:   config.Mode&NeedExtendedElaboration != 0,
+			AccountLinkingEnabled:        config.AccountLinkingEnabled,
+			AttachmentsEnabled:           config.AttachmentsEnabled,
+			CapabilityControllersEnabled: config.CapabilityControllersEnabled,
 			ImportHandler: func(
 				checker *sema.Checker,
 				importedLocation common.Location,
@@ -158,8 +172,8 @@ func (programs Programs) check(
 
 	err = checker.Check()
 	if err != nil {
-		return nil, err
+		return checker, err
 	}
 
-	return checker.Elaboration, nil
+	return checker, nil
 }
@@ -0,0 +1,182 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+)
+
+// ReportFormat is a machine-readable format in which diagnostics are reported
+type ReportFormat uint8
+
+const (
+	// ReportFormatJSON reports diagnostics as a JSON array
+	ReportFormatJSON ReportFormat = iota
+	// ReportFormatSARIF reports diagnostics as a SARIF 2.1.0 log
+	ReportFormatSARIF
+	// ReportFormatGitHub reports diagnostics as GitHub Actions workflow commands,
+	// which are shown as annotations of the code
+	ReportFormatGitHub
+)
+
+// WriteReport writes the given diagnostics to the given writer, in the given format.
+// The name of the tool which reported the diagnostics is included in formats which support it
+func WriteReport(
+	writer io.Writer,
+	format ReportFormat,
+	toolName string,
+	diagnostics []Diagnostic,
+) error {
+	switch format {
+	case ReportFormatJSON:
+		return WriteJSONReport(writer, diagnostics)
+	case ReportFormatSARIF:
+		return WriteSARIFReport(writer, toolName, diagnostics)
+	case ReportFormatGitHub:
+		return WriteGitHubAnnotations(writer, diagnostics)
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
+type jsonPosition struct {
+	Offset int `json:"offset"`
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func newJSONPosition(position ast.Position) jsonPosition {
+	return jsonPosition{
+		Offset: position.Offset,
+		Line:   position.Line,
+		Column: position.Column,
+	}
+}
+
+// jsonRange is the range of a diagnostic or note. Like in the AST, the end position is inclusive
+type jsonRange struct {
+	Start jsonPosition `json:"start"`
+	End   jsonPosition `json:"end"`
+}
+
+func newJSONRange(astRange ast.Range) jsonRange {
+	return jsonRange{
+		Start: newJSONPosition(astRange.StartPos),
+		End:   newJSONPosition(astRange.EndPos),
+	}
+}
+
+type jsonNote struct {
+	Message string    `json:"message"`
+	Range   jsonRange `json:"range"`
+}
+
+// jsonTextEdit replaces the given number of bytes of the code at the start position with the text.
+// An insertion has a length of zero
+type jsonTextEdit struct {
+	Text   string       `json:"text"`
+	Start  jsonPosition `json:"start"`
+	Length int          `json:"length"`
+}
+
+type jsonSuggestedFix struct {
+	Message string         `json:"message"`
+	Edits   []jsonTextEdit `json:"edits"`
+}
+
+type jsonDiagnostic struct {
+	Location         string             `json:"location"`
+	Severity         string             `json:"severity"`
+	Category         string             `json:"category"`
+	ErrorType        string             `json:"errorType,omitempty"`
+	Message          string             `json:"message"`
+	SecondaryMessage string             `json:"secondaryMessage,omitempty"`
+	Notes            []jsonNote         `json:"notes,omitempty"`
+	SuggestedFixes   []jsonSuggestedFix `json:"suggestedFixes,omitempty"`
+	Range            jsonRange          `json:"range"`
+}
+
+// WriteJSONReport writes the given diagnostics to the given writer as a JSON array
+func WriteJSONReport(writer io.Writer, diagnostics []Diagnostic) error {
+	jsonDiagnostics := make([]jsonDiagnostic, 0, len(diagnostics))
+
+	for _, diagnostic := range diagnostics {
+		jsonDiagnostic := jsonDiagnostic{
+			Location:         diagnosticPath(diagnostic.Location),
+			Severity:         diagnostic.Severity.String(),
+			Category:         diagnostic.Category,
+			ErrorType:        diagnostic.ErrorType,
+			Message:          diagnostic.Message,
+			SecondaryMessage: diagnostic.SecondaryMessage,
+			Range:            newJSONRange(diagnostic.Range),
+		}
+
+		for _, note := range diagnostic.Notes {
+			jsonDiagnostic.Notes = append(
+				jsonDiagnostic.Notes,
+				jsonNote{
+					Message: note.Message,
+					Range:   newJSONRange(note.Range),
+				},
+			)
+		}
+
+		for _, fix := range diagnostic.SuggestedFixes {
+			jsonFix := jsonSuggestedFix{
+				Message: fix.Message,
+				Edits:   make([]jsonTextEdit, 0, len(fix.TextEdits)),
+			}
+
+			for _, edit := range fix.TextEdits {
+				span := newTextEditSpan(edit)
+				jsonFix.Edits = append(
+					jsonFix.Edits,
+					jsonTextEdit{
+						Text:   span.text,
+						Start:  newJSONPosition(edit.StartPos),
+						Length: span.end - span.start,
+					},
+				)
+			}
+
+			jsonDiagnostic.SuggestedFixes = append(jsonDiagnostic.SuggestedFixes, jsonFix)
+		}
+
+		jsonDiagnostics = append(jsonDiagnostics, jsonDiagnostic)
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(jsonDiagnostics)
+}
+
+// diagnosticPath returns the path of the file with the given location,
+// or the string representation of the location if it is not a file
+func diagnosticPath(location common.Location) string {
+	if location == nil {
+		return ""
+	}
+	return location.String()
+}
@@ -0,0 +1,114 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/errors"
+)
+
+// githubAnnotationCommand returns the workflow command which annotates code with a diagnostic of the given severity
+func githubAnnotationCommand(severity Severity) string {
+	switch severity {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "notice"
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
+var githubMessageEscaper = strings.NewReplacer(
+	"%", "%25",
+	"\r", "%0D",
+	"\n", "%0A",
+)
+
+var githubPropertyEscaper = strings.NewReplacer(
+	"%", "%25",
+	"\r", "%0D",
+	"\n", "%0A",
+	":", "%3A",
+	",", "%2C",
+)
+
+// WriteGitHubAnnotations writes the given diagnostics to the given writer as GitHub Actions workflow commands,
+// e.g. `::error file=test.cdc,line=1,col=1::message`, so they are shown as annotations of the code.
+// Columns are 1-based, and the end column is inclusive
+func WriteGitHubAnnotations(writer io.Writer, diagnostics []Diagnostic) error {
+	for _, diagnostic := range diagnostics {
+		properties := []string{
+			"file=" + githubPropertyEscaper.Replace(diagnosticPath(diagnostic.Location)),
+		}
+
+		if diagnostic.StartPos.Line > 0 {
+			properties = append(
+				properties,
+				fmt.Sprintf("line=%d", diagnostic.StartPos.Line),
+				fmt.Sprintf("endLine=%d", diagnostic.EndPos.Line),
+				fmt.Sprintf("col=%d", diagnostic.StartPos.Column+1),
+				fmt.Sprintf("endColumn=%d", diagnostic.EndPos.Column+1),
+			)
+		}
+
+		if diagnostic.Category != "" {
+			properties = append(
+				properties,
+				"title="+githubPropertyEscaper.Replace(diagnostic.Category),
+			)
+		}
+
+		var message strings.Builder
+		message.WriteString(diagnostic.Message)
+		if diagnostic.SecondaryMessage != "" {
+			message.WriteString(": ")
+			message.WriteString(diagnostic.SecondaryMessage)
+		}
+		for _, note := range diagnostic.Notes {
+			message.WriteString("\n")
+			if note.StartPos.Line > 0 {
+				fmt.Fprintf(&message, "%d:%d: ", note.StartPos.Line, note.StartPos.Column)
+			}
+			message.WriteString(note.Message)
+		}
+		for _, fix := range diagnostic.SuggestedFixes {
+			message.WriteString("\nsuggested fix: ")
+			message.WriteString(fix.Message)
+		}
+
+		_, err := fmt.Fprintf(
+			writer,
+			"::%s %s::%s\n",
+			githubAnnotationCommand(diagnostic.Severity),
+			strings.Join(properties, ","),
+			githubMessageEscaper.Replace(message.String()),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
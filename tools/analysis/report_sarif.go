@@ -0,0 +1,275 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+const sarifVersion = "2.1.0"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID           string           `json:"ruleId"`
+	Level            string           `json:"level"`
+	Message          sarifMessage     `json:"message"`
+	Locations        []sarifLocation  `json:"locations,omitempty"`
+	RelatedLocations []sarifLocation  `json:"relatedLocations,omitempty"`
+	Fixes            []sarifFix       `json:"fixes,omitempty"`
+	Properties       *sarifProperties `json:"properties,omitempty"`
+}
+
+// sarifProperties is the property bag of a result
+type sarifProperties struct {
+	ErrorType string `json:"errorType,omitempty"`
+}
+
+type sarifLocation struct {
+	Message          *sarifMessage         `json:"message,omitempty"`
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	ID               *int                  `json:"id,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// sarifRegion is a region of an artifact.
+// Lines and columns are 1-based, and the end column is exclusive.
+// Alternatively, the region is a range of characters
+type sarifRegion struct {
+	StartLine   int  `json:"startLine,omitempty"`
+	StartColumn int  `json:"startColumn,omitempty"`
+	EndLine     int  `json:"endLine,omitempty"`
+	EndColumn   int  `json:"endColumn,omitempty"`
+	CharOffset  *int `json:"charOffset,omitempty"`
+	CharLength  *int `json:"charLength,omitempty"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion   `json:"deletedRegion"`
+	InsertedContent *sarifMessage `json:"insertedContent,omitempty"`
+}
+
+// sarifLevel returns the SARIF level of a result with the given severity
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "note"
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
+// newSARIFRegion returns the region of the given range, or nil if the range has no position
+func newSARIFRegion(astRange ast.Range) *sarifRegion {
+	if astRange.StartPos.Line == 0 {
+		return nil
+	}
+
+	return &sarifRegion{
+		StartLine:   astRange.StartPos.Line,
+		StartColumn: astRange.StartPos.Column + 1,
+		EndLine:     astRange.EndPos.Line,
+		EndColumn:   astRange.EndPos.Column + 2,
+	}
+}
+
+func newSARIFLocation(location common.Location, astRange ast.Range) sarifLocation {
+	return sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{
+				URI: diagnosticPath(location),
+			},
+			Region: newSARIFRegion(astRange),
+		},
+	}
+}
+
+// WriteSARIFReport writes the given diagnostics to the given writer as a SARIF 2.1.0 log,
+// which contains a single run of the tool with the given name.
+// The categories of the diagnostics are the rules of the tool,
+// and the types of errors are properties of the results
+func WriteSARIFReport(writer io.Writer, toolName string, diagnostics []Diagnostic) error {
+	ruleIDs := map[string]struct{}{}
+
+	results := make([]sarifResult, 0, len(diagnostics))
+
+	for _, diagnostic := range diagnostics {
+		ruleIDs[diagnostic.Category] = struct{}{}
+
+		message := diagnostic.Message
+		if diagnostic.SecondaryMessage != "" {
+			message += ": " + diagnostic.SecondaryMessage
+		}
+
+		result := sarifResult{
+			RuleID: diagnostic.Category,
+			Level:  sarifLevel(diagnostic.Severity),
+			Message: sarifMessage{
+				Text: message,
+			},
+			Locations: []sarifLocation{
+				newSARIFLocation(diagnostic.Location, diagnostic.Range),
+			},
+		}
+
+		if diagnostic.ErrorType != "" {
+			result.Properties = &sarifProperties{
+				ErrorType: diagnostic.ErrorType,
+			}
+		}
+
+		for i, note := range diagnostic.Notes {
+			id := i
+			relatedLocation := newSARIFLocation(diagnostic.Location, note.Range)
+			relatedLocation.ID = &id
+			relatedLocation.Message = &sarifMessage{
+				Text: note.Message,
+			}
+			result.RelatedLocations = append(result.RelatedLocations, relatedLocation)
+		}
+
+		for _, fix := range diagnostic.SuggestedFixes {
+			change := sarifArtifactChange{
+				ArtifactLocation: sarifArtifactLocation{
+					URI: diagnosticPath(diagnostic.Location),
+				},
+				Replacements: make([]sarifReplacement, 0, len(fix.TextEdits)),
+			}
+
+			for _, edit := range fix.TextEdits {
+				span := newTextEditSpan(edit)
+				offset := span.start
+				length := span.end - span.start
+
+				replacement := sarifReplacement{
+					DeletedRegion: sarifRegion{
+						CharOffset: &offset,
+						CharLength: &length,
+					},
+				}
+				if span.text != "" {
+					replacement.InsertedContent = &sarifMessage{
+						Text: span.text,
+					}
+				}
+
+				change.Replacements = append(change.Replacements, replacement)
+			}
+
+			result.Fixes = append(
+				result.Fixes,
+				sarifFix{
+					Description: sarifMessage{
+						Text: fix.Message,
+					},
+					ArtifactChanges: []sarifArtifactChange{change},
+				},
+			)
+		}
+
+		results = append(results, result)
+	}
+
+	rules := make([]sarifRule, 0, len(ruleIDs))
+	for ruleID := range ruleIDs { //nolint:maprange
+		rules = append(rules, sarifRule{ID: ruleID})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:    toolName,
+						Version: cadence.Version,
+						Rules:   rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(log)
+}
@@ -0,0 +1,335 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const reportTestCode = `
+pub resource R {}
+
+pub fun test() {
+    let r: @R = create R()
+    destroy r
+    let x = 1
+    let x = 2
+}
+`
+
+func reportTestDiagnostics(t *testing.T) []analysis.Diagnostic {
+	location := common.StringLocation("test.cdc")
+
+	codes := map[common.Location][]byte{}
+
+	config := &analysis.Config{
+		Mode: analysis.NeedTypes,
+		ResolveCode: func(
+			location common.Location,
+			_ common.Location,
+			_ ast.Range,
+		) ([]byte, error) {
+			code := []byte(reportTestCode)
+			codes[location] = code
+			return code, nil
+		},
+	}
+
+	_, err := analysis.Load(config, location)
+	require.Error(t, err)
+
+	return analysis.ErrorDiagnostics(err, nil, codes)
+}
+
+func TestErrorDiagnostics(t *testing.T) {
+
+	t.Parallel()
+
+	location := common.StringLocation("test.cdc")
+
+	require.Equal(t,
+		[]analysis.Diagnostic{
+			{
+				Location:         location,
+				Category:         "C0064",
+				ErrorType:        "IncorrectTransferOperationError",
+				Message:          "incorrect transfer operation",
+				SecondaryMessage: "expected `<-`",
+				SuggestedFixes: []analysis.SuggestedFix{
+					{
+						Message: "replace with `<-`",
+						TextEdits: []analysis.TextEdit{
+							{
+								Replacement: "<-",
+								Range: ast.Range{
+									StartPos: ast.Position{Offset: 51, Line: 5, Column: 14},
+									EndPos:   ast.Position{Offset: 51, Line: 5, Column: 14},
+								},
+							},
+						},
+					},
+				},
+				Range: ast.Range{
+					StartPos: ast.Position{Offset: 51, Line: 5, Column: 14},
+					EndPos:   ast.Position{Offset: 51, Line: 5, Column: 14},
+				},
+				Severity: analysis.SeverityError,
+			},
+			{
+				Location:  location,
+				Category:  "C0002",
+				ErrorType: "RedeclarationError",
+				Message:   "cannot redeclare constant: `x` is already declared",
+				Notes: []analysis.DiagnosticNote{
+					{
+						Message: "previously declared here",
+						Range: ast.Range{
+							StartPos: ast.Position{Offset: 86, Line: 7, Column: 8},
+							EndPos:   ast.Position{Offset: 86, Line: 7, Column: 8},
+						},
+					},
+				},
+				Range: ast.Range{
+					StartPos: ast.Position{Offset: 100, Line: 8, Column: 8},
+					EndPos:   ast.Position{Offset: 100, Line: 8, Column: 8},
+				},
+				Severity: analysis.SeverityError,
+			},
+		},
+		reportTestDiagnostics(t),
+	)
+}
+
+func TestWriteJSONReport(t *testing.T) {
+
+	t.Parallel()
+
+	var builder strings.Builder
+	err := analysis.WriteReport(
+		&builder,
+		analysis.ReportFormatJSON,
+		"cadence-test",
+		reportTestDiagnostics(t),
+	)
+	require.NoError(t, err)
+
+	require.JSONEq(t,
+		`
+          [
+            {
+              "location": "test.cdc",
+              "severity": "error",
+              "category": "C0064",
+              "errorType": "IncorrectTransferOperationError",
+              "message": "incorrect transfer operation",
+              "secondaryMessage": "expected `+"`<-`"+`",
+              "suggestedFixes": [
+                {
+                  "message": "replace with `+"`<-`"+`",
+                  "edits": [
+                    {
+                      "text": "<-",
+                      "start": {"offset": 51, "line": 5, "column": 14},
+                      "length": 1
+                    }
+                  ]
+                }
+              ],
+              "range": {
+                "start": {"offset": 51, "line": 5, "column": 14},
+                "end": {"offset": 51, "line": 5, "column": 14}
+              }
+            },
+            {
+              "location": "test.cdc",
+              "severity": "error",
+              "category": "C0002",
+              "errorType": "RedeclarationError",
+              "message": "cannot redeclare constant: `+"`x`"+` is already declared",
+              "notes": [
+                {
+                  "message": "previously declared here",
+                  "range": {
+                    "start": {"offset": 86, "line": 7, "column": 8},
+                    "end": {"offset": 86, "line": 7, "column": 8}
+                  }
+                }
+              ],
+              "range": {
+                "start": {"offset": 100, "line": 8, "column": 8},
+                "end": {"offset": 100, "line": 8, "column": 8}
+              }
+            }
+          ]
+        `,
+		builder.String(),
+	)
+}
+
+func TestWriteSARIFReport(t *testing.T) {
+
+	t.Parallel()
+
+	var builder strings.Builder
+	err := analysis.WriteReport(
+		&builder,
+		analysis.ReportFormatSARIF,
+		"cadence-test",
+		reportTestDiagnostics(t),
+	)
+	require.NoError(t, err)
+
+	require.JSONEq(t,
+		`
+          {
+            "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+            "version": "2.1.0",
+            "runs": [
+              {
+                "tool": {
+                  "driver": {
+                    "name": "cadence-test",
+                    "version": "`+cadence.Version+`",
+                    "rules": [
+                      {"id": "C0002"},
+                      {"id": "C0064"}
+                    ]
+                  }
+                },
+                "results": [
+                  {
+                    "ruleId": "C0064",
+                    "level": "error",
+                    "message": {"text": "incorrect transfer operation: expected `+"`<-`"+`"},
+                    "locations": [
+                      {
+                        "physicalLocation": {
+                          "artifactLocation": {"uri": "test.cdc"},
+                          "region": {"startLine": 5, "startColumn": 15, "endLine": 5, "endColumn": 16}
+                        }
+                      }
+                    ],
+                    "fixes": [
+                      {
+                        "description": {"text": "replace with `+"`<-`"+`"},
+                        "artifactChanges": [
+                          {
+                            "artifactLocation": {"uri": "test.cdc"},
+                            "replacements": [
+                              {
+                                "deletedRegion": {"charOffset": 51, "charLength": 1},
+                                "insertedContent": {"text": "<-"}
+                              }
+                            ]
+                          }
+                        ]
+                      }
+                    ],
+                    "properties": {"errorType": "IncorrectTransferOperationError"}
+                  },
+                  {
+                    "ruleId": "C0002",
+                    "level": "error",
+                    "message": {"text": "cannot redeclare constant: `+"`x`"+` is already declared"},
+                    "locations": [
+                      {
+                        "physicalLocation": {
+                          "artifactLocation": {"uri": "test.cdc"},
+                          "region": {"startLine": 8, "startColumn": 9, "endLine": 8, "endColumn": 10}
+                        }
+                      }
+                    ],
+                    "relatedLocations": [
+                      {
+                        "id": 0,
+                        "message": {"text": "previously declared here"},
+                        "physicalLocation": {
+                          "artifactLocation": {"uri": "test.cdc"},
+                          "region": {"startLine": 7, "startColumn": 9, "endLine": 7, "endColumn": 10}
+                        }
+                      }
+                    ],
+                    "properties": {"errorType": "RedeclarationError"}
+                  }
+                ]
+              }
+            ]
+          }
+        `,
+		builder.String(),
+	)
+}
+
+func TestWriteGitHubAnnotations(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("errors", func(t *testing.T) {
+
+		t.Parallel()
+
+		var builder strings.Builder
+		err := analysis.WriteReport(
+			&builder,
+			analysis.ReportFormatGitHub,
+			"cadence-test",
+			reportTestDiagnostics(t),
+		)
+		require.NoError(t, err)
+
+		require.Equal(t,
+			"::error file=test.cdc,line=5,endLine=5,col=15,endColumn=15,title=C0064"+
+				"::incorrect transfer operation: expected `<-`%0Asuggested fix: replace with `<-`\n"+
+				"::error file=test.cdc,line=8,endLine=8,col=9,endColumn=9,title=C0002"+
+				"::cannot redeclare constant: `x` is already declared%0A7:8: previously declared here\n",
+			builder.String(),
+		)
+	})
+
+	t.Run("escaping", func(t *testing.T) {
+
+		t.Parallel()
+
+		var builder strings.Builder
+		err := analysis.WriteGitHubAnnotations(
+			&builder,
+			[]analysis.Diagnostic{
+				{
+					Location: common.StringLocation("a,b:c.cdc"),
+					Category: "test",
+					Message:  "100% wrong\r\n",
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		require.Equal(t,
+			"::warning file=a%2Cb%3Ac.cdc,title=test::100%25 wrong%0D%0A\n",
+			builder.String(),
+		)
+	})
+}
@@ -0,0 +1,397 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package docgen generates documentation for Cadence programs.
+//
+// The documentation of a program is built from its syntax and its resolved types,
+// and is rendered as Markdown or HTML pages.
+package docgen
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+// Documentation is the documentation of one or more programs
+type Documentation struct {
+	// Declarations are the documented top-level composite and interface declarations
+	Declarations []*Declaration
+	// Functions are the documented top-level functions
+	Functions []*Member
+}
+
+// Declaration is the documentation of a composite or interface declaration,
+// e.g. a contract, resource, struct, event, enum, or attachment
+type Declaration struct {
+	// Parent is the declaration which contains the declaration, if any
+	Parent *Declaration
+	// Kind is the kind of the declaration, e.g. "resource interface"
+	Kind string
+	// Identifier is the name of the declaration
+	Identifier string
+	// QualifiedIdentifier is the name of the declaration, qualified by the names of its parents
+	QualifiedIdentifier string
+	// Signature is the declaration without its members, e.g. "pub resource Vault: Receiver"
+	Signature string
+	// DocString is the documentation comment of the declaration
+	DocString string
+	// Location describes the location of the program which contains the declaration
+	Location    string
+	Fields      []*Member
+	Initializer *Member
+	Functions   []*Member
+	EnumCases   []*Member
+	Events      []*Declaration
+	Nested      []*Declaration
+}
+
+// IsEvent returns true if the declaration is an event declaration
+func (d *Declaration) IsEvent() bool {
+	return d.Kind == common.CompositeKindEvent.Name()
+}
+
+// Member is the documentation of a member of a declaration,
+// i.e. a field, a function, an initializer, or an enum case
+type Member struct {
+	// Identifier is the name of the member
+	Identifier string
+	// Signature is the member without its body, e.g. "pub fun deposit(from: @Vault)"
+	Signature      string
+	DocString      string
+	PreConditions  []Condition
+	PostConditions []Condition
+}
+
+// Condition is the documentation of a pre-condition or post-condition of a function
+type Condition struct {
+	Test    string
+	Message string
+}
+
+// New returns the documentation of the given programs.
+// The programs must have been loaded with type information (analysis.NeedTypes)
+func New(programs ...*analysis.Program) *Documentation {
+	documentation := &Documentation{}
+
+	for _, program := range programs {
+		generator := documentationGenerator{
+			program: program,
+		}
+
+		for _, declaration := range program.Program.Declarations() {
+			switch declaration := declaration.(type) {
+			case ast.CompositeLikeDeclaration, *ast.InterfaceDeclaration:
+				documentation.Declarations = append(
+					documentation.Declarations,
+					generator.declaration(declaration, nil),
+				)
+
+			case *ast.FunctionDeclaration:
+				functionType := program.Elaboration.FunctionDeclarationFunctionType(declaration)
+				documentation.Functions = append(
+					documentation.Functions,
+					generator.function(declaration, functionType),
+				)
+			}
+		}
+	}
+
+	return documentation
+}
+
+// AllDeclarations returns all documented declarations, including nested declarations,
+// sorted by their qualified identifiers
+func (d *Documentation) AllDeclarations() []*Declaration {
+	var declarations []*Declaration
+
+	var add func(declaration *Declaration)
+	add = func(declaration *Declaration) {
+		declarations = append(declarations, declaration)
+		for _, event := range declaration.Events {
+			add(event)
+		}
+		for _, nested := range declaration.Nested {
+			add(nested)
+		}
+	}
+
+	for _, declaration := range d.Declarations {
+		add(declaration)
+	}
+
+	sort.SliceStable(declarations, func(i, j int) bool {
+		return declarations[i].QualifiedIdentifier < declarations[j].QualifiedIdentifier
+	})
+
+	return declarations
+}
+
+type documentationGenerator struct {
+	program *analysis.Program
+}
+
+func (g documentationGenerator) declaration(declaration ast.Declaration, parent *Declaration) *Declaration {
+
+	var members *sema.StringMemberOrderedMap
+	var signature strings.Builder
+	var kind common.DeclarationKind
+	var constructorParameters []sema.Parameter
+
+	writeAccess(&signature, declaration.DeclarationAccess())
+
+	switch declaration := declaration.(type) {
+	case *ast.InterfaceDeclaration:
+		interfaceType := g.program.Elaboration.InterfaceDeclarationType(declaration)
+		members = interfaceType.Members
+		constructorParameters = interfaceType.InitializerParameters
+		kind = declaration.DeclarationKind()
+
+		signature.WriteString(kind.Keywords())
+		signature.WriteByte(' ')
+		signature.WriteString(declaration.Identifier.Identifier)
+
+	case ast.CompositeLikeDeclaration:
+		compositeType := g.program.Elaboration.CompositeDeclarationType(declaration)
+		members = compositeType.Members
+		constructorParameters = compositeType.ConstructorParameters
+		kind = declaration.DeclarationKind()
+
+		signature.WriteString(kind.Keywords())
+		signature.WriteByte(' ')
+		signature.WriteString(declaration.DeclarationIdentifier().Identifier)
+
+		switch compositeType.Kind {
+		case common.CompositeKindEvent:
+			signature.WriteString(parametersSignature(constructorParameters))
+
+		case common.CompositeKindAttachment:
+			signature.WriteString(" for ")
+			signature.WriteString(compositeType.GetBaseType().QualifiedString())
+		}
+
+		var conformances []string
+		if compositeType.EnumRawType != nil {
+			conformances = append(conformances, compositeType.EnumRawType.QualifiedString())
+		}
+		for _, conformance := range compositeType.ExplicitInterfaceConformances {
+			conformances = append(conformances, conformance.QualifiedString())
+		}
+		if len(conformances) > 0 {
+			signature.WriteString(": ")
+			signature.WriteString(strings.Join(conformances, ", "))
+		}
+	}
+
+	identifier := declaration.DeclarationIdentifier().Identifier
+	qualifiedIdentifier := identifier
+	if parent != nil {
+		qualifiedIdentifier = parent.QualifiedIdentifier + "." + identifier
+	}
+
+	result := &Declaration{
+		Parent:              parent,
+		Kind:                kind.Name(),
+		Identifier:          identifier,
+		QualifiedIdentifier: qualifiedIdentifier,
+		Signature:           signature.String(),
+		DocString:           formatDocString(declaration.DeclarationDocString()),
+		Location:            g.program.Location.Description(),
+	}
+
+	for _, member := range declaration.DeclarationMembers().Declarations() {
+		switch member := member.(type) {
+		case *ast.FieldDeclaration:
+			result.Fields = append(result.Fields, g.field(member, members))
+
+		case *ast.FunctionDeclaration:
+			var functionType *sema.FunctionType
+			if semaMember, ok := members.Get(member.Identifier.Identifier); ok {
+				functionType, _ = semaMember.TypeAnnotation.Type.(*sema.FunctionType)
+			}
+			result.Functions = append(result.Functions, g.function(member, functionType))
+
+		case *ast.SpecialFunctionDeclaration:
+			// Event declarations have an implicit initializer,
+			// which is already documented in the signature
+			if member.Kind != common.DeclarationKindInitializer || result.IsEvent() {
+				continue
+			}
+			initializer := g.function(member.FunctionDeclaration, nil)
+			initializer.Identifier = member.Kind.Keywords()
+			initializer.Signature = member.Kind.Keywords() + parametersSignature(constructorParameters)
+			result.Initializer = initializer
+
+		case *ast.EnumCaseDeclaration:
+			result.EnumCases = append(
+				result.EnumCases,
+				&Member{
+					Identifier: member.Identifier.Identifier,
+					Signature:  "case " + member.Identifier.Identifier,
+					DocString:  formatDocString(member.DocString),
+				},
+			)
+
+		case ast.CompositeLikeDeclaration:
+			nested := g.declaration(member, result)
+			if nested.IsEvent() {
+				result.Events = append(result.Events, nested)
+			} else {
+				result.Nested = append(result.Nested, nested)
+			}
+
+		case *ast.InterfaceDeclaration:
+			result.Nested = append(result.Nested, g.declaration(member, result))
+		}
+	}
+
+	return result
+}
+
+func (g documentationGenerator) field(
+	declaration *ast.FieldDeclaration,
+	members *sema.StringMemberOrderedMap,
+) *Member {
+	var signature strings.Builder
+
+	writeAccess(&signature, declaration.Access)
+
+	if declaration.VariableKind != ast.VariableKindNotSpecified {
+		signature.WriteString(declaration.VariableKind.Keyword())
+		signature.WriteByte(' ')
+	}
+
+	identifier := declaration.Identifier.Identifier
+	signature.WriteString(identifier)
+
+	if member, ok := members.Get(identifier); ok {
+		signature.WriteString(": ")
+		signature.WriteString(member.TypeAnnotation.QualifiedString())
+	}
+
+	return &Member{
+		Identifier: identifier,
+		Signature:  signature.String(),
+		DocString:  formatDocString(declaration.DocString),
+	}
+}
+
+func (g documentationGenerator) function(
+	declaration *ast.FunctionDeclaration,
+	functionType *sema.FunctionType,
+) *Member {
+	var signature strings.Builder
+
+	writeAccess(&signature, declaration.Access)
+
+	identifier := declaration.Identifier.Identifier
+	signature.WriteString("fun ")
+	signature.WriteString(identifier)
+
+	if functionType != nil {
+		if len(functionType.TypeParameters) > 0 {
+			typeParameters := make([]string, 0, len(functionType.TypeParameters))
+			for _, typeParameter := range functionType.TypeParameters {
+				typeParameters = append(typeParameters, typeParameter.QualifiedString())
+			}
+			signature.WriteByte('<')
+			signature.WriteString(strings.Join(typeParameters, ", "))
+			signature.WriteByte('>')
+		}
+
+		signature.WriteString(parametersSignature(functionType.Parameters))
+
+		returnType := functionType.ReturnTypeAnnotation.Type
+		if returnType != nil && returnType != sema.VoidType {
+			signature.WriteString(": ")
+			signature.WriteString(functionType.ReturnTypeAnnotation.QualifiedString())
+		}
+	}
+
+	member := &Member{
+		Identifier: identifier,
+		Signature:  signature.String(),
+		DocString:  formatDocString(declaration.DocString),
+	}
+
+	if functionBlock := declaration.FunctionBlock; functionBlock != nil {
+		member.PreConditions = conditions(functionBlock.PreConditions)
+		member.PostConditions = conditions(functionBlock.PostConditions)
+	}
+
+	return member
+}
+
+func conditions(conditions *ast.Conditions) []Condition {
+	if conditions.IsEmpty() {
+		return nil
+	}
+
+	result := make([]Condition, 0, len(*conditions))
+	for _, condition := range *conditions {
+		var message string
+		if condition.Message != nil {
+			message = condition.Message.String()
+		}
+		result = append(
+			result,
+			Condition{
+				Test:    condition.Test.String(),
+				Message: message,
+			},
+		)
+	}
+	return result
+}
+
+func parametersSignature(parameters []sema.Parameter) string {
+	strs := make([]string, 0, len(parameters))
+	for _, parameter := range parameters {
+		strs = append(strs, parameter.QualifiedString())
+	}
+	return "(" + strings.Join(strs, ", ") + ")"
+}
+
+func writeAccess(builder *strings.Builder, access ast.Access) {
+	keyword := access.Keyword()
+	if keyword == "" {
+		return
+	}
+	builder.WriteString(keyword)
+	builder.WriteByte(' ')
+}
+
+// formatDocString removes the indentation and the leading asterisks of block comments
+// from each line of the given documentation comment
+func formatDocString(docString string) string {
+	lines := strings.Split(docString, "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line != "*" {
+			line = strings.TrimPrefix(line, "* ")
+		} else {
+			line = ""
+		}
+		lines[i] = line
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,405 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package docgen_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/tools/analysis"
+	"github.com/onflow/cadence/tools/docgen"
+)
+
+const testContract = `
+/// A token which can be exchanged for goods.
+pub contract Token {
+
+    /// The total number of tokens in existence.
+    pub var totalSupply: UFix64
+
+    /// Emitted when tokens are deposited into a vault.
+    pub event Deposited(amount: UFix64, to: Address?)
+
+    pub enum Color: UInt8 {
+        /// The color red.
+        pub case red
+        pub case green
+    }
+
+    pub resource interface Receiver {
+        /// Deposits the given vault.
+        pub fun deposit(from: @Vault) {
+            pre {
+                from.balance > 0.0: "cannot deposit an empty vault"
+            }
+        }
+    }
+
+    /**
+     * A vault holds tokens.
+     *
+     * Vaults can be nested.
+     */
+    pub resource Vault: Receiver {
+        pub var balance: UFix64
+        access(contract) let ids: {String: [UInt64]}
+
+        init(balance: UFix64) {
+            self.balance = balance
+            self.ids = {}
+        }
+
+        pub fun deposit(from: @Vault) {
+            post {
+                self.balance == before(self.balance) + before(from.balance)
+            }
+            self.balance = self.balance + from.balance
+            destroy from
+        }
+
+        pub fun withdraw(amount: UFix64): @Vault {
+            self.balance = self.balance - amount
+            return <-create Vault(balance: amount)
+        }
+
+        destroy() {}
+    }
+
+    init() {
+        self.totalSupply = 0.0
+    }
+}
+`
+
+func loadDocumentation(t *testing.T, code string) *docgen.Documentation {
+	location := common.StringLocation("Token.cdc")
+
+	config := analysis.NewSimpleConfig(
+		analysis.NeedTypes,
+		map[common.Location][]byte{
+			location: []byte(code),
+		},
+		nil,
+		nil,
+	)
+
+	programs, err := analysis.Load(config, location)
+	require.NoError(t, err)
+
+	return docgen.New(programs[location])
+}
+
+func pageContents(t *testing.T, documentation *docgen.Documentation, format docgen.Format) map[string]string {
+	pages, err := documentation.Pages(format)
+	require.NoError(t, err)
+
+	contents := map[string]string{}
+	for _, page := range pages {
+		contents[page.Name] = string(page.Content)
+	}
+	return contents
+}
+
+func TestDocumentation(t *testing.T) {
+
+	t.Parallel()
+
+	documentation := loadDocumentation(t, testContract)
+
+	declarations := documentation.AllDeclarations()
+	identifiers := make([]string, 0, len(declarations))
+	for _, declaration := range declarations {
+		identifiers = append(identifiers, declaration.QualifiedIdentifier)
+	}
+
+	require.Equal(t,
+		[]string{
+			"Token",
+			"Token.Color",
+			"Token.Deposited",
+			"Token.Receiver",
+			"Token.Vault",
+		},
+		identifiers,
+	)
+}
+
+func TestDocumentationMarkdown(t *testing.T) {
+
+	t.Parallel()
+
+	documentation := loadDocumentation(t, testContract)
+
+	contents := pageContents(t, documentation, docgen.FormatMarkdown)
+
+	require.Len(t, contents, 6)
+
+	require.Equal(t,
+		"# Documentation\n"+
+			"\n"+
+			"## Declarations\n"+
+			"\n"+
+			"- [`Token`](Token.md) (contract)\n"+
+			"- [`Token.Color`](Token.Color.md) (enum)\n"+
+			"- [`Token.Deposited`](Token.Deposited.md) (event)\n"+
+			"- [`Token.Receiver`](Token.Receiver.md) (resource interface)\n"+
+			"- [`Token.Vault`](Token.Vault.md) (resource)\n",
+		contents[docgen.IndexPageName],
+	)
+
+	require.Equal(t,
+		"# Contract `Token`\n"+
+			"\n"+
+			"```cadence\n"+
+			"pub contract Token\n"+
+			"```\n"+
+			"\n"+
+			"A token which can be exchanged for goods.\n"+
+			"\n"+
+			"Defined in Token.cdc.\n"+
+			"\n"+
+			"## Fields\n"+
+			"\n"+
+			"### `totalSupply`\n"+
+			"\n"+
+			"```cadence\n"+
+			"pub var totalSupply: UFix64\n"+
+			"```\n"+
+			"\n"+
+			"The total number of tokens in existence.\n"+
+			"\n"+
+			"## Initializer\n"+
+			"\n"+
+			"### `init`\n"+
+			"\n"+
+			"```cadence\n"+
+			"init()\n"+
+			"```\n"+
+			"\n"+
+			"## Events\n"+
+			"\n"+
+			"### [`Deposited`](Token.Deposited.md)\n"+
+			"\n"+
+			"```cadence\n"+
+			"pub event Deposited(amount: UFix64, to: Address?)\n"+
+			"```\n"+
+			"\n"+
+			"Emitted when tokens are deposited into a vault.\n"+
+			"\n"+
+			"## Nested Declarations\n"+
+			"\n"+
+			"- [`Color`](Token.Color.md) (enum)\n"+
+			"- [`Receiver`](Token.Receiver.md) (resource interface)\n"+
+			"- [`Vault`](Token.Vault.md) (resource)\n",
+		contents["Token"],
+	)
+
+	require.Equal(t,
+		"# Resource interface `Token.Receiver`\n"+
+			"\n"+
+			"```cadence\n"+
+			"pub resource interface Receiver\n"+
+			"```\n"+
+			"\n"+
+			"Defined in [`Token`](Token.md).\n"+
+			"\n"+
+			"## Functions\n"+
+			"\n"+
+			"### `deposit`\n"+
+			"\n"+
+			"```cadence\n"+
+			"pub fun deposit(from: @Token.Vault)\n"+
+			"```\n"+
+			"\n"+
+			"Deposits the given vault.\n"+
+			"\n"+
+			"Pre-conditions:\n"+
+			"\n"+
+			"- `from.balance > 0.0`: \"cannot deposit an empty vault\"\n",
+		contents["Token.Receiver"],
+	)
+
+	require.Equal(t,
+		"# Resource `Token.Vault`\n"+
+			"\n"+
+			"```cadence\n"+
+			"pub resource Vault: Token.Receiver\n"+
+			"```\n"+
+			"\n"+
+			"A vault holds tokens.\n"+
+			"\n"+
+			"Vaults can be nested.\n"+
+			"\n"+
+			"Defined in [`Token`](Token.md).\n"+
+			"\n"+
+			"## Fields\n"+
+			"\n"+
+			"### `balance`\n"+
+			"\n"+
+			"```cadence\n"+
+			"pub var balance: UFix64\n"+
+			"```\n"+
+			"\n"+
+			"### `ids`\n"+
+			"\n"+
+			"```cadence\n"+
+			"access(contract) let ids: {String: [UInt64]}\n"+
+			"```\n"+
+			"\n"+
+			"## Initializer\n"+
+			"\n"+
+			"### `init`\n"+
+			"\n"+
+			"```cadence\n"+
+			"init(balance: UFix64)\n"+
+			"```\n"+
+			"\n"+
+			"## Functions\n"+
+			"\n"+
+			"### `deposit`\n"+
+			"\n"+
+			"```cadence\n"+
+			"pub fun deposit(from: @Token.Vault)\n"+
+			"```\n"+
+			"\n"+
+			"Post-conditions:\n"+
+			"\n"+
+			"- `self.balance == before(self.balance) + before(from.balance)`\n"+
+			"\n"+
+			"### `withdraw`\n"+
+			"\n"+
+			"```cadence\n"+
+			"pub fun withdraw(amount: UFix64): @Token.Vault\n"+
+			"```\n",
+		contents["Token.Vault"],
+	)
+
+	require.Equal(t,
+		"# Enum `Token.Color`\n"+
+			"\n"+
+			"```cadence\n"+
+			"pub enum Color: UInt8\n"+
+			"```\n"+
+			"\n"+
+			"Defined in [`Token`](Token.md).\n"+
+			"\n"+
+			"## Enum Cases\n"+
+			"\n"+
+			"### `red`\n"+
+			"\n"+
+			"```cadence\n"+
+			"case red\n"+
+			"```\n"+
+			"\n"+
+			"The color red.\n"+
+			"\n"+
+			"### `green`\n"+
+			"\n"+
+			"```cadence\n"+
+			"case green\n"+
+			"```\n",
+		contents["Token.Color"],
+	)
+}
+
+func TestDocumentationHTML(t *testing.T) {
+
+	t.Parallel()
+
+	documentation := loadDocumentation(t, testContract)
+
+	contents := pageContents(t, documentation, docgen.FormatHTML)
+
+	require.Len(t, contents, 6)
+
+	index := contents[docgen.IndexPageName]
+	require.Contains(t, index, "<title>Documentation</title>")
+	require.Contains(t, index,
+		`<li><a href="Token.Vault.html"><code>Token.Vault</code></a> (resource)</li>`,
+	)
+
+	receiver := contents["Token.Receiver"]
+	require.Contains(t, receiver,
+		`<pre><code>pub fun deposit(from: @Token.Vault)</code></pre>`,
+	)
+	require.Contains(t, receiver,
+		`<li><code>from.balance &gt; 0.0</code>: &#34;cannot deposit an empty vault&#34;</li>`,
+	)
+
+	vault := contents["Token.Vault"]
+	require.Contains(t, vault,
+		"<p>A vault holds tokens.</p>\n<p>Vaults can be nested.</p>",
+	)
+	require.Contains(t, vault,
+		`<p>Defined in <a href="Token.html"><code>Token</code></a>.</p>`,
+	)
+}
+
+func TestDocumentationFunctions(t *testing.T) {
+
+	t.Parallel()
+
+	documentation := loadDocumentation(t, `
+      /// Returns the sum of the given integers.
+      pub fun add(_ a: Int, to b: Int): Int {
+          pre {
+              a >= 0
+          }
+          return a + b
+      }
+    `)
+
+	require.Empty(t, documentation.Declarations)
+	require.Equal(t,
+		[]*docgen.Member{
+			{
+				Identifier: "add",
+				Signature:  "pub fun add(_ a: Int, to b: Int): Int",
+				DocString:  "Returns the sum of the given integers.",
+				PreConditions: []docgen.Condition{
+					{Test: "a >= 0"},
+				},
+			},
+		},
+		documentation.Functions,
+	)
+
+	contents := pageContents(t, documentation, docgen.FormatMarkdown)
+
+	require.Equal(t,
+		"# Documentation\n"+
+			"\n"+
+			"## Functions\n"+
+			"\n"+
+			"### `add`\n"+
+			"\n"+
+			"```cadence\n"+
+			"pub fun add(_ a: Int, to b: Int): Int\n"+
+			"```\n"+
+			"\n"+
+			"Returns the sum of the given integers.\n"+
+			"\n"+
+			"Pre-conditions:\n"+
+			"\n"+
+			"- `a >= 0`\n",
+		contents[docgen.IndexPageName],
+	)
+}
@@ -0,0 +1,183 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package docgen
+
+import (
+	"bytes"
+	"embed"
+	htmlTemplate "html/template"
+	"io"
+	"regexp"
+	"strings"
+	textTemplate "text/template"
+)
+
+// Format is an output format of the documentation
+type Format uint8
+
+const (
+	FormatMarkdown Format = iota
+	FormatHTML
+)
+
+// FileExtension returns the file extension of pages in the format
+func (f Format) FileExtension() string {
+	switch f {
+	case FormatMarkdown:
+		return ".md"
+	case FormatHTML:
+		return ".html"
+	}
+
+	panic("unsupported format")
+}
+
+// IndexPageName is the name of the page which lists all documented declarations
+const IndexPageName = "index"
+
+// Page is a rendered documentation page
+type Page struct {
+	// Name is the name of the page, without file extension
+	Name    string
+	Content []byte
+}
+
+//go:embed templates
+var templateFiles embed.FS
+
+var templateFunctions = map[string]interface{}{
+	"pageName": pageName,
+	"paragraphs": func(text string) []string {
+		return strings.Split(text, "\n\n")
+	},
+	"title": func(text string) string {
+		if text == "" {
+			return text
+		}
+		return strings.ToUpper(text[:1]) + text[1:]
+	},
+}
+
+var blankLinesRegexp = regexp.MustCompile(`\n(\s*\n)+`)
+
+// normalizeMarkdown removes redundant blank lines,
+// which are left behind by the template actions
+func normalizeMarkdown(content []byte) []byte {
+	content = blankLinesRegexp.ReplaceAll(content, []byte("\n\n"))
+	content = bytes.TrimSpace(content)
+	return append(content, '\n')
+}
+
+var markdownTemplate = textTemplate.Must(
+	textTemplate.New("").
+		Funcs(templateFunctions).
+		ParseFS(templateFiles, "templates/*.md.tmpl"),
+)
+
+var htmlTemplates = htmlTemplate.Must(
+	htmlTemplate.New("").
+		Funcs(templateFunctions).
+		ParseFS(templateFiles, "templates/*.html.tmpl"),
+)
+
+type executableTemplate interface {
+	ExecuteTemplate(writer io.Writer, name string, data interface{}) error
+}
+
+func (f Format) template() executableTemplate {
+	switch f {
+	case FormatMarkdown:
+		return markdownTemplate
+	case FormatHTML:
+		return htmlTemplates
+	}
+
+	panic("unsupported format")
+}
+
+// Pages renders the documentation in the given format.
+// The result contains one page for each declaration, and an index page
+func (d *Documentation) Pages(format Format) ([]Page, error) {
+	template := format.template()
+	extension := format.FileExtension()
+
+	render := func(name string, data interface{}) (Page, error) {
+		var buffer bytes.Buffer
+		err := template.ExecuteTemplate(&buffer, name+extension+".tmpl", data)
+		if err != nil {
+			return Page{}, err
+		}
+		content := buffer.Bytes()
+		if format == FormatMarkdown {
+			content = normalizeMarkdown(content)
+		} else {
+			content = append(bytes.TrimSpace(content), '\n')
+		}
+		return Page{
+			Content: content,
+		}, nil
+	}
+
+	declarations := d.AllDeclarations()
+
+	pages := make([]Page, 0, len(declarations)+1)
+
+	index, err := render(
+		"index",
+		struct {
+			*Documentation
+			AllDeclarations []*Declaration
+			Extension       string
+		}{
+			Documentation:   d,
+			AllDeclarations: declarations,
+			Extension:       extension,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	index.Name = IndexPageName
+	pages = append(pages, index)
+
+	for _, declaration := range declarations {
+		page, err := render(
+			"declaration",
+			struct {
+				*Declaration
+				Extension string
+			}{
+				Declaration: declaration,
+				Extension:   extension,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+		page.Name = pageName(declaration)
+		pages = append(pages, page)
+	}
+
+	return pages, nil
+}
+
+// pageName returns the name of the page for the given declaration
+func pageName(declaration *Declaration) string {
+	return declaration.QualifiedIdentifier
+}
@@ -0,0 +1,114 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const AccountAccessRule = "account-access-in-attachment-or-interface"
+
+// AccountAccessAnalyzer reports accesses of `AuthAccount` fields, e.g. `self.account`,
+// in attachments and interfaces.
+// Attachments can be used by anyone who owns a resource of the base type,
+// and default functions of interfaces are run by all implementations
+var AccountAccessAnalyzer = &analysis.Analyzer{
+	Description: "Reports uses of `self.account` and other `AuthAccount` fields in attachments and interfaces",
+	Requires: []*analysis.Analyzer{
+		analysis.InspectorAnalyzer,
+	},
+	Run: func(pass *analysis.Pass) interface{} {
+		program := pass.Program
+		elaboration := program.Elaboration
+		inspector := pass.ResultOf[analysis.InspectorAnalyzer].(*ast.Inspector)
+
+		inspector.WithStack(
+			[]ast.Element{
+				(*ast.MemberExpression)(nil),
+			},
+			func(element ast.Element, push bool, stack []ast.Element) bool {
+				if !push {
+					return true
+				}
+
+				expression := element.(*ast.MemberExpression)
+
+				memberInfo, ok := elaboration.MemberExpressionMemberInfo(expression)
+				if !ok ||
+					memberInfo.Member == nil ||
+					memberInfo.Member.DeclarationKind != common.DeclarationKindField ||
+					!isAuthAccountType(memberInfo.Member.TypeAnnotation.Type) {
+
+					return true
+				}
+
+				// Find the innermost enclosing attachment or interface, if any
+
+				var reason string
+				var declaration ast.Declaration
+
+			Stack:
+				for i := len(stack) - 1; i >= 0; i-- {
+					switch enclosing := stack[i].(type) {
+					case *ast.AttachmentDeclaration:
+						declaration = enclosing
+						reason = "which can be used by anyone who owns a base resource"
+						break Stack
+
+					case *ast.InterfaceDeclaration:
+						declaration = enclosing
+						reason = "the code of which is run by all implementations"
+						break Stack
+					}
+				}
+
+				if declaration == nil {
+					return true
+				}
+
+				pass.Report(
+					analysis.Diagnostic{
+						Location: program.Location,
+						Category: AccountAccessRule,
+						Message: fmt.Sprintf(
+							"`%s` is accessed in %s `%s`, %s",
+							expression.String(),
+							declaration.DeclarationKind().Name(),
+							declaration.DeclarationIdentifier().Identifier,
+							reason,
+						),
+						Range: ast.NewUnmeteredRangeFromPositioned(expression),
+					},
+				)
+
+				return true
+			},
+		)
+
+		return nil
+	},
+}
+
+func init() {
+	registerSecurityAnalyzer(AccountAccessRule, AccountAccessAnalyzer)
+}
@@ -0,0 +1,88 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/tools/lint"
+)
+
+func TestAccountAccessAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("interface", func(t *testing.T) {
+
+		t.Parallel()
+
+		diagnostics := testLint(t,
+			`
+              pub contract interface I {
+
+                  pub fun address(): Address {
+                      return self.account.address
+                  }
+              }
+            `,
+			lint.AccountAccessRule,
+		)
+
+		require.Equal(t,
+			[]string{
+				"5:29: `self.account` is accessed in contract interface `I`, the code of which is run by all implementations",
+			},
+			summarize(diagnostics),
+		)
+	})
+
+	t.Run("attachment", func(t *testing.T) {
+
+		t.Parallel()
+
+		diagnostics := testLint(t,
+			`
+              pub contract C {
+
+                  pub resource R {}
+
+                  pub attachment A for R {
+                      pub fun address(): Address {
+                          return C.account.address
+                      }
+                  }
+
+                  pub fun address(): Address {
+                      return self.account.address
+                  }
+              }
+            `,
+			lint.AccountAccessRule,
+		)
+
+		require.Equal(t,
+			[]string{
+				"8:33: `C.account` is accessed in attachment `A`, which can be used by anyone who owns a base resource",
+			},
+			summarize(diagnostics),
+		)
+	})
+}
@@ -0,0 +1,108 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const PublicAuthAccountParameterRule = "public-auth-account-parameter"
+
+// PublicAuthAccountParameterAnalyzer reports public functions of composites and interfaces
+// which have `AuthAccount` parameters.
+// Callers must pass their account, which gives the function full access to it
+var PublicAuthAccountParameterAnalyzer = &analysis.Analyzer{
+	Description: "Reports public functions which accept `AuthAccount` parameters",
+	Requires: []*analysis.Analyzer{
+		analysis.InspectorAnalyzer,
+	},
+	Run: func(pass *analysis.Pass) interface{} {
+		program := pass.Program
+		elaboration := program.Elaboration
+		inspector := pass.ResultOf[analysis.InspectorAnalyzer].(*ast.Inspector)
+
+		inspector.Preorder(
+			[]ast.Element{
+				(*ast.CompositeDeclaration)(nil),
+				(*ast.AttachmentDeclaration)(nil),
+				(*ast.InterfaceDeclaration)(nil),
+			},
+			func(element ast.Element) {
+				declaration := element.(ast.Declaration)
+
+				for _, function := range declaration.DeclarationMembers().Functions() {
+					if !isPublicAccess(function.Access) {
+						continue
+					}
+
+					functionType := elaboration.FunctionDeclarationFunctionType(function)
+					if functionType == nil {
+						continue
+					}
+
+					for i, parameter := range functionType.Parameters {
+						if !isAuthAccountType(parameter.TypeAnnotation.Type) {
+							continue
+						}
+
+						astParameter := function.ParameterList.Parameters[i]
+
+						pass.Report(
+							analysis.Diagnostic{
+								Location: program.Location,
+								Category: PublicAuthAccountParameterRule,
+								Message: fmt.Sprintf(
+									"public function `%s` accepts parameter `%s` of type `%s`, which gives it full access to the caller's account",
+									function.Identifier.Identifier,
+									parameter.Identifier,
+									parameter.TypeAnnotation.Type.QualifiedString(),
+								),
+								Range: ast.NewUnmeteredRangeFromPositioned(astParameter.Identifier),
+							},
+						)
+					}
+				}
+			},
+		)
+
+		return nil
+	},
+}
+
+func init() {
+	registerSecurityAnalyzer(PublicAuthAccountParameterRule, PublicAuthAccountParameterAnalyzer)
+}
+
+// isAuthAccountType returns true if the given type is `AuthAccount`,
+// or an optional of or a reference to `AuthAccount`
+func isAuthAccountType(ty sema.Type) bool {
+	switch ty := ty.(type) {
+	case *sema.OptionalType:
+		return isAuthAccountType(ty.Type)
+
+	case *sema.ReferenceType:
+		return isAuthAccountType(ty.Type)
+	}
+
+	return ty.Equal(sema.AuthAccountType)
+}
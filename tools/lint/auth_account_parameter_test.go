@@ -0,0 +1,63 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/tools/lint"
+)
+
+func TestPublicAuthAccountParameterAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := testLint(t,
+		`
+          pub contract C {
+
+              pub fun setup(account: AuthAccount) {}
+
+              pub fun setupOptional(_ account: &AuthAccount?, name: String) {}
+
+              access(contract) fun internal(account: AuthAccount) {}
+
+              pub fun address(account: PublicAccount) {}
+
+              pub resource interface I {
+                  pub fun configure(account: AuthAccount)
+              }
+
+              init(account: AuthAccount) {}
+          }
+        `,
+		lint.PublicAuthAccountParameterRule,
+	)
+
+	require.Equal(t,
+		[]string{
+			"4:28: public function `setup` accepts parameter `account` of type `AuthAccount`, which gives it full access to the caller's account",
+			"6:38: public function `setupOptional` accepts parameter `account` of type `&AuthAccount?`, which gives it full access to the caller's account",
+			"13:36: public function `configure` accepts parameter `account` of type `AuthAccount`, which gives it full access to the caller's account",
+		},
+		summarize(diagnostics),
+	)
+}
@@ -0,0 +1,103 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const CapabilityExposesWithdrawRule = "capability-exposes-withdraw"
+
+// CapabilityExposesWithdrawAnalyzer reports public capabilities which have a restricted borrow type,
+// where the restrictions still expose withdraw-style functions, e.g. `&Vault{Provider}`
+var CapabilityExposesWithdrawAnalyzer = &analysis.Analyzer{
+	Description: "Reports public capabilities with restricted types which expose withdraw-style functions",
+	Requires: []*analysis.Analyzer{
+		analysis.InspectorAnalyzer,
+	},
+	Run: func(pass *analysis.Pass) interface{} {
+		program := pass.Program
+		inspector := pass.ResultOf[analysis.InspectorAnalyzer].(*ast.Inspector)
+
+		for _, publication := range capabilityPublications(program, inspector) {
+			referenceType, ok := publication.borrowType.(*sema.ReferenceType)
+			if !ok {
+				continue
+			}
+
+			restrictedType, ok := referenceType.Type.(*sema.RestrictedType)
+			if !ok {
+				continue
+			}
+
+			memberSets := make([]map[string]sema.MemberResolver, 0, len(restrictedType.Restrictions))
+			for _, restriction := range restrictedType.Restrictions {
+				memberSets = append(memberSets, restriction.GetMembers())
+			}
+
+			functions := withdrawFunctions(memberSets...)
+			if len(functions) == 0 {
+				continue
+			}
+
+			pass.Report(
+				analysis.Diagnostic{
+					Location: program.Location,
+					Category: CapabilityExposesWithdrawRule,
+					Message: fmt.Sprintf(
+						"public capability of type `%s` exposes %s",
+						referenceType.QualifiedString(),
+						formatFunctionNames(functions),
+					),
+					Range: ast.NewUnmeteredRangeFromPositioned(publication.invocation),
+				},
+			)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	registerSecurityAnalyzer(CapabilityExposesWithdrawRule, CapabilityExposesWithdrawAnalyzer)
+}
+
+// formatFunctionNames returns the given function names as a list of code spans,
+// e.g. "function `withdraw`" or "functions `withdraw` and `withdrawAll`"
+func formatFunctionNames(names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, fmt.Sprintf("`%s`", name))
+	}
+
+	if len(quoted) == 1 {
+		return "function " + quoted[0]
+	}
+
+	return fmt.Sprintf(
+		"functions %s and %s",
+		strings.Join(quoted[:len(quoted)-1], ", "),
+		quoted[len(quoted)-1],
+	)
+}
@@ -0,0 +1,90 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/tools/lint"
+)
+
+const testVaultCode = `
+  pub resource interface Provider {
+      pub fun withdraw(amount: UFix64): @Vault
+  }
+
+  pub resource interface Receiver {
+      pub fun deposit(from: @Vault)
+  }
+
+  pub resource interface Balance {
+      pub var balance: UFix64
+  }
+
+  pub resource Vault: Provider, Receiver, Balance {
+      pub var balance: UFix64
+
+      init() {
+          self.balance = 0.0
+      }
+
+      pub fun withdraw(amount: UFix64): @Vault {
+          return <-create Vault()
+      }
+
+      pub fun deposit(from: @Vault) {
+          destroy from
+      }
+  }
+`
+
+func TestCapabilityExposesWithdrawAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := testLint(t,
+		testVaultCode+`
+          transaction {
+              prepare(signer: AuthAccount) {
+                  signer.save(<-create Vault(), to: /storage/vault)
+
+                  signer.link<&Vault{Receiver, Balance}>(/public/receiver, target: /storage/vault)
+                  signer.link<&Vault{Provider}>(/public/provider, target: /storage/vault)
+                  signer.link<&Vault{Provider, Receiver}>(/private/provider, target: /storage/vault)
+
+                  let cap = signer.capabilities.storage.issue<&{Provider, Balance}>(/storage/vault)
+                  signer.capabilities.publish(cap, at: /public/issued)
+                  signer.inbox.publish(cap, name: "provider", recipient: 0x1)
+              }
+          }
+        `,
+		lint.CapabilityExposesWithdrawRule,
+	)
+
+	require.Equal(t,
+		[]string{
+			"35:18: public capability of type `&Vault{Provider}` exposes function `withdraw`",
+			"39:18: public capability of type `&AnyResource{Provider, Balance}` exposes function `withdraw`",
+			"40:18: public capability of type `&AnyResource{Provider, Balance}` exposes function `withdraw`",
+		},
+		summarize(diagnostics),
+	)
+}
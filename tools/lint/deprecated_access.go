@@ -0,0 +1,124 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const DeprecatedAccessKeywordRule = "deprecated-access-keyword"
+
+// deprecatedAccessKeywords maps the deprecated access keywords to their replacements
+var deprecatedAccessKeywords = map[ast.Access]struct {
+	keyword     string
+	replacement string
+}{
+	ast.AccessPublic: {
+		keyword:     "pub",
+		replacement: "access(all)",
+	},
+	ast.AccessPrivate: {
+		keyword:     "priv",
+		replacement: "access(self)",
+	},
+}
+
+// DeprecatedAccessKeywordAnalyzer reports declarations which use the deprecated access keywords
+// `pub` and `priv`, instead of `access(all)` and `access(self)`
+var DeprecatedAccessKeywordAnalyzer = &analysis.Analyzer{
+	Description: "Reports uses of the deprecated access keywords `pub` and `priv`",
+	Requires: []*analysis.Analyzer{
+		analysis.InspectorAnalyzer,
+	},
+	Run: func(pass *analysis.Pass) interface{} {
+		program := pass.Program
+		code := program.Code
+		inspector := pass.ResultOf[analysis.InspectorAnalyzer].(*ast.Inspector)
+
+		inspector.Preorder(
+			nil,
+			func(element ast.Element) {
+				declaration, ok := element.(ast.Declaration)
+				if !ok {
+					return
+				}
+
+				deprecated, ok := deprecatedAccessKeywords[declaration.DeclarationAccess()]
+				if !ok {
+					return
+				}
+
+				// The access modifier is the start of the declaration.
+				// The keyword might also be the start of another modifier, e.g. `pub(set)`,
+				// or the declaration might have been written with the new keyword,
+				// so ensure it is the deprecated keyword
+
+				startPos := declaration.StartPosition()
+				keyword := []byte(deprecated.keyword)
+				endOffset := startPos.Offset + len(keyword)
+				if endOffset >= len(code) ||
+					!bytes.Equal(code[startPos.Offset:endOffset], keyword) ||
+					isIdentifierCharacter(code[endOffset]) ||
+					code[endOffset] == '(' {
+
+					return
+				}
+
+				keywordRange := ast.NewUnmeteredRange(
+					startPos,
+					startPos.Shifted(nil, len(keyword)-1),
+				)
+
+				pass.Report(
+					analysis.Diagnostic{
+						Location: program.Location,
+						Category: DeprecatedAccessKeywordRule,
+						Message: fmt.Sprintf(
+							"access keyword `%s` is deprecated, use `%s` instead",
+							deprecated.keyword,
+							deprecated.replacement,
+						),
+						Range: keywordRange,
+						SuggestedFixes: []analysis.SuggestedFix{
+							{
+								Message: fmt.Sprintf("replace with `%s`", deprecated.replacement),
+								TextEdits: []analysis.TextEdit{
+									{
+										Replacement: deprecated.replacement,
+										Range:       keywordRange,
+									},
+								},
+							},
+						},
+					},
+				)
+			},
+		)
+
+		return nil
+	},
+}
+
+func init() {
+	RegisterAnalyzer(DeprecatedAccessKeywordRule, DeprecatedAccessKeywordAnalyzer)
+}
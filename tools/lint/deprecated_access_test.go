@@ -0,0 +1,70 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/tools/lint"
+)
+
+func TestDeprecatedAccessKeywordAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := testLint(t,
+		`
+          pub contract C {
+              pub(set) var a: Int
+              priv let b: Int
+              access(all) let c: Int
+              access(self) let d: Int
+              access(contract) let e: Int
+
+              pub enum E: UInt8 {
+                  pub case x
+              }
+
+              init() {
+                  self.a = 1
+                  self.b = 2
+                  self.c = 3
+                  self.d = 4
+                  self.e = 5
+              }
+
+              pub fun test() {}
+          }
+        `,
+		lint.DeprecatedAccessKeywordRule,
+	)
+
+	require.Equal(t,
+		[]string{
+			"2:10: access keyword `pub` is deprecated, use `access(all)` instead",
+			"4:14: access keyword `priv` is deprecated, use `access(self)` instead",
+			"9:14: access keyword `pub` is deprecated, use `access(all)` instead",
+			"10:18: access keyword `pub` is deprecated, use `access(all)` instead",
+			"21:14: access keyword `pub` is deprecated, use `access(all)` instead",
+		},
+		summarize(diagnostics),
+	)
+}
@@ -0,0 +1,185 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/parser/lexer"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const EmptyConditionsRule = "empty-conditions"
+
+// EmptyConditionsAnalyzer reports empty `pre` and `post` blocks
+// of functions and transactions
+var EmptyConditionsAnalyzer = &analysis.Analyzer{
+	Description: "Reports empty `pre` and `post` blocks",
+	Requires: []*analysis.Analyzer{
+		analysis.InspectorAnalyzer,
+	},
+	Run: func(pass *analysis.Pass) interface{} {
+		program := pass.Program
+		inspector := pass.ResultOf[analysis.InspectorAnalyzer].(*ast.Inspector)
+
+		// The conditions have no positions in the AST,
+		// so the positions of the keywords are determined from the tokens
+		tokens := significantTokens(program.Code)
+
+		check := func(
+			preConditions *ast.Conditions,
+			postConditions *ast.Conditions,
+			startPos ast.Position,
+			endPos ast.Position,
+		) {
+			checkPre := preConditions != nil && len(*preConditions) == 0
+			checkPost := postConditions != nil && len(*postConditions) == 0
+			if !checkPre && !checkPost {
+				return
+			}
+
+			for _, keyword := range emptyConditionKeywords(program.Code, tokens, startPos, endPos) {
+				source := string(keyword.Source(program.Code))
+				if (source == "pre" && !checkPre) || (source == "post" && !checkPost) {
+					continue
+				}
+
+				pass.Report(
+					analysis.Diagnostic{
+						Location: program.Location,
+						Category: EmptyConditionsRule,
+						Message:  fmt.Sprintf("empty `%s` block", source),
+						Range:    keyword.Range,
+					},
+				)
+			}
+		}
+
+		inspector.Preorder(
+			[]ast.Element{
+				(*ast.FunctionBlock)(nil),
+				(*ast.TransactionDeclaration)(nil),
+			},
+			func(element ast.Element) {
+				switch element := element.(type) {
+				case *ast.FunctionBlock:
+					endPos := element.Block.EndPos
+					if len(element.Block.Statements) > 0 {
+						endPos = element.Block.Statements[0].StartPosition()
+					}
+					check(
+						element.PreConditions,
+						element.PostConditions,
+						element.Block.StartPos,
+						endPos,
+					)
+
+				case *ast.TransactionDeclaration:
+					check(
+						element.PreConditions,
+						element.PostConditions,
+						element.StartPos,
+						element.EndPos,
+					)
+				}
+			},
+		)
+
+		return nil
+	},
+}
+
+func init() {
+	RegisterAnalyzer(EmptyConditionsRule, EmptyConditionsAnalyzer)
+}
+
+// significantTokens returns the tokens of the given code, without whitespace and comments
+func significantTokens(code []byte) []lexer.Token {
+	tokenStream := lexer.Lex(code, nil)
+	defer tokenStream.Reclaim()
+
+	var tokens []lexer.Token
+	for {
+		token := tokenStream.Next()
+		switch token.Type {
+		case lexer.TokenEOF:
+			return tokens
+
+		case lexer.TokenSpace,
+			lexer.TokenLineComment,
+			lexer.TokenBlockCommentStart,
+			lexer.TokenBlockCommentContent,
+			lexer.TokenBlockCommentEnd:
+
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+}
+
+// emptyConditionKeywords returns the `pre` and `post` keywords in the given range,
+// which are directly nested in the first block of the range and followed by an empty block
+func emptyConditionKeywords(
+	code []byte,
+	tokens []lexer.Token,
+	startPos ast.Position,
+	endPos ast.Position,
+) []lexer.Token {
+	var keywords []lexer.Token
+
+	depth := 0
+	for i, token := range tokens {
+		if token.StartPos.Offset < startPos.Offset {
+			continue
+		}
+		if token.StartPos.Offset >= endPos.Offset {
+			break
+		}
+
+		switch token.Type {
+		case lexer.TokenBraceOpen:
+			depth++
+
+		case lexer.TokenBraceClose:
+			depth--
+			if depth == 0 {
+				return keywords
+			}
+
+		case lexer.TokenIdentifier:
+			if depth != 1 || i+2 >= len(tokens) {
+				continue
+			}
+
+			source := string(token.Source(code))
+			if source != "pre" && source != "post" {
+				continue
+			}
+
+			if tokens[i+1].Is(lexer.TokenBraceOpen) &&
+				tokens[i+2].Is(lexer.TokenBraceClose) {
+
+				keywords = append(keywords, token)
+			}
+		}
+	}
+
+	return keywords
+}
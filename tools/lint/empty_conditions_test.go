@@ -0,0 +1,100 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/tools/lint"
+)
+
+func TestEmptyConditionsAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("functions", func(t *testing.T) {
+
+		t.Parallel()
+
+		diagnostics := testLint(t,
+			`
+              access(all) fun test(x: Int) {
+                  pre {}
+                  post {
+                      x > 0
+                  }
+              }
+
+              access(all) fun test2(x: Int) {
+                  pre {
+                      x > 0
+                  }
+                  post {
+                      // TODO
+                  }
+                  let f = fun () {
+                      pre {}
+                  }
+              }
+
+              access(all) fun test3() {
+                  let pre = 1
+              }
+            `,
+			lint.EmptyConditionsRule,
+		)
+
+		require.Equal(t,
+			[]string{
+				"3:18: empty `pre` block",
+				"13:18: empty `post` block",
+				"17:22: empty `pre` block",
+			},
+			summarize(diagnostics),
+		)
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+
+		t.Parallel()
+
+		diagnostics := testLint(t,
+			`
+              transaction {
+                  prepare(signer: AuthAccount) {}
+                  pre {}
+                  execute {}
+                  post {
+                      true
+                  }
+              }
+            `,
+			lint.EmptyConditionsRule,
+		)
+
+		require.Equal(t,
+			[]string{
+				"4:18: empty `pre` block",
+			},
+			summarize(diagnostics),
+		)
+	})
+}
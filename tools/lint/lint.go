@@ -0,0 +1,156 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package lint provides a standard set of analyzers for Cadence programs,
+// and runs them on programs loaded with package analysis.
+//
+// Each analyzer implements a rule, which is identified by its name.
+// Diagnostics reported by an analyzer have the name of the rule as their category.
+// Rules can be enabled and disabled using a Config,
+// and diagnostics can be suppressed inline using `// lint-disable` comments
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+// LoadMode is the load mode programs must be loaded with to be linted
+const LoadMode = analysis.NeedTypes |
+	analysis.NeedPositionInfo |
+	analysis.NeedExtendedElaboration
+
+var analyzers = map[string]*analysis.Analyzer{}
+
+// RegisterAnalyzer registers the analyzer for the rule with the given name
+func RegisterAnalyzer(name string, analyzer *analysis.Analyzer) {
+	if _, ok := analyzers[name]; ok {
+		panic(fmt.Errorf("duplicate analyzer for rule: %s", name))
+	}
+	analyzers[name] = analyzer
+}
+
+// Analyzer returns the analyzer for the rule with the given name, if any
+func Analyzer(name string) (*analysis.Analyzer, bool) {
+	analyzer, ok := analyzers[name]
+	return analyzer, ok
+}
+
+// Rules returns the names of all rules, sorted
+func Rules() []string {
+	names := make([]string, 0, len(analyzers))
+	for name := range analyzers { //nolint:maprange
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Config configures which rules are run.
+// The zero value is a valid configuration, which enables all rules
+type Config struct {
+	// Rules enables (true) or disables (false) rules by name.
+	// Rules which are not configured are enabled
+	Rules map[string]bool `json:"rules"`
+}
+
+// Enabled returns true if the rule with the given name is enabled
+func (c Config) Enabled(name string) bool {
+	enabled, ok := c.Rules[name]
+	return !ok || enabled
+}
+
+// Analyzers returns the analyzers of the enabled rules.
+// It is an error to configure a rule which does not exist
+func (c Config) Analyzers() ([]*analysis.Analyzer, error) {
+	for name := range c.Rules { //nolint:maprange
+		if _, ok := analyzers[name]; !ok {
+			return nil, fmt.Errorf("unknown lint rule: %s", name)
+		}
+	}
+
+	var result []*analysis.Analyzer
+	for _, name := range Rules() {
+		if c.Enabled(name) {
+			result = append(result, analyzers[name])
+		}
+	}
+	return result, nil
+}
+
+// Lint runs the analyzers of the enabled rules on the given program,
+// and returns the reported diagnostics which are not suppressed, sorted by position.
+// The program must have been loaded with LoadMode
+func Lint(program *analysis.Program, config Config) ([]analysis.Diagnostic, error) {
+	enabledAnalyzers, err := config.Analyzers()
+	if err != nil {
+		return nil, err
+	}
+
+	suppressions := parseSuppressions(program.Code)
+
+	var lock sync.Mutex
+	var diagnostics []analysis.Diagnostic
+
+	program.Run(
+		enabledAnalyzers,
+		func(diagnostic analysis.Diagnostic) {
+			if suppressions.suppresses(diagnostic) {
+				return
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			diagnostics = append(diagnostics, diagnostic)
+		},
+	)
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a := diagnostics[i]
+		b := diagnostics[j]
+		if a.StartPos.Offset != b.StartPos.Offset {
+			return a.StartPos.Offset < b.StartPos.Offset
+		}
+		return a.Category < b.Category
+	})
+
+	return diagnostics, nil
+}
+
+// HandleCheckerError is a handler for analysis.Config.HandleCheckerError,
+// which lets programs be loaded if all checker errors are also reported by analyzers,
+// i.e. unreachable statements
+func HandleCheckerError(err analysis.ParsingCheckingError, _ *sema.Checker) error {
+	checkerErr, ok := err.Unwrap().(*sema.CheckerError)
+	if !ok {
+		return err
+	}
+
+	for _, childErr := range checkerErr.Errors {
+		if _, ok := childErr.(*sema.UnreachableStatementError); !ok {
+			return err
+		}
+	}
+
+	return nil
+}
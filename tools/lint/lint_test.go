@@ -0,0 +1,346 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/tools/analysis"
+	"github.com/onflow/cadence/tools/lint"
+)
+
+var testLocation = common.StringLocation("test.cdc")
+
+var testContractLocation = common.AddressLocation{
+	Address: common.MustBytesToAddress([]byte{0x1}),
+	Name:    "Test",
+}
+
+const testContractCode = `
+  pub contract Test {
+      pub struct S {}
+      pub fun test() {}
+  }
+`
+
+func analysisConfig(code string) *analysis.Config {
+	config := analysis.NewSimpleConfig(
+		lint.LoadMode,
+		map[common.Location][]byte{
+			testLocation:         []byte(code),
+			testContractLocation: []byte(testContractCode),
+		},
+		map[common.Address][]string{
+			testContractLocation.Address: {testContractLocation.Name},
+		},
+		nil,
+	)
+	config.AccountLinkingEnabled = true
+	config.AttachmentsEnabled = true
+	config.CapabilityControllersEnabled = true
+	return config
+}
+
+func loadTestProgram(t *testing.T, code string) *analysis.Program {
+	config := analysisConfig(code)
+	config.HandleCheckerError = lint.HandleCheckerError
+
+	programs, err := analysis.Load(config, testLocation)
+	require.NoError(t, err)
+
+	return programs[testLocation]
+}
+
+// testLint lints the given code with only the given rule enabled
+func testLint(t *testing.T, code string, rule string) []analysis.Diagnostic {
+	rules := map[string]bool{}
+	for _, name := range lint.Rules() {
+		rules[name] = name == rule
+	}
+
+	diagnostics, err := lint.Lint(
+		loadTestProgram(t, code),
+		lint.Config{
+			Rules: rules,
+		},
+	)
+	require.NoError(t, err)
+
+	return diagnostics
+}
+
+// summarize returns the positions and messages of the given diagnostics
+func summarize(diagnostics []analysis.Diagnostic) []string {
+	summaries := make([]string, 0, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		summaries = append(
+			summaries,
+			fmt.Sprintf(
+				"%d:%d: %s",
+				diagnostic.StartPos.Line,
+				diagnostic.StartPos.Column,
+				diagnostic.Message,
+			),
+		)
+	}
+	return summaries
+}
+
+func TestRules(t *testing.T) {
+
+	t.Parallel()
+
+	require.Equal(t,
+		[]string{
+			"account-access-in-attachment-or-interface",
+			"capability-exposes-withdraw",
+			"deprecated-access-keyword",
+			"empty-conditions",
+			"public-auth-account-parameter",
+			"public-mutable-field",
+			"public-vault-capability",
+			"redundant-cast",
+			"shadowing",
+			"unnecessary-force-unwrap",
+			"unreachable-code",
+			"unused-import",
+			"unused-parameter",
+			"unused-variable",
+		},
+		lint.Rules(),
+	)
+
+	require.Equal(t,
+		[]string{
+			"account-access-in-attachment-or-interface",
+			"capability-exposes-withdraw",
+			"public-auth-account-parameter",
+			"public-mutable-field",
+			"public-vault-capability",
+		},
+		lint.SecurityRules(),
+	)
+
+	for _, name := range lint.SecurityRules() {
+		require.True(t, lint.IsSecurityRule(name))
+	}
+	require.False(t, lint.IsSecurityRule(lint.ShadowingRule))
+
+	for _, name := range lint.Rules() {
+		analyzer, ok := lint.Analyzer(name)
+		require.True(t, ok)
+		require.NotEmpty(t, analyzer.Description)
+	}
+}
+
+func TestConfig(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("zero value enables all rules", func(t *testing.T) {
+
+		t.Parallel()
+
+		analyzers, err := lint.Config{}.Analyzers()
+		require.NoError(t, err)
+		require.Len(t, analyzers, len(lint.Rules()))
+	})
+
+	t.Run("disabled rule", func(t *testing.T) {
+
+		t.Parallel()
+
+		config := lint.Config{
+			Rules: map[string]bool{
+				lint.ShadowingRule:     false,
+				lint.UnusedImportRule:  true,
+				lint.RedundantCastRule: false,
+			},
+		}
+
+		require.False(t, config.Enabled(lint.ShadowingRule))
+		require.True(t, config.Enabled(lint.UnusedImportRule))
+		require.True(t, config.Enabled(lint.UnusedVariableRule))
+
+		analyzers, err := config.Analyzers()
+		require.NoError(t, err)
+		require.Len(t, analyzers, len(lint.Rules())-2)
+		require.NotContains(t, analyzers, lint.ShadowingAnalyzer)
+		require.NotContains(t, analyzers, lint.RedundantCastAnalyzer)
+	})
+
+	t.Run("unknown rule", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := lint.Config{
+			Rules: map[string]bool{
+				"unknown": false,
+			},
+		}.Analyzers()
+		require.EqualError(t, err, "unknown lint rule: unknown")
+	})
+}
+
+func TestLint(t *testing.T) {
+
+	t.Parallel()
+
+	program := loadTestProgram(t, `
+      pub fun test(a: Int): Int {
+          let b = a as Int
+          return a
+      }
+    `)
+
+	diagnostics, err := lint.Lint(program, lint.Config{})
+	require.NoError(t, err)
+
+	require.Equal(t,
+		[]analysis.Diagnostic{
+			{
+				Location: testLocation,
+				Category: lint.DeprecatedAccessKeywordRule,
+				Message:  "access keyword `pub` is deprecated, use `access(all)` instead",
+				Range: ast.Range{
+					StartPos: ast.Position{Offset: 7, Line: 2, Column: 6},
+					EndPos:   ast.Position{Offset: 9, Line: 2, Column: 8},
+				},
+				SuggestedFixes: []analysis.SuggestedFix{
+					{
+						Message: "replace with `access(all)`",
+						TextEdits: []analysis.TextEdit{
+							{
+								Replacement: "access(all)",
+								Range: ast.Range{
+									StartPos: ast.Position{Offset: 7, Line: 2, Column: 6},
+									EndPos:   ast.Position{Offset: 9, Line: 2, Column: 8},
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				Location: testLocation,
+				Category: lint.UnusedVariableRule,
+				Message:  "unused variable `b`",
+				Range: ast.Range{
+					StartPos: ast.Position{Offset: 49, Line: 3, Column: 14},
+					EndPos:   ast.Position{Offset: 49, Line: 3, Column: 14},
+				},
+			},
+			{
+				Location: testLocation,
+				Category: lint.RedundantCastRule,
+				Message:  "cast to `Int` is redundant",
+				Range: ast.Range{
+					StartPos: ast.Position{Offset: 53, Line: 3, Column: 18},
+					EndPos:   ast.Position{Offset: 60, Line: 3, Column: 25},
+				},
+				SuggestedFixes: []analysis.SuggestedFix{
+					{
+						Message: "remove cast",
+						TextEdits: []analysis.TextEdit{
+							{
+								Replacement: "",
+								Range: ast.Range{
+									StartPos: ast.Position{Offset: 54, Line: 3, Column: 19},
+									EndPos:   ast.Position{Offset: 60, Line: 3, Column: 25},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		diagnostics,
+	)
+}
+
+func TestLintFixes(t *testing.T) {
+
+	t.Parallel()
+
+	code := `
+      pub fun test(a: Int): Int {
+          let b = a!
+          let c = b as Int
+          return c
+      }
+    `
+
+	diagnostics, err := lint.Lint(loadTestProgram(t, code), lint.Config{})
+	require.NoError(t, err)
+
+	fixes := make([]analysis.SuggestedFix, 0, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		require.Len(t, diagnostic.SuggestedFixes, 1)
+		fixes = append(fixes, diagnostic.SuggestedFixes[0])
+	}
+
+	result, applied, err := analysis.ApplyFixes([]byte(code), fixes)
+	require.NoError(t, err)
+	require.Equal(t, []bool{true, true, true}, applied)
+
+	require.Equal(t,
+		`
+      access(all) fun test(a: Int): Int {
+          let b = a
+          let c = b
+          return c
+      }
+    `,
+		string(result),
+	)
+}
+
+func TestSuppression(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := testLint(t,
+		`
+          access(all) fun test() {
+              let a = 1 // lint-disable
+              let b = 2 // lint-disable unused-variable
+              let c = 3 // lint-disable shadowing
+              // lint-disable
+              let d = 4
+              // lint-disable shadowing, unused-variable
+              let e = 5
+              let f = 6 // lint-disabled
+          }
+        `,
+		lint.UnusedVariableRule,
+	)
+
+	require.Equal(t,
+		[]string{
+			"5:18: unused variable `c`",
+			"10:18: unused variable `f`",
+		},
+		summarize(diagnostics),
+	)
+}
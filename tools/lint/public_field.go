@@ -0,0 +1,125 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const PublicMutableFieldRule = "public-mutable-field"
+
+// PublicMutableFieldAnalyzer reports public fields which have a resource type,
+// or a collection type, i.e. an array or dictionary type.
+// Anyone may access the functions of resources in public fields,
+// and may mutate public collections, e.g. by appending to arrays
+var PublicMutableFieldAnalyzer = &analysis.Analyzer{
+	Description: "Reports public fields of resource type or mutable collection type",
+	Requires: []*analysis.Analyzer{
+		analysis.InspectorAnalyzer,
+	},
+	Run: func(pass *analysis.Pass) interface{} {
+		program := pass.Program
+		elaboration := program.Elaboration
+		inspector := pass.ResultOf[analysis.InspectorAnalyzer].(*ast.Inspector)
+
+		inspector.Preorder(
+			[]ast.Element{
+				(*ast.CompositeDeclaration)(nil),
+				(*ast.AttachmentDeclaration)(nil),
+				(*ast.InterfaceDeclaration)(nil),
+			},
+			func(element ast.Element) {
+				declaration := element.(ast.Declaration)
+
+				members := compositeLikeMembers(elaboration, declaration)
+				if members == nil {
+					return
+				}
+
+				for _, field := range declaration.DeclarationMembers().Fields() {
+					if !isPublicAccess(field.Access) {
+						continue
+					}
+
+					member, ok := members.Get(field.Identifier.Identifier)
+					if !ok {
+						continue
+					}
+
+					fieldType := member.TypeAnnotation.Type
+
+					var message string
+
+					switch {
+					case fieldType.IsResourceType():
+						message = fmt.Sprintf(
+							"public field `%s` has resource type `%s`, which anyone can access",
+							field.Identifier.Identifier,
+							fieldType.QualifiedString(),
+						)
+
+					case isCollectionType(fieldType):
+						message = fmt.Sprintf(
+							"public field `%s` has collection type `%s`, which anyone can mutate",
+							field.Identifier.Identifier,
+							fieldType.QualifiedString(),
+						)
+
+					default:
+						continue
+					}
+
+					pass.Report(
+						analysis.Diagnostic{
+							Location: program.Location,
+							Category: PublicMutableFieldRule,
+							Message:  message,
+							Range:    ast.NewUnmeteredRangeFromPositioned(field.Identifier),
+						},
+					)
+				}
+			},
+		)
+
+		return nil
+	},
+}
+
+func init() {
+	registerSecurityAnalyzer(PublicMutableFieldRule, PublicMutableFieldAnalyzer)
+}
+
+// isCollectionType returns true if the given type, or the type it is optional of,
+// is an array type or a dictionary type
+func isCollectionType(ty sema.Type) bool {
+	if optionalType, ok := ty.(*sema.OptionalType); ok {
+		return isCollectionType(optionalType.Type)
+	}
+
+	switch ty.(type) {
+	case sema.ArrayType, *sema.DictionaryType:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,84 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/tools/lint"
+)
+
+func TestPublicMutableFieldAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := testLint(t,
+		`
+          pub contract C {
+
+              pub resource R {}
+
+              pub resource Collection {
+                  pub var ids: [UInt64]
+                  pub let owners: {UInt64: Address}
+                  pub(set) var names: [String]?
+                  pub let r: @R
+                  pub let count: Int
+                  access(contract) var hidden: [UInt64]
+                  priv let secret: @R
+
+                  init() {
+                      self.ids = []
+                      self.owners = {}
+                      self.names = nil
+                      self.r <- create R()
+                      self.count = 0
+                      self.hidden = []
+                      self.secret <- create R()
+                  }
+
+                  destroy() {
+                      destroy self.r
+                      destroy self.secret
+                  }
+              }
+
+              pub resource interface I {
+                  pub let items: [Int]
+              }
+
+              init() {}
+          }
+        `,
+		lint.PublicMutableFieldRule,
+	)
+
+	require.Equal(t,
+		[]string{
+			"7:26: public field `ids` has collection type `[UInt64]`, which anyone can mutate",
+			"8:26: public field `owners` has collection type `{UInt64: Address}`, which anyone can mutate",
+			"9:31: public field `names` has collection type `[String]?`, which anyone can mutate",
+			"10:26: public field `r` has resource type `C.R`, which anyone can access",
+			"32:26: public field `items` has collection type `[Int]`, which anyone can mutate",
+		},
+		summarize(diagnostics),
+	)
+}
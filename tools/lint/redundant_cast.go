@@ -0,0 +1,168 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const RedundantCastRule = "redundant-cast"
+
+// RedundantCastAnalyzer reports static casts (`as`) which do not change the type of the expression,
+// and force casts (`as!`) and failable casts (`as?`) which always succeed
+var RedundantCastAnalyzer = &analysis.Analyzer{
+	Description: "Reports casts which are redundant or always succeed",
+	Requires: []*analysis.Analyzer{
+		analysis.InspectorAnalyzer,
+	},
+	Run: func(pass *analysis.Pass) interface{} {
+		program := pass.Program
+		elaboration := program.Elaboration
+		inspector := pass.ResultOf[analysis.InspectorAnalyzer].(*ast.Inspector)
+
+		inspector.Preorder(
+			[]ast.Element{
+				(*ast.CastingExpression)(nil),
+			},
+			func(element ast.Element) {
+				expression := element.(*ast.CastingExpression)
+
+				var message string
+				var suggestedFixes []analysis.SuggestedFix
+
+				switch expression.Operation {
+				case ast.OperationCast:
+					types := elaboration.StaticCastTypes(expression)
+					if !isRedundantStaticCast(expression.Expression, types) {
+						return
+					}
+					message = fmt.Sprintf(
+						"cast to `%s` is redundant",
+						types.TargetType.QualifiedString(),
+					)
+
+					// Remove everything after the cast expression, i.e. the operator and the type
+
+					suggestedFixes = []analysis.SuggestedFix{
+						{
+							Message: "remove cast",
+							TextEdits: []analysis.TextEdit{
+								{
+									Replacement: "",
+									Range: ast.NewUnmeteredRange(
+										expression.Expression.EndPosition(nil).Shifted(nil, 1),
+										expression.EndPosition(nil),
+									),
+								},
+							},
+						},
+					}
+
+				case ast.OperationForceCast, ast.OperationFailableCast:
+					types := elaboration.RuntimeCastTypes(expression)
+					if types.Left == nil ||
+						types.Right == nil ||
+						!sema.IsSubType(types.Left, types.Right) {
+
+						return
+					}
+					kind := "force"
+					if expression.Operation == ast.OperationFailableCast {
+						kind = "failable"
+					}
+					message = fmt.Sprintf(
+						"%s cast (`%s`) from `%s` to `%s` always succeeds",
+						kind,
+						expression.Operation.Symbol(),
+						types.Left.QualifiedString(),
+						types.Right.QualifiedString(),
+					)
+
+				default:
+					return
+				}
+
+				pass.Report(
+					analysis.Diagnostic{
+						Location:       program.Location,
+						Category:       RedundantCastRule,
+						Message:        message,
+						Range:          ast.NewUnmeteredRangeFromPositioned(expression),
+						SuggestedFixes: suggestedFixes,
+					},
+				)
+			},
+		)
+
+		return nil
+	},
+}
+
+func init() {
+	RegisterAnalyzer(RedundantCastRule, RedundantCastAnalyzer)
+}
+
+// isRedundantStaticCast returns true if the static cast of the given expression is redundant:
+// Either the target type is already the type expected by the context of the cast,
+// or the expression already has the target type, even without the type being inferred from the cast
+func isRedundantStaticCast(expression ast.Expression, types sema.CastTypes) bool {
+	targetType := types.TargetType
+	if targetType == nil || targetType.IsInvalidType() {
+		return false
+	}
+
+	expectedType := types.ExpectedType
+	if expectedType != nil &&
+		!expectedType.IsInvalidType() &&
+		expectedType.Equal(targetType) {
+
+		return true
+	}
+
+	var actualType sema.Type
+
+	switch expression.(type) {
+	case *ast.IntegerExpression:
+		actualType = sema.IntType
+
+	case *ast.StringExpression:
+		actualType = sema.StringType
+
+	case *ast.BoolExpression:
+		actualType = sema.BoolType
+
+	case *ast.IdentifierExpression,
+		*ast.MemberExpression,
+		*ast.IndexExpression:
+
+		// The types of these expressions do not depend on the expected type,
+		// so the actual type is their type without the cast
+		actualType = types.ExprActualType
+
+	default:
+		// The types of other expressions, e.g. array literals or invocations of generic functions, may be inferred from the cast
+		return false
+	}
+
+	return actualType != nil && actualType.Equal(targetType)
+}
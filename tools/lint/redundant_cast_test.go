@@ -0,0 +1,60 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/tools/lint"
+)
+
+func TestRedundantCastAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := testLint(t,
+		`
+          access(all) fun test(x: Int, y: AnyStruct) {
+              let a = x as Int
+              let b: Int8 = 1 as Int8
+              let c = 1 as Int
+              let d = 1 as UInt8
+              let e = [1] as [UInt8]
+              let f = x as! Int
+              let g = x as? Int
+              let h = y as! Int
+              let i = x as Integer
+          }
+        `,
+		lint.RedundantCastRule,
+	)
+
+	require.Equal(t,
+		[]string{
+			"3:22: cast to `Int` is redundant",
+			"4:28: cast to `Int8` is redundant",
+			"5:22: cast to `Int` is redundant",
+			"8:22: force cast (`as!`) from `Int` to `Int` always succeeds",
+			"9:22: failable cast (`as?`) from `Int` to `Int` always succeeds",
+		},
+		summarize(diagnostics),
+	)
+}
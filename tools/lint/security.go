@@ -0,0 +1,321 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+var securityRules = map[string]struct{}{}
+
+// registerSecurityAnalyzer registers the analyzer for the rule with the given name,
+// and marks the rule as a security rule
+func registerSecurityAnalyzer(name string, analyzer *analysis.Analyzer) {
+	RegisterAnalyzer(name, analyzer)
+	securityRules[name] = struct{}{}
+}
+
+// SecurityRules returns the names of all rules which report potential vulnerabilities, sorted
+func SecurityRules() []string {
+	names := make([]string, 0, len(securityRules))
+	for name := range securityRules { //nolint:maprange
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsSecurityRule returns true if the rule with the given name reports potential vulnerabilities
+func IsSecurityRule(name string) bool {
+	_, ok := securityRules[name]
+	return ok
+}
+
+// isWithdrawFunctionName returns true if the function with the given name
+// is a withdraw-style function, e.g. `withdraw` or `withdrawTokens`
+func isWithdrawFunctionName(name string) bool {
+	return strings.HasPrefix(strings.ToLower(name), "withdraw")
+}
+
+// withdrawFunctions returns the names of the withdraw-style functions of the given members, sorted
+func withdrawFunctions(memberSets ...map[string]sema.MemberResolver) []string {
+	var names []string
+	seen := map[string]struct{}{}
+
+	for _, members := range memberSets {
+		for name, member := range members { //nolint:maprange
+			if member.Kind != common.DeclarationKindFunction ||
+				!isWithdrawFunctionName(name) {
+
+				continue
+			}
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// isVaultType returns true if the given type is a resource type with withdraw-style functions
+func isVaultType(ty sema.Type) bool {
+	compositeType, ok := ty.(*sema.CompositeType)
+	return ok &&
+		compositeType.Kind == common.CompositeKindResource &&
+		len(withdrawFunctions(compositeType.GetMembers())) > 0
+}
+
+// capabilityPublication is a publication of a capability by a program:
+// Either a link to a public path, or a publication of a capability using
+// `AuthAccount.capabilities.publish` or `AuthAccount.inbox.publish`
+type capabilityPublication struct {
+	// invocation is the invocation of the function which publishes the capability
+	invocation *ast.InvocationExpression
+	// borrowType is the borrow type of the published capability
+	borrowType sema.Type
+	// storagePath is the identifier of the storage path the capability targets, if known
+	storagePath string
+}
+
+// capabilityPublications returns the publications of capabilities by the given program
+func capabilityPublications(program *analysis.Program, inspector *ast.Inspector) []capabilityPublication {
+	elaboration := program.Elaboration
+
+	// Find the issued capabilities which are stored in variables,
+	// so that the storage paths of published variables can be determined
+
+	issuedCapabilities := map[ast.Position]*ast.InvocationExpression{}
+
+	inspector.Preorder(
+		[]ast.Element{
+			(*ast.VariableDeclaration)(nil),
+		},
+		func(element ast.Element) {
+			declaration := element.(*ast.VariableDeclaration)
+
+			invocation, ok := declaration.Value.(*ast.InvocationExpression)
+			if !ok || !isAccountFunctionInvocation(
+				elaboration,
+				invocation,
+				sema.AuthAccountStorageCapabilitiesType,
+				sema.AuthAccountStorageCapabilitiesTypeIssueFunctionName,
+			) {
+				return
+			}
+
+			issuedCapabilities[declaration.Identifier.Pos] = invocation
+		},
+	)
+
+	// issuedStoragePath returns the storage path of the given capability expression,
+	// if it is an issued capability
+	issuedStoragePath := func(expression ast.Expression) string {
+		if identifierExpression, ok := expression.(*ast.IdentifierExpression); ok {
+			positionInfo := program.PositionInfo
+			if positionInfo == nil {
+				return ""
+			}
+
+			occurrence := positionInfo.Occurrences.Find(
+				sema.ASTToSemaPosition(identifierExpression.Identifier.Pos),
+			)
+			if occurrence == nil ||
+				occurrence.Origin == nil ||
+				occurrence.Origin.StartPos == nil {
+
+				return ""
+			}
+
+			invocation, ok := issuedCapabilities[*occurrence.Origin.StartPos]
+			if !ok {
+				return ""
+			}
+			expression = invocation
+		}
+
+		invocation, ok := expression.(*ast.InvocationExpression)
+		if !ok || !isAccountFunctionInvocation(
+			elaboration,
+			invocation,
+			sema.AuthAccountStorageCapabilitiesType,
+			sema.AuthAccountStorageCapabilitiesTypeIssueFunctionName,
+		) {
+			return ""
+		}
+
+		return pathArgumentIdentifier(invocation, 0, common.PathDomainStorage)
+	}
+
+	var publications []capabilityPublication
+
+	inspector.Preorder(
+		[]ast.Element{
+			(*ast.InvocationExpression)(nil),
+		},
+		func(element ast.Element) {
+			invocation := element.(*ast.InvocationExpression)
+			invocationTypes := elaboration.InvocationExpressionTypes(invocation)
+
+			switch {
+			case isAccountFunctionInvocation(
+				elaboration,
+				invocation,
+				sema.AuthAccountType,
+				sema.AuthAccountTypeLinkFunctionName,
+			):
+				if len(invocationTypes.ArgumentTypes) < 1 ||
+					invocationTypes.ArgumentTypes[0] != sema.PublicPathType ||
+					invocationTypes.TypeArguments == nil ||
+					invocationTypes.TypeArguments.Len() != 1 {
+
+					return
+				}
+
+				publications = append(
+					publications,
+					capabilityPublication{
+						invocation:  invocation,
+						borrowType:  invocationTypes.TypeArguments.Oldest().Value,
+						storagePath: pathArgumentIdentifier(invocation, 1, common.PathDomainStorage),
+					},
+				)
+
+			case isAccountFunctionInvocation(
+				elaboration,
+				invocation,
+				sema.AuthAccountCapabilitiesType,
+				sema.AuthAccountCapabilitiesTypePublishFunctionName,
+			),
+				isAccountFunctionInvocation(
+					elaboration,
+					invocation,
+					sema.AuthAccountInboxType,
+					sema.AuthAccountInboxTypePublishFunctionName,
+				):
+
+				if len(invocationTypes.ArgumentTypes) < 1 ||
+					len(invocation.Arguments) < 1 {
+
+					return
+				}
+
+				capabilityType, ok := invocationTypes.ArgumentTypes[0].(*sema.CapabilityType)
+				if !ok || capabilityType.BorrowType == nil {
+					return
+				}
+
+				publications = append(
+					publications,
+					capabilityPublication{
+						invocation:  invocation,
+						borrowType:  capabilityType.BorrowType,
+						storagePath: issuedStoragePath(invocation.Arguments[0].Expression),
+					},
+				)
+			}
+		},
+	)
+
+	return publications
+}
+
+// isAccountFunctionInvocation returns true if the given invocation
+// is an invocation of the function with the given name of the given account type
+func isAccountFunctionInvocation(
+	elaboration *sema.Elaboration,
+	invocation *ast.InvocationExpression,
+	accountType sema.Type,
+	functionName string,
+) bool {
+	memberExpression, ok := invocation.InvokedExpression.(*ast.MemberExpression)
+	if !ok || memberExpression.Identifier.Identifier != functionName {
+		return false
+	}
+
+	memberInfo, ok := elaboration.MemberExpressionMemberInfo(memberExpression)
+	if !ok || memberInfo.AccessedType == nil {
+		return false
+	}
+
+	accessedType := memberInfo.AccessedType
+	if referenceType, ok := accessedType.(*sema.ReferenceType); ok {
+		accessedType = referenceType.Type
+	}
+
+	return accessedType.Equal(accountType)
+}
+
+// pathArgumentIdentifier returns the identifier of the path literal
+// in the given domain, which is passed as the argument with the given index, if any
+func pathArgumentIdentifier(
+	invocation *ast.InvocationExpression,
+	index int,
+	domain common.PathDomain,
+) string {
+	if index >= len(invocation.Arguments) {
+		return ""
+	}
+
+	pathExpression, ok := invocation.Arguments[index].Expression.(*ast.PathExpression)
+	if !ok || pathExpression.Domain.Identifier != domain.Identifier() {
+		return ""
+	}
+
+	return pathExpression.Identifier.Identifier
+}
+
+// compositeLikeMembers returns the members of the type of the given composite or interface declaration
+func compositeLikeMembers(
+	elaboration *sema.Elaboration,
+	declaration ast.Declaration,
+) *sema.StringMemberOrderedMap {
+	switch declaration := declaration.(type) {
+	case ast.CompositeLikeDeclaration:
+		compositeType := elaboration.CompositeDeclarationType(declaration)
+		if compositeType == nil {
+			return nil
+		}
+		return compositeType.Members
+
+	case *ast.InterfaceDeclaration:
+		interfaceType := elaboration.InterfaceDeclarationType(declaration)
+		if interfaceType == nil {
+			return nil
+		}
+		return interfaceType.Members
+	}
+
+	return nil
+}
+
+// isPublicAccess returns true if the given access makes a declaration accessible by anyone
+func isPublicAccess(access ast.Access) bool {
+	return access == ast.AccessPublic ||
+		access == ast.AccessPublicSettable
+}
@@ -0,0 +1,261 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const ShadowingRule = "shadowing"
+
+// ShadowingAnalyzer reports local declarations which shadow a declaration of an enclosing scope,
+// e.g. a local variable which has the same name as a parameter or a global declaration
+var ShadowingAnalyzer = &analysis.Analyzer{
+	Description: "Reports local declarations which shadow declarations of enclosing scopes",
+	Run: func(pass *analysis.Pass) interface{} {
+		program := pass.Program
+
+		checker := &shadowingChecker{
+			report: func(identifier ast.Identifier, previous ast.Identifier) {
+				pass.Report(
+					analysis.Diagnostic{
+						Location: program.Location,
+						Category: ShadowingRule,
+						Message: fmt.Sprintf(
+							"declaration of `%s` shadows the declaration on line %d",
+							identifier.Identifier,
+							previous.Pos.Line,
+						),
+						Range: ast.NewUnmeteredRangeFromPositioned(identifier),
+					},
+				)
+			},
+		}
+
+		checker.checkProgram(program.Program)
+
+		return nil
+	},
+}
+
+func init() {
+	RegisterAnalyzer(ShadowingRule, ShadowingAnalyzer)
+}
+
+type shadowingChecker struct {
+	report func(identifier ast.Identifier, previous ast.Identifier)
+	scopes []map[string]ast.Identifier
+}
+
+func (c *shadowingChecker) pushScope() {
+	c.scopes = append(c.scopes, map[string]ast.Identifier{})
+}
+
+func (c *shadowingChecker) popScope() {
+	c.scopes = c.scopes[:len(c.scopes)-1]
+}
+
+// declare declares the given identifier in the current scope,
+// and reports if it shadows a declaration of an enclosing scope
+func (c *shadowingChecker) declare(identifier ast.Identifier) {
+	name := identifier.Identifier
+	if name == "" || name == "_" {
+		return
+	}
+
+	current := len(c.scopes) - 1
+	for i := current - 1; i >= 0; i-- {
+		if previous, ok := c.scopes[i][name]; ok {
+			c.report(identifier, previous)
+			break
+		}
+	}
+
+	c.scopes[current][name] = identifier
+}
+
+func (c *shadowingChecker) checkProgram(program *ast.Program) {
+	c.pushScope()
+	defer c.popScope()
+
+	// Global declarations are visible in the whole program,
+	// so declare all of them before checking the nested scopes
+
+	for _, declaration := range program.ImportDeclarations() {
+		for _, identifier := range declaration.Identifiers {
+			c.declare(identifier)
+		}
+	}
+
+	for _, declaration := range program.Declarations() {
+		switch declaration.(type) {
+		case *ast.FunctionDeclaration,
+			*ast.VariableDeclaration,
+			*ast.CompositeDeclaration,
+			*ast.InterfaceDeclaration,
+			*ast.AttachmentDeclaration:
+
+			if identifier := declaration.DeclarationIdentifier(); identifier != nil {
+				c.declare(*identifier)
+			}
+		}
+	}
+
+	for _, declaration := range program.Declarations() {
+		c.checkDeclaration(declaration)
+	}
+}
+
+// checkDeclaration checks the given global or member declaration,
+// which has already been declared if it is visible as a value
+func (c *shadowingChecker) checkDeclaration(declaration ast.Declaration) {
+	switch declaration := declaration.(type) {
+	case *ast.FunctionDeclaration:
+		c.checkFunction(declaration.ParameterList, declaration.FunctionBlock)
+
+	case *ast.SpecialFunctionDeclaration:
+		c.checkFunction(
+			declaration.FunctionDeclaration.ParameterList,
+			declaration.FunctionDeclaration.FunctionBlock,
+		)
+
+	case *ast.VariableDeclaration:
+		c.checkElement(declaration.Value)
+		if declaration.SecondValue != nil {
+			c.checkElement(declaration.SecondValue)
+		}
+
+	case *ast.CompositeDeclaration,
+		*ast.InterfaceDeclaration,
+		*ast.AttachmentDeclaration:
+
+		// Members are accessed through `self`, so they are not declared in a scope
+		for _, member := range declaration.DeclarationMembers().Declarations() {
+			c.checkDeclaration(member)
+		}
+
+	case *ast.TransactionDeclaration:
+		c.pushScope()
+		defer c.popScope()
+
+		if declaration.ParameterList != nil {
+			for _, parameter := range declaration.ParameterList.Parameters {
+				c.declare(parameter.Identifier)
+			}
+		}
+		if declaration.Prepare != nil {
+			c.checkDeclaration(declaration.Prepare)
+		}
+		if declaration.Execute != nil {
+			c.checkDeclaration(declaration.Execute)
+		}
+	}
+}
+
+func (c *shadowingChecker) checkFunction(parameterList *ast.ParameterList, functionBlock *ast.FunctionBlock) {
+	c.pushScope()
+	defer c.popScope()
+
+	if parameterList != nil {
+		for _, parameter := range parameterList.Parameters {
+			c.declare(parameter.Identifier)
+		}
+	}
+
+	if functionBlock != nil {
+		c.checkElement(functionBlock.Block)
+	}
+}
+
+// checkElement checks the given element of a function body
+func (c *shadowingChecker) checkElement(element ast.Element) {
+	if element == nil {
+		return
+	}
+
+	switch element := element.(type) {
+	case *ast.Block:
+		c.pushScope()
+		defer c.popScope()
+
+		for _, statement := range element.Statements {
+			c.checkElement(statement)
+		}
+
+	case *ast.VariableDeclaration:
+		c.checkElement(element.Value)
+		if element.SecondValue != nil {
+			c.checkElement(element.SecondValue)
+		}
+		c.declare(element.Identifier)
+
+	case *ast.FunctionDeclaration:
+		c.declare(element.Identifier)
+		c.checkFunction(element.ParameterList, element.FunctionBlock)
+
+	case *ast.FunctionExpression:
+		c.checkFunction(element.ParameterList, element.FunctionBlock)
+
+	case *ast.IfStatement:
+		if variableDeclaration, ok := element.Test.(*ast.VariableDeclaration); ok {
+			c.checkElement(variableDeclaration.Value)
+
+			c.pushScope()
+			c.declare(variableDeclaration.Identifier)
+			c.checkElement(element.Then)
+			c.popScope()
+		} else {
+			c.checkElement(element.Test)
+			c.checkElement(element.Then)
+		}
+		if element.Else != nil {
+			c.checkElement(element.Else)
+		}
+
+	case *ast.ForStatement:
+		c.checkElement(element.Value)
+
+		c.pushScope()
+		defer c.popScope()
+
+		if element.Index != nil {
+			c.declare(*element.Index)
+		}
+		c.declare(element.Identifier)
+		c.checkElement(element.Block)
+
+	case *ast.SwitchStatement:
+		c.checkElement(element.Expression)
+		for _, switchCase := range element.Cases {
+			c.checkElement(switchCase.Expression)
+
+			c.pushScope()
+			for _, statement := range switchCase.Statements {
+				c.checkElement(statement)
+			}
+			c.popScope()
+		}
+
+	default:
+		element.Walk(c.checkElement)
+	}
+}
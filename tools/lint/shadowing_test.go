@@ -0,0 +1,81 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/tools/lint"
+)
+
+func TestShadowingAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := testLint(t,
+		`
+          import Test from 0x1
+
+          access(all) let x = 1
+
+          access(all) fun test(a: Int) {
+              let x = 2
+              if true {
+                  let a = 3
+              }
+              for i, b in [1] {
+                  let i = 4
+              }
+              if let c = a as Int? {
+                  let c = 5
+              }
+              let f = fun (a: Int) {}
+              let Test = 6
+          }
+
+          access(all) struct S {
+              access(all) let test: Int
+
+              init(test: Int) {
+                  self.test = test
+              }
+          }
+
+          access(all) fun later() {
+              let a = 7
+          }
+        `,
+		lint.ShadowingRule,
+	)
+
+	require.Equal(t,
+		[]string{
+			"7:18: declaration of `x` shadows the declaration on line 4",
+			"9:22: declaration of `a` shadows the declaration on line 6",
+			"12:22: declaration of `i` shadows the declaration on line 11",
+			"15:22: declaration of `c` shadows the declaration on line 14",
+			"17:27: declaration of `a` shadows the declaration on line 6",
+			"18:18: declaration of `Test` shadows the declaration on line 2",
+			"24:19: declaration of `test` shadows the declaration on line 6",
+		},
+		summarize(diagnostics),
+	)
+}
@@ -0,0 +1,118 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/parser/lexer"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const suppressionCommentPrefix = "// lint-disable"
+
+// suppressions are the rules suppressed by `// lint-disable` comments, by line.
+// A nil set of rules suppresses all rules
+type suppressions map[int]map[string]struct{}
+
+// parseSuppressions returns the suppressions declared by the comments in the given code.
+//
+// A comment `// lint-disable` suppresses all diagnostics on the line it is on.
+// If the comment is on its own line, it suppresses all diagnostics on the following line instead.
+//
+// Rules can be listed after the prefix, e.g. `// lint-disable shadowing, unused-variable`,
+// to only suppress diagnostics of these rules
+func parseSuppressions(code []byte) suppressions {
+	result := suppressions{}
+
+	tokens := lexer.Lex(code, nil)
+	defer tokens.Reclaim()
+
+	for {
+		token := tokens.Next()
+
+		switch token.Type {
+		case lexer.TokenEOF:
+			return result
+
+		case lexer.TokenLineComment:
+			comment := string(token.Source(code))
+
+			rest, ok := strings.CutPrefix(comment, suppressionCommentPrefix)
+			if !ok {
+				continue
+			}
+
+			// The prefix must be followed by the end of the comment or a list of rules,
+			// e.g. `// lint-disable-foo` is not a suppression
+			if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+				continue
+			}
+
+			line := token.StartPos.Line
+			lineStart := bytes.LastIndexByte(code[:token.StartPos.Offset], '\n') + 1
+			if len(bytes.TrimSpace(code[lineStart:token.StartPos.Offset])) == 0 {
+				line++
+			}
+
+			result.add(line, rest)
+		}
+	}
+}
+
+func (s suppressions) add(line int, rules string) {
+	var ruleSet map[string]struct{}
+
+	for _, rule := range strings.Split(rules, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		if ruleSet == nil {
+			ruleSet = map[string]struct{}{}
+		}
+		ruleSet[rule] = struct{}{}
+	}
+
+	existing, ok := s[line]
+	switch {
+	case !ok:
+		s[line] = ruleSet
+	case existing == nil || ruleSet == nil:
+		s[line] = nil
+	default:
+		for rule := range ruleSet { //nolint:maprange
+			existing[rule] = struct{}{}
+		}
+	}
+}
+
+// suppresses returns true if the given diagnostic is suppressed
+func (s suppressions) suppresses(diagnostic analysis.Diagnostic) bool {
+	rules, ok := s[diagnostic.StartPos.Line]
+	if !ok {
+		return false
+	}
+	if rules == nil {
+		return true
+	}
+	_, ok = rules[diagnostic.Category]
+	return ok
+}
@@ -0,0 +1,93 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const UnnecessaryForceUnwrapRule = "unnecessary-force-unwrap"
+
+// UnnecessaryForceUnwrapAnalyzer reports force-unwraps (`!`) of values which are not optional
+var UnnecessaryForceUnwrapAnalyzer = &analysis.Analyzer{
+	Description: "Reports force-unwraps of values which are not optional",
+	Requires: []*analysis.Analyzer{
+		analysis.InspectorAnalyzer,
+	},
+	Run: func(pass *analysis.Pass) interface{} {
+		program := pass.Program
+		inspector := pass.ResultOf[analysis.InspectorAnalyzer].(*ast.Inspector)
+
+		inspector.Preorder(
+			[]ast.Element{
+				(*ast.ForceExpression)(nil),
+			},
+			func(element ast.Element) {
+				expression := element.(*ast.ForceExpression)
+
+				valueType := program.Elaboration.ForceExpressionType(expression)
+				if valueType == nil || valueType.IsInvalidType() {
+					return
+				}
+
+				if _, ok := valueType.(*sema.OptionalType); ok {
+					return
+				}
+
+				// Remove everything after the force-unwrapped expression, i.e. the force operator
+
+				pass.Report(
+					analysis.Diagnostic{
+						Location: program.Location,
+						Category: UnnecessaryForceUnwrapRule,
+						Message: fmt.Sprintf(
+							"unnecessary force-unwrap of non-optional value of type `%s`",
+							valueType.QualifiedString(),
+						),
+						Range: ast.NewUnmeteredRangeFromPositioned(expression),
+						SuggestedFixes: []analysis.SuggestedFix{
+							{
+								Message: "remove force-unwrap",
+								TextEdits: []analysis.TextEdit{
+									{
+										Replacement: "",
+										Range: ast.NewUnmeteredRange(
+											expression.Expression.EndPosition(nil).Shifted(nil, 1),
+											expression.EndPos,
+										),
+									},
+								},
+							},
+						},
+					},
+				)
+			},
+		)
+
+		return nil
+	},
+}
+
+func init() {
+	RegisterAnalyzer(UnnecessaryForceUnwrapRule, UnnecessaryForceUnwrapAnalyzer)
+}
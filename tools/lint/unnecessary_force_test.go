@@ -0,0 +1,52 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/tools/lint"
+)
+
+func TestUnnecessaryForceUnwrapAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := testLint(t,
+		`
+          access(all) fun test(x: Int, y: Int?, z: {String: Int}) {
+              let a = x!
+              let b = y!
+              let c = z["a"]!
+              let d = z!
+          }
+        `,
+		lint.UnnecessaryForceUnwrapRule,
+	)
+
+	require.Equal(t,
+		[]string{
+			"3:22: unnecessary force-unwrap of non-optional value of type `Int`",
+			"6:22: unnecessary force-unwrap of non-optional value of type `{String: Int}`",
+		},
+		summarize(diagnostics),
+	)
+}
@@ -0,0 +1,100 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const UnreachableCodeRule = "unreachable-code"
+
+// UnreachableCodeAnalyzer reports statements which follow a statement that always exits the block,
+// i.e. a `return`, `break`, or `continue` statement, or a call of `panic`.
+//
+// Programs with unreachable code are rejected by the checker.
+// To lint them, the programs must be loaded with HandleCheckerError
+var UnreachableCodeAnalyzer = &analysis.Analyzer{
+	Description: "Reports unreachable code after `return`, `break`, `continue`, and `panic`",
+	Requires: []*analysis.Analyzer{
+		analysis.InspectorAnalyzer,
+	},
+	Run: func(pass *analysis.Pass) interface{} {
+		program := pass.Program
+		inspector := pass.ResultOf[analysis.InspectorAnalyzer].(*ast.Inspector)
+
+		inspector.Preorder(
+			[]ast.Element{
+				(*ast.Block)(nil),
+			},
+			func(element ast.Element) {
+				statements := element.(*ast.Block).Statements
+
+				for i, statement := range statements {
+					if i == len(statements)-1 || !isExitingStatement(statement) {
+						continue
+					}
+
+					lastStatement := statements[len(statements)-1]
+
+					pass.Report(
+						analysis.Diagnostic{
+							Location: program.Location,
+							Category: UnreachableCodeRule,
+							Message:  "unreachable code",
+							Range: ast.NewUnmeteredRange(
+								statements[i+1].StartPosition(),
+								lastStatement.EndPosition(nil),
+							),
+						},
+					)
+
+					return
+				}
+			},
+		)
+
+		return nil
+	},
+}
+
+func init() {
+	RegisterAnalyzer(UnreachableCodeRule, UnreachableCodeAnalyzer)
+}
+
+// isExitingStatement returns true if the given statement always exits the block it is in
+func isExitingStatement(statement ast.Statement) bool {
+	switch statement := statement.(type) {
+	case *ast.ReturnStatement,
+		*ast.BreakStatement,
+		*ast.ContinueStatement:
+
+		return true
+
+	case *ast.ExpressionStatement:
+		invocation, ok := statement.Expression.(*ast.InvocationExpression)
+		if !ok {
+			return false
+		}
+		identifier, ok := invocation.InvokedExpression.(*ast.IdentifierExpression)
+		return ok && identifier.Identifier.Identifier == "panic"
+	}
+
+	return false
+}
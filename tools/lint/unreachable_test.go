@@ -0,0 +1,84 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/tools/analysis"
+	"github.com/onflow/cadence/tools/lint"
+)
+
+func TestUnreachableCodeAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := testLint(t,
+		`
+          access(all) fun test(): Int {
+              while true {
+                  break
+                  log("break")
+              }
+              for x in [1] {
+                  continue
+                  log("continue")
+              }
+              if false {
+                  panic("panic")
+                  log("panic")
+              }
+              return 1
+              log("return")
+              log("return")
+          }
+        `,
+		lint.UnreachableCodeRule,
+	)
+
+	require.Equal(t,
+		[]string{
+			"5:18: unreachable code",
+			"9:18: unreachable code",
+			"13:18: unreachable code",
+			"16:14: unreachable code",
+		},
+		summarize(diagnostics),
+	)
+}
+
+func TestHandleCheckerError(t *testing.T) {
+
+	t.Parallel()
+
+	// Programs with other errors are not loaded
+	config := analysisConfig(`
+      access(all) fun test() {
+          let x: Int = "x"
+          return
+          log(x)
+      }
+    `)
+	config.HandleCheckerError = lint.HandleCheckerError
+
+	_, err := analysis.Load(config, testLocation)
+	require.Error(t, err)
+}
@@ -0,0 +1,253 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const (
+	UnusedVariableRule  = "unused-variable"
+	UnusedParameterRule = "unused-parameter"
+	UnusedImportRule    = "unused-import"
+)
+
+// UnusedVariableAnalyzer reports local variables which are never used.
+// Variables with names starting with an underscore are ignored
+var UnusedVariableAnalyzer = &analysis.Analyzer{
+	Description: "Reports local variables which are never used",
+	Requires: []*analysis.Analyzer{
+		analysis.InspectorAnalyzer,
+	},
+	Run: func(pass *analysis.Pass) interface{} {
+		program := pass.Program
+		inspector := pass.ResultOf[analysis.InspectorAnalyzer].(*ast.Inspector)
+
+		globals := map[ast.Element]struct{}{}
+		for _, declaration := range program.Program.Declarations() {
+			globals[declaration] = struct{}{}
+		}
+
+		report := func(identifier ast.Identifier, kind string) {
+			if isUsed(program.PositionInfo, identifier) {
+				return
+			}
+			pass.Report(
+				analysis.Diagnostic{
+					Location: program.Location,
+					Category: UnusedVariableRule,
+					Message:  fmt.Sprintf("unused %s `%s`", kind, identifier.Identifier),
+					Range:    ast.NewUnmeteredRangeFromPositioned(identifier),
+				},
+			)
+		}
+
+		inspector.Preorder(
+			[]ast.Element{
+				(*ast.VariableDeclaration)(nil),
+				(*ast.ForStatement)(nil),
+			},
+			func(element ast.Element) {
+				switch element := element.(type) {
+				case *ast.VariableDeclaration:
+					if _, ok := globals[element]; ok {
+						return
+					}
+					report(element.Identifier, "variable")
+
+				case *ast.ForStatement:
+					report(element.Identifier, "loop variable")
+					if element.Index != nil {
+						report(*element.Index, "loop index")
+					}
+				}
+			},
+		)
+
+		return nil
+	},
+}
+
+// UnusedParameterAnalyzer reports parameters of functions which are never used.
+// Functions without statements and parameters with names starting with an underscore are ignored
+var UnusedParameterAnalyzer = &analysis.Analyzer{
+	Description: "Reports function parameters which are never used",
+	Requires: []*analysis.Analyzer{
+		analysis.InspectorAnalyzer,
+	},
+	Run: func(pass *analysis.Pass) interface{} {
+		program := pass.Program
+		inspector := pass.ResultOf[analysis.InspectorAnalyzer].(*ast.Inspector)
+
+		checkParameters := func(parameterList *ast.ParameterList, functionBlock *ast.FunctionBlock) {
+			if parameterList == nil || !functionBlock.HasStatements() {
+				return
+			}
+
+			for _, parameter := range parameterList.Parameters {
+				identifier := parameter.Identifier
+				if isUsed(program.PositionInfo, identifier) {
+					continue
+				}
+				pass.Report(
+					analysis.Diagnostic{
+						Location: program.Location,
+						Category: UnusedParameterRule,
+						Message:  fmt.Sprintf("unused parameter `%s`", identifier.Identifier),
+						Range:    ast.NewUnmeteredRangeFromPositioned(identifier),
+					},
+				)
+			}
+		}
+
+		inspector.Preorder(
+			[]ast.Element{
+				(*ast.FunctionDeclaration)(nil),
+				(*ast.SpecialFunctionDeclaration)(nil),
+				(*ast.FunctionExpression)(nil),
+			},
+			func(element ast.Element) {
+				switch element := element.(type) {
+				case *ast.FunctionDeclaration:
+					checkParameters(element.ParameterList, element.FunctionBlock)
+
+				case *ast.SpecialFunctionDeclaration:
+					declaration := element.FunctionDeclaration
+					checkParameters(declaration.ParameterList, declaration.FunctionBlock)
+
+				case *ast.FunctionExpression:
+					checkParameters(element.ParameterList, element.FunctionBlock)
+				}
+			},
+		)
+
+		return nil
+	},
+}
+
+// UnusedImportAnalyzer reports imported declarations which are never used,
+// e.g. `A` in `import A from 0x1`.
+// Imports of all declarations of a location, e.g. `import 0x1`, are not reported
+var UnusedImportAnalyzer = &analysis.Analyzer{
+	Description: "Reports imported declarations which are never used",
+	Run: func(pass *analysis.Pass) interface{} {
+		program := pass.Program
+
+		usedImports := map[string]struct{}{}
+
+		lineStarts := lineStartOffsets(program.Code)
+
+		for _, occurrence := range program.PositionInfo.Occurrences.All() {
+			// Imported declarations have no position in the importing program
+			origin := occurrence.Origin
+			if origin == nil || origin.StartPos == nil || origin.StartPos.Line != 0 {
+				continue
+			}
+
+			line := occurrence.StartPos.Line
+			if line < 1 || line > len(lineStarts) {
+				continue
+			}
+			offset := lineStarts[line-1] + occurrence.StartPos.Column
+			name := identifierAt(program.Code, offset)
+			usedImports[name] = struct{}{}
+		}
+
+		for _, declaration := range program.Program.ImportDeclarations() {
+			for _, identifier := range declaration.Identifiers {
+				if _, ok := usedImports[identifier.Identifier]; ok {
+					continue
+				}
+				pass.Report(
+					analysis.Diagnostic{
+						Location: program.Location,
+						Category: UnusedImportRule,
+						Message:  fmt.Sprintf("unused import `%s`", identifier.Identifier),
+						Range:    ast.NewUnmeteredRangeFromPositioned(identifier),
+					},
+				)
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	RegisterAnalyzer(UnusedVariableRule, UnusedVariableAnalyzer)
+	RegisterAnalyzer(UnusedParameterRule, UnusedParameterAnalyzer)
+	RegisterAnalyzer(UnusedImportRule, UnusedImportAnalyzer)
+}
+
+// isUsed returns true if the variable declared with the given identifier is used,
+// i.e. there are occurrences of the variable besides its declaration.
+//
+// Variables with names starting with an underscore are considered used.
+// If the declaration is unknown, the variable is also considered used
+func isUsed(positionInfo *sema.PositionInfo, identifier ast.Identifier) bool {
+	if identifier.Identifier == "" || identifier.Identifier[0] == '_' {
+		return true
+	}
+
+	occurrence := positionInfo.Occurrences.Find(sema.ASTToSemaPosition(identifier.Pos))
+	if occurrence == nil || occurrence.Origin == nil {
+		return true
+	}
+
+	origin := occurrence.Origin
+	if origin.StartPos == nil || origin.StartPos.Offset != identifier.Pos.Offset {
+		return true
+	}
+
+	return len(origin.Occurrences) > 1
+}
+
+// lineStartOffsets returns the offsets of the starts of the lines of the given code
+func lineStartOffsets(code []byte) []int {
+	offsets := []int{0}
+	for i, b := range code {
+		if b == '\n' {
+			offsets = append(offsets, i+1)
+		}
+	}
+	return offsets
+}
+
+// identifierAt returns the identifier at the given offset of the given code
+func identifierAt(code []byte, offset int) string {
+	if offset < 0 || offset > len(code) {
+		return ""
+	}
+	end := offset
+	for end < len(code) && isIdentifierCharacter(code[end]) {
+		end++
+	}
+	return string(code[offset:end])
+}
+
+func isIdentifierCharacter(b byte) bool {
+	return b == '_' ||
+		('a' <= b && b <= 'z') ||
+		('A' <= b && b <= 'Z') ||
+		('0' <= b && b <= '9')
+}
@@ -0,0 +1,176 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/tools/lint"
+)
+
+func TestUnusedVariableAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := testLint(t,
+		`
+          access(all) let global = 1
+
+          access(all) fun test(): Int {
+              let a = 1
+              let b = 2
+              var c = 3
+              let _d = 4
+              for e in [1, 2] {}
+              for i, f in [1, 2] {
+                  c = c + f
+              }
+              if let g = 1 as Int? {}
+              let h = fun (): Int {
+                  let j = 5
+                  return b
+              }
+              return h()
+          }
+        `,
+		lint.UnusedVariableRule,
+	)
+
+	require.Equal(t,
+		[]string{
+			"5:18: unused variable `a`",
+			"9:18: unused loop variable `e`",
+			"10:18: unused loop index `i`",
+			"13:21: unused variable `g`",
+			"15:22: unused variable `j`",
+		},
+		summarize(diagnostics),
+	)
+}
+
+func TestUnusedParameterAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := testLint(t,
+		`
+          access(all) fun test(a: Int, b: Int, _c: Int): Int {
+              return b
+          }
+
+          access(all) fun empty(a: Int) {}
+
+          access(all) struct S {
+              init(x: Int) {
+                  let f = fun (y: Int): Int {
+                      return 1
+                  }
+              }
+          }
+
+          access(all) struct interface I {
+              access(all) fun test(a: Int)
+          }
+        `,
+		lint.UnusedParameterRule,
+	)
+
+	require.Equal(t,
+		[]string{
+			"2:31: unused parameter `a`",
+			"9:19: unused parameter `x`",
+			"10:31: unused parameter `y`",
+		},
+		summarize(diagnostics),
+	)
+}
+
+func TestUnusedImportAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("unused", func(t *testing.T) {
+
+		t.Parallel()
+
+		diagnostics := testLint(t,
+			`
+              import Test from 0x1
+            `,
+			lint.UnusedImportRule,
+		)
+
+		require.Equal(t,
+			[]string{
+				"2:21: unused import `Test`",
+			},
+			summarize(diagnostics),
+		)
+	})
+
+	t.Run("used as value", func(t *testing.T) {
+
+		t.Parallel()
+
+		diagnostics := testLint(t,
+			`
+              import Test from 0x1
+
+              access(all) fun main() {
+                  Test.test()
+              }
+            `,
+			lint.UnusedImportRule,
+		)
+
+		require.Empty(t, diagnostics)
+	})
+
+	t.Run("used as type", func(t *testing.T) {
+
+		t.Parallel()
+
+		diagnostics := testLint(t,
+			`
+              import Test from 0x1
+
+              access(all) fun main(s: Test.S) {}
+            `,
+			lint.UnusedImportRule,
+		)
+
+		require.Empty(t, diagnostics)
+	})
+
+	t.Run("location only", func(t *testing.T) {
+
+		t.Parallel()
+
+		diagnostics := testLint(t,
+			`
+              import 0x1
+            `,
+			lint.UnusedImportRule,
+		)
+
+		require.Empty(t, diagnostics)
+	})
+}
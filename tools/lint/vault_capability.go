@@ -0,0 +1,136 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const PublicVaultCapabilityRule = "public-vault-capability"
+
+// PublicVaultCapabilityAnalyzer reports public capabilities for vaults,
+// i.e. for storage paths which the program saves vaults to, or for vault types,
+// which are not restricted, or which are authorized and can be downcast to the vault
+var PublicVaultCapabilityAnalyzer = &analysis.Analyzer{
+	Description: "Reports links and publications of capabilities for vaults which expose the whole vault",
+	Requires: []*analysis.Analyzer{
+		analysis.InspectorAnalyzer,
+	},
+	Run: func(pass *analysis.Pass) interface{} {
+		program := pass.Program
+		inspector := pass.ResultOf[analysis.InspectorAnalyzer].(*ast.Inspector)
+
+		vaultPaths := savedVaultPaths(program.Elaboration, inspector)
+
+		for _, publication := range capabilityPublications(program, inspector) {
+			referenceType, ok := publication.borrowType.(*sema.ReferenceType)
+			if !ok {
+				continue
+			}
+
+			referencedType := referenceType.Type
+			restrictedType, isRestricted := referencedType.(*sema.RestrictedType)
+			if isRestricted {
+				if !referenceType.Authorized {
+					continue
+				}
+				referencedType = restrictedType.Type
+			}
+
+			var message string
+
+			_, isVaultPath := vaultPaths[publication.storagePath]
+
+			switch {
+			case isVaultPath:
+				message = fmt.Sprintf(
+					"public capability of type `%s` exposes the vault stored at `/storage/%s`",
+					referenceType.QualifiedString(),
+					publication.storagePath,
+				)
+
+			case isVaultType(referencedType):
+				message = fmt.Sprintf(
+					"public capability of type `%s` exposes the vault",
+					referenceType.QualifiedString(),
+				)
+
+			default:
+				continue
+			}
+
+			pass.Report(
+				analysis.Diagnostic{
+					Location: program.Location,
+					Category: PublicVaultCapabilityRule,
+					Message:  message,
+					Range:    ast.NewUnmeteredRangeFromPositioned(publication.invocation),
+				},
+			)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	registerSecurityAnalyzer(PublicVaultCapabilityRule, PublicVaultCapabilityAnalyzer)
+}
+
+// savedVaultPaths returns the identifiers of the storage paths which the program saves vaults to
+func savedVaultPaths(elaboration *sema.Elaboration, inspector *ast.Inspector) map[string]struct{} {
+	paths := map[string]struct{}{}
+
+	inspector.Preorder(
+		[]ast.Element{
+			(*ast.InvocationExpression)(nil),
+		},
+		func(element ast.Element) {
+			invocation := element.(*ast.InvocationExpression)
+
+			if !isAccountFunctionInvocation(
+				elaboration,
+				invocation,
+				sema.AuthAccountType,
+				sema.AuthAccountTypeSaveFunctionName,
+			) {
+				return
+			}
+
+			argumentTypes := elaboration.InvocationExpressionTypes(invocation).ArgumentTypes
+			if len(argumentTypes) < 1 || !isVaultType(argumentTypes[0]) {
+				return
+			}
+
+			path := pathArgumentIdentifier(invocation, 1, common.PathDomainStorage)
+			if path == "" {
+				return
+			}
+
+			paths[path] = struct{}{}
+		},
+	)
+
+	return paths
+}
@@ -0,0 +1,69 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/tools/lint"
+)
+
+func TestPublicVaultCapabilityAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := testLint(t,
+		testVaultCode+`
+          transaction {
+              prepare(signer: AuthAccount) {
+                  signer.save(<-create Vault(), to: /storage/vault)
+
+                  signer.link<&Vault{Receiver, Balance}>(/public/receiver, target: /storage/vault)
+                  signer.link<&Vault>(/public/vault, target: /storage/vault)
+                  signer.link<auth &AnyResource{Receiver}>(/public/auth, target: /storage/vault)
+                  signer.link<&AnyResource>(/public/any, target: /storage/vault)
+                  signer.link<&AnyResource>(/public/other, target: /storage/other)
+                  signer.link<&Vault>(/private/vault, target: /storage/vault)
+
+                  let cap = signer.capabilities.storage.issue<&AnyResource>(/storage/vault)
+                  signer.capabilities.publish(cap, at: /public/issued)
+                  signer.inbox.publish(
+                      signer.capabilities.storage.issue<&Vault>(/storage/other),
+                      name: "vault",
+                      recipient: 0x1
+                  )
+              }
+          }
+        `,
+		lint.PublicVaultCapabilityRule,
+	)
+
+	require.Equal(t,
+		[]string{
+			"35:18: public capability of type `&Vault` exposes the vault stored at `/storage/vault`",
+			"36:18: public capability of type `auth &AnyResource{Receiver}` exposes the vault stored at `/storage/vault`",
+			"37:18: public capability of type `&AnyResource` exposes the vault stored at `/storage/vault`",
+			"42:18: public capability of type `&AnyResource` exposes the vault stored at `/storage/vault`",
+			"43:18: public capability of type `&Vault` exposes the vault",
+		},
+		summarize(diagnostics),
+	)
+}
@@ -0,0 +1,497 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/compiler/bytecode"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// bytecodeVM is a stack-based virtual machine,
+// which executes programs generated by the bytecode generator.
+//
+// Like the WebAssembly-based VM, it reuses the interpreter's values and storage,
+// and delegates operations on values to the compiler runtime.
+type bytecodeVM struct {
+	runtime         *Runtime
+	program         *bytecode.Program
+	functionIndices map[string]int
+	constants       []interpreter.Value
+	staticTypes     []interpreter.StaticType
+	semaTypes       []sema.Type
+	locationRanges  []interpreter.LocationRange
+}
+
+var _ VM = &bytecodeVM{}
+
+// NewBytecodeVM returns a new VM for the given program,
+// which uses the given interpreter to access globals and storage.
+//
+// The interpreter's configuration is used to report computation,
+// function invocations, and loop iterations
+func NewBytecodeVM(program *bytecode.Program, inter *interpreter.Interpreter) (VM, error) {
+
+	runtime := NewRuntime(inter)
+
+	functionIndices := make(map[string]int, len(program.Functions))
+	for i, function := range program.Functions {
+		functionIndices[function.Name] = i
+	}
+
+	constants := make([]interpreter.Value, len(program.Constants))
+	for i, constant := range program.Constants {
+		value, err := decodeConstant(runtime, constant)
+		if err != nil {
+			return nil, err
+		}
+		constants[i] = value
+	}
+
+	staticTypes := make([]interpreter.StaticType, len(program.Types))
+	for i, encoded := range program.Types {
+		staticType, err := decodeStaticType(encoded)
+		if err != nil {
+			return nil, err
+		}
+		staticTypes[i] = staticType
+	}
+
+	locationRanges := make([]interpreter.LocationRange, len(program.Ranges))
+	for i, statementRange := range program.Ranges {
+		locationRanges[i] = interpreter.LocationRange{
+			Location:    inter.Location,
+			HasPosition: statementRange,
+		}
+	}
+
+	return &bytecodeVM{
+		runtime:         runtime,
+		program:         program,
+		functionIndices: functionIndices,
+		constants:       constants,
+		staticTypes:     staticTypes,
+		// Sema types are converted lazily, as the conversion might require loading imported programs
+		semaTypes:      make([]sema.Type, len(program.Types)),
+		locationRanges: locationRanges,
+	}, nil
+}
+
+func decodeConstant(runtime *Runtime, constant bytecode.Constant) (interpreter.Value, error) {
+	data := constant.Data
+
+	switch constant.Kind {
+	case bytecode.ConstantKindInt:
+		if len(data) < 1 {
+			return nil, fmt.Errorf("invalid Int constant: %x", data)
+		}
+		return runtime.Int(data), nil
+
+	case bytecode.ConstantKindString:
+		return runtime.String(data), nil
+
+	case bytecode.ConstantKindCharacter:
+		return runtime.Character(data), nil
+
+	case bytecode.ConstantKindFix64:
+		if len(data) != 8 {
+			return nil, fmt.Errorf("invalid Fix64 constant: %x", data)
+		}
+		return runtime.Fix64(int64(binary.BigEndian.Uint64(data))), nil
+
+	case bytecode.ConstantKindUFix64:
+		if len(data) != 8 {
+			return nil, fmt.Errorf("invalid UFix64 constant: %x", data)
+		}
+		return interpreter.NewUnmeteredUFix64Value(binary.BigEndian.Uint64(data)), nil
+
+	case bytecode.ConstantKindPath:
+		if len(data) < 1 {
+			return nil, fmt.Errorf("invalid path constant: %x", data)
+		}
+		return runtime.Path(int32(data[0]), data[1:]), nil
+
+	default:
+		return nil, fmt.Errorf("invalid constant kind: %s", constant.Kind)
+	}
+}
+
+func decodeStaticType(encoded []byte) (staticType interpreter.StaticType, err error) {
+	decoder := interpreter.CBORDecMode.NewByteStreamDecoder(encoded)
+	staticType, err = interpreter.NewTypeDecoder(decoder, nil).DecodeStaticType()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode type: %w", err)
+	}
+	return staticType, nil
+}
+
+func (m *bytecodeVM) Invoke(name string, arguments ...interpreter.Value) (result interpreter.Value, err error) {
+	functionIndex, ok := m.functionIndices[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function: %s", name)
+	}
+
+	function := m.program.Functions[functionIndex]
+	if len(arguments) != int(function.ParameterCount) {
+		return nil, fmt.Errorf(
+			"invalid argument count for function %s: expected %d, got %d",
+			name,
+			function.ParameterCount,
+			len(arguments),
+		)
+	}
+
+	// Operations report errors by panicking, like in the interpreter
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			recoveredErr, ok := recovered.(error)
+			if !ok {
+				panic(recovered)
+			}
+			err = m.wrapError(recoveredErr)
+		}
+	}()
+
+	return m.call(function, arguments), nil
+}
+
+// wrapError wraps the given error with the position of the statement which is currently executed,
+// if the error has no position information, and with the location of the program,
+// like the interpreter does
+func (m *bytecodeVM) wrapError(err error) error {
+	if _, ok := err.(interpreter.Error); ok {
+		return err
+	}
+
+	inter := m.runtime.interpreter
+
+	hasPosition := m.runtime.locationRange.HasPosition
+	if _, ok := err.(ast.HasPosition); !ok && hasPosition != nil {
+		err = interpreter.PositionedError{
+			Err:   err,
+			Range: ast.NewUnmeteredRangeFromPositioned(hasPosition),
+		}
+	}
+
+	return interpreter.Error{
+		Err:        err,
+		Location:   inter.Location,
+		StackTrace: inter.CallStack(),
+	}
+}
+
+func (m *bytecodeVM) semaType(typeIndex uint16) sema.Type {
+	semaType := m.semaTypes[typeIndex]
+	if semaType == nil {
+		semaType = m.runtime.interpreter.MustConvertStaticToSemaType(m.staticTypes[typeIndex])
+		m.semaTypes[typeIndex] = semaType
+	}
+	return semaType
+}
+
+// call executes the given function with the given arguments.
+// Functions without a result return void
+func (m *bytecodeVM) call(function *bytecode.Function, arguments []interpreter.Value) interpreter.Value {
+
+	runtime := m.runtime
+	code := function.Code
+
+	locals := make([]interpreter.Value, int(function.ParameterCount)+int(function.LocalCount))
+	copy(locals, arguments)
+
+	var stack []interpreter.Value
+
+	push := func(value interpreter.Value) {
+		stack = append(stack, value)
+	}
+
+	pop := func() interpreter.Value {
+		lastIndex := len(stack) - 1
+		value := stack[lastIndex]
+		stack[lastIndex] = nil
+		stack = stack[:lastIndex]
+		return value
+	}
+
+	// popN pops the given number of values, in the order they were pushed
+	popN := func(count uint16) []interpreter.Value {
+		start := len(stack) - int(count)
+		values := make([]interpreter.Value, count)
+		copy(values, stack[start:])
+		for i := start; i < len(stack); i++ {
+			stack[i] = nil
+		}
+		stack = stack[:start]
+		return values
+	}
+
+	ip := 0
+
+	operand := func() uint16 {
+		value := binary.BigEndian.Uint16(code[ip:])
+		ip += 2
+		return value
+	}
+
+	for ip < len(code) {
+		opcode := bytecode.Opcode(code[ip])
+		ip++
+
+		switch opcode {
+
+		// constants
+
+		case bytecode.OpcodeConstant:
+			push(m.constants[operand()])
+
+		case bytecode.OpcodeTrue:
+			push(interpreter.TrueValue)
+
+		case bytecode.OpcodeFalse:
+			push(interpreter.FalseValue)
+
+		case bytecode.OpcodeNil:
+			push(interpreter.Nil)
+
+		case bytecode.OpcodeVoid:
+			push(interpreter.Void)
+
+		// locals and globals
+
+		case bytecode.OpcodeGetLocal:
+			push(locals[operand()])
+
+		case bytecode.OpcodeSetLocal:
+			locals[operand()] = pop()
+
+		case bytecode.OpcodeGetGlobal:
+			push(runtime.global(m.program.Names[operand()]))
+
+		// stack
+
+		case bytecode.OpcodePop:
+			pop()
+
+		case bytecode.OpcodeDup:
+			push(stack[len(stack)-1])
+
+		// control flow
+
+		case bytecode.OpcodeJump:
+			ip = int(operand())
+
+		case bytecode.OpcodeJumpIfFalse:
+			target := operand()
+			if runtime.IsTrue(pop()) == 0 {
+				ip = int(target)
+			}
+
+		case bytecode.OpcodeJumpIfTrue:
+			target := operand()
+			if runtime.IsTrue(pop()) != 0 {
+				ip = int(target)
+			}
+
+		case bytecode.OpcodeLoopIteration:
+			runtime.ReportLoopIteration()
+
+		case bytecode.OpcodeStatement:
+			runtime.locationRange = m.locationRanges[operand()]
+			runtime.ReportStatement()
+
+		case bytecode.OpcodeReturn:
+			return pop()
+
+		case bytecode.OpcodeReturnVoid:
+			return interpreter.Void
+
+		case bytecode.OpcodeFailCondition:
+			kind := operand()
+			runtime.FailCondition(int32(kind), pop())
+
+		// binary operations
+
+		case bytecode.OpcodeAdd:
+			right, left := pop(), pop()
+			push(runtime.Add(left, right))
+
+		case bytecode.OpcodeSubtract:
+			right, left := pop(), pop()
+			push(runtime.Subtract(left, right))
+
+		case bytecode.OpcodeMultiply:
+			right, left := pop(), pop()
+			push(runtime.Multiply(left, right))
+
+		case bytecode.OpcodeDivide:
+			right, left := pop(), pop()
+			push(runtime.Divide(left, right))
+
+		case bytecode.OpcodeMod:
+			right, left := pop(), pop()
+			push(runtime.Mod(left, right))
+
+		case bytecode.OpcodeLess:
+			right, left := pop(), pop()
+			push(runtime.Less(left, right))
+
+		case bytecode.OpcodeLessEqual:
+			right, left := pop(), pop()
+			push(runtime.LessEqual(left, right))
+
+		case bytecode.OpcodeGreater:
+			right, left := pop(), pop()
+			push(runtime.Greater(left, right))
+
+		case bytecode.OpcodeGreaterEqual:
+			right, left := pop(), pop()
+			push(runtime.GreaterEqual(left, right))
+
+		case bytecode.OpcodeEqual:
+			right, left := pop(), pop()
+			push(runtime.Equal(left, right))
+
+		case bytecode.OpcodeNotEqual:
+			right, left := pop(), pop()
+			push(runtime.NotEqual(left, right))
+
+		case bytecode.OpcodeBitwiseOr:
+			right, left := pop(), pop()
+			push(runtime.BitwiseOr(left, right))
+
+		case bytecode.OpcodeBitwiseXor:
+			right, left := pop(), pop()
+			push(runtime.BitwiseXor(left, right))
+
+		case bytecode.OpcodeBitwiseAnd:
+			right, left := pop(), pop()
+			push(runtime.BitwiseAnd(left, right))
+
+		case bytecode.OpcodeBitwiseLeftShift:
+			right, left := pop(), pop()
+			push(runtime.BitwiseLeftShift(left, right))
+
+		case bytecode.OpcodeBitwiseRightShift:
+			right, left := pop(), pop()
+			push(runtime.BitwiseRightShift(left, right))
+
+		// unary operations
+
+		case bytecode.OpcodeNegate:
+			push(runtime.Negate(pop()))
+
+		case bytecode.OpcodeMinus:
+			push(runtime.Minus(pop()))
+
+		case bytecode.OpcodeForce:
+			push(runtime.Force(pop()))
+
+		case bytecode.OpcodeIsNil:
+			push(runtime.IsNil(pop()))
+
+		case bytecode.OpcodeSome:
+			push(runtime.Some(pop()))
+
+		case bytecode.OpcodeTransfer:
+			push(runtime.Transfer(pop()))
+
+		// invocations
+
+		case bytecode.OpcodeCall:
+			functionIndex := operand()
+			argumentCount := operand()
+			callArguments := popN(argumentCount)
+
+			// Like in the interpreter, invocations are reported by the caller
+			runtime.ReportFunctionInvocation()
+			locationRange := runtime.locationRange
+			result := m.call(m.program.Functions[functionIndex], callArguments)
+			runtime.locationRange = locationRange
+			runtime.ReportFunctionReturn()
+
+			push(result)
+
+		case bytecode.OpcodeInvoke:
+			argumentCount := operand()
+			invokeArguments := popN(argumentCount)
+			functionValue := pop()
+
+			push(runtime.invoke(functionValue, invokeArguments))
+
+		// values
+
+		case bytecode.OpcodeGetMember:
+			name := m.program.Names[operand()]
+			push(runtime.getMember(pop(), name))
+
+		case bytecode.OpcodeSetMember:
+			name := m.program.Names[operand()]
+			value, target := pop(), pop()
+			runtime.setMember(target, name, value)
+
+		case bytecode.OpcodeGetIndex:
+			index, target := pop(), pop()
+			push(runtime.GetIndex(target, index))
+
+		case bytecode.OpcodeSetIndex:
+			value, index, target := pop(), pop(), pop()
+			runtime.SetIndex(target, index, value)
+
+		case bytecode.OpcodeNewArray:
+			staticType := m.staticTypes[operand()]
+			elementCount := operand()
+			push(runtime.array(popN(elementCount), staticType))
+
+		case bytecode.OpcodeNewDictionary:
+			staticType := m.staticTypes[operand()]
+			entryCount := operand()
+			push(runtime.dictionary(popN(entryCount*2), staticType))
+
+		case bytecode.OpcodeConvert:
+			push(runtime.convert(pop(), m.semaType(operand())))
+
+		case bytecode.OpcodeFailableCast:
+			push(runtime.failableCast(pop(), m.semaType(operand())))
+
+		case bytecode.OpcodeForceCast:
+			push(runtime.forceCast(pop(), m.semaType(operand())))
+
+		case bytecode.OpcodeReference:
+			push(runtime.reference(pop(), m.semaType(operand())))
+
+		case bytecode.OpcodeDestroy:
+			push(runtime.Destroy(pop()))
+
+		case bytecode.OpcodeEmit:
+			runtime.emit(pop(), m.semaType(operand()))
+
+		default:
+			panic(errors.NewUnexpectedError("invalid opcode: %s", opcode))
+		}
+	}
+
+	// Semantic analysis ensures that all paths of functions with a result return,
+	// and the generator emits an explicit return for functions without a result
+	panic(errors.NewUnreachableError())
+}
@@ -0,0 +1,327 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/compiler"
+	"github.com/onflow/cadence/runtime/compiler/wasm"
+	"github.com/onflow/cadence/runtime/compiler/wasm/executor"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// executorVM is a VM which executes WebAssembly modules
+// using the pure-Go executor, i.e. it does not require cgo
+type executorVM struct {
+	instance *executor.Instance
+}
+
+var _ VM = &executorVM{}
+
+func (m *executorVM) Invoke(name string, arguments ...interpreter.Value) (result interpreter.Value, err error) {
+	// Runtime functions report errors by panicking
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			recoveredErr, ok := recovered.(error)
+			if !ok {
+				panic(recovered)
+			}
+			err = recoveredErr
+		}
+	}()
+
+	rawArguments := make([]executor.Value, len(arguments))
+	for i, argument := range arguments {
+		rawArguments[i] = argument
+	}
+
+	results, err := m.instance.Invoke(name, rawArguments...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 || results[0] == nil {
+		return nil, nil
+	}
+
+	value, ok := results[0].(interpreter.Value)
+	if !ok {
+		return nil, fmt.Errorf("invalid result of function %s: %#+v", name, results[0])
+	}
+
+	return value, nil
+}
+
+// NewExecutorVM returns a new VM for the given WebAssembly module,
+// which is executed using the pure-Go executor,
+// and which uses the given interpreter to implement the compiler runtime functions.
+//
+// The executed instructions are metered like the interpreter meters computation,
+// i.e. using the interpreter's configuration
+func NewExecutorVM(binary []byte, inter *interpreter.Interpreter) (VM, error) {
+
+	reader := wasm.NewWASMReader(wasm.NewBuffer(binary))
+	err := reader.ReadModule()
+	if err != nil {
+		return nil, err
+	}
+
+	imports, err := executorImports(NewRuntime(inter))
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err := executor.NewInstance(
+		&reader.Module,
+		imports,
+		&executor.Config{
+			ComputationGauge: interpreterComputationGauge{
+				interpreter: inter,
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &executorVM{
+		instance: instance,
+	}, nil
+}
+
+// interpreterComputationGauge meters computation using the interpreter's configuration
+type interpreterComputationGauge struct {
+	interpreter *interpreter.Interpreter
+}
+
+var _ executor.ComputationGauge = interpreterComputationGauge{}
+
+func (g interpreterComputationGauge) MeterComputation(kind common.ComputationKind, intensity uint) error {
+	// NOTE: the callback reports errors by panicking, like in the interpreter
+	onMeterComputation := g.interpreter.SharedState.Config.OnMeterComputation
+	if onMeterComputation != nil {
+		onMeterComputation(kind, intensity)
+	}
+	return nil
+}
+
+func executorImports(runtime *Runtime) (executor.Imports, error) {
+
+	// memory returns the bytes of the module's memory at the given offset and length
+	memory := func(instance *executor.Instance, offset executor.Value, length executor.Value) []byte {
+		// copy, as the memory might change
+		data := instance.MemoryRange(offset.(int32), length.(int32))
+		result := make([]byte, len(data))
+		copy(result, data)
+		return result
+	}
+
+	value := func(value interpreter.Value) []executor.Value {
+		return []executor.Value{value}
+	}
+
+	constant := func(f func([]byte) interpreter.Value) executor.HostFunction {
+		return executor.HostFunction{
+			Function: func(instance *executor.Instance, arguments []executor.Value) []executor.Value {
+				return value(f(memory(instance, arguments[0], arguments[1])))
+			},
+		}
+	}
+
+	nullary := func(f func() interpreter.Value) executor.HostFunction {
+		return executor.HostFunction{
+			Function: func(_ *executor.Instance, _ []executor.Value) []executor.Value {
+				return value(f())
+			},
+		}
+	}
+
+	unary := func(f func(any) interpreter.Value) executor.HostFunction {
+		return executor.HostFunction{
+			Function: func(_ *executor.Instance, arguments []executor.Value) []executor.Value {
+				return value(f(arguments[0]))
+			},
+		}
+	}
+
+	binary := func(f func(any, any) interpreter.Value) executor.HostFunction {
+		return executor.HostFunction{
+			Function: func(_ *executor.Instance, arguments []executor.Value) []executor.Value {
+				return value(f(arguments[0], arguments[1]))
+			},
+		}
+	}
+
+	valueAndConstant := func(f func(any, []byte) interpreter.Value) executor.HostFunction {
+		return executor.HostFunction{
+			Function: func(instance *executor.Instance, arguments []executor.Value) []executor.Value {
+				return value(f(arguments[0], memory(instance, arguments[1], arguments[2])))
+			},
+		}
+	}
+
+	report := func(f func()) executor.HostFunction {
+		return executor.HostFunction{
+			Function: func(_ *executor.Instance, _ []executor.Value) []executor.Value {
+				f()
+				return nil
+			},
+		}
+	}
+
+	functions := map[string]executor.HostFunction{
+		// constants
+
+		compiler.RuntimeFunctionNameInt:       constant(runtime.Int),
+		compiler.RuntimeFunctionNameString:    constant(runtime.String),
+		compiler.RuntimeFunctionNameCharacter: constant(runtime.Character),
+		compiler.RuntimeFunctionNameBool: {
+			Function: func(_ *executor.Instance, arguments []executor.Value) []executor.Value {
+				return value(runtime.Bool(arguments[0].(int32)))
+			},
+		},
+		compiler.RuntimeFunctionNameNil:  nullary(runtime.Nil),
+		compiler.RuntimeFunctionNameVoid: nullary(runtime.Void),
+		compiler.RuntimeFunctionNameFix64: {
+			Function: func(_ *executor.Instance, arguments []executor.Value) []executor.Value {
+				return value(runtime.Fix64(arguments[0].(int64)))
+			},
+		},
+		compiler.RuntimeFunctionNameUFix64: {
+			Function: func(_ *executor.Instance, arguments []executor.Value) []executor.Value {
+				return value(runtime.UFix64(arguments[0].(int64)))
+			},
+		},
+		compiler.RuntimeFunctionNamePath: {
+			Function: func(instance *executor.Instance, arguments []executor.Value) []executor.Value {
+				identifier := memory(instance, arguments[1], arguments[2])
+				return value(runtime.Path(arguments[0].(int32), identifier))
+			},
+		},
+
+		// binary operations
+
+		compiler.RuntimeFunctionNameAdd:               binary(runtime.Add),
+		compiler.RuntimeFunctionNameSubtract:          binary(runtime.Subtract),
+		compiler.RuntimeFunctionNameMultiply:          binary(runtime.Multiply),
+		compiler.RuntimeFunctionNameDivide:            binary(runtime.Divide),
+		compiler.RuntimeFunctionNameMod:               binary(runtime.Mod),
+		compiler.RuntimeFunctionNameLess:              binary(runtime.Less),
+		compiler.RuntimeFunctionNameLessEqual:         binary(runtime.LessEqual),
+		compiler.RuntimeFunctionNameGreater:           binary(runtime.Greater),
+		compiler.RuntimeFunctionNameGreaterEqual:      binary(runtime.GreaterEqual),
+		compiler.RuntimeFunctionNameEqual:             binary(runtime.Equal),
+		compiler.RuntimeFunctionNameNotEqual:          binary(runtime.NotEqual),
+		compiler.RuntimeFunctionNameBitwiseOr:         binary(runtime.BitwiseOr),
+		compiler.RuntimeFunctionNameBitwiseXor:        binary(runtime.BitwiseXor),
+		compiler.RuntimeFunctionNameBitwiseAnd:        binary(runtime.BitwiseAnd),
+		compiler.RuntimeFunctionNameBitwiseLeftShift:  binary(runtime.BitwiseLeftShift),
+		compiler.RuntimeFunctionNameBitwiseRightShift: binary(runtime.BitwiseRightShift),
+
+		// unary operations
+
+		compiler.RuntimeFunctionNameNegate:   unary(runtime.Negate),
+		compiler.RuntimeFunctionNameMinus:    unary(runtime.Minus),
+		compiler.RuntimeFunctionNameForce:    unary(runtime.Force),
+		compiler.RuntimeFunctionNameIsNil:    unary(runtime.IsNil),
+		compiler.RuntimeFunctionNameSome:     unary(runtime.Some),
+		compiler.RuntimeFunctionNameTransfer: unary(runtime.Transfer),
+
+		// control flow
+
+		compiler.RuntimeFunctionNameIsTrue: {
+			Function: func(_ *executor.Instance, arguments []executor.Value) []executor.Value {
+				return []executor.Value{runtime.IsTrue(arguments[0])}
+			},
+		},
+		compiler.RuntimeFunctionNameFailCondition: {
+			Function: func(_ *executor.Instance, arguments []executor.Value) []executor.Value {
+				runtime.FailCondition(arguments[0].(int32), arguments[1])
+				return nil
+			},
+		},
+
+		// values
+
+		compiler.RuntimeFunctionNameGlobal: constant(runtime.Global),
+		compiler.RuntimeFunctionNameNewList: {
+			Function: func(_ *executor.Instance, _ []executor.Value) []executor.Value {
+				return []executor.Value{runtime.NewList()}
+			},
+		},
+		compiler.RuntimeFunctionNameAppendList: {
+			Function: func(_ *executor.Instance, arguments []executor.Value) []executor.Value {
+				return []executor.Value{runtime.AppendList(arguments[0], arguments[1])}
+			},
+		},
+		compiler.RuntimeFunctionNameInvoke:    binary(runtime.Invoke),
+		compiler.RuntimeFunctionNameGetMember: valueAndConstant(runtime.GetMember),
+		compiler.RuntimeFunctionNameSetMember: {
+			Function: func(instance *executor.Instance, arguments []executor.Value) []executor.Value {
+				name := memory(instance, arguments[1], arguments[2])
+				runtime.SetMember(arguments[0], name, arguments[3])
+				return nil
+			},
+		},
+		compiler.RuntimeFunctionNameGetIndex: binary(runtime.GetIndex),
+		compiler.RuntimeFunctionNameSetIndex: {
+			Function: func(_ *executor.Instance, arguments []executor.Value) []executor.Value {
+				runtime.SetIndex(arguments[0], arguments[1], arguments[2])
+				return nil
+			},
+		},
+		compiler.RuntimeFunctionNameArray:        valueAndConstant(runtime.Array),
+		compiler.RuntimeFunctionNameDictionary:   valueAndConstant(runtime.Dictionary),
+		compiler.RuntimeFunctionNameConvert:      valueAndConstant(runtime.Convert),
+		compiler.RuntimeFunctionNameFailableCast: valueAndConstant(runtime.FailableCast),
+		compiler.RuntimeFunctionNameForceCast:    valueAndConstant(runtime.ForceCast),
+		compiler.RuntimeFunctionNameReference:    valueAndConstant(runtime.Reference),
+		compiler.RuntimeFunctionNameDestroy:      unary(runtime.Destroy),
+		compiler.RuntimeFunctionNameEmit: {
+			Function: func(instance *executor.Instance, arguments []executor.Value) []executor.Value {
+				eventType := memory(instance, arguments[1], arguments[2])
+				runtime.Emit(arguments[0], eventType)
+				return nil
+			},
+		},
+
+		// reporting
+
+		compiler.RuntimeFunctionNameReportFunctionInvocation: report(runtime.ReportFunctionInvocation),
+		compiler.RuntimeFunctionNameReportFunctionReturn:     report(runtime.ReportFunctionReturn),
+		compiler.RuntimeFunctionNameReportLoopIteration:      report(runtime.ReportLoopIteration),
+		compiler.RuntimeFunctionNameReportStatement:          report(runtime.ReportStatement),
+	}
+
+	runtimeFunctions := make(map[string]executor.HostFunction, len(compiler.RuntimeFunctions))
+	for _, runtimeFunction := range compiler.RuntimeFunctions {
+		function, ok := functions[runtimeFunction.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing runtime function: %s", runtimeFunction.Name)
+		}
+		function.Type = runtimeFunction.Type
+		runtimeFunctions[runtimeFunction.Name] = function
+	}
+
+	return executor.Imports{
+		compiler.RuntimeModuleName: runtimeFunctions,
+	}, nil
+}
@@ -0,0 +1,239 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/compiler"
+	"github.com/onflow/cadence/runtime/compiler/ir"
+	"github.com/onflow/cadence/runtime/compiler/wasm"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/tests/checker"
+	. "github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func compileExecutor(t *testing.T, code string, config *interpreter.Config) VM {
+
+	checker, err := checker.ParseAndCheck(t, code)
+	require.NoError(t, err)
+
+	comp := compiler.NewCompiler(checker)
+	funcs := comp.VisitProgram(checker.Program).([]*ir.Func)
+
+	mod := compiler.GenerateWasm(funcs)
+
+	var buf wasm.Buffer
+	w := wasm.NewWASMWriter(&buf)
+	err = w.WriteModule(mod)
+	require.NoError(t, err)
+
+	if config == nil {
+		config = &interpreter.Config{}
+	}
+	config.Storage = interpreter.NewInMemoryStorage(nil)
+
+	inter, err := interpreter.NewInterpreter(nil, nil, config)
+	require.NoError(t, err)
+
+	vm, err := NewExecutorVM(buf.Bytes(), inter)
+	require.NoError(t, err)
+
+	return vm
+}
+
+func TestExecutorVMFib(t *testing.T) {
+
+	t.Parallel()
+
+	vm := compileExecutor(t,
+		`
+          fun fib(_ n: Int): Int {
+              if n < 2 {
+                  return n
+              }
+              return fib(n - 1) + fib(n - 2)
+          }
+        `,
+		nil,
+	)
+
+	result, err := vm.Invoke("fib", interpreter.NewUnmeteredIntValueFromInt64(10))
+	require.NoError(t, err)
+
+	AssertValuesEqual(
+		t,
+		nil,
+		interpreter.NewUnmeteredIntValueFromInt64(55),
+		result,
+	)
+}
+
+func TestExecutorVMValues(t *testing.T) {
+
+	t.Parallel()
+
+	vm := compileExecutor(t,
+		`
+          fun sum(_ xs: [Int]): Int {
+              var s = 0
+              for x in xs {
+                  if x == 3 { continue }
+                  if x > 10 { break }
+                  s = s + x
+              }
+              return s
+          }
+
+          fun name(_ x: Int): String {
+              switch x {
+              case 1:
+                  return "one"
+              default:
+                  return "many"
+              }
+          }
+
+          fun test(): [AnyStruct] {
+              let d: {String: Int} = {"a": 1}
+              d["b"] = 2
+              let x: Int? = nil
+              return [
+                  sum([1, 2, 3, 4, 20, 5]),
+                  x ?? 7,
+                  name(1),
+                  d["a"]! + d["b"]!,
+                  "hello".concat(" world"),
+                  1.5,
+                  /storage/foo
+              ]
+          }
+        `,
+		nil,
+	)
+
+	result, err := vm.Invoke("test")
+	require.NoError(t, err)
+
+	require.Equal(t,
+		`[7, 7, "one", 3, "hello world", 1.50000000, /storage/foo]`,
+		result.String(),
+	)
+}
+
+func TestExecutorVMConditionFailure(t *testing.T) {
+
+	t.Parallel()
+
+	vm := compileExecutor(t,
+		`
+          fun inc(_ x: Int): Int {
+              pre { x > 0: "x must be positive" }
+              return x + 1
+          }
+        `,
+		nil,
+	)
+
+	_, err := vm.Invoke("inc", interpreter.NewUnmeteredIntValueFromInt64(0))
+	require.ErrorAs(t, err, &interpreter.ConditionError{})
+}
+
+func TestExecutorVMComputationMetering(t *testing.T) {
+
+	t.Parallel()
+
+	computation := map[common.ComputationKind]uint{}
+
+	vm := compileExecutor(t,
+		`
+          fun id(_ x: Int): Int {
+              return x
+          }
+
+          fun test(): Int {
+              var i = 0
+              while true {
+                  i = id(i) + 1
+                  if i == 3 {
+                      break
+                  }
+              }
+              return i
+          }
+        `,
+		&interpreter.Config{
+			OnMeterComputation: func(compKind common.ComputationKind, intensity uint) {
+				computation[compKind] += intensity
+			},
+		},
+	)
+
+	_, err := vm.Invoke("test")
+	require.NoError(t, err)
+
+	// The executed instructions are metered, too
+
+	require.NotZero(t, computation[common.ComputationKindWasmInstruction])
+	delete(computation, common.ComputationKindWasmInstruction)
+
+	require.Equal(t,
+		map[common.ComputationKind]uint{
+			common.ComputationKindStatement:          13,
+			common.ComputationKindFunctionInvocation: 3,
+			common.ComputationKindLoop:               3,
+		},
+		computation,
+	)
+}
+
+func TestExecutorVMComputationLimit(t *testing.T) {
+
+	t.Parallel()
+
+	computationLimitErr := errors.New("computation limit exceeded")
+
+	var instructions uint
+
+	vm := compileExecutor(t,
+		`
+          fun test() {
+              while true {}
+          }
+        `,
+		&interpreter.Config{
+			OnMeterComputation: func(compKind common.ComputationKind, intensity uint) {
+				if compKind != common.ComputationKindWasmInstruction {
+					return
+				}
+				instructions += intensity
+				if instructions > 10_000 {
+					panic(computationLimitErr)
+				}
+			},
+		},
+	)
+
+	_, err := vm.Invoke("test")
+	require.ErrorIs(t, err, computationLimitErr)
+}
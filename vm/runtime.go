@@ -0,0 +1,703 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// Runtime implements the functions of the compiler runtime,
+// which are imported by the WebAssembly modules generated by the compiler.
+//
+// Values are interpreter values, and operations on values are delegated to the interpreter,
+// so the compiled code has the same semantics as the interpreted code.
+//
+// Errors are reported by panicking, like in the interpreter.
+type Runtime struct {
+	interpreter *interpreter.Interpreter
+	// locationRange is the location range of the statement which is currently executed.
+	// It is empty if the compiled code has no position information
+	locationRange interpreter.LocationRange
+}
+
+func NewRuntime(inter *interpreter.Interpreter) *Runtime {
+	return &Runtime{
+		interpreter: inter,
+	}
+}
+
+func (r *Runtime) Interpreter() *interpreter.Interpreter {
+	return r.interpreter
+}
+
+// ValueList is a list of values,
+// used to pass a variable number of values to the runtime,
+// e.g. the arguments of an invocation
+type ValueList struct {
+	Values []interpreter.Value
+}
+
+// constants
+
+func (r *Runtime) Int(bytes []byte) interpreter.Value {
+	if len(bytes) < 1 {
+		panic(fmt.Errorf("Int: invalid length: %d", len(bytes)))
+	}
+
+	value := new(big.Int).SetBytes(bytes[1:])
+	if bytes[0] == 0 {
+		value = value.Neg(value)
+	}
+
+	return interpreter.NewUnmeteredIntValueFromBigInt(value)
+}
+
+func (r *Runtime) String(bytes []byte) interpreter.Value {
+	return interpreter.NewUnmeteredStringValue(string(bytes))
+}
+
+func (r *Runtime) Character(bytes []byte) interpreter.Value {
+	return interpreter.NewUnmeteredCharacterValue(string(bytes))
+}
+
+func (r *Runtime) Bool(value int32) interpreter.Value {
+	return interpreter.AsBoolValue(value != 0)
+}
+
+func (r *Runtime) Nil() interpreter.Value {
+	return interpreter.Nil
+}
+
+func (r *Runtime) Void() interpreter.Value {
+	return interpreter.Void
+}
+
+func (r *Runtime) Fix64(value int64) interpreter.Value {
+	return interpreter.NewUnmeteredFix64Value(value)
+}
+
+func (r *Runtime) UFix64(value int64) interpreter.Value {
+	return interpreter.NewUnmeteredUFix64Value(uint64(value))
+}
+
+func (r *Runtime) Path(domain int32, identifier []byte) interpreter.Value {
+	return interpreter.NewUnmeteredPathValue(
+		common.PathDomain(domain),
+		string(identifier),
+	)
+}
+
+// binary operations
+
+func (r *Runtime) numberOperands(operation ast.Operation, left, right any) (interpreter.NumberValue, interpreter.NumberValue) {
+	leftNumber, leftOk := left.(interpreter.NumberValue)
+	rightNumber, rightOk := right.(interpreter.NumberValue)
+	if !leftOk || !rightOk {
+		panic(r.invalidOperandsError(operation, left, right))
+	}
+	return leftNumber, rightNumber
+}
+
+func (r *Runtime) integerOperands(operation ast.Operation, left, right any) (interpreter.IntegerValue, interpreter.IntegerValue) {
+	leftInteger, leftOk := left.(interpreter.IntegerValue)
+	rightInteger, rightOk := right.(interpreter.IntegerValue)
+	if !leftOk || !rightOk {
+		panic(r.invalidOperandsError(operation, left, right))
+	}
+	return leftInteger, rightInteger
+}
+
+func (r *Runtime) comparableOperands(operation ast.Operation, left, right any) (interpreter.ComparableValue, interpreter.ComparableValue) {
+	leftComparable, leftOk := left.(interpreter.ComparableValue)
+	rightComparable, rightOk := right.(interpreter.ComparableValue)
+	if !leftOk || !rightOk {
+		panic(r.invalidOperandsError(operation, left, right))
+	}
+	return leftComparable, rightComparable
+}
+
+func (r *Runtime) invalidOperandsError(operation ast.Operation, left, right any) error {
+	leftValue, leftOk := left.(interpreter.Value)
+	rightValue, rightOk := right.(interpreter.Value)
+	if !leftOk || !rightOk {
+		return fmt.Errorf("%s: invalid operands: %#+v, %#+v", operation, left, right)
+	}
+
+	return interpreter.InvalidOperandsError{
+		Operation:     operation,
+		LeftType:      leftValue.StaticType(r.interpreter),
+		RightType:     rightValue.StaticType(r.interpreter),
+		LocationRange: r.locationRange,
+	}
+}
+
+func (r *Runtime) Add(left, right any) interpreter.Value {
+	leftNumber, rightNumber := r.numberOperands(ast.OperationPlus, left, right)
+	return leftNumber.Plus(r.interpreter, rightNumber, r.locationRange)
+}
+
+func (r *Runtime) Subtract(left, right any) interpreter.Value {
+	leftNumber, rightNumber := r.numberOperands(ast.OperationMinus, left, right)
+	return leftNumber.Minus(r.interpreter, rightNumber, r.locationRange)
+}
+
+func (r *Runtime) Multiply(left, right any) interpreter.Value {
+	leftNumber, rightNumber := r.numberOperands(ast.OperationMul, left, right)
+	return leftNumber.Mul(r.interpreter, rightNumber, r.locationRange)
+}
+
+func (r *Runtime) Divide(left, right any) interpreter.Value {
+	leftNumber, rightNumber := r.numberOperands(ast.OperationDiv, left, right)
+	return leftNumber.Div(r.interpreter, rightNumber, r.locationRange)
+}
+
+func (r *Runtime) Mod(left, right any) interpreter.Value {
+	leftNumber, rightNumber := r.numberOperands(ast.OperationMod, left, right)
+	return leftNumber.Mod(r.interpreter, rightNumber, r.locationRange)
+}
+
+func (r *Runtime) Less(left, right any) interpreter.Value {
+	leftComparable, rightComparable := r.comparableOperands(ast.OperationLess, left, right)
+	return leftComparable.Less(r.interpreter, rightComparable, r.locationRange)
+}
+
+func (r *Runtime) LessEqual(left, right any) interpreter.Value {
+	leftComparable, rightComparable := r.comparableOperands(ast.OperationLessEqual, left, right)
+	return leftComparable.LessEqual(r.interpreter, rightComparable, r.locationRange)
+}
+
+func (r *Runtime) Greater(left, right any) interpreter.Value {
+	leftComparable, rightComparable := r.comparableOperands(ast.OperationGreater, left, right)
+	return leftComparable.Greater(r.interpreter, rightComparable, r.locationRange)
+}
+
+func (r *Runtime) GreaterEqual(left, right any) interpreter.Value {
+	leftComparable, rightComparable := r.comparableOperands(ast.OperationGreaterEqual, left, right)
+	return leftComparable.GreaterEqual(r.interpreter, rightComparable, r.locationRange)
+}
+
+func (r *Runtime) testEqual(left, right any) bool {
+	leftValue := r.interpreter.Unbox(r.locationRange, r.value(left))
+	rightValue := r.interpreter.Unbox(r.locationRange, r.value(right))
+
+	leftEquatable, ok := leftValue.(interpreter.EquatableValue)
+	if !ok {
+		return false
+	}
+
+	return leftEquatable.Equal(r.interpreter, r.locationRange, rightValue)
+}
+
+func (r *Runtime) Equal(left, right any) interpreter.Value {
+	return interpreter.AsBoolValue(r.testEqual(left, right))
+}
+
+func (r *Runtime) NotEqual(left, right any) interpreter.Value {
+	return interpreter.AsBoolValue(!r.testEqual(left, right))
+}
+
+func (r *Runtime) BitwiseOr(left, right any) interpreter.Value {
+	leftInteger, rightInteger := r.integerOperands(ast.OperationBitwiseOr, left, right)
+	return leftInteger.BitwiseOr(r.interpreter, rightInteger, r.locationRange)
+}
+
+func (r *Runtime) BitwiseXor(left, right any) interpreter.Value {
+	leftInteger, rightInteger := r.integerOperands(ast.OperationBitwiseXor, left, right)
+	return leftInteger.BitwiseXor(r.interpreter, rightInteger, r.locationRange)
+}
+
+func (r *Runtime) BitwiseAnd(left, right any) interpreter.Value {
+	leftInteger, rightInteger := r.integerOperands(ast.OperationBitwiseAnd, left, right)
+	return leftInteger.BitwiseAnd(r.interpreter, rightInteger, r.locationRange)
+}
+
+func (r *Runtime) BitwiseLeftShift(left, right any) interpreter.Value {
+	leftInteger, rightInteger := r.integerOperands(ast.OperationBitwiseLeftShift, left, right)
+	return leftInteger.BitwiseLeftShift(r.interpreter, rightInteger, r.locationRange)
+}
+
+func (r *Runtime) BitwiseRightShift(left, right any) interpreter.Value {
+	leftInteger, rightInteger := r.integerOperands(ast.OperationBitwiseRightShift, left, right)
+	return leftInteger.BitwiseRightShift(r.interpreter, rightInteger, r.locationRange)
+}
+
+// unary operations
+
+func (r *Runtime) Negate(value any) interpreter.Value {
+	boolValue, ok := value.(interpreter.BoolValue)
+	if !ok {
+		panic(fmt.Errorf("negate: invalid operand: %#+v", value))
+	}
+	return boolValue.Negate(r.interpreter)
+}
+
+func (r *Runtime) Minus(value any) interpreter.Value {
+	numberValue, ok := value.(interpreter.NumberValue)
+	if !ok {
+		panic(fmt.Errorf("minus: invalid operand: %#+v", value))
+	}
+	return numberValue.Negate(r.interpreter, r.locationRange)
+}
+
+func (r *Runtime) Force(value any) interpreter.Value {
+	switch value := r.value(value).(type) {
+	case *interpreter.SomeValue:
+		return value.InnerValue(r.interpreter, r.locationRange)
+
+	case interpreter.NilValue:
+		panic(interpreter.ForceNilError{
+			LocationRange: r.locationRange,
+		})
+
+	default:
+		return value
+	}
+}
+
+func (r *Runtime) IsNil(value any) interpreter.Value {
+	_, ok := r.value(value).(interpreter.NilValue)
+	return interpreter.AsBoolValue(ok)
+}
+
+func (r *Runtime) Some(value any) interpreter.Value {
+	return interpreter.NewSomeValueNonCopying(r.interpreter, r.value(value))
+}
+
+func (r *Runtime) Transfer(value any) interpreter.Value {
+	return r.value(value).Transfer(
+		r.interpreter,
+		r.locationRange,
+		atree.Address{},
+		false,
+		nil,
+		nil,
+	)
+}
+
+// control flow
+
+func (r *Runtime) IsTrue(value any) int32 {
+	boolValue, ok := value.(interpreter.BoolValue)
+	if !ok {
+		panic(fmt.Errorf("is_true: invalid operand: %#+v", value))
+	}
+	if boolValue {
+		return 1
+	}
+	return 0
+}
+
+func (r *Runtime) FailCondition(kind int32, message any) {
+	var messageString string
+	if stringValue, ok := message.(*interpreter.StringValue); ok {
+		messageString = stringValue.Str
+	}
+
+	panic(interpreter.ConditionError{
+		ConditionKind: ast.ConditionKind(kind),
+		Message:       messageString,
+		LocationRange: r.locationRange,
+	})
+}
+
+// values
+
+func (r *Runtime) Global(name []byte) interpreter.Value {
+	return r.global(string(name))
+}
+
+func (r *Runtime) global(name string) interpreter.Value {
+	variable := r.interpreter.FindVariable(name)
+	if variable == nil {
+		panic(fmt.Errorf("global: unknown global: %s", name))
+	}
+	return variable.GetValue()
+}
+
+func (r *Runtime) NewList() *ValueList {
+	return &ValueList{}
+}
+
+func (r *Runtime) AppendList(list any, value any) *ValueList {
+	valueList := r.list(list)
+	valueList.Values = append(valueList.Values, r.value(value))
+	return valueList
+}
+
+func (r *Runtime) Invoke(function any, arguments any) interpreter.Value {
+	return r.invoke(function, r.list(arguments).Values)
+}
+
+func (r *Runtime) invoke(function any, argumentValues []interpreter.Value) interpreter.Value {
+	functionValue, ok := function.(interpreter.FunctionValue)
+	if !ok {
+		panic(fmt.Errorf("invoke: invalid function: %#+v", function))
+	}
+
+	// NOTE: the compiled code already transferred and converted the arguments
+
+	// Like in the interpreter, invocations are reported by the caller
+
+	r.ReportFunctionInvocation()
+	defer r.ReportFunctionReturn()
+
+	argumentTypes := make([]sema.Type, len(argumentValues))
+	for i, argument := range argumentValues {
+		argumentTypes[i] = r.interpreter.MustSemaTypeOfValue(argument)
+	}
+
+	result, err := r.interpreter.InvokeFunctionValue(
+		functionValue,
+		argumentValues,
+		argumentTypes,
+		nil,
+		ast.EmptyRange,
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+func (r *Runtime) GetMember(target any, name []byte) interpreter.Value {
+	return r.getMember(target, string(name))
+}
+
+func (r *Runtime) getMember(target any, identifier string) interpreter.Value {
+	result := r.interpreter.GetMember(r.value(target), r.locationRange, identifier)
+	if result == nil {
+		panic(interpreter.UseBeforeInitializationError{
+			Name:          identifier,
+			LocationRange: r.locationRange,
+		})
+	}
+
+	return result
+}
+
+func (r *Runtime) SetMember(target any, name []byte, value any) {
+	r.setMember(target, string(name), value)
+}
+
+func (r *Runtime) setMember(target any, name string, value any) {
+	memberAccessibleValue, ok := target.(interpreter.MemberAccessibleValue)
+	if !ok {
+		panic(fmt.Errorf("set_member: invalid target: %#+v", target))
+	}
+
+	memberAccessibleValue.SetMember(
+		r.interpreter,
+		r.locationRange,
+		name,
+		r.value(value),
+	)
+}
+
+func (r *Runtime) indexable(target any) interpreter.ValueIndexableValue {
+	indexableValue, ok := target.(interpreter.ValueIndexableValue)
+	if !ok {
+		panic(fmt.Errorf("invalid indexed value: %#+v", target))
+	}
+	return indexableValue
+}
+
+func (r *Runtime) GetIndex(target any, index any) interpreter.Value {
+	return r.indexable(target).GetKey(
+		r.interpreter,
+		r.locationRange,
+		r.value(index),
+	)
+}
+
+func (r *Runtime) SetIndex(target any, index any, value any) {
+	r.indexable(target).SetKey(
+		r.interpreter,
+		r.locationRange,
+		r.value(index),
+		r.value(value),
+	)
+}
+
+func (r *Runtime) Array(elements any, typ []byte) interpreter.Value {
+	return r.array(r.list(elements).Values, r.staticType(typ))
+}
+
+func (r *Runtime) array(elements []interpreter.Value, staticType interpreter.StaticType) interpreter.Value {
+	arrayType, ok := staticType.(interpreter.ArrayStaticType)
+	if !ok {
+		panic(fmt.Errorf("array: invalid type: %s", staticType))
+	}
+
+	return interpreter.NewArrayValue(
+		r.interpreter,
+		r.locationRange,
+		arrayType,
+		common.ZeroAddress,
+		elements...,
+	)
+}
+
+func (r *Runtime) Dictionary(keysAndValues any, typ []byte) interpreter.Value {
+	return r.dictionary(r.list(keysAndValues).Values, r.staticType(typ))
+}
+
+func (r *Runtime) dictionary(keysAndValues []interpreter.Value, staticType interpreter.StaticType) interpreter.Value {
+	dictionaryType, ok := staticType.(interpreter.DictionaryStaticType)
+	if !ok {
+		panic(fmt.Errorf("dictionary: invalid type: %s", staticType))
+	}
+
+	return interpreter.NewDictionaryValue(
+		r.interpreter,
+		r.locationRange,
+		dictionaryType,
+		keysAndValues...,
+	)
+}
+
+func (r *Runtime) Convert(value any, typ []byte) interpreter.Value {
+	return r.convert(value, r.semaType(typ))
+}
+
+func (r *Runtime) convert(value any, targetType sema.Type) interpreter.Value {
+	v := r.value(value)
+	valueType := r.interpreter.MustSemaTypeOfValue(v)
+	return r.interpreter.ConvertAndBox(r.locationRange, v, valueType, targetType)
+}
+
+func (r *Runtime) FailableCast(value any, typ []byte) interpreter.Value {
+	return r.failableCast(value, r.semaType(typ))
+}
+
+func (r *Runtime) failableCast(value any, expectedType sema.Type) interpreter.Value {
+	v := r.value(value)
+
+	valueStaticType := v.StaticType(r.interpreter)
+	if !r.interpreter.IsSubTypeOfSemaType(valueStaticType, expectedType) {
+		return interpreter.Nil
+	}
+
+	// The failable cast may upcast to an optional type, e.g. `1 as? Int?`, so box
+	v = r.interpreter.BoxOptional(r.locationRange, v, expectedType)
+
+	return interpreter.NewSomeValueNonCopying(r.interpreter, v)
+}
+
+func (r *Runtime) ForceCast(value any, typ []byte) interpreter.Value {
+	return r.forceCast(value, r.semaType(typ))
+}
+
+func (r *Runtime) forceCast(value any, expectedType sema.Type) interpreter.Value {
+	v := r.value(value)
+
+	valueStaticType := v.StaticType(r.interpreter)
+	if !r.interpreter.IsSubTypeOfSemaType(valueStaticType, expectedType) {
+		valueSemaType := r.interpreter.MustConvertStaticToSemaType(valueStaticType)
+
+		panic(interpreter.ForceCastTypeMismatchError{
+			ExpectedType:  expectedType,
+			ActualType:    valueSemaType,
+			LocationRange: r.locationRange,
+		})
+	}
+
+	// The force cast may upcast to an optional type, e.g. `1 as! Int?`, so box
+	return r.interpreter.BoxOptional(r.locationRange, v, expectedType)
+}
+
+func (r *Runtime) Reference(value any, typ []byte) interpreter.Value {
+	return r.reference(value, r.semaType(typ))
+}
+
+func (r *Runtime) reference(value any, borrowType sema.Type) interpreter.Value {
+	v := r.value(value)
+
+	// TODO: track referenced resources
+
+	switch typ := borrowType.(type) {
+	case *sema.OptionalType:
+		innerBorrowType, ok := typ.Type.(*sema.ReferenceType)
+		// we enforce this in the checker
+		if !ok {
+			panic(errors.NewUnreachableError())
+		}
+
+		switch v := v.(type) {
+		case *interpreter.SomeValue:
+			// References to optionals are transformed into optional references,
+			// so move the *SomeValue out to the reference itself
+
+			innerValue := v.InnerValue(r.interpreter, r.locationRange)
+
+			return interpreter.NewSomeValueNonCopying(
+				r.interpreter,
+				interpreter.NewEphemeralReferenceValue(
+					r.interpreter,
+					innerBorrowType.Authorized,
+					innerValue,
+					innerBorrowType.Type,
+				),
+			)
+
+		case interpreter.NilValue:
+			return interpreter.Nil
+
+		default:
+			// If the referenced value is non-optional,
+			// but the target type is optional,
+			// then box the reference properly
+
+			return r.interpreter.BoxOptional(
+				r.locationRange,
+				interpreter.NewEphemeralReferenceValue(
+					r.interpreter,
+					innerBorrowType.Authorized,
+					v,
+					innerBorrowType.Type,
+				),
+				borrowType,
+			)
+		}
+
+	case *sema.ReferenceType:
+		return interpreter.NewEphemeralReferenceValue(
+			r.interpreter,
+			typ.Authorized,
+			v,
+			typ.Type,
+		)
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
+func (r *Runtime) Destroy(value any) interpreter.Value {
+	resourceKindedValue, ok := value.(interpreter.ResourceKindedValue)
+	if !ok {
+		panic(fmt.Errorf("destroy: invalid value: %#+v", value))
+	}
+
+	resourceKindedValue.Destroy(r.interpreter, r.locationRange)
+
+	return interpreter.Void
+}
+
+func (r *Runtime) Emit(event any, typ []byte) {
+	r.emit(event, r.semaType(typ))
+}
+
+func (r *Runtime) emit(event any, ty sema.Type) {
+	compositeValue, ok := event.(*interpreter.CompositeValue)
+	if !ok {
+		panic(fmt.Errorf("emit: invalid event: %#+v", event))
+	}
+
+	eventType, ok := ty.(*sema.CompositeType)
+	if !ok {
+		panic(fmt.Errorf("emit: invalid event type: %s", ty))
+	}
+
+	onEventEmitted := r.interpreter.SharedState.Config.OnEventEmitted
+	if onEventEmitted == nil {
+		panic(interpreter.EventEmissionUnavailableError{
+			LocationRange: r.locationRange,
+		})
+	}
+
+	err := onEventEmitted(r.interpreter, r.locationRange, compositeValue, eventType)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (r *Runtime) value(value any) interpreter.Value {
+	result, ok := value.(interpreter.Value)
+	if !ok {
+		panic(fmt.Errorf("invalid value: %#+v", value))
+	}
+	return result
+}
+
+func (r *Runtime) list(list any) *ValueList {
+	result, ok := list.(*ValueList)
+	if !ok {
+		panic(fmt.Errorf("invalid list: %#+v", list))
+	}
+	return result
+}
+
+func (r *Runtime) staticType(encoded []byte) interpreter.StaticType {
+	decoder := interpreter.CBORDecMode.NewByteStreamDecoder(encoded)
+	staticType, err := interpreter.NewTypeDecoder(decoder, nil).DecodeStaticType()
+	if err != nil {
+		panic(fmt.Errorf("failed to decode type: %w", err))
+	}
+	return staticType
+}
+
+func (r *Runtime) semaType(encoded []byte) sema.Type {
+	return r.interpreter.MustConvertStaticToSemaType(r.staticType(encoded))
+}
+
+// reporting
+
+func (r *Runtime) ReportFunctionInvocation() {
+	config := r.interpreter.SharedState.Config
+
+	onMeterComputation := config.OnMeterComputation
+	if onMeterComputation != nil {
+		onMeterComputation(common.ComputationKindFunctionInvocation, 1)
+	}
+
+	onFunctionInvocation := config.OnFunctionInvocation
+	if onFunctionInvocation != nil {
+		onFunctionInvocation(r.interpreter)
+	}
+}
+
+func (r *Runtime) ReportFunctionReturn() {
+	onInvokedFunctionReturn := r.interpreter.SharedState.Config.OnInvokedFunctionReturn
+	if onInvokedFunctionReturn != nil {
+		onInvokedFunctionReturn(r.interpreter)
+	}
+}
+
+func (r *Runtime) ReportLoopIteration() {
+	onMeterComputation := r.interpreter.SharedState.Config.OnMeterComputation
+	if onMeterComputation != nil {
+		onMeterComputation(common.ComputationKindLoop, 1)
+	}
+}
+
+func (r *Runtime) ReportStatement() {
+	onMeterComputation := r.interpreter.SharedState.Config.OnMeterComputation
+	if onMeterComputation != nil {
+		onMeterComputation(common.ComputationKindStatement, 1)
+	}
+}
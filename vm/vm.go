@@ -23,26 +23,37 @@ package vm
 
 import (
 	"fmt"
-	"math/big"
-
-	"C"
 
 	"github.com/bytecodealliance/wasmtime-go/v7"
 
+	"github.com/onflow/cadence/runtime/compiler"
 	"github.com/onflow/cadence/runtime/interpreter"
 )
 
-type VM interface {
-	Invoke(name string, arguments ...interpreter.Value) (interpreter.Value, error)
-}
-
 type vm struct {
 	instance *wasmtime.Instance
 	store    *wasmtime.Store
 }
 
-func (m *vm) Invoke(name string, arguments ...interpreter.Value) (interpreter.Value, error) {
-	f := m.instance.GetExport(m.store, name).Func()
+func (m *vm) Invoke(name string, arguments ...interpreter.Value) (result interpreter.Value, err error) {
+	// Runtime functions report errors by panicking,
+	// which wasmtime propagates through the call
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			recoveredErr, ok := recovered.(error)
+			if !ok {
+				panic(recovered)
+			}
+			err = recoveredErr
+		}
+	}()
+
+	export := m.instance.GetExport(m.store, name)
+	if export == nil {
+		return nil, fmt.Errorf("unknown function: %s", name)
+	}
+
+	f := export.Func()
 
 	rawArguments := make([]any, len(arguments))
 	for i, argument := range arguments {
@@ -63,11 +74,26 @@ func (m *vm) Invoke(name string, arguments ...interpreter.Value) (interpreter.Va
 
 func NewVM(wasm []byte) (VM, error) {
 
-	inter, err := interpreter.NewInterpreter(nil, nil, &interpreter.Config{})
+	inter, err := interpreter.NewInterpreter(
+		nil,
+		nil,
+		&interpreter.Config{
+			Storage: interpreter.NewInMemoryStorage(nil),
+		},
+	)
 	if err != nil {
 		return nil, err
 	}
 
+	return NewVMWithInterpreter(wasm, inter)
+}
+
+// NewVMWithInterpreter returns a new VM for the given WebAssembly module,
+// which uses the given interpreter to implement the compiler runtime functions
+func NewVMWithInterpreter(wasm []byte, inter *interpreter.Interpreter) (VM, error) {
+
+	runtime := NewRuntime(inter)
+
 	config := wasmtime.NewConfig()
 	config.SetWasmReferenceTypes(true)
 
@@ -80,78 +106,238 @@ func NewVM(wasm []byte) (VM, error) {
 		return nil, err
 	}
 
-	intFunc := wasmtime.WrapFunc(
-		store,
-		func(caller *wasmtime.Caller, offset int32, length int32) (any, *wasmtime.Trap) {
-			if offset < 0 {
-				return nil, wasmtime.NewTrap(fmt.Sprintf("Int: invalid offset: %d", offset))
-			}
+	// memory returns the bytes of the module's memory at the given offset and length
+	memory := func(caller *wasmtime.Caller, name string, offset int32, length int32) []byte {
+		if offset < 0 {
+			panic(fmt.Errorf("%s: invalid offset: %d", name, offset))
+		}
 
-			if length < 2 {
-				return nil, wasmtime.NewTrap(fmt.Sprintf("Int: invalid length: %d", length))
-			}
+		if length < 0 {
+			panic(fmt.Errorf("%s: invalid length: %d", name, length))
+		}
 
-			mem := caller.GetExport("mem").Memory()
+		data := caller.GetExport("mem").Memory().UnsafeData(store)
 
-			bytes := C.GoBytes(mem.Data(store), C.int(length))
+		end := int64(offset) + int64(length)
+		if end > int64(len(data)) {
+			panic(fmt.Errorf("%s: out of bounds memory access: %d", name, end))
+		}
 
-			value := new(big.Int).SetBytes(bytes[1:])
-			if bytes[0] == 0 {
-				value = value.Neg(value)
-			}
-
-			return interpreter.NewUnmeteredIntValueFromBigInt(value), nil
-		},
-	)
-
-	stringFunc := wasmtime.WrapFunc(
-		store,
-		func(caller *wasmtime.Caller, offset int32, length int32) (any, *wasmtime.Trap) {
-			if offset < 0 {
-				return nil, wasmtime.NewTrap(fmt.Sprintf("String: invalid offset: %d", offset))
-			}
-
-			if length < 0 {
-				return nil, wasmtime.NewTrap(fmt.Sprintf("String: invalid length: %d", length))
-			}
+		// copy, as the memory might change
+		result := make([]byte, length)
+		copy(result, data[offset:end])
+		return result
+	}
 
-			mem := caller.GetExport("mem").Memory()
+	constant := func(name string, f func([]byte) interpreter.Value) *wasmtime.Func {
+		return wasmtime.WrapFunc(
+			store,
+			func(caller *wasmtime.Caller, offset int32, length int32) any {
+				return f(memory(caller, name, offset, length))
+			},
+		)
+	}
 
-			bytes := C.GoBytes(mem.Data(store), C.int(length))
+	nullary := func(f func() interpreter.Value) *wasmtime.Func {
+		return wasmtime.WrapFunc(
+			store,
+			func() any {
+				return f()
+			},
+		)
+	}
 
-			return interpreter.NewUnmeteredStringValue(string(bytes)), nil
-		},
-	)
+	unary := func(f func(any) interpreter.Value) *wasmtime.Func {
+		return wasmtime.WrapFunc(
+			store,
+			func(value any) any {
+				return f(value)
+			},
+		)
+	}
 
-	addFunc := wasmtime.WrapFunc(
-		store,
-		func(left, right any) (any, *wasmtime.Trap) {
-			leftNumber, ok := left.(interpreter.NumberValue)
-			if !ok {
-				return nil, wasmtime.NewTrap(fmt.Sprintf("add: invalid left: %#+v", left))
-			}
+	binary := func(f func(any, any) interpreter.Value) *wasmtime.Func {
+		return wasmtime.WrapFunc(
+			store,
+			func(left, right any) any {
+				return f(left, right)
+			},
+		)
+	}
 
-			rightNumber, ok := right.(interpreter.NumberValue)
-			if !ok {
-				return nil, wasmtime.NewTrap(fmt.Sprintf("add: invalid right: %#+v", right))
-			}
+	valueAndConstant := func(name string, f func(any, []byte) interpreter.Value) *wasmtime.Func {
+		return wasmtime.WrapFunc(
+			store,
+			func(caller *wasmtime.Caller, value any, offset int32, length int32) any {
+				return f(value, memory(caller, name, offset, length))
+			},
+		)
+	}
 
-			return leftNumber.Plus(inter, rightNumber, interpreter.EmptyLocationRange), nil
-		},
-	)
+	functions := map[string]*wasmtime.Func{
+		// constants
+
+		compiler.RuntimeFunctionNameInt:       constant(compiler.RuntimeFunctionNameInt, runtime.Int),
+		compiler.RuntimeFunctionNameString:    constant(compiler.RuntimeFunctionNameString, runtime.String),
+		compiler.RuntimeFunctionNameCharacter: constant(compiler.RuntimeFunctionNameCharacter, runtime.Character),
+		compiler.RuntimeFunctionNameBool: wasmtime.WrapFunc(
+			store,
+			func(value int32) any {
+				return runtime.Bool(value)
+			},
+		),
+		compiler.RuntimeFunctionNameNil:  nullary(runtime.Nil),
+		compiler.RuntimeFunctionNameVoid: nullary(runtime.Void),
+		compiler.RuntimeFunctionNameFix64: wasmtime.WrapFunc(
+			store,
+			func(value int64) any {
+				return runtime.Fix64(value)
+			},
+		),
+		compiler.RuntimeFunctionNameUFix64: wasmtime.WrapFunc(
+			store,
+			func(value int64) any {
+				return runtime.UFix64(value)
+			},
+		),
+		compiler.RuntimeFunctionNamePath: wasmtime.WrapFunc(
+			store,
+			func(caller *wasmtime.Caller, domain int32, offset int32, length int32) any {
+				identifier := memory(caller, compiler.RuntimeFunctionNamePath, offset, length)
+				return runtime.Path(domain, identifier)
+			},
+		),
+
+		// binary operations
+
+		compiler.RuntimeFunctionNameAdd:               binary(runtime.Add),
+		compiler.RuntimeFunctionNameSubtract:          binary(runtime.Subtract),
+		compiler.RuntimeFunctionNameMultiply:          binary(runtime.Multiply),
+		compiler.RuntimeFunctionNameDivide:            binary(runtime.Divide),
+		compiler.RuntimeFunctionNameMod:               binary(runtime.Mod),
+		compiler.RuntimeFunctionNameLess:              binary(runtime.Less),
+		compiler.RuntimeFunctionNameLessEqual:         binary(runtime.LessEqual),
+		compiler.RuntimeFunctionNameGreater:           binary(runtime.Greater),
+		compiler.RuntimeFunctionNameGreaterEqual:      binary(runtime.GreaterEqual),
+		compiler.RuntimeFunctionNameEqual:             binary(runtime.Equal),
+		compiler.RuntimeFunctionNameNotEqual:          binary(runtime.NotEqual),
+		compiler.RuntimeFunctionNameBitwiseOr:         binary(runtime.BitwiseOr),
+		compiler.RuntimeFunctionNameBitwiseXor:        binary(runtime.BitwiseXor),
+		compiler.RuntimeFunctionNameBitwiseAnd:        binary(runtime.BitwiseAnd),
+		compiler.RuntimeFunctionNameBitwiseLeftShift:  binary(runtime.BitwiseLeftShift),
+		compiler.RuntimeFunctionNameBitwiseRightShift: binary(runtime.BitwiseRightShift),
+
+		// unary operations
+
+		compiler.RuntimeFunctionNameNegate:   unary(runtime.Negate),
+		compiler.RuntimeFunctionNameMinus:    unary(runtime.Minus),
+		compiler.RuntimeFunctionNameForce:    unary(runtime.Force),
+		compiler.RuntimeFunctionNameIsNil:    unary(runtime.IsNil),
+		compiler.RuntimeFunctionNameSome:     unary(runtime.Some),
+		compiler.RuntimeFunctionNameTransfer: unary(runtime.Transfer),
+
+		// control flow
+
+		compiler.RuntimeFunctionNameIsTrue: wasmtime.WrapFunc(store, runtime.IsTrue),
+		compiler.RuntimeFunctionNameFailCondition: wasmtime.WrapFunc(
+			store,
+			func(kind int32, message any) {
+				runtime.FailCondition(kind, message)
+			},
+		),
+
+		// values
+
+		compiler.RuntimeFunctionNameGlobal: constant(compiler.RuntimeFunctionNameGlobal, runtime.Global),
+		compiler.RuntimeFunctionNameNewList: wasmtime.WrapFunc(
+			store,
+			func() any {
+				return runtime.NewList()
+			},
+		),
+		compiler.RuntimeFunctionNameAppendList: wasmtime.WrapFunc(
+			store,
+			func(list any, value any) any {
+				return runtime.AppendList(list, value)
+			},
+		),
+		compiler.RuntimeFunctionNameInvoke: binary(runtime.Invoke),
+		compiler.RuntimeFunctionNameGetMember: valueAndConstant(
+			compiler.RuntimeFunctionNameGetMember,
+			runtime.GetMember,
+		),
+		compiler.RuntimeFunctionNameSetMember: wasmtime.WrapFunc(
+			store,
+			func(caller *wasmtime.Caller, target any, offset int32, length int32, value any) {
+				name := memory(caller, compiler.RuntimeFunctionNameSetMember, offset, length)
+				runtime.SetMember(target, name, value)
+			},
+		),
+		compiler.RuntimeFunctionNameGetIndex: binary(runtime.GetIndex),
+		compiler.RuntimeFunctionNameSetIndex: wasmtime.WrapFunc(
+			store,
+			func(target any, index any, value any) {
+				runtime.SetIndex(target, index, value)
+			},
+		),
+		compiler.RuntimeFunctionNameArray: valueAndConstant(
+			compiler.RuntimeFunctionNameArray,
+			runtime.Array,
+		),
+		compiler.RuntimeFunctionNameDictionary: valueAndConstant(
+			compiler.RuntimeFunctionNameDictionary,
+			runtime.Dictionary,
+		),
+		compiler.RuntimeFunctionNameConvert: valueAndConstant(
+			compiler.RuntimeFunctionNameConvert,
+			runtime.Convert,
+		),
+		compiler.RuntimeFunctionNameFailableCast: valueAndConstant(
+			compiler.RuntimeFunctionNameFailableCast,
+			runtime.FailableCast,
+		),
+		compiler.RuntimeFunctionNameForceCast: valueAndConstant(
+			compiler.RuntimeFunctionNameForceCast,
+			runtime.ForceCast,
+		),
+		compiler.RuntimeFunctionNameReference: valueAndConstant(
+			compiler.RuntimeFunctionNameReference,
+			runtime.Reference,
+		),
+		compiler.RuntimeFunctionNameDestroy: unary(runtime.Destroy),
+		compiler.RuntimeFunctionNameEmit: wasmtime.WrapFunc(
+			store,
+			func(caller *wasmtime.Caller, event any, offset int32, length int32) {
+				eventType := memory(caller, compiler.RuntimeFunctionNameEmit, offset, length)
+				runtime.Emit(event, eventType)
+			},
+		),
+
+		// reporting
+
+		compiler.RuntimeFunctionNameReportFunctionInvocation: wasmtime.WrapFunc(store, runtime.ReportFunctionInvocation),
+		compiler.RuntimeFunctionNameReportFunctionReturn:     wasmtime.WrapFunc(store, runtime.ReportFunctionReturn),
+		compiler.RuntimeFunctionNameReportLoopIteration:      wasmtime.WrapFunc(store, runtime.ReportLoopIteration),
+		compiler.RuntimeFunctionNameReportStatement:          wasmtime.WrapFunc(store, runtime.ReportStatement),
+	}
 
 	// NOTE: wasmtime currently does not support specifying imports by name,
 	// unlike other WebAssembly APIs like wasmer, JavaScript, etc.,
 	// i.e. imports are imported in the order they are given.
 
+	imports := make([]wasmtime.AsExtern, 0, len(compiler.RuntimeFunctions))
+	for _, runtimeFunction := range compiler.RuntimeFunctions {
+		function, ok := functions[runtimeFunction.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing runtime function: %s", runtimeFunction.Name)
+		}
+		imports = append(imports, function)
+	}
+
 	instance, err := wasmtime.NewInstance(
 		store,
 		module,
-		[]wasmtime.AsExtern{
-			intFunc,
-			stringFunc,
-			addFunc,
-		},
+		imports,
 	)
 	if err != nil {
 		return nil, err
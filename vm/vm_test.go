@@ -0,0 +1,173 @@
+//go:build wasmtime
+// +build wasmtime
+
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/compiler"
+	"github.com/onflow/cadence/runtime/compiler/ir"
+	"github.com/onflow/cadence/runtime/compiler/wasm"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/tests/checker"
+	. "github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func compileAndLoad(t *testing.T, code string) VM {
+
+	checker, err := checker.ParseAndCheck(t, code)
+	require.NoError(t, err)
+
+	comp := compiler.NewCompiler(checker)
+	funcs := comp.VisitProgram(checker.Program).([]*ir.Func)
+
+	mod := compiler.GenerateWasm(funcs)
+
+	var buf wasm.Buffer
+	w := wasm.NewWASMWriter(&buf)
+	err = w.WriteModule(mod)
+	require.NoError(t, err)
+
+	vm, err := NewVM(buf.Bytes())
+	require.NoError(t, err)
+
+	return vm
+}
+
+func TestVMFib(t *testing.T) {
+
+	t.Parallel()
+
+	vm := compileAndLoad(t, `
+      fun fib(_ n: Int): Int {
+          if n < 2 {
+              return n
+          }
+          var a = 0
+          var b = 1
+          var i = 1
+          while i < n {
+              let c = a + b
+              a = b
+              b = c
+              i = i + 1
+          }
+          return b
+      }
+    `)
+
+	result, err := vm.Invoke("fib", interpreter.NewUnmeteredIntValueFromInt64(10))
+	require.NoError(t, err)
+
+	AssertValuesEqual(
+		t,
+		nil,
+		interpreter.NewUnmeteredIntValueFromInt64(55),
+		result,
+	)
+}
+
+func TestVMValues(t *testing.T) {
+
+	t.Parallel()
+
+	vm := compileAndLoad(t, `
+      fun sum(_ xs: [Int]): Int {
+          var s = 0
+          for x in xs {
+              if x == 3 { continue }
+              if x > 10 { break }
+              s = s + x
+          }
+          return s
+      }
+
+      fun double(_ x: Int?): Int {
+          if let y = x {
+              return y * 2
+          }
+          return x ?? 7
+      }
+
+      fun name(_ x: Int): String {
+          switch x {
+          case 1:
+              return "one"
+          case 2:
+              return "two"
+          default:
+              return "many"
+          }
+      }
+
+      fun test(): [AnyStruct] {
+          let d: {String: Int} = {"a": 1}
+          d["b"] = 2
+          return [
+              sum([1, 2, 3, 4, 20, 5]),
+              double(nil),
+              double(4),
+              name(2),
+              name(3),
+              d["a"]! + d["b"]!,
+              "hello".concat(" world"),
+              -3 as Int8
+          ]
+      }
+    `)
+
+	result, err := vm.Invoke("test")
+	require.NoError(t, err)
+
+	require.Equal(t,
+		`[7, 7, 8, "two", "many", 3, "hello world", -3]`,
+		result.String(),
+	)
+}
+
+func TestVMConditionFailure(t *testing.T) {
+
+	t.Parallel()
+
+	vm := compileAndLoad(t, `
+      fun inc(_ x: Int): Int {
+          pre { x > 0: "x must be positive" }
+          post { result > x }
+          return x + 1
+      }
+    `)
+
+	result, err := vm.Invoke("inc", interpreter.NewUnmeteredIntValueFromInt64(1))
+	require.NoError(t, err)
+
+	AssertValuesEqual(
+		t,
+		nil,
+		interpreter.NewUnmeteredIntValueFromInt64(2),
+		result,
+	)
+
+	_, err = vm.Invoke("inc", interpreter.NewUnmeteredIntValueFromInt64(0))
+	require.ErrorAs(t, err, &interpreter.ConditionError{})
+}